@@ -0,0 +1,227 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/vmware/go-vcloud-director/v2/govcd"
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// runLogin logs in and shows the session
+func runLogin(conn connection, args []string) error {
+	vcdClient, err := conn.login()
+	if err != nil {
+		return err
+	}
+	defer vcdClient.Disconnect()
+
+	fmt.Printf("Logged in to %s as %s@%s\n", conn.url, conn.user, conn.org)
+	fmt.Printf("API version:          %s\n", vcdClient.Client.APIVersion)
+	fmt.Printf("System administrator: %t\n", vcdClient.Client.IsSysAdmin)
+	return nil
+}
+
+// runVApp runs the vApp subcommands
+func runVApp(conn connection, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing subcommand: list, create, power-on, power-off or delete")
+	}
+	subcommand := args[0]
+	flags := flag.NewFlagSet("vapp "+subcommand, flag.ExitOnError)
+	name := flags.String("name", "", "vApp name")
+	catalogName := flags.String("catalog", "", "catalog of the template (create)")
+	itemName := flags.String("item", "", "catalog item of the template (create)")
+	networkName := flags.String("network", "", "org VDC network of the vApp (create)")
+	storageProfile := flags.String("storage-profile", "", "storage profile, the VDC default when empty (create)")
+	description := flags.String("description", "", "vApp description (create)")
+	_ = flags.Parse(args[1:])
+	if subcommand != "list" && *name == "" {
+		return fmt.Errorf("-name is required")
+	}
+
+	vcdClient, err := conn.login()
+	if err != nil {
+		return err
+	}
+	defer vcdClient.Disconnect()
+	vdc, err := conn.getVdc(vcdClient)
+	if err != nil {
+		return err
+	}
+
+	switch subcommand {
+	case "list":
+		return vdc.WalkVApps(func(vapp *govcd.VApp) error {
+			fmt.Printf("%-40s %s\n", vapp.VApp.Name, types.VAppStatuses[vapp.VApp.Status])
+			return nil
+		})
+	case "create":
+		return createVApp(conn, vcdClient, vdc, *name, *description, *catalogName, *itemName, *networkName, *storageProfile)
+	}
+
+	vapp, err := vdc.FindVAppByName(*name)
+	if err != nil {
+		return err
+	}
+	var task govcd.Task
+	switch subcommand {
+	case "power-on":
+		task, err = vapp.PowerOn()
+	case "power-off":
+		task, err = vapp.PowerOff()
+	case "delete":
+		return govcd.DeleteVApps([]*govcd.VApp{&vapp}, 1)
+	default:
+		return fmt.Errorf("unknown subcommand '%s'", subcommand)
+	}
+	if err != nil {
+		return err
+	}
+	return task.WaitTaskCompletion()
+}
+
+// createVApp creates a vApp from a catalog item, and waits for its creation
+func createVApp(conn connection, vcdClient *govcd.VCDClient, vdc *govcd.Vdc, name, description, catalogName, itemName, networkName, storageProfile string) error {
+	if catalogName == "" || itemName == "" {
+		return fmt.Errorf("-catalog and -item are required")
+	}
+	org, err := govcd.GetOrgByName(vcdClient, conn.org)
+	if err != nil {
+		return err
+	}
+	catalog, err := org.FindCatalog(catalogName)
+	if err != nil {
+		return err
+	}
+	if catalog.Catalog == nil {
+		return fmt.Errorf("catalog %s not found", catalogName)
+	}
+	catalogItem, err := catalog.FindCatalogItem(itemName)
+	if err != nil {
+		return err
+	}
+	if catalogItem.CatalogItem == nil {
+		return fmt.Errorf("catalog item %s not found in catalog %s", itemName, catalogName)
+	}
+	template, err := catalogItem.GetVAppTemplate()
+	if err != nil {
+		return err
+	}
+
+	networks := []*types.OrgVDCNetwork{}
+	if networkName != "" {
+		network, err := vdc.FindVDCNetwork(networkName)
+		if err != nil {
+			return err
+		}
+		networks = append(networks, network.OrgVDCNetwork)
+	}
+	storageProfileReference := types.Reference{}
+	if storageProfile != "" {
+		storageProfileReference, err = vdc.FindStorageProfileReference(storageProfile)
+		if err != nil {
+			return err
+		}
+	}
+
+	task, err := vdc.ComposeVApp(networks, template, storageProfileReference, name, description, true)
+	if err != nil {
+		return err
+	}
+	err = task.WaitTaskCompletion()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("vApp %s created\n", name)
+	return nil
+}
+
+// runUploadOva uploads an OVA to a catalog, and waits for its import
+func runUploadOva(conn connection, args []string) error {
+	flags := flag.NewFlagSet("upload-ova", flag.ExitOnError)
+	catalogName := flags.String("catalog", "", "catalog")
+	itemName := flags.String("item", "", "name of the catalog item")
+	fileName := flags.String("file", "", "OVA file")
+	description := flags.String("description", "", "description of the catalog item")
+	_ = flags.Parse(args)
+	if *catalogName == "" || *itemName == "" || *fileName == "" {
+		return fmt.Errorf("-catalog, -item and -file are required")
+	}
+
+	vcdClient, err := conn.login()
+	if err != nil {
+		return err
+	}
+	defer vcdClient.Disconnect()
+	org, err := govcd.GetOrgByName(vcdClient, conn.org)
+	if err != nil {
+		return err
+	}
+	catalog, err := org.FindCatalog(*catalogName)
+	if err != nil {
+		return err
+	}
+	if catalog.Catalog == nil {
+		return fmt.Errorf("catalog %s not found", *catalogName)
+	}
+
+	uploadTask, err := catalog.UploadOvf(*fileName, *itemName, *description, 1024*1024)
+	if err != nil {
+		return err
+	}
+	err = uploadTask.ShowUploadProgress()
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+	return uploadTask.WaitTaskCompletion()
+}
+
+// runQuery runs a query and prints its records as JSON
+func runQuery(conn connection, args []string) error {
+	flags := flag.NewFlagSet("query", flag.ExitOnError)
+	queryType := flags.String("type", "", "query type, e.g. vm, vApp, orgVdcNetwork")
+	filter := flags.String("filter", "", "comma separated conditions field==value")
+	fields := flags.String("fields", "", "comma separated fields to retrieve, all when empty")
+	maxRecords := flags.Int("max", govcd.DefaultQueryMaxRecords, "maximum number of records")
+	_ = flags.Parse(args)
+	if *queryType == "" {
+		return fmt.Errorf("-type is required")
+	}
+
+	options := &govcd.QueryOptions{MaxRecords: *maxRecords}
+	if *filter != "" {
+		options.Filter = govcd.NewQueryFilter()
+		for _, condition := range strings.Split(*filter, ",") {
+			fieldValue := strings.SplitN(condition, "==", 2)
+			if len(fieldValue) != 2 {
+				return fmt.Errorf("invalid condition '%s', expected field==value", condition)
+			}
+			options.Filter.Equal(fieldValue[0], fieldValue[1])
+		}
+	}
+	if *fields != "" {
+		options.Fields = strings.Split(*fields, ",")
+	}
+
+	vcdClient, err := conn.login()
+	if err != nil {
+		return err
+	}
+	defer vcdClient.Disconnect()
+	results, err := vcdClient.Client.QueryAllPages(*queryType, options)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(results.Results)
+}
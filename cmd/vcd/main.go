@@ -0,0 +1,150 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+/*
+vcd is a small command line tool built on go-vcloud-director, to exercise and debug the SDK.
+Every command logs in, runs its operations and logs out.
+
+Usage:
+
+	vcd [connection flags] <command> [command flags]
+
+The connection flags can also be set with environment variables:
+
+	-url       VCD_URL       API endpoint, e.g. https://vcd.example.com/api
+	-user      VCD_USER      User name
+	-password  VCD_PASSWORD  Password
+	-org       VCD_ORG       Organization of the user, "System" for system administrators
+	-vdc       VCD_VDC       VDC used by the vApp commands
+	-insecure  VCD_INSECURE  Skip the verification of the TLS certificate when "true"
+
+Commands:
+
+	login                                          Checks the credentials and shows the session
+	vapp list                                      Lists the vApps of the VDC
+	vapp create -name N -catalog C -item I [-network NET] [-storage-profile SP] [-description D]
+	vapp power-on -name N
+	vapp power-off -name N
+	vapp delete -name N                            Undeploys the vApp when needed, then deletes it
+	upload-ova -catalog C -item I -file F [-description D]
+	query -type T [-filter field==value,...] [-fields f1,f2] [-max M]
+	                                               Prints the records of a query as JSON
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/vmware/go-vcloud-director/v2/govcd"
+)
+
+// connection holds the parameters needed to log in
+type connection struct {
+	url      string
+	user     string
+	password string
+	org      string
+	vdc      string
+	insecure bool
+}
+
+// command is a subcommand of the tool
+type command struct {
+	name        string
+	description string
+	run         func(conn connection, args []string) error
+}
+
+var commands = []command{
+	{"login", "check the credentials and show the session", runLogin},
+	{"vapp", "list, create, power on, power off and delete vApps", runVApp},
+	{"upload-ova", "upload an OVA to a catalog", runUploadOva},
+	{"query", "run a query and print its records as JSON", runQuery},
+}
+
+func main() {
+	conn := connection{}
+	flags := flag.NewFlagSet("vcd", flag.ExitOnError)
+	flags.StringVar(&conn.url, "url", os.Getenv("VCD_URL"), "API endpoint, e.g. https://vcd.example.com/api")
+	flags.StringVar(&conn.user, "user", os.Getenv("VCD_USER"), "user name")
+	flags.StringVar(&conn.password, "password", os.Getenv("VCD_PASSWORD"), "password")
+	flags.StringVar(&conn.org, "org", os.Getenv("VCD_ORG"), "organization of the user")
+	flags.StringVar(&conn.vdc, "vdc", os.Getenv("VCD_VDC"), "VDC used by the vApp commands")
+	flags.BoolVar(&conn.insecure, "insecure", os.Getenv("VCD_INSECURE") == "true", "skip TLS certificate verification")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: vcd [connection flags] <command> [command flags]\n\nCommands:\n")
+		for _, cmd := range commands {
+			fmt.Fprintf(os.Stderr, "  %-12s %s\n", cmd.name, cmd.description)
+		}
+		fmt.Fprintf(os.Stderr, "\nConnection flags:\n")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(os.Args[1:])
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	for _, cmd := range commands {
+		if cmd.name == flags.Arg(0) {
+			err := cmd.run(conn, flags.Args()[1:])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "vcd %s: %s\n", cmd.name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+	fmt.Fprintf(os.Stderr, "unknown command '%s'\n", flags.Arg(0))
+	flags.Usage()
+	os.Exit(2)
+}
+
+// login returns an authenticated client
+func (conn connection) login() (*govcd.VCDClient, error) {
+	var missing []string
+	for _, parameter := range []struct{ name, value string }{
+		{"url", conn.url}, {"user", conn.user}, {"password", conn.password}, {"org", conn.org},
+	} {
+		if parameter.value == "" {
+			missing = append(missing, parameter.name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing connection parameters: %s", strings.Join(missing, ", "))
+	}
+	apiUrl, err := url.ParseRequestURI(conn.url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url %s: %s", conn.url, err)
+	}
+	vcdClient := govcd.NewVCDClient(*apiUrl, conn.insecure)
+	err = vcdClient.Authenticate(conn.user, conn.password, conn.org)
+	if err != nil {
+		return nil, err
+	}
+	return vcdClient, nil
+}
+
+// getVdc returns the VDC of the connection, in the org of the user
+func (conn connection) getVdc(vcdClient *govcd.VCDClient) (*govcd.Vdc, error) {
+	if conn.vdc == "" {
+		return nil, fmt.Errorf("missing connection parameter: vdc")
+	}
+	org, err := govcd.GetOrgByName(vcdClient, conn.org)
+	if err != nil {
+		return nil, err
+	}
+	vdc, err := org.GetVdcByName(conn.vdc)
+	if err != nil {
+		return nil, err
+	}
+	if vdc.Vdc == nil || vdc.Vdc.HREF == "" {
+		return nil, fmt.Errorf("VDC %s not found in org %s", conn.vdc, conn.org)
+	}
+	return &vdc, nil
+}
@@ -1,5 +1,7 @@
 module github.com/vmware/go-vcloud-director/v2
 
+go 1.13
+
 require (
 	github.com/hashicorp/go-version v1.1.0
 	github.com/kr/pretty v0.1.0 // indirect
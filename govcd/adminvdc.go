@@ -0,0 +1,138 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/util"
+)
+
+// GetAdminVdcByName retrieves the admin view of the VDC with the given name.
+// Returns an error if the VDC is not found.
+func (adminOrg *AdminOrg) GetAdminVdcByName(vdcName string) (*AdminVdc, error) {
+	if adminOrg.AdminOrg.Vdcs != nil {
+		for _, vdc := range adminOrg.AdminOrg.Vdcs.Vdcs {
+			if vdc.Name == vdcName {
+				return adminOrg.GetAdminVdcByHref(vdc.HREF)
+			}
+		}
+	}
+	return nil, fmt.Errorf("vdc %s not found in org %s", vdcName, adminOrg.AdminOrg.Name)
+}
+
+// GetAdminVdcByHref retrieves the admin view of a VDC by its admin HREF
+func (adminOrg *AdminOrg) GetAdminVdcByHref(href string) (*AdminVdc, error) {
+	adminVdc := NewAdminVdc(adminOrg.client)
+	_, err := adminOrg.client.ExecuteRequest(href, http.MethodGet,
+		"", "error retrieving admin vdc: %s", nil, adminVdc.AdminVdc)
+	if err != nil {
+		return nil, err
+	}
+	return adminVdc, nil
+}
+
+// Refresh retrieves the admin VDC again
+func (adminVdc *AdminVdc) Refresh() error {
+	if adminVdc.AdminVdc.HREF == "" {
+		return fmt.Errorf("cannot refresh, Object is empty")
+	}
+	href := adminVdc.AdminVdc.HREF
+
+	// Unmarshal into an empty struct, otherwise we end up with duplicate elements in
+	// slices, and keep the current one when the request fails.
+	refreshed := &types.AdminVdc{}
+
+	_, err := adminVdc.client.ExecuteRequest(href, http.MethodGet,
+		"", "error refreshing admin vdc: %s", nil, refreshed)
+	if err != nil {
+		return err
+	}
+	adminVdc.AdminVdc = refreshed
+	return nil
+}
+
+// Update sends the current VDC definition to vCD: name, description, compute capacity, quotas,
+// guarantees and the other admin settings can be changed. The allocation model, the provider VDC
+// and the storage profiles cannot be changed this way.
+// Returns the update task.
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/PUT-Vdc.html
+func (adminVdc *AdminVdc) Update() (Task, error) {
+	util.Logger.Printf("[TRACE] AdminVdc.Update - updating VDC %s", adminVdc.AdminVdc.Name)
+
+	if adminVdc.AdminVdc.HREF == "" {
+		return Task{}, fmt.Errorf("cannot update, Object is empty")
+	}
+	if adminVdc.AdminVdc.AllocationModel == types.VdcAllocationModelFlex && !adminVdc.client.APIClientVersionIs(">= 32.0") {
+		return Task{}, fmt.Errorf("the Flex allocation model requires API version 32.0 or newer, the client uses %s",
+			adminVdc.client.APIVersion)
+	}
+
+	// Read-only elements are not sent back
+	payload := *adminVdc.AdminVdc
+	payload.Xmlns = types.XMLNamespaceVCloud
+	payload.Link = nil
+	payload.Tasks = nil
+	payload.ResourceEntities = nil
+	payload.AvailableNetworks = nil
+	payload.Capabilities = nil
+	payload.VdcStorageProfiles = nil
+	// Compute policies are removed for the API versions which don't know them (see payloadAdjustments)
+
+	updated := NewAdminVdc(adminVdc.client)
+	_, err := adminVdc.client.ExecuteRequest(adminVdc.AdminVdc.HREF, http.MethodPut,
+		types.MimeAdminVdc, "error updating vdc: %s", &payload, updated.AdminVdc)
+	if err != nil {
+		return Task{}, err
+	}
+	if updated.AdminVdc.Tasks == nil || len(updated.AdminVdc.Tasks.Task) == 0 {
+		return Task{}, nil
+	}
+	task := NewTask(adminVdc.client)
+	task.Task = updated.AdminVdc.Tasks.Task[0]
+	return *task, nil
+}
+
+// UpdateWait updates the VDC, waits for the task to complete and refreshes the VDC
+func (adminVdc *AdminVdc) UpdateWait() error {
+	task, err := adminVdc.Update()
+	if err != nil {
+		return err
+	}
+	if task != (Task{}) {
+		err = task.WaitTaskCompletion()
+		if err != nil {
+			return fmt.Errorf("couldn't finish updating vdc %#v", err)
+		}
+	}
+	return adminVdc.Refresh()
+}
+
+// Delete deletes the VDC. force and recursive also remove the vApps and the other
+// objects contained in the VDC.
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/DELETE-Vdc.html
+func (adminVdc *AdminVdc) Delete(force bool, recursive bool) (Task, error) {
+	util.Logger.Printf("[TRACE] AdminVdc.Delete - deleting VDC with force: %t, recursive: %t", force, recursive)
+
+	if adminVdc.AdminVdc.HREF == "" {
+		return Task{}, fmt.Errorf("cannot delete, Object is empty")
+	}
+	return deleteVdc(adminVdc.client, adminVdc.AdminVdc.HREF, force, recursive)
+}
+
+// DeleteWait deletes the VDC and waits for the asynchronous task to complete
+func (adminVdc *AdminVdc) DeleteWait(force bool, recursive bool) error {
+	task, err := adminVdc.Delete(force, recursive)
+	if err != nil {
+		return err
+	}
+	err = task.WaitTaskCompletion()
+	if err != nil {
+		return fmt.Errorf("couldn't finish removing vdc %#v", err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/util"
+)
+
+// GetAmqpSettings retrieves the settings of the AMQP broker used by vCD for notifications and
+// extension services. The password is not returned.
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/GET-AmqpSettings.html
+func GetAmqpSettings(vcdClient *VCDClient) (*types.AmqpSettings, error) {
+	settings := &types.AmqpSettings{}
+	_, err := vcdClient.Client.ExecuteRequest(getAmqpSettingsHref(vcdClient), http.MethodGet,
+		types.MimeAmqpSettings, "error retrieving AMQP settings: %s", nil, settings)
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// SetAmqpSettings updates the settings of the AMQP broker and returns the new settings.
+// The password must be given every time, as vCD does not return it.
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/PUT-AmqpSettings.html
+func SetAmqpSettings(vcdClient *VCDClient, settings *types.AmqpSettings) (*types.AmqpSettings, error) {
+	util.Logger.Printf("[TRACE] SetAmqpSettings - setting AMQP broker %s:%d", settings.AmqpHost, settings.AmqpPort)
+
+	err := validateAmqpSettings(settings)
+	if err != nil {
+		return nil, err
+	}
+	payload := *settings
+	payload.Link = nil
+
+	updated := &types.AmqpSettings{}
+	_, err = vcdClient.Client.ExecuteRequest(getAmqpSettingsHref(vcdClient), http.MethodPut,
+		types.MimeAmqpSettings, "error updating AMQP settings: %s", &payload, updated)
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// TestAmqpSettings checks whether vCD can connect to the AMQP broker with the given settings,
+// without applying them. It returns false when the connection fails.
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/POST-TestAmqpSettings.html
+func TestAmqpSettings(vcdClient *VCDClient, settings *types.AmqpSettings) (bool, error) {
+	err := validateAmqpSettings(settings)
+	if err != nil {
+		return false, err
+	}
+	payload := *settings
+	payload.Link = nil
+
+	result := &types.AmqpSettingsTestResult{}
+	_, err = vcdClient.Client.ExecuteRequest(getAmqpSettingsHref(vcdClient)+"/action/test", http.MethodPost,
+		types.MimeAmqpSettingsTest, "error testing AMQP settings: %s",
+		&types.AmqpSettingsTest{AmqpSettings: &payload}, result)
+	if err != nil {
+		return false, err
+	}
+	return result.Valid, nil
+}
+
+// validateAmqpSettings checks that the settings have a host, a port and an exchange
+func validateAmqpSettings(settings *types.AmqpSettings) error {
+	if settings == nil || settings.AmqpHost == "" {
+		return fmt.Errorf("AMQP host is required")
+	}
+	if settings.AmqpPort <= 0 || settings.AmqpPort > 65535 {
+		return fmt.Errorf("invalid AMQP port %d", settings.AmqpPort)
+	}
+	if settings.AmqpExchange == "" {
+		return fmt.Errorf("AMQP exchange is required")
+	}
+	return nil
+}
+
+// getAmqpSettingsHref returns the HREF of the AMQP settings
+func getAmqpSettingsHref(vcdClient *VCDClient) string {
+	amqpHREF := vcdClient.Client.VCDHREF
+	amqpHREF.Path += "/admin/extension/settings/amqp"
+	return amqpHREF.String()
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"testing"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// Retrieves the AMQP settings and tests a broker which cannot be reached
+func (vcd *TestVCD) Test_AmqpSettings(check *C) {
+	if vcd.skipAdminTests {
+		check.Skip(fmt.Sprintf(TestRequiresSysAdminPrivileges, check.TestName()))
+	}
+
+	settings, err := GetAmqpSettings(vcd.client)
+	check.Assert(err, IsNil)
+	check.Assert(settings.AmqpPassword, Equals, "")
+
+	valid, err := TestAmqpSettings(vcd.client, &types.AmqpSettings{
+		AmqpHost:     "unreachable.amqp.test",
+		AmqpPort:     5672,
+		AmqpUsername: "guest",
+		AmqpPassword: "guest",
+		AmqpExchange: "systemExchange",
+		AmqpVHost:    "/",
+		AmqpPrefix:   "vcd",
+	})
+	check.Assert(err, IsNil)
+	check.Assert(valid, Equals, false)
+}
+
+func TestValidateAmqpSettings(t *testing.T) {
+	valid := types.AmqpSettings{AmqpHost: "amqp.example.com", AmqpPort: 5672, AmqpExchange: "systemExchange"}
+	if err := validateAmqpSettings(&valid); err != nil {
+		t.Errorf("unexpected error validating AMQP settings: %s", err)
+	}
+	noHost := valid
+	noHost.AmqpHost = ""
+	badPort := valid
+	badPort.AmqpPort = 70000
+	noExchange := valid
+	noExchange.AmqpExchange = ""
+	for _, settings := range []*types.AmqpSettings{nil, &noHost, &badPort, &noExchange} {
+		if err := validateAmqpSettings(settings); err == nil {
+			t.Errorf("expected error validating AMQP settings %#v", settings)
+		}
+	}
+}
@@ -7,6 +7,7 @@ package govcd
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
 	"fmt"
 	"io"
@@ -20,6 +21,25 @@ import (
 	"github.com/vmware/go-vcloud-director/v2/util"
 )
 
+// Concurrency
+//
+// A client is safe for concurrent use by multiple goroutines once it is authenticated: the
+// requests only read its fields, and the entity cache, the rate limiter and the retry policy
+// have their own synchronization. The operations which change the session (Authenticate,
+// ImportSessionToken and Disconnect) must not run concurrently with other requests.
+//
+// The entities (VApp, VM, Vdc, ...) are not safe for concurrent use: Refresh and the operations
+// which refresh an entity replace the pointer to its data (e.g. VApp.VApp). Give each goroutine
+// its own copy of the entity, which shares the client:
+//
+//	for _, vapp := range vapps {
+//		vappCopy := *vapp
+//		go reconcile(&vappCopy)
+//	}
+//
+// Refresh never changes the data it replaces, so that a copy is not affected by the refresh of
+// another copy, and keeps the current data when the request fails.
+
 // Client provides a client to vCloud Director, values can be populated automatically using the Authenticate method.
 type Client struct {
 	APIVersion    string      // The API version required
@@ -29,10 +49,28 @@ type Client struct {
 	Http          http.Client // HttpClient is the client to use. Default will be used if not provided.
 	IsSysAdmin    bool        // flag if client is connected as system administrator
 
+	// VCDAccessToken is the bearer token (JWT) returned at login by vCD 10.0+.
+	// It is needed by the OAuth endpoints, e.g. to create API tokens.
+	VCDAccessToken string
+
+	// UserAgent is the User-Agent header of the requests, the default one of net/http when empty
+	// (see WithUserAgent)
+	UserAgent string
+
 	// MaxRetryTimeout specifies a time limit (in seconds) for retrying requests made by the SDK
 	// where vCloud director may take time to respond and retry mechanism is needed.
 	// This must be >0 to avoid instant timeout errors.
 	MaxRetryTimeout int
+
+	// cache keeps the entities retrieved by refresh operations, when enabled with WithEntityCache
+	cache *entityCache
+
+	// ctx is the context of the requests, context.Background() when nil (see WithContext)
+	ctx context.Context
+
+	// tenantContext is the UUID of the org in which the OpenAPI requests are run, when set
+	// (see withTenantContext)
+	tenantContext string
 }
 
 // Function allow to pass complex values params which shouldn't be encoded like for queries. e.g. /query?filter=(name=foo)
@@ -57,6 +95,11 @@ func (cli *Client) NewRequestWitNotEncodedParams(params map[string]string, notEn
 	// passing a string version of an url.URL struct and http.NewRequest returns
 	// error only if can't process an url.ParseRequestURI().
 	req, _ := http.NewRequest(method, reqUrl.String(), body)
+	req = req.WithContext(cli.Context())
+	cli.setUserAgent(req)
+
+	// Any change may affect the cached entities
+	cli.clearEntityCacheForChange(method)
 
 	if cli.VCDAuthHeader != "" && cli.VCDToken != "" {
 		// Add the authorization header
@@ -97,7 +140,15 @@ func (cli *Client) NewRequest(params map[string]string, method string, reqUrl ur
 	return cli.NewRequestWitNotEncodedParams(params, nil, method, reqUrl, body)
 }
 
-// ParseErr takes an error XML resp and returns a single string for use in error messages.
+// setUserAgent sets the User-Agent header of the request, when the client has one
+func (cli *Client) setUserAgent(req *http.Request) {
+	if cli.UserAgent != "" {
+		req.Header.Set("User-Agent", cli.UserAgent)
+	}
+}
+
+// ParseErr takes an error XML resp and returns it as an *APIError, whose message is suitable
+// for use in error messages.
 func ParseErr(resp *http.Response) error {
 
 	errBody := new(types.Error)
@@ -108,7 +159,13 @@ func ParseErr(resp *http.Response) error {
 		return fmt.Errorf("[ParseErr]: error parsing error body for non-200 request: %s (%+v)", err, resp)
 	}
 
-	return fmt.Errorf("API Error: %d: %s", errBody.MajorErrorCode, errBody.Message)
+	return &APIError{
+		StatusCode:              resp.StatusCode,
+		MajorErrorCode:          errBody.MajorErrorCode,
+		MinorErrorCode:          errBody.MinorErrorCode,
+		VendorSpecificErrorCode: errBody.VendorSpecificErrorCode,
+		Message:                 errBody.Message,
+	}
 }
 
 // decodeBody is used to XML decode a response body
@@ -195,7 +252,7 @@ func (client *Client) ExecuteTaskRequest(pathURL, requestType, contentType, erro
 
 	resp, err := executeRequest(pathURL, requestType, contentType, payload, client)
 	if err != nil {
-		return Task{}, fmt.Errorf(errorMessage, err)
+		return Task{}, wrapError(errorMessage, err)
 	}
 
 	task := NewTask(client)
@@ -228,7 +285,7 @@ func (client *Client) ExecuteRequestWithoutResponse(pathURL, requestType, conten
 
 	resp, err := executeRequest(pathURL, requestType, contentType, payload, client)
 	if err != nil {
-		return fmt.Errorf(errorMessage, err)
+		return wrapError(errorMessage, err)
 	}
 
 	err = resp.Body.Close()
@@ -257,7 +314,7 @@ func (client *Client) ExecuteRequest(pathURL, requestType, contentType, errorMes
 
 	resp, err := executeRequest(pathURL, requestType, contentType, payload, client)
 	if err != nil {
-		return resp, fmt.Errorf(errorMessage, err)
+		return resp, wrapError(errorMessage, err)
 	}
 
 	if err = decodeBody(resp, out); err != nil {
@@ -280,7 +337,7 @@ func executeRequest(pathURL, requestType, contentType string, payload interface{
 	switch requestType {
 	case http.MethodPost, http.MethodPut:
 
-		marshaledXml, err := xml.MarshalIndent(payload, "  ", "    ")
+		marshaledXml, err := xml.MarshalIndent(client.adjustPayload(payload), "  ", "    ")
 		if err != nil {
 			return &http.Response{}, fmt.Errorf("error marshalling xml data %v", err)
 		}
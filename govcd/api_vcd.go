@@ -24,6 +24,9 @@ type VCDClient struct {
 	QueryHREF         url.URL // HREF for the query API
 	Mutex             sync.Mutex
 	supportedVersions SupportedVersions // Versions from /api/versions endpoint
+
+	sessionMutex sync.Mutex
+	keepAlive    *sessionKeepAlive // Goroutine keeping the session alive, when started
 }
 
 func (vcdCli *VCDClient) vcdloginurl() error {
@@ -76,6 +79,8 @@ func (vcdCli *VCDClient) vcdauthorize(user, pass, org string) error {
 	// Store the authentication header
 	vcdCli.Client.VCDToken = resp.Header.Get("x-vcloud-authorization")
 	vcdCli.Client.VCDAuthHeader = "x-vcloud-authorization"
+	// Newer versions of vCD also return a bearer token, which is used for OAuth operations
+	vcdCli.Client.VCDAccessToken = resp.Header.Get("X-VMWARE-VCLOUD-ACCESS-TOKEN")
 	vcdCli.Client.IsSysAdmin = false
 	if "system" == strings.ToLower(org) {
 		vcdCli.Client.IsSysAdmin = true
@@ -87,7 +92,9 @@ func (vcdCli *VCDClient) vcdauthorize(user, pass, org string) error {
 }
 
 // NewVCDClient initializes VMware vCloud Director client with reasonable defaults.
-// It accepts functions of type VCDClientOption for adjusting defaults.
+// It accepts functions of type VCDClientOption for adjusting defaults, e.g. WithAPIVersion,
+// WithHttpClient, WithTLSConfig, WithProxy, WithUserAgent, WithHttpTimeout, WithHttpHook,
+// WithRetryPolicy, WithRateLimit and WithEntityCache. The options are applied in order.
 func NewVCDClient(vcdEndpoint url.URL, insecure bool, options ...VCDClientOption) *VCDClient {
 	// Setting defaults
 	vcdClient := &VCDClient{
@@ -135,11 +142,13 @@ func (vcdCli *VCDClient) Authenticate(username, password, org string) error {
 	return nil
 }
 
-// Disconnect performs a disconnection from the vCloud Director API endpoint.
+// Disconnect performs a disconnection from the vCloud Director API endpoint, which ends the
+// session. The session keepalive, if started, is stopped.
 func (vcdCli *VCDClient) Disconnect() error {
 	if vcdCli.Client.VCDToken == "" && vcdCli.Client.VCDAuthHeader == "" {
 		return fmt.Errorf("cannot disconnect, client is not authenticated")
 	}
+	vcdCli.StopSessionKeepAlive()
 	req := vcdCli.Client.NewRequest(map[string]string{}, http.MethodDelete, vcdCli.sessionHREF, nil)
 	// Add the Accept header for vCA
 	req.Header.Add("Accept", "application/xml;version="+vcdCli.Client.APIVersion)
@@ -148,6 +157,8 @@ func (vcdCli *VCDClient) Disconnect() error {
 	if _, err := checkResp(vcdCli.Client.Http.Do(req)); err != nil {
 		return fmt.Errorf("error processing session delete for vCloud Director: %s", err)
 	}
+	vcdCli.Client.VCDToken = ""
+	vcdCli.Client.VCDAccessToken = ""
 	return nil
 }
 
@@ -167,3 +178,87 @@ func WithAPIVersion(version string) VCDClientOption {
 		return nil
 	}
 }
+
+// WithHttpClient makes the vCD client send its requests through httpClient, instead of the
+// default one, whose TLS and proxy settings are ignored. The retry policy, rate limit and hooks
+// set by other options are kept, whatever the order of the options.
+func WithHttpClient(httpClient *http.Client) VCDClientOption {
+	return func(vcdClient *VCDClient) error {
+		if httpClient == nil {
+			return fmt.Errorf("nil HTTP client")
+		}
+		transport := httpClient.Transport
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+		withHttpClient := *httpClient
+		withHttpClient.Transport = vcdClient.Client.Http.Transport
+		*innermostTransport(&withHttpClient) = transport
+		vcdClient.Client.Http = withHttpClient
+		return nil
+	}
+}
+
+// WithTLSConfig sets the TLS configuration of the connections to vCD, e.g. to trust a private
+// certificate authority. It overrides the insecure flag of NewVCDClient.
+func WithTLSConfig(tlsConfig *tls.Config) VCDClientOption {
+	return func(vcdClient *VCDClient) error {
+		transport := cloneInnermostHttpTransport(&vcdClient.Client.Http)
+		if transport == nil {
+			return fmt.Errorf("cannot set the TLS configuration of a custom HTTP transport")
+		}
+		transport.TLSClientConfig = tlsConfig
+		return nil
+	}
+}
+
+// WithProxy sends the requests through the proxy with the given URL, instead of the one set by
+// the environment (HTTPS_PROXY and NO_PROXY). A nil URL disables the proxy.
+func WithProxy(proxyUrl *url.URL) VCDClientOption {
+	return func(vcdClient *VCDClient) error {
+		transport := cloneInnermostHttpTransport(&vcdClient.Client.Http)
+		if transport == nil {
+			return fmt.Errorf("cannot set the proxy of a custom HTTP transport")
+		}
+		transport.Proxy = http.ProxyURL(proxyUrl)
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header of the requests, e.g. to identify the application in
+// the logs of vCD
+func WithUserAgent(userAgent string) VCDClientOption {
+	return func(vcdClient *VCDClient) error {
+		vcdClient.Client.UserAgent = userAgent
+		return nil
+	}
+}
+
+// WithHttpTimeout sets a time limit for each HTTP request, including the reading of the response
+// body. It is not the time limit of the operations, which can be set with WithContext.
+func WithHttpTimeout(timeout time.Duration) VCDClientOption {
+	return func(vcdClient *VCDClient) error {
+		if timeout < 0 {
+			return fmt.Errorf("HTTP timeout cannot be negative")
+		}
+		vcdClient.Client.Http.Timeout = timeout
+		return nil
+	}
+}
+
+// WithHttpHook calls hook after every HTTP exchange of the client, e.g. to log or measure the
+// requests. Several hooks can be set, and are called from the last one set.
+func WithHttpHook(hook HttpHook) VCDClientOption {
+	return func(vcdClient *VCDClient) error {
+		if hook == nil {
+			return fmt.Errorf("nil HTTP hook")
+		}
+		location := innermostTransport(&vcdClient.Client.Http)
+		transport := *location
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+		*location = &hookTransport{hook: hook, transport: transport}
+		return nil
+	}
+}
@@ -17,6 +17,7 @@ import (
 
 	"github.com/vmware/go-vcloud-director/v2/types/v56"
 	"github.com/vmware/go-vcloud-director/v2/util"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
 	. "gopkg.in/check.v1"
 	"gopkg.in/yaml.v2"
 )
@@ -54,6 +55,14 @@ const (
 	TestVMAttachOrDetachDisk      = "TestVMAttachOrDetachDisk"
 	TestVMAttachDisk              = "TestVMAttachDisk"
 	TestVMDetachDisk              = "TestVMDetachDisk"
+	TestCreateGroup               = "TestCreateGroup"
+	TestCreateUser                = "TestCreateUser"
+	TestGlobalRole                = "TestGlobalRole"
+	TestRole                      = "TestRole"
+	TestRightsBundle              = "TestRightsBundle"
+	TestVdcComputePolicy          = "TestVdcComputePolicy"
+	TestCreateExternalNetwork     = "TestCreateExternalNetwork"
+	TestExtensionService          = "TestExtensionService"
 )
 
 const (
@@ -99,6 +108,8 @@ type TestConfig struct {
 			Size          int `yaml:"size,omitempty"`
 			SizeForUpdate int `yaml:"sizeForUpdate,omitempty"`
 		}
+		// Port group backing the external network created by tests
+		ExternalNetworkPortGroup string `yaml:"externalNetworkPortGroup,omitempty"`
 	} `yaml:"vcd"`
 	Logging struct {
 		Enabled          bool   `yaml:"enabled,omitempty"`
@@ -131,6 +142,7 @@ type TestVCD struct {
 	config         TestConfig
 	skipVappTests  bool
 	skipAdminTests bool
+	recorder       *vcdtest.Recorder
 }
 
 // Cleanup entity structure used by the tear-down procedure
@@ -152,6 +164,12 @@ var cleanupEntityList []CleanupEntity
 // Use this value to run a specific test that does not need a pre-created vApp.
 var skipVappCreation bool = os.Getenv("GOVCD_SKIP_VAPP_CREATION") != ""
 
+// Use GOVCD_FIXTURE_MODE=record to save the API interactions of the tests to the file
+// GOVCD_FIXTURE_FILE, and GOVCD_FIXTURE_MODE=replay to run the tests from that file
+// without a live vCD.
+var fixtureMode = os.Getenv("GOVCD_FIXTURE_MODE")
+var fixtureFile = os.Getenv("GOVCD_FIXTURE_FILE")
+
 // Adds an entity to the cleanup list.
 // To be called by all tests when a new entity has been created, before
 // running any other operation.
@@ -266,6 +284,13 @@ func (vcd *TestVCD) SetUpSuite(check *C) {
 		panic(err)
 	}
 	vcd.client = vcdClient
+	if fixtureMode != "" {
+		vcd.recorder, err = vcdtest.NewRecorder(fixtureFile, vcdtest.RecorderMode(fixtureMode), vcdClient.Client.Http.Transport)
+		if err != nil {
+			panic(err)
+		}
+		vcdClient.Client.Http.Transport = vcd.recorder
+	}
 	// org and vdc are the test org and vdc that is used in all other test cases
 	err = vcd.client.Authenticate(config.Provider.User, config.Provider.Password, config.Provider.SysOrg)
 	if err != nil {
@@ -493,6 +518,137 @@ func (vcd *TestVCD) removeLeftoverEntities(entity CleanupEntity) {
 	case "vm":
 		// nothing so far
 		return
+	case "globalRole":
+		globalRole, err := vcd.client.Client.GetGlobalRoleByName(entity.Name)
+		if err != nil {
+			vcd.infoCleanup(notFoundMsg, entity.EntityType, entity.Name)
+			return
+		}
+		err = globalRole.Delete()
+		if err == nil {
+			vcd.infoCleanup(removedMsg, entity.EntityType, entity.Name, entity.CreatedBy)
+		} else {
+			vcd.infoCleanup(notDeletedMsg, entity.EntityType, entity.Name, err)
+		}
+		return
+	case "role":
+		if entity.Parent == "" {
+			vcd.infoCleanup("removeLeftoverEntries: [ERROR] No ORG provided for role '%s'\n", entity.Name)
+			return
+		}
+		org, err := GetAdminOrgByName(vcd.client, entity.Parent)
+		if org == (AdminOrg{}) || err != nil {
+			vcd.infoCleanup(notFoundMsg, "org", entity.Parent)
+			return
+		}
+		role, err := org.GetRoleByName(entity.Name)
+		if err != nil {
+			vcd.infoCleanup(notFoundMsg, entity.EntityType, entity.Name)
+			return
+		}
+		err = role.Delete()
+		if err == nil {
+			vcd.infoCleanup(removedMsg, entity.EntityType, entity.Name, entity.CreatedBy)
+		} else {
+			vcd.infoCleanup(notDeletedMsg, entity.EntityType, entity.Name, err)
+		}
+		return
+	case "rightsBundle":
+		rightsBundle, err := vcd.client.Client.GetRightsBundleByName(entity.Name)
+		if err != nil {
+			vcd.infoCleanup(notFoundMsg, entity.EntityType, entity.Name)
+			return
+		}
+		err = rightsBundle.Delete()
+		if err == nil {
+			vcd.infoCleanup(removedMsg, entity.EntityType, entity.Name, entity.CreatedBy)
+		} else {
+			vcd.infoCleanup(notDeletedMsg, entity.EntityType, entity.Name, err)
+		}
+		return
+	case "vdcComputePolicy":
+		policy, err := vcd.client.Client.GetVdcComputePolicyByName(entity.Name)
+		if err != nil {
+			vcd.infoCleanup(notFoundMsg, entity.EntityType, entity.Name)
+			return
+		}
+		err = policy.Delete()
+		if err == nil {
+			vcd.infoCleanup(removedMsg, entity.EntityType, entity.Name, entity.CreatedBy)
+		} else {
+			vcd.infoCleanup(notDeletedMsg, entity.EntityType, entity.Name, err)
+		}
+		return
+	case "externalNetwork":
+		externalNetwork, err := GetExternalNetwork(vcd.client, entity.Name)
+		if err != nil {
+			vcd.infoCleanup(notFoundMsg, entity.EntityType, entity.Name)
+			return
+		}
+		err = externalNetwork.DeleteWait()
+		if err == nil {
+			vcd.infoCleanup(removedMsg, entity.EntityType, entity.Name, entity.CreatedBy)
+		} else {
+			vcd.infoCleanup(notDeletedMsg, entity.EntityType, entity.Name, err)
+		}
+		return
+	case "extensionService":
+		service, err := GetExtensionServiceByName(vcd.client, entity.Name)
+		if err != nil {
+			vcd.infoCleanup(notFoundMsg, entity.EntityType, entity.Name)
+			return
+		}
+		err = service.Delete()
+		if err == nil {
+			vcd.infoCleanup(removedMsg, entity.EntityType, entity.Name, entity.CreatedBy)
+		} else {
+			vcd.infoCleanup(notDeletedMsg, entity.EntityType, entity.Name, err)
+		}
+		return
+	case "group":
+		if entity.Parent == "" {
+			vcd.infoCleanup("removeLeftoverEntries: [ERROR] No ORG provided for group '%s'\n", entity.Name)
+			return
+		}
+		org, err := GetAdminOrgByName(vcd.client, entity.Parent)
+		if org == (AdminOrg{}) || err != nil {
+			vcd.infoCleanup(notFoundMsg, "org", entity.Parent)
+			return
+		}
+		group, err := org.GetGroupByName(entity.Name)
+		if err != nil {
+			vcd.infoCleanup(notFoundMsg, entity.EntityType, entity.Name)
+			return
+		}
+		err = group.Delete()
+		if err == nil {
+			vcd.infoCleanup(removedMsg, entity.EntityType, entity.Name, entity.CreatedBy)
+		} else {
+			vcd.infoCleanup(notDeletedMsg, entity.EntityType, entity.Name, err)
+		}
+		return
+	case "user":
+		if entity.Parent == "" {
+			vcd.infoCleanup("removeLeftoverEntries: [ERROR] No ORG provided for user '%s'\n", entity.Name)
+			return
+		}
+		org, err := GetAdminOrgByName(vcd.client, entity.Parent)
+		if org == (AdminOrg{}) || err != nil {
+			vcd.infoCleanup(notFoundMsg, "org", entity.Parent)
+			return
+		}
+		user, err := org.GetUserByName(entity.Name)
+		if err != nil {
+			vcd.infoCleanup(notFoundMsg, entity.EntityType, entity.Name)
+			return
+		}
+		err = user.Delete()
+		if err == nil {
+			vcd.infoCleanup(removedMsg, entity.EntityType, entity.Name, entity.CreatedBy)
+		} else {
+			vcd.infoCleanup(notDeletedMsg, entity.EntityType, entity.Name, err)
+		}
+		return
 	case "disk":
 		// Find disk by href rather than find disk by name, because disk name can be duplicated in VDC,
 		// so the unique href is required for finding the disk.
@@ -587,6 +743,12 @@ func (vcd *TestVCD) TearDownSuite(check *C) {
 	for _, cleanupEntity := range cleanupEntityList {
 		vcd.removeLeftoverEntities(cleanupEntity)
 	}
+	if vcd.recorder != nil {
+		err := vcd.recorder.Save()
+		if err != nil {
+			fmt.Printf("TearDownSuite: [ERROR] %s\n", err)
+		}
+	}
 }
 
 // Tests getloginurl with the endpoint given
@@ -8,9 +8,11 @@ import (
 	"fmt"
 	"net/http"
 	"sort"
+	"strings"
 
 	semver "github.com/hashicorp/go-version"
 
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
 	"github.com/vmware/go-vcloud-director/v2/util"
 )
 
@@ -50,7 +52,7 @@ func (vcdCli *VCDClient) APIVCDMaxVersionIs(versionConstraint string) bool {
 		return false
 	}
 
-	isSupported, err := vcdCli.apiVersionMatchesConstraint(maxVersion, versionConstraint)
+	isSupported, err := apiVersionMatchesConstraint(maxVersion, versionConstraint)
 	if err != nil {
 		util.Logger.Printf("[ERROR] unable to find max supported version : %s", err)
 		return false
@@ -67,10 +69,16 @@ func (vcdCli *VCDClient) APIVCDMaxVersionIs(versionConstraint string) bool {
 //
 // vCD version mapping to API version support https://code.vmware.com/doc/preview?id=8072
 func (vcdCli *VCDClient) APIClientVersionIs(versionConstraint string) bool {
+	return vcdCli.Client.APIClientVersionIs(versionConstraint)
+}
+
+// APIClientVersionIs allows to compare against currently used API version Client.APIVersion.
+// It is the same as VCDClient.APIClientVersionIs, for functions which only have access to a Client.
+func (cli *Client) APIClientVersionIs(versionConstraint string) bool {
 
 	util.Logger.Printf("[TRACE] checking current API version against constraints '%s'", versionConstraint)
 
-	isSupported, err := vcdCli.apiVersionMatchesConstraint(vcdCli.Client.APIVersion, versionConstraint)
+	isSupported, err := apiVersionMatchesConstraint(cli.APIVersion, versionConstraint)
 	if err != nil {
 		util.Logger.Printf("[ERROR] unable to find cur supported version : %s", err)
 		return false
@@ -79,6 +87,98 @@ func (vcdCli *VCDClient) APIClientVersionIs(versionConstraint string) bool {
 	return isSupported
 }
 
+// VcdVersion is the product version of vCD, as opposed to the API version
+type VcdVersion struct {
+	Version   *semver.Version // Full version with build number, e.g. 9.7.0.13635483
+	BuildDate string          // Build date, as reported by vCD
+}
+
+// GetVcdFullVersion retrieves the product version of vCD, with build number and build date.
+// Unlike the API version, it identifies the exact build of vCD. Requires system administrator privileges.
+func (vcdCli *VCDClient) GetVcdFullVersion() (VcdVersion, error) {
+	adminHREF := vcdCli.Client.VCDHREF
+	adminHREF.Path += "/admin"
+
+	vcloud := &types.VCloud{}
+	_, err := vcdCli.Client.ExecuteRequest(adminHREF.String(), http.MethodGet,
+		"", "error retrieving vCD version: %s", nil, vcloud)
+	if err != nil {
+		return VcdVersion{}, err
+	}
+	return parseVcdVersion(vcloud.Description)
+}
+
+// GetVcdShortVersion retrieves the product version of vCD without build number, e.g. 9.7.0
+func (vcdCli *VCDClient) GetVcdShortVersion() (string, error) {
+	vcdVersion, err := vcdCli.GetVcdFullVersion()
+	if err != nil {
+		return "", err
+	}
+	return vcdVersion.ShortVersion(), nil
+}
+
+// VcdVersionIs compares the product version of vCD against a constraint. The build number can be
+// used in the constraint, e.g. ">= 9.7.0.13635483" or ">= 9.5, < 10.0".
+func (vcdCli *VCDClient) VcdVersionIs(versionConstraint string) (bool, error) {
+	vcdVersion, err := vcdCli.GetVcdFullVersion()
+	if err != nil {
+		return false, err
+	}
+	return vcdVersion.Matches(versionConstraint)
+}
+
+// ShortVersion returns the version without build number, e.g. 9.7.0
+func (vcdVersion VcdVersion) ShortVersion() string {
+	if vcdVersion.Version == nil {
+		return ""
+	}
+	segments := vcdVersion.Version.Segments()
+	if len(segments) > 3 {
+		segments = segments[:3]
+	}
+	var digits []string
+	for _, segment := range segments {
+		digits = append(digits, fmt.Sprintf("%d", segment))
+	}
+	return strings.Join(digits, ".")
+}
+
+// Matches returns true if the version satisfies the constraint, e.g. ">= 9.7.0.13635483"
+func (vcdVersion VcdVersion) Matches(versionConstraint string) (bool, error) {
+	if vcdVersion.Version == nil {
+		return false, fmt.Errorf("vCD version is empty")
+	}
+	return apiVersionMatchesConstraint(vcdVersion.Version.Original(), versionConstraint)
+}
+
+// CompareVersions compares two vCD or API versions, with any number of segments. It returns -1
+// when version1 is lower than version2, 0 when they are equal and 1 when version1 is greater.
+func CompareVersions(version1, version2 string) (int, error) {
+	v1, err := semver.NewVersion(version1)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse version %s: %s", version1, err)
+	}
+	v2, err := semver.NewVersion(version2)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse version %s: %s", version2, err)
+	}
+	return v1.Compare(v2), nil
+}
+
+// parseVcdVersion extracts the version and the build date from the description of the VCloud
+// entity, which has the format "9.7.0.13635483 Wed May 29 2019 04:44:06 GMT"
+func parseVcdVersion(description string) (VcdVersion, error) {
+	fields := strings.Fields(description)
+	if len(fields) == 0 {
+		return VcdVersion{}, fmt.Errorf("no vCD version found in '%s'", description)
+	}
+	version, err := semver.NewVersion(fields[0])
+	if err != nil {
+		return VcdVersion{}, fmt.Errorf("unable to parse vCD version '%s': %s", fields[0], err)
+	}
+	return VcdVersion{Version: version, BuildDate: strings.Join(fields[1:], " ")}, nil
+}
+
 // vcdFetchSupportedVersions retrieves list of supported versions from
 // /api/versions endpoint and stores them in VCDClient for future uses.
 // It only does it once.
@@ -132,7 +232,7 @@ func (vcdCli *VCDClient) vcdCheckSupportedVersion(version string) (bool, error)
 // Constraint format can be in format ">= 27.0, < 32",">= 30" ,"= 27.0".
 func (vcdCli *VCDClient) checkSupportedVersionConstraint(versionConstraint string) (bool, error) {
 	for _, versionInfo := range vcdCli.supportedVersions.VersionInfos {
-		versionMatch, err := vcdCli.apiVersionMatchesConstraint(versionInfo.Version, versionConstraint)
+		versionMatch, err := apiVersionMatchesConstraint(versionInfo.Version, versionConstraint)
 		if err != nil {
 			return false, fmt.Errorf("cannot match version: %s", err)
 		}
@@ -144,7 +244,7 @@ func (vcdCli *VCDClient) checkSupportedVersionConstraint(versionConstraint strin
 	return false, fmt.Errorf("version %s is not supported", versionConstraint)
 }
 
-func (vcdCli *VCDClient) apiVersionMatchesConstraint(version, versionConstraint string) (bool, error) {
+func apiVersionMatchesConstraint(version, versionConstraint string) (bool, error) {
 
 	checkVer, err := semver.NewVersion(version)
 	if err != nil {
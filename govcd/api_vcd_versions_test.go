@@ -6,6 +6,7 @@ package govcd
 
 import (
 	"fmt"
+	"testing"
 
 	. "gopkg.in/check.v1"
 )
@@ -109,6 +110,93 @@ func (vcd *TestVCD) Test_validateAPIVersion(check *C) {
 	check.Assert(err, ErrorMatches, "API version .* is not supported: version = .* is not supported")
 }
 
+// Test_GetVcdFullVersion checks that the product version of vCD and the version of its cells match
+func (vcd *TestVCD) Test_GetVcdFullVersion(check *C) {
+	if vcd.skipAdminTests {
+		check.Skip(fmt.Sprintf(TestRequiresSysAdminPrivileges, check.TestName()))
+	}
+	vcdVersion, err := vcd.client.GetVcdFullVersion()
+	check.Assert(err, IsNil)
+	check.Assert(len(vcdVersion.Version.Segments()) >= 3, Equals, true)
+
+	shortVersion, err := vcd.client.GetVcdShortVersion()
+	check.Assert(err, IsNil)
+	check.Assert(shortVersion, Equals, vcdVersion.ShortVersion())
+
+	matches, err := vcd.client.VcdVersionIs(">= 8.20")
+	check.Assert(err, IsNil)
+	check.Assert(matches, Equals, true)
+
+	cells, err := QueryCells(vcd.client)
+	check.Assert(err, IsNil)
+	check.Assert(len(cells) > 0, Equals, true)
+	for _, cell := range cells {
+		check.Assert(cell.Name, Not(Equals), "")
+		if cell.IsActive == 1 {
+			comparison, err := CompareVersions(cell.Version, vcdVersion.Version.Original())
+			check.Assert(err, IsNil)
+			check.Assert(comparison, Equals, 0)
+		}
+	}
+}
+
+func TestParseVcdVersion(t *testing.T) {
+	vcdVersion, err := parseVcdVersion("9.7.0.13635483 Wed May 29 2019 04:44:06 GMT")
+	if err != nil {
+		t.Fatalf("error parsing vCD version: %s", err)
+	}
+	if vcdVersion.Version.Original() != "9.7.0.13635483" || vcdVersion.BuildDate != "Wed May 29 2019 04:44:06 GMT" {
+		t.Errorf("unexpected vCD version %#v", vcdVersion)
+	}
+	if vcdVersion.ShortVersion() != "9.7.0" {
+		t.Errorf("unexpected short version %s", vcdVersion.ShortVersion())
+	}
+	var constraintTests = []struct {
+		constraint string
+		matches    bool
+	}{
+		{">= 9.7", true},
+		{">= 9.7.0.13635483", true},
+		{"> 9.7.0.13635483", false},
+		{">= 9.5, < 10.0", true},
+		{">= 10.0", false},
+	}
+	for _, tt := range constraintTests {
+		matches, err := vcdVersion.Matches(tt.constraint)
+		if err != nil || matches != tt.matches {
+			t.Errorf("expected %t for constraint '%s', got %t (%v)", tt.matches, tt.constraint, matches, err)
+		}
+	}
+
+	for _, description := range []string{"", "not-a-version"} {
+		if _, err := parseVcdVersion(description); err == nil {
+			t.Errorf("expected error parsing '%s'", description)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	var versionTests = []struct {
+		version1 string
+		version2 string
+		expected int
+	}{
+		{"9.7.0.13635483", "9.7.0.13635483", 0},
+		{"9.7.0", "9.7.0.13635483", -1},
+		{"10.0.0.15406335", "9.7.0.13635483", 1},
+		{"31.0", "27.0", 1},
+	}
+	for _, tt := range versionTests {
+		result, err := CompareVersions(tt.version1, tt.version2)
+		if err != nil || result != tt.expected {
+			t.Errorf("expected %d comparing %s and %s, got %d (%v)", tt.expected, tt.version1, tt.version2, result, err)
+		}
+	}
+	if _, err := CompareVersions("invalid", "9.7"); err == nil {
+		t.Errorf("expected error comparing invalid version")
+	}
+}
+
 func getMockVcdWithAPIVersion(version string) *VCDClient {
 	return &VCDClient{
 		Client: Client{
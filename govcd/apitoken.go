@@ -0,0 +1,218 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/util"
+)
+
+// ApiToken is an API token, which can be used by automation instead of user and password.
+// API tokens are available in vCD 10.3.1+.
+type ApiToken struct {
+	Token  *types.Token
+	client *Client
+}
+
+// CreateApiToken creates an API token with the given name for the current user, who belongs to
+// org. It returns the token entry and the API token itself, which vCD shows only once and
+// needs to be stored by the caller.
+// A token is created by registering an OAuth client and exchanging the bearer token of the
+// current session for a refresh token, which is the API token.
+func (client *Client) CreateApiToken(org, tokenName string) (*ApiToken, string, error) {
+	if org == "" || tokenName == "" {
+		return nil, "", fmt.Errorf("org and token name are required to create an API token")
+	}
+	if client.VCDAccessToken == "" {
+		return nil, "", fmt.Errorf("cannot create API token: the session has no bearer token")
+	}
+	apiVersion, err := getOpenApiVersion(types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointTokens)
+	if err != nil {
+		return nil, "", err
+	}
+
+	existing, err := client.GetAllApiTokens(url.Values{"filter": []string{fiqlEq("name", tokenName)}})
+	if err != nil {
+		return nil, "", err
+	}
+	if len(existing) > 0 {
+		return nil, "", fmt.Errorf("an API token named '%s' already exists", tokenName)
+	}
+
+	registerUrl, err := client.oauthBuildEndpoint(org, "register")
+	if err != nil {
+		return nil, "", err
+	}
+	payload, err := json.Marshal(&types.ApiTokenClient{ClientName: tokenName})
+	if err != nil {
+		return nil, "", fmt.Errorf("error marshalling API token client: %s", err)
+	}
+	tokenClient := &types.ApiTokenClient{}
+	err = client.oauthPost(apiVersion, registerUrl, "application/json", bytes.NewBuffer(payload), tokenClient)
+	if err != nil {
+		return nil, "", fmt.Errorf("error registering API token client: %s", err)
+	}
+
+	tokenUrl, err := client.oauthBuildEndpoint(org, "token")
+	if err != nil {
+		return nil, "", err
+	}
+	form := url.Values{}
+	form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	form.Set("client_id", tokenClient.ClientID)
+	form.Set("assertion", client.VCDAccessToken)
+	refresh := &types.ApiTokenRefresh{}
+	err = client.oauthPost(apiVersion, tokenUrl, "application/x-www-form-urlencoded", strings.NewReader(form.Encode()), refresh)
+	if err != nil {
+		return nil, "", fmt.Errorf("error creating API token: %s", err)
+	}
+
+	apiToken, err := client.GetApiTokenByName(tokenName)
+	if err != nil {
+		return nil, "", err
+	}
+	return apiToken, refresh.RefreshToken, nil
+}
+
+// GetAllApiTokens retrieves the API tokens visible to the current user. Users see their own
+// tokens, while administrators with the right to manage all API tokens see the tokens of the
+// users they administer. Query parameters can be supplied to perform additional filtering
+// (e.g. "filter" => "owner.name==user1")
+func (client *Client) GetAllApiTokens(queryParameters url.Values) ([]*ApiToken, error) {
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointTokens
+	apiVersion, err := getOpenApiVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	// The endpoint also lists other kinds of tokens: only API tokens are retrieved
+	params := queryParameterFilterAnd(fiqlEq("type", types.ApiTokenType), queryParameters)
+
+	var typeResponses []*types.Token
+	err = client.OpenApiGetAllItems(apiVersion, urlRef, params, &typeResponses)
+	if err != nil {
+		return nil, err
+	}
+
+	apiTokens := make([]*ApiToken, len(typeResponses))
+	for index, typeResponse := range typeResponses {
+		apiTokens[index] = &ApiToken{Token: typeResponse, client: client}
+	}
+	return apiTokens, nil
+}
+
+// GetAllApiTokensForUser retrieves the API tokens owned by the user with the given name
+func (client *Client) GetAllApiTokensForUser(userName string) ([]*ApiToken, error) {
+	return client.GetAllApiTokens(url.Values{"filter": []string{fiqlEq("owner.name", userName)}})
+}
+
+// GetApiTokenByName retrieves the API token of the current user with the given name
+func (client *Client) GetApiTokenByName(name string) (*ApiToken, error) {
+	apiTokens, err := client.GetAllApiTokens(url.Values{"filter": []string{fiqlEq("name", name)}})
+	if err != nil {
+		return nil, err
+	}
+	if len(apiTokens) == 0 {
+		return nil, fmt.Errorf("API token '%s' not found", name)
+	}
+	if len(apiTokens) > 1 {
+		return nil, fmt.Errorf("more than one API token found with name '%s'", name)
+	}
+	return apiTokens[0], nil
+}
+
+// GetApiTokenById retrieves the API token with the given ID
+func (client *Client) GetApiTokenById(id string) (*ApiToken, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty API token ID")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointTokens
+	apiVersion, err := getOpenApiVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint, id)
+	if err != nil {
+		return nil, err
+	}
+
+	apiToken := &ApiToken{Token: &types.Token{}, client: client}
+	err = client.OpenApiGetItem(apiVersion, urlRef, nil, apiToken.Token)
+	if err != nil {
+		return nil, err
+	}
+	return apiToken, nil
+}
+
+// Revoke deletes the API token. Automation using it will not be able to log in anymore.
+func (apiToken *ApiToken) Revoke() error {
+	if apiToken.Token.ID == "" {
+		return fmt.Errorf("cannot revoke API token without ID")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointTokens
+	apiVersion, err := getOpenApiVersion(endpoint)
+	if err != nil {
+		return err
+	}
+	urlRef, err := apiToken.client.OpenApiBuildEndpoint(endpoint, apiToken.Token.ID)
+	if err != nil {
+		return err
+	}
+	err = apiToken.client.OpenApiDeleteItem(apiVersion, urlRef, nil)
+	if err != nil {
+		return fmt.Errorf("error revoking API token: %s", err)
+	}
+	return nil
+}
+
+// oauthBuildEndpoint builds the URL of an OAuth endpoint for the given org.
+// The provider uses /oauth/provider/, while tenants use /oauth/tenant/ORG_NAME/
+func (client *Client) oauthBuildEndpoint(org, endpoint string) (*url.URL, error) {
+	path := "/oauth/tenant/" + url.PathEscape(org) + "/"
+	if strings.ToLower(org) == "system" {
+		path = "/oauth/provider/"
+	}
+	urlRef, err := url.ParseRequestURI(client.VCDHREF.Scheme + "://" + client.VCDHREF.Host + path + endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error formatting OAuth endpoint: %s", err)
+	}
+	return urlRef, nil
+}
+
+// oauthPost sends a POST request to an OAuth endpoint, authenticated with the bearer token of
+// the session, and decodes the JSON response into outType
+func (client *Client) oauthPost(apiVersion string, urlRef *url.URL, contentType string, body io.Reader, outType interface{}) error {
+	util.Logger.Printf("[TRACE] POST to OAuth endpoint %s", urlRef.String())
+
+	req, _ := http.NewRequest(http.MethodPost, urlRef.String(), body)
+	req = req.WithContext(client.Context())
+	client.setUserAgent(req)
+	client.clearEntityCacheForChange(http.MethodPost)
+	req.Header.Add("Authorization", "Bearer "+client.VCDAccessToken)
+	req.Header.Add("Accept", "application/json;version="+apiVersion)
+	req.Header.Add("Content-Type", contentType)
+
+	resp, err := checkOpenApiResp(client.Http.Do(req))
+	if err != nil {
+		return err
+	}
+	if err = decodeJsonBody(resp, outType); err != nil {
+		_ = resp.Body.Close()
+		return fmt.Errorf("error decoding JSON response: %s", err)
+	}
+	return resp.Body.Close()
+}
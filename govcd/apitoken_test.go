@@ -0,0 +1,102 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+	. "gopkg.in/check.v1"
+)
+
+func (vcd *TestVCD) Test_ApiTokens(check *C) {
+	if vcd.client.APIVCDMaxVersionIs("< 36.1") {
+		check.Skip("API tokens require vCD 10.3.1+")
+	}
+	client := &vcd.client.Client
+
+	tokenName := check.TestName()
+	apiToken, token, err := client.CreateApiToken(vcd.config.VCD.Org, tokenName)
+	check.Assert(err, IsNil)
+	check.Assert(token, Not(Equals), "")
+	check.Assert(apiToken.Token.Name, Equals, tokenName)
+
+	apiTokens, err := client.GetAllApiTokens(nil)
+	check.Assert(err, IsNil)
+	found := false
+	for _, t := range apiTokens {
+		if t.Token.ID == apiToken.Token.ID {
+			found = true
+		}
+	}
+	check.Assert(found, Equals, true)
+
+	apiTokenById, err := client.GetApiTokenById(apiToken.Token.ID)
+	check.Assert(err, IsNil)
+	check.Assert(apiTokenById.Token.Name, Equals, tokenName)
+
+	// Token names are unique for a user
+	_, _, err = client.CreateApiToken(vcd.config.VCD.Org, tokenName)
+	check.Assert(err, NotNil)
+
+	err = apiToken.Revoke()
+	check.Assert(err, IsNil)
+	_, err = client.GetApiTokenByName(tokenName)
+	check.Assert(err, NotNil)
+}
+
+// Checks the OAuth exchange performed by CreateApiToken, using a local test server
+func TestClient_CreateApiToken(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	const oauthPath = "/oauth/tenant/" + vcdtest.MockOrgName
+	created := false
+	server.HandleFunc(http.MethodPost, oauthPath+"/register", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+vcdtest.MockAccessToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"client_id":"client-1","client_name":"token1"}`)
+	})
+	server.HandleFunc(http.MethodPost, oauthPath+"/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.PostForm.Get("client_id") != "client-1" || r.PostForm.Get("assertion") != vcdtest.MockAccessToken {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		created = true
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"access_token":"access","token_type":"Bearer","expires_in":3600,"refresh_token":"api-token"}`)
+	})
+	server.HandleFunc(http.MethodGet, "/cloudapi/1.0.0/tokens/", func(w http.ResponseWriter, r *http.Request) {
+		values := "[]"
+		if created && r.URL.Query().Get("filter") == "name==token1;type==REFRESH" {
+			values = `[{"id":"urn:vcloud:token:1","name":"token1","type":"REFRESH"}]`
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"resultTotal":1,"pageCount":1,"page":1,"pageSize":128,"values":%s}`, values)
+	})
+
+	client := &newMockClient(t, server).Client
+
+	apiToken, token, err := client.CreateApiToken(vcdtest.MockOrgName, "token1")
+	if err != nil {
+		t.Fatalf("error creating API token: %s", err)
+	}
+	if token != "api-token" {
+		t.Errorf("unexpected API token: %s", token)
+	}
+	if apiToken.Token.ID != "urn:vcloud:token:1" {
+		t.Errorf("unexpected API token entry: %#v", apiToken.Token)
+	}
+
+	_, _, err = client.CreateApiToken(vcdtest.MockOrgName, "token1")
+	if err == nil {
+		t.Errorf("expected error when creating a token with a duplicate name")
+	}
+}
@@ -0,0 +1,142 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// auditTrailTimeFormat is the format of the timestamps in the filters of the audit trail and of
+// the event query
+const auditTrailTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// AuditTrailFilter selects the events of the audit trail. All the conditions set must be
+// satisfied. The same filter can be used with the audit trail (GetAuditTrail) and with the
+// legacy event query (see EventQueryOptions), to correlate the operations of the SDK with the
+// events recorded by vCD.
+type AuditTrailFilter struct {
+	From      time.Time // Events recorded at or after this time. Not checked when zero
+	To        time.Time // Events recorded before this time. Not checked when zero
+	EventType string    // Type of the events, e.g. com/vmware/vcloud/event/vm/create
+	User      string    // Name of the user who triggered the events
+	PageSize  int       // Number of events per page. The default of the function is used when 0
+	// Page to retrieve, starting from 1. All the pages are retrieved when 0
+	Page int
+}
+
+// validate checks the consistency of the filter
+func (filter *AuditTrailFilter) validate() error {
+	if filter.PageSize < 0 || filter.Page < 0 {
+		return fmt.Errorf("page and page size cannot be negative")
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return fmt.Errorf("the start of the time range (%s) must be before its end (%s)",
+			filter.From.Format(time.RFC3339), filter.To.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// GetAuditTrail retrieves the events of the audit trail (cloudapi auditTrail endpoint) selected by
+// filter, which can be nil, sorted by time. System administrators see the events of all orgs.
+// Audit trail is available in vCD 10.0+ (API 33.0).
+func (client *Client) GetAuditTrail(filter *AuditTrailFilter) ([]*types.AuditTrailEvent, error) {
+	if filter == nil {
+		filter = &AuditTrailFilter{}
+	}
+	err := filter.validate()
+	if err != nil {
+		return nil, err
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointAuditTrail
+	apiVersion, err := client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var conditions []string
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "timestamp=ge="+fiqlValueEscaper.Replace(filter.From.UTC().Format(auditTrailTimeFormat)))
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "timestamp=lt="+fiqlValueEscaper.Replace(filter.To.UTC().Format(auditTrailTimeFormat)))
+	}
+	if filter.EventType != "" {
+		conditions = append(conditions, fiqlEq("eventType", filter.EventType))
+	}
+	if filter.User != "" {
+		conditions = append(conditions, fiqlEq("user.name", filter.User))
+	}
+	params := url.Values{}
+	if len(conditions) > 0 {
+		params.Set("filter", strings.Join(conditions, ";"))
+	}
+	params.Set("sortAsc", "timestamp")
+	if filter.PageSize > 0 {
+		params.Set("pageSize", strconv.Itoa(filter.PageSize))
+	}
+
+	var events []*types.AuditTrailEvent
+	if filter.Page == 0 {
+		err = client.OpenApiGetAllItems(apiVersion, urlRef, params, &events)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving audit trail: %s", err)
+		}
+		return events, nil
+	}
+
+	params.Set("page", strconv.Itoa(filter.Page))
+	page := types.OpenApiPages{}
+	err = client.OpenApiGetItem(apiVersion, urlRef, params, &page)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving page %d of audit trail: %s", filter.Page, err)
+	}
+	if len(page.Values) > 0 {
+		err = json.Unmarshal(page.Values, &events)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding page %d of audit trail: %s", filter.Page, err)
+		}
+	}
+	return events, nil
+}
+
+// EventQueryOptions returns the options of the legacy event query (QueryEvents, QueryAdminEvents
+// or Query with types.QtEvent) matching the filter, sorted by time. The legacy query is available
+// in all the versions of vCD.
+func (filter *AuditTrailFilter) EventQueryOptions() (*QueryOptions, error) {
+	err := filter.validate()
+	if err != nil {
+		return nil, err
+	}
+	queryFilter := NewQueryFilter()
+	if !filter.From.IsZero() {
+		queryFilter.GreaterOrEqual("timeStamp", filter.From.UTC().Format(auditTrailTimeFormat))
+	}
+	if !filter.To.IsZero() {
+		queryFilter.LessThan("timeStamp", filter.To.UTC().Format(auditTrailTimeFormat))
+	}
+	if filter.EventType != "" {
+		queryFilter.Equal("eventType", filter.EventType)
+	}
+	if filter.User != "" {
+		queryFilter.Equal("userName", filter.User)
+	}
+	return &QueryOptions{
+		Filter:   queryFilter,
+		SortAsc:  "timeStamp",
+		PageSize: filter.PageSize,
+		Page:     filter.Page,
+	}, nil
+}
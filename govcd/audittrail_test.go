@@ -0,0 +1,99 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// Checks the filters and the pagination of the audit trail and of the event query, against a fake vCD
+func TestClient_GetAuditTrail(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	const auditTrailPath = "/cloudapi/1.0.0/auditTrail/"
+	server.HandleJSON(http.MethodGet, auditTrailPath, http.StatusOK,
+		`{"resultTotal":2,"pageCount":1,"page":1,"pageSize":128,"values":[
+		  {"eventId":"1","eventType":"com/vmware/vcloud/event/vm/create","timestamp":"2021-03-01T10:00:00.000Z","user":{"name":"user1"}},
+		  {"eventId":"2","eventType":"com/vmware/vcloud/event/vm/create","timestamp":"2021-03-01T11:00:00.000Z","user":{"name":"user1"}}]}`)
+	server.HandleXML(http.MethodGet, "/api/query", http.StatusOK,
+		`<QueryResultRecords xmlns="http://www.vmware.com/vcloud/v1.5" total="1" page="1" pageSize="128">
+		  <EventRecord eventType="com/vmware/vcloud/event/vm/create" userName="user1" timeStamp="2021-03-01T10:00:00.000Z" entityName="vm1"/>
+		</QueryResultRecords>`)
+
+	vcdClient := newMockClient(t, server)
+	client := &vcdClient.Client
+
+	from := time.Date(2021, 3, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+	filter := &AuditTrailFilter{
+		From:      from,
+		To:        from.Add(24 * time.Hour),
+		EventType: "com/vmware/vcloud/event/vm/create",
+		User:      "user1",
+	}
+	events, err := client.GetAuditTrail(filter)
+	if err != nil {
+		t.Fatalf("error retrieving audit trail: %s", err)
+	}
+	if len(events) != 2 || events[1].EventID != "2" || events[1].User == nil || events[1].User.Name != "user1" {
+		t.Errorf("unexpected events: %#v", events)
+	}
+	requests := server.RequestsTo(http.MethodGet, auditTrailPath)
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 audit trail request, got %d", len(requests))
+	}
+	query, _ := url.ParseQuery(requests[0].RawQuery)
+	expectedFilter := "timestamp=ge=2021-03-01T11:00:00.000Z;timestamp=lt=2021-03-02T11:00:00.000Z;" +
+		"eventType==com/vmware/vcloud/event/vm/create;user.name==user1"
+	if query.Get("filter") != expectedFilter || query.Get("sortAsc") != "timestamp" || query.Get("page") != "1" {
+		t.Errorf("unexpected audit trail query: %s", requests[0].RawQuery)
+	}
+
+	// A single page
+	server.ClearRequests()
+	_, err = client.GetAuditTrail(&AuditTrailFilter{PageSize: 10, Page: 3})
+	if err != nil {
+		t.Fatalf("error retrieving audit trail page: %s", err)
+	}
+	requests = server.RequestsTo(http.MethodGet, auditTrailPath)
+	query, _ = url.ParseQuery(requests[0].RawQuery)
+	if len(requests) != 1 || query.Get("page") != "3" || query.Get("pageSize") != "10" || query.Get("filter") != "" {
+		t.Errorf("unexpected audit trail page query: %v", requests)
+	}
+
+	for _, invalid := range []*AuditTrailFilter{{From: from, To: from}, {Page: -1}} {
+		if _, err = client.GetAuditTrail(invalid); err == nil {
+			t.Errorf("expected error with filter %#v", invalid)
+		}
+	}
+
+	// The legacy event query with the same filter
+	options, err := filter.EventQueryOptions()
+	if err != nil {
+		t.Fatalf("error building event query options: %s", err)
+	}
+	records, err := client.QueryEvents(options)
+	if err != nil {
+		t.Fatalf("error querying events: %s", err)
+	}
+	if len(records) != 1 || records[0].EntityName != "vm1" {
+		t.Errorf("unexpected event records: %#v", records)
+	}
+	// The filter of the query service is not encoded, so it cannot be parsed as a query string
+	requests = server.RequestsTo(http.MethodGet, "/api/query")
+	expectedFilter = "filter=(timeStamp=ge=2021-03-01T11%3A00%3A00.000Z;timeStamp=lt=2021-03-02T11%3A00%3A00.000Z;" +
+		"eventType==com%2Fvmware%2Fvcloud%2Fevent%2Fvm%2Fcreate;userName==user1)"
+	if !strings.Contains(requests[0].RawQuery, "type="+types.QtEvent) || !strings.Contains(requests[0].RawQuery, expectedFilter) ||
+		!strings.Contains(requests[0].RawQuery, "sortAsc=timeStamp") {
+		t.Errorf("unexpected event query: %s", requests[0].RawQuery)
+	}
+}
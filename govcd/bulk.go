@@ -0,0 +1,250 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/util"
+)
+
+// RunParallel runs the given functions, each of which starts a task, and waits for the
+// completion of their tasks. At most concurrency functions run at the same time, which bounds
+// the number of requests and of running tasks sent to vCD. A function which has nothing to do
+// can return an empty Task and no error.
+// All the functions are run even when some of them fail. The returned error lists all the
+// failures, with the index of the function which failed.
+func RunParallel(tasksFn []func() (Task, error), concurrency int) error {
+	if concurrency <= 0 {
+		return fmt.Errorf("concurrency must be positive, got %d", concurrency)
+	}
+
+	errors := make([]error, len(tasksFn))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for index, taskFn := range tasksFn {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(index int, taskFn func() (Task, error)) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			task, err := taskFn()
+			if err == nil && task.Task != nil {
+				err = task.WaitTaskCompletion()
+			}
+			errors[index] = err
+		}(index, taskFn)
+	}
+	wg.Wait()
+
+	return joinIndexedErrors(errors, "operations")
+}
+
+// joinIndexedErrors returns an error listing the errors which are not nil, with their index, or
+// nil when there is none. what names the items which failed, e.g. "operations".
+func joinIndexedErrors(errors []error, what string) error {
+	var failures []string
+	for index, err := range errors {
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("[%d] %s", index, err))
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("%d of %d %s failed: %s", len(failures), len(errors), what, strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// TaskGroupProgressFunc receives the progress of a task group: the number of tasks completed, the
+// number of tasks, and the average progress of the tasks (0-100)
+type TaskGroupProgressFunc func(completed, total, progress int)
+
+// TaskGroup waits for the completion of many tasks already started, e.g. the ones of the
+// composition of many VMs or of the deletion of many vApps. The tasks are polled concurrently.
+//
+//	group := NewTaskGroup(tasks...)
+//	group.Timeout = 30 * time.Minute
+//	err := group.WaitAll()
+type TaskGroup struct {
+	Tasks []Task
+
+	// Concurrency is the maximum number of tasks polled at the same time, all of them when 0
+	Concurrency int
+	// PollInterval is the delay between two refreshes of a task, 3 seconds when 0
+	PollInterval time.Duration
+	// Timeout is the time given to the whole group, after which the tasks still running are not
+	// waited for anymore and reported as failed. No limit when 0.
+	Timeout time.Duration
+	// Progress, when set, is called every time a task completes or reports a new progress
+	Progress TaskGroupProgressFunc
+}
+
+// NewTaskGroup returns a task group with the given tasks
+func NewTaskGroup(tasks ...Task) *TaskGroup {
+	return &TaskGroup{Tasks: tasks}
+}
+
+// Add adds a task to the group
+func (group *TaskGroup) Add(task Task) {
+	group.Tasks = append(group.Tasks, task)
+}
+
+// WaitAll waits for the completion of all the tasks of the group. Empty tasks are ignored. The
+// returned error lists all the tasks which failed or were still running at the end of the
+// timeout, with their index.
+func (group *TaskGroup) WaitAll() error {
+	if group.Concurrency < 0 || group.PollInterval < 0 || group.Timeout < 0 {
+		return fmt.Errorf("task group concurrency, poll interval and timeout cannot be negative")
+	}
+	concurrency := group.Concurrency
+	if concurrency == 0 || concurrency > len(group.Tasks) {
+		concurrency = len(group.Tasks)
+	}
+	pollInterval := group.PollInterval
+	if pollInterval == 0 {
+		pollInterval = 3 * time.Second
+	}
+	var deadline time.Time
+	if group.Timeout > 0 {
+		deadline = time.Now().Add(group.Timeout)
+	}
+
+	var mutex sync.Mutex
+	progresses := make([]int, len(group.Tasks))
+	completed := 0
+	// report records the progress of a task, and passes the progress of the group to the caller
+	report := func(index, progress int, done bool) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if done {
+			completed++
+			progress = 100
+		} else if progress == progresses[index] {
+			return
+		}
+		progresses[index] = progress
+		if group.Progress == nil {
+			return
+		}
+		total := 0
+		for _, taskProgress := range progresses {
+			total += taskProgress
+		}
+		group.Progress(completed, len(progresses), total/len(progresses))
+	}
+
+	errors := make([]error, len(group.Tasks))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for index, task := range group.Tasks {
+		if task.Task == nil {
+			report(index, 100, true)
+			continue
+		}
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(index int, task Task) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			if !deadline.IsZero() {
+				ctx, cancel := context.WithDeadline(task.client.Context(), deadline)
+				defer cancel()
+				task = *task.WithContext(ctx)
+			}
+			errors[index] = task.WaitInspectTaskCompletion(func(inspected *types.Task, howManyTimes int, elapsed time.Duration, first, last bool) {
+				if inspected.Status == "queued" || inspected.Status == "preRunning" || inspected.Status == "running" {
+					report(index, inspected.Progress, false)
+				}
+			}, pollInterval)
+			report(index, 100, true)
+		}(index, task)
+	}
+	wg.Wait()
+
+	return joinIndexedErrors(errors, "tasks")
+}
+
+// PowerOnAllVApps powers on all the vApps of the VDC which are not powered on yet, with at most
+// concurrency operations at the same time
+func (vdc *Vdc) PowerOnAllVApps(concurrency int) error {
+	util.Logger.Printf("[TRACE] Vdc.PowerOnAllVApps - powering on vApps of %s", vdc.Vdc.Name)
+
+	vapps, err := vdc.getAllVApps()
+	if err != nil {
+		return err
+	}
+	var tasksFn []func() (Task, error)
+	for _, vapp := range vapps {
+		vapp := vapp
+		tasksFn = append(tasksFn, func() (Task, error) {
+			if types.VAppStatuses[vapp.VApp.Status] == "POWERED_ON" {
+				return Task{}, nil
+			}
+			return vapp.PowerOn()
+		})
+	}
+	return RunParallel(tasksFn, concurrency)
+}
+
+// DeleteVApps deletes the given vApps, undeploying them first when needed, with at most
+// concurrency operations at the same time
+func DeleteVApps(vapps []*VApp, concurrency int) error {
+	var tasksFn []func() (Task, error)
+	for _, vapp := range vapps {
+		vapp := vapp
+		tasksFn = append(tasksFn, func() (Task, error) {
+			err := vapp.Refresh()
+			if err != nil {
+				return Task{}, err
+			}
+			if vapp.VApp.Deployed {
+				task, err := vapp.Undeploy()
+				if err != nil {
+					return Task{}, err
+				}
+				err = task.WaitTaskCompletion()
+				if err != nil {
+					return Task{}, fmt.Errorf("error undeploying vApp %s: %s", vapp.VApp.Name, err)
+				}
+			}
+			return vapp.Delete()
+		})
+	}
+	return RunParallel(tasksFn, concurrency)
+}
+
+// getAllVApps retrieves all the vApps of the VDC
+func (vdc *Vdc) getAllVApps() ([]*VApp, error) {
+	err := vdc.Refresh()
+	if err != nil {
+		return nil, fmt.Errorf("error refreshing vdc: %s", err)
+	}
+	var vapps []*VApp
+	for _, resourceEntities := range vdc.Vdc.ResourceEntities {
+		for _, resourceEntity := range resourceEntities.ResourceEntity {
+			if resourceEntity.Type != types.MimeVApp {
+				continue
+			}
+			vappHREF, err := url.Parse(resourceEntity.HREF)
+			if err != nil {
+				return nil, err
+			}
+			vapp, err := vdc.getVdcVAppbyHREF(vappHREF)
+			if err != nil {
+				return nil, fmt.Errorf("error retrieving vApp %s: %s", resourceEntity.Name, err)
+			}
+			vapps = append(vapps, vapp)
+		}
+	}
+	return vapps, nil
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// Tests that RunParallel bounds the concurrency, runs all the functions and aggregates the failures
+func TestRunParallel(t *testing.T) {
+	var mutex sync.Mutex
+	running, maxRunning, calls := 0, 0, 0
+
+	var tasksFn []func() (Task, error)
+	for i := 0; i < 10; i++ {
+		i := i
+		tasksFn = append(tasksFn, func() (Task, error) {
+			mutex.Lock()
+			calls++
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mutex.Unlock()
+
+			time.Sleep(10 * time.Millisecond)
+
+			mutex.Lock()
+			running--
+			mutex.Unlock()
+			if i%4 == 0 {
+				return Task{}, fmt.Errorf("failure %d", i)
+			}
+			return Task{}, nil
+		})
+	}
+
+	err := RunParallel(tasksFn, 3)
+	if calls != 10 {
+		t.Errorf("expected 10 calls, got %d", calls)
+	}
+	if maxRunning > 3 {
+		t.Errorf("expected at most 3 concurrent calls, got %d", maxRunning)
+	}
+	if err == nil {
+		t.Fatalf("expected aggregated error")
+	}
+	for _, expected := range []string{"3 of 10 operations failed", "[0] failure 0", "[4] failure 4", "[8] failure 8"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected '%s' in error: %s", expected, err)
+		}
+	}
+
+	err = RunParallel(tasksFn[1:4], 1)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	err = RunParallel(tasksFn, 0)
+	if err == nil {
+		t.Errorf("expected error for invalid concurrency")
+	}
+}
+
+// Tests that a task group waits for all its tasks, within its timeout, and reports their progress
+func TestTaskGroup_WaitAll(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	const runningTaskPath = "/api/task/running"
+	server.HandleXML(http.MethodGet, runningTaskPath, http.StatusOK,
+		`<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="running" href="{{server}}`+runningTaskPath+`">
+		  <Progress>40</Progress>
+		</Task>`)
+
+	vcdClient := newMockClient(t, server)
+	newTask := func(path string) Task {
+		task := NewTask(&vcdClient.Client)
+		task.Task = &types.Task{HREF: server.URL() + path, Status: "queued"}
+		return *task
+	}
+
+	var progresses []string
+	group := NewTaskGroup(newTask(vcdtest.MockTaskPath), Task{})
+	group.Add(newTask(runningTaskPath))
+	group.Concurrency = 1
+	group.PollInterval = 10 * time.Millisecond
+	group.Timeout = 200 * time.Millisecond
+	group.Progress = func(completed, total, progress int) {
+		progresses = append(progresses, fmt.Sprintf("%d/%d %d%%", completed, total, progress))
+	}
+	err := group.WaitAll()
+	if err == nil || !strings.Contains(err.Error(), "1 of 3 tasks failed: [2]") {
+		t.Errorf("expected the running task to fail at the timeout, got %v", err)
+	}
+	expected := "1/3 33%,2/3 66%,2/3 80%,3/3 100%"
+	if strings.Join(progresses, ",") != expected {
+		t.Errorf("expected progress %s, got %v", expected, progresses)
+	}
+	if len(server.RequestsTo(http.MethodGet, runningTaskPath)) < 2 {
+		t.Errorf("expected the running task to be polled several times")
+	}
+
+	err = NewTaskGroup(newTask(vcdtest.MockTaskPath), newTask(vcdtest.MockTaskPath)).WaitAll()
+	if err != nil {
+		t.Errorf("unexpected error waiting for successful tasks: %s", err)
+	}
+	err = (&TaskGroup{Concurrency: -1}).WaitAll()
+	if err == nil {
+		t.Errorf("expected error for invalid concurrency")
+	}
+}
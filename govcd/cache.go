@@ -0,0 +1,242 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmware/go-vcloud-director/v2/util"
+)
+
+// entityCache keeps the responses of the GET requests for entities, such as VM.Refresh, for a
+// freshness window. It is emptied by every change sent through the client (POST, PUT, PATCH and
+// DELETE requests to the XML API, the OpenAPI, the OAuth endpoints and the upload links) and
+// every task completion, so that a cached entity never hides a change made by this client.
+// Changes made by other clients are seen when the freshness window expires.
+//
+// The sections of VMs and vApps (e.g. the network connections or the guest customization) are
+// fetched in a batch with their entity: they are decoded from the cached entity, instead of being
+// retrieved one by one.
+type entityCache struct {
+	freshness time.Duration
+	mutex     sync.Mutex
+	entries   map[string]cachedEntity
+	// generation is incremented when entries are removed, so that the response of a GET started
+	// before the removal, and possibly older than the change, is not stored
+	generation uint64
+}
+
+// cachedEntity is the raw body of a GET response with its retrieval time
+type cachedEntity struct {
+	body      []byte
+	retrieved time.Time
+}
+
+// WithEntityCache enables an entity cache: the refresh of VMs, vApps and their sections is
+// served from memory when the entity was retrieved less than freshness ago. It removes the
+// requests repeated by the methods which refresh an entity before changing it, and the requests
+// for the sections of an entity already retrieved.
+func WithEntityCache(freshness time.Duration) VCDClientOption {
+	return func(vcdClient *VCDClient) error {
+		if freshness <= 0 {
+			return fmt.Errorf("entity cache freshness must be positive")
+		}
+		vcdClient.Client.cache = &entityCache{
+			freshness: freshness,
+			entries:   make(map[string]cachedEntity),
+		}
+		return nil
+	}
+}
+
+// InvalidateCachedEntity removes the entity with the given HREF from the cache, if enabled, so
+// that it is retrieved again at the next refresh
+func (client *Client) InvalidateCachedEntity(href string) {
+	if client.cache == nil {
+		return
+	}
+	client.cache.invalidate(href)
+}
+
+// ClearEntityCache removes all the entities from the cache, if enabled
+func (client *Client) ClearEntityCache() {
+	if client.cache == nil {
+		return
+	}
+	client.cache.clear()
+}
+
+// clearEntityCacheForChange empties the cache, if enabled, before a request with the given method,
+// unless the method only reads (GET)
+func (client *Client) clearEntityCacheForChange(method string) {
+	if client.cache != nil && method != http.MethodGet {
+		client.cache.clear()
+	}
+}
+
+// executeCachedRequest retrieves the entity with the given HREF into out, from the cache when a
+// fresh copy is available. Without cache, it is the same as a GET through ExecuteRequest.
+func (client *Client) executeCachedRequest(href, contentType, errorMessage string, out interface{}) error {
+	if client.cache == nil {
+		_, err := client.ExecuteRequest(href, http.MethodGet, contentType, errorMessage, nil, out)
+		return err
+	}
+
+	body, err := client.getCachedBody(href, contentType, errorMessage)
+	if err != nil {
+		return err
+	}
+	if err := xml.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("error decoding response: %s", err)
+	}
+	return nil
+}
+
+// executeCachedSectionRequest retrieves into out the section with the given element name (e.g.
+// NetworkConnectionSection) of the entity with the given HREF. With the cache enabled, the section
+// is decoded from the entity, which is retrieved once for all its sections and refreshes. Without
+// cache, or when the entity does not contain the section, the section is retrieved from its own
+// endpoint (e.g. HREF/networkConnectionSection/).
+func (client *Client) executeCachedSectionRequest(entityHref, sectionName, contentType, errorMessage string, out interface{}) error {
+	sectionHref := strings.TrimSuffix(entityHref, "/") + "/" + strings.ToLower(sectionName[:1]) + sectionName[1:] + "/"
+	if client.cache == nil {
+		_, err := client.ExecuteRequest(sectionHref, http.MethodGet, contentType, errorMessage, nil, out)
+		return err
+	}
+
+	body, err := client.getCachedBody(entityHref, "", errorMessage)
+	if err != nil {
+		return err
+	}
+	found, err := decodeSection(body, sectionName, out)
+	if err != nil || found {
+		return err
+	}
+	return client.executeCachedRequest(sectionHref, contentType, errorMessage, out)
+}
+
+// getCachedBody returns the body of the GET response for href, from the cache when a fresh copy is
+// available. The cache must be enabled.
+func (client *Client) getCachedBody(href, contentType, errorMessage string) ([]byte, error) {
+	body, found := client.cache.get(href)
+	if found {
+		util.Logger.Printf("[TRACE] entity %s retrieved from cache", href)
+		return body, nil
+	}
+
+	generation := client.cache.currentGeneration()
+	resp, err := executeRequest(href, http.MethodGet, contentType, nil, client)
+	if err != nil {
+		return nil, wrapError(errorMessage, err)
+	}
+	body, err = ioutil.ReadAll(resp.Body)
+	util.ProcessResponseOutput(util.FuncNameCallStack(), resp, fmt.Sprintf("%s", body))
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %s", err)
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error closing response body: %s", err)
+	}
+	client.cache.set(href, body, generation)
+	return body, nil
+}
+
+// decodeSection decodes into out the child of the root element of entity with the given local
+// name, and tells whether it was found. The namespace of the section is declared on it, as in
+// the response of its own endpoint.
+func decodeSection(entity []byte, sectionName string, out interface{}) (bool, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(entity))
+	depth := 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("error decoding response: %s", err)
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			depth++
+			if depth != 2 || element.Name.Local != sectionName {
+				continue
+			}
+			declared := false
+			for _, attr := range element.Attr {
+				declared = declared || (attr.Name.Space == "" && attr.Name.Local == "xmlns")
+			}
+			if !declared && element.Name.Space != "" {
+				element.Attr = append(element.Attr, xml.Attr{Name: xml.Name{Local: "xmlns"}, Value: element.Name.Space})
+			}
+			err = decoder.DecodeElement(out, &element)
+			if err != nil {
+				return false, fmt.Errorf("error decoding %s: %s", sectionName, err)
+			}
+			return true, nil
+		case xml.EndElement:
+			depth--
+		}
+	}
+}
+
+// get returns the body cached for href, if retrieved within the freshness window
+func (cache *entityCache) get(href string) ([]byte, bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	entry, found := cache.entries[href]
+	if !found {
+		return nil, false
+	}
+	if time.Since(entry.retrieved) > cache.freshness {
+		delete(cache.entries, href)
+		return nil, false
+	}
+	return entry.body, true
+}
+
+// currentGeneration returns the generation of the entries, to be given to set with the body of
+// the GET request started afterwards
+func (cache *entityCache) currentGeneration() uint64 {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	return cache.generation
+}
+
+// set stores the body retrieved for href, unless entries were removed since the request started,
+// at the given generation
+func (cache *entityCache) set(href string, body []byte, generation uint64) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if generation != cache.generation {
+		util.Logger.Printf("[TRACE] entity %s not cached, as the cache was emptied during its retrieval", href)
+		return
+	}
+	cache.entries[href] = cachedEntity{body: body, retrieved: time.Now()}
+}
+
+// invalidate removes the entry of href
+func (cache *entityCache) invalidate(href string) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	delete(cache.entries, href)
+	cache.generation++
+}
+
+// clear removes all the entries
+func (cache *entityCache) clear() {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	cache.entries = make(map[string]cachedEntity)
+	cache.generation++
+}
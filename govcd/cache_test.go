@@ -0,0 +1,249 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+const cachedVmPath = "/api/vApp/vm-55555555-5555-5555-5555-555555555555"
+
+const cachedVmXml = `<?xml version="1.0" encoding="UTF-8"?>
+<Vm xmlns="http://www.vmware.com/vcloud/v1.5" status="8" name="test-vm" type="application/vnd.vmware.vcloud.vm+xml" href="{{server}}` + cachedVmPath + `">
+  <Description>VM of the fake vCD</Description>
+</Vm>`
+
+const cachedTaskXml = `<?xml version="1.0" encoding="UTF-8"?>
+<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="running" operationName="vappUpdateVm" type="application/vnd.vmware.vcloud.task+xml" href="{{server}}` + vcdtest.MockTaskPath + `"/>`
+
+// Tests that VM refreshes are served from the cache until a change is made or the freshness
+// window expires
+func TestEntityCache(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	server.HandleXML(http.MethodGet, cachedVmPath, http.StatusOK, cachedVmXml)
+	server.HandleXML(http.MethodPut, cachedVmPath+"/virtualHardwareSection/memory", http.StatusAccepted, cachedTaskXml)
+
+	vcdClient := newMockClient(t, server, WithEntityCache(200*time.Millisecond))
+
+	vm := NewVM(&vcdClient.Client)
+	vm.VM.HREF = server.URL() + cachedVmPath
+	countRefreshes := func(expected int) {
+		t.Helper()
+		err := vm.Refresh()
+		if err != nil {
+			t.Fatalf("error refreshing VM: %s", err)
+		}
+		if vm.VM.Name != "test-vm" || vm.VM.Description != "VM of the fake vCD" {
+			t.Errorf("unexpected VM %#v", vm.VM)
+		}
+		requests := len(server.RequestsTo(http.MethodGet, cachedVmPath))
+		if requests != expected {
+			t.Errorf("expected %d VM requests, got %d", expected, requests)
+		}
+	}
+
+	countRefreshes(1)
+	countRefreshes(1)
+
+	// The change empties the cache, so the refresh it runs first is the only one cached
+	task, err := vm.ChangeMemorySize(1024)
+	if err != nil {
+		t.Fatalf("error changing memory: %s", err)
+	}
+	countRefreshes(2)
+
+	// The completion of the task empties the cache
+	err = task.WaitInspectTaskCompletion(nil, time.Millisecond)
+	if err != nil {
+		t.Fatalf("error waiting for task: %s", err)
+	}
+	countRefreshes(3)
+
+	vcdClient.Client.InvalidateCachedEntity(vm.VM.HREF)
+	countRefreshes(4)
+
+	time.Sleep(250 * time.Millisecond)
+	countRefreshes(5)
+}
+
+// Tests that the response of a refresh is not cached when the cache is emptied while it runs, as it
+// can predate the change which emptied the cache
+func TestEntityCache_ClearDuringRefresh(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	var vcdClient *VCDClient
+	server.HandleFunc(http.MethodGet, cachedVmPath, func(w http.ResponseWriter, r *http.Request) {
+		// A change sent concurrently by another goroutine
+		vcdClient.Client.ClearEntityCache()
+		w.Header().Set("Content-Type", "application/vnd.vmware.vcloud.vm+xml")
+		_, _ = w.Write([]byte(strings.Replace(cachedVmXml, "{{server}}", server.URL(), -1)))
+	})
+	vcdClient = newMockClient(t, server, WithEntityCache(time.Minute))
+
+	vm := NewVM(&vcdClient.Client)
+	vm.VM.HREF = server.URL() + cachedVmPath
+	for i := 0; i < 2; i++ {
+		err := vm.Refresh()
+		if err != nil {
+			t.Fatalf("error refreshing VM: %s", err)
+		}
+	}
+	if requests := len(server.RequestsTo(http.MethodGet, cachedVmPath)); requests != 2 {
+		t.Errorf("expected 2 VM requests, got %d", requests)
+	}
+}
+
+// Tests that refreshes are not cached without WithEntityCache
+func TestEntityCacheDisabled(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	server.HandleXML(http.MethodGet, cachedVmPath, http.StatusOK, cachedVmXml)
+
+	vcdClient := newMockClient(t, server)
+	vm := NewVM(&vcdClient.Client)
+	vm.VM.HREF = server.URL() + cachedVmPath
+	for i := 0; i < 2; i++ {
+		err := vm.Refresh()
+		if err != nil {
+			t.Fatalf("error refreshing VM: %s", err)
+		}
+	}
+	if requests := len(server.RequestsTo(http.MethodGet, cachedVmPath)); requests != 2 {
+		t.Errorf("expected 2 VM requests, got %d", requests)
+	}
+}
+
+// Tests that the changes sent through the OpenAPI, the OAuth endpoints and the upload links empty
+// the cache too
+func TestEntityCache_OtherChanges(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	server.HandleXML(http.MethodGet, cachedVmPath, http.StatusOK, cachedVmXml)
+	server.Handle(http.MethodDelete, "/cloudapi/1.0.0/items/1", vcdtest.Response{Status: http.StatusNoContent})
+	server.HandleJSON(http.MethodPost, "/oauth/provider/register", http.StatusOK, `{}`)
+	server.Handle(http.MethodPut, vcdtest.MockTransferPath+"disk1.vmdk", vcdtest.Response{Status: http.StatusOK})
+
+	vcdClient := newMockClient(t, server, WithEntityCache(time.Minute))
+	client := &vcdClient.Client
+	vm := NewVM(client)
+	vm.VM.HREF = server.URL() + cachedVmPath
+
+	changes := map[string]func() error{
+		"OpenAPI DELETE": func() error {
+			itemUrl, _ := url.Parse(server.URL() + "/cloudapi/1.0.0/items/1")
+			return client.OpenApiDeleteItem("33.0", itemUrl, nil)
+		},
+		"OAuth POST": func() error {
+			oauthUrl, _ := url.Parse(server.URL() + "/oauth/provider/register")
+			var out map[string]interface{}
+			return client.oauthPost("33.0", oauthUrl, "application/json", strings.NewReader(`{}`), &out)
+		},
+		"upload": func() error {
+			var uploadError error
+			return uploadPartFile(client, []byte("data"), 4, uploadDetails{
+				uploadLink:       server.URL() + vcdtest.MockTransferPath + "disk1.vmdk",
+				fileSizeToUpload: 4,
+				callBack:         func(bytesUpload, totalSize int64) {},
+				uploadError:      &uploadError,
+			})
+		},
+	}
+	for name, change := range changes {
+		client.ClearEntityCache()
+		server.ClearRequests()
+		for i := 0; i < 2; i++ {
+			if err := vm.Refresh(); err != nil {
+				t.Fatalf("error refreshing VM: %s", err)
+			}
+		}
+		if err := change(); err != nil {
+			t.Fatalf("error sending %s: %s", name, err)
+		}
+		if err := vm.Refresh(); err != nil {
+			t.Fatalf("error refreshing VM: %s", err)
+		}
+		if requests := len(server.RequestsTo(http.MethodGet, cachedVmPath)); requests != 2 {
+			t.Errorf("expected the %s to empty the cache: got %d VM requests instead of 2", name, requests)
+		}
+	}
+}
+
+// Tests that the sections of a VM are decoded from the cached VM, instead of being retrieved one by one
+func TestEntityCache_Sections(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	server.HandleXML(http.MethodGet, cachedVmPath, http.StatusOK,
+		`<Vm xmlns="http://www.vmware.com/vcloud/v1.5" xmlns:ovf="http://schemas.dmtf.org/ovf/envelope/1" name="test-vm" href="{{server}}`+cachedVmPath+`">
+  <NetworkConnectionSection href="{{server}}`+cachedVmPath+`/networkConnectionSection/" ovf:required="false">
+    <ovf:Info>Specifies the available VM network connections</ovf:Info>
+    <PrimaryNetworkConnectionIndex>0</PrimaryNetworkConnectionIndex>
+    <NetworkConnection network="net1" needsCustomization="false">
+      <NetworkConnectionIndex>0</NetworkConnectionIndex>
+      <IpAddress>192.168.1.10</IpAddress>
+      <IsConnected>true</IsConnected>
+      <IpAddressAllocationMode>MANUAL</IpAddressAllocationMode>
+    </NetworkConnection>
+  </NetworkConnectionSection>
+</Vm>`)
+	server.HandleXML(http.MethodGet, cachedVmPath+"/networkConnectionSection/", http.StatusOK,
+		`<NetworkConnectionSection xmlns="http://www.vmware.com/vcloud/v1.5"><PrimaryNetworkConnectionIndex>0</PrimaryNetworkConnectionIndex></NetworkConnectionSection>`)
+	guestCustomizationPath := cachedVmPath + "/guestCustomizationSection/"
+	server.HandleXML(http.MethodGet, guestCustomizationPath, http.StatusOK,
+		`<GuestCustomizationSection xmlns="http://www.vmware.com/vcloud/v1.5"><Enabled>true</Enabled><ComputerName>vm1</ComputerName></GuestCustomizationSection>`)
+
+	for _, cached := range []bool{true, false} {
+		server.ClearRequests()
+		var options []VCDClientOption
+		if cached {
+			options = append(options, WithEntityCache(time.Minute))
+		}
+		vcdClient := newMockClient(t, server, options...)
+		vm := NewVM(&vcdClient.Client)
+		vm.VM.HREF = server.URL() + cachedVmPath
+		if err := vm.Refresh(); err != nil {
+			t.Fatalf("error refreshing VM: %s", err)
+		}
+
+		for i := 0; i < 2; i++ {
+			section, err := vm.GetNetworkConnectionSection()
+			if err != nil {
+				t.Fatalf("error retrieving network connection section: %s", err)
+			}
+			if cached && (len(section.NetworkConnection) != 1 || section.NetworkConnection[0].IPAddress != "192.168.1.10" ||
+				section.Xmlns != types.XMLNamespaceVCloud) {
+				t.Errorf("unexpected network connection section: %#v", section)
+			}
+		}
+		// A section missing from the VM is retrieved from its endpoint
+		customization, err := vm.GetGuestCustomizationSection()
+		if err != nil {
+			t.Fatalf("error retrieving guest customization section: %s", err)
+		}
+		if customization.ComputerName != "vm1" {
+			t.Errorf("unexpected guest customization section: %#v", customization)
+		}
+
+		sectionRequests := len(server.RequestsTo(http.MethodGet, cachedVmPath+"/networkConnectionSection/"))
+		vmRequests := len(server.RequestsTo(http.MethodGet, cachedVmPath))
+		if cached && (vmRequests != 1 || sectionRequests != 0) {
+			t.Errorf("expected the sections from the cached VM: %d VM and %d section requests", vmRequests, sectionRequests)
+		}
+		if !cached && sectionRequests != 2 {
+			t.Errorf("expected 2 section requests without cache, got %d", sectionRequests)
+		}
+		if len(server.RequestsTo(http.MethodGet, guestCustomizationPath)) != 1 {
+			t.Errorf("expected the guest customization section from its endpoint")
+		}
+	}
+}
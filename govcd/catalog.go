@@ -120,6 +120,89 @@ func (adminCatalog *AdminCatalog) Update() error {
 	return err
 }
 
+// PublishToExternalOrganizations publishes the catalog to subscribers outside vCD, or stops
+// publishing it when settings.IsPublishedExternally is false. The org of the catalog needs to be
+// allowed to publish externally.
+func (catalog *Catalog) PublishToExternalOrganizations(settings types.PublishExternalCatalogParams) error {
+	adminCatalogHREF := catalog.client.VCDHREF
+	adminCatalogHREF.Path += "/admin/catalog/" + getEntityNumericId(catalog.Catalog.ID) + "/action/publishToExternalOrganizations"
+
+	settings.Xmlns = types.XMLNamespaceVCloud
+	settings.CatalogPublishedUrl = ""
+	return catalog.client.ExecuteRequestWithoutResponse(adminCatalogHREF.String(), http.MethodPost,
+		types.MimePublishExternalCatalog, "error publishing catalog to external organizations: %s", &settings)
+}
+
+// PublishToExternalOrganizations publishes the catalog to subscribers outside vCD, or stops
+// publishing it when settings.IsPublishedExternally is false
+func (adminCatalog *AdminCatalog) PublishToExternalOrganizations(settings types.PublishExternalCatalogParams) error {
+	catalog := NewCatalog(adminCatalog.client)
+	catalog.Catalog = &adminCatalog.AdminCatalog.Catalog
+	return catalog.PublishToExternalOrganizations(settings)
+}
+
+// GetAccessControl retrieves the access controls of the catalog, i.e. the orgs, users and groups
+// it is shared with
+func (catalog *Catalog) GetAccessControl() (*types.ControlAccessParams, error) {
+	link := catalog.Catalog.Link.ForType(types.MimeControlAccess, types.RelDown)
+	if link == nil {
+		return nil, fmt.Errorf("no access control link found in catalog %s", catalog.Catalog.Name)
+	}
+	controlAccess := &types.ControlAccessParams{}
+	_, err := catalog.client.ExecuteRequest(link.HREF, http.MethodGet,
+		types.MimeControlAccess, "error retrieving catalog access control: %s", nil, controlAccess)
+	if err != nil {
+		return nil, err
+	}
+	return controlAccess, nil
+}
+
+// SetAccessControl replaces the access controls of the catalog, to share it with everyone in the
+// org, or with specific orgs, users and groups with an access level (types.ControlAccess*).
+// Returns the access controls applied by vCD.
+func (catalog *Catalog) SetAccessControl(controlAccess *types.ControlAccessParams) (*types.ControlAccessParams, error) {
+	if controlAccess == nil {
+		return nil, fmt.Errorf("empty access control")
+	}
+	if controlAccess.IsSharedToEveryone {
+		if controlAccess.EveryoneAccessLevel == "" {
+			return nil, fmt.Errorf("an access level is needed to share catalog %s with everyone", catalog.Catalog.Name)
+		}
+	} else if controlAccess.EveryoneAccessLevel != "" {
+		return nil, fmt.Errorf("an access level for everyone is only allowed when catalog %s is shared with everyone", catalog.Catalog.Name)
+	}
+	if controlAccess.AccessSettings != nil {
+		for _, setting := range controlAccess.AccessSettings.AccessSetting {
+			if setting.Subject == nil || setting.Subject.HREF == "" {
+				return nil, fmt.Errorf("access setting without subject in catalog %s", catalog.Catalog.Name)
+			}
+			if setting.AccessLevel == "" {
+				return nil, fmt.Errorf("access setting without access level for %s in catalog %s", setting.Subject.HREF, catalog.Catalog.Name)
+			}
+		}
+	}
+
+	link := catalog.Catalog.Link.ForType(types.MimeControlAccess, types.RelControlAccess)
+	if link == nil {
+		return nil, fmt.Errorf("no access control action found in catalog %s", catalog.Catalog.Name)
+	}
+	payload := *controlAccess
+	payload.Xmlns = types.XMLNamespaceVCloud
+	result := &types.ControlAccessParams{}
+	_, err := catalog.client.ExecuteRequest(link.HREF, http.MethodPost,
+		types.MimeControlAccess, "error setting catalog access control: %s", &payload, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RemoveAccessControl stops sharing the catalog with everyone, and with all orgs, users and groups
+func (catalog *Catalog) RemoveAccessControl() error {
+	_, err := catalog.SetAccessControl(&types.ControlAccessParams{IsSharedToEveryone: false})
+	return err
+}
+
 // Envelope is a ovf description root element. File contains information for vmdk files.
 // Namespace: http://schemas.dmtf.org/ovf/envelope/1
 // Description: Envelope is a ovf description root element. File contains information for vmdk files..
@@ -163,11 +246,27 @@ func (adminCatalog *AdminCatalog) UploadOvf(ovaFileName, itemName, description s
 	return catalog.UploadOvf(ovaFileName, itemName, description, uploadPieceSize)
 }
 
+// UploadOvfWithProgress is UploadOvf calling progress with the bytes uploaded so far
+// and the total size of the files. See Catalog.UploadOvfWithProgress.
+func (adminCatalog *AdminCatalog) UploadOvfWithProgress(ovaFileName, itemName, description string, uploadPieceSize int64, progress UploadProgressFunc) (UploadTask, error) {
+	catalog := NewCatalog(adminCatalog.client)
+	catalog.Catalog = &adminCatalog.AdminCatalog.Catalog
+	return catalog.UploadOvfWithProgress(ovaFileName, itemName, description, uploadPieceSize, progress)
+}
+
 // Uploads an ova file to a catalog. This method only uploads bits to vCD spool area.
 // Returns errors if any occur during upload from vCD or upload process. On upload fail client may need to
 // remove vCD catalog item which waits for files to be uploaded. Files from ova are extracted to system
 // temp folder "govcd+random number" and left for inspection on error.
 func (cat *Catalog) UploadOvf(ovaFileName, itemName, description string, uploadPieceSize int64) (UploadTask, error) {
+	return cat.UploadOvfWithProgress(ovaFileName, itemName, description, uploadPieceSize, nil)
+}
+
+// UploadOvfWithProgress uploads an ova file, or an ovf descriptor with the files it references in
+// the same folder, to a catalog. Besides updating the progress of the returned UploadTask, the upload
+// calls progress, when not nil, with the bytes uploaded so far and the total size of the files.
+// The callback runs in the background upload and must not block.
+func (cat *Catalog) UploadOvfWithProgress(ovaFileName, itemName, description string, uploadPieceSize int64, progress UploadProgressFunc) (UploadTask, error) {
 
 	//	On a very high level the flow is as follows
 	//	1. Makes a POST call to vCD to create the catalog item (also creates a transfer folder in the spool area and as result will give a sparse catalog item resource XML).
@@ -190,7 +289,15 @@ func (cat *Catalog) UploadOvf(ovaFileName, itemName, description string, uploadP
 		}
 	}
 
-	filesAbsPaths, tmpDir, err := util.Unpack(ovaFileName)
+	var filesAbsPaths []string
+	var tmpDir string
+	// the folder of an ovf descriptor belongs to the caller: only the files extracted from an ova are removed
+	unpacked := filepath.Ext(ovaFileName) != ".ovf"
+	if unpacked {
+		filesAbsPaths, tmpDir, err = util.Unpack(ovaFileName)
+	} else {
+		filesAbsPaths, tmpDir, err = getOvfFolderFiles(ovaFileName)
+	}
 	if err != nil {
 		return UploadTask{}, fmt.Errorf("%v. Unpacked files for checking are accessible in: "+tmpDir, err)
 	}
@@ -243,11 +350,18 @@ func (cat *Catalog) UploadOvf(ovaFileName, itemName, description string, uploadP
 	}
 
 	callBack, uploadProgress := getCallBackFunction()
+	if progress != nil {
+		updateProgress := callBack
+		callBack = func(bytesUploaded, totalSize int64) {
+			updateProgress(bytesUploaded, totalSize)
+			progress(bytesUploaded, totalSize)
+		}
+	}
 
 	uploadError := *new(error)
 
 	//sending upload process to background, this allows no to lock and return task to client
-	go uploadFiles(cat.client, vappTemplate, &ovfFileDesc, tmpDir, filesAbsPaths, uploadPieceSize, callBack, &uploadError)
+	go uploadFiles(cat.client, vappTemplate, &ovfFileDesc, tmpDir, unpacked, filesAbsPaths, uploadPieceSize, callBack, &uploadError)
 
 	var task Task
 	for _, item := range vappTemplate.Tasks.Task {
@@ -277,11 +391,12 @@ func (cat *Catalog) UploadOvf(ovaFileName, itemName, description string, uploadP
 // vappTemplate - parsed from response vApp template
 // ovfFileDesc - parsed from xml part containing ova files definition
 // tempPath - path where extracted files are
+// removeTempPath - whether tempPath holds files extracted from an ova, to be removed after the upload
 // filesAbsPaths - array of extracted files
 // uploadPieceSize - size of chunks in which the file will be uploaded to the catalog.
 // callBack a function with signature //function(bytesUpload, totalSize) to let the caller monitor progress of the upload operation.
 // uploadError - error to be ready be task
-func uploadFiles(client *Client, vappTemplate *types.VAppTemplate, ovfFileDesc *Envelope, tempPath string, filesAbsPaths []string, uploadPieceSize int64, callBack func(bytesUpload, totalSize int64), uploadError *error) error {
+func uploadFiles(client *Client, vappTemplate *types.VAppTemplate, ovfFileDesc *Envelope, tempPath string, removeTempPath bool, filesAbsPaths []string, uploadPieceSize int64, callBack func(bytesUpload, totalSize int64), uploadError *error) error {
 	var uploadedBytes int64
 	for _, item := range vappTemplate.Files.File {
 		if item.BytesTransferred == 0 {
@@ -332,6 +447,10 @@ func uploadFiles(client *Client, vappTemplate *types.VAppTemplate, ovfFileDesc *
 		}
 	}
 
+	if !removeTempPath {
+		return nil
+	}
+
 	//remove extracted files with temp dir
 	err := os.RemoveAll(tempPath)
 	if err != nil {
@@ -566,6 +685,30 @@ func getOvfPath(filesAbsPaths []string) (string, error) {
 	return "", errors.New("ova is not correct - missing ovf file")
 }
 
+// getOvfFolderFiles returns the ovf descriptor, first, and the files it references in its folder,
+// which is returned as the folder of the files to upload. Other files of the folder are left out.
+func getOvfFolderFiles(ovfFilePath string) ([]string, string, error) {
+	ovfFilePath, err := filepath.Abs(ovfFilePath)
+	if err != nil {
+		return nil, "", err
+	}
+	folder := filepath.Dir(ovfFilePath)
+	ovfFileDesc, err := getOvf(ovfFilePath)
+	if err != nil {
+		return nil, folder, err
+	}
+	filesAbsPaths := []string{ovfFilePath}
+	for _, fileDescription := range ovfFileDesc.File {
+		if fileDescription.ChunkSize == 0 {
+			filesAbsPaths = append(filesAbsPaths, filepath.Join(folder, fileDescription.HREF))
+			continue
+		}
+		filesAbsPaths = append(filesAbsPaths, getChunkedFilePaths(folder, fileDescription.HREF,
+			fileDescription.Size, fileDescription.ChunkSize)...)
+	}
+	return filesAbsPaths, folder, nil
+}
+
 func getOvf(ovfFilePath string) (Envelope, error) {
 	openedFile, err := os.Open(ovfFilePath)
 	if err != nil {
@@ -675,6 +818,13 @@ func removeCatalogItemOnError(client *Client, vappTemplateLink *url.URL, itemNam
 }
 
 func (cat *Catalog) UploadMediaImage(mediaName, mediaDescription, filePath string, uploadPieceSize int64) (UploadTask, error) {
+	return cat.UploadMediaImageWithProgress(mediaName, mediaDescription, filePath, uploadPieceSize, nil)
+}
+
+// UploadMediaImageWithProgress uploads an ISO file as media of the catalog, calling progress, when not
+// nil, with the bytes uploaded so far and the size of the file. The returned UploadTask tracks the
+// upload and the import task of vCD. The callback runs in the background upload and must not block.
+func (cat *Catalog) UploadMediaImageWithProgress(mediaName, mediaDescription, filePath string, uploadPieceSize int64, progress UploadProgressFunc) (UploadTask, error) {
 
 	if *cat == (Catalog{}) {
 		return UploadTask{}, errors.New("catalog can not be empty or nil")
@@ -717,5 +867,20 @@ func (cat *Catalog) UploadMediaImage(mediaName, mediaDescription, filePath strin
 		return UploadTask{}, err
 	}
 
-	return executeUpload(cat.client, createdMedia, mediaFilePath, mediaName, fileSize, uploadPieceSize)
+	return executeUpload(cat.client, createdMedia, mediaFilePath, mediaName, fileSize, uploadPieceSize, progress)
+}
+
+// CaptureVApp creates a vApp template from the given vApp and adds it to the catalog, to build
+// golden images from configured vApps. copyOrMove is either CaptureVAppCopy or CaptureVAppMove
+// (the vApp is removed once captured, and needs to be undeployed). When customizeOnInstantiate
+// is true, the VMs instantiated from the template are customized, otherwise they are identical
+// copies. Returns the capture task, or with CaptureVAppMove the task removing the vApp.
+func (cat *Catalog) CaptureVApp(vapp VApp, name, description, copyOrMove string, customizeOnInstantiate bool) (Task, error) {
+	catalogHref, err := url.ParseRequestURI(cat.Catalog.HREF)
+	if err != nil {
+		return Task{}, fmt.Errorf("error getting catalog href: %s", err)
+	}
+	catalogHref.Path += "/action/captureVApp"
+
+	return captureVApp(cat.client, catalogHref.String(), true, vapp, name, description, copyOrMove, customizeOnInstantiate)
 }
@@ -7,10 +7,15 @@ package govcd
 import (
 	"io/ioutil"
 	"log"
+	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
+	"testing"
 
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
 	"github.com/vmware/go-vcloud-director/v2/util"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
 	. "gopkg.in/check.v1"
 )
 
@@ -407,3 +412,221 @@ func (vcd *TestVCD) Test_CatalogDeleteMediaImage(check *C) {
 	}
 	check.Assert(entityFound, Equals, false)
 }
+
+// testOvfDescriptor references a plain disk and a disk in chunks of 4 bytes
+const testOvfDescriptor = `<Envelope xmlns="http://schemas.dmtf.org/ovf/envelope/1">
+  <References>
+    <File ovf:href="disk1.vmdk" ovf:id="file1" ovf:size="10" xmlns:ovf="http://schemas.dmtf.org/ovf/envelope/1"/>
+    <File ovf:href="disk2.vmdk" ovf:id="file2" ovf:size="6" ovf:chunkSize="4" xmlns:ovf="http://schemas.dmtf.org/ovf/envelope/1"/>
+  </References>
+</Envelope>`
+
+// writeOvfFolder writes an ovf descriptor, the files it references and an unrelated file to a new
+// folder
+func writeOvfFolder(t *testing.T) string {
+	folder, err := ioutil.TempDir("", "govcd")
+	if err != nil {
+		t.Fatalf("error creating folder: %s", err)
+	}
+	files := map[string]string{
+		"template.ovf":         testOvfDescriptor,
+		"disk1.vmdk":           "disk1-data",
+		"disk2.vmdk.000000000": "disk",
+		"disk2.vmdk.000000001": "-2",
+		"notes.txt":            "not referenced",
+	}
+	for name, content := range files {
+		err = ioutil.WriteFile(filepath.Join(folder, name), []byte(content), 0600)
+		if err != nil {
+			t.Fatalf("error writing %s: %s", name, err)
+		}
+	}
+	err = os.Mkdir(filepath.Join(folder, "other"), 0700)
+	if err != nil {
+		t.Fatalf("error creating folder: %s", err)
+	}
+	return folder
+}
+
+// Checks that an ovf descriptor is uploaded with the files it references in its folder, descriptor first
+func TestGetOvfFolderFiles(t *testing.T) {
+	folder := writeOvfFolder(t)
+	defer os.RemoveAll(folder)
+
+	files, filesFolder, err := getOvfFolderFiles(filepath.Join(folder, "template.ovf"))
+	if err != nil {
+		t.Fatalf("error listing ovf files: %s", err)
+	}
+	if filesFolder != folder || len(files) != 4 || files[0] != filepath.Join(folder, "template.ovf") ||
+		findFilePath(files, "disk1.vmdk") == "" || findFilePath(files, "disk2.vmdk.000000001") == "" ||
+		findFilePath(files, "notes.txt") != "" {
+		t.Errorf("unexpected ovf files in %s: %v", filesFolder, files)
+	}
+	ovfPath, err := getOvfPath(files)
+	if err != nil || ovfPath != files[0] {
+		t.Errorf("unexpected ovf descriptor: %s, %v", ovfPath, err)
+	}
+	ovfFileDesc, err := getOvf(ovfPath)
+	if err != nil {
+		t.Fatalf("error reading ovf descriptor: %s", err)
+	}
+	err = validateOvaContent(files, &ovfFileDesc, filesFolder)
+	if err != nil {
+		t.Errorf("unexpected invalid ovf files: %s", err)
+	}
+}
+
+// Checks that uploading the files of an ovf folder leaves the folder of the caller in place, while
+// the files extracted from an ova are removed
+func TestUploadFiles_KeepsOvfFolder(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	vcdClient := newMockClient(t, server)
+
+	vappTemplate := &types.VAppTemplate{Files: &types.FilesList{}}
+	for _, name := range []string{"disk1.vmdk", "disk2.vmdk"} {
+		server.Handle(http.MethodPut, vcdtest.MockTransferPath+name, vcdtest.Response{Status: http.StatusOK})
+		vappTemplate.Files.File = append(vappTemplate.Files.File, &types.File{
+			Name: name,
+			Size: 10,
+			Link: types.LinkList{{HREF: server.URL() + vcdtest.MockTransferPath + name}},
+		})
+	}
+	vappTemplate.Files.File[1].Size = 6
+
+	for _, removeTempPath := range []bool{false, true} {
+		server.ClearRequests()
+		folder := writeOvfFolder(t)
+		files, filesFolder, err := getOvfFolderFiles(filepath.Join(folder, "template.ovf"))
+		if err != nil {
+			t.Fatalf("error listing ovf files: %s", err)
+		}
+		ovfFileDesc, err := getOvf(files[0])
+		if err != nil {
+			t.Fatalf("error reading ovf descriptor: %s", err)
+		}
+
+		var uploadError error
+		err = uploadFiles(&vcdClient.Client, vappTemplate, &ovfFileDesc, filesFolder, removeTempPath, files, 0,
+			func(bytesUpload, totalSize int64) {}, &uploadError)
+		if err != nil || uploadError != nil {
+			t.Fatalf("error uploading files: %v, %v", err, uploadError)
+		}
+
+		_, err = os.Stat(filepath.Join(folder, "notes.txt"))
+		if removeTempPath && !os.IsNotExist(err) {
+			t.Errorf("expected extracted files removed from %s: %v", folder, err)
+		}
+		if !removeTempPath {
+			if err != nil {
+				t.Errorf("expected ovf folder kept after the upload: %s", err)
+			}
+			_ = os.RemoveAll(folder)
+		}
+
+		uploads := server.RequestsTo(http.MethodPut, vcdtest.MockTransferPath+"disk1.vmdk")
+		if len(uploads) != 1 || uploads[0].Body != "disk1-data" {
+			t.Errorf("unexpected upload of disk1.vmdk: %#v", uploads)
+		}
+		uploads = server.RequestsTo(http.MethodPut, vcdtest.MockTransferPath+"disk2.vmdk")
+		if len(uploads) != 2 || uploads[0].Body+uploads[1].Body != "disk-2" {
+			t.Errorf("unexpected upload of disk2.vmdk: %#v", uploads)
+		}
+	}
+}
+
+// Checks the sharing of a catalog with orgs and users, and its external publishing
+func TestCatalog_AccessControlAndPublish(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	catalogId := "97384890-180c-4563-b9b7-0dc50a2430b0"
+	controlAccessPath := "/api/org/org-1/catalog/" + catalogId + "/controlAccess/"
+	actionPath := "/api/org/org-1/catalog/" + catalogId + "/action/controlAccess"
+	publishPath := "/api/admin/catalog/" + catalogId + "/action/publishToExternalOrganizations"
+	controlAccess := `<ControlAccessParams xmlns="http://www.vmware.com/vcloud/v1.5">
+  <IsSharedToEveryone>false</IsSharedToEveryone>
+  <AccessSettings>
+    <AccessSetting>
+      <Subject href="{{server}}/api/org/org-2" name="org2" type="application/vnd.vmware.vcloud.org+xml"/>
+      <AccessLevel>ReadOnly</AccessLevel>
+    </AccessSetting>
+  </AccessSettings>
+</ControlAccessParams>`
+	server.HandleXML(http.MethodGet, controlAccessPath, http.StatusOK, controlAccess)
+	server.HandleXML(http.MethodPost, actionPath, http.StatusOK, controlAccess)
+	server.Handle(http.MethodPost, publishPath, vcdtest.Response{Status: http.StatusNoContent})
+
+	vcdClient := newMockClient(t, server)
+	catalog := NewCatalog(&vcdClient.Client)
+	catalog.Catalog.Name = "cat"
+	catalog.Catalog.ID = "urn:vcloud:catalog:" + catalogId
+	catalog.Catalog.Link = types.LinkList{
+		{Rel: types.RelDown, Type: types.MimeControlAccess, HREF: server.URL() + controlAccessPath},
+		{Rel: types.RelControlAccess, Type: types.MimeControlAccess, HREF: server.URL() + actionPath},
+	}
+
+	current, err := catalog.GetAccessControl()
+	if err != nil {
+		t.Fatalf("error retrieving access control: %s", err)
+	}
+	if current.AccessSettings == nil || len(current.AccessSettings.AccessSetting) != 1 ||
+		current.AccessSettings.AccessSetting[0].AccessLevel != types.ControlAccessReadOnly {
+		t.Fatalf("unexpected access control: %+v", current)
+	}
+
+	current.AccessSettings.AccessSetting = append(current.AccessSettings.AccessSetting, &types.AccessSetting{
+		Subject:     &types.LocalSubject{HREF: server.URL() + "/api/admin/user/user-1", Type: types.MimeAdminUser},
+		AccessLevel: types.ControlAccessFullControl,
+	})
+	_, err = catalog.SetAccessControl(current)
+	if err != nil {
+		t.Fatalf("error setting access control: %s", err)
+	}
+	requests := server.RequestsTo(http.MethodPost, actionPath)
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 access control request, got %d", len(requests))
+	}
+	for _, expected := range []string{"<IsSharedToEveryone>false</IsSharedToEveryone>", `name="org2"`,
+		"/api/admin/user/user-1", "<AccessLevel>FullControl</AccessLevel>"} {
+		if !strings.Contains(requests[0].Body, expected) {
+			t.Errorf("expected %s in access control payload:\n%s", expected, requests[0].Body)
+		}
+	}
+
+	_, err = catalog.SetAccessControl(&types.ControlAccessParams{IsSharedToEveryone: true})
+	if err == nil {
+		t.Errorf("expected error when sharing with everyone without access level")
+	}
+	err = catalog.RemoveAccessControl()
+	if err != nil {
+		t.Fatalf("error removing access control: %s", err)
+	}
+	requests = server.RequestsTo(http.MethodPost, actionPath)
+	if strings.Contains(requests[len(requests)-1].Body, "AccessSettings") {
+		t.Errorf("expected no access settings when removing access control:\n%s", requests[len(requests)-1].Body)
+	}
+
+	err = catalog.PublishToExternalOrganizations(types.PublishExternalCatalogParams{
+		IsPublishedExternally: true,
+		Password:              "secret",
+		IsCachedEnabled:       true,
+	})
+	if err != nil {
+		t.Fatalf("error publishing catalog: %s", err)
+	}
+	err = catalog.PublishToExternalOrganizations(types.PublishExternalCatalogParams{})
+	if err != nil {
+		t.Fatalf("error unpublishing catalog: %s", err)
+	}
+	requests = server.RequestsTo(http.MethodPost, publishPath)
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 publish requests, got %d", len(requests))
+	}
+	if !strings.Contains(requests[0].Body, "<IsPublishedExternally>true</IsPublishedExternally>") ||
+		!strings.Contains(requests[0].Body, "<Password>secret</Password>") {
+		t.Errorf("unexpected publish payload:\n%s", requests[0].Body)
+	}
+	if !strings.Contains(requests[1].Body, "<IsPublishedExternally>false</IsPublishedExternally>") {
+		t.Errorf("unexpected unpublish payload:\n%s", requests[1].Body)
+	}
+}
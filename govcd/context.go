@@ -0,0 +1,181 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Context support
+//
+// The requests of a client use its context, which is context.Background() by default. WithContext
+// returns a copy of a client, or of an entity, whose requests use another context, so that a
+// caller can cancel an operation or give it a deadline:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+//	defer cancel()
+//	task, err := vapp.WithContext(ctx).PowerOn()
+//	...
+//	err = task.WaitTaskCompletion() // also stops when ctx is done
+//
+// The entities and tasks returned by the operations of the copy keep its context. The copy shares
+// the entity data with the original until one of them is refreshed.
+
+// WithContext returns a copy of the client whose requests use ctx. The copy is a snapshot: a later
+// authentication of the original client is not seen by the copy.
+func (cli *Client) WithContext(ctx context.Context) *Client {
+	if ctx == nil {
+		panic("nil context")
+	}
+	withContext := *cli
+	withContext.ctx = ctx
+	return &withContext
+}
+
+// Context returns the context of the requests of the client
+func (cli *Client) Context() context.Context {
+	if cli.ctx != nil {
+		return cli.ctx
+	}
+	return context.Background()
+}
+
+// NewRequestWithContext creates a new HTTP request, as NewRequest, which uses ctx instead of the
+// context of the client
+func (cli *Client) NewRequestWithContext(ctx context.Context, params map[string]string, method string, reqUrl url.URL, body io.Reader) *http.Request {
+	return cli.NewRequest(params, method, reqUrl, body).WithContext(ctx)
+}
+
+// sleep waits for delay, or until the context of the client is done. In the latter case the
+// error of the context is returned.
+func (cli *Client) sleep(delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-cli.Context().Done():
+		return cli.Context().Err()
+	}
+}
+
+// WithContext returns a copy of the vCD client whose requests use ctx
+func (vcdCli *VCDClient) WithContext(ctx context.Context) *VCDClient {
+	return &VCDClient{
+		Client:            *vcdCli.Client.WithContext(ctx),
+		sessionHREF:       vcdCli.sessionHREF,
+		QueryHREF:         vcdCli.QueryHREF,
+		supportedVersions: vcdCli.supportedVersions,
+	}
+}
+
+// WithContext returns a copy of the vApp whose operations use ctx
+func (vapp *VApp) WithContext(ctx context.Context) *VApp {
+	withContext := *vapp
+	withContext.client = vapp.client.WithContext(ctx)
+	return &withContext
+}
+
+// WithContext returns a copy of the VM whose operations use ctx
+func (vm *VM) WithContext(ctx context.Context) *VM {
+	withContext := *vm
+	withContext.client = vm.client.WithContext(ctx)
+	return &withContext
+}
+
+// WithContext returns a copy of the VDC whose operations use ctx
+func (vdc *Vdc) WithContext(ctx context.Context) *Vdc {
+	withContext := *vdc
+	withContext.client = vdc.client.WithContext(ctx)
+	return &withContext
+}
+
+// WithContext returns a copy of the admin VDC whose operations use ctx
+func (adminVdc *AdminVdc) WithContext(ctx context.Context) *AdminVdc {
+	withContext := *adminVdc
+	withContext.client = adminVdc.client.WithContext(ctx)
+	return &withContext
+}
+
+// WithContext returns a copy of the org whose operations use ctx
+func (org *Org) WithContext(ctx context.Context) *Org {
+	withContext := *org
+	withContext.client = org.client.WithContext(ctx)
+	return &withContext
+}
+
+// WithContext returns a copy of the admin org whose operations use ctx
+func (adminOrg *AdminOrg) WithContext(ctx context.Context) *AdminOrg {
+	withContext := *adminOrg
+	withContext.client = adminOrg.client.WithContext(ctx)
+	return &withContext
+}
+
+// WithContext returns a copy of the catalog whose operations use ctx
+func (catalog *Catalog) WithContext(ctx context.Context) *Catalog {
+	withContext := *catalog
+	withContext.client = catalog.client.WithContext(ctx)
+	return &withContext
+}
+
+// WithContext returns a copy of the admin catalog whose operations use ctx
+func (adminCatalog *AdminCatalog) WithContext(ctx context.Context) *AdminCatalog {
+	withContext := *adminCatalog
+	withContext.client = adminCatalog.client.WithContext(ctx)
+	return &withContext
+}
+
+// WithContext returns a copy of the catalog item whose operations use ctx
+func (catalogItem *CatalogItem) WithContext(ctx context.Context) *CatalogItem {
+	withContext := *catalogItem
+	withContext.client = catalogItem.client.WithContext(ctx)
+	return &withContext
+}
+
+// WithContext returns a copy of the vApp template whose operations use ctx
+func (vappTemplate *VAppTemplate) WithContext(ctx context.Context) *VAppTemplate {
+	withContext := *vappTemplate
+	withContext.client = vappTemplate.client.WithContext(ctx)
+	return &withContext
+}
+
+// WithContext returns a copy of the task whose operations use ctx
+func (task *Task) WithContext(ctx context.Context) *Task {
+	withContext := *task
+	withContext.client = task.client.WithContext(ctx)
+	return &withContext
+}
+
+// WithContext returns a copy of the edge gateway whose operations use ctx
+func (egw *EdgeGateway) WithContext(ctx context.Context) *EdgeGateway {
+	withContext := *egw
+	withContext.client = egw.client.WithContext(ctx)
+	return &withContext
+}
+
+// WithContext returns a copy of the network whose operations use ctx
+func (orgVdcNet *OrgVDCNetwork) WithContext(ctx context.Context) *OrgVDCNetwork {
+	withContext := *orgVdcNet
+	withContext.client = orgVdcNet.client.WithContext(ctx)
+	return &withContext
+}
+
+// WithContext returns a copy of the disk whose operations use ctx
+func (disk *Disk) WithContext(ctx context.Context) *Disk {
+	withContext := *disk
+	withContext.client = disk.client.WithContext(ctx)
+	return &withContext
+}
+
+// WithContext returns a copy of the media item whose operations use ctx
+func (mediaItem *MediaItem) WithContext(ctx context.Context) *MediaItem {
+	withContext := *mediaItem
+	withContext.client = mediaItem.client.WithContext(ctx)
+	return &withContext
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// Checks that a hung endpoint does not block a request whose context has a deadline
+func TestClient_WithContext(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	const vappPath = "/api/vApp/vapp-1"
+	server.HandleFunc(http.MethodGet, vappPath, func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+
+	client := &newMockClient(t, server).Client
+	if client.Context() != context.Background() {
+		t.Errorf("expected background context by default")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	vapp := NewVApp(client).WithContext(ctx)
+	vapp.VApp.HREF = server.URL() + vappPath
+
+	start := time.Now()
+	err := vapp.Refresh()
+	if err == nil || !strings.Contains(err.Error(), context.DeadlineExceeded.Error()) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("request not stopped by the context after %s", elapsed)
+	}
+	if client.ctx != nil {
+		t.Errorf("original client changed")
+	}
+}
+
+// Checks that waiting for a task stops when the context is cancelled
+func TestTask_WaitWithContext(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	const taskPath = "/api/task/1"
+	server.Handle(http.MethodGet, taskPath, vcdtest.Response{ContentType: types.MimeTask,
+		Body: `<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="running" href="{{server}}` + taskPath + `"/>`})
+
+	client := &newMockClient(t, server).Client
+	ctx, cancel := context.WithCancel(context.Background())
+	task := NewTask(client).WithContext(ctx)
+	task.Task.HREF = server.URL() + taskPath
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+	}()
+	start := time.Now()
+	err := task.WaitInspectTaskCompletion(nil, time.Minute)
+	if err == nil || !strings.Contains(err.Error(), context.Canceled.Error()) {
+		t.Errorf("expected cancelled wait, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("wait not stopped by the context after %s", elapsed)
+	}
+}
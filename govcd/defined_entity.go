@@ -0,0 +1,401 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// Runtime defined entities (RDE, API 35.0+) are JSON documents stored in vCD, whose content follows
+// the schema of their type. RDE types are defined by the provider, and implement RDE interfaces,
+// which declare the behaviors that can be invoked on the entities.
+
+// DefinedInterface is an interface of runtime defined entities
+type DefinedInterface struct {
+	DefinedInterface *types.DefinedInterface
+	client           *Client
+}
+
+// DefinedEntityType is a type of runtime defined entities
+type DefinedEntityType struct {
+	DefinedEntityType *types.DefinedEntityType
+	client            *Client
+}
+
+// DefinedEntity is a runtime defined entity
+type DefinedEntity struct {
+	DefinedEntity *types.DefinedEntity
+	client        *Client
+}
+
+// CreateDefinedInterface creates an RDE interface. Only system administrators can create them.
+func (client *Client) CreateDefinedInterface(interfaceConfig *types.DefinedInterface) (*DefinedInterface, error) {
+	if interfaceConfig == nil || interfaceConfig.Name == "" {
+		return nil, fmt.Errorf("RDE interface name is required")
+	}
+	err := validateRdeIdentity(interfaceConfig.Vendor, interfaceConfig.Namespace, interfaceConfig.Version)
+	if err != nil {
+		return nil, fmt.Errorf("invalid RDE interface %s: %s", interfaceConfig.Name, err)
+	}
+	urlRef, apiVersion, err := rdeBuildEndpoint(client, types.OpenApiEndpointRdeInterfaces)
+	if err != nil {
+		return nil, err
+	}
+
+	definedInterface := &DefinedInterface{DefinedInterface: &types.DefinedInterface{}, client: client}
+	err = client.OpenApiPostItem(apiVersion, urlRef, nil, interfaceConfig, definedInterface.DefinedInterface)
+	if err != nil {
+		return nil, fmt.Errorf("error creating RDE interface: %s", err)
+	}
+	return definedInterface, nil
+}
+
+// GetAllDefinedInterfaces retrieves the RDE interfaces. Query parameters can be supplied to perform
+// additional filtering.
+func (client *Client) GetAllDefinedInterfaces(queryParameters url.Values) ([]*DefinedInterface, error) {
+	urlRef, apiVersion, err := rdeBuildEndpoint(client, types.OpenApiEndpointRdeInterfaces)
+	if err != nil {
+		return nil, err
+	}
+
+	var typeResponses []*types.DefinedInterface
+	err = client.OpenApiGetAllItems(apiVersion, urlRef, queryParameters, &typeResponses)
+	if err != nil {
+		return nil, err
+	}
+
+	definedInterfaces := make([]*DefinedInterface, len(typeResponses))
+	for index, typeResponse := range typeResponses {
+		definedInterfaces[index] = &DefinedInterface{DefinedInterface: typeResponse, client: client}
+	}
+	return definedInterfaces, nil
+}
+
+// GetDefinedInterface retrieves the RDE interface with the given vendor, namespace and version
+func (client *Client) GetDefinedInterface(vendor, namespace, version string) (*DefinedInterface, error) {
+	definedInterfaces, err := client.GetAllDefinedInterfaces(rdeIdentityFilter(vendor, namespace, version))
+	if err != nil {
+		return nil, err
+	}
+	if len(definedInterfaces) == 0 {
+		return nil, wrapErrorf(ErrorEntityNotFound, "RDE interface %s:%s:%s not found: %s",
+			vendor, namespace, version, ErrorEntityNotFound)
+	}
+	return definedInterfaces[0], nil
+}
+
+// GetDefinedInterfaceById retrieves the RDE interface with the given ID
+func (client *Client) GetDefinedInterfaceById(id string) (*DefinedInterface, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty RDE interface ID")
+	}
+	urlRef, apiVersion, err := rdeBuildEndpoint(client, types.OpenApiEndpointRdeInterfaces, id)
+	if err != nil {
+		return nil, err
+	}
+
+	definedInterface := &DefinedInterface{DefinedInterface: &types.DefinedInterface{}, client: client}
+	err = client.OpenApiGetItem(apiVersion, urlRef, nil, definedInterface.DefinedInterface)
+	if err != nil {
+		return nil, err
+	}
+	return definedInterface, nil
+}
+
+// Delete removes the RDE interface. It fails while RDE types implement it.
+func (definedInterface *DefinedInterface) Delete() error {
+	if definedInterface.DefinedInterface.ID == "" {
+		return fmt.Errorf("cannot delete RDE interface without ID")
+	}
+	urlRef, apiVersion, err := rdeBuildEndpoint(definedInterface.client, types.OpenApiEndpointRdeInterfaces,
+		definedInterface.DefinedInterface.ID)
+	if err != nil {
+		return err
+	}
+	err = definedInterface.client.OpenApiDeleteItem(apiVersion, urlRef, nil)
+	if err != nil {
+		return fmt.Errorf("error deleting RDE interface: %s", err)
+	}
+	return nil
+}
+
+// CreateDefinedEntityType creates an RDE type. Only system administrators can create them.
+func (client *Client) CreateDefinedEntityType(entityTypeConfig *types.DefinedEntityType) (*DefinedEntityType, error) {
+	err := validateDefinedEntityType(entityTypeConfig)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, apiVersion, err := rdeBuildEndpoint(client, types.OpenApiEndpointRdeEntityTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	entityType := &DefinedEntityType{DefinedEntityType: &types.DefinedEntityType{}, client: client}
+	err = client.OpenApiPostItem(apiVersion, urlRef, nil, entityTypeConfig, entityType.DefinedEntityType)
+	if err != nil {
+		return nil, fmt.Errorf("error creating RDE type: %s", err)
+	}
+	return entityType, nil
+}
+
+// GetAllDefinedEntityTypes retrieves the RDE types. Query parameters can be supplied to perform
+// additional filtering.
+func (client *Client) GetAllDefinedEntityTypes(queryParameters url.Values) ([]*DefinedEntityType, error) {
+	urlRef, apiVersion, err := rdeBuildEndpoint(client, types.OpenApiEndpointRdeEntityTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	var typeResponses []*types.DefinedEntityType
+	err = client.OpenApiGetAllItems(apiVersion, urlRef, queryParameters, &typeResponses)
+	if err != nil {
+		return nil, err
+	}
+
+	entityTypes := make([]*DefinedEntityType, len(typeResponses))
+	for index, typeResponse := range typeResponses {
+		entityTypes[index] = &DefinedEntityType{DefinedEntityType: typeResponse, client: client}
+	}
+	return entityTypes, nil
+}
+
+// GetDefinedEntityType retrieves the RDE type with the given vendor, namespace and version
+func (client *Client) GetDefinedEntityType(vendor, namespace, version string) (*DefinedEntityType, error) {
+	entityTypes, err := client.GetAllDefinedEntityTypes(rdeIdentityFilter(vendor, namespace, version))
+	if err != nil {
+		return nil, err
+	}
+	if len(entityTypes) == 0 {
+		return nil, wrapErrorf(ErrorEntityNotFound, "RDE type %s:%s:%s not found: %s",
+			vendor, namespace, version, ErrorEntityNotFound)
+	}
+	return entityTypes[0], nil
+}
+
+// GetDefinedEntityTypeById retrieves the RDE type with the given ID
+func (client *Client) GetDefinedEntityTypeById(id string) (*DefinedEntityType, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty RDE type ID")
+	}
+	urlRef, apiVersion, err := rdeBuildEndpoint(client, types.OpenApiEndpointRdeEntityTypes, id)
+	if err != nil {
+		return nil, err
+	}
+
+	entityType := &DefinedEntityType{DefinedEntityType: &types.DefinedEntityType{}, client: client}
+	err = client.OpenApiGetItem(apiVersion, urlRef, nil, entityType.DefinedEntityType)
+	if err != nil {
+		return nil, err
+	}
+	return entityType, nil
+}
+
+// Update sends the current definition of the RDE type to vCD. Its vendor, namespace and version
+// cannot change.
+func (entityType *DefinedEntityType) Update() error {
+	if entityType.DefinedEntityType.ID == "" {
+		return fmt.Errorf("cannot update RDE type without ID")
+	}
+	err := validateDefinedEntityType(entityType.DefinedEntityType)
+	if err != nil {
+		return err
+	}
+	urlRef, apiVersion, err := rdeBuildEndpoint(entityType.client, types.OpenApiEndpointRdeEntityTypes,
+		entityType.DefinedEntityType.ID)
+	if err != nil {
+		return err
+	}
+
+	updated := &types.DefinedEntityType{}
+	err = entityType.client.OpenApiPutItem(apiVersion, urlRef, nil, entityType.DefinedEntityType, updated)
+	if err != nil {
+		return fmt.Errorf("error updating RDE type: %s", err)
+	}
+	entityType.DefinedEntityType = updated
+	return nil
+}
+
+// Delete removes the RDE type. It fails while entities of the type exist.
+func (entityType *DefinedEntityType) Delete() error {
+	if entityType.DefinedEntityType.ID == "" {
+		return fmt.Errorf("cannot delete RDE type without ID")
+	}
+	urlRef, apiVersion, err := rdeBuildEndpoint(entityType.client, types.OpenApiEndpointRdeEntityTypes,
+		entityType.DefinedEntityType.ID)
+	if err != nil {
+		return err
+	}
+	err = entityType.client.OpenApiDeleteItem(apiVersion, urlRef, nil)
+	if err != nil {
+		return fmt.Errorf("error deleting RDE type: %s", err)
+	}
+	return nil
+}
+
+// CreateDefinedEntity creates an entity of the RDE type, in the org of the client. The entity is
+// PRE_CREATED, and must be resolved before being used.
+func (entityType *DefinedEntityType) CreateDefinedEntity(entityConfig *types.DefinedEntity) (*DefinedEntity, error) {
+	if entityType.DefinedEntityType.ID == "" {
+		return nil, fmt.Errorf("cannot create an entity of an RDE type without ID")
+	}
+	if entityConfig == nil || entityConfig.Name == "" {
+		return nil, fmt.Errorf("RDE name is required")
+	}
+	if entityConfig.Entity == nil {
+		return nil, fmt.Errorf("RDE %s has no content", entityConfig.Name)
+	}
+	client := entityType.client
+	urlRef, apiVersion, err := rdeBuildEndpoint(client, types.OpenApiEndpointRdeEntityTypes, entityType.DefinedEntityType.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// vCD creates the entity asynchronously, and the task references it as its owner
+	resp, err := client.openApiSendPayload(http.MethodPost, apiVersion, urlRef, nil, entityConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error creating RDE: %s", err)
+	}
+	entity := &DefinedEntity{DefinedEntity: &types.DefinedEntity{}, client: client}
+	if resp.StatusCode != http.StatusAccepted {
+		err = decodeJsonBody(resp, entity.DefinedEntity)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error decoding JSON response after POST: %s", err)
+		}
+		return entity, nil
+	}
+	_ = resp.Body.Close()
+
+	task := NewTask(client)
+	task.Task.HREF = resp.Header.Get("Location")
+	if task.Task.HREF == "" {
+		return nil, fmt.Errorf("no task in the response of the creation of RDE %s", entityConfig.Name)
+	}
+	err = task.WaitTaskCompletion()
+	if err != nil {
+		return nil, fmt.Errorf("error waiting for the creation of RDE %s: %s", entityConfig.Name, err)
+	}
+	if task.Task.Owner == nil || task.Task.Owner.ID == "" {
+		return nil, fmt.Errorf("task %s does not reference the created RDE", task.Task.HREF)
+	}
+	return client.GetDefinedEntityById(task.Task.Owner.ID)
+}
+
+// GetDefinedEntityById retrieves the RDE with the given ID
+func (client *Client) GetDefinedEntityById(id string) (*DefinedEntity, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty RDE ID")
+	}
+	urlRef, apiVersion, err := rdeBuildEndpoint(client, types.OpenApiEndpointRdeEntities, id)
+	if err != nil {
+		return nil, err
+	}
+
+	entity := &DefinedEntity{DefinedEntity: &types.DefinedEntity{}, client: client}
+	err = client.OpenApiGetItem(apiVersion, urlRef, nil, entity.DefinedEntity)
+	if err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+// Resolve validates the content of the RDE against the schema of its type. The entity is then
+// RESOLVED, or in RESOLUTION_ERROR if its content is not valid.
+func (entity *DefinedEntity) Resolve() error {
+	if entity.DefinedEntity.ID == "" {
+		return fmt.Errorf("cannot resolve RDE without ID")
+	}
+	urlRef, apiVersion, err := rdeBuildEndpoint(entity.client, types.OpenApiEndpointRdeEntityResolve, entity.DefinedEntity.ID)
+	if err != nil {
+		return err
+	}
+
+	resolved := &types.DefinedEntity{}
+	err = entity.client.OpenApiPostItem(apiVersion, urlRef, nil, nil, resolved)
+	if err != nil {
+		return fmt.Errorf("error resolving RDE %s: %s", entity.DefinedEntity.Name, err)
+	}
+	entity.DefinedEntity = resolved
+	return nil
+}
+
+// Delete removes the RDE
+func (entity *DefinedEntity) Delete() error {
+	if entity.DefinedEntity.ID == "" {
+		return fmt.Errorf("cannot delete RDE without ID")
+	}
+	urlRef, apiVersion, err := rdeBuildEndpoint(entity.client, types.OpenApiEndpointRdeEntities, entity.DefinedEntity.ID)
+	if err != nil {
+		return err
+	}
+	err = entity.client.OpenApiDeleteItem(apiVersion, urlRef, nil)
+	if err != nil {
+		return fmt.Errorf("error deleting RDE: %s", err)
+	}
+	return nil
+}
+
+// rdeBuildEndpoint returns the URL of an RDE endpoint, with the API version to use. The first IDs
+// fill the placeholders of endpointFormat, the others are appended to it.
+func rdeBuildEndpoint(client *Client, endpointFormat string, ids ...string) (*url.URL, string, error) {
+	endpoint := types.OpenApiPathVersion1_0_0 + endpointFormat
+	apiVersion, err := client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, "", err
+	}
+	placeholders := strings.Count(endpointFormat, "%s")
+	if len(ids) < placeholders {
+		return nil, "", fmt.Errorf("missing IDs for endpoint %s", endpointFormat)
+	}
+	if placeholders > 0 {
+		args := make([]interface{}, placeholders)
+		for i := range args {
+			args[i] = ids[i]
+		}
+		endpoint = fmt.Sprintf(endpoint, args...)
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(append([]string{endpoint}, ids[placeholders:]...)...)
+	if err != nil {
+		return nil, "", err
+	}
+	return urlRef, apiVersion, nil
+}
+
+// rdeIdentityFilter returns the query parameters selecting the RDE interfaces or types with the
+// given vendor, namespace and version
+func rdeIdentityFilter(vendor, namespace, version string) url.Values {
+	queryParams := url.Values{}
+	queryParams.Set("filter", fiqlEq("vendor", vendor)+";"+fiqlEq("nss", namespace)+";"+fiqlEq("version", version))
+	return queryParams
+}
+
+// validateRdeIdentity checks the vendor, namespace and version which identify RDE interfaces and
+// types
+func validateRdeIdentity(vendor, namespace, version string) error {
+	if vendor == "" || namespace == "" || version == "" {
+		return fmt.Errorf("vendor, namespace and version are required")
+	}
+	return nil
+}
+
+// validateDefinedEntityType checks the fields needed to create or update an RDE type
+func validateDefinedEntityType(entityTypeConfig *types.DefinedEntityType) error {
+	if entityTypeConfig == nil || entityTypeConfig.Name == "" {
+		return fmt.Errorf("RDE type name is required")
+	}
+	err := validateRdeIdentity(entityTypeConfig.Vendor, entityTypeConfig.Namespace, entityTypeConfig.Version)
+	if err != nil {
+		return fmt.Errorf("invalid RDE type %s: %s", entityTypeConfig.Name, err)
+	}
+	if entityTypeConfig.Schema == nil {
+		return fmt.Errorf("RDE type %s has no schema", entityTypeConfig.Name)
+	}
+	return nil
+}
@@ -0,0 +1,296 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// Behaviors of runtime defined entities (API 37.0+, vCD 10.4+) are declared by RDE interfaces and
+// inherited by the RDE types implementing them, which can override their execution. Invoking a
+// behavior needs the access level to the entity set for the behavior in the access controls of
+// the type. Invocations are asynchronous, and the result of the behavior is the result of the task.
+
+// AddBehavior declares a behavior in the RDE interface. The RDE types implementing the interface
+// get it.
+func (definedInterface *DefinedInterface) AddBehavior(behaviorConfig types.Behavior) (*types.Behavior, error) {
+	err := validateBehavior(&behaviorConfig)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, apiVersion, err := definedInterface.behaviorsEndpoint()
+	if err != nil {
+		return nil, err
+	}
+
+	behavior := &types.Behavior{}
+	err = definedInterface.client.OpenApiPostItem(apiVersion, urlRef, nil, behaviorConfig, behavior)
+	if err != nil {
+		return nil, fmt.Errorf("error adding behavior to RDE interface: %s", err)
+	}
+	return behavior, nil
+}
+
+// GetAllBehaviors retrieves the behaviors of the RDE interface. Query parameters can be supplied to
+// perform additional filtering.
+func (definedInterface *DefinedInterface) GetAllBehaviors(queryParameters url.Values) ([]*types.Behavior, error) {
+	urlRef, apiVersion, err := definedInterface.behaviorsEndpoint()
+	if err != nil {
+		return nil, err
+	}
+	return getAllBehaviors(definedInterface.client, apiVersion, urlRef, queryParameters)
+}
+
+// GetBehaviorByName retrieves the behavior of the RDE interface with the given name
+func (definedInterface *DefinedInterface) GetBehaviorByName(name string) (*types.Behavior, error) {
+	behaviors, err := definedInterface.GetAllBehaviors(nil)
+	if err != nil {
+		return nil, err
+	}
+	return findBehaviorByName(behaviors, name)
+}
+
+// UpdateBehavior sends the given definition of a behavior of the RDE interface to vCD
+func (definedInterface *DefinedInterface) UpdateBehavior(behaviorConfig types.Behavior) (*types.Behavior, error) {
+	if behaviorConfig.ID == "" {
+		return nil, fmt.Errorf("cannot update behavior without ID")
+	}
+	err := validateBehavior(&behaviorConfig)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, apiVersion, err := definedInterface.behaviorsEndpoint(behaviorConfig.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := &types.Behavior{}
+	err = definedInterface.client.OpenApiPutItem(apiVersion, urlRef, nil, behaviorConfig, updated)
+	if err != nil {
+		return nil, fmt.Errorf("error updating behavior of RDE interface: %s", err)
+	}
+	return updated, nil
+}
+
+// DeleteBehavior removes the behavior with the given ID from the RDE interface
+func (definedInterface *DefinedInterface) DeleteBehavior(behaviorId string) error {
+	if behaviorId == "" {
+		return fmt.Errorf("cannot delete behavior without ID")
+	}
+	urlRef, apiVersion, err := definedInterface.behaviorsEndpoint(behaviorId)
+	if err != nil {
+		return err
+	}
+	err = definedInterface.client.OpenApiDeleteItem(apiVersion, urlRef, nil)
+	if err != nil {
+		return fmt.Errorf("error deleting behavior of RDE interface: %s", err)
+	}
+	return nil
+}
+
+// GetAllBehaviors retrieves the behaviors of the RDE type: the ones of its interfaces, and its
+// overrides. Query parameters can be supplied to perform additional filtering.
+func (entityType *DefinedEntityType) GetAllBehaviors(queryParameters url.Values) ([]*types.Behavior, error) {
+	urlRef, apiVersion, err := entityType.behaviorsEndpoint()
+	if err != nil {
+		return nil, err
+	}
+	return getAllBehaviors(entityType.client, apiVersion, urlRef, queryParameters)
+}
+
+// GetBehaviorByName retrieves the behavior of the RDE type with the given name
+func (entityType *DefinedEntityType) GetBehaviorByName(name string) (*types.Behavior, error) {
+	behaviors, err := entityType.GetAllBehaviors(nil)
+	if err != nil {
+		return nil, err
+	}
+	return findBehaviorByName(behaviors, name)
+}
+
+// UpdateBehaviorOverride sets the execution of a behavior of an interface for the entities of the
+// RDE type. The ID of behaviorConfig is the one of the behavior of the interface.
+func (entityType *DefinedEntityType) UpdateBehaviorOverride(behaviorConfig types.Behavior) (*types.Behavior, error) {
+	if behaviorConfig.ID == "" {
+		return nil, fmt.Errorf("cannot override behavior without ID")
+	}
+	err := validateBehavior(&behaviorConfig)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, apiVersion, err := entityType.behaviorsEndpoint(behaviorConfig.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := &types.Behavior{}
+	err = entityType.client.OpenApiPutItem(apiVersion, urlRef, nil, behaviorConfig, updated)
+	if err != nil {
+		return nil, fmt.Errorf("error overriding behavior of RDE type: %s", err)
+	}
+	return updated, nil
+}
+
+// DeleteBehaviorOverride removes the override of the behavior with the given ID from the RDE type,
+// whose entities get the execution of the interface again
+func (entityType *DefinedEntityType) DeleteBehaviorOverride(behaviorId string) error {
+	if behaviorId == "" {
+		return fmt.Errorf("cannot delete behavior override without ID")
+	}
+	urlRef, apiVersion, err := entityType.behaviorsEndpoint(behaviorId)
+	if err != nil {
+		return err
+	}
+	err = entityType.client.OpenApiDeleteItem(apiVersion, urlRef, nil)
+	if err != nil {
+		return fmt.Errorf("error deleting behavior override of RDE type: %s", err)
+	}
+	return nil
+}
+
+// GetAllBehaviorsAccessControls retrieves the access levels needed to invoke the behaviors of the
+// RDE type. Query parameters can be supplied to perform additional filtering.
+func (entityType *DefinedEntityType) GetAllBehaviorsAccessControls(queryParameters url.Values) ([]*types.BehaviorAccess, error) {
+	urlRef, apiVersion, err := entityType.behaviorAccessControlsEndpoint()
+	if err != nil {
+		return nil, err
+	}
+
+	var accessControls []*types.BehaviorAccess
+	err = entityType.client.OpenApiGetAllItems(apiVersion, urlRef, queryParameters, &accessControls)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving behavior access controls of RDE type: %s", err)
+	}
+	return accessControls, nil
+}
+
+// SetBehaviorAccessControls replaces the access controls of the behaviors of the RDE type. Behaviors
+// missing from the list cannot be invoked; an empty list removes all the access controls.
+func (entityType *DefinedEntityType) SetBehaviorAccessControls(accessControls []*types.BehaviorAccess) error {
+	for _, accessControl := range accessControls {
+		if accessControl == nil || accessControl.BehaviorId == "" {
+			return fmt.Errorf("behavior access controls need a behavior ID")
+		}
+		switch accessControl.AccessLevelId {
+		case types.RdeAccessLevelReadOnly, types.RdeAccessLevelReadWrite, types.RdeAccessLevelFullControl:
+		default:
+			return fmt.Errorf("invalid access level '%s' for behavior %s", accessControl.AccessLevelId, accessControl.BehaviorId)
+		}
+	}
+	urlRef, apiVersion, err := entityType.behaviorAccessControlsEndpoint()
+	if err != nil {
+		return err
+	}
+
+	payload := struct {
+		Values []*types.BehaviorAccess `json:"values"`
+	}{Values: accessControls}
+	if payload.Values == nil {
+		payload.Values = []*types.BehaviorAccess{}
+	}
+	err = entityType.client.OpenApiPutItem(apiVersion, urlRef, nil, payload, nil)
+	if err != nil {
+		return fmt.Errorf("error setting behavior access controls of RDE type: %s", err)
+	}
+	return nil
+}
+
+// InvokeBehavior invokes the behavior with the given ID on the RDE, waits for its completion and
+// returns its result as is. The result of a behavior without a value is empty.
+func (entity *DefinedEntity) InvokeBehavior(behaviorId string, invocation types.BehaviorInvocation) (string, error) {
+	if entity.DefinedEntity.ID == "" {
+		return "", fmt.Errorf("cannot invoke behavior on RDE without ID")
+	}
+	if behaviorId == "" {
+		return "", fmt.Errorf("cannot invoke behavior without ID")
+	}
+	urlRef, apiVersion, err := rdeBuildEndpoint(entity.client, types.OpenApiEndpointRdeEntityBehaviorInvocations,
+		entity.DefinedEntity.ID, behaviorId)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := entity.client.openApiPostItemForTaskResultContent(apiVersion, urlRef, nil, invocation)
+	if err != nil {
+		return "", fmt.Errorf("error invoking behavior %s on RDE %s: %s", behaviorId, entity.DefinedEntity.Name, err)
+	}
+	return result, nil
+}
+
+// InvokeBehaviorAndUnmarshal invokes the behavior with the given ID on the RDE, like InvokeBehavior,
+// and unmarshals its JSON result into output
+func (entity *DefinedEntity) InvokeBehaviorAndUnmarshal(behaviorId string, invocation types.BehaviorInvocation, output interface{}) error {
+	result, err := entity.InvokeBehavior(behaviorId, invocation)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal([]byte(result), output)
+	if err != nil {
+		return fmt.Errorf("error decoding the result of behavior %s: %s", behaviorId, err)
+	}
+	return nil
+}
+
+// behaviorsEndpoint returns the URL of the behaviors of the RDE interface, or of one of them
+func (definedInterface *DefinedInterface) behaviorsEndpoint(behaviorId ...string) (*url.URL, string, error) {
+	if definedInterface.DefinedInterface.ID == "" {
+		return nil, "", fmt.Errorf("RDE interface %s has no ID", definedInterface.DefinedInterface.Name)
+	}
+	return rdeBuildEndpoint(definedInterface.client, types.OpenApiEndpointRdeInterfaceBehaviors,
+		append([]string{definedInterface.DefinedInterface.ID}, behaviorId...)...)
+}
+
+// behaviorsEndpoint returns the URL of the behaviors of the RDE type, or of one of them
+func (entityType *DefinedEntityType) behaviorsEndpoint(behaviorId ...string) (*url.URL, string, error) {
+	if entityType.DefinedEntityType.ID == "" {
+		return nil, "", fmt.Errorf("RDE type %s has no ID", entityType.DefinedEntityType.Name)
+	}
+	return rdeBuildEndpoint(entityType.client, types.OpenApiEndpointRdeEntityTypeBehaviors,
+		append([]string{entityType.DefinedEntityType.ID}, behaviorId...)...)
+}
+
+// behaviorAccessControlsEndpoint returns the URL of the behavior access controls of the RDE type
+func (entityType *DefinedEntityType) behaviorAccessControlsEndpoint() (*url.URL, string, error) {
+	if entityType.DefinedEntityType.ID == "" {
+		return nil, "", fmt.Errorf("RDE type %s has no ID", entityType.DefinedEntityType.Name)
+	}
+	return rdeBuildEndpoint(entityType.client, types.OpenApiEndpointRdeEntityTypeBehaviorAccessControls,
+		entityType.DefinedEntityType.ID)
+}
+
+// getAllBehaviors retrieves the behaviors at urlRef, of an RDE interface or type
+func getAllBehaviors(client *Client, apiVersion string, urlRef *url.URL, queryParameters url.Values) ([]*types.Behavior, error) {
+	var behaviors []*types.Behavior
+	err := client.OpenApiGetAllItems(apiVersion, urlRef, queryParameters, &behaviors)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving behaviors: %s", err)
+	}
+	return behaviors, nil
+}
+
+// findBehaviorByName returns the behavior with the given name. Behaviors are filtered here, as their
+// endpoints do not support filters.
+func findBehaviorByName(behaviors []*types.Behavior, name string) (*types.Behavior, error) {
+	for _, behavior := range behaviors {
+		if behavior.Name == name {
+			return behavior, nil
+		}
+	}
+	return nil, wrapErrorf(ErrorEntityNotFound, "behavior '%s' not found: %s", name, ErrorEntityNotFound)
+}
+
+// validateBehavior checks the fields needed to define or override a behavior
+func validateBehavior(behaviorConfig *types.Behavior) error {
+	if behaviorConfig.Name == "" {
+		return fmt.Errorf("behavior name is required")
+	}
+	if behaviorConfig.Execution == nil || behaviorConfig.Execution["type"] == nil {
+		return fmt.Errorf("behavior %s needs an execution type", behaviorConfig.Name)
+	}
+	return nil
+}
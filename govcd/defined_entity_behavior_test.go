@@ -0,0 +1,127 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// Checks the definition of a behavior in an RDE interface, its override and access control in an
+// RDE type, and its invocation on an entity, against a fake vCD
+func TestDefinedEntity_InvokeBehavior(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	const interfaceId = "urn:vcloud:interface:acme:managed:1.0.0"
+	const typeId = "urn:vcloud:type:acme:cluster:1.0.0"
+	const entityId = "urn:vcloud:entity:acme:cluster:77777777-7777-7777-7777-777777777777"
+	const behaviorId = "urn:vcloud:behavior-interface:scale:acme:managed:1.0.0"
+	const interfaceBehaviorsPath = "/cloudapi/1.0.0/interfaces/" + interfaceId + "/behaviors/"
+	const typeBehaviorsPath = "/cloudapi/1.0.0/entityTypes/" + typeId + "/behaviors/"
+	const accessControlsPath = "/cloudapi/1.0.0/entityTypes/" + typeId + "/behaviorAccessControls"
+	const invocationsPath = "/cloudapi/1.0.0/entities/" + entityId + "/behaviors/" + behaviorId + "/invocations"
+	server.HandleJSON(http.MethodPost, interfaceBehaviorsPath, http.StatusCreated,
+		`{"id":"`+behaviorId+`","name":"scale","execution":{"type":"noop"}}`)
+	server.HandleJSON(http.MethodGet, typeBehaviorsPath, http.StatusOK,
+		`{"resultTotal":1,"pageCount":1,"page":1,"pageSize":128,"values":[{"id":"`+behaviorId+`","name":"scale","execution":{"type":"noop"}}]}`)
+	server.HandleJSON(http.MethodPut, typeBehaviorsPath+behaviorId, http.StatusOK,
+		`{"id":"urn:vcloud:behavior-type:scale:acme:cluster:1.0.0","name":"scale","ref":"`+behaviorId+`",
+		"execution":{"type":"WebHook","id":"scale-hook"}}`)
+	server.Handle(http.MethodPut, accessControlsPath, vcdtest.Response{Status: http.StatusOK, ContentType: "application/json",
+		Body: `{"values":[]}`})
+	server.Handle(http.MethodPost, invocationsPath, vcdtest.Response{
+		Status: http.StatusAccepted,
+		Header: map[string]string{"Location": "{{server}}" + vcdtest.MockTaskPath},
+	})
+	server.HandleXML(http.MethodGet, vcdtest.MockTaskPath, http.StatusOK,
+		`<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="success" href="{{server}}`+vcdtest.MockTaskPath+`">
+		  <Result><ResultContent>{"size":5,"message":"scaled"}</ResultContent></Result>
+		</Task>`)
+
+	vcdClient := newMockClient(t, server)
+	client := &vcdClient.Client
+	definedInterface := &DefinedInterface{DefinedInterface: &types.DefinedInterface{ID: interfaceId, Name: "managed"}, client: client}
+	entityType := &DefinedEntityType{DefinedEntityType: &types.DefinedEntityType{ID: typeId, Name: "cluster"}, client: client}
+	entity := &DefinedEntity{DefinedEntity: &types.DefinedEntity{ID: entityId, Name: "cluster1"}, client: client}
+
+	if _, err := definedInterface.AddBehavior(types.Behavior{Name: "scale"}); err == nil {
+		t.Errorf("expected error adding behavior without execution")
+	}
+	_, err := definedInterface.AddBehavior(types.Behavior{Name: "scale",
+		Execution: map[string]interface{}{"type": types.RdeBehaviorExecutionNoop}})
+	if err != nil {
+		t.Fatalf("error adding behavior to RDE interface: %s", err)
+	}
+	behavior, err := entityType.GetBehaviorByName("scale")
+	if err != nil {
+		t.Fatalf("error retrieving behavior of RDE type: %s", err)
+	}
+	if _, err = entityType.GetBehaviorByName("shrink"); err == nil {
+		t.Errorf("expected error retrieving missing behavior")
+	}
+	behavior.Execution = map[string]interface{}{"type": types.RdeBehaviorExecutionWebhook, "id": "scale-hook"}
+	override, err := entityType.UpdateBehaviorOverride(*behavior)
+	if err != nil {
+		t.Fatalf("error overriding behavior: %s", err)
+	}
+	puts := server.RequestsTo(http.MethodPut, typeBehaviorsPath+behaviorId)
+	if override.Ref != behaviorId || len(puts) != 1 || puts[0].Header.Get("Accept") != "application/json;version=37.0" {
+		t.Errorf("unexpected behavior override %+v sent with %#v", override, puts)
+	}
+
+	invalid := []*types.BehaviorAccess{{BehaviorId: behaviorId, AccessLevelId: "ReadWrite"}}
+	if err = entityType.SetBehaviorAccessControls(invalid); err == nil {
+		t.Errorf("expected error setting an invalid access level")
+	}
+	err = entityType.SetBehaviorAccessControls([]*types.BehaviorAccess{
+		{BehaviorId: behaviorId, AccessLevelId: types.RdeAccessLevelReadWrite}})
+	if err != nil {
+		t.Fatalf("error setting behavior access controls: %s", err)
+	}
+	puts = server.RequestsTo(http.MethodPut, accessControlsPath)
+	var sentAccess struct {
+		Values []types.BehaviorAccess `json:"values"`
+	}
+	if len(puts) != 1 || json.Unmarshal([]byte(puts[0].Body), &sentAccess) != nil || len(sentAccess.Values) != 1 ||
+		sentAccess.Values[0].AccessLevelId != types.RdeAccessLevelReadWrite {
+		t.Errorf("unexpected behavior access controls sent: %#v", puts)
+	}
+
+	invocation := types.BehaviorInvocation{Arguments: map[string]interface{}{"size": 5}}
+	result, err := entity.InvokeBehavior(behaviorId, invocation)
+	if err != nil {
+		t.Fatalf("error invoking behavior: %s", err)
+	}
+	if result != `{"size":5,"message":"scaled"}` {
+		t.Errorf("unexpected result of behavior: %s", result)
+	}
+	posts := server.RequestsTo(http.MethodPost, invocationsPath)
+	var sentInvocation types.BehaviorInvocation
+	if len(posts) != 1 || json.Unmarshal([]byte(posts[0].Body), &sentInvocation) != nil ||
+		sentInvocation.Arguments.(map[string]interface{})["size"] != float64(5) {
+		t.Errorf("unexpected invocation sent: %#v", posts)
+	}
+	var output struct {
+		Size    int    `json:"size"`
+		Message string `json:"message"`
+	}
+	err = entity.InvokeBehaviorAndUnmarshal(behaviorId, invocation, &output)
+	if err != nil || output.Size != 5 || output.Message != "scaled" {
+		t.Errorf("unexpected result of behavior: %+v %v", output, err)
+	}
+
+	// Behaviors without a value have an empty result
+	server.HandleXML(http.MethodGet, vcdtest.MockTaskPath, http.StatusOK,
+		`<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="success" href="{{server}}`+vcdtest.MockTaskPath+`"/>`)
+	result, err = entity.InvokeBehavior(behaviorId, types.BehaviorInvocation{})
+	if err != nil || result != "" {
+		t.Errorf("unexpected result of behavior without value: %q %v", result, err)
+	}
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// Checks the creation of an RDE type and of an entity of the type, which vCD creates
+// asynchronously, against a fake vCD
+func TestDefinedEntityType_CreateDefinedEntity(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	const typeId = "urn:vcloud:type:acme:cluster:1.0.0"
+	const entityId = "urn:vcloud:entity:acme:cluster:77777777-7777-7777-7777-777777777777"
+	const typesPath = "/cloudapi/1.0.0/entityTypes/"
+	const entitiesPath = "/cloudapi/1.0.0/entities/"
+	typeJson := `{"id":"` + typeId + `","name":"cluster","vendor":"acme","nss":"cluster","version":"1.0.0",
+		"schema":{"type":"object"},"interfaces":["urn:vcloud:interface:acme:managed:1.0.0"]}`
+	server.HandleJSON(http.MethodPost, typesPath, http.StatusCreated, typeJson)
+	server.HandleJSON(http.MethodGet, typesPath, http.StatusOK,
+		`{"resultTotal":1,"pageCount":1,"page":1,"pageSize":128,"values":[`+typeJson+`]}`)
+	server.Handle(http.MethodPost, typesPath+typeId, vcdtest.Response{
+		Status: http.StatusAccepted,
+		Header: map[string]string{"Location": "{{server}}" + vcdtest.MockTaskPath},
+	})
+	server.HandleXML(http.MethodGet, vcdtest.MockTaskPath, http.StatusOK,
+		`<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="success" href="{{server}}`+vcdtest.MockTaskPath+`">
+		  <Owner href="" id="`+entityId+`" name="cluster1" type="application/json"/>
+		</Task>`)
+	server.HandleJSON(http.MethodGet, entitiesPath+entityId, http.StatusOK,
+		`{"id":"`+entityId+`","entityType":"`+typeId+`","name":"cluster1","entity":{"size":3},"state":"PRE_CREATED"}`)
+	server.HandleJSON(http.MethodPost, entitiesPath+entityId+"/resolve", http.StatusOK,
+		`{"id":"`+entityId+`","entityType":"`+typeId+`","name":"cluster1","entity":{"size":3},"state":"RESOLVED"}`)
+	server.Handle(http.MethodDelete, entitiesPath+entityId, vcdtest.Response{Status: http.StatusNoContent})
+
+	vcdClient := newMockClient(t, server)
+	client := &vcdClient.Client
+
+	if _, err := client.CreateDefinedEntityType(&types.DefinedEntityType{Name: "cluster", Vendor: "acme",
+		Namespace: "cluster", Version: "1.0.0"}); err == nil {
+		t.Errorf("expected error creating RDE type without schema")
+	}
+	_, err := client.CreateDefinedEntityType(&types.DefinedEntityType{Name: "cluster", Vendor: "acme",
+		Namespace: "cluster", Version: "1.0.0", Schema: map[string]interface{}{"type": "object"}})
+	if err != nil {
+		t.Fatalf("error creating RDE type: %s", err)
+	}
+	entityType, err := client.GetDefinedEntityType("acme", "cluster", "1.0.0")
+	if err != nil {
+		t.Fatalf("error retrieving RDE type: %s", err)
+	}
+	gets := server.RequestsTo(http.MethodGet, typesPath)
+	query, _ := url.ParseQuery(gets[0].RawQuery)
+	if entityType.DefinedEntityType.ID != typeId || query.Get("filter") != "vendor==acme;nss==cluster;version==1.0.0" ||
+		gets[0].Header.Get("Accept") != "application/json;version=35.0" {
+		t.Errorf("unexpected RDE type %+v retrieved with %#v", entityType.DefinedEntityType, gets[0])
+	}
+
+	if _, err = entityType.CreateDefinedEntity(&types.DefinedEntity{Name: "cluster1"}); err == nil {
+		t.Errorf("expected error creating RDE without content")
+	}
+	entity, err := entityType.CreateDefinedEntity(&types.DefinedEntity{Name: "cluster1",
+		Entity: map[string]interface{}{"size": 3}})
+	if err != nil {
+		t.Fatalf("error creating RDE: %s", err)
+	}
+	if entity.DefinedEntity.ID != entityId || entity.DefinedEntity.State != types.RdeStatePreCreated {
+		t.Errorf("unexpected RDE created: %+v", entity.DefinedEntity)
+	}
+	if err = entity.Resolve(); err != nil {
+		t.Fatalf("error resolving RDE: %s", err)
+	}
+	if entity.DefinedEntity.State != types.RdeStateResolved {
+		t.Errorf("unexpected state of resolved RDE: %s", entity.DefinedEntity.State)
+	}
+	if err = entity.Delete(); err != nil {
+		t.Fatalf("error deleting RDE: %s", err)
+	}
+
+	server.HandleJSON(http.MethodGet, typesPath, http.StatusOK, `{"resultTotal":0,"pageCount":0,"page":1,"pageSize":128,"values":[]}`)
+	_, err = client.GetDefinedEntityType("acme", "cluster", "2.0.0")
+	if !errors.Is(err, ErrorEntityNotFound) {
+		t.Errorf("expected entity not found error, got %v", err)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"reflect"
 	"regexp"
 	"strings"
 	"time"
@@ -378,14 +379,17 @@ func (eGW *EdgeGateway) Refresh() error {
 
 	url := eGW.EdgeGateway.HREF
 
-	// Empty struct before a new unmarshal, otherwise we end up with duplicate
-	// elements in slices.
-	eGW.EdgeGateway = &types.EdgeGateway{}
+	// Unmarshal into an empty struct, otherwise we end up with duplicate elements in
+	// slices, and keep the current one when the request fails.
+	refreshed := &types.EdgeGateway{}
 
 	_, err := eGW.client.ExecuteRequest(url, http.MethodGet,
-		"", "error retrieving Edge Gateway: %s", nil, eGW.EdgeGateway)
-
-	return err
+		"", "error retrieving Edge Gateway: %s", nil, refreshed)
+	if err != nil {
+		return err
+	}
+	eGW.EdgeGateway = refreshed
+	return nil
 }
 
 func (eGW *EdgeGateway) Remove1to1Mapping(internal, external string) (Task, error) {
@@ -624,3 +628,394 @@ func (eGW *EdgeGateway) RemoveIpsecVPN() (Task, error) {
 	}
 	return eGW.AddIpsecVPN(ipsecVPNConfig)
 }
+
+// FirewallPolicy is a declarative view of the firewall service of an edge
+// gateway: global settings plus the ordered list of rules. It is what
+// ExportPolicy returns and what ApplyPolicy expects.
+type FirewallPolicy struct {
+	IsEnabled        bool
+	DefaultAction    string
+	LogDefaultAction bool
+	Rules            []*types.FirewallRule
+}
+
+// FirewallPolicyDiff describes the changes needed to turn the current
+// firewall policy into the desired one.
+type FirewallPolicyDiff struct {
+	SettingsChanged bool                  // IsEnabled, DefaultAction or LogDefaultAction differ
+	Added           []*types.FirewallRule // rules present only in the desired policy
+	Removed         []*types.FirewallRule // rules present only in the current policy
+	Reordered       bool                  // rules kept from the current policy change their relative order
+}
+
+// HasChanges returns true if applying the policy would modify the edge gateway
+func (diff FirewallPolicyDiff) HasChanges() bool {
+	return diff.SettingsChanged || len(diff.Added) > 0 || len(diff.Removed) > 0 || diff.Reordered
+}
+
+// ExportPolicy refreshes the edge gateway and returns its firewall
+// configuration as a FirewallPolicy. Rule IDs are removed, as they are
+// assigned by vCD and are not part of a desired state.
+func (eGW *EdgeGateway) ExportPolicy() (*FirewallPolicy, error) {
+	err := eGW.Refresh()
+	if err != nil {
+		return nil, fmt.Errorf("error refreshing edge gateway: %s", err)
+	}
+	policy := eGW.currentFirewallPolicy()
+	for i, rule := range policy.Rules {
+		policy.Rules[i] = firewallRuleWithoutId(rule)
+	}
+	return policy, nil
+}
+
+// ApplyPolicy compares the firewall configuration of the edge gateway with
+// the desired policy and, only if they differ, reconfigures the firewall
+// service and waits for the task to complete. Rules that are unchanged keep
+// their vCD identifiers. The computed difference is returned in any case,
+// so that callers can report what was (or would have been) changed.
+// This works on NSX-V edge gateways, through the configureServices action.
+func (eGW *EdgeGateway) ApplyPolicy(desired *FirewallPolicy) (FirewallPolicyDiff, error) {
+	if desired == nil {
+		return FirewallPolicyDiff{}, fmt.Errorf("desired firewall policy must not be nil")
+	}
+	err := eGW.Refresh()
+	if err != nil {
+		return FirewallPolicyDiff{}, fmt.Errorf("error refreshing edge gateway: %s", err)
+	}
+
+	current := eGW.currentFirewallPolicy()
+	diff, rules := diffFirewallPolicy(current, desired)
+	if !diff.HasChanges() {
+		util.Logger.Printf("[TRACE] firewall policy of edge gateway %s is up to date", eGW.EdgeGateway.Name)
+		return diff, nil
+	}
+
+	newConfig := &types.EdgeGatewayServiceConfiguration{
+		Xmlns: types.XMLNamespaceVCloud,
+		FirewallService: &types.FirewallService{
+			IsEnabled:        desired.IsEnabled,
+			DefaultAction:    desired.DefaultAction,
+			LogDefaultAction: desired.LogDefaultAction,
+			FirewallRule:     rules,
+		},
+	}
+
+	apiEndpoint, _ := url.ParseRequestURI(eGW.EdgeGateway.HREF)
+	apiEndpoint.Path += "/action/configureServices"
+
+	task, err := eGW.client.ExecuteTaskRequest(apiEndpoint.String(), http.MethodPost,
+		"application/vnd.vmware.admin.edgeGatewayServiceConfiguration+xml", "error reconfiguring Edge Gateway: %s", newConfig)
+	if err != nil {
+		return diff, err
+	}
+	err = task.WaitTaskCompletion()
+	if err != nil {
+		return diff, fmt.Errorf("error applying firewall policy: %s", err)
+	}
+	return diff, eGW.Refresh()
+}
+
+// currentFirewallPolicy returns the firewall policy of the edge gateway as
+// stored in the structure, without refreshing it.
+func (eGW *EdgeGateway) currentFirewallPolicy() *FirewallPolicy {
+	policy := &FirewallPolicy{}
+	if eGW.EdgeGateway.Configuration == nil ||
+		eGW.EdgeGateway.Configuration.EdgeGatewayServiceConfiguration == nil ||
+		eGW.EdgeGateway.Configuration.EdgeGatewayServiceConfiguration.FirewallService == nil {
+		return policy
+	}
+	service := eGW.EdgeGateway.Configuration.EdgeGatewayServiceConfiguration.FirewallService
+	policy.IsEnabled = service.IsEnabled
+	policy.DefaultAction = service.DefaultAction
+	policy.LogDefaultAction = service.LogDefaultAction
+	policy.Rules = append(policy.Rules, service.FirewallRule...)
+	return policy
+}
+
+// diffFirewallPolicy compares two policies. Rules are matched regardless of
+// their ID. Besides the difference, it returns the rule list to be sent to
+// vCD, where rules matching an existing one re-use it (and its ID).
+func diffFirewallPolicy(current, desired *FirewallPolicy) (FirewallPolicyDiff, []*types.FirewallRule) {
+	diff := FirewallPolicyDiff{
+		SettingsChanged: current.IsEnabled != desired.IsEnabled ||
+			current.DefaultAction != desired.DefaultAction ||
+			current.LogDefaultAction != desired.LogDefaultAction,
+	}
+
+	matches, reordered := matchOrderedRules(len(current.Rules), len(desired.Rules), func(currentIndex, desiredIndex int) bool {
+		return firewallRulesEqual(current.Rules[currentIndex], desired.Rules[desiredIndex])
+	})
+	diff.Reordered = reordered
+	used := make([]bool, len(current.Rules))
+	var rules []*types.FirewallRule
+	for desiredIndex, found := range matches {
+		if found < 0 {
+			diff.Added = append(diff.Added, desired.Rules[desiredIndex])
+			rules = append(rules, firewallRuleWithoutId(desired.Rules[desiredIndex]))
+			continue
+		}
+		used[found] = true
+		rules = append(rules, current.Rules[found])
+	}
+	for i, existing := range current.Rules {
+		if !used[i] {
+			diff.Removed = append(diff.Removed, existing)
+		}
+	}
+	return diff, rules
+}
+
+// matchOrderedRules matches each desired rule with the first equal current rule not matched yet. It
+// returns, for each desired rule, the index of its current rule or -1 when there is none, and
+// whether the matched rules change their relative order.
+func matchOrderedRules(currentCount, desiredCount int, equal func(currentIndex, desiredIndex int) bool) ([]int, bool) {
+	used := make([]bool, currentCount)
+	matches := make([]int, desiredCount)
+	reordered := false
+	lastMatched := -1
+	for desiredIndex := range matches {
+		matches[desiredIndex] = -1
+		for currentIndex := 0; currentIndex < currentCount; currentIndex++ {
+			if !used[currentIndex] && equal(currentIndex, desiredIndex) {
+				matches[desiredIndex] = currentIndex
+				break
+			}
+		}
+		found := matches[desiredIndex]
+		if found < 0 {
+			continue
+		}
+		used[found] = true
+		if found < lastMatched {
+			reordered = true
+		}
+		lastMatched = found
+	}
+	return matches, reordered
+}
+
+// firewallRulesEqual compares two rules ignoring their ID
+func firewallRulesEqual(first, second *types.FirewallRule) bool {
+	return reflect.DeepEqual(firewallRuleWithoutId(first), firewallRuleWithoutId(second))
+}
+
+// firewallRuleWithoutId returns a copy of the rule with an empty ID
+func firewallRuleWithoutId(rule *types.FirewallRule) *types.FirewallRule {
+	ruleCopy := *rule
+	ruleCopy.ID = ""
+	return &ruleCopy
+}
+
+// GetNatRules refreshes the edge gateway and returns its SNAT and DNAT rules, in the order
+// in which they are applied
+func (eGW *EdgeGateway) GetNatRules() ([]*types.NatRule, error) {
+	err := eGW.Refresh()
+	if err != nil {
+		return nil, fmt.Errorf("error refreshing edge gateway: %s", err)
+	}
+	var rules []*types.NatRule
+	for _, rule := range eGW.currentNatRules() {
+		if rule.GatewayNatRule != nil {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+// GetNatRuleById refreshes the edge gateway and returns the NAT rule with the given ID
+func (eGW *EdgeGateway) GetNatRuleById(id string) (*types.NatRule, error) {
+	rules, err := eGW.GetNatRules()
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if rule.ID == id {
+			return rule, nil
+		}
+	}
+	return nil, fmt.Errorf("NAT rule %s not found in edge gateway %s", id, eGW.EdgeGateway.Name)
+}
+
+// CreateNatRule adds a SNAT or DNAT rule to the edge gateway, at the given position in the list
+// of NAT rules. A negative position, or one past the end of the list, adds the rule last. When
+// the rule has no interface, the first uplink of the edge gateway is used. Returns the rule as
+// created by vCD, with its ID.
+func (eGW *EdgeGateway) CreateNatRule(rule *types.NatRule, position int) (*types.NatRule, error) {
+	err := eGW.Refresh()
+	if err != nil {
+		return nil, fmt.Errorf("error refreshing edge gateway: %s", err)
+	}
+	newRule, err := eGW.newNatRule(rule)
+	if err != nil {
+		return nil, err
+	}
+	newRule.ID = ""
+
+	rules := eGW.currentNatRules()
+	if position < 0 || position > len(rules) {
+		position = len(rules)
+	}
+	rules = append(rules[:position], append([]*types.NatRule{newRule}, rules[position:]...)...)
+
+	err = eGW.updateNatRules(rules)
+	if err != nil {
+		return nil, err
+	}
+	rules = eGW.currentNatRules()
+	if position >= len(rules) {
+		return nil, fmt.Errorf("NAT rule created at position %d not found in edge gateway %s", position, eGW.EdgeGateway.Name)
+	}
+	return rules[position], nil
+}
+
+// UpdateNatRule replaces the NAT rule having the ID of rule, keeping its position
+func (eGW *EdgeGateway) UpdateNatRule(rule *types.NatRule) (*types.NatRule, error) {
+	if rule == nil || rule.ID == "" {
+		return nil, fmt.Errorf("NAT rule to update must have an ID")
+	}
+	err := eGW.Refresh()
+	if err != nil {
+		return nil, fmt.Errorf("error refreshing edge gateway: %s", err)
+	}
+	newRule, err := eGW.newNatRule(rule)
+	if err != nil {
+		return nil, err
+	}
+	rules := eGW.currentNatRules()
+	position, err := eGW.findNatRule(rules, rule.ID)
+	if err != nil {
+		return nil, err
+	}
+	rules[position] = newRule
+
+	err = eGW.updateNatRules(rules)
+	if err != nil {
+		return nil, err
+	}
+	return eGW.GetNatRuleById(rule.ID)
+}
+
+// MoveNatRule moves the NAT rule with the given ID to a new position in the list of NAT rules.
+// A negative position, or one past the end of the list, moves the rule last.
+func (eGW *EdgeGateway) MoveNatRule(id string, position int) error {
+	err := eGW.Refresh()
+	if err != nil {
+		return fmt.Errorf("error refreshing edge gateway: %s", err)
+	}
+	rules := eGW.currentNatRules()
+	current, err := eGW.findNatRule(rules, id)
+	if err != nil {
+		return err
+	}
+	rule := rules[current]
+	rules = append(rules[:current], rules[current+1:]...)
+	if position < 0 || position > len(rules) {
+		position = len(rules)
+	}
+	if position == current {
+		util.Logger.Printf("[TRACE] NAT rule %s is already at position %d", id, position)
+		return nil
+	}
+	rules = append(rules[:position], append([]*types.NatRule{rule}, rules[position:]...)...)
+	return eGW.updateNatRules(rules)
+}
+
+// DeleteNatRule removes the NAT rule with the given ID
+func (eGW *EdgeGateway) DeleteNatRule(id string) error {
+	err := eGW.Refresh()
+	if err != nil {
+		return fmt.Errorf("error refreshing edge gateway: %s", err)
+	}
+	rules := eGW.currentNatRules()
+	position, err := eGW.findNatRule(rules, id)
+	if err != nil {
+		return err
+	}
+	return eGW.updateNatRules(append(rules[:position], rules[position+1:]...))
+}
+
+// currentNatRules returns a copy of the list of NAT rules stored in the structure, without
+// refreshing it
+func (eGW *EdgeGateway) currentNatRules() []*types.NatRule {
+	if eGW.EdgeGateway.Configuration == nil ||
+		eGW.EdgeGateway.Configuration.EdgeGatewayServiceConfiguration == nil ||
+		eGW.EdgeGateway.Configuration.EdgeGatewayServiceConfiguration.NatService == nil {
+		return nil
+	}
+	return append([]*types.NatRule{}, eGW.EdgeGateway.Configuration.EdgeGatewayServiceConfiguration.NatService.NatRule...)
+}
+
+// findNatRule returns the position of the SNAT or DNAT rule with the given ID in rules
+func (eGW *EdgeGateway) findNatRule(rules []*types.NatRule, id string) (int, error) {
+	for position, rule := range rules {
+		if rule.ID == id && rule.GatewayNatRule != nil {
+			return position, nil
+		}
+	}
+	return -1, fmt.Errorf("NAT rule %s not found in edge gateway %s", id, eGW.EdgeGateway.Name)
+}
+
+// newNatRule validates rule and returns a copy of it, with the first uplink of the edge gateway
+// as interface when it has none
+func (eGW *EdgeGateway) newNatRule(rule *types.NatRule) (*types.NatRule, error) {
+	if rule == nil || rule.GatewayNatRule == nil {
+		return nil, fmt.Errorf("NAT rule must have a gateway NAT rule")
+	}
+	if rule.RuleType != "SNAT" && rule.RuleType != "DNAT" {
+		return nil, fmt.Errorf("NAT rule type '%s' is not one of SNAT, DNAT", rule.RuleType)
+	}
+	gatewayRule := *rule.GatewayNatRule
+	if gatewayRule.Protocol != "" && !isValidProtocol(gatewayRule.Protocol) {
+		return nil, fmt.Errorf("provided protocol is not one of TCP, UDP, TCPUDP, ICMP, ANY")
+	}
+	if strings.ToUpper(gatewayRule.Protocol) == "ICMP" && !isValidIcmpSubType(gatewayRule.IcmpSubType) {
+		return nil, fmt.Errorf("provided icmp sub type is not correct")
+	}
+	if gatewayRule.Interface == nil || gatewayRule.Interface.HREF == "" {
+		var uplink types.Reference
+		if eGW.EdgeGateway.Configuration != nil && eGW.EdgeGateway.Configuration.GatewayInterfaces != nil {
+			uplink = eGW.getFirstUplink()
+		}
+		if uplink.HREF == "" {
+			return nil, fmt.Errorf("edge gateway %s has no uplink for the NAT rule", eGW.EdgeGateway.Name)
+		}
+		gatewayRule.Interface = &types.Reference{HREF: uplink.HREF}
+	}
+	newRule := *rule
+	newRule.GatewayNatRule = &gatewayRule
+	return &newRule, nil
+}
+
+// updateNatRules sends the NAT service of the edge gateway with the given rules, waits for the
+// task to complete and refreshes the edge gateway
+func (eGW *EdgeGateway) updateNatRules(rules []*types.NatRule) error {
+	newNatService := &types.NatService{IsEnabled: true}
+	if eGW.EdgeGateway.Configuration != nil && eGW.EdgeGateway.Configuration.EdgeGatewayServiceConfiguration != nil &&
+		eGW.EdgeGateway.Configuration.EdgeGatewayServiceConfiguration.NatService != nil {
+		natService := eGW.EdgeGateway.Configuration.EdgeGatewayServiceConfiguration.NatService
+		newNatService.IsEnabled = natService.IsEnabled
+		newNatService.NatType = natService.NatType
+		newNatService.Policy = natService.Policy
+		newNatService.ExternalIP = natService.ExternalIP
+	}
+	newNatService.NatRule = rules
+
+	newConfig := &types.EdgeGatewayServiceConfiguration{
+		Xmlns:      types.XMLNamespaceVCloud,
+		NatService: newNatService,
+	}
+
+	apiEndpoint, _ := url.ParseRequestURI(eGW.EdgeGateway.HREF)
+	apiEndpoint.Path += "/action/configureServices"
+
+	task, err := eGW.client.ExecuteTaskRequest(apiEndpoint.String(), http.MethodPost,
+		"application/vnd.vmware.admin.edgeGatewayServiceConfiguration+xml", "error reconfiguring Edge Gateway: %s", newConfig)
+	if err != nil {
+		return err
+	}
+	err = task.WaitTaskCompletion()
+	if err != nil {
+		return fmt.Errorf("error updating NAT rules: %s", err)
+	}
+	return eGW.Refresh()
+}
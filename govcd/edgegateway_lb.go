@@ -0,0 +1,591 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// This file contains the management of the load balancer of the edge gateways with advanced
+// networking, through the NSX-V endpoints. For each entity (application profiles, service
+// monitors, server pools, application rules and virtual servers) the Create and Update methods
+// return the configuration stored by NSX, retrieved again after the change.
+// The entities refer to each other by ID: e.g. a virtual server has the ID of its default pool,
+// and a pool the ID of its service monitor.
+
+// GetLbAppProfiles retrieves all the load balancer application profiles of the edge gateway
+func (eGW *EdgeGateway) GetLbAppProfiles() ([]*types.LbAppProfile, error) {
+	lbConfig, err := eGW.getLbEntities(types.EdgeLbAppProfilePath)
+	if err != nil {
+		return nil, err
+	}
+	return lbConfig.ApplicationProfiles, nil
+}
+
+// GetLbAppProfileById retrieves the load balancer application profile with the given ID
+func (eGW *EdgeGateway) GetLbAppProfileById(id string) (*types.LbAppProfile, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty load balancer application profile ID")
+	}
+	appProfile := &types.LbAppProfile{}
+	err := eGW.getLbEntity(types.EdgeLbAppProfilePath, id, appProfile)
+	if err != nil {
+		return nil, err
+	}
+	return appProfile, nil
+}
+
+// GetLbAppProfileByName retrieves the load balancer application profile with the given name
+func (eGW *EdgeGateway) GetLbAppProfileByName(name string) (*types.LbAppProfile, error) {
+	appProfiles, err := eGW.GetLbAppProfiles()
+	if err != nil {
+		return nil, err
+	}
+	for _, appProfile := range appProfiles {
+		if appProfile.Name == name {
+			return appProfile, nil
+		}
+	}
+	return nil, fmt.Errorf("load balancer application profile '%s' not found in edge gateway %s", name, eGW.EdgeGateway.Name)
+}
+
+// CreateLbAppProfile creates a load balancer application profile and returns it as stored by NSX
+func (eGW *EdgeGateway) CreateLbAppProfile(appProfile *types.LbAppProfile) (*types.LbAppProfile, error) {
+	err := validateLbAppProfile(appProfile)
+	if err != nil {
+		return nil, err
+	}
+	id, err := eGW.createLbEntity(types.EdgeLbAppProfilePath, appProfile)
+	if err != nil {
+		return nil, wrapErrorf(err, "error creating load balancer application profile %s: %s", appProfile.Name, err)
+	}
+	return eGW.GetLbAppProfileById(id)
+}
+
+// UpdateLbAppProfile updates the load balancer application profile with the same ID, or with the same
+// name when the ID is empty, and returns it as stored by NSX
+func (eGW *EdgeGateway) UpdateLbAppProfile(appProfile *types.LbAppProfile) (*types.LbAppProfile, error) {
+	err := validateLbAppProfile(appProfile)
+	if err != nil {
+		return nil, err
+	}
+	id := appProfile.ID
+	if id == "" {
+		existing, err := eGW.GetLbAppProfileByName(appProfile.Name)
+		if err != nil {
+			return nil, err
+		}
+		id = existing.ID
+	}
+	payload := *appProfile
+	payload.ID = id
+	err = eGW.updateLbEntity(types.EdgeLbAppProfilePath, id, &payload)
+	if err != nil {
+		return nil, wrapErrorf(err, "error updating load balancer application profile %s: %s", appProfile.Name, err)
+	}
+	return eGW.GetLbAppProfileById(id)
+}
+
+// DeleteLbAppProfileById removes the load balancer application profile with the given ID
+func (eGW *EdgeGateway) DeleteLbAppProfileById(id string) error {
+	if id == "" {
+		return fmt.Errorf("empty load balancer application profile ID")
+	}
+	err := eGW.deleteLbEntity(types.EdgeLbAppProfilePath, id)
+	if err != nil {
+		return wrapErrorf(err, "error deleting load balancer application profile %s: %s", id, err)
+	}
+	return nil
+}
+
+// DeleteLbAppProfileByName removes the load balancer application profile with the given name
+func (eGW *EdgeGateway) DeleteLbAppProfileByName(name string) error {
+	appProfile, err := eGW.GetLbAppProfileByName(name)
+	if err != nil {
+		return err
+	}
+	return eGW.DeleteLbAppProfileById(appProfile.ID)
+}
+
+// validateLbAppProfile checks the fields needed to create or update a load balancer application profile
+func validateLbAppProfile(appProfile *types.LbAppProfile) error {
+	if appProfile == nil || appProfile.Name == "" {
+		return fmt.Errorf("load balancer application profile name is required")
+	}
+	return nil
+}
+
+// GetLbServiceMonitors retrieves all the load balancer service monitors of the edge gateway
+func (eGW *EdgeGateway) GetLbServiceMonitors() ([]*types.LbServiceMonitor, error) {
+	lbConfig, err := eGW.getLbEntities(types.EdgeLbServiceMonitorPath)
+	if err != nil {
+		return nil, err
+	}
+	return lbConfig.Monitors, nil
+}
+
+// GetLbServiceMonitorById retrieves the load balancer service monitor with the given ID
+func (eGW *EdgeGateway) GetLbServiceMonitorById(id string) (*types.LbServiceMonitor, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty load balancer service monitor ID")
+	}
+	serviceMonitor := &types.LbServiceMonitor{}
+	err := eGW.getLbEntity(types.EdgeLbServiceMonitorPath, id, serviceMonitor)
+	if err != nil {
+		return nil, err
+	}
+	return serviceMonitor, nil
+}
+
+// GetLbServiceMonitorByName retrieves the load balancer service monitor with the given name
+func (eGW *EdgeGateway) GetLbServiceMonitorByName(name string) (*types.LbServiceMonitor, error) {
+	serviceMonitors, err := eGW.GetLbServiceMonitors()
+	if err != nil {
+		return nil, err
+	}
+	for _, serviceMonitor := range serviceMonitors {
+		if serviceMonitor.Name == name {
+			return serviceMonitor, nil
+		}
+	}
+	return nil, fmt.Errorf("load balancer service monitor '%s' not found in edge gateway %s", name, eGW.EdgeGateway.Name)
+}
+
+// CreateLbServiceMonitor creates a load balancer service monitor and returns it as stored by NSX
+func (eGW *EdgeGateway) CreateLbServiceMonitor(serviceMonitor *types.LbServiceMonitor) (*types.LbServiceMonitor, error) {
+	err := validateLbServiceMonitor(serviceMonitor)
+	if err != nil {
+		return nil, err
+	}
+	id, err := eGW.createLbEntity(types.EdgeLbServiceMonitorPath, serviceMonitor)
+	if err != nil {
+		return nil, wrapErrorf(err, "error creating load balancer service monitor %s: %s", serviceMonitor.Name, err)
+	}
+	return eGW.GetLbServiceMonitorById(id)
+}
+
+// UpdateLbServiceMonitor updates the load balancer service monitor with the same ID, or with the same
+// name when the ID is empty, and returns it as stored by NSX
+func (eGW *EdgeGateway) UpdateLbServiceMonitor(serviceMonitor *types.LbServiceMonitor) (*types.LbServiceMonitor, error) {
+	err := validateLbServiceMonitor(serviceMonitor)
+	if err != nil {
+		return nil, err
+	}
+	id := serviceMonitor.ID
+	if id == "" {
+		existing, err := eGW.GetLbServiceMonitorByName(serviceMonitor.Name)
+		if err != nil {
+			return nil, err
+		}
+		id = existing.ID
+	}
+	payload := *serviceMonitor
+	payload.ID = id
+	err = eGW.updateLbEntity(types.EdgeLbServiceMonitorPath, id, &payload)
+	if err != nil {
+		return nil, wrapErrorf(err, "error updating load balancer service monitor %s: %s", serviceMonitor.Name, err)
+	}
+	return eGW.GetLbServiceMonitorById(id)
+}
+
+// DeleteLbServiceMonitorById removes the load balancer service monitor with the given ID
+func (eGW *EdgeGateway) DeleteLbServiceMonitorById(id string) error {
+	if id == "" {
+		return fmt.Errorf("empty load balancer service monitor ID")
+	}
+	err := eGW.deleteLbEntity(types.EdgeLbServiceMonitorPath, id)
+	if err != nil {
+		return wrapErrorf(err, "error deleting load balancer service monitor %s: %s", id, err)
+	}
+	return nil
+}
+
+// DeleteLbServiceMonitorByName removes the load balancer service monitor with the given name
+func (eGW *EdgeGateway) DeleteLbServiceMonitorByName(name string) error {
+	serviceMonitor, err := eGW.GetLbServiceMonitorByName(name)
+	if err != nil {
+		return err
+	}
+	return eGW.DeleteLbServiceMonitorById(serviceMonitor.ID)
+}
+
+// validateLbServiceMonitor checks the fields needed to create or update a load balancer service monitor
+func validateLbServiceMonitor(serviceMonitor *types.LbServiceMonitor) error {
+	if serviceMonitor == nil || serviceMonitor.Name == "" {
+		return fmt.Errorf("load balancer service monitor name is required")
+	}
+	if serviceMonitor.Type == "" {
+		return fmt.Errorf("service monitor type is required")
+	}
+	return nil
+}
+
+// GetLbServerPools retrieves all the load balancer server pools of the edge gateway
+func (eGW *EdgeGateway) GetLbServerPools() ([]*types.LbPool, error) {
+	lbConfig, err := eGW.getLbEntities(types.EdgeLbServerPoolPath)
+	if err != nil {
+		return nil, err
+	}
+	return lbConfig.Pools, nil
+}
+
+// GetLbServerPoolById retrieves the load balancer server pool with the given ID
+func (eGW *EdgeGateway) GetLbServerPoolById(id string) (*types.LbPool, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty load balancer server pool ID")
+	}
+	serverPool := &types.LbPool{}
+	err := eGW.getLbEntity(types.EdgeLbServerPoolPath, id, serverPool)
+	if err != nil {
+		return nil, err
+	}
+	return serverPool, nil
+}
+
+// GetLbServerPoolByName retrieves the load balancer server pool with the given name
+func (eGW *EdgeGateway) GetLbServerPoolByName(name string) (*types.LbPool, error) {
+	serverPools, err := eGW.GetLbServerPools()
+	if err != nil {
+		return nil, err
+	}
+	for _, serverPool := range serverPools {
+		if serverPool.Name == name {
+			return serverPool, nil
+		}
+	}
+	return nil, fmt.Errorf("load balancer server pool '%s' not found in edge gateway %s", name, eGW.EdgeGateway.Name)
+}
+
+// CreateLbServerPool creates a load balancer server pool and returns it as stored by NSX
+func (eGW *EdgeGateway) CreateLbServerPool(serverPool *types.LbPool) (*types.LbPool, error) {
+	err := validateLbServerPool(serverPool)
+	if err != nil {
+		return nil, err
+	}
+	id, err := eGW.createLbEntity(types.EdgeLbServerPoolPath, serverPool)
+	if err != nil {
+		return nil, wrapErrorf(err, "error creating load balancer server pool %s: %s", serverPool.Name, err)
+	}
+	return eGW.GetLbServerPoolById(id)
+}
+
+// UpdateLbServerPool updates the load balancer server pool with the same ID, or with the same
+// name when the ID is empty, and returns it as stored by NSX
+func (eGW *EdgeGateway) UpdateLbServerPool(serverPool *types.LbPool) (*types.LbPool, error) {
+	err := validateLbServerPool(serverPool)
+	if err != nil {
+		return nil, err
+	}
+	id := serverPool.ID
+	if id == "" {
+		existing, err := eGW.GetLbServerPoolByName(serverPool.Name)
+		if err != nil {
+			return nil, err
+		}
+		id = existing.ID
+	}
+	payload := *serverPool
+	payload.ID = id
+	err = eGW.updateLbEntity(types.EdgeLbServerPoolPath, id, &payload)
+	if err != nil {
+		return nil, wrapErrorf(err, "error updating load balancer server pool %s: %s", serverPool.Name, err)
+	}
+	return eGW.GetLbServerPoolById(id)
+}
+
+// DeleteLbServerPoolById removes the load balancer server pool with the given ID
+func (eGW *EdgeGateway) DeleteLbServerPoolById(id string) error {
+	if id == "" {
+		return fmt.Errorf("empty load balancer server pool ID")
+	}
+	err := eGW.deleteLbEntity(types.EdgeLbServerPoolPath, id)
+	if err != nil {
+		return wrapErrorf(err, "error deleting load balancer server pool %s: %s", id, err)
+	}
+	return nil
+}
+
+// DeleteLbServerPoolByName removes the load balancer server pool with the given name
+func (eGW *EdgeGateway) DeleteLbServerPoolByName(name string) error {
+	serverPool, err := eGW.GetLbServerPoolByName(name)
+	if err != nil {
+		return err
+	}
+	return eGW.DeleteLbServerPoolById(serverPool.ID)
+}
+
+// validateLbServerPool checks the fields needed to create or update a load balancer server pool
+func validateLbServerPool(serverPool *types.LbPool) error {
+	if serverPool == nil || serverPool.Name == "" {
+		return fmt.Errorf("load balancer server pool name is required")
+	}
+	if serverPool.Algorithm == "" {
+		return fmt.Errorf("server pool algorithm is required")
+	}
+	return nil
+}
+
+// GetLbAppRules retrieves all the load balancer application rules of the edge gateway
+func (eGW *EdgeGateway) GetLbAppRules() ([]*types.LbAppRule, error) {
+	lbConfig, err := eGW.getLbEntities(types.EdgeLbAppRulePath)
+	if err != nil {
+		return nil, err
+	}
+	return lbConfig.ApplicationRules, nil
+}
+
+// GetLbAppRuleById retrieves the load balancer application rule with the given ID
+func (eGW *EdgeGateway) GetLbAppRuleById(id string) (*types.LbAppRule, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty load balancer application rule ID")
+	}
+	appRule := &types.LbAppRule{}
+	err := eGW.getLbEntity(types.EdgeLbAppRulePath, id, appRule)
+	if err != nil {
+		return nil, err
+	}
+	return appRule, nil
+}
+
+// GetLbAppRuleByName retrieves the load balancer application rule with the given name
+func (eGW *EdgeGateway) GetLbAppRuleByName(name string) (*types.LbAppRule, error) {
+	appRules, err := eGW.GetLbAppRules()
+	if err != nil {
+		return nil, err
+	}
+	for _, appRule := range appRules {
+		if appRule.Name == name {
+			return appRule, nil
+		}
+	}
+	return nil, fmt.Errorf("load balancer application rule '%s' not found in edge gateway %s", name, eGW.EdgeGateway.Name)
+}
+
+// CreateLbAppRule creates a load balancer application rule and returns it as stored by NSX
+func (eGW *EdgeGateway) CreateLbAppRule(appRule *types.LbAppRule) (*types.LbAppRule, error) {
+	err := validateLbAppRule(appRule)
+	if err != nil {
+		return nil, err
+	}
+	id, err := eGW.createLbEntity(types.EdgeLbAppRulePath, appRule)
+	if err != nil {
+		return nil, wrapErrorf(err, "error creating load balancer application rule %s: %s", appRule.Name, err)
+	}
+	return eGW.GetLbAppRuleById(id)
+}
+
+// UpdateLbAppRule updates the load balancer application rule with the same ID, or with the same
+// name when the ID is empty, and returns it as stored by NSX
+func (eGW *EdgeGateway) UpdateLbAppRule(appRule *types.LbAppRule) (*types.LbAppRule, error) {
+	err := validateLbAppRule(appRule)
+	if err != nil {
+		return nil, err
+	}
+	id := appRule.ID
+	if id == "" {
+		existing, err := eGW.GetLbAppRuleByName(appRule.Name)
+		if err != nil {
+			return nil, err
+		}
+		id = existing.ID
+	}
+	payload := *appRule
+	payload.ID = id
+	err = eGW.updateLbEntity(types.EdgeLbAppRulePath, id, &payload)
+	if err != nil {
+		return nil, wrapErrorf(err, "error updating load balancer application rule %s: %s", appRule.Name, err)
+	}
+	return eGW.GetLbAppRuleById(id)
+}
+
+// DeleteLbAppRuleById removes the load balancer application rule with the given ID
+func (eGW *EdgeGateway) DeleteLbAppRuleById(id string) error {
+	if id == "" {
+		return fmt.Errorf("empty load balancer application rule ID")
+	}
+	err := eGW.deleteLbEntity(types.EdgeLbAppRulePath, id)
+	if err != nil {
+		return wrapErrorf(err, "error deleting load balancer application rule %s: %s", id, err)
+	}
+	return nil
+}
+
+// DeleteLbAppRuleByName removes the load balancer application rule with the given name
+func (eGW *EdgeGateway) DeleteLbAppRuleByName(name string) error {
+	appRule, err := eGW.GetLbAppRuleByName(name)
+	if err != nil {
+		return err
+	}
+	return eGW.DeleteLbAppRuleById(appRule.ID)
+}
+
+// validateLbAppRule checks the fields needed to create or update a load balancer application rule
+func validateLbAppRule(appRule *types.LbAppRule) error {
+	if appRule == nil || appRule.Name == "" {
+		return fmt.Errorf("load balancer application rule name is required")
+	}
+	if appRule.Script == "" {
+		return fmt.Errorf("application rule script is required")
+	}
+	return nil
+}
+
+// GetLbVirtualServers retrieves all the load balancer virtual servers of the edge gateway
+func (eGW *EdgeGateway) GetLbVirtualServers() ([]*types.LbVirtualServer, error) {
+	lbConfig, err := eGW.getLbEntities(types.EdgeLbVirtualServerPath)
+	if err != nil {
+		return nil, err
+	}
+	return lbConfig.VirtualServers, nil
+}
+
+// GetLbVirtualServerById retrieves the load balancer virtual server with the given ID
+func (eGW *EdgeGateway) GetLbVirtualServerById(id string) (*types.LbVirtualServer, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty load balancer virtual server ID")
+	}
+	virtualServer := &types.LbVirtualServer{}
+	err := eGW.getLbEntity(types.EdgeLbVirtualServerPath, id, virtualServer)
+	if err != nil {
+		return nil, err
+	}
+	return virtualServer, nil
+}
+
+// GetLbVirtualServerByName retrieves the load balancer virtual server with the given name
+func (eGW *EdgeGateway) GetLbVirtualServerByName(name string) (*types.LbVirtualServer, error) {
+	virtualServers, err := eGW.GetLbVirtualServers()
+	if err != nil {
+		return nil, err
+	}
+	for _, virtualServer := range virtualServers {
+		if virtualServer.Name == name {
+			return virtualServer, nil
+		}
+	}
+	return nil, fmt.Errorf("load balancer virtual server '%s' not found in edge gateway %s", name, eGW.EdgeGateway.Name)
+}
+
+// CreateLbVirtualServer creates a load balancer virtual server and returns it as stored by NSX
+func (eGW *EdgeGateway) CreateLbVirtualServer(virtualServer *types.LbVirtualServer) (*types.LbVirtualServer, error) {
+	err := validateLbVirtualServer(virtualServer)
+	if err != nil {
+		return nil, err
+	}
+	id, err := eGW.createLbEntity(types.EdgeLbVirtualServerPath, virtualServer)
+	if err != nil {
+		return nil, wrapErrorf(err, "error creating load balancer virtual server %s: %s", virtualServer.Name, err)
+	}
+	return eGW.GetLbVirtualServerById(id)
+}
+
+// UpdateLbVirtualServer updates the load balancer virtual server with the same ID, or with the same
+// name when the ID is empty, and returns it as stored by NSX
+func (eGW *EdgeGateway) UpdateLbVirtualServer(virtualServer *types.LbVirtualServer) (*types.LbVirtualServer, error) {
+	err := validateLbVirtualServer(virtualServer)
+	if err != nil {
+		return nil, err
+	}
+	id := virtualServer.ID
+	if id == "" {
+		existing, err := eGW.GetLbVirtualServerByName(virtualServer.Name)
+		if err != nil {
+			return nil, err
+		}
+		id = existing.ID
+	}
+	payload := *virtualServer
+	payload.ID = id
+	err = eGW.updateLbEntity(types.EdgeLbVirtualServerPath, id, &payload)
+	if err != nil {
+		return nil, wrapErrorf(err, "error updating load balancer virtual server %s: %s", virtualServer.Name, err)
+	}
+	return eGW.GetLbVirtualServerById(id)
+}
+
+// DeleteLbVirtualServerById removes the load balancer virtual server with the given ID
+func (eGW *EdgeGateway) DeleteLbVirtualServerById(id string) error {
+	if id == "" {
+		return fmt.Errorf("empty load balancer virtual server ID")
+	}
+	err := eGW.deleteLbEntity(types.EdgeLbVirtualServerPath, id)
+	if err != nil {
+		return wrapErrorf(err, "error deleting load balancer virtual server %s: %s", id, err)
+	}
+	return nil
+}
+
+// DeleteLbVirtualServerByName removes the load balancer virtual server with the given name
+func (eGW *EdgeGateway) DeleteLbVirtualServerByName(name string) error {
+	virtualServer, err := eGW.GetLbVirtualServerByName(name)
+	if err != nil {
+		return err
+	}
+	return eGW.DeleteLbVirtualServerById(virtualServer.ID)
+}
+
+// validateLbVirtualServer checks the fields needed to create or update a load balancer virtual server
+func validateLbVirtualServer(virtualServer *types.LbVirtualServer) error {
+	if virtualServer == nil || virtualServer.Name == "" {
+		return fmt.Errorf("load balancer virtual server name is required")
+	}
+	if virtualServer.IpAddress == "" || virtualServer.Protocol == "" || virtualServer.Port == 0 {
+		return fmt.Errorf("virtual server IP address, protocol and port are required")
+	}
+	return nil
+}
+
+// getLbEntities retrieves the list of load balancer entities found at endpointPath
+func (eGW *EdgeGateway) getLbEntities(endpointPath string) (*types.LbConfig, error) {
+	href, err := eGW.buildProxiedEdgeEndpointURL(endpointPath)
+	if err != nil {
+		return nil, err
+	}
+	lbConfig := &types.LbConfig{}
+	err = eGW.client.nsxvGet(href, lbConfig)
+	if err != nil {
+		return nil, err
+	}
+	return lbConfig, nil
+}
+
+// getLbEntity retrieves the load balancer entity with the given ID found at endpointPath into out
+func (eGW *EdgeGateway) getLbEntity(endpointPath, id string, out interface{}) error {
+	href, err := eGW.buildProxiedEdgeEndpointURL(endpointPath + id)
+	if err != nil {
+		return err
+	}
+	return eGW.client.nsxvGet(href, out)
+}
+
+// createLbEntity creates a load balancer entity at endpointPath and returns its ID
+func (eGW *EdgeGateway) createLbEntity(endpointPath string, payload interface{}) (string, error) {
+	href, err := eGW.buildProxiedEdgeEndpointURL(endpointPath)
+	if err != nil {
+		return "", err
+	}
+	return eGW.client.nsxvCreate(href, payload)
+}
+
+// updateLbEntity replaces the load balancer entity with the given ID found at endpointPath
+func (eGW *EdgeGateway) updateLbEntity(endpointPath, id string, payload interface{}) error {
+	href, err := eGW.buildProxiedEdgeEndpointURL(endpointPath + id)
+	if err != nil {
+		return err
+	}
+	return eGW.client.nsxvSend(href, http.MethodPut, payload)
+}
+
+// deleteLbEntity removes the load balancer entity with the given ID found at endpointPath
+func (eGW *EdgeGateway) deleteLbEntity(endpointPath, id string) error {
+	href, err := eGW.buildProxiedEdgeEndpointURL(endpointPath + id)
+	if err != nil {
+		return err
+	}
+	return eGW.client.nsxvSend(href, http.MethodDelete, nil)
+}
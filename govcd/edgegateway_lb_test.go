@@ -0,0 +1,94 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+const lbEdgeUuid = "22222222-2222-2222-2222-222222222222"
+
+// Checks the requests sent to the NSX-V endpoints to manage the load balancer server pools, and
+// the errors returned by NSX
+func TestEdgeGateway_LbServerPools(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	poolsPath := "/network/edges/" + lbEdgeUuid + "/loadbalancer/config/pools/"
+	poolXml := `<pool><poolId>pool-1</poolId><name>web</name><algorithm>round-robin</algorithm><transparent>false</transparent>
+<member><memberId>member-1</memberId><name>web1</name><ipAddress>10.0.0.11</ipAddress><port>8080</port></member></pool>`
+	server.Handle(http.MethodPost, poolsPath, vcdtest.Response{Status: http.StatusCreated,
+		Header: map[string]string{"Location": poolsPath + "pool-1"}})
+	server.HandleXML(http.MethodGet, poolsPath, http.StatusOK, "<loadBalancer>"+poolXml+"</loadBalancer>")
+	server.HandleXML(http.MethodGet, poolsPath+"pool-1", http.StatusOK, poolXml)
+	server.HandleXML(http.MethodPut, poolsPath+"pool-1", http.StatusNoContent, "")
+	server.HandleXML(http.MethodDelete, poolsPath+"pool-1", http.StatusNoContent, "")
+	server.HandleXML(http.MethodPost, "/network/edges/"+lbEdgeUuid+"/loadbalancer/config/virtualservers/", http.StatusBadRequest,
+		`<error><details>Invalid IP address 10.0.0</details><errorCode>14523</errorCode><moduleName>vShield Edge</moduleName></error>`)
+
+	vcdClient := newMockClient(t, server)
+	edge := NewEdgeGateway(&vcdClient.Client)
+	edge.EdgeGateway.Name = "edge"
+	edge.EdgeGateway.ID = "urn:vcloud:gateway:" + lbEdgeUuid
+
+	_, err := edge.GetLbServerPools()
+	if err == nil {
+		t.Errorf("expected error for an edge gateway without advanced networking")
+	}
+	edge.EdgeGateway.Configuration = &types.GatewayConfiguration{AdvancedNetworkingEnabled: true}
+
+	pool, err := edge.CreateLbServerPool(&types.LbPool{Name: "web", Algorithm: "round-robin",
+		Members: []*types.LbPoolMember{{Name: "web1", IpAddress: "10.0.0.11", Port: 8080}}})
+	if err != nil {
+		t.Fatalf("error creating server pool: %s", err)
+	}
+	if pool.ID != "pool-1" || len(pool.Members) != 1 || pool.Members[0].ID != "member-1" {
+		t.Errorf("unexpected server pool: %+v", pool)
+	}
+	created := server.RequestsTo(http.MethodPost, poolsPath)
+	if len(created) != 1 || !strings.Contains(created[0].Body, "<ipAddress>10.0.0.11</ipAddress>") ||
+		strings.Contains(created[0].Body, "<poolId>") || created[0].Header.Get("Content-Type") != "application/xml" {
+		t.Errorf("unexpected creation request: %+v", created)
+	}
+
+	pool.Algorithm = "leastconn"
+	pool.ID = ""
+	_, err = edge.UpdateLbServerPool(pool)
+	if err != nil {
+		t.Fatalf("error updating server pool: %s", err)
+	}
+	updated := server.RequestsTo(http.MethodPut, poolsPath+"pool-1")
+	if len(updated) != 1 || !strings.Contains(updated[0].Body, "<algorithm>leastconn</algorithm>") ||
+		!strings.Contains(updated[0].Body, "<poolId>pool-1</poolId>") {
+		t.Errorf("unexpected update request: %+v", updated)
+	}
+
+	err = edge.DeleteLbServerPoolByName("web")
+	if err != nil {
+		t.Fatalf("error deleting server pool: %s", err)
+	}
+	if len(server.RequestsTo(http.MethodDelete, poolsPath+"pool-1")) != 1 {
+		t.Errorf("server pool not deleted")
+	}
+	_, err = edge.GetLbServerPoolByName("missing")
+	if err == nil {
+		t.Errorf("expected error for a missing server pool")
+	}
+
+	_, err = edge.CreateLbVirtualServer(&types.LbVirtualServer{Name: "vs", IpAddress: "10.0.0", Protocol: "http", Port: 80})
+	var apiError *APIError
+	if !errors.As(err, &apiError) || apiError.MinorErrorCode != "14523" || apiError.Message != "Invalid IP address 10.0.0" {
+		t.Errorf("unexpected NSX error: %v", err)
+	}
+	_, err = edge.CreateLbVirtualServer(&types.LbVirtualServer{Name: "vs"})
+	if err == nil {
+		t.Errorf("expected error for a virtual server without address")
+	}
+}
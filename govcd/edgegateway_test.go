@@ -5,7 +5,13 @@
 package govcd
 
 import (
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
 	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
 	. "gopkg.in/check.v1"
 )
 
@@ -175,3 +181,221 @@ func (vcd *TestVCD) Test_AddIpsecVPN(check *C) {
 	check.Assert(newConfTunnel, IsNil)
 	check.Assert(newConfEndpoint, IsNil)
 }
+
+func (vcd *TestVCD) Test_FirewallPolicy(check *C) {
+	if vcd.config.VCD.EdgeGateway == "" {
+		check.Skip("Skipping test because no edge gateway given")
+	}
+	edge, err := vcd.vdc.FindEdgeGateway(vcd.config.VCD.EdgeGateway)
+	check.Assert(err, IsNil)
+
+	original, err := edge.ExportPolicy()
+	check.Assert(err, IsNil)
+
+	// Applying the exported policy must not change anything
+	diff, err := edge.ApplyPolicy(original)
+	check.Assert(err, IsNil)
+	check.Assert(diff.HasChanges(), Equals, false)
+
+	desired := *original
+	desired.Rules = append([]*types.FirewallRule{}, original.Rules...)
+	desired.Rules = append(desired.Rules, &types.FirewallRule{
+		Description:          "Test_FirewallPolicy",
+		IsEnabled:            true,
+		Policy:               "allow",
+		Protocols:            &types.FirewallRuleProtocols{TCP: true},
+		DestinationPortRange: "8443",
+		DestinationIP:        "Any",
+		SourcePortRange:      "Any",
+		SourceIP:             "Any",
+	})
+	diff, err = edge.ApplyPolicy(&desired)
+	check.Assert(err, IsNil)
+	check.Assert(len(diff.Added), Equals, 1)
+	check.Assert(len(diff.Removed), Equals, 0)
+
+	// Restore the original policy
+	diff, err = edge.ApplyPolicy(original)
+	check.Assert(err, IsNil)
+	check.Assert(len(diff.Added), Equals, 0)
+	check.Assert(len(diff.Removed), Equals, 1)
+
+	exported, err := edge.ExportPolicy()
+	check.Assert(err, IsNil)
+	check.Assert(len(exported.Rules), Equals, len(original.Rules))
+}
+
+func TestDiffFirewallPolicy(t *testing.T) {
+	ruleA := &types.FirewallRule{ID: "1", Description: "A", Policy: "allow", SourceIP: "Any"}
+	ruleB := &types.FirewallRule{ID: "2", Description: "B", Policy: "drop", SourceIP: "10.0.0.1"}
+	ruleC := &types.FirewallRule{Description: "C", Policy: "allow", SourceIP: "10.0.0.2"}
+
+	current := &FirewallPolicy{IsEnabled: true, DefaultAction: "drop", Rules: []*types.FirewallRule{ruleA, ruleB}}
+
+	// Same rules without IDs: no changes
+	desired := &FirewallPolicy{IsEnabled: true, DefaultAction: "drop", Rules: []*types.FirewallRule{
+		firewallRuleWithoutId(ruleA), firewallRuleWithoutId(ruleB)}}
+	diff, rules := diffFirewallPolicy(current, desired)
+	if diff.HasChanges() {
+		t.Errorf("expected no changes, got %#v", diff)
+	}
+	if rules[0].ID != "1" || rules[1].ID != "2" {
+		t.Errorf("expected existing rule IDs to be kept")
+	}
+
+	// Swapped order, one rule added and default action changed
+	desired = &FirewallPolicy{IsEnabled: true, DefaultAction: "allow", Rules: []*types.FirewallRule{ruleB, ruleA, ruleC}}
+	diff, rules = diffFirewallPolicy(current, desired)
+	if !diff.Reordered || !diff.SettingsChanged || len(diff.Added) != 1 || len(diff.Removed) != 0 {
+		t.Errorf("unexpected difference: %#v", diff)
+	}
+	if len(rules) != 3 || rules[2].ID != "" {
+		t.Errorf("unexpected rule list: %#v", rules)
+	}
+
+	// Removal only
+	desired = &FirewallPolicy{IsEnabled: true, DefaultAction: "drop", Rules: []*types.FirewallRule{ruleB}}
+	diff, _ = diffFirewallPolicy(current, desired)
+	if diff.Reordered || len(diff.Removed) != 1 || diff.Removed[0] != ruleA {
+		t.Errorf("unexpected difference: %#v", diff)
+	}
+}
+
+const natEdgeGatewayXml = `<EdgeGateway xmlns="http://www.vmware.com/vcloud/v1.5" name="edge" href="{{server}}/api/admin/edgeGateway/edge-1" type="application/vnd.vmware.admin.edgeGateway+xml">
+  <Configuration>
+    <GatewayBackingConfig>compact</GatewayBackingConfig>
+    <GatewayInterfaces>
+      <GatewayInterface>
+        <Name>external</Name>
+        <Network href="{{server}}/api/admin/network/external-1" name="external"/>
+        <InterfaceType>uplink</InterfaceType>
+      </GatewayInterface>
+    </GatewayInterfaces>
+    <EdgeGatewayServiceConfiguration>
+      <NatService>
+        <IsEnabled>true</IsEnabled>
+        <NatRule>
+          <RuleType>SNAT</RuleType>
+          <IsEnabled>true</IsEnabled>
+          <Id>65537</Id>
+          <GatewayNatRule>
+            <Interface href="{{server}}/api/admin/network/external-1"/>
+            <OriginalIp>10.10.0.0/24</OriginalIp>
+            <TranslatedIp>192.168.1.10</TranslatedIp>
+          </GatewayNatRule>
+        </NatRule>
+        <NatRule>
+          <RuleType>DNAT</RuleType>
+          <IsEnabled>true</IsEnabled>
+          <Id>65538</Id>
+          <GatewayNatRule>
+            <Interface href="{{server}}/api/admin/network/external-1"/>
+            <OriginalIp>192.168.1.10</OriginalIp>
+            <OriginalPort>443</OriginalPort>
+            <TranslatedIp>10.10.0.5</TranslatedIp>
+            <TranslatedPort>8443</TranslatedPort>
+            <Protocol>tcp</Protocol>
+          </GatewayNatRule>
+        </NatRule>
+      </NatService>
+    </EdgeGatewayServiceConfiguration>
+  </Configuration>
+</EdgeGateway>`
+
+// Checks the NAT services sent to create, update, move and delete NAT rules
+func TestEdgeGateway_NatRules(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	edgePath := "/api/admin/edgeGateway/edge-1"
+	server.HandleXML(http.MethodGet, edgePath, http.StatusOK, natEdgeGatewayXml)
+	server.HandleXML(http.MethodPost, edgePath+"/action/configureServices", http.StatusAccepted,
+		`<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="running" href="{{server}}`+vcdtest.MockTaskPath+`"/>`)
+
+	vcdClient := newMockClient(t, server)
+	edge := NewEdgeGateway(&vcdClient.Client)
+	edge.EdgeGateway.HREF = server.URL() + edgePath
+
+	rules, err := edge.GetNatRules()
+	if err != nil {
+		t.Fatalf("error retrieving NAT rules: %s", err)
+	}
+	if len(rules) != 2 || rules[0].ID != "65537" || rules[1].GatewayNatRule.TranslatedPort != "8443" {
+		t.Fatalf("unexpected NAT rules: %+v", rules)
+	}
+
+	// lastNatRules returns the IDs, or the original IPs of the rules without ID, sent in the last update
+	lastNatRules := func() []string {
+		requests := server.RequestsTo(http.MethodPost, edgePath+"/action/configureServices")
+		if len(requests) == 0 {
+			t.Fatalf("no update of the NAT service sent")
+		}
+		var sent []string
+		for _, rule := range regexp.MustCompile(`(?s)<NatRule>.*?</NatRule>`).FindAllString(requests[len(requests)-1].Body, -1) {
+			if id := regexp.MustCompile(`<Id>(.*)</Id>`).FindStringSubmatch(rule); id != nil {
+				sent = append(sent, id[1])
+			} else {
+				sent = append(sent, regexp.MustCompile(`<OriginalIp>(.*)</OriginalIp>`).FindStringSubmatch(rule)[1])
+			}
+		}
+		return sent
+	}
+
+	dnat := &types.NatRule{RuleType: "DNAT", IsEnabled: true, GatewayNatRule: &types.GatewayNatRule{
+		OriginalIP: "192.168.1.11", OriginalPort: "22", TranslatedIP: "10.10.0.6", TranslatedPort: "22", Protocol: "tcp"}}
+	_, err = edge.CreateNatRule(dnat, 1)
+	if err != nil {
+		t.Fatalf("error creating NAT rule: %s", err)
+	}
+	if sent := strings.Join(lastNatRules(), ","); sent != "65537,192.168.1.11,65538" {
+		t.Errorf("unexpected NAT rules sent for creation: %s", sent)
+	}
+	body := server.RequestsTo(http.MethodPost, edgePath+"/action/configureServices")[0].Body
+	if !strings.Contains(body, `<Interface href="`+server.URL()+`/api/admin/network/external-1"`) {
+		t.Errorf("expected the uplink as interface of the new rule:\n%s", body)
+	}
+	if dnat.GatewayNatRule.Interface != nil {
+		t.Errorf("rule of the caller changed: %+v", dnat.GatewayNatRule)
+	}
+
+	update := *rules[1]
+	update.GatewayNatRule = &types.GatewayNatRule{OriginalIP: "192.168.1.10", OriginalPort: "443", TranslatedIP: "10.10.0.7",
+		TranslatedPort: "443", Protocol: "tcp"}
+	_, err = edge.UpdateNatRule(&update)
+	if err != nil {
+		t.Fatalf("error updating NAT rule: %s", err)
+	}
+	if sent := strings.Join(lastNatRules(), ","); sent != "65537,65538" {
+		t.Errorf("unexpected NAT rules sent for update: %s", sent)
+	}
+
+	err = edge.MoveNatRule("65538", 0)
+	if err != nil {
+		t.Fatalf("error moving NAT rule: %s", err)
+	}
+	if sent := strings.Join(lastNatRules(), ","); sent != "65538,65537" {
+		t.Errorf("unexpected NAT rules sent for move: %s", sent)
+	}
+
+	err = edge.DeleteNatRule("65537")
+	if err != nil {
+		t.Fatalf("error deleting NAT rule: %s", err)
+	}
+	if sent := strings.Join(lastNatRules(), ","); sent != "65538" {
+		t.Errorf("unexpected NAT rules sent for deletion: %s", sent)
+	}
+
+	for _, invalid := range []*types.NatRule{
+		{RuleType: "NAT", GatewayNatRule: &types.GatewayNatRule{OriginalIP: "10.0.0.1", TranslatedIP: "10.0.0.2"}},
+		{RuleType: "DNAT"},
+		{RuleType: "DNAT", GatewayNatRule: &types.GatewayNatRule{Protocol: "icmp", IcmpSubType: "invalid"}},
+	} {
+		_, err = edge.CreateNatRule(invalid, -1)
+		if err == nil {
+			t.Errorf("expected error creating NAT rule %+v", invalid)
+		}
+	}
+	err = edge.DeleteNatRule("1")
+	if err == nil {
+		t.Errorf("expected error deleting unknown NAT rule")
+	}
+}
@@ -64,19 +64,7 @@ func (ejectTask *EjectTask) WaitInspectTaskCompletion(isAnswerYes bool, delay ti
 		}
 
 		if question.QuestionId != "" && strings.Contains(question.Question, questionMessage) {
-			var choiceToUse *types.VmQuestionAnswerChoiceType
-			for _, choice := range question.Choices {
-				if isAnswerYes {
-					if strings.Contains(choice.Text, "yes") {
-						choiceToUse = choice
-					}
-				} else {
-					if strings.Contains(choice.Text, "no") {
-						choiceToUse = choice
-					}
-				}
-			}
-
+			choiceToUse := findEjectAnswer(question, isAnswerYes)
 			if choiceToUse != nil {
 				err = ejectTask.vm.AnswerQuestion(question.QuestionId, choiceToUse.Id)
 				if err != nil {
@@ -86,7 +74,20 @@ func (ejectTask *EjectTask) WaitInspectTaskCompletion(isAnswerYes bool, delay ti
 
 		}
 
-		// Sleep for a given period and try again.
-		time.Sleep(delay)
+		// Sleep for a given period and try again, unless the context of the client is done.
+		err = ejectTask.Task.client.sleep(delay)
+		if err != nil {
+			return fmt.Errorf("stopped waiting for task: %s", err)
+		}
+	}
+}
+
+// findEjectAnswer returns the choice answering yes or no to the question about overriding the
+// CD-ROM lock. The texts of the choices are compared regardless of their case, as they are
+// capitalized by vCD ("Yes", "No").
+func findEjectAnswer(question types.VmPendingQuestion, isAnswerYes bool) *types.VmQuestionAnswerChoiceType {
+	if isAnswerYes {
+		return findQuestionChoice(question, "yes")
 	}
+	return findQuestionChoice(question, "no")
 }
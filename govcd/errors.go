@@ -0,0 +1,83 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinel errors matching the errors returned by vCD, to be checked with errors.Is.
+// E.g. errors.Is(err, ErrorEntityNotFound) is true when the entity requested does not exist.
+var (
+	ErrorEntityNotFound = errors.New("[ENF] entity not found")
+	ErrorUnauthorized   = errors.New("unauthorized")
+	ErrorForbidden      = errors.New("access forbidden")
+	ErrorBusyEntity     = errors.New("entity busy")
+)
+
+// APIError is the error returned when vCD rejects a request. It carries the information found in
+// the error body of the response, and can be retrieved with errors.As, even when the error has been
+// wrapped by the ExecuteRequest family of functions.
+type APIError struct {
+	StatusCode              int    // HTTP status code of the response
+	MajorErrorCode          int    // vCD major error code, usually matching the HTTP status code
+	MinorErrorCode          string // vCD minor error code, e.g. ACCESS_TO_RESOURCE_IS_FORBIDDEN or BUSY_ENTITY
+	VendorSpecificErrorCode string
+	Message                 string
+	openApi                 bool // the error was returned by an OpenAPI endpoint
+}
+
+// Error returns the error message, in the same format used before the errors were typed
+func (apiError *APIError) Error() string {
+	if apiError.openApi {
+		return fmt.Sprintf("API Error: %d: %s - %s", apiError.StatusCode, apiError.MinorErrorCode, apiError.Message)
+	}
+	return fmt.Sprintf("API Error: %d: %s", apiError.MajorErrorCode, apiError.Message)
+}
+
+// Is reports whether the error matches one of the sentinel errors of this package
+func (apiError *APIError) Is(target error) bool {
+	switch target {
+	case ErrorEntityNotFound:
+		return apiError.StatusCode == http.StatusNotFound || apiError.MajorErrorCode == http.StatusNotFound
+	case ErrorUnauthorized:
+		return apiError.StatusCode == http.StatusUnauthorized || apiError.MajorErrorCode == http.StatusUnauthorized
+	case ErrorForbidden:
+		return apiError.StatusCode == http.StatusForbidden || apiError.MajorErrorCode == http.StatusForbidden
+	case ErrorBusyEntity:
+		return apiError.MinorErrorCode == "BUSY_ENTITY"
+	}
+	return false
+}
+
+// wrappedError is an error formatted with a message, which keeps the original error available
+// to errors.Is and errors.As
+type wrappedError struct {
+	message string
+	err     error
+}
+
+func (wrapped *wrappedError) Error() string {
+	return wrapped.message
+}
+
+// Unwrap returns the original error
+func (wrapped *wrappedError) Unwrap() error {
+	return wrapped.err
+}
+
+// wrapError formats err with errorMessage, which must have a placeholder for the error, like
+// fmt.Errorf(errorMessage, err), but keeps err available to errors.Is and errors.As
+func wrapError(errorMessage string, err error) error {
+	return &wrappedError{message: fmt.Sprintf(errorMessage, err), err: err}
+}
+
+// wrapErrorf formats a message like fmt.Errorf(format, args...), which usually includes err,
+// and keeps err available to errors.Is and errors.As
+func wrapErrorf(err error, format string, args ...interface{}) error {
+	return &wrappedError{message: fmt.Sprintf(format, args...), err: err}
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// Checks that the errors returned by vCD are typed, and can be matched through the wrapping
+// of the request functions
+func TestClient_APIError(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	server.Handle(http.MethodGet, "/api/vApp/vapp-1", vcdtest.Response{Status: http.StatusNotFound, ContentType: types.MimeError,
+		Body: `<Error xmlns="http://www.vmware.com/vcloud/v1.5" majorErrorCode="404" minorErrorCode="RESOURCE_NOT_FOUND" message="[ abc ] vApp not found"/>`})
+	server.Handle(http.MethodPost, "/api/vApp/vapp-2/action/deploy", vcdtest.Response{Status: http.StatusBadRequest, ContentType: types.MimeError,
+		Body: `<Error xmlns="http://www.vmware.com/vcloud/v1.5" majorErrorCode="400" minorErrorCode="BUSY_ENTITY" message="vApp is busy"/>`})
+	server.HandleJSON(http.MethodGet, "/cloudapi/1.0.0/items/1", http.StatusForbidden,
+		`{"minorErrorCode":"ACCESS_TO_RESOURCE_IS_FORBIDDEN","message":"no access"}`)
+
+	client := &newMockClient(t, server).Client
+
+	_, err := client.ExecuteRequest(server.URL()+"/api/vApp/vapp-1", http.MethodGet, "", "error retrieving vApp: %s", nil, &types.VApp{})
+	if err == nil || err.Error() != "error retrieving vApp: API Error: 404: [ abc ] vApp not found" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !errors.Is(err, ErrorEntityNotFound) || errors.Is(err, ErrorForbidden) {
+		t.Errorf("error not matched as entity not found: %s", err)
+	}
+	var apiError *APIError
+	if !errors.As(err, &apiError) || apiError.StatusCode != http.StatusNotFound || apiError.MinorErrorCode != "RESOURCE_NOT_FOUND" {
+		t.Errorf("unexpected API error: %#v", apiError)
+	}
+
+	_, err = client.ExecuteTaskRequest(server.URL()+"/api/vApp/vapp-2/action/deploy", http.MethodPost, "", "error deploying vApp: %s", nil)
+	if !errors.Is(err, ErrorBusyEntity) || errors.Is(err, ErrorEntityNotFound) {
+		t.Errorf("error not matched as busy entity: %v", err)
+	}
+
+	urlRef, _ := client.OpenApiBuildEndpoint("1.0.0/", "items/1")
+	err = client.OpenApiGetItem("33.0", urlRef, nil, &struct{}{})
+	if !errors.Is(err, ErrorForbidden) || !errors.As(err, &apiError) || apiError.MinorErrorCode != "ACCESS_TO_RESOURCE_IS_FORBIDDEN" {
+		t.Errorf("unexpected OpenAPI error: %v", err)
+	}
+}
@@ -0,0 +1,213 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/util"
+)
+
+// ExtensionService is a service registered to receive, through AMQP, the API requests matching
+// its API filters. Extension services are only available to system administrators.
+type ExtensionService struct {
+	ExtensionService *types.AdminService
+	client           *Client
+}
+
+// NewExtensionService creates a new extension service structure which still needs to have
+// ExtensionService attribute populated
+func NewExtensionService(cli *Client) *ExtensionService {
+	return &ExtensionService{
+		ExtensionService: new(types.AdminService),
+		client:           cli,
+	}
+}
+
+// RegisterExtensionService registers an extension service with its namespace, routing key,
+// exchange and optional API filters. New services are disabled: use SetEnabled to start
+// forwarding requests to them.
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/POST-RegisterService.html
+func RegisterExtensionService(vcdClient *VCDClient, service *types.AdminService) (*ExtensionService, error) {
+	util.Logger.Printf("[TRACE] RegisterExtensionService - registering extension service %#v", service)
+
+	if service == nil || service.Name == "" || service.Namespace == "" {
+		return nil, fmt.Errorf("extension service name and namespace are required")
+	}
+	if service.RoutingKey == "" || service.Exchange == "" {
+		return nil, fmt.Errorf("extension service %s needs a routing key and an exchange", service.Name)
+	}
+
+	registerHREF := vcdClient.Client.VCDHREF
+	registerHREF.Path += "/admin/extension/service"
+
+	registered := NewExtensionService(&vcdClient.Client)
+	_, err := vcdClient.Client.ExecuteRequest(registerHREF.String(), http.MethodPost,
+		types.MimeAdminService, "error registering extension service: %s", service, registered.ExtensionService)
+	if err != nil {
+		return nil, err
+	}
+	return registered, nil
+}
+
+// QueryExtensionServices returns the query records of all the registered extension services
+func QueryExtensionServices(vcdClient *VCDClient) ([]*types.QueryResultAdminServiceRecordType, error) {
+	results, err := vcdClient.Client.QueryAllPages("adminService", nil)
+	if err != nil {
+		return nil, fmt.Errorf("error querying extension services: %s", err)
+	}
+	return results.Results.AdminServiceRecord, nil
+}
+
+// GetExtensionServiceByName retrieves the extension service with the given name
+func GetExtensionServiceByName(vcdClient *VCDClient, name string) (*ExtensionService, error) {
+	results, err := vcdClient.Client.QueryAllPages("adminService", &QueryOptions{
+		Filter: NewQueryFilter().Equal("name", name),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error querying extension services: %s", err)
+	}
+	if len(results.Results.AdminServiceRecord) == 0 {
+		return nil, fmt.Errorf("extension service %s not found", name)
+	}
+	return GetExtensionServiceByHref(vcdClient, results.Results.AdminServiceRecord[0].HREF)
+}
+
+// GetExtensionServiceByHref retrieves the extension service with the given HREF
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/GET-Service.html
+func GetExtensionServiceByHref(vcdClient *VCDClient, href string) (*ExtensionService, error) {
+	service := NewExtensionService(&vcdClient.Client)
+	_, err := vcdClient.Client.ExecuteRequest(href, http.MethodGet,
+		"", "error retrieving extension service: %s", nil, service.ExtensionService)
+	if err != nil {
+		return nil, err
+	}
+	return service, nil
+}
+
+// Refresh retrieves the extension service again
+func (service *ExtensionService) Refresh() error {
+	if service.ExtensionService.HREF == "" {
+		return fmt.Errorf("cannot refresh, Object is empty")
+	}
+	href := service.ExtensionService.HREF
+
+	// Unmarshal into an empty struct, otherwise we end up with duplicate elements in
+	// slices, and keep the current one when the request fails.
+	refreshed := &types.AdminService{}
+
+	_, err := service.client.ExecuteRequest(href, http.MethodGet,
+		"", "error refreshing extension service: %s", nil, refreshed)
+	if err != nil {
+		return err
+	}
+	service.ExtensionService = refreshed
+	return nil
+}
+
+// Update sends the current definition of the extension service to vCD
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/PUT-Service.html
+func (service *ExtensionService) Update() error {
+	util.Logger.Printf("[TRACE] ExtensionService.Update - updating extension service %s", service.ExtensionService.Name)
+
+	if service.ExtensionService.HREF == "" {
+		return fmt.Errorf("cannot update, Object is empty")
+	}
+	// Read-only elements are not sent back. API filters are managed with their own requests.
+	payload := *service.ExtensionService
+	payload.Link = nil
+	payload.Tasks = nil
+	payload.ApiFilters = nil
+
+	updated := &types.AdminService{}
+	_, err := service.client.ExecuteRequest(service.ExtensionService.HREF, http.MethodPut,
+		types.MimeAdminService, "error updating extension service: %s", &payload, updated)
+	if err != nil {
+		return err
+	}
+	service.ExtensionService = updated
+	return nil
+}
+
+// SetEnabled enables or disables the forwarding of requests to the extension service
+func (service *ExtensionService) SetEnabled(enabled bool) error {
+	service.ExtensionService.Enabled = &enabled
+	return service.Update()
+}
+
+// Delete unregisters the extension service, disabling it first when needed
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/DELETE-Service.html
+func (service *ExtensionService) Delete() error {
+	util.Logger.Printf("[TRACE] ExtensionService.Delete - deleting extension service %s", service.ExtensionService.Name)
+
+	if service.ExtensionService.HREF == "" {
+		return fmt.Errorf("cannot delete, Object is empty")
+	}
+	if service.ExtensionService.Enabled != nil && *service.ExtensionService.Enabled {
+		err := service.SetEnabled(false)
+		if err != nil {
+			return fmt.Errorf("error disabling extension service before deletion: %s", err)
+		}
+	}
+	return service.client.ExecuteRequestWithoutResponse(service.ExtensionService.HREF, http.MethodDelete,
+		"", "error deleting extension service: %s", nil)
+}
+
+// AddApiFilter adds an API filter to the extension service: the requests whose URL matches the
+// urlPattern regular expression are forwarded to the service
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/POST-CreateApiFilter.html
+func (service *ExtensionService) AddApiFilter(urlPattern string) (*types.ApiFilter, error) {
+	if urlPattern == "" {
+		return nil, fmt.Errorf("API filter URL pattern is required")
+	}
+	href, err := service.buildHref("/apifilters")
+	if err != nil {
+		return nil, err
+	}
+	apiFilter := &types.ApiFilter{}
+	_, err = service.client.ExecuteRequest(href, http.MethodPost,
+		types.MimeApiFilter, "error adding API filter: %s", &types.ApiFilter{UrlPattern: urlPattern}, apiFilter)
+	if err != nil {
+		return nil, err
+	}
+	return apiFilter, nil
+}
+
+// QueryApiFilters returns the query records of the API filters of the extension service
+func (service *ExtensionService) QueryApiFilters() ([]*types.QueryResultApiFilterRecordType, error) {
+	results, err := service.client.QueryAllPages("adminApiFilter", &QueryOptions{
+		Filter: NewQueryFilter().Equal("service", service.ExtensionService.HREF),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error querying API filters: %s", err)
+	}
+	return results.Results.AdminApiFilterRecord, nil
+}
+
+// DeleteApiFilter removes the API filter with the given HREF from the extension service
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/DELETE-ApiFilter.html
+func (service *ExtensionService) DeleteApiFilter(href string) error {
+	if href == "" {
+		return fmt.Errorf("API filter HREF is required")
+	}
+	return service.client.ExecuteRequestWithoutResponse(href, http.MethodDelete,
+		"", "error deleting API filter: %s", nil)
+}
+
+// buildHref returns the HREF of a sub-resource of the extension service
+func (service *ExtensionService) buildHref(path string) (string, error) {
+	if service.ExtensionService.HREF == "" {
+		return "", fmt.Errorf("extension service HREF is empty")
+	}
+	href, err := url.ParseRequestURI(service.ExtensionService.HREF)
+	if err != nil {
+		return "", fmt.Errorf("error parsing extension service url: %s", err)
+	}
+	href.Path += path
+	return href.String(), nil
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// Registers an extension service, enables it, manages its API filters and unregisters it
+func (vcd *TestVCD) Test_ExtensionService(check *C) {
+	if vcd.skipAdminTests {
+		check.Skip(fmt.Sprintf(TestRequiresSysAdminPrivileges, check.TestName()))
+	}
+
+	service, err := RegisterExtensionService(vcd.client, &types.AdminService{
+		Name:        TestExtensionService,
+		Description: "Test extension service",
+		Namespace:   "govcdtest",
+		RoutingKey:  "govcdtest",
+		Exchange:    "vcdext",
+		ApiFilters: &types.ApiFilters{
+			ApiFilter: []*types.ApiFilter{{UrlPattern: "/api/govcdtest/.*"}},
+		},
+	})
+	check.Assert(err, IsNil)
+	AddToCleanupList(TestExtensionService, "extensionService", "", check.TestName())
+	check.Assert(service.ExtensionService.HREF, Not(Equals), "")
+
+	err = service.SetEnabled(true)
+	check.Assert(err, IsNil)
+	check.Assert(*service.ExtensionService.Enabled, Equals, true)
+
+	apiFilter, err := service.AddApiFilter("/api/govcdtest2/.*")
+	check.Assert(err, IsNil)
+	check.Assert(apiFilter.UrlPattern, Equals, "/api/govcdtest2/.*")
+
+	apiFilters, err := service.QueryApiFilters()
+	check.Assert(err, IsNil)
+	check.Assert(len(apiFilters), Equals, 2)
+
+	err = service.DeleteApiFilter(apiFilter.HREF)
+	check.Assert(err, IsNil)
+	apiFilters, err = service.QueryApiFilters()
+	check.Assert(err, IsNil)
+	check.Assert(len(apiFilters), Equals, 1)
+
+	found, err := GetExtensionServiceByName(vcd.client, TestExtensionService)
+	check.Assert(err, IsNil)
+	check.Assert(found.ExtensionService.HREF, Equals, service.ExtensionService.HREF)
+
+	err = service.Delete()
+	check.Assert(err, IsNil)
+	_, err = GetExtensionServiceByName(vcd.client, TestExtensionService)
+	check.Assert(err, NotNil)
+}
+
+// Checks that the extension service elements are in the extension namespace, except the ones
+// inherited from the vCloud entity type
+func TestAdminServiceMarshal(t *testing.T) {
+	service := &types.AdminService{
+		Name:        "test",
+		Description: "description",
+		Namespace:   "ns",
+		RoutingKey:  "key",
+		Exchange:    "exchange",
+		ApiFilters:  &types.ApiFilters{ApiFilter: []*types.ApiFilter{{UrlPattern: "/api/test/.*"}}},
+	}
+	out, err := xml.Marshal(service)
+	if err != nil {
+		t.Fatalf("error marshalling extension service: %s", err)
+	}
+	payload := string(out)
+	for _, expected := range []string{
+		`<Service xmlns="http://www.vmware.com/vcloud/extension/v1.5" name="test">`,
+		`<Description xmlns="http://www.vmware.com/vcloud/v1.5">description</Description>`,
+		`<Namespace>ns</Namespace>`,
+		`<ApiFilters><ApiFilter xmlns="http://www.vmware.com/vcloud/extension/v1.5"><UrlPattern>/api/test/.*</UrlPattern></ApiFilter></ApiFilters>`,
+	} {
+		if !strings.Contains(payload, expected) {
+			t.Errorf("expected %s in payload %s", expected, payload)
+		}
+	}
+	if strings.Contains(payload, "<Enabled>") {
+		t.Errorf("unexpected Enabled element in payload %s", payload)
+	}
+}
@@ -0,0 +1,230 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/util"
+)
+
+// ExternalNetwork is an external network backed by one or more vSphere port groups.
+// External networks are only available to system administrators.
+type ExternalNetwork struct {
+	ExternalNetwork *types.VMWExternalNetwork
+	client          *Client
+}
+
+// NewExternalNetwork creates a new external network structure which still needs to have
+// ExternalNetwork attribute populated
+func NewExternalNetwork(cli *Client) *ExternalNetwork {
+	return &ExternalNetwork{
+		ExternalNetwork: new(types.VMWExternalNetwork),
+		client:          cli,
+	}
+}
+
+// QueryPortGroups returns the query records of the vSphere port groups with the given name.
+// All port groups are returned when name is empty.
+// The records provide the MoRef and the type needed to reference a port group as external network backing.
+func QueryPortGroups(vcdClient *VCDClient, name string) ([]*types.QueryResultPortgroupRecordType, error) {
+	options := &QueryOptions{}
+	if name != "" {
+		options.Filter = NewQueryFilter().Equal("name", name)
+	}
+	results, err := vcdClient.Client.QueryAllPages("portgroup", options)
+	if err != nil {
+		return nil, fmt.Errorf("error querying port groups: %s", err)
+	}
+	return results.Results.PortgroupRecord, nil
+}
+
+// CreateExternalNetwork creates an external network backed by the vSphere port groups listed in
+// VimPortGroupRefs, with the subnets and IP pools given in the IP scopes of its configuration.
+// Returns the creation task.
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/POST-CreateExternalNetwork.html
+func CreateExternalNetwork(vcdClient *VCDClient, externalNetwork *types.VMWExternalNetwork) (Task, error) {
+	util.Logger.Printf("[TRACE] CreateExternalNetwork - creating external network %#v", externalNetwork)
+
+	err := validateExternalNetwork(externalNetwork)
+	if err != nil {
+		return Task{}, err
+	}
+	if externalNetwork.Configuration.FenceMode == "" {
+		externalNetwork.Configuration.FenceMode = types.FenceModeIsolated
+	}
+
+	createHREF := vcdClient.Client.VCDHREF
+	createHREF.Path += "/admin/extension/externalnets"
+
+	created := NewExternalNetwork(&vcdClient.Client)
+	_, err = vcdClient.Client.ExecuteRequest(createHREF.String(), http.MethodPost,
+		types.MimeExternalNetwork, "error creating external network: %s", externalNetwork, created.ExternalNetwork)
+	if err != nil {
+		return Task{}, err
+	}
+	if created.ExternalNetwork.Tasks == nil || len(created.ExternalNetwork.Tasks.Task) == 0 {
+		return Task{}, fmt.Errorf("no task found after creating external network %s", externalNetwork.Name)
+	}
+	task := NewTask(&vcdClient.Client)
+	task.Task = created.ExternalNetwork.Tasks.Task[0]
+	return *task, nil
+}
+
+// validateExternalNetwork checks that the external network definition has a name, at least one
+// subnet and at least one port group backing
+func validateExternalNetwork(externalNetwork *types.VMWExternalNetwork) error {
+	if externalNetwork == nil || externalNetwork.Name == "" {
+		return fmt.Errorf("external network name is required")
+	}
+	if externalNetwork.Configuration == nil || externalNetwork.Configuration.IPScopes == nil ||
+		len(externalNetwork.Configuration.IPScopes.IPScope) == 0 {
+		return fmt.Errorf("external network %s needs at least one IP scope", externalNetwork.Name)
+	}
+	if externalNetwork.VimPortGroupRefs == nil || len(externalNetwork.VimPortGroupRefs.VimObjectRef) == 0 {
+		return fmt.Errorf("external network %s needs at least one port group backing", externalNetwork.Name)
+	}
+	for _, portGroupRef := range externalNetwork.VimPortGroupRefs.VimObjectRef {
+		if portGroupRef.VimServerRef == nil || portGroupRef.VimServerRef.HREF == "" || portGroupRef.MoRef == "" {
+			return fmt.Errorf("port group backings of external network %s need a vCenter reference and a MoRef",
+				externalNetwork.Name)
+		}
+		if portGroupRef.VimObjectType != types.PortGroupTypeDistributed && portGroupRef.VimObjectType != types.PortGroupTypeStandard {
+			return fmt.Errorf("unsupported port group type '%s' in external network %s", portGroupRef.VimObjectType,
+				externalNetwork.Name)
+		}
+	}
+	return nil
+}
+
+// GetExternalNetwork retrieves the external network with the given name
+func GetExternalNetwork(vcdClient *VCDClient, name string) (*ExternalNetwork, error) {
+	reference, err := GetExternalNetworkByName(vcdClient, name)
+	if err != nil {
+		return nil, err
+	}
+	if reference.HREF == "" {
+		return nil, fmt.Errorf("external network %s not found", name)
+	}
+	return GetExternalNetworkByHref(vcdClient, reference.HREF)
+}
+
+// GetExternalNetworkByHref retrieves the external network with the given HREF
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/GET-ExternalNetwork.html
+func GetExternalNetworkByHref(vcdClient *VCDClient, href string) (*ExternalNetwork, error) {
+	externalNetwork := NewExternalNetwork(&vcdClient.Client)
+	_, err := vcdClient.Client.ExecuteRequest(href, http.MethodGet,
+		"", "error retrieving external network: %s", nil, externalNetwork.ExternalNetwork)
+	if err != nil {
+		return nil, err
+	}
+	return externalNetwork, nil
+}
+
+// Refresh retrieves the external network again
+func (externalNetwork *ExternalNetwork) Refresh() error {
+	if externalNetwork.ExternalNetwork.HREF == "" {
+		return fmt.Errorf("cannot refresh, Object is empty")
+	}
+	href := externalNetwork.ExternalNetwork.HREF
+
+	// Unmarshal into an empty struct, otherwise we end up with duplicate elements in
+	// slices, and keep the current one when the request fails.
+	refreshed := &types.VMWExternalNetwork{}
+
+	_, err := externalNetwork.client.ExecuteRequest(href, http.MethodGet,
+		"", "error refreshing external network: %s", nil, refreshed)
+	if err != nil {
+		return err
+	}
+	externalNetwork.ExternalNetwork = refreshed
+	return nil
+}
+
+// Update sends the current definition of the external network to vCD: name, description,
+// IP scopes (subnets and IP pools) and port group backings can be changed.
+// Returns the update task.
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/PUT-ExternalNetwork.html
+func (externalNetwork *ExternalNetwork) Update() (Task, error) {
+	util.Logger.Printf("[TRACE] ExternalNetwork.Update - updating external network %s", externalNetwork.ExternalNetwork.Name)
+
+	if externalNetwork.ExternalNetwork.HREF == "" {
+		return Task{}, fmt.Errorf("cannot update, Object is empty")
+	}
+	err := validateExternalNetwork(externalNetwork.ExternalNetwork)
+	if err != nil {
+		return Task{}, err
+	}
+
+	// Read-only elements are not sent back
+	payload := *externalNetwork.ExternalNetwork
+	payload.Link = nil
+	payload.Tasks = nil
+	configuration := *payload.Configuration
+	configuration.IPScopes = &types.ExternalNetworkIPScopes{}
+	for _, ipScope := range payload.Configuration.IPScopes.IPScope {
+		scope := *ipScope
+		scope.AllocatedIPAddresses = nil
+		scope.SubAllocations = nil
+		configuration.IPScopes.IPScope = append(configuration.IPScopes.IPScope, &scope)
+	}
+	payload.Configuration = &configuration
+
+	updated := NewExternalNetwork(externalNetwork.client)
+	_, err = externalNetwork.client.ExecuteRequest(externalNetwork.ExternalNetwork.HREF, http.MethodPut,
+		types.MimeExternalNetwork, "error updating external network: %s", &payload, updated.ExternalNetwork)
+	if err != nil {
+		return Task{}, err
+	}
+	if updated.ExternalNetwork.Tasks == nil || len(updated.ExternalNetwork.Tasks.Task) == 0 {
+		return Task{}, nil
+	}
+	task := NewTask(externalNetwork.client)
+	task.Task = updated.ExternalNetwork.Tasks.Task[0]
+	return *task, nil
+}
+
+// UpdateWait updates the external network, waits for the task to complete and refreshes the network
+func (externalNetwork *ExternalNetwork) UpdateWait() error {
+	task, err := externalNetwork.Update()
+	if err != nil {
+		return err
+	}
+	if task != (Task{}) {
+		err = task.WaitTaskCompletion()
+		if err != nil {
+			return fmt.Errorf("couldn't finish updating external network %#v", err)
+		}
+	}
+	return externalNetwork.Refresh()
+}
+
+// Delete deletes the external network. It fails if the network is still used by edge gateways
+// or org VDC networks.
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/DELETE-ExternalNetwork.html
+func (externalNetwork *ExternalNetwork) Delete() (Task, error) {
+	util.Logger.Printf("[TRACE] ExternalNetwork.Delete - deleting external network %s", externalNetwork.ExternalNetwork.Name)
+
+	if externalNetwork.ExternalNetwork.HREF == "" {
+		return Task{}, fmt.Errorf("cannot delete, Object is empty")
+	}
+	return externalNetwork.client.ExecuteTaskRequest(externalNetwork.ExternalNetwork.HREF, http.MethodDelete,
+		"", "error deleting external network: %s", nil)
+}
+
+// DeleteWait deletes the external network and waits for the asynchronous task to complete
+func (externalNetwork *ExternalNetwork) DeleteWait() error {
+	task, err := externalNetwork.Delete()
+	if err != nil {
+		return err
+	}
+	err = task.WaitTaskCompletion()
+	if err != nil {
+		return fmt.Errorf("couldn't finish removing external network %#v", err)
+	}
+	return nil
+}
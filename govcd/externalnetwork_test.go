@@ -0,0 +1,175 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+func (vcd *TestVCD) Test_CreateUpdateDeleteExternalNetwork(check *C) {
+	if vcd.skipAdminTests {
+		check.Skip(fmt.Sprintf(TestRequiresSysAdminPrivileges, check.TestName()))
+	}
+	if vcd.config.VCD.ExternalNetworkPortGroup == "" {
+		check.Skip("no external network port group found in configuration")
+	}
+
+	portGroups, err := QueryPortGroups(vcd.client, vcd.config.VCD.ExternalNetworkPortGroup)
+	check.Assert(err, IsNil)
+	check.Assert(len(portGroups), Equals, 1)
+
+	externalNetwork := &types.VMWExternalNetwork{
+		Name:        TestCreateExternalNetwork,
+		Description: "Test external network",
+		Configuration: &types.ExternalNetworkConfiguration{
+			IPScopes: &types.ExternalNetworkIPScopes{
+				IPScope: []*types.IPScope{{
+					Gateway:   "192.168.201.1",
+					Netmask:   "255.255.255.0",
+					DNS1:      "192.168.201.2",
+					IsEnabled: true,
+					IPRanges: &types.IPRanges{
+						IPRange: []*types.IPRange{{
+							StartAddress: "192.168.201.3",
+							EndAddress:   "192.168.201.100",
+						}},
+					},
+				}},
+			},
+		},
+		VimPortGroupRefs: &types.VimObjectRefs{
+			VimObjectRef: []*types.VimObjectRef{{
+				VimServerRef:  &types.Reference{HREF: portGroups[0].VcHREF},
+				MoRef:         portGroups[0].MoRef,
+				VimObjectType: portGroups[0].PortgroupType,
+			}},
+		},
+	}
+	task, err := CreateExternalNetwork(vcd.client, externalNetwork)
+	check.Assert(err, IsNil)
+	AddToCleanupList(TestCreateExternalNetwork, "externalNetwork", "", check.TestName())
+	err = task.WaitTaskCompletion()
+	check.Assert(err, IsNil)
+
+	createdNetwork, err := GetExternalNetwork(vcd.client, TestCreateExternalNetwork)
+	check.Assert(err, IsNil)
+	check.Assert(createdNetwork.ExternalNetwork.Configuration.FenceMode, Equals, types.FenceModeIsolated)
+	check.Assert(len(createdNetwork.ExternalNetwork.Configuration.IPScopes.IPScope), Equals, 1)
+	check.Assert(len(createdNetwork.ExternalNetwork.VimPortGroupRefs.VimObjectRef), Equals, 1)
+	check.Assert(createdNetwork.ExternalNetwork.VimPortGroupRefs.VimObjectRef[0].MoRef, Equals, portGroups[0].MoRef)
+
+	// Add a second subnet
+	createdNetwork.ExternalNetwork.Description = "Updated test external network"
+	createdNetwork.ExternalNetwork.Configuration.IPScopes.IPScope = append(createdNetwork.ExternalNetwork.Configuration.IPScopes.IPScope,
+		&types.IPScope{
+			Gateway:   "192.168.202.1",
+			Netmask:   "255.255.255.0",
+			IsEnabled: true,
+			IPRanges: &types.IPRanges{
+				IPRange: []*types.IPRange{{
+					StartAddress: "192.168.202.2",
+					EndAddress:   "192.168.202.50",
+				}},
+			},
+		})
+	err = createdNetwork.UpdateWait()
+	check.Assert(err, IsNil)
+	check.Assert(createdNetwork.ExternalNetwork.Description, Equals, "Updated test external network")
+	check.Assert(len(createdNetwork.ExternalNetwork.Configuration.IPScopes.IPScope), Equals, 2)
+
+	err = createdNetwork.DeleteWait()
+	check.Assert(err, IsNil)
+	_, err = GetExternalNetwork(vcd.client, TestCreateExternalNetwork)
+	check.Assert(err, NotNil)
+}
+
+func TestValidateExternalNetwork(t *testing.T) {
+	ipScopes := &types.ExternalNetworkIPScopes{IPScope: []*types.IPScope{{Gateway: "10.0.0.1", Netmask: "255.255.255.0"}}}
+	portGroup := &types.VimObjectRef{
+		VimServerRef:  &types.Reference{HREF: "https://vcd/api/admin/extension/vimServer/1"},
+		MoRef:         "dvportgroup-1",
+		VimObjectType: types.PortGroupTypeDistributed,
+	}
+
+	tests := []struct {
+		name    string
+		network *types.VMWExternalNetwork
+		valid   bool
+	}{
+		{"NoName", &types.VMWExternalNetwork{}, false},
+		{"NoIpScopes", &types.VMWExternalNetwork{
+			Name:             "net",
+			Configuration:    &types.ExternalNetworkConfiguration{},
+			VimPortGroupRefs: &types.VimObjectRefs{VimObjectRef: []*types.VimObjectRef{portGroup}},
+		}, false},
+		{"NoBacking", &types.VMWExternalNetwork{
+			Name:          "net",
+			Configuration: &types.ExternalNetworkConfiguration{IPScopes: ipScopes},
+		}, false},
+		{"WrongBackingType", &types.VMWExternalNetwork{
+			Name:          "net",
+			Configuration: &types.ExternalNetworkConfiguration{IPScopes: ipScopes},
+			VimPortGroupRefs: &types.VimObjectRefs{VimObjectRef: []*types.VimObjectRef{{
+				VimServerRef: portGroup.VimServerRef, MoRef: "resgroup-1", VimObjectType: "RESOURCE_POOL"}}},
+		}, false},
+		{"Valid", &types.VMWExternalNetwork{
+			Name:             "net",
+			Configuration:    &types.ExternalNetworkConfiguration{IPScopes: ipScopes},
+			VimPortGroupRefs: &types.VimObjectRefs{VimObjectRef: []*types.VimObjectRef{portGroup, portGroup}},
+		}, true},
+	}
+	for _, test := range tests {
+		err := validateExternalNetwork(test.network)
+		if test.valid && err != nil {
+			t.Errorf("%s: unexpected error: %s", test.name, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("%s: expected error", test.name)
+		}
+	}
+}
+
+// Checks that the external network payload mixes the extension and vCloud namespaces as vCD expects
+func TestVMWExternalNetworkMarshal(t *testing.T) {
+	externalNetwork := &types.VMWExternalNetwork{
+		Name:          "net",
+		Description:   "description",
+		Configuration: &types.ExternalNetworkConfiguration{FenceMode: types.FenceModeIsolated},
+		VimPortGroupRefs: &types.VimObjectRefs{VimObjectRef: []*types.VimObjectRef{{
+			VimServerRef: &types.Reference{HREF: "https://vcd/api/admin/extension/vimServer/1"}, MoRef: "network-1",
+			VimObjectType: types.PortGroupTypeStandard}}},
+	}
+	output, err := xml.Marshal(externalNetwork)
+	if err != nil {
+		t.Fatalf("error marshalling external network: %s", err)
+	}
+	expected := []string{
+		`<VMWExternalNetwork xmlns="` + types.XMLNamespaceExtension + `" name="net">`,
+		`<Description xmlns="` + types.XMLNamespaceVCloud + `">description</Description>`,
+		`<Configuration xmlns="` + types.XMLNamespaceVCloud + `"><FenceMode>isolated</FenceMode></Configuration>`,
+		`<VimPortGroupRefs><VimObjectRef><VimServerRef href="https://vcd/api/admin/extension/vimServer/1"></VimServerRef>`,
+	}
+	for _, fragment := range expected {
+		if !strings.Contains(string(output), fragment) {
+			t.Errorf("fragment %s not found in %s", fragment, output)
+		}
+	}
+
+	unmarshalled := &types.VMWExternalNetwork{}
+	err = xml.Unmarshal(output, unmarshalled)
+	if err != nil {
+		t.Fatalf("error unmarshalling external network: %s", err)
+	}
+	if unmarshalled.Description != "description" || unmarshalled.VimPortGroupRefs.VimObjectRef[0].MoRef != "network-1" {
+		t.Errorf("unexpected unmarshalled external network: %#v", unmarshalled)
+	}
+}
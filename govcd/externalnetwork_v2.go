@@ -0,0 +1,395 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// ExternalNetworkV2 is an external network managed through OpenAPI (API 33.0+). Unlike
+// ExternalNetwork, it supports the NSX-T backed external networks, which are needed by the NSX-T
+// edge gateways. External networks are only available to system administrators.
+type ExternalNetworkV2 struct {
+	ExternalNetwork *types.ExternalNetworkV2
+	client          *Client
+}
+
+// GetAllExternalNetworksV2 retrieves all the external networks. Query parameters can be supplied to
+// perform additional filtering (e.g. "filter" => "name==extnet1")
+func (client *Client) GetAllExternalNetworksV2(queryParameters url.Values) ([]*ExternalNetworkV2, error) {
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointExternalNetworks
+	apiVersion, err := client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var typeResponses []*types.ExternalNetworkV2
+	err = client.OpenApiGetAllItems(apiVersion, urlRef, queryParameters, &typeResponses)
+	if err != nil {
+		return nil, err
+	}
+
+	networks := make([]*ExternalNetworkV2, len(typeResponses))
+	for index, typeResponse := range typeResponses {
+		networks[index] = &ExternalNetworkV2{ExternalNetwork: typeResponse, client: client}
+	}
+	return networks, nil
+}
+
+// GetExternalNetworkV2ByName retrieves the external network with the given name
+func (client *Client) GetExternalNetworkV2ByName(name string) (*ExternalNetworkV2, error) {
+	queryParams := url.Values{}
+	queryParams.Set("filter", fiqlEq("name", name))
+	networks, err := client.GetAllExternalNetworksV2(queryParams)
+	if err != nil {
+		return nil, err
+	}
+	if len(networks) == 0 {
+		return nil, fmt.Errorf("external network '%s' not found", name)
+	}
+	if len(networks) > 1 {
+		return nil, fmt.Errorf("more than one external network found with name '%s'", name)
+	}
+	return networks[0], nil
+}
+
+// GetExternalNetworkV2ById retrieves the external network with the given ID
+func (client *Client) GetExternalNetworkV2ById(id string) (*ExternalNetworkV2, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty external network ID")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointExternalNetworks
+	apiVersion, err := client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint, id)
+	if err != nil {
+		return nil, err
+	}
+
+	network := &ExternalNetworkV2{ExternalNetwork: &types.ExternalNetworkV2{}, client: client}
+	err = client.OpenApiGetItem(apiVersion, urlRef, nil, network.ExternalNetwork)
+	if err != nil {
+		return nil, err
+	}
+	return network, nil
+}
+
+// CreateExternalNetworkV2 creates an external network with the given subnets and IP pools, backed
+// by a vSphere port group or by an NSX-T tier-0 router or segment (see
+// GetImportableNsxtTier0RouterByName)
+func (client *Client) CreateExternalNetworkV2(networkConfig *types.ExternalNetworkV2) (*ExternalNetworkV2, error) {
+	err := validateExternalNetworkV2(networkConfig)
+	if err != nil {
+		return nil, err
+	}
+	for _, backing := range networkConfig.NetworkBackings.Values {
+		if externalNetworkBackingType(backing) == types.ExternalNetworkBackingTypeNsxtSegment && !client.APIClientVersionIs(">= 36.0") {
+			return nil, fmt.Errorf("external networks backed by NSX-T segments need API version 36.0 or newer, the client uses %s",
+				client.APIVersion)
+		}
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointExternalNetworks
+	apiVersion, err := client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	network := &ExternalNetworkV2{ExternalNetwork: &types.ExternalNetworkV2{}, client: client}
+	err = client.OpenApiPostItem(apiVersion, urlRef, nil, externalNetworkV2Payload(client, networkConfig),
+		network.ExternalNetwork)
+	if err != nil {
+		return nil, fmt.Errorf("error creating external network: %s", err)
+	}
+	return network, nil
+}
+
+// Update sends the current definition of the external network (name, description, subnets and
+// their IP pools) to vCD
+func (network *ExternalNetworkV2) Update() error {
+	if network.ExternalNetwork.ID == "" {
+		return fmt.Errorf("cannot update external network without ID")
+	}
+	err := validateExternalNetworkV2(network.ExternalNetwork)
+	if err != nil {
+		return err
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointExternalNetworks
+	apiVersion, err := network.client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return err
+	}
+	urlRef, err := network.client.OpenApiBuildEndpoint(endpoint, network.ExternalNetwork.ID)
+	if err != nil {
+		return err
+	}
+
+	updated := &types.ExternalNetworkV2{}
+	err = network.client.OpenApiPutItem(apiVersion, urlRef, nil,
+		externalNetworkV2Payload(network.client, network.ExternalNetwork), updated)
+	if err != nil {
+		return fmt.Errorf("error updating external network: %s", err)
+	}
+	network.ExternalNetwork = updated
+	return nil
+}
+
+// IsNsxtBacked returns true if the external network is backed by an NSX-T tier-0 router, VRF or segment
+func (network *ExternalNetworkV2) IsNsxtBacked() bool {
+	for _, backing := range network.ExternalNetwork.NetworkBackings.Values {
+		switch externalNetworkBackingType(backing) {
+		case types.ExternalNetworkBackingTypeNsxtTier0Router, types.ExternalNetworkBackingTypeNsxtVrfTier0Router,
+			types.ExternalNetworkBackingTypeNsxtSegment:
+			return true
+		}
+	}
+	return false
+}
+
+// AddSubnet adds a subnet, with its IP pools, to the external network
+func (network *ExternalNetworkV2) AddSubnet(subnet types.ExternalNetworkV2Subnet) error {
+	for _, existing := range network.ExternalNetwork.Subnets.Values {
+		if existing.Gateway == subnet.Gateway {
+			return fmt.Errorf("external network %s already has a subnet with gateway %s",
+				network.ExternalNetwork.Name, subnet.Gateway)
+		}
+	}
+	network.ExternalNetwork.Subnets.Values = append(network.ExternalNetwork.Subnets.Values, subnet)
+	return network.updateOrRefresh()
+}
+
+// AddIpRanges adds IP ranges to the IP pool of the subnet of the external network with the given
+// gateway
+func (network *ExternalNetworkV2) AddIpRanges(gateway string, ipRanges ...types.OpenApiIPRange) error {
+	for index, subnet := range network.ExternalNetwork.Subnets.Values {
+		if subnet.Gateway == gateway {
+			network.ExternalNetwork.Subnets.Values[index].IPRanges.Values = append(subnet.IPRanges.Values, ipRanges...)
+			return network.updateOrRefresh()
+		}
+	}
+	return fmt.Errorf("subnet with gateway %s not found in external network %s", gateway, network.ExternalNetwork.Name)
+}
+
+// updateOrRefresh sends the current definition of the external network to vCD. When the update
+// fails, the definition is refreshed so that it keeps matching vCD.
+func (network *ExternalNetworkV2) updateOrRefresh() error {
+	err := network.Update()
+	if err != nil {
+		refreshed, refreshErr := network.client.GetExternalNetworkV2ById(network.ExternalNetwork.ID)
+		if refreshErr == nil {
+			network.ExternalNetwork = refreshed.ExternalNetwork
+		}
+		return err
+	}
+	return nil
+}
+
+// Delete removes the external network. It fails if edge gateways are still connected to it.
+func (network *ExternalNetworkV2) Delete() error {
+	if network.ExternalNetwork.ID == "" {
+		return fmt.Errorf("cannot delete external network without ID")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointExternalNetworks
+	apiVersion, err := network.client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return err
+	}
+	urlRef, err := network.client.OpenApiBuildEndpoint(endpoint, network.ExternalNetwork.ID)
+	if err != nil {
+		return err
+	}
+	err = network.client.OpenApiDeleteItem(apiVersion, urlRef, nil)
+	if err != nil {
+		return fmt.Errorf("error deleting external network: %s", err)
+	}
+	return nil
+}
+
+// GetImportableNsxtTier0RouterByName retrieves the NSX-T tier-0 router with the given display name,
+// among the ones of the NSX-T manager which are not used yet by an external network
+func (client *Client) GetImportableNsxtTier0RouterByName(name, nsxtManagerId string) (*types.NsxtTier0Router, error) {
+	if nsxtManagerId == "" {
+		return nil, fmt.Errorf("empty NSX-T manager ID")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointImportableTier0s
+	apiVersion, err := client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	queryParams := url.Values{}
+	queryParams.Set("filter", fiqlEq("_context", nsxtManagerId))
+	var routers []*types.NsxtTier0Router
+	err = client.OpenApiGetAllItems(apiVersion, urlRef, queryParams, &routers)
+	if err != nil {
+		return nil, err
+	}
+	for _, router := range routers {
+		if router.DisplayName == name {
+			return router, nil
+		}
+	}
+	return nil, fmt.Errorf("importable NSX-T tier-0 router '%s' not found", name)
+}
+
+// QueryNsxtManagers returns the query records of the NSX-T managers registered in vCD
+func (client *Client) QueryNsxtManagers() ([]*types.QueryResultNsxtManagerRecordType, error) {
+	results, err := client.QueryAllPages(types.QtNsxtManager, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error querying NSX-T managers: %s", err)
+	}
+	return results.Results.NsxtManagerRecord, nil
+}
+
+// GetNsxtManagerUrnByName returns the ID of the NSX-T manager with the given name, as used in the
+// network provider of NSX-T backings and to look up their tier-0 routers
+func (client *Client) GetNsxtManagerUrnByName(name string) (string, error) {
+	managers, err := client.QueryNsxtManagers()
+	if err != nil {
+		return "", err
+	}
+	for _, manager := range managers {
+		if manager.Name == name {
+			return HrefToUrn(manager.HREF)
+		}
+	}
+	return "", wrapErrorf(ErrorEntityNotFound, "NSX-T manager '%s' not found: %s", name, ErrorEntityNotFound)
+}
+
+// NewNsxtTier0RouterBacking returns the backing of an external network by an NSX-T tier-0 router or
+// VRF of the NSX-T manager with the given ID
+func NewNsxtTier0RouterBacking(router *types.NsxtTier0Router, nsxtManagerUrn string) types.ExternalNetworkV2Backing {
+	backingType := types.ExternalNetworkBackingTypeNsxtTier0Router
+	if router.ParentTier0ID != "" {
+		backingType = types.ExternalNetworkBackingTypeNsxtVrfTier0Router
+	}
+	return types.ExternalNetworkV2Backing{
+		BackingID:        router.ID,
+		Name:             router.DisplayName,
+		BackingTypeValue: backingType,
+		NetworkProvider:  types.OpenApiReference{ID: nsxtManagerUrn},
+	}
+}
+
+// NewNsxtSegmentBacking returns the backing of an external network by an NSX-T segment of the NSX-T
+// manager with the given ID (API 36.0+, vCD 10.3+)
+func NewNsxtSegmentBacking(segmentId, nsxtManagerUrn string) types.ExternalNetworkV2Backing {
+	return types.ExternalNetworkV2Backing{
+		BackingID:        segmentId,
+		BackingTypeValue: types.ExternalNetworkBackingTypeNsxtSegment,
+		NetworkProvider:  types.OpenApiReference{ID: nsxtManagerUrn},
+	}
+}
+
+// validateExternalNetworkV2 checks that the external network has a name, subnets and a backing
+func validateExternalNetworkV2(networkConfig *types.ExternalNetworkV2) error {
+	if networkConfig == nil || networkConfig.Name == "" {
+		return fmt.Errorf("external network name is required")
+	}
+	if len(networkConfig.Subnets.Values) == 0 {
+		return fmt.Errorf("external network %s needs at least one subnet", networkConfig.Name)
+	}
+	for _, subnet := range networkConfig.Subnets.Values {
+		if subnet.Gateway == "" || subnet.PrefixLength == 0 {
+			return fmt.Errorf("subnets of external network %s need gateway and prefix length", networkConfig.Name)
+		}
+		err := validateExternalNetworkV2Subnet(subnet)
+		if err != nil {
+			return fmt.Errorf("invalid subnet in external network %s: %s", networkConfig.Name, err)
+		}
+	}
+	if len(networkConfig.NetworkBackings.Values) == 0 {
+		return fmt.Errorf("external network %s needs a network backing", networkConfig.Name)
+	}
+	nsxtBackings := 0
+	for _, backing := range networkConfig.NetworkBackings.Values {
+		switch externalNetworkBackingType(backing) {
+		case types.ExternalNetworkBackingTypeNsxtTier0Router, types.ExternalNetworkBackingTypeNsxtVrfTier0Router,
+			types.ExternalNetworkBackingTypeNsxtSegment:
+			nsxtBackings++
+		}
+	}
+	if nsxtBackings > 0 && len(networkConfig.NetworkBackings.Values) > 1 {
+		return fmt.Errorf("external network %s backed by NSX-T must have a single backing", networkConfig.Name)
+	}
+	for _, backing := range networkConfig.NetworkBackings.Values {
+		if backing.BackingID == "" || backing.NetworkProvider.ID == "" {
+			return fmt.Errorf("backings of external network %s need a backing ID and a network provider",
+				networkConfig.Name)
+		}
+		if backing.BackingType == "" && backing.BackingTypeValue == "" {
+			return fmt.Errorf("backings of external network %s need a backing type", networkConfig.Name)
+		}
+	}
+	return nil
+}
+
+// validateExternalNetworkV2Subnet checks that the IP ranges of the subnet are valid and belong to it
+func validateExternalNetworkV2Subnet(subnet types.ExternalNetworkV2Subnet) error {
+	if net.ParseIP(subnet.Gateway) == nil {
+		return fmt.Errorf("invalid gateway '%s'", subnet.Gateway)
+	}
+	_, ipNet, err := net.ParseCIDR(fmt.Sprintf("%s/%d", subnet.Gateway, subnet.PrefixLength))
+	if err != nil {
+		return fmt.Errorf("invalid prefix length %d for gateway %s", subnet.PrefixLength, subnet.Gateway)
+	}
+	for _, ipRange := range subnet.IPRanges.Values {
+		start, end, err := parseIpRange(ipRange)
+		if err != nil {
+			return err
+		}
+		if !ipNet.Contains(start) || !ipNet.Contains(end) {
+			return fmt.Errorf("IP range %s-%s is not in subnet %s", ipRange.StartAddress, ipRange.EndAddress, ipNet)
+		}
+	}
+	return nil
+}
+
+// externalNetworkBackingType returns the backing type, whatever the field in which it is set
+func externalNetworkBackingType(backing types.ExternalNetworkV2Backing) string {
+	if backing.BackingTypeValue != "" {
+		return backing.BackingTypeValue
+	}
+	return backing.BackingType
+}
+
+// externalNetworkV2Payload returns a copy of the external network where the backing types are set
+// in the field known by the API version of client: backingType before 35.0, backingTypeValue since then
+func externalNetworkV2Payload(client *Client, networkConfig *types.ExternalNetworkV2) *types.ExternalNetworkV2 {
+	payload := *networkConfig
+	payload.NetworkBackings.Values = make([]types.ExternalNetworkV2Backing, len(networkConfig.NetworkBackings.Values))
+	for index, backing := range networkConfig.NetworkBackings.Values {
+		if client.APIClientVersionIs(">= 35.0") {
+			if backing.BackingTypeValue == "" {
+				backing.BackingTypeValue = backing.BackingType
+			}
+			backing.BackingType = ""
+		} else {
+			if backing.BackingType == "" {
+				backing.BackingType = backing.BackingTypeValue
+			}
+			backing.BackingTypeValue = ""
+		}
+		payload.NetworkBackings.Values[index] = backing
+	}
+	return &payload
+}
@@ -0,0 +1,160 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// Checks that the backing types of external networks are sent in the field known by the API version
+func TestExternalNetworkV2Payload(t *testing.T) {
+	network := &types.ExternalNetworkV2{
+		Name: "extnet1",
+		Subnets: types.ExternalNetworkV2Subnets{Values: []types.ExternalNetworkV2Subnet{{
+			Gateway:      "192.168.100.1",
+			PrefixLength: 24,
+			Enabled:      true,
+			IPRanges: types.OpenApiIPRanges{Values: []types.OpenApiIPRange{
+				{StartAddress: "192.168.100.10", EndAddress: "192.168.100.50"},
+			}},
+		}}},
+		NetworkBackings: types.ExternalNetworkV2Backings{Values: []types.ExternalNetworkV2Backing{{
+			BackingID:       "tier0-id",
+			BackingType:     types.ExternalNetworkBackingTypeNsxtTier0Router,
+			NetworkProvider: types.OpenApiReference{ID: "urn:vcloud:nsxtmanager:99999999-9999-9999-9999-999999999999"},
+		}}},
+	}
+	if err := validateExternalNetworkV2(network); err != nil {
+		t.Fatalf("unexpected validation error: %s", err)
+	}
+
+	payload := externalNetworkV2Payload(&Client{APIVersion: "33.0"}, network)
+	backing := payload.NetworkBackings.Values[0]
+	if backing.BackingType != types.ExternalNetworkBackingTypeNsxtTier0Router || backing.BackingTypeValue != "" {
+		t.Errorf("unexpected backing for API 33.0: %#v", backing)
+	}
+
+	payload = externalNetworkV2Payload(&Client{APIVersion: "35.0"}, network)
+	backing = payload.NetworkBackings.Values[0]
+	if backing.BackingTypeValue != types.ExternalNetworkBackingTypeNsxtTier0Router || backing.BackingType != "" {
+		t.Errorf("unexpected backing for API 35.0: %#v", backing)
+	}
+	if network.NetworkBackings.Values[0].BackingTypeValue != "" {
+		t.Errorf("the original network definition was modified")
+	}
+
+	network.NetworkBackings.Values[0].NetworkProvider.ID = ""
+	if err := validateExternalNetworkV2(network); err == nil {
+		t.Errorf("expected error validating backing without network provider")
+	}
+}
+
+// Checks the creation of an external network backed by an NSX-T tier-0 router against a fake vCD,
+// from the lookup of the NSX-T manager to the addition of an IP pool
+func TestClient_CreateNsxtExternalNetwork(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	const managerUuid = "99999999-9999-9999-9999-999999999999"
+	const networksPath = "/cloudapi/1.0.0/externalNetworks/"
+	const networkId = "urn:vcloud:network:12121212-1212-1212-1212-121212121212"
+	server.HandleXML(http.MethodGet, "/api/query", http.StatusOK,
+		`<QueryResultRecords xmlns="http://www.vmware.com/vcloud/v1.5" total="1" page="1" pageSize="128">
+<NsxTManagerRecord name="nsxt1" url="https://nsxt1.example.com" href="{{server}}/api/admin/extension/nsxtManagers/`+managerUuid+`"/>
+</QueryResultRecords>`)
+	server.HandleJSON(http.MethodGet, "/cloudapi/1.0.0/nsxTResources/importableTier0Routers", http.StatusOK,
+		`{"resultTotal":2,"pageCount":1,"page":1,"pageSize":128,"values":[
+		{"id":"tier0-id","displayName":"tier0"},{"id":"vrf-id","displayName":"vrf1","parentTier0Id":"tier0-id"}]}`)
+	networkJson := `{"id":"` + networkId + `","name":"extnet1","description":"",
+"subnets":{"values":[{"gateway":"192.168.100.1","prefixLength":24,"enabled":true,"ipRanges":{"values":[{"startAddress":"192.168.100.10","endAddress":"192.168.100.50"}]}}]},
+"networkBackings":{"values":[{"backingId":"vrf-id","backingTypeValue":"NSXT_VRF_TIER0","networkProvider":{"id":"urn:vcloud:nsxtmanager:` + managerUuid + `"}}]}}`
+	server.HandleJSON(http.MethodPost, networksPath, http.StatusCreated, networkJson)
+	server.HandleJSON(http.MethodPut, networksPath+networkId, http.StatusOK, networkJson)
+	server.HandleJSON(http.MethodGet, networksPath+networkId, http.StatusOK, networkJson)
+
+	client := &newMockClient(t, server).Client
+	client.APIVersion = "35.0"
+
+	managerUrn, err := client.GetNsxtManagerUrnByName("nsxt1")
+	if err != nil {
+		t.Fatalf("error retrieving NSX-T manager: %s", err)
+	}
+	if managerUrn != "urn:vcloud:nsxtmanager:"+managerUuid {
+		t.Errorf("unexpected NSX-T manager ID: %s", managerUrn)
+	}
+	if _, err = client.GetNsxtManagerUrnByName("nsxt2"); !errors.Is(err, ErrorEntityNotFound) {
+		t.Errorf("expected entity not found error for unknown NSX-T manager, got %v", err)
+	}
+	router, err := client.GetImportableNsxtTier0RouterByName("vrf1", managerUrn)
+	if err != nil {
+		t.Fatalf("error retrieving tier-0 router: %s", err)
+	}
+
+	networkConfig := &types.ExternalNetworkV2{
+		Name: "extnet1",
+		Subnets: types.ExternalNetworkV2Subnets{Values: []types.ExternalNetworkV2Subnet{{
+			Gateway:      "192.168.100.1",
+			PrefixLength: 24,
+			Enabled:      true,
+			IPRanges: types.OpenApiIPRanges{Values: []types.OpenApiIPRange{
+				{StartAddress: "192.168.100.10", EndAddress: "192.168.100.50"},
+			}},
+		}}},
+		NetworkBackings: types.ExternalNetworkV2Backings{Values: []types.ExternalNetworkV2Backing{
+			NewNsxtTier0RouterBacking(router, managerUrn),
+		}},
+	}
+	network, err := client.CreateExternalNetworkV2(networkConfig)
+	if err != nil {
+		t.Fatalf("error creating external network: %s", err)
+	}
+	if network.ExternalNetwork.ID != networkId || !network.IsNsxtBacked() {
+		t.Errorf("unexpected external network: %#v", network.ExternalNetwork)
+	}
+	posts := server.RequestsTo(http.MethodPost, networksPath)
+	if len(posts) != 1 || !strings.Contains(posts[0].Body, `"backingTypeValue": "NSXT_VRF_TIER0"`) ||
+		!strings.Contains(posts[0].Body, `"id": "urn:vcloud:nsxtmanager:`+managerUuid+`"`) {
+		t.Errorf("unexpected creation request: %#v", posts)
+	}
+
+	err = network.AddIpRanges("192.168.100.1", types.OpenApiIPRange{StartAddress: "192.168.100.100", EndAddress: "192.168.100.120"})
+	if err != nil {
+		t.Fatalf("error adding IP range: %s", err)
+	}
+	puts := server.RequestsTo(http.MethodPut, networksPath+networkId)
+	if len(puts) != 1 || !strings.Contains(puts[0].Body, `"startAddress": "192.168.100.100"`) {
+		t.Errorf("unexpected update request: %#v", puts)
+	}
+	// Invalid IP pools are refused before any request, and the network is refreshed
+	err = network.AddIpRanges("192.168.100.1", types.OpenApiIPRange{StartAddress: "192.168.200.1", EndAddress: "192.168.200.9"})
+	if err == nil || len(server.RequestsTo(http.MethodPut, networksPath+networkId)) != 1 {
+		t.Errorf("expected error adding an IP range outside of the subnet: %v", err)
+	}
+	if len(network.ExternalNetwork.Subnets.Values[0].IPRanges.Values) != 1 {
+		t.Errorf("the network was not refreshed after a failed update: %#v", network.ExternalNetwork.Subnets)
+	}
+	if err = network.AddIpRanges("10.0.0.1", types.OpenApiIPRange{StartAddress: "10.0.0.2"}); err == nil {
+		t.Errorf("expected error adding IP range to an unknown subnet")
+	}
+	if err = network.AddSubnet(network.ExternalNetwork.Subnets.Values[0]); err == nil {
+		t.Errorf("expected error adding a subnet with an existing gateway")
+	}
+
+	// Segments need API 36.0, and NSX-T backed networks have a single backing
+	networkConfig.NetworkBackings.Values = []types.ExternalNetworkV2Backing{NewNsxtSegmentBacking("segment-id", managerUrn)}
+	if _, err = client.CreateExternalNetworkV2(networkConfig); err == nil {
+		t.Errorf("expected error creating an external network backed by a segment with API 35.0")
+	}
+	networkConfig.NetworkBackings.Values = append(networkConfig.NetworkBackings.Values, NewNsxtTier0RouterBacking(router, managerUrn))
+	if err = validateExternalNetworkV2(networkConfig); err == nil {
+		t.Errorf("expected error validating an NSX-T backed network with several backings")
+	}
+}
@@ -0,0 +1,389 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// Criteria of the client-side search engine (see Client.SearchByFilter)
+const (
+	FilterNameRegex = "name_regex" // The name matches a regular expression
+	FilterIp        = "ip"         // The IP address matches a regular expression
+	FilterDate      = "date"       // The creation date satisfies the conditions, e.g. ">= 2019-01-01 and < 2019-07-01"
+	FilterLatest    = "latest"     // Only the item with the most recent creation date is returned
+	FilterEarliest  = "earliest"   // Only the item with the oldest creation date is returned
+)
+
+// supportedFilters lists the criteria accepted by FilterDef.AddFilter
+var supportedFilters = []string{FilterNameRegex, FilterIp, FilterDate, FilterLatest, FilterEarliest}
+
+// MetadataDef is a metadata condition: the item must have a metadata entry with the given key,
+// whose value matches the regular expression ValueRegex
+type MetadataDef struct {
+	Key        string
+	ValueRegex string
+}
+
+// FilterDef holds the criteria of a client-side search. All the criteria must be satisfied.
+type FilterDef struct {
+	Filters  map[string]string
+	Metadata []MetadataDef
+}
+
+// NewFilterDef creates a filter definition without criteria
+func NewFilterDef() *FilterDef {
+	return &FilterDef{Filters: make(map[string]string)}
+}
+
+// AddFilter adds a criterion to the filter definition, checking that its value is valid.
+// FilterLatest and FilterEarliest take the value "true".
+func (fd *FilterDef) AddFilter(key, value string) error {
+	switch key {
+	case FilterNameRegex, FilterIp:
+		if _, err := regexp.Compile(value); err != nil {
+			return fmt.Errorf("invalid regular expression for filter %s: %s", key, err)
+		}
+	case FilterDate:
+		if _, err := parseDateConditions(value); err != nil {
+			return err
+		}
+	case FilterLatest, FilterEarliest:
+		if value != "true" {
+			return fmt.Errorf("filter %s only accepts the value 'true'", key)
+		}
+		other := FilterEarliest
+		if key == FilterEarliest {
+			other = FilterLatest
+		}
+		if fd.Filters[other] != "" {
+			return fmt.Errorf("filters %s and %s cannot be used together", FilterLatest, FilterEarliest)
+		}
+	default:
+		return fmt.Errorf("filter %s not supported, use one of %s", key, strings.Join(supportedFilters, ", "))
+	}
+	if fd.Filters == nil {
+		fd.Filters = make(map[string]string)
+	}
+	fd.Filters[key] = value
+	return nil
+}
+
+// AddMetadataFilter adds a metadata condition to the filter definition
+func (fd *FilterDef) AddMetadataFilter(key, valueRegex string) error {
+	if key == "" {
+		return fmt.Errorf("metadata key is required")
+	}
+	if _, err := regexp.Compile(valueRegex); err != nil {
+		return fmt.Errorf("invalid regular expression for metadata %s: %s", key, err)
+	}
+	fd.Metadata = append(fd.Metadata, MetadataDef{Key: key, ValueRegex: valueRegex})
+	return nil
+}
+
+// QueryItem is a query record that can be examined by the search engine
+type QueryItem interface {
+	GetName() string
+	GetType() string
+	GetHref() string
+	GetIp() string   // IP address of the item, if it has one
+	GetDate() string // Creation date of the item
+}
+
+// QueryVAppTemplate is a vApp template record as QueryItem
+type QueryVAppTemplate types.QueryResultVAppTemplateRecordType
+
+// QueryVApp is a vApp record as QueryItem
+type QueryVApp types.QueryResultVAppRecordType
+
+// QueryVm is a VM record as QueryItem
+type QueryVm types.QueryResultVMRecordType
+
+// QueryOrgVdcNetwork is an org VDC network record as QueryItem. Its IP address is the default gateway.
+type QueryOrgVdcNetwork types.QueryResultOrgVdcNetworkRecordType
+
+func (item QueryVAppTemplate) GetName() string { return item.Name }
+func (item QueryVAppTemplate) GetType() string { return types.QtVappTemplate }
+func (item QueryVAppTemplate) GetHref() string { return item.HREF }
+func (item QueryVAppTemplate) GetIp() string   { return "" }
+func (item QueryVAppTemplate) GetDate() string { return item.CreationDate }
+
+func (item QueryVApp) GetName() string { return item.Name }
+func (item QueryVApp) GetType() string { return types.QtVapp }
+func (item QueryVApp) GetHref() string { return item.HREF }
+func (item QueryVApp) GetIp() string   { return "" }
+func (item QueryVApp) GetDate() string { return item.CreationDate }
+
+func (item QueryVm) GetName() string { return item.Name }
+func (item QueryVm) GetType() string { return types.QtVm }
+func (item QueryVm) GetHref() string { return item.HREF }
+func (item QueryVm) GetIp() string   { return item.IpAddress }
+func (item QueryVm) GetDate() string { return item.DateCreated }
+
+func (item QueryOrgVdcNetwork) GetName() string { return item.Name }
+func (item QueryOrgVdcNetwork) GetType() string { return types.QtOrgVdcNetwork }
+func (item QueryOrgVdcNetwork) GetHref() string { return item.HREF }
+func (item QueryOrgVdcNetwork) GetIp() string   { return item.DefaultGateway }
+func (item QueryOrgVdcNetwork) GetDate() string { return "" }
+
+// SearchByFilter runs a query of the given type and returns the items satisfying the criteria.
+// Supported query types: QtVappTemplate, QtVapp, QtAdminVapp, QtVm, QtAdminVm, QtOrgVdcNetwork.
+// Example, to find the newest template whose name starts with "ubuntu-22":
+//
+//	criteria := NewFilterDef()
+//	_ = criteria.AddFilter(FilterNameRegex, "^ubuntu-22")
+//	_ = criteria.AddFilter(FilterLatest, "true")
+//	items, err := client.SearchByFilter(types.QtVappTemplate, criteria)
+func (client *Client) SearchByFilter(queryType string, criteria *FilterDef) ([]QueryItem, error) {
+	if criteria == nil {
+		criteria = NewFilterDef()
+	}
+	// FilterDef.Metadata can be set without AddMetadataFilter, so its expressions are checked here
+	metadataConditions, err := compileMetadataConditions(criteria.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	items, err := client.queryItems(queryType)
+	if err != nil {
+		return nil, err
+	}
+	items, err = filterItems(items, criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(metadataConditions) > 0 {
+		var withMetadata []QueryItem
+		for _, item := range items {
+			metadata, err := getMetadata(client, item.GetHref())
+			if err != nil {
+				return nil, err
+			}
+			if matchesMetadata(metadata, metadataConditions) {
+				withMetadata = append(withMetadata, item)
+			}
+		}
+		items = withMetadata
+	}
+
+	return selectByDate(items, criteria), nil
+}
+
+// queryItems runs a query of the given type and returns the records as QueryItem
+func (client *Client) queryItems(queryType string) ([]QueryItem, error) {
+	switch queryType {
+	case types.QtVappTemplate, types.QtVapp, types.QtAdminVapp, types.QtVm, types.QtAdminVm, types.QtOrgVdcNetwork:
+	default:
+		return nil, fmt.Errorf("query type %s not supported by the search engine", queryType)
+	}
+	results, err := client.QueryAllPages(queryType, nil)
+	if err != nil {
+		return nil, err
+	}
+	var items []QueryItem
+	switch queryType {
+	case types.QtVappTemplate:
+		for _, record := range results.Results.VAppTemplateRecord {
+			items = append(items, QueryVAppTemplate(*record))
+		}
+	case types.QtVapp, types.QtAdminVapp:
+		records := results.Results.VAppRecord
+		if queryType == types.QtAdminVapp {
+			records = results.Results.AdminVAppRecord
+		}
+		for _, record := range records {
+			items = append(items, QueryVApp(*record))
+		}
+	case types.QtVm, types.QtAdminVm:
+		records := results.Results.VMRecord
+		if queryType == types.QtAdminVm {
+			records = results.Results.AdminVMRecord
+		}
+		for _, record := range records {
+			items = append(items, QueryVm(*record))
+		}
+	case types.QtOrgVdcNetwork:
+		for _, record := range results.Results.OrgVdcNetworkRecord {
+			items = append(items, QueryOrgVdcNetwork(*record))
+		}
+	}
+	return items, nil
+}
+
+// filterItems returns the items satisfying the name, IP and date criteria
+func filterItems(items []QueryItem, criteria *FilterDef) ([]QueryItem, error) {
+	var nameRegex, ipRegex *regexp.Regexp
+	var dateConditions []dateCondition
+	var err error
+	if value := criteria.Filters[FilterNameRegex]; value != "" {
+		if nameRegex, err = regexp.Compile(value); err != nil {
+			return nil, err
+		}
+	}
+	if value := criteria.Filters[FilterIp]; value != "" {
+		if ipRegex, err = regexp.Compile(value); err != nil {
+			return nil, err
+		}
+	}
+	if value := criteria.Filters[FilterDate]; value != "" {
+		if dateConditions, err = parseDateConditions(value); err != nil {
+			return nil, err
+		}
+	}
+
+	var filtered []QueryItem
+	for _, item := range items {
+		if nameRegex != nil && !nameRegex.MatchString(item.GetName()) {
+			continue
+		}
+		if ipRegex != nil && (item.GetIp() == "" || !ipRegex.MatchString(item.GetIp())) {
+			continue
+		}
+		if len(dateConditions) > 0 {
+			date, err := parseDate(item.GetDate())
+			if err != nil || !matchesDateConditions(date, dateConditions) {
+				continue
+			}
+		}
+		filtered = append(filtered, item)
+	}
+	return filtered, nil
+}
+
+// metadataCondition is a metadata condition whose regular expression is compiled
+type metadataCondition struct {
+	key        string
+	valueRegex *regexp.Regexp
+}
+
+// compileMetadataConditions compiles the regular expressions of the metadata conditions
+func compileMetadataConditions(definitions []MetadataDef) ([]metadataCondition, error) {
+	conditions := make([]metadataCondition, len(definitions))
+	for i, definition := range definitions {
+		valueRegex, err := regexp.Compile(definition.ValueRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression for metadata %s: %s", definition.Key, err)
+		}
+		conditions[i] = metadataCondition{key: definition.Key, valueRegex: valueRegex}
+	}
+	return conditions, nil
+}
+
+// matchesMetadata returns true if the metadata satisfies all the conditions
+func matchesMetadata(metadata *types.Metadata, conditions []metadataCondition) bool {
+	for _, condition := range conditions {
+		found := false
+		for _, entry := range metadata.MetadataEntry {
+			if entry.Key == condition.key && entry.TypedValue != nil && condition.valueRegex.MatchString(entry.TypedValue.Value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// selectByDate returns only the latest or the earliest item, when the criteria ask for it
+func selectByDate(items []QueryItem, criteria *FilterDef) []QueryItem {
+	latest := criteria.Filters[FilterLatest] == "true"
+	earliest := criteria.Filters[FilterEarliest] == "true"
+	if !latest && !earliest {
+		return items
+	}
+	var dated []QueryItem
+	for _, item := range items {
+		if _, err := parseDate(item.GetDate()); err == nil {
+			dated = append(dated, item)
+		}
+	}
+	if len(dated) == 0 {
+		return nil
+	}
+	sort.SliceStable(dated, func(i, j int) bool {
+		first, _ := parseDate(dated[i].GetDate())
+		second, _ := parseDate(dated[j].GetDate())
+		return first.Before(second)
+	})
+	if latest {
+		return dated[len(dated)-1:]
+	}
+	return dated[:1]
+}
+
+// dateCondition is a comparison of a date with a reference date
+type dateCondition struct {
+	operator string
+	date     time.Time
+}
+
+// dateLayouts are the date formats accepted in date conditions and returned by vCD
+var dateLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"}
+
+// parseDate parses a date in one of the supported formats
+func parseDate(value string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		date, err := time.Parse(layout, value)
+		if err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date '%s'", value)
+}
+
+// parseDateConditions parses expressions like ">= 2019-01-01 and < 2019-07-01"
+func parseDateConditions(expression string) ([]dateCondition, error) {
+	var conditions []dateCondition
+	for _, part := range strings.Split(expression, " and ") {
+		part = strings.TrimSpace(part)
+		operator := ""
+		for _, op := range []string{">=", "<=", "==", ">", "<"} {
+			if strings.HasPrefix(part, op) {
+				operator = op
+				break
+			}
+		}
+		if operator == "" {
+			return nil, fmt.Errorf("invalid date condition '%s': it must start with one of >, >=, <, <=, ==", part)
+		}
+		date, err := parseDate(strings.TrimSpace(strings.TrimPrefix(part, operator)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid date condition '%s': %s", part, err)
+		}
+		conditions = append(conditions, dateCondition{operator: operator, date: date})
+	}
+	return conditions, nil
+}
+
+// matchesDateConditions returns true if the date satisfies all the conditions
+func matchesDateConditions(date time.Time, conditions []dateCondition) bool {
+	for _, condition := range conditions {
+		var matches bool
+		switch condition.operator {
+		case ">":
+			matches = date.After(condition.date)
+		case ">=":
+			matches = !date.Before(condition.date)
+		case "<":
+			matches = date.Before(condition.date)
+		case "<=":
+			matches = !date.After(condition.date)
+		case "==":
+			matches = date.Equal(condition.date)
+		}
+		if !matches {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,161 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"testing"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// Searches the template of the test catalog item by name, and the test vApp by name and date
+func (vcd *TestVCD) Test_SearchByFilter(check *C) {
+	if vcd.config.VCD.Catalog.CatalogItem == "" {
+		check.Skip("no catalog item found in configuration")
+	}
+	client := &vcd.client.Client
+
+	criteria := NewFilterDef()
+	err := criteria.AddFilter(FilterNameRegex, "^"+vcd.config.VCD.Catalog.CatalogItem+"$")
+	check.Assert(err, IsNil)
+	err = criteria.AddFilter(FilterLatest, "true")
+	check.Assert(err, IsNil)
+	items, err := client.SearchByFilter(types.QtVappTemplate, criteria)
+	check.Assert(err, IsNil)
+	check.Assert(len(items), Equals, 1)
+	check.Assert(items[0].GetName(), Equals, vcd.config.VCD.Catalog.CatalogItem)
+
+	if !vcd.skipVappTests {
+		criteria = NewFilterDef()
+		err = criteria.AddFilter(FilterNameRegex, "^"+vcd.vapp.VApp.Name+"$")
+		check.Assert(err, IsNil)
+		err = criteria.AddFilter(FilterDate, "> 2000-01-01")
+		check.Assert(err, IsNil)
+		items, err = client.SearchByFilter(types.QtVapp, criteria)
+		check.Assert(err, IsNil)
+		check.Assert(len(items), Equals, 1)
+		check.Assert(items[0].GetHref(), Equals, vcd.vapp.VApp.HREF)
+	}
+
+	_, err = client.SearchByFilter(types.QtMedia, nil)
+	check.Assert(err, NotNil)
+}
+
+func TestFilterDef_AddFilter(t *testing.T) {
+	criteria := NewFilterDef()
+	valid := map[string]string{
+		FilterNameRegex: "^ubuntu-22",
+		FilterIp:        `^192\.168\.1\.`,
+		FilterDate:      ">= 2019-01-01 and < 2019-07-01T00:00:00Z",
+		FilterLatest:    "true",
+	}
+	for key, value := range valid {
+		if err := criteria.AddFilter(key, value); err != nil {
+			t.Errorf("unexpected error adding filter %s: %s", key, err)
+		}
+	}
+	invalid := map[string]string{
+		FilterNameRegex: "(",
+		FilterDate:      "after 2019-01-01",
+		FilterEarliest:  "true", // cannot be used with FilterLatest
+		"unknown":       "value",
+	}
+	for key, value := range invalid {
+		if err := criteria.AddFilter(key, value); err == nil {
+			t.Errorf("expected error adding filter %s=%s", key, value)
+		}
+	}
+	if err := criteria.AddMetadataFilter("", "value"); err == nil {
+		t.Errorf("expected error adding metadata filter without key")
+	}
+}
+
+func TestFilterItems(t *testing.T) {
+	items := []QueryItem{
+		QueryVAppTemplate{Name: "ubuntu-22.04-a", CreationDate: "2019-03-01T10:00:00.000Z"},
+		QueryVAppTemplate{Name: "ubuntu-22.04-b", CreationDate: "2019-05-01T10:00:00.000Z"},
+		QueryVAppTemplate{Name: "ubuntu-20.04", CreationDate: "2019-06-01T10:00:00.000Z"},
+		QueryVAppTemplate{Name: "centos-7", CreationDate: "2018-01-01T10:00:00.000Z"},
+		QueryVm{Name: "vm1", IpAddress: "192.168.1.10"},
+		QueryVm{Name: "vm2", IpAddress: "10.0.0.10"},
+	}
+
+	search := func(filters map[string]string) []QueryItem {
+		criteria := NewFilterDef()
+		for key, value := range filters {
+			if err := criteria.AddFilter(key, value); err != nil {
+				t.Fatalf("error adding filter %s: %s", key, err)
+			}
+		}
+		filtered, err := filterItems(items, criteria)
+		if err != nil {
+			t.Fatalf("error filtering items: %s", err)
+		}
+		return selectByDate(filtered, criteria)
+	}
+
+	names := func(found []QueryItem) []string {
+		var result []string
+		for _, item := range found {
+			result = append(result, item.GetName())
+		}
+		return result
+	}
+
+	tests := []struct {
+		filters  map[string]string
+		expected []string
+	}{
+		{map[string]string{FilterNameRegex: `^ubuntu-22\.`}, []string{"ubuntu-22.04-a", "ubuntu-22.04-b"}},
+		{map[string]string{FilterNameRegex: `^ubuntu-22\.`, FilterLatest: "true"}, []string{"ubuntu-22.04-b"}},
+		{map[string]string{FilterNameRegex: `^ubuntu`, FilterEarliest: "true"}, []string{"ubuntu-22.04-a"}},
+		{map[string]string{FilterDate: ">= 2019-04-01 and < 2019-06-01"}, []string{"ubuntu-22.04-b"}},
+		{map[string]string{FilterIp: `^192\.168\.`}, []string{"vm1"}},
+		{map[string]string{FilterNameRegex: "^debian"}, nil},
+	}
+	for _, test := range tests {
+		found := names(search(test.filters))
+		if len(found) != len(test.expected) {
+			t.Errorf("filters %v: expected %v, got %v", test.filters, test.expected, found)
+			continue
+		}
+		for i := range found {
+			if found[i] != test.expected[i] {
+				t.Errorf("filters %v: expected %v, got %v", test.filters, test.expected, found)
+			}
+		}
+	}
+}
+
+func TestMatchesMetadata(t *testing.T) {
+	metadata := &types.Metadata{MetadataEntry: []*types.MetadataEntry{
+		{Key: "os", TypedValue: &types.TypedValue{Value: "ubuntu-22.04"}},
+		{Key: "team", TypedValue: &types.TypedValue{Value: "infra"}},
+	}}
+	matches := func(definitions ...MetadataDef) bool {
+		conditions, err := compileMetadataConditions(definitions)
+		if err != nil {
+			t.Fatalf("error compiling metadata conditions: %s", err)
+		}
+		return matchesMetadata(metadata, conditions)
+	}
+	if !matches(MetadataDef{Key: "os", ValueRegex: "^ubuntu"}, MetadataDef{Key: "team", ValueRegex: "infra"}) {
+		t.Errorf("expected metadata to match")
+	}
+	if matches(MetadataDef{Key: "os", ValueRegex: "^centos"}) {
+		t.Errorf("expected metadata not to match a different value")
+	}
+	if matches(MetadataDef{Key: "owner", ValueRegex: ".*"}) {
+		t.Errorf("expected metadata not to match a missing key")
+	}
+
+	// An invalid expression set directly in the filter definition is an error, not a panic
+	criteria := &FilterDef{Metadata: []MetadataDef{{Key: "os", ValueRegex: "("}}}
+	if _, err := (&Client{}).SearchByFilter(types.QtVm, criteria); err == nil {
+		t.Errorf("expected error searching with an invalid metadata expression")
+	}
+}
@@ -0,0 +1,232 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// GlobalRole is a role template defined by the provider and published to tenants.
+// Global roles are managed through OpenAPI and need system administrator privileges.
+type GlobalRole struct {
+	GlobalRole *types.GlobalRole
+	client     *Client
+}
+
+// NewGlobalRole creates a new global role structure which still needs to have GlobalRole attribute populated
+func NewGlobalRole(cli *Client) *GlobalRole {
+	return &GlobalRole{
+		GlobalRole: new(types.GlobalRole),
+		client:     cli,
+	}
+}
+
+// GetAllGlobalRoles retrieves all global roles. Query parameters can be supplied to perform
+// additional filtering
+func (client *Client) GetAllGlobalRoles(queryParameters url.Values) ([]*GlobalRole, error) {
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointGlobalRoles
+	apiVersion, err := getOpenApiVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var typeResponses []*types.GlobalRole
+	err = client.OpenApiGetAllItems(apiVersion, urlRef, queryParameters, &typeResponses)
+	if err != nil {
+		return nil, err
+	}
+
+	globalRoles := make([]*GlobalRole, len(typeResponses))
+	for index, typeResponse := range typeResponses {
+		globalRoles[index] = &GlobalRole{GlobalRole: typeResponse, client: client}
+	}
+	return globalRoles, nil
+}
+
+// GetGlobalRoleByName retrieves the global role with the given name
+func (client *Client) GetGlobalRoleByName(name string) (*GlobalRole, error) {
+	queryParams := url.Values{}
+	queryParams.Set("filter", fiqlEq("name", name))
+	globalRoles, err := client.GetAllGlobalRoles(queryParams)
+	if err != nil {
+		return nil, err
+	}
+	if len(globalRoles) == 0 {
+		return nil, wrapErrorf(ErrorEntityNotFound, "global role '%s' not found: %s", name, ErrorEntityNotFound)
+	}
+	if len(globalRoles) > 1 {
+		return nil, fmt.Errorf("more than one global role found with name '%s'", name)
+	}
+	return globalRoles[0], nil
+}
+
+// GetGlobalRoleById retrieves the global role with the given ID
+func (client *Client) GetGlobalRoleById(id string) (*GlobalRole, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty global role ID")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointGlobalRoles
+	apiVersion, err := getOpenApiVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint, id)
+	if err != nil {
+		return nil, err
+	}
+
+	globalRole := NewGlobalRole(client)
+	err = client.OpenApiGetItem(apiVersion, urlRef, nil, globalRole.GlobalRole)
+	if err != nil {
+		return nil, err
+	}
+	return globalRole, nil
+}
+
+// CreateGlobalRole creates a new global role. The rights and the tenants need to be set
+// after creation, with AddRights and PublishTenants
+func (client *Client) CreateGlobalRole(newGlobalRole *types.GlobalRole) (*GlobalRole, error) {
+	if newGlobalRole == nil || newGlobalRole.Name == "" {
+		return nil, fmt.Errorf("global role name is required")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointGlobalRoles
+	apiVersion, err := getOpenApiVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	globalRole := NewGlobalRole(client)
+	err = client.OpenApiPostItem(apiVersion, urlRef, nil, newGlobalRole, globalRole.GlobalRole)
+	if err != nil {
+		return nil, fmt.Errorf("error creating global role: %s", err)
+	}
+	return globalRole, nil
+}
+
+// Refresh retrieves the current definition of the global role
+func (globalRole *GlobalRole) Refresh() error {
+	refreshed, err := globalRole.client.GetGlobalRoleById(globalRole.GlobalRole.ID)
+	if err != nil {
+		return err
+	}
+	globalRole.GlobalRole = refreshed.GlobalRole
+	return nil
+}
+
+// Update sends the current definition of the global role (name, description) to vCD
+func (globalRole *GlobalRole) Update() error {
+	if globalRole.GlobalRole.ID == "" {
+		return fmt.Errorf("cannot update global role without ID")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointGlobalRoles
+	apiVersion, err := getOpenApiVersion(endpoint)
+	if err != nil {
+		return err
+	}
+	urlRef, err := globalRole.client.OpenApiBuildEndpoint(endpoint, globalRole.GlobalRole.ID)
+	if err != nil {
+		return err
+	}
+
+	updated := &types.GlobalRole{}
+	err = globalRole.client.OpenApiPutItem(apiVersion, urlRef, nil, globalRole.GlobalRole, updated)
+	if err != nil {
+		return fmt.Errorf("error updating global role: %s", err)
+	}
+	globalRole.GlobalRole = updated
+	return nil
+}
+
+// Delete removes the global role
+func (globalRole *GlobalRole) Delete() error {
+	if globalRole.GlobalRole.ID == "" {
+		return fmt.Errorf("cannot delete global role without ID")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointGlobalRoles
+	apiVersion, err := getOpenApiVersion(endpoint)
+	if err != nil {
+		return err
+	}
+	urlRef, err := globalRole.client.OpenApiBuildEndpoint(endpoint, globalRole.GlobalRole.ID)
+	if err != nil {
+		return err
+	}
+	err = globalRole.client.OpenApiDeleteItem(apiVersion, urlRef, nil)
+	if err != nil {
+		return fmt.Errorf("error deleting global role: %s", err)
+	}
+	return nil
+}
+
+// GetRights retrieves the rights of the global role
+func (globalRole *GlobalRole) GetRights(queryParameters url.Values) ([]*types.Right, error) {
+	return getRightsCollection(globalRole.client, types.OpenApiPathVersion1_0_0+types.OpenApiEndpointGlobalRoles,
+		globalRole.GlobalRole.ID, queryParameters)
+}
+
+// AddRights adds the given rights to the global role
+func (globalRole *GlobalRole) AddRights(rights types.OpenApiReferences) error {
+	return addRightsToCollection(globalRole.client, types.OpenApiPathVersion1_0_0+types.OpenApiEndpointGlobalRoles,
+		globalRole.GlobalRole.ID, rights)
+}
+
+// RemoveRights removes the given rights from the global role
+func (globalRole *GlobalRole) RemoveRights(rights types.OpenApiReferences) error {
+	return removeRightsFromCollection(globalRole.client, types.OpenApiPathVersion1_0_0+types.OpenApiEndpointGlobalRoles,
+		globalRole.GlobalRole.ID, rights)
+}
+
+// UpdateRights replaces the rights of the global role with the given list
+func (globalRole *GlobalRole) UpdateRights(rights types.OpenApiReferences) error {
+	return updateRightsCollection(globalRole.client, types.OpenApiPathVersion1_0_0+types.OpenApiEndpointGlobalRoles,
+		globalRole.GlobalRole.ID, rights)
+}
+
+// GetTenants retrieves the organizations the global role is published to
+func (globalRole *GlobalRole) GetTenants(queryParameters url.Values) (types.OpenApiReferences, error) {
+	return getTenantsCollection(globalRole.client, types.OpenApiPathVersion1_0_0+types.OpenApiEndpointGlobalRoles,
+		globalRole.GlobalRole.ID, queryParameters)
+}
+
+// PublishTenants publishes the global role to the given organizations
+func (globalRole *GlobalRole) PublishTenants(tenants types.OpenApiReferences) error {
+	return changeTenantsCollection(globalRole.client, types.OpenApiPathVersion1_0_0+types.OpenApiEndpointGlobalRoles,
+		globalRole.GlobalRole.ID, "publish", tenants)
+}
+
+// UnpublishTenants removes the global role from the given organizations
+func (globalRole *GlobalRole) UnpublishTenants(tenants types.OpenApiReferences) error {
+	return changeTenantsCollection(globalRole.client, types.OpenApiPathVersion1_0_0+types.OpenApiEndpointGlobalRoles,
+		globalRole.GlobalRole.ID, "unpublish", tenants)
+}
+
+// ReplacePublishedTenants publishes the global role to exactly the given organizations
+func (globalRole *GlobalRole) ReplacePublishedTenants(tenants types.OpenApiReferences) error {
+	return changeTenantsCollection(globalRole.client, types.OpenApiPathVersion1_0_0+types.OpenApiEndpointGlobalRoles,
+		globalRole.GlobalRole.ID, "", tenants)
+}
+
+// PublishAllTenants publishes the global role to all organizations, including future ones
+func (globalRole *GlobalRole) PublishAllTenants() error {
+	return changeTenantsCollection(globalRole.client, types.OpenApiPathVersion1_0_0+types.OpenApiEndpointGlobalRoles,
+		globalRole.GlobalRole.ID, "publishAll", nil)
+}
+
+// UnpublishAllTenants removes the global role from all organizations
+func (globalRole *GlobalRole) UnpublishAllTenants() error {
+	return changeTenantsCollection(globalRole.client, types.OpenApiPathVersion1_0_0+types.OpenApiEndpointGlobalRoles,
+		globalRole.GlobalRole.ID, "unpublishAll", nil)
+}
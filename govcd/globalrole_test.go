@@ -0,0 +1,144 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+	. "gopkg.in/check.v1"
+)
+
+func (vcd *TestVCD) Test_GlobalRoles(check *C) {
+	if vcd.skipAdminTests {
+		check.Skip(fmt.Sprintf(TestRequiresSysAdminPrivileges, check.TestName()))
+	}
+	if vcd.client.APIVCDMaxVersionIs("< 33.0") {
+		check.Skip("global roles require vCD 10.0+")
+	}
+	client := &vcd.client.Client
+
+	globalRoles, err := client.GetAllGlobalRoles(nil)
+	check.Assert(err, IsNil)
+	check.Assert(len(globalRoles), Not(Equals), 0)
+
+	globalRole, err := client.CreateGlobalRole(&types.GlobalRole{
+		Name:        TestGlobalRole,
+		Description: TestGlobalRole,
+		BundleKey:   types.VcloudUndefinedKey,
+	})
+	check.Assert(err, IsNil)
+	AddToCleanupList(TestGlobalRole, "globalRole", "", "Test_GlobalRoles")
+
+	right, err := client.GetRightByName("Catalog: View Private and Shared Catalogs")
+	check.Assert(err, IsNil)
+	err = globalRole.AddRights(types.OpenApiReferences{{Name: right.Name, ID: right.ID}})
+	check.Assert(err, IsNil)
+	rights, err := globalRole.GetRights(nil)
+	check.Assert(err, IsNil)
+	check.Assert(len(rights), Equals, 1)
+
+	globalRole.GlobalRole.Description = TestGlobalRole + " updated"
+	err = globalRole.Update()
+	check.Assert(err, IsNil)
+	check.Assert(globalRole.GlobalRole.Description, Equals, TestGlobalRole+" updated")
+
+	adminOrg, err := GetAdminOrgByName(vcd.client, vcd.config.VCD.Org)
+	check.Assert(err, IsNil)
+	tenant := types.OpenApiReferences{{Name: adminOrg.AdminOrg.Name, ID: adminOrg.AdminOrg.ID}}
+	err = globalRole.PublishTenants(tenant)
+	check.Assert(err, IsNil)
+	tenants, err := globalRole.GetTenants(nil)
+	check.Assert(err, IsNil)
+	check.Assert(len(tenants), Equals, 1)
+	err = globalRole.UnpublishTenants(tenant)
+	check.Assert(err, IsNil)
+
+	err = globalRole.RemoveRights(types.OpenApiReferences{{Name: right.Name, ID: right.ID}})
+	check.Assert(err, IsNil)
+
+	foundRole, err := client.GetGlobalRoleByName(TestGlobalRole)
+	check.Assert(err, IsNil)
+	check.Assert(foundRole.GlobalRole.ID, Equals, globalRole.GlobalRole.ID)
+
+	err = globalRole.Delete()
+	check.Assert(err, IsNil)
+	_, err = client.GetGlobalRoleById(globalRole.GlobalRole.ID)
+	check.Assert(err, NotNil)
+}
+
+// Checks the publishing of global roles and rights bundles to tenants against a fake vCD
+func TestGlobalRole_Publish(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	const roleId = "urn:vcloud:globalRole:55555555-5555-5555-5555-555555555555"
+	const bundleId = "urn:vcloud:rightsBundle:66666666-6666-6666-6666-666666666666"
+	const rolePath = "/cloudapi/1.0.0/globalRoles/" + roleId
+	const bundlePath = "/cloudapi/1.0.0/rightsBundles/" + bundleId
+	server.HandleJSON(http.MethodGet, rolePath, http.StatusOK,
+		`{"id":"`+roleId+`","name":"role1","description":"updated","bundleKey":"ROLE_ROLE1","readOnly":false}`)
+	server.HandleJSON(http.MethodGet, bundlePath, http.StatusOK,
+		`{"id":"`+bundleId+`","name":"bundle1","description":"","bundleKey":"BUNDLE_1","readOnly":false}`)
+	for _, path := range []string{rolePath, bundlePath} {
+		server.HandleJSON(http.MethodPost, path+"/tenants/publish", http.StatusOK, `{}`)
+		server.HandleJSON(http.MethodPost, path+"/tenants/publishAll", http.StatusOK, `{}`)
+		server.HandleJSON(http.MethodPut, path+"/tenants", http.StatusOK, `{}`)
+	}
+	server.HandleJSON(http.MethodGet, "/cloudapi/1.0.0/rightsBundles/", http.StatusOK,
+		`{"resultTotal":0,"pageCount":0,"page":1,"pageSize":128,"values":[]}`)
+
+	vcdClient := newMockClient(t, server)
+	client := &vcdClient.Client
+	tenants := types.OpenApiReferences{{Name: vcdtest.MockOrgName, ID: "urn:vcloud:org:" + vcdtest.MockOrgId}}
+
+	globalRole := NewGlobalRole(client)
+	globalRole.GlobalRole.ID = roleId
+	err := globalRole.Refresh()
+	if err != nil {
+		t.Fatalf("error refreshing global role: %s", err)
+	}
+	if globalRole.GlobalRole.Name != "role1" || globalRole.GlobalRole.Description != "updated" {
+		t.Errorf("unexpected global role: %#v", globalRole.GlobalRole)
+	}
+	err = globalRole.PublishTenants(tenants)
+	if err != nil {
+		t.Fatalf("error publishing global role: %s", err)
+	}
+	posts := server.RequestsTo(http.MethodPost, rolePath+"/tenants/publish")
+	sent := types.OpenApiItems{}
+	if len(posts) != 1 || json.Unmarshal([]byte(posts[0].Body), &sent) != nil || len(sent.Values) != 1 {
+		t.Errorf("unexpected publishing of global role: %#v", posts)
+	}
+	err = globalRole.ReplacePublishedTenants(nil)
+	if err != nil {
+		t.Fatalf("error replacing tenants of global role: %s", err)
+	}
+	puts := server.RequestsTo(http.MethodPut, rolePath+"/tenants")
+	if len(puts) != 1 || json.Unmarshal([]byte(puts[0].Body), &sent) != nil || len(sent.Values) != 0 {
+		t.Errorf("expected an empty list of tenants, got %#v", puts)
+	}
+
+	rightsBundle, err := client.GetRightsBundleById(bundleId)
+	if err != nil {
+		t.Fatalf("error retrieving rights bundle: %s", err)
+	}
+	err = rightsBundle.PublishAllTenants()
+	if err != nil {
+		t.Fatalf("error publishing rights bundle to all tenants: %s", err)
+	}
+	if len(server.RequestsTo(http.MethodPost, bundlePath+"/tenants/publishAll")) != 1 {
+		t.Errorf("expected the rights bundle to be published to all tenants")
+	}
+	_, err = client.GetRightsBundleByName("bundle2")
+	if !errors.Is(err, ErrorEntityNotFound) {
+		t.Errorf("expected entity not found error for rights bundle, got %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// Names of the predefined roles available in every organization
+const (
+	OrgUserRoleOrganizationAdministrator = "Organization Administrator"
+	OrgUserRoleCatalogAuthor             = "Catalog Author"
+	OrgUserRoleVappAuthor                = "vApp Author"
+	OrgUserRoleVappUser                  = "vApp User"
+	OrgUserRoleConsoleAccessOnly         = "Console Access Only"
+	OrgUserRoleDeferToIdentityProvider   = "Defer to Identity Provider"
+)
+
+// OrgGroup defines a group imported into an organization
+type OrgGroup struct {
+	Group    *types.Group
+	client   *Client
+	AdminOrg *AdminOrg // the organization the group belongs to, used to look up roles
+}
+
+// OrgGroupConfiguration is a simplified definition of a group imported from an identity provider,
+// used by CreateGroupSimple
+type OrgGroupConfiguration struct {
+	Name         string // mandatory: name of the group in the identity provider
+	ProviderType string // mandatory: types.OrgUserProviderIntegrated for LDAP groups or types.OrgUserProviderSAML
+	RoleName     string // mandatory: name of an org role, such as OrgUserRoleVappUser
+	Description  string
+}
+
+// NewGroup creates a new group structure which still needs to have Group attribute populated
+func NewGroup(cli *Client, org *AdminOrg) *OrgGroup {
+	return &OrgGroup{
+		Group:    new(types.Group),
+		client:   cli,
+		AdminOrg: org,
+	}
+}
+
+// CreateGroup imports a group from the identity provider of the org.
+// Name and ProviderType (one of types.OrgUserProviderIntegrated, types.OrgUserProviderSAML,
+// types.OrgUserProviderOAUTH) are mandatory. Role, if set, is the role given to the group members.
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/POST-CreateGroup.html
+func (adminOrg *AdminOrg) CreateGroup(group *types.Group) (*OrgGroup, error) {
+	if group == nil || group.Name == "" {
+		return nil, fmt.Errorf("group name is required")
+	}
+	switch group.ProviderType {
+	case types.OrgUserProviderIntegrated, types.OrgUserProviderSAML, types.OrgUserProviderOAUTH:
+	case "":
+		return nil, fmt.Errorf("group provider type is required")
+	default:
+		return nil, fmt.Errorf("invalid provider type '%s' for group %s", group.ProviderType, group.Name)
+	}
+	group.Xmlns = types.XMLNamespaceVCloud
+
+	groupsHREF, err := url.ParseRequestURI(adminOrg.AdminOrg.HREF)
+	if err != nil {
+		return nil, fmt.Errorf("error getting AdminOrg HREF %s : %v", adminOrg.AdminOrg.HREF, err)
+	}
+	groupsHREF.Path += "/groups"
+
+	orgGroup := NewGroup(adminOrg.client, adminOrg)
+	_, err = adminOrg.client.ExecuteRequest(groupsHREF.String(), http.MethodPost,
+		types.MimeAdminGroup, "error creating group: %s", group, orgGroup.Group)
+	if err != nil {
+		return nil, err
+	}
+	return orgGroup, nil
+}
+
+// CreateGroupSimple imports a group from a simplified configuration, looking up the role by name
+func (adminOrg *AdminOrg) CreateGroupSimple(groupConfiguration OrgGroupConfiguration) (*OrgGroup, error) {
+	if groupConfiguration.Name == "" || groupConfiguration.ProviderType == "" || groupConfiguration.RoleName == "" {
+		return nil, fmt.Errorf("group name, provider type and role name are required")
+	}
+	role, err := adminOrg.GetRoleReference(groupConfiguration.RoleName)
+	if err != nil {
+		return nil, err
+	}
+	return adminOrg.CreateGroup(&types.Group{
+		Name:         groupConfiguration.Name,
+		Description:  groupConfiguration.Description,
+		ProviderType: groupConfiguration.ProviderType,
+		Role:         &types.Reference{HREF: role.HREF},
+	})
+}
+
+// GetGroupByHref retrieves a group by its HREF
+func (adminOrg *AdminOrg) GetGroupByHref(href string) (*OrgGroup, error) {
+	orgGroup := NewGroup(adminOrg.client, adminOrg)
+	_, err := adminOrg.client.ExecuteRequest(href, http.MethodGet,
+		"", "error retrieving group: %s", nil, orgGroup.Group)
+	if err != nil {
+		return nil, err
+	}
+	return orgGroup, nil
+}
+
+// GetGroupByName refreshes the org and retrieves the group with the given name.
+// Returns ErrorEntityNotFound if the group is not found.
+func (adminOrg *AdminOrg) GetGroupByName(name string) (*OrgGroup, error) {
+	err := adminOrg.Refresh()
+	if err != nil {
+		return nil, err
+	}
+	if adminOrg.AdminOrg.Groups != nil {
+		for _, groupRef := range adminOrg.AdminOrg.Groups.GroupReference {
+			if groupRef.Name == name {
+				return adminOrg.GetGroupByHref(groupRef.HREF)
+			}
+		}
+	}
+	return nil, wrapErrorf(ErrorEntityNotFound, "group %s not found in org %s: %s", name, adminOrg.AdminOrg.Name, ErrorEntityNotFound)
+}
+
+// GetRoleReference returns the reference of the org role with the given name
+func (adminOrg *AdminOrg) GetRoleReference(roleName string) (*types.Reference, error) {
+	if adminOrg.AdminOrg.RoleReferences != nil {
+		for _, role := range adminOrg.AdminOrg.RoleReferences.RoleReference {
+			if role.Name == roleName {
+				return role, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("role %s not found in org %s", roleName, adminOrg.AdminOrg.Name)
+}
+
+// Refresh retrieves the group again, updating its members and role
+func (group *OrgGroup) Refresh() error {
+	if group.Group == nil || group.Group.HREF == "" {
+		return fmt.Errorf("cannot refresh, Object is empty")
+	}
+	href := group.Group.HREF
+
+	// Unmarshal into an empty struct, otherwise we end up with duplicate elements in
+	// slices, and keep the current one when the request fails.
+	refreshed := &types.Group{}
+
+	_, err := group.client.ExecuteRequest(href, http.MethodGet,
+		"", "error retrieving group: %s", nil, refreshed)
+	if err != nil {
+		return err
+	}
+	group.Group = refreshed
+	return nil
+}
+
+// Update sends the current group definition (description and role) to vCD
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/PUT-Group.html
+func (group *OrgGroup) Update() error {
+	if group.Group.HREF == "" {
+		return fmt.Errorf("cannot update group without HREF")
+	}
+	payload := &types.Group{
+		Xmlns:        types.XMLNamespaceVCloud,
+		Name:         group.Group.Name,
+		Description:  group.Group.Description,
+		NameInSource: group.Group.NameInSource,
+		ProviderType: group.Group.ProviderType,
+		Role:         group.Group.Role,
+	}
+	updated := &types.Group{}
+	_, err := group.client.ExecuteRequest(group.Group.HREF, http.MethodPut,
+		types.MimeAdminGroup, "error updating group: %s", payload, updated)
+	if err != nil {
+		return err
+	}
+	group.Group = updated
+	return nil
+}
+
+// ChangeRole assigns the org role with the given name to the group members
+func (group *OrgGroup) ChangeRole(roleName string) error {
+	if group.AdminOrg == nil {
+		return fmt.Errorf("group %s has no parent organization", group.Group.Name)
+	}
+	role, err := group.AdminOrg.GetRoleReference(roleName)
+	if err != nil {
+		return err
+	}
+	group.Group.Role = &types.Reference{HREF: role.HREF, Name: role.Name}
+	return group.Update()
+}
+
+// Delete removes the group from the organization. The users imported through the
+// group are not removed.
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/DELETE-Group.html
+func (group *OrgGroup) Delete() error {
+	if group.Group.HREF == "" {
+		return fmt.Errorf("cannot delete group without HREF")
+	}
+	return group.client.ExecuteRequestWithoutResponse(group.Group.HREF, http.MethodDelete,
+		"", "error deleting group: %s", nil)
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+	. "gopkg.in/check.v1"
+)
+
+// Imports a SAML group into the test org, changes its role and deletes it
+func (vcd *TestVCD) Test_GroupCRUD(check *C) {
+	if vcd.skipAdminTests {
+		check.Skip(fmt.Sprintf(TestRequiresSysAdminPrivileges, check.TestName()))
+	}
+	adminOrg, err := GetAdminOrgByName(vcd.client, vcd.config.VCD.Org)
+	check.Assert(err, IsNil)
+
+	role, err := adminOrg.GetRoleReference(OrgUserRoleVappUser)
+	check.Assert(err, IsNil)
+
+	group, err := adminOrg.CreateGroup(&types.Group{
+		Name:         TestCreateGroup,
+		Description:  TestCreateGroup,
+		ProviderType: types.OrgUserProviderSAML,
+		Role:         &types.Reference{HREF: role.HREF},
+	})
+	check.Assert(err, IsNil)
+	AddToCleanupList(TestCreateGroup, "group", vcd.config.VCD.Org, "Test_GroupCRUD")
+	check.Assert(group.Group.Name, Equals, TestCreateGroup)
+	check.Assert(group.Group.Role, NotNil)
+	check.Assert(group.Group.Role.Name, Equals, OrgUserRoleVappUser)
+
+	group, err = adminOrg.GetGroupByName(TestCreateGroup)
+	check.Assert(err, IsNil)
+
+	err = group.ChangeRole(OrgUserRoleCatalogAuthor)
+	check.Assert(err, IsNil)
+	err = group.Refresh()
+	check.Assert(err, IsNil)
+	check.Assert(group.Group.Role.Name, Equals, OrgUserRoleCatalogAuthor)
+
+	err = group.Delete()
+	check.Assert(err, IsNil)
+	_, err = adminOrg.GetGroupByName(TestCreateGroup)
+	check.Assert(err, NotNil)
+}
+
+// Checks the import of groups bound to a role by name against a fake vCD
+func TestAdminOrg_CreateGroupSimple(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	const groupsPath = vcdtest.MockAdminOrgPath + "/groups"
+	const groupPath = "/api/admin/group/44444444-4444-4444-4444-444444444444"
+	server.HandleXML(http.MethodGet, vcdtest.MockAdminOrgPath, http.StatusOK,
+		`<AdminOrg xmlns="http://www.vmware.com/vcloud/v1.5" name="`+vcdtest.MockOrgName+`" href="{{server}}`+vcdtest.MockAdminOrgPath+`">
+  <Groups>
+    <GroupReference type="application/vnd.vmware.admin.group+xml" name="cn=developers" href="{{server}}`+groupPath+`"/>
+  </Groups>
+  <RoleReferences>
+    <RoleReference type="application/vnd.vmware.admin.role+xml" name="vApp User" href="{{server}}/api/admin/role/vapp-user"/>
+  </RoleReferences>
+</AdminOrg>`)
+	groupXml := `<Group xmlns="http://www.vmware.com/vcloud/v1.5" name="cn=developers" href="{{server}}` + groupPath + `">
+  <ProviderType>INTEGRATED</ProviderType>
+  <Role type="application/vnd.vmware.admin.role+xml" name="vApp User" href="{{server}}/api/admin/role/vapp-user"/>
+</Group>`
+	server.HandleXML(http.MethodPost, groupsPath, http.StatusCreated, groupXml)
+	server.HandleXML(http.MethodGet, groupPath, http.StatusOK, groupXml)
+
+	vcdClient := newMockClient(t, server)
+	adminOrg, err := GetAdminOrgByName(vcdClient, vcdtest.MockOrgName)
+	if err != nil {
+		t.Fatalf("error retrieving admin org: %s", err)
+	}
+
+	invalidConfigurations := []OrgGroupConfiguration{
+		{ProviderType: types.OrgUserProviderIntegrated, RoleName: OrgUserRoleVappUser},
+		{Name: "cn=developers", RoleName: OrgUserRoleVappUser},
+		{Name: "cn=developers", ProviderType: "LDAP", RoleName: OrgUserRoleVappUser},
+		{Name: "cn=developers", ProviderType: types.OrgUserProviderIntegrated, RoleName: OrgUserRoleCatalogAuthor},
+	}
+	for _, configuration := range invalidConfigurations {
+		if _, err = adminOrg.CreateGroupSimple(configuration); err == nil {
+			t.Errorf("expected error importing group %#v", configuration)
+		}
+	}
+	if len(server.RequestsTo(http.MethodPost, groupsPath)) != 0 {
+		t.Fatalf("expected no group import after invalid configurations")
+	}
+
+	group, err := adminOrg.CreateGroupSimple(OrgGroupConfiguration{
+		Name:         "cn=developers",
+		ProviderType: types.OrgUserProviderIntegrated,
+		RoleName:     OrgUserRoleVappUser,
+	})
+	if err != nil {
+		t.Fatalf("error importing group: %s", err)
+	}
+	if group.Group.Role == nil || group.Group.Role.Name != OrgUserRoleVappUser {
+		t.Errorf("unexpected role of the imported group: %#v", group.Group.Role)
+	}
+	body := server.RequestsTo(http.MethodPost, groupsPath)[0].Body
+	if !strings.Contains(body, `name="cn=developers"`) || !strings.Contains(body, "<ProviderType>INTEGRATED</ProviderType>") ||
+		!strings.Contains(body, "/api/admin/role/vapp-user") {
+		t.Errorf("unexpected group sent:\n%s", body)
+	}
+
+	_, err = adminOrg.GetGroupByName("cn=developers")
+	if err != nil {
+		t.Errorf("error retrieving group: %s", err)
+	}
+	_, err = adminOrg.GetGroupByName("cn=testers")
+	if !errors.Is(err, ErrorEntityNotFound) {
+		t.Errorf("expected entity not found error for group, got %v", err)
+	}
+}
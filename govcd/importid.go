@@ -0,0 +1,259 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/util"
+)
+
+// DefaultImportSeparator separates the names in a hierarchical import ID, such as "org.vdc.vapp.vm"
+const DefaultImportSeparator = "."
+
+// importEscape escapes a separator, or itself, inside a name of an import ID
+const importEscape = `\`
+
+// ImportId identifies an entity by the names of its ancestors, as used by the import
+// commands of Terraform-style consumers. Org and Vdc are always set; VApp is needed
+// to identify a VM.
+type ImportId struct {
+	Org  string
+	Vdc  string
+	VApp string
+	VM   string
+}
+
+// String returns the import ID composed with DefaultImportSeparator
+func (id ImportId) String() string {
+	return id.Compose(DefaultImportSeparator)
+}
+
+// Compose returns the import ID with its names joined by separator, e.g. "org.vdc.vapp.vm".
+// Separators and backslashes inside the names are escaped with a backslash.
+func (id ImportId) Compose(separator string) string {
+	var names []string
+	for _, name := range []string{id.Org, id.Vdc, id.VApp, id.VM} {
+		if name == "" {
+			break
+		}
+		name = strings.Replace(name, importEscape, importEscape+importEscape, -1)
+		name = strings.Replace(name, separator, importEscape+separator, -1)
+		names = append(names, name)
+	}
+	return strings.Join(names, separator)
+}
+
+// ParseImportId parses an import ID composed of two (org, vdc), three (org, vdc, vApp)
+// or four (org, vdc, vApp, VM) names joined by separator.
+// A backslash escapes a separator or a backslash inside a name.
+func ParseImportId(importId, separator string) (ImportId, error) {
+	if separator == "" {
+		return ImportId{}, fmt.Errorf("empty separator for import ID %s", importId)
+	}
+	var names []string
+	var name strings.Builder
+	for i := 0; i < len(importId); {
+		switch {
+		case strings.HasPrefix(importId[i:], importEscape):
+			rest := importId[i+len(importEscape):]
+			switch {
+			case strings.HasPrefix(rest, separator):
+				name.WriteString(separator)
+				i += len(importEscape) + len(separator)
+			case strings.HasPrefix(rest, importEscape):
+				name.WriteString(importEscape)
+				i += 2 * len(importEscape)
+			default:
+				return ImportId{}, fmt.Errorf("invalid escape sequence at position %d in import ID %s", i, importId)
+			}
+		case strings.HasPrefix(importId[i:], separator):
+			names = append(names, name.String())
+			name.Reset()
+			i += len(separator)
+		default:
+			name.WriteByte(importId[i])
+			i++
+		}
+	}
+	names = append(names, name.String())
+
+	if len(names) < 2 || len(names) > 4 {
+		return ImportId{}, fmt.Errorf("import ID %s should have between 2 and 4 names separated by '%s', found %d",
+			importId, separator, len(names))
+	}
+	for i, name := range names {
+		if name == "" {
+			return ImportId{}, fmt.Errorf("empty name at position %d in import ID %s", i+1, importId)
+		}
+	}
+	names = append(names, "", "")
+	return ImportId{Org: names[0], Vdc: names[1], VApp: names[2], VM: names[3]}, nil
+}
+
+// ResolvedImportId contains the live entities identified by an ImportId.
+// VApp and VM are nil when the import ID does not include them.
+type ResolvedImportId struct {
+	Org  *Org
+	Vdc  *Vdc
+	VApp *VApp
+	VM   *VM
+}
+
+// Urn returns the URN of the deepest entity of the import ID
+func (resolved *ResolvedImportId) Urn() (string, error) {
+	switch {
+	case resolved.VM != nil:
+		return entityUrn(resolved.VM.VM.ID, resolved.VM.VM.HREF)
+	case resolved.VApp != nil:
+		return entityUrn(resolved.VApp.VApp.ID, resolved.VApp.VApp.HREF)
+	case resolved.Vdc != nil:
+		return entityUrn(resolved.Vdc.Vdc.ID, resolved.Vdc.Vdc.HREF)
+	case resolved.Org != nil:
+		return entityUrn(resolved.Org.Org.ID, resolved.Org.Org.HREF)
+	}
+	return "", fmt.Errorf("no entity resolved")
+}
+
+// ResolveImportId retrieves the entities identified by an import ID. Each entity is
+// found in the body of its parent, so that only one GET is needed per level, besides
+// the list of organizations.
+func (vcdClient *VCDClient) ResolveImportId(id ImportId) (*ResolvedImportId, error) {
+	util.Logger.Printf("[TRACE] Resolving import ID %s", id)
+	if id.Org == "" || id.Vdc == "" {
+		return nil, fmt.Errorf("import ID %s must include an org and a VDC", id)
+	}
+	if id.VM != "" && id.VApp == "" {
+		return nil, fmt.Errorf("import ID %s must include a vApp to identify a VM", id)
+	}
+
+	org, err := GetOrgByName(vcdClient, id.Org)
+	if err != nil {
+		return nil, err
+	}
+	if org.Org.HREF == "" {
+		return nil, fmt.Errorf("org %s not found", id.Org)
+	}
+	vdc, err := org.GetVdcByName(id.Vdc)
+	if err != nil {
+		return nil, err
+	}
+	if vdc.Vdc == nil || vdc.Vdc.HREF == "" {
+		return nil, fmt.Errorf("VDC %s not found in org %s", id.Vdc, id.Org)
+	}
+	resolved := &ResolvedImportId{Org: &org, Vdc: &vdc}
+	if id.VApp == "" {
+		return resolved, nil
+	}
+
+	vappHref := ""
+	for _, resourceEntities := range vdc.Vdc.ResourceEntities {
+		for _, resourceEntity := range resourceEntities.ResourceEntity {
+			if resourceEntity.Name == id.VApp && resourceEntity.Type == types.MimeVApp {
+				vappHref = resourceEntity.HREF
+			}
+		}
+	}
+	if vappHref == "" {
+		return nil, fmt.Errorf("vApp %s not found in VDC %s", id.VApp, id.Vdc)
+	}
+	vapp := NewVApp(&vcdClient.Client)
+	_, err = vcdClient.Client.ExecuteRequest(vappHref, http.MethodGet,
+		"", "error retrieving vApp: %s", nil, vapp.VApp)
+	if err != nil {
+		return nil, err
+	}
+	resolved.VApp = vapp
+	if id.VM == "" {
+		return resolved, nil
+	}
+
+	if vapp.VApp.Children != nil {
+		for _, child := range vapp.VApp.Children.VM {
+			if child.Name == id.VM {
+				vm := NewVM(&vcdClient.Client)
+				_, err = vcdClient.Client.ExecuteRequest(child.HREF, http.MethodGet,
+					"", "error retrieving VM: %s", nil, vm.VM)
+				if err != nil {
+					return nil, err
+				}
+				resolved.VM = vm
+				return resolved, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("VM %s not found in vApp %s", id.VM, id.VApp)
+}
+
+// urnUuid matches the UUID at the end of an HREF or URN
+var urnUuid = regexp.MustCompile(`([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})$`)
+
+// urnTypes maps the path of an entity HREF, and the prefix of its last element when
+// there is one, to the entity type in its URN
+var urnTypes = map[string]string{
+	"org":                       "org",
+	"vdc":                       "vdc",
+	"catalog":                   "catalog",
+	"catalogItem":               "catalogitem",
+	"network":                   "network",
+	"disk":                      "disk",
+	"media":                     "media",
+	"edgeGateway":               "gateway",
+	"user":                      "user",
+	"group":                     "group",
+	"task":                      "task",
+	"nsxtManagers":              "nsxtmanager",
+	"vApp/vapp":                 "vapp",
+	"vApp/vm":                   "vm",
+	"vAppTemplate/vappTemplate": "vapptemplate",
+	"vAppTemplate/vm":           "vm",
+}
+
+// HrefToUrn returns the stable URN of the entity at href, e.g. "urn:vcloud:vm:<uuid>" for
+// "https://vcd.example.com/api/vApp/vm-<uuid>". Admin HREFs give the same URN as their
+// non-admin counterparts.
+func HrefToUrn(href string) (string, error) {
+	parsedHref, err := url.Parse(href)
+	if err != nil {
+		return "", fmt.Errorf("error parsing HREF %s: %s", href, err)
+	}
+	elements := strings.Split(strings.Trim(parsedHref.Path, "/"), "/")
+	uuid := urnUuid.FindString(elements[len(elements)-1])
+	if len(elements) < 2 || uuid == "" {
+		return "", fmt.Errorf("HREF %s does not end with an entity ID", href)
+	}
+	key := elements[len(elements)-2]
+	if prefix := strings.TrimSuffix(elements[len(elements)-1], uuid); prefix != "" {
+		key += "/" + strings.TrimSuffix(prefix, "-")
+	}
+	entityType, ok := urnTypes[key]
+	if !ok {
+		return "", fmt.Errorf("unknown entity type in HREF %s", href)
+	}
+	return "urn:vcloud:" + entityType + ":" + strings.ToLower(uuid), nil
+}
+
+// ParseUrn returns the entity type and the UUID of a URN such as "urn:vcloud:vapp:<uuid>"
+func ParseUrn(urn string) (string, string, error) {
+	elements := strings.Split(urn, ":")
+	if len(elements) != 4 || elements[0] != "urn" || elements[1] != "vcloud" || elements[2] == "" ||
+		!urnUuid.MatchString(elements[3]) || len(elements[3]) != 36 {
+		return "", "", fmt.Errorf("invalid URN %s", urn)
+	}
+	return elements[2], strings.ToLower(elements[3]), nil
+}
+
+// entityUrn returns the ID of an entity when it is a URN, or the URN computed from its HREF
+func entityUrn(id, href string) (string, error) {
+	if _, _, err := ParseUrn(id); err == nil {
+		return strings.ToLower(id), nil
+	}
+	return HrefToUrn(href)
+}
@@ -0,0 +1,147 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// Checks that import IDs survive a compose/parse round trip, including escaped separators
+func TestParseImportId(t *testing.T) {
+	ids := []ImportId{
+		{Org: "org", Vdc: "vdc"},
+		{Org: "org", Vdc: "vdc", VApp: "vapp"},
+		{Org: "org", Vdc: "vdc", VApp: "my.vapp", VM: `back\slash`},
+	}
+	for _, id := range ids {
+		for _, separator := range []string{DefaultImportSeparator, "::"} {
+			composed := id.Compose(separator)
+			parsed, err := ParseImportId(composed, separator)
+			if err != nil {
+				t.Errorf("error parsing %s: %s", composed, err)
+				continue
+			}
+			if parsed != id {
+				t.Errorf("expected %#v from %s, got %#v", id, composed, parsed)
+			}
+		}
+	}
+	if composed := ids[2].String(); composed != `org.vdc.my\.vapp.back\\slash` {
+		t.Errorf("unexpected import ID %s", composed)
+	}
+
+	for _, invalid := range []string{"org", "org.vdc.vapp.vm.extra", "org..vapp", `org.vdc\x`} {
+		_, err := ParseImportId(invalid, DefaultImportSeparator)
+		if err == nil {
+			t.Errorf("expected error for import ID %s", invalid)
+		}
+	}
+}
+
+// Checks the URNs computed from HREFs
+func TestHrefToUrn(t *testing.T) {
+	uuid := "6a3a3c5f-2d95-4d1e-8b4d-1b2d3c4e5f60"
+	expected := map[string]string{
+		"https://vcd/api/org/" + uuid:                                           "urn:vcloud:org:" + uuid,
+		"https://vcd/api/admin/org/" + uuid:                                     "urn:vcloud:org:" + uuid,
+		"https://vcd/api/admin/vdc/" + uuid:                                     "urn:vcloud:vdc:" + uuid,
+		"https://vcd/api/vApp/vapp-" + uuid:                                     "urn:vcloud:vapp:" + uuid,
+		"https://vcd/api/vApp/vm-" + uuid:                                       "urn:vcloud:vm:" + uuid,
+		"https://vcd/api/vAppTemplate/vappTemplate-" + uuid:                     "urn:vcloud:vapptemplate:" + uuid,
+		"https://vcd/api/admin/network/" + uuid:                                 "urn:vcloud:network:" + uuid,
+		"https://vcd/api/admin/edgeGateway/" + uuid + "/":                       "urn:vcloud:gateway:" + uuid,
+		"https://vcd/api/catalogItem/" + "6A3A3C5F-2D95-4D1E-8B4D-1B2D3C4E5F60": "urn:vcloud:catalogitem:" + uuid,
+	}
+	for href, urn := range expected {
+		got, err := HrefToUrn(href)
+		if err != nil {
+			t.Errorf("error converting %s: %s", href, err)
+		} else if got != urn {
+			t.Errorf("expected %s from %s, got %s", urn, href, got)
+		}
+		entityType, entityUuid, err := ParseUrn(got)
+		if err != nil || entityUuid != uuid || entityType == "" {
+			t.Errorf("unexpected parsing of %s: %s %s %v", got, entityType, entityUuid, err)
+		}
+	}
+	for _, invalid := range []string{"https://vcd/api/org", "https://vcd/api/unknown/" + uuid, "https://vcd/api/vApp/" + uuid} {
+		_, err := HrefToUrn(invalid)
+		if err == nil {
+			t.Errorf("expected error for HREF %s", invalid)
+		}
+	}
+}
+
+const (
+	importVAppPath = "/api/vApp/vapp-55555555-5555-5555-5555-555555555555"
+	importVmPath   = "/api/vApp/vm-66666666-6666-6666-6666-666666666666"
+)
+
+const importVdcXml = `<?xml version="1.0" encoding="UTF-8"?>
+<Vdc xmlns="http://www.vmware.com/vcloud/v1.5" status="1" name="` + vcdtest.MockVdcName + `" id="urn:vcloud:vdc:` + vcdtest.MockVdcId + `" href="{{server}}` + vcdtest.MockVdcPath + `">
+  <ResourceEntities>
+    <ResourceEntity type="application/vnd.vmware.vcloud.vApp+xml" name="app" href="{{server}}` + importVAppPath + `"/>
+  </ResourceEntities>
+</Vdc>`
+
+const importVAppXml = `<?xml version="1.0" encoding="UTF-8"?>
+<VApp xmlns="http://www.vmware.com/vcloud/v1.5" status="8" name="app" href="{{server}}` + importVAppPath + `">
+  <Children>
+    <Vm status="8" name="web" href="{{server}}` + importVmPath + `"/>
+  </Children>
+</VApp>`
+
+const importVmXml = `<?xml version="1.0" encoding="UTF-8"?>
+<Vm xmlns="http://www.vmware.com/vcloud/v1.5" status="8" name="web" href="{{server}}` + importVmPath + `"/>`
+
+// Checks that an import ID is resolved with one GET per level
+func TestResolveImportId(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	server.HandleXML(http.MethodGet, vcdtest.MockVdcPath, http.StatusOK, importVdcXml)
+	server.HandleXML(http.MethodGet, importVAppPath, http.StatusOK, importVAppXml)
+	server.HandleXML(http.MethodGet, importVmPath, http.StatusOK, importVmXml)
+
+	vcdClient := newMockClient(t, server)
+
+	id, err := ParseImportId(vcdtest.MockOrgName+"."+vcdtest.MockVdcName+".app.web", DefaultImportSeparator)
+	if err != nil {
+		t.Fatalf("error parsing import ID: %s", err)
+	}
+	resolved, err := vcdClient.ResolveImportId(id)
+	if err != nil {
+		t.Fatalf("error resolving import ID: %s", err)
+	}
+	if resolved.Vdc.Vdc.Name != vcdtest.MockVdcName || resolved.VApp.VApp.Name != "app" || resolved.VM.VM.Name != "web" {
+		t.Errorf("unexpected entities resolved: %#v", resolved)
+	}
+	for _, path := range []string{vcdtest.MockVdcPath, importVAppPath, importVmPath} {
+		if requests := server.RequestsTo(http.MethodGet, path); len(requests) != 1 {
+			t.Errorf("expected 1 request to %s, got %d", path, len(requests))
+		}
+	}
+	urn, err := resolved.Urn()
+	if err != nil || urn != "urn:vcloud:vm:66666666-6666-6666-6666-666666666666" {
+		t.Errorf("unexpected URN %s (%v)", urn, err)
+	}
+	resolved.VM = nil
+	urn, err = resolved.Urn()
+	if err != nil || urn != "urn:vcloud:vapp:55555555-5555-5555-5555-555555555555" {
+		t.Errorf("unexpected URN %s (%v)", urn, err)
+	}
+
+	id.VM = "db"
+	_, err = vcdClient.ResolveImportId(id)
+	if err == nil {
+		t.Errorf("expected error for missing VM")
+	}
+	_, err = vcdClient.ResolveImportId(ImportId{Org: vcdtest.MockOrgName, Vdc: "missing"})
+	if err == nil {
+		t.Errorf("expected error for missing VDC")
+	}
+}
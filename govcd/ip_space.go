@@ -0,0 +1,481 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// IpSpace is an IP space (API 37.1+, vCD 10.4.1+): IP ranges and prefixes from which the orgs
+// allocate floating IP addresses and network prefixes, within quotas. Public and shared IP spaces are
+// managed by the provider, private ones by the org owning them. An IP space is used by the edge
+// gateways of an NSX-T backed external network once connected to it by an uplink (IpSpaceUplink).
+type IpSpace struct {
+	IpSpace *types.IpSpace
+	client  *Client
+}
+
+// GetAllIpSpaceSummaries retrieves the summaries of the IP spaces, without their ranges and
+// prefixes. Query parameters can be supplied to perform additional filtering (e.g. "filter" =>
+// "type==PUBLIC")
+func (client *Client) GetAllIpSpaceSummaries(queryParameters url.Values) ([]*types.IpSpace, error) {
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointIpSpaceSummaries
+	apiVersion, err := client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var summaries []*types.IpSpace
+	err = client.OpenApiGetAllItems(apiVersion, urlRef, queryParameters, &summaries)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving IP spaces: %s", err)
+	}
+	return summaries, nil
+}
+
+// GetIpSpaceByName retrieves the IP space with the given name
+func (client *Client) GetIpSpaceByName(name string) (*IpSpace, error) {
+	queryParams := url.Values{}
+	queryParams.Set("filter", fiqlEq("name", name))
+	summaries, err := client.GetAllIpSpaceSummaries(queryParams)
+	if err != nil {
+		return nil, err
+	}
+	if len(summaries) == 0 {
+		return nil, wrapErrorf(ErrorEntityNotFound, "IP space '%s' not found: %s", name, ErrorEntityNotFound)
+	}
+	if len(summaries) > 1 {
+		return nil, fmt.Errorf("more than one IP space found with name '%s'", name)
+	}
+	return client.GetIpSpaceById(summaries[0].ID)
+}
+
+// GetIpSpaceById retrieves the IP space with the given ID, with its ranges and prefixes
+func (client *Client) GetIpSpaceById(id string) (*IpSpace, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty IP space ID")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointIpSpaces
+	apiVersion, err := client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ipSpace := &IpSpace{IpSpace: &types.IpSpace{}, client: client}
+	err = client.OpenApiGetItem(apiVersion, urlRef, nil, ipSpace.IpSpace)
+	if err != nil {
+		return nil, err
+	}
+	return ipSpace, nil
+}
+
+// CreateIpSpace creates an IP space with the given internal scope, ranges and prefixes. A private IP
+// space needs the org owning it in OrgRef.
+func (client *Client) CreateIpSpace(ipSpaceConfig *types.IpSpace) (*IpSpace, error) {
+	err := validateIpSpace(ipSpaceConfig)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointIpSpaces
+	apiVersion, err := client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	ipSpace := &IpSpace{IpSpace: &types.IpSpace{}, client: client}
+	err = client.OpenApiPostItem(apiVersion, urlRef, nil, ipSpaceConfig, ipSpace.IpSpace)
+	if err != nil {
+		return nil, fmt.Errorf("error creating IP space: %s", err)
+	}
+	return ipSpace, nil
+}
+
+// Update sends the current definition of the IP space (scopes, ranges, prefixes and default
+// quotas) to vCD
+func (ipSpace *IpSpace) Update() error {
+	err := validateIpSpace(ipSpace.IpSpace)
+	if err != nil {
+		return err
+	}
+	urlRef, apiVersion, err := ipSpace.buildEndpoint(types.OpenApiEndpointIpSpaces, ipSpace.IpSpace.ID)
+	if err != nil {
+		return err
+	}
+
+	updated := &types.IpSpace{}
+	err = ipSpace.client.OpenApiPutItem(apiVersion, urlRef, nil, ipSpace.IpSpace, updated)
+	if err != nil {
+		return fmt.Errorf("error updating IP space: %s", err)
+	}
+	ipSpace.IpSpace = updated
+	return nil
+}
+
+// Delete removes the IP space. It fails while IP addresses or prefixes are allocated from it.
+func (ipSpace *IpSpace) Delete() error {
+	urlRef, apiVersion, err := ipSpace.buildEndpoint(types.OpenApiEndpointIpSpaces, ipSpace.IpSpace.ID)
+	if err != nil {
+		return err
+	}
+	err = ipSpace.client.OpenApiDeleteItem(apiVersion, urlRef, nil)
+	if err != nil {
+		return fmt.Errorf("error deleting IP space: %s", err)
+	}
+	return nil
+}
+
+// AllocateIp allocates floating IP addresses or prefixes of the IP space to the org with the given
+// ID, and returns them once the allocation task is complete
+func (ipSpace *IpSpace) AllocateIp(orgId string, request *types.IpSpaceIpAllocationRequest) ([]types.IpSpaceIpAllocationRequestResult, error) {
+	err := validateIpSpaceIpAllocationRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	_, orgUuid, err := ParseUrn(orgId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid org ID: %s", err)
+	}
+	urlRef, apiVersion, err := ipSpace.buildEndpoint(types.OpenApiEndpointIpSpaceAllocate)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []types.IpSpaceIpAllocationRequestResult
+	err = ipSpace.client.withTenantContext(orgUuid).openApiPostItemForTaskResult(apiVersion, urlRef, nil, request, &results)
+	if err != nil {
+		return nil, fmt.Errorf("error allocating %s from IP space %s: %s", request.Type, ipSpace.IpSpace.Name, err)
+	}
+	return results, nil
+}
+
+// AllocateIpSpaceIp allocates floating IP addresses or prefixes of the IP space with the given ID to
+// the org of the edge gateway, for its NAT rules or its routed networks. The IP space must be
+// connected by an uplink to the external network of the edge gateway.
+func (egw *NsxtEdgeGateway) AllocateIpSpaceIp(ipSpaceId string, request *types.IpSpaceIpAllocationRequest) ([]types.IpSpaceIpAllocationRequestResult, error) {
+	if egw.NsxtEdgeGateway.Org == nil || egw.NsxtEdgeGateway.Org.ID == "" {
+		return nil, fmt.Errorf("NSX-T edge gateway %s has no org", egw.NsxtEdgeGateway.Name)
+	}
+	ipSpace := &IpSpace{IpSpace: &types.IpSpace{ID: ipSpaceId}, client: egw.client}
+	return ipSpace.AllocateIp(egw.NsxtEdgeGateway.Org.ID, request)
+}
+
+// GetAllIpAllocations retrieves the allocations of the IP space of the given type
+// (types.IpSpaceAllocationFloatingIp or types.IpSpaceAllocationIpPrefix). Query parameters can be
+// supplied to perform additional filtering (e.g. "filter" => "orgRef.id==...")
+func (ipSpace *IpSpace) GetAllIpAllocations(allocationType string, queryParameters url.Values) ([]*types.IpSpaceIpAllocation, error) {
+	urlRef, apiVersion, err := ipSpace.buildEndpoint(types.OpenApiEndpointIpSpaceAllocations)
+	if err != nil {
+		return nil, err
+	}
+	var allocations []*types.IpSpaceIpAllocation
+	err = ipSpace.client.OpenApiGetAllItems(apiVersion, urlRef,
+		queryParameterFilterAnd(fiqlEq("type", allocationType), queryParameters), &allocations)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving allocations of IP space: %s", err)
+	}
+	return allocations, nil
+}
+
+// GetIpAllocationByValue retrieves the allocation of the IP space of the given type with the given
+// value: an IP address or a prefix in CIDR notation
+func (ipSpace *IpSpace) GetIpAllocationByValue(allocationType, value string) (*types.IpSpaceIpAllocation, error) {
+	queryParams := url.Values{}
+	queryParams.Set("filter", fiqlEq("value", value))
+	allocations, err := ipSpace.GetAllIpAllocations(allocationType, queryParams)
+	if err != nil {
+		return nil, err
+	}
+	if len(allocations) == 0 {
+		return nil, wrapErrorf(ErrorEntityNotFound, "allocation of %s '%s' not found in IP space %s: %s",
+			allocationType, value, ipSpace.IpSpace.Name, ErrorEntityNotFound)
+	}
+	return allocations[0], nil
+}
+
+// UpdateIpAllocation sets the usage state and the description of an allocation of the IP space.
+// Only the unused allocations can be reserved (types.IpSpaceAllocationUsedManual) or freed
+// (types.IpSpaceAllocationUnused); the ones used by vCD entities are managed by vCD.
+func (ipSpace *IpSpace) UpdateIpAllocation(allocation *types.IpSpaceIpAllocation) (*types.IpSpaceIpAllocation, error) {
+	if allocation == nil || allocation.ID == "" {
+		return nil, fmt.Errorf("cannot update IP space allocation without ID")
+	}
+	switch allocation.UsageState {
+	case types.IpSpaceAllocationUnused, types.IpSpaceAllocationUsedManual:
+	default:
+		return nil, fmt.Errorf("invalid usage state '%s' for allocation %s", allocation.UsageState, allocation.Value)
+	}
+	urlRef, apiVersion, err := ipSpace.buildEndpoint(types.OpenApiEndpointIpSpaceAllocations, allocation.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := &types.IpSpaceIpAllocation{}
+	err = ipSpace.client.OpenApiPutItem(apiVersion, urlRef, nil, allocation, updated)
+	if err != nil {
+		return nil, fmt.Errorf("error updating allocation %s of IP space: %s", allocation.Value, err)
+	}
+	return updated, nil
+}
+
+// ReleaseIpAllocation gives back to the IP space the IP address or prefix of the allocation with
+// the given ID
+func (ipSpace *IpSpace) ReleaseIpAllocation(allocationId string) error {
+	if allocationId == "" {
+		return fmt.Errorf("empty IP space allocation ID")
+	}
+	urlRef, apiVersion, err := ipSpace.buildEndpoint(types.OpenApiEndpointIpSpaceAllocations, allocationId)
+	if err != nil {
+		return err
+	}
+	err = ipSpace.client.OpenApiDeleteItem(apiVersion, urlRef, nil)
+	if err != nil {
+		return fmt.Errorf("error releasing allocation %s of IP space: %s", allocationId, err)
+	}
+	return nil
+}
+
+// GetAllOrgAssignments retrieves the orgs using the IP space, with their quotas. Query parameters
+// can be supplied to perform additional filtering.
+func (ipSpace *IpSpace) GetAllOrgAssignments(queryParameters url.Values) ([]*types.IpSpaceOrgAssignment, error) {
+	urlRef, apiVersion, err := ipSpace.buildEndpoint(types.OpenApiEndpointIpSpaceOrgAssignments)
+	if err != nil {
+		return nil, err
+	}
+	var assignments []*types.IpSpaceOrgAssignment
+	err = ipSpace.client.OpenApiGetAllItems(apiVersion, urlRef, queryParameters, &assignments)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving org assignments of IP space: %s", err)
+	}
+	return assignments, nil
+}
+
+// SetOrgQuotas sets the custom quotas of the org with the given ID in the IP space, which override
+// the default quotas of the IP space. The prefix quotas must refer to prefix lengths of the IP space.
+func (ipSpace *IpSpace) SetOrgQuotas(orgId string, quotas *types.IpSpaceOrgAssignmentQuotas) (*types.IpSpaceOrgAssignment, error) {
+	if orgId == "" {
+		return nil, fmt.Errorf("empty org ID")
+	}
+	err := validateIpSpaceQuotas(ipSpace.IpSpace, quotas)
+	if err != nil {
+		return nil, err
+	}
+	queryParams := url.Values{}
+	queryParams.Set("filter", fiqlEq("orgRef.id", orgId))
+	assignments, err := ipSpace.GetAllOrgAssignments(queryParams)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := &types.IpSpaceOrgAssignment{}
+	if len(assignments) == 0 {
+		// The org has not used the IP space yet
+		urlRef, apiVersion, err := ipSpace.buildEndpoint(types.OpenApiEndpointIpSpaceOrgAssignments)
+		if err != nil {
+			return nil, err
+		}
+		payload := &types.IpSpaceOrgAssignment{
+			IPSpaceRef:   &types.OpenApiReference{ID: ipSpace.IpSpace.ID},
+			OrgRef:       &types.OpenApiReference{ID: orgId},
+			CustomQuotas: quotas,
+		}
+		err = ipSpace.client.OpenApiPostItem(apiVersion, urlRef, nil, payload, updated)
+		if err != nil {
+			return nil, fmt.Errorf("error setting quotas of org in IP space %s: %s", ipSpace.IpSpace.Name, err)
+		}
+		return updated, nil
+	}
+
+	payload := *assignments[0]
+	payload.CustomQuotas = quotas
+	urlRef, apiVersion, err := ipSpace.buildEndpoint(types.OpenApiEndpointIpSpaceOrgAssignments, payload.ID)
+	if err != nil {
+		return nil, err
+	}
+	err = ipSpace.client.OpenApiPutItem(apiVersion, urlRef, nil, &payload, updated)
+	if err != nil {
+		return nil, fmt.Errorf("error setting quotas of org in IP space %s: %s", ipSpace.IpSpace.Name, err)
+	}
+	return updated, nil
+}
+
+// buildEndpoint returns the URL of an endpoint of the IP space, formatted with its ID when needed
+// and followed by suffix, with the API version to use
+func (ipSpace *IpSpace) buildEndpoint(endpointFormat string, suffix ...string) (*url.URL, string, error) {
+	if ipSpace.IpSpace.ID == "" {
+		return nil, "", fmt.Errorf("IP space %s has no ID", ipSpace.IpSpace.Name)
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + endpointFormat
+	apiVersion, err := ipSpace.client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, "", err
+	}
+	if strings.Contains(endpointFormat, "%s") {
+		endpoint = fmt.Sprintf(endpoint, ipSpace.IpSpace.ID)
+	}
+	urlRef, err := ipSpace.client.OpenApiBuildEndpoint(append([]string{endpoint}, suffix...)...)
+	if err != nil {
+		return nil, "", err
+	}
+	return urlRef, apiVersion, nil
+}
+
+// validateIpSpace checks that the ranges and prefixes of the IP space belong to its internal scope
+func validateIpSpace(ipSpaceConfig *types.IpSpace) error {
+	if ipSpaceConfig == nil || ipSpaceConfig.Name == "" {
+		return fmt.Errorf("IP space name is required")
+	}
+	switch ipSpaceConfig.Type {
+	case types.IpSpacePublic, types.IpSpaceShared:
+	case types.IpSpacePrivate:
+		if ipSpaceConfig.OrgRef == nil || ipSpaceConfig.OrgRef.ID == "" {
+			return fmt.Errorf("private IP space %s needs an org in OrgRef", ipSpaceConfig.Name)
+		}
+	default:
+		return fmt.Errorf("invalid type '%s' for IP space %s", ipSpaceConfig.Type, ipSpaceConfig.Name)
+	}
+	if len(ipSpaceConfig.IPSpaceInternalScope) == 0 {
+		return fmt.Errorf("IP space %s needs an internal scope", ipSpaceConfig.Name)
+	}
+	var scope []*net.IPNet
+	for _, cidr := range ipSpaceConfig.IPSpaceInternalScope {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return fmt.Errorf("invalid internal scope '%s' of IP space %s", cidr, ipSpaceConfig.Name)
+		}
+		scope = append(scope, ipNet)
+	}
+	inScope := func(first, last net.IP) bool {
+		for _, ipNet := range scope {
+			if ipNet.Contains(first) && ipNet.Contains(last) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ipSpaceConfig.IPSpaceRanges.DefaultFloatingIPQuota < -1 {
+		return fmt.Errorf("invalid default floating IP quota %d of IP space %s", ipSpaceConfig.IPSpaceRanges.DefaultFloatingIPQuota,
+			ipSpaceConfig.Name)
+	}
+	for _, ipRange := range ipSpaceConfig.IPSpaceRanges.IPRanges {
+		start, end, err := parseIpRange(types.OpenApiIPRange{StartAddress: ipRange.StartIPAddress, EndAddress: ipRange.EndIPAddress})
+		if err != nil {
+			return err
+		}
+		if !inScope(start, end) {
+			return fmt.Errorf("IP range %s-%s is outside of the internal scope of IP space %s",
+				ipRange.StartIPAddress, ipRange.EndIPAddress, ipSpaceConfig.Name)
+		}
+	}
+
+	for _, prefixes := range ipSpaceConfig.IPSpacePrefixes {
+		if prefixes.DefaultQuotaForPrefixLength < -1 {
+			return fmt.Errorf("invalid default prefix quota %d of IP space %s", prefixes.DefaultQuotaForPrefixLength, ipSpaceConfig.Name)
+		}
+		for _, sequence := range prefixes.IPPrefixSequence {
+			if sequence.PrefixLength != prefixes.IPPrefixSequence[0].PrefixLength {
+				return fmt.Errorf("the prefix sequences sharing a quota in IP space %s must have the same prefix length",
+					ipSpaceConfig.Name)
+			}
+			prefix := fmt.Sprintf("%s/%d", sequence.StartingPrefixIPAddress, sequence.PrefixLength)
+			ip, ipNet, err := net.ParseCIDR(prefix)
+			if err != nil || !ip.Equal(ipNet.IP) || sequence.TotalPrefixCount < 1 {
+				return fmt.Errorf("invalid prefix sequence of %d prefixes starting at %s in IP space %s",
+					sequence.TotalPrefixCount, prefix, ipSpaceConfig.Name)
+			}
+			last := ipNet.IP.To16()
+			bits := len(ipNet.Mask) * 8
+			for i := 0; i < sequence.TotalPrefixCount; i++ {
+				last = lastIpOfPrefix(last, bits-sequence.PrefixLength)
+				if i < sequence.TotalPrefixCount-1 {
+					last = nextIp(last)
+				}
+			}
+			if !inScope(ipNet.IP, last) {
+				return fmt.Errorf("prefix sequence starting at %s is outside of the internal scope of IP space %s",
+					prefix, ipSpaceConfig.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// lastIpOfPrefix returns the last address of the prefix starting at ip, whose host part has the
+// given number of bits
+func lastIpOfPrefix(ip net.IP, hostBits int) net.IP {
+	last := make(net.IP, len(ip))
+	copy(last, ip)
+	for index := len(last) - 1; index >= 0 && hostBits > 0; index-- {
+		if hostBits >= 8 {
+			last[index] = 0xff
+		} else {
+			last[index] |= byte(1<<uint(hostBits)) - 1
+		}
+		hostBits -= 8
+	}
+	return last
+}
+
+// validateIpSpaceIpAllocationRequest checks that the request asks for a quantity or a value
+func validateIpSpaceIpAllocationRequest(request *types.IpSpaceIpAllocationRequest) error {
+	if request == nil {
+		return fmt.Errorf("no IP space allocation request given")
+	}
+	switch request.Type {
+	case types.IpSpaceAllocationFloatingIp:
+	case types.IpSpaceAllocationIpPrefix:
+		if request.Value == "" && request.PrefixLength == nil {
+			return fmt.Errorf("the allocation of prefixes needs a prefix length")
+		}
+	default:
+		return fmt.Errorf("invalid IP space allocation type '%s'", request.Type)
+	}
+	if (request.Quantity == nil) == (request.Value == "") {
+		return fmt.Errorf("an IP space allocation request needs either a quantity or a value")
+	}
+	if request.Quantity != nil && *request.Quantity < 1 {
+		return fmt.Errorf("invalid quantity %d in IP space allocation request", *request.Quantity)
+	}
+	return nil
+}
+
+// validateIpSpaceQuotas checks that the prefix quotas refer to prefix lengths of the IP space
+func validateIpSpaceQuotas(ipSpace *types.IpSpace, quotas *types.IpSpaceOrgAssignmentQuotas) error {
+	if quotas == nil {
+		return fmt.Errorf("no IP space quotas given")
+	}
+	if quotas.FloatingIPQuota != nil && *quotas.FloatingIPQuota < -1 {
+		return fmt.Errorf("invalid floating IP quota %d", *quotas.FloatingIPQuota)
+	}
+	for _, quota := range quotas.IPPrefixQuotas {
+		found := false
+		for _, prefixes := range ipSpace.IPSpacePrefixes {
+			for _, sequence := range prefixes.IPPrefixSequence {
+				found = found || sequence.PrefixLength == quota.PrefixLength
+			}
+		}
+		if !found || quota.Quota < -1 {
+			return fmt.Errorf("invalid quota %d for prefixes of length %d in IP space %s", quota.Quota, quota.PrefixLength, ipSpace.Name)
+		}
+	}
+	return nil
+}
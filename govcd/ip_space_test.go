@@ -0,0 +1,206 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// Checks the creation of an IP space, the allocation of its addresses and prefixes to an org and
+// to the org of an edge gateway, and the quotas of the org against a fake vCD
+func TestIpSpace(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	const ipSpaceId = "urn:vcloud:ipSpace:55555555-5555-5555-5555-555555555555"
+	const orgId = "urn:vcloud:org:" + vcdtest.MockOrgId
+	const ipSpacesPath = "/cloudapi/1.0.0/ipSpaces/"
+	const allocationsPath = ipSpacesPath + ipSpaceId + "/allocations/"
+	const assignmentsPath = ipSpacesPath + ipSpaceId + "/orgAssignments/"
+	ipSpaceJson := `{"id":"` + ipSpaceId + `","name":"public1","type":"PUBLIC","ipSpaceInternalScope":["10.10.0.0/16"],
+		"ipSpaceRanges":{"ipRanges":[{"id":"range-1","startIPAddress":"10.10.0.10","endIPAddress":"10.10.0.100"}],"defaultFloatingIPQuota":5},
+		"ipSpacePrefixes":[{"ipPrefixSequence":[{"id":"seq-1","startingPrefixIpAddress":"10.10.128.0","prefixLength":24,"totalPrefixCount":8}],
+		"defaultQuotaForPrefixLength":2}],"routeAdvertisementEnabled":false}`
+	server.HandleJSON(http.MethodPost, ipSpacesPath, http.StatusCreated, ipSpaceJson)
+	server.HandleJSON(http.MethodGet, ipSpacesPath+"summaries", http.StatusOK,
+		`{"resultTotal":1,"pageCount":1,"page":1,"pageSize":128,"values":[{"id":"`+ipSpaceId+`","name":"public1","type":"PUBLIC"}]}`)
+	server.HandleJSON(http.MethodGet, ipSpacesPath+ipSpaceId, http.StatusOK, ipSpaceJson)
+	server.Handle(http.MethodPost, ipSpacesPath+ipSpaceId+"/allocate", vcdtest.Response{
+		Status: http.StatusAccepted,
+		Header: map[string]string{"Location": "{{server}}" + vcdtest.MockTaskPath},
+	})
+	server.HandleXML(http.MethodGet, vcdtest.MockTaskPath, http.StatusOK,
+		`<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="success" href="{{server}}`+vcdtest.MockTaskPath+`">
+		  <Result><ResultContent>[{"id":"alloc-1","value":"10.10.128.0/24","suggested":false}]</ResultContent></Result>
+		</Task>`)
+	server.HandleJSON(http.MethodGet, allocationsPath, http.StatusOK,
+		`{"resultTotal":1,"pageCount":1,"page":1,"pageSize":128,"values":[{"id":"alloc-1","type":"IP_PREFIX",
+		"value":"10.10.128.0/24","usageState":"UNUSED","orgRef":{"id":"`+orgId+`"}}]}`)
+	server.HandleJSON(http.MethodPut, allocationsPath+"alloc-1", http.StatusOK,
+		`{"id":"alloc-1","type":"IP_PREFIX","value":"10.10.128.0/24","usageState":"USED_MANUAL","description":"lab"}`)
+	server.Handle(http.MethodDelete, allocationsPath+"alloc-1", vcdtest.Response{Status: http.StatusNoContent})
+	server.HandleJSON(http.MethodGet, assignmentsPath, http.StatusOK, `{"resultTotal":0,"pageCount":0,"page":1,"pageSize":128,"values":[]}`)
+	server.HandleJSON(http.MethodPost, assignmentsPath, http.StatusCreated,
+		`{"id":"assignment-1","ipSpaceRef":{"id":"`+ipSpaceId+`"},"orgRef":{"id":"`+orgId+`"},"customQuotas":{"floatingIPQuota":10}}`)
+
+	vcdClient := newMockClient(t, server)
+	client := &vcdClient.Client
+
+	config := &types.IpSpace{
+		Name:                 "public1",
+		Type:                 types.IpSpacePublic,
+		IPSpaceInternalScope: []string{"10.10.0.0/16"},
+		IPSpaceRanges: types.IpSpaceRanges{
+			IPRanges:               []types.IpSpaceRange{{StartIPAddress: "10.10.0.10", EndIPAddress: "10.10.0.100"}},
+			DefaultFloatingIPQuota: 5,
+		},
+		IPSpacePrefixes: []types.IpSpacePrefixes{{
+			IPPrefixSequence:            []types.IpSpacePrefixSequence{{StartingPrefixIPAddress: "10.10.128.0", PrefixLength: 24, TotalPrefixCount: 8}},
+			DefaultQuotaForPrefixLength: 2,
+		}},
+	}
+	invalid := map[string]func(ipSpace *types.IpSpace){
+		"private without org": func(ipSpace *types.IpSpace) { ipSpace.Type = types.IpSpacePrivate },
+		"range out of scope":  func(ipSpace *types.IpSpace) { ipSpace.IPSpaceRanges.IPRanges[0].EndIPAddress = "10.11.0.1" },
+		"misaligned prefix": func(ipSpace *types.IpSpace) {
+			ipSpace.IPSpacePrefixes[0].IPPrefixSequence[0].StartingPrefixIPAddress = "10.10.128.1"
+		},
+		"prefixes past their scope": func(ipSpace *types.IpSpace) { ipSpace.IPSpacePrefixes[0].IPPrefixSequence[0].TotalPrefixCount = 200 },
+	}
+	for name, change := range invalid {
+		invalidConfig := *config
+		invalidConfig.IPSpaceRanges.IPRanges = []types.IpSpaceRange{config.IPSpaceRanges.IPRanges[0]}
+		invalidConfig.IPSpacePrefixes = []types.IpSpacePrefixes{{IPPrefixSequence: []types.IpSpacePrefixSequence{
+			config.IPSpacePrefixes[0].IPPrefixSequence[0]}}}
+		change(&invalidConfig)
+		if _, err := client.CreateIpSpace(&invalidConfig); err == nil {
+			t.Errorf("expected error creating IP space with %s", name)
+		}
+	}
+	if len(server.RequestsTo(http.MethodPost, ipSpacesPath)) != 0 {
+		t.Errorf("unexpected creation of an invalid IP space")
+	}
+
+	created, err := client.CreateIpSpace(config)
+	if err != nil {
+		t.Fatalf("error creating IP space: %s", err)
+	}
+	if created.IpSpace.ID != ipSpaceId {
+		t.Errorf("unexpected IP space: %+v", created.IpSpace)
+	}
+	ipSpace, err := client.GetIpSpaceByName("public1")
+	if err != nil {
+		t.Fatalf("error retrieving IP space by name: %s", err)
+	}
+	if len(ipSpace.IpSpace.IPSpacePrefixes) != 1 || ipSpace.IpSpace.IPSpaceRanges.DefaultFloatingIPQuota != 5 {
+		t.Errorf("expected the IP space with its ranges and prefixes, got %+v", ipSpace.IpSpace)
+	}
+
+	// Allocations
+	quantity, prefixLength := 1, 24
+	prefixRequest := &types.IpSpaceIpAllocationRequest{Type: types.IpSpaceAllocationIpPrefix, Quantity: &quantity, PrefixLength: &prefixLength}
+	invalidRequests := []*types.IpSpaceIpAllocationRequest{nil, {Type: "IP"},
+		{Type: types.IpSpaceAllocationFloatingIp}, {Type: types.IpSpaceAllocationIpPrefix, Quantity: &quantity}}
+	for _, request := range invalidRequests {
+		if _, err = ipSpace.AllocateIp(orgId, request); err == nil {
+			t.Errorf("expected error with allocation request %+v", request)
+		}
+	}
+	results, err := ipSpace.AllocateIp(orgId, prefixRequest)
+	if err != nil {
+		t.Fatalf("error allocating prefix: %s", err)
+	}
+	if len(results) != 1 || results[0].Value != "10.10.128.0/24" {
+		t.Errorf("unexpected allocation results: %+v", results)
+	}
+	edge := &NsxtEdgeGateway{NsxtEdgeGateway: &types.NsxtEdgeGateway{Name: "edge1", Org: &types.OpenApiReference{ID: orgId}},
+		client: client}
+	_, err = edge.AllocateIpSpaceIp(ipSpaceId, &types.IpSpaceIpAllocationRequest{Type: types.IpSpaceAllocationFloatingIp, Value: "10.10.0.20"})
+	if err != nil {
+		t.Fatalf("error allocating floating IP to the edge gateway: %s", err)
+	}
+	posts := server.RequestsTo(http.MethodPost, ipSpacesPath+ipSpaceId+"/allocate")
+	if len(posts) != 2 || posts[0].Header.Get("X-VMWARE-VCLOUD-TENANT-CONTEXT") != vcdtest.MockOrgId ||
+		posts[1].Header.Get("X-VMWARE-VCLOUD-TENANT-CONTEXT") != vcdtest.MockOrgId ||
+		!strings.Contains(posts[1].Body, `"value": "10.10.0.20"`) {
+		t.Errorf("unexpected allocation requests: %#v", posts)
+	}
+
+	allocation, err := ipSpace.GetIpAllocationByValue(types.IpSpaceAllocationIpPrefix, "10.10.128.0/24")
+	if err != nil {
+		t.Fatalf("error retrieving allocation: %s", err)
+	}
+	gets := server.RequestsTo(http.MethodGet, allocationsPath)
+	query, _ := url.ParseQuery(gets[len(gets)-1].RawQuery)
+	if allocation.ID != "alloc-1" || query.Get("filter") != "value==10.10.128.0/24;type==IP_PREFIX" {
+		t.Errorf("unexpected allocation %+v with filter %s", allocation, query.Get("filter"))
+	}
+	allocation.UsageState = types.IpSpaceAllocationUsed
+	if _, err = ipSpace.UpdateIpAllocation(allocation); err == nil {
+		t.Errorf("expected error marking an allocation as used by a vCD entity")
+	}
+	allocation.UsageState = types.IpSpaceAllocationUsedManual
+	allocation.Description = "lab"
+	allocation, err = ipSpace.UpdateIpAllocation(allocation)
+	if err != nil {
+		t.Fatalf("error updating allocation: %s", err)
+	}
+	if allocation.UsageState != types.IpSpaceAllocationUsedManual {
+		t.Errorf("unexpected allocation: %+v", allocation)
+	}
+	err = ipSpace.ReleaseIpAllocation(allocation.ID)
+	if err != nil {
+		t.Fatalf("error releasing allocation: %s", err)
+	}
+	if len(server.RequestsTo(http.MethodDelete, allocationsPath+"alloc-1")) != 1 {
+		t.Errorf("expected the allocation to be released")
+	}
+
+	// Quotas
+	floatingIpQuota := 10
+	if _, err = ipSpace.SetOrgQuotas(orgId, &types.IpSpaceOrgAssignmentQuotas{
+		IPPrefixQuotas: []types.IpSpacePrefixQuota{{PrefixLength: 28, Quota: 4}}}); err == nil {
+		t.Errorf("expected error setting a quota for a prefix length missing from the IP space")
+	}
+	assignment, err := ipSpace.SetOrgQuotas(orgId, &types.IpSpaceOrgAssignmentQuotas{FloatingIPQuota: &floatingIpQuota,
+		IPPrefixQuotas: []types.IpSpacePrefixQuota{{PrefixLength: 24, Quota: 4}}})
+	if err != nil {
+		t.Fatalf("error setting org quotas: %s", err)
+	}
+	if assignment.ID != "assignment-1" {
+		t.Errorf("unexpected org assignment: %+v", assignment)
+	}
+	server.HandleJSON(http.MethodGet, assignmentsPath, http.StatusOK,
+		`{"resultTotal":1,"pageCount":1,"page":1,"pageSize":128,"values":[{"id":"assignment-1","ipSpaceRef":{"id":"`+ipSpaceId+`"},
+		"orgRef":{"id":"`+orgId+`"},"defaultQuotas":{"floatingIPQuota":5}}]}`)
+	server.HandleJSON(http.MethodPut, assignmentsPath+"assignment-1", http.StatusOK,
+		`{"id":"assignment-1","ipSpaceRef":{"id":"`+ipSpaceId+`"},"orgRef":{"id":"`+orgId+`"},"customQuotas":{"floatingIPQuota":-1}}`)
+	unlimited := -1
+	_, err = ipSpace.SetOrgQuotas(orgId, &types.IpSpaceOrgAssignmentQuotas{FloatingIPQuota: &unlimited})
+	if err != nil {
+		t.Fatalf("error updating org quotas: %s", err)
+	}
+	assignmentPosts := server.RequestsTo(http.MethodPost, assignmentsPath)
+	puts := server.RequestsTo(http.MethodPut, assignmentsPath+"assignment-1")
+	var sent types.IpSpaceOrgAssignment
+	if len(assignmentPosts) != 1 || len(puts) != 1 || json.Unmarshal([]byte(puts[0].Body), &sent) != nil ||
+		sent.CustomQuotas == nil || *sent.CustomQuotas.FloatingIPQuota != -1 || sent.OrgRef.ID != orgId {
+		t.Errorf("unexpected org assignment requests: %#v %#v", assignmentPosts, puts)
+	}
+
+	server.HandleJSON(http.MethodGet, ipSpacesPath+"summaries", http.StatusOK, `{"resultTotal":0,"pageCount":0,"page":1,"pageSize":128,"values":[]}`)
+	_, err = client.GetIpSpaceByName("private1")
+	if !errors.Is(err, ErrorEntityNotFound) {
+		t.Errorf("expected entity not found error, got %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// IpSpaceUplink connects an IP space to an NSX-T backed external network (API 37.1+), so that the
+// edge gateways connected to the external network allocate their IP addresses from the IP space.
+// Uplinks are only available to system administrators.
+type IpSpaceUplink struct {
+	IpSpaceUplink *types.IpSpaceUplink
+	client        *Client
+}
+
+// GetAllIpSpaceUplinks retrieves the IP space uplinks of the external network with the given ID.
+// Query parameters can be supplied to perform additional filtering.
+func (client *Client) GetAllIpSpaceUplinks(externalNetworkId string, queryParameters url.Values) ([]*IpSpaceUplink, error) {
+	if externalNetworkId == "" {
+		return nil, fmt.Errorf("empty external network ID")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointIpSpaceUplinks
+	apiVersion, err := client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var typeResponses []*types.IpSpaceUplink
+	err = client.OpenApiGetAllItems(apiVersion, urlRef,
+		queryParameterFilterAnd(fiqlEq("externalNetworkRef.id", externalNetworkId), queryParameters), &typeResponses)
+	if err != nil {
+		return nil, err
+	}
+
+	uplinks := make([]*IpSpaceUplink, len(typeResponses))
+	for index, typeResponse := range typeResponses {
+		uplinks[index] = &IpSpaceUplink{IpSpaceUplink: typeResponse, client: client}
+	}
+	return uplinks, nil
+}
+
+// GetIpSpaceUplinkByName retrieves the IP space uplink of the external network with the given ID
+// and name
+func (client *Client) GetIpSpaceUplinkByName(externalNetworkId, name string) (*IpSpaceUplink, error) {
+	queryParams := url.Values{}
+	queryParams.Set("filter", fiqlEq("name", name))
+	uplinks, err := client.GetAllIpSpaceUplinks(externalNetworkId, queryParams)
+	if err != nil {
+		return nil, err
+	}
+	if len(uplinks) == 0 {
+		return nil, wrapErrorf(ErrorEntityNotFound, "IP space uplink '%s' not found: %s", name, ErrorEntityNotFound)
+	}
+	if len(uplinks) > 1 {
+		return nil, fmt.Errorf("more than one IP space uplink found with name '%s'", name)
+	}
+	return uplinks[0], nil
+}
+
+// CreateIpSpaceUplink connects the IP space of IPSpaceRef to the external network of
+// ExternalNetworkRef
+func (client *Client) CreateIpSpaceUplink(uplinkConfig *types.IpSpaceUplink) (*IpSpaceUplink, error) {
+	err := validateIpSpaceUplink(uplinkConfig)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointIpSpaceUplinks
+	apiVersion, err := client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	uplink := &IpSpaceUplink{IpSpaceUplink: &types.IpSpaceUplink{}, client: client}
+	err = client.OpenApiPostItem(apiVersion, urlRef, nil, uplinkConfig, uplink.IpSpaceUplink)
+	if err != nil {
+		return nil, fmt.Errorf("error creating IP space uplink: %s", err)
+	}
+	return uplink, nil
+}
+
+// Update sends the current definition of the IP space uplink (name and description) to vCD
+func (uplink *IpSpaceUplink) Update() error {
+	if uplink.IpSpaceUplink.ID == "" {
+		return fmt.Errorf("cannot update IP space uplink without ID")
+	}
+	err := validateIpSpaceUplink(uplink.IpSpaceUplink)
+	if err != nil {
+		return err
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointIpSpaceUplinks
+	apiVersion, err := uplink.client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return err
+	}
+	urlRef, err := uplink.client.OpenApiBuildEndpoint(endpoint, uplink.IpSpaceUplink.ID)
+	if err != nil {
+		return err
+	}
+
+	updated := &types.IpSpaceUplink{}
+	err = uplink.client.OpenApiPutItem(apiVersion, urlRef, nil, uplink.IpSpaceUplink, updated)
+	if err != nil {
+		return fmt.Errorf("error updating IP space uplink: %s", err)
+	}
+	uplink.IpSpaceUplink = updated
+	return nil
+}
+
+// Delete disconnects the IP space from the external network
+func (uplink *IpSpaceUplink) Delete() error {
+	if uplink.IpSpaceUplink.ID == "" {
+		return fmt.Errorf("cannot delete IP space uplink without ID")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointIpSpaceUplinks
+	apiVersion, err := uplink.client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return err
+	}
+	urlRef, err := uplink.client.OpenApiBuildEndpoint(endpoint, uplink.IpSpaceUplink.ID)
+	if err != nil {
+		return err
+	}
+	err = uplink.client.OpenApiDeleteItem(apiVersion, urlRef, nil)
+	if err != nil {
+		return fmt.Errorf("error deleting IP space uplink: %s", err)
+	}
+	return nil
+}
+
+// validateIpSpaceUplink checks the fields needed to create or update an IP space uplink
+func validateIpSpaceUplink(uplinkConfig *types.IpSpaceUplink) error {
+	if uplinkConfig == nil || uplinkConfig.Name == "" {
+		return fmt.Errorf("IP space uplink name is required")
+	}
+	if uplinkConfig.ExternalNetworkRef == nil || uplinkConfig.ExternalNetworkRef.ID == "" {
+		return fmt.Errorf("IP space uplink %s needs an external network", uplinkConfig.Name)
+	}
+	if uplinkConfig.IPSpaceRef == nil || uplinkConfig.IPSpaceRef.ID == "" {
+		return fmt.Errorf("IP space uplink %s needs an IP space", uplinkConfig.Name)
+	}
+	return nil
+}
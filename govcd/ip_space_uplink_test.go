@@ -0,0 +1,79 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// Checks the connection of an IP space to an external network against a fake vCD
+func TestIpSpaceUplink(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	const ipSpaceId = "urn:vcloud:ipSpace:55555555-5555-5555-5555-555555555555"
+	const networkId = "urn:vcloud:network:22222222-2222-2222-2222-222222222222"
+	const uplinksPath = "/cloudapi/1.0.0/ipSpaceUplinks/"
+	uplinkJson := `{"id":"uplink-1","name":"uplink1","externalNetworkRef":{"id":"` + networkId + `"},
+		"ipSpaceRef":{"id":"` + ipSpaceId + `"},"status":"REALIZED"}`
+	server.HandleJSON(http.MethodPost, uplinksPath, http.StatusCreated, uplinkJson)
+	server.HandleJSON(http.MethodGet, uplinksPath, http.StatusOK,
+		`{"resultTotal":1,"pageCount":1,"page":1,"pageSize":128,"values":[`+uplinkJson+`]}`)
+	server.HandleJSON(http.MethodPut, uplinksPath+"uplink-1", http.StatusOK, uplinkJson)
+	server.Handle(http.MethodDelete, uplinksPath+"uplink-1", vcdtest.Response{Status: http.StatusNoContent})
+
+	vcdClient := newMockClient(t, server)
+	client := &vcdClient.Client
+
+	if _, err := client.CreateIpSpaceUplink(&types.IpSpaceUplink{Name: "uplink1",
+		ExternalNetworkRef: &types.OpenApiReference{ID: networkId}}); err == nil {
+		t.Errorf("expected error creating IP space uplink without IP space")
+	}
+	uplink, err := client.CreateIpSpaceUplink(&types.IpSpaceUplink{
+		Name:               "uplink1",
+		ExternalNetworkRef: &types.OpenApiReference{ID: networkId},
+		IPSpaceRef:         &types.OpenApiReference{ID: ipSpaceId},
+	})
+	if err != nil {
+		t.Fatalf("error creating IP space uplink: %s", err)
+	}
+	posts := server.RequestsTo(http.MethodPost, uplinksPath)
+	if uplink.IpSpaceUplink.ID != "uplink-1" || len(posts) != 1 || posts[0].Header.Get("Accept") != "application/json;version=37.1" {
+		t.Errorf("unexpected IP space uplink %+v created with %#v", uplink.IpSpaceUplink, posts)
+	}
+
+	byName, err := client.GetIpSpaceUplinkByName(networkId, "uplink1")
+	if err != nil {
+		t.Fatalf("error retrieving IP space uplink by name: %s", err)
+	}
+	gets := server.RequestsTo(http.MethodGet, uplinksPath)
+	query, _ := url.ParseQuery(gets[len(gets)-1].RawQuery)
+	if byName.IpSpaceUplink.IPSpaceRef.ID != ipSpaceId || query.Get("filter") != "name==uplink1;externalNetworkRef.id=="+networkId {
+		t.Errorf("unexpected IP space uplink %+v with filter %s", byName.IpSpaceUplink, query.Get("filter"))
+	}
+
+	byName.IpSpaceUplink.Description = "to the internet"
+	err = byName.Update()
+	if err != nil {
+		t.Fatalf("error updating IP space uplink: %s", err)
+	}
+	puts := server.RequestsTo(http.MethodPut, uplinksPath+"uplink-1")
+	if len(puts) != 1 || !strings.Contains(puts[0].Body, `"description": "to the internet"`) {
+		t.Errorf("unexpected IP space uplink updates: %#v", puts)
+	}
+	err = byName.Delete()
+	if err != nil {
+		t.Fatalf("error deleting IP space uplink: %s", err)
+	}
+	if len(server.RequestsTo(http.MethodDelete, uplinksPath+"uplink-1")) != 1 {
+		t.Errorf("expected the IP space uplink to be deleted")
+	}
+}
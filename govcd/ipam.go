@@ -0,0 +1,301 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// IP pools of the external networks
+//
+// The IP pools of an external network are shared by the edge gateways connected to it. NSX-V edge
+// gateways get addresses sub-allocated to their uplink interface (see
+// EdgeGateway.AddSubAllocatedIpRange), while NSX-T edge gateways get addresses allocated to their
+// uplinks (see NsxtEdgeGateway.AllocateIpAddresses and AllocateIpRange). Provisioning code can pick
+// one of the free addresses of IpPoolUsage and sub-allocate it to an edge gateway.
+
+// IpPoolUsage is the usage of the IP pools of an external network. The addresses are in their
+// normalized form and in the order of the pools.
+type IpPoolUsage struct {
+	Total        []string // Addresses of the IP pools
+	Used         []string // Addresses allocated to VMs and to the interfaces of edge gateways
+	SubAllocated []string // Addresses sub-allocated to NSX-V edge gateways
+	Free         []string // Addresses of the IP pools which are neither used nor sub-allocated
+}
+
+// newIpPoolUsage computes the usage of the given IP pools
+func newIpPoolUsage(pools []types.OpenApiIPRange, used, subAllocated []string) (*IpPoolUsage, error) {
+	total, err := ipRangesAddresses(pools)
+	if err != nil {
+		return nil, err
+	}
+	usage := &IpPoolUsage{Total: total}
+	taken := make(map[string]bool)
+	for _, address := range used {
+		if ip := net.ParseIP(address); ip != nil {
+			usage.Used = append(usage.Used, ip.String())
+			taken[ip.String()] = true
+		}
+	}
+	for _, address := range subAllocated {
+		if ip := net.ParseIP(address); ip != nil {
+			usage.SubAllocated = append(usage.SubAllocated, ip.String())
+			taken[ip.String()] = true
+		}
+	}
+	for _, address := range total {
+		if !taken[address] {
+			usage.Free = append(usage.Free, address)
+		}
+	}
+	return usage, nil
+}
+
+// openApiIpRanges converts the IP ranges of the XML API to their OpenAPI form
+func openApiIpRanges(ipRanges *types.IPRanges) []types.OpenApiIPRange {
+	if ipRanges == nil {
+		return nil
+	}
+	result := make([]types.OpenApiIPRange, 0, len(ipRanges.IPRange))
+	for _, ipRange := range ipRanges.IPRange {
+		result = append(result, types.OpenApiIPRange{StartAddress: ipRange.StartAddress, EndAddress: ipRange.EndAddress})
+	}
+	return result
+}
+
+// GetIpPoolUsage refreshes the external network and returns the usage of its IP pools
+func (externalNetwork *ExternalNetwork) GetIpPoolUsage() (*IpPoolUsage, error) {
+	err := externalNetwork.Refresh()
+	if err != nil {
+		return nil, err
+	}
+	var pools []types.OpenApiIPRange
+	var used, subAllocated []string
+	configuration := externalNetwork.ExternalNetwork.Configuration
+	if configuration != nil && configuration.IPScopes != nil {
+		for _, ipScope := range configuration.IPScopes.IPScope {
+			pools = append(pools, openApiIpRanges(ipScope.IPRanges)...)
+			if ipScope.AllocatedIPAddresses != nil {
+				used = append(used, ipScope.AllocatedIPAddresses.IPAddress...)
+			}
+			if ipScope.SubAllocations == nil {
+				continue
+			}
+			for _, subAllocation := range ipScope.SubAllocations.SubAllocation {
+				addresses, err := ipRangesAddresses(openApiIpRanges(subAllocation.IPRanges))
+				if err != nil {
+					return nil, err
+				}
+				subAllocated = append(subAllocated, addresses...)
+			}
+		}
+	}
+	return newIpPoolUsage(pools, used, subAllocated)
+}
+
+// GetUsedIpAddresses retrieves the IP addresses of the external network allocated to VMs and edge
+// gateways. Query parameters can be supplied to perform additional filtering (e.g. "filter" =>
+// "allocationType==VSM_ALLOCATED")
+func (network *ExternalNetworkV2) GetUsedIpAddresses(queryParameters url.Values) ([]*types.ExternalNetworkV2UsedIpAddress, error) {
+	if network.ExternalNetwork.ID == "" {
+		return nil, fmt.Errorf("cannot retrieve used IP addresses of external network without ID")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointExternalNetworkUsedIps
+	apiVersion, err := network.client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := network.client.OpenApiBuildEndpoint(fmt.Sprintf(endpoint, network.ExternalNetwork.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	var usedIps []*types.ExternalNetworkV2UsedIpAddress
+	err = network.client.OpenApiGetAllItems(apiVersion, urlRef, queryParameters, &usedIps)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving used IP addresses of external network: %s", err)
+	}
+	return usedIps, nil
+}
+
+// GetIpPoolUsage retrieves the external network again and returns the usage of its IP pools. The
+// addresses allocated to NSX-T edge gateways are reported as used.
+func (network *ExternalNetworkV2) GetIpPoolUsage() (*IpPoolUsage, error) {
+	refreshed, err := network.client.GetExternalNetworkV2ById(network.ExternalNetwork.ID)
+	if err != nil {
+		return nil, err
+	}
+	network.ExternalNetwork = refreshed.ExternalNetwork
+	usedIps, err := network.GetUsedIpAddresses(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var pools []types.OpenApiIPRange
+	for _, subnet := range network.ExternalNetwork.Subnets.Values {
+		pools = append(pools, subnet.IPRanges.Values...)
+	}
+	used := make([]string, len(usedIps))
+	for index, usedIp := range usedIps {
+		used[index] = usedIp.IPAddress
+	}
+	return newIpPoolUsage(pools, used, nil)
+}
+
+// GetSubAllocatedIpRanges returns the IP ranges of the external network sub-allocated to the
+// uplink interface of the NSX-V edge gateway
+func (eGW *EdgeGateway) GetSubAllocatedIpRanges(externalNetworkName string) ([]*types.IPRange, error) {
+	gatewayInterface, err := eGW.findUplinkInterface(externalNetworkName)
+	if err != nil {
+		return nil, err
+	}
+	var ipRanges []*types.IPRange
+	for _, subnet := range gatewayInterface.SubnetParticipation {
+		if subnet.IPRanges != nil {
+			ipRanges = append(ipRanges, subnet.IPRanges.IPRange...)
+		}
+	}
+	return ipRanges, nil
+}
+
+// AddSubAllocatedIpRange sub-allocates the given range of the IP pools of the external network to
+// the uplink interface of the NSX-V edge gateway, in the subnet containing it. The edge gateway is
+// refreshed once the task completes.
+func (eGW *EdgeGateway) AddSubAllocatedIpRange(externalNetworkName string, ipRange *types.IPRange) error {
+	if ipRange == nil {
+		return fmt.Errorf("IP range is required")
+	}
+	start, end, err := parseIpRange(types.OpenApiIPRange{StartAddress: ipRange.StartAddress, EndAddress: ipRange.EndAddress})
+	if err != nil {
+		return err
+	}
+	return eGW.updateUplinkInterface(externalNetworkName, func(gatewayInterface *types.GatewayInterface) error {
+		for _, subnet := range gatewayInterface.SubnetParticipation {
+			mask := net.ParseIP(subnet.Netmask).To4()
+			gateway := net.ParseIP(subnet.Gateway)
+			if mask == nil || gateway == nil {
+				continue
+			}
+			network := net.IPNet{IP: gateway.Mask(net.IPMask(mask)), Mask: net.IPMask(mask)}
+			if !network.Contains(start) || !network.Contains(end) {
+				continue
+			}
+			if subnet.IPRanges == nil {
+				subnet.IPRanges = &types.IPRanges{}
+			}
+			current := openApiIpRanges(subnet.IPRanges)
+			if ipRangesContain(current, ipRange.StartAddress) || ipRangesContain(current, ipRange.EndAddress) {
+				return fmt.Errorf("IP range %s-%s is already sub-allocated", ipRange.StartAddress, ipRange.EndAddress)
+			}
+			subnet.IPRanges.IPRange = append(subnet.IPRanges.IPRange, &types.IPRange{
+				StartAddress: start.String(),
+				EndAddress:   end.String(),
+			})
+			return nil
+		}
+		return fmt.Errorf("no subnet of external network %s contains IP range %s-%s", externalNetworkName,
+			ipRange.StartAddress, ipRange.EndAddress)
+	})
+}
+
+// RemoveSubAllocatedIpRange gives the given sub-allocated range back to the IP pools of the
+// external network. The edge gateway is refreshed once the task completes.
+func (eGW *EdgeGateway) RemoveSubAllocatedIpRange(externalNetworkName string, ipRange *types.IPRange) error {
+	if ipRange == nil {
+		return fmt.Errorf("IP range is required")
+	}
+	return eGW.updateUplinkInterface(externalNetworkName, func(gatewayInterface *types.GatewayInterface) error {
+		for _, subnet := range gatewayInterface.SubnetParticipation {
+			if subnet.IPRanges == nil {
+				continue
+			}
+			for index, subAllocated := range subnet.IPRanges.IPRange {
+				if net.ParseIP(subAllocated.StartAddress).Equal(net.ParseIP(ipRange.StartAddress)) &&
+					net.ParseIP(subAllocated.EndAddress).Equal(net.ParseIP(ipRange.EndAddress)) {
+					subnet.IPRanges.IPRange = append(subnet.IPRanges.IPRange[:index], subnet.IPRanges.IPRange[index+1:]...)
+					if len(subnet.IPRanges.IPRange) == 0 {
+						subnet.IPRanges = nil
+					}
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("IP range %s-%s is not sub-allocated to edge gateway %s", ipRange.StartAddress,
+			ipRange.EndAddress, eGW.EdgeGateway.Name)
+	})
+}
+
+// findUplinkInterface returns the uplink interface of the edge gateway connected to the external
+// network
+func (eGW *EdgeGateway) findUplinkInterface(externalNetworkName string) (*types.GatewayInterface, error) {
+	if eGW.EdgeGateway.Configuration != nil && eGW.EdgeGateway.Configuration.GatewayInterfaces != nil {
+		for _, gatewayInterface := range eGW.EdgeGateway.Configuration.GatewayInterfaces.GatewayInterface {
+			if gatewayInterface.InterfaceType == "uplink" && gatewayInterface.Network != nil &&
+				gatewayInterface.Network.Name == externalNetworkName {
+				return gatewayInterface, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("edge gateway %s has no uplink to external network %s", eGW.EdgeGateway.Name, externalNetworkName)
+}
+
+// updateUplinkInterface applies modify to a copy of the uplink interface of the edge gateway
+// connected to the external network, sends the edge gateway with it, waits for the task to
+// complete and refreshes the edge gateway
+func (eGW *EdgeGateway) updateUplinkInterface(externalNetworkName string, modify func(gatewayInterface *types.GatewayInterface) error) error {
+	if eGW.EdgeGateway.HREF == "" {
+		return fmt.Errorf("cannot update, Object is empty")
+	}
+	current, err := eGW.findUplinkInterface(externalNetworkName)
+	if err != nil {
+		return err
+	}
+	gatewayInterface := *current
+	gatewayInterface.SubnetParticipation = make([]*types.SubnetParticipation, len(current.SubnetParticipation))
+	for index, subnet := range current.SubnetParticipation {
+		subnetCopy := *subnet
+		if subnet.IPRanges != nil {
+			subnetCopy.IPRanges = &types.IPRanges{IPRange: append([]*types.IPRange(nil), subnet.IPRanges.IPRange...)}
+		}
+		gatewayInterface.SubnetParticipation[index] = &subnetCopy
+	}
+	err = modify(&gatewayInterface)
+	if err != nil {
+		return err
+	}
+
+	// Read-only elements are not sent back, and the services are configured through
+	// action/configureServices
+	payload := *eGW.EdgeGateway
+	payload.Xmlns = types.XMLNamespaceVCloud
+	payload.Link = nil
+	payload.Tasks = nil
+	configuration := *payload.Configuration
+	configuration.EdgeGatewayServiceConfiguration = nil
+	configuration.GatewayInterfaces = &types.GatewayInterfaces{}
+	for _, existing := range eGW.EdgeGateway.Configuration.GatewayInterfaces.GatewayInterface {
+		if existing == current {
+			existing = &gatewayInterface
+		}
+		configuration.GatewayInterfaces.GatewayInterface = append(configuration.GatewayInterfaces.GatewayInterface, existing)
+	}
+	payload.Configuration = &configuration
+
+	task, err := eGW.client.ExecuteTaskRequest(eGW.EdgeGateway.HREF, http.MethodPut,
+		types.MimeEdgeGateway, "error updating edge gateway: %s", &payload)
+	if err != nil {
+		return err
+	}
+	err = task.WaitTaskCompletion()
+	if err != nil {
+		return fmt.Errorf("error updating sub-allocated IP ranges: %s", err)
+	}
+	return eGW.Refresh()
+}
@@ -0,0 +1,202 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// Checks the usage of the IP pools of external networks and the sub-allocation of IP ranges to
+// NSX-V edge gateways against a fake vCD
+func TestIpPoolUsage(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	const externalNetworkPath = "/api/admin/extension/externalnet/external-1"
+	const externalNetworkV2Id = "urn:vcloud:network:44444444-4444-4444-4444-444444444444"
+	const externalNetworkV2Path = "/cloudapi/1.0.0/externalNetworks/" + externalNetworkV2Id
+	const edgePath = "/api/admin/edgeGateway/edge-1"
+	server.HandleXML(http.MethodGet, externalNetworkPath, http.StatusOK,
+		`<VMWExternalNetwork xmlns="http://www.vmware.com/vcloud/extension/v1.5" xmlns:vcloud="http://www.vmware.com/vcloud/v1.5"
+		  name="external1" href="{{server}}`+externalNetworkPath+`">
+		  <vcloud:Configuration>
+		    <vcloud:IpScopes>
+		      <vcloud:IpScope>
+		        <vcloud:IsInherited>false</vcloud:IsInherited>
+		        <vcloud:Gateway>192.168.1.1</vcloud:Gateway>
+		        <vcloud:Netmask>255.255.255.0</vcloud:Netmask>
+		        <vcloud:IpRanges>
+		          <vcloud:IpRange><vcloud:StartAddress>192.168.1.10</vcloud:StartAddress><vcloud:EndAddress>192.168.1.19</vcloud:EndAddress></vcloud:IpRange>
+		        </vcloud:IpRanges>
+		        <vcloud:AllocatedIpAddresses>
+		          <vcloud:IpAddress>192.168.1.10</vcloud:IpAddress>
+		          <vcloud:IpAddress>192.168.1.11</vcloud:IpAddress>
+		        </vcloud:AllocatedIpAddresses>
+		        <vcloud:SubAllocations>
+		          <vcloud:SubAllocation>
+		            <vcloud:EdgeGateway name="edge1" href="{{server}}`+edgePath+`"/>
+		            <vcloud:IpRanges>
+		              <vcloud:IpRange><vcloud:StartAddress>192.168.1.12</vcloud:StartAddress><vcloud:EndAddress>192.168.1.13</vcloud:EndAddress></vcloud:IpRange>
+		            </vcloud:IpRanges>
+		          </vcloud:SubAllocation>
+		          <vcloud:SubAllocation>
+		            <vcloud:EdgeGateway name="edge2" href="{{server}}/api/admin/edgeGateway/edge-2"/>
+		            <vcloud:IpRanges>
+		              <vcloud:IpRange><vcloud:StartAddress>192.168.1.15</vcloud:StartAddress><vcloud:EndAddress>192.168.1.15</vcloud:EndAddress></vcloud:IpRange>
+		            </vcloud:IpRanges>
+		          </vcloud:SubAllocation>
+		        </vcloud:SubAllocations>
+		      </vcloud:IpScope>
+		    </vcloud:IpScopes>
+		    <vcloud:FenceMode>isolated</vcloud:FenceMode>
+		  </vcloud:Configuration>
+		</VMWExternalNetwork>`)
+	server.HandleJSON(http.MethodGet, externalNetworkV2Path, http.StatusOK,
+		`{"id":"`+externalNetworkV2Id+`","name":"external2","subnets":{"values":[{"gateway":"10.0.0.1","prefixLength":24,"enabled":true,
+		"ipRanges":{"values":[{"startAddress":"10.0.0.10","endAddress":"10.0.0.14"}]}}]},"networkBackings":{"values":[]}}`)
+	server.HandleJSON(http.MethodGet, externalNetworkV2Path+"/usedIpAddresses", http.StatusOK,
+		`{"resultTotal":2,"pageCount":1,"page":1,"pageSize":128,"values":[
+		{"entityName":"edge3","ipAddress":"10.0.0.10","allocationType":"VSM_ALLOCATED","deployed":true},
+		{"entityName":"edge3","ipAddress":"10.0.0.12","allocationType":"VSM_ALLOCATED","deployed":true}]}`)
+	server.HandleXML(http.MethodGet, edgePath, http.StatusOK,
+		`<EdgeGateway xmlns="http://www.vmware.com/vcloud/v1.5" name="edge1" href="{{server}}`+edgePath+`">
+		  <Link rel="edit" href="{{server}}`+edgePath+`"/>
+		  <Configuration>
+		    <GatewayBackingConfig>compact</GatewayBackingConfig>
+		    <GatewayInterfaces>
+		      <GatewayInterface>
+		        <Name>external1</Name>
+		        <Network type="application/vnd.vmware.admin.network+xml" name="external1" href="{{server}}/api/admin/network/external-1"/>
+		        <InterfaceType>uplink</InterfaceType>
+		        <SubnetParticipation>
+		          <Gateway>192.168.1.1</Gateway>
+		          <Netmask>255.255.255.0</Netmask>
+		          <IpAddress>192.168.1.11</IpAddress>
+		          <IpRanges>
+		            <IpRange><StartAddress>192.168.1.12</StartAddress><EndAddress>192.168.1.13</EndAddress></IpRange>
+		          </IpRanges>
+		        </SubnetParticipation>
+		      </GatewayInterface>
+		      <GatewayInterface>
+		        <Name>internal1</Name>
+		        <Network type="application/vnd.vmware.admin.network+xml" name="internal1" href="{{server}}/api/admin/network/internal-1"/>
+		        <InterfaceType>internal</InterfaceType>
+		      </GatewayInterface>
+		    </GatewayInterfaces>
+		    <EdgeGatewayServiceConfiguration>
+		      <NatService><IsEnabled>true</IsEnabled></NatService>
+		    </EdgeGatewayServiceConfiguration>
+		  </Configuration>
+		</EdgeGateway>`)
+	server.HandleXML(http.MethodPut, edgePath, http.StatusAccepted,
+		`<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="running" href="{{server}}`+vcdtest.MockTaskPath+`"/>`)
+
+	vcdClient := newMockClient(t, server)
+
+	externalNetwork, err := GetExternalNetworkByHref(vcdClient, server.URL()+externalNetworkPath)
+	if err != nil {
+		t.Fatalf("error retrieving external network: %s", err)
+	}
+	usage, err := externalNetwork.GetIpPoolUsage()
+	if err != nil {
+		t.Fatalf("error retrieving IP pool usage: %s", err)
+	}
+	expected := &IpPoolUsage{
+		Total: []string{"192.168.1.10", "192.168.1.11", "192.168.1.12", "192.168.1.13", "192.168.1.14",
+			"192.168.1.15", "192.168.1.16", "192.168.1.17", "192.168.1.18", "192.168.1.19"},
+		Used:         []string{"192.168.1.10", "192.168.1.11"},
+		SubAllocated: []string{"192.168.1.12", "192.168.1.13", "192.168.1.15"},
+		Free:         []string{"192.168.1.14", "192.168.1.16", "192.168.1.17", "192.168.1.18", "192.168.1.19"},
+	}
+	if !reflect.DeepEqual(usage, expected) {
+		t.Errorf("expected IP pool usage %+v, got %+v", expected, usage)
+	}
+
+	networkV2, err := vcdClient.Client.GetExternalNetworkV2ById(externalNetworkV2Id)
+	if err != nil {
+		t.Fatalf("error retrieving external network: %s", err)
+	}
+	usage, err = networkV2.GetIpPoolUsage()
+	if err != nil {
+		t.Fatalf("error retrieving IP pool usage: %s", err)
+	}
+	if len(usage.Total) != 5 || !reflect.DeepEqual(usage.Free, []string{"10.0.0.11", "10.0.0.13", "10.0.0.14"}) {
+		t.Errorf("unexpected IP pool usage: %+v", usage)
+	}
+
+	// Sub-allocation of a free address to the NSX-V edge gateway
+	edge := NewEdgeGateway(&vcdClient.Client)
+	edge.EdgeGateway.HREF = server.URL() + edgePath
+	err = edge.Refresh()
+	if err != nil {
+		t.Fatalf("error retrieving edge gateway: %s", err)
+	}
+	ipRanges, err := edge.GetSubAllocatedIpRanges("external1")
+	if err != nil {
+		t.Fatalf("error retrieving sub-allocated IP ranges: %s", err)
+	}
+	if len(ipRanges) != 1 || ipRanges[0].StartAddress != "192.168.1.12" {
+		t.Errorf("unexpected sub-allocated IP ranges: %+v", ipRanges)
+	}
+	if _, err = edge.GetSubAllocatedIpRanges("internal1"); err == nil {
+		t.Errorf("expected error retrieving sub-allocated IP ranges of internal network")
+	}
+	invalidRanges := []*types.IPRange{
+		{StartAddress: "192.168.2.10", EndAddress: "192.168.2.10"},
+		{StartAddress: "192.168.1.13", EndAddress: "192.168.1.14"},
+		{StartAddress: "192.168.1.16", EndAddress: "192.168.1.14"},
+	}
+	for _, ipRange := range invalidRanges {
+		if err = edge.AddSubAllocatedIpRange("external1", ipRange); err == nil {
+			t.Errorf("expected error sub-allocating IP range %+v", ipRange)
+		}
+	}
+	if len(server.RequestsTo(http.MethodPut, edgePath)) != 0 {
+		t.Fatalf("expected no update of the edge gateway after invalid sub-allocations")
+	}
+
+	err = edge.AddSubAllocatedIpRange("external1", &types.IPRange{StartAddress: usage.Free[0], EndAddress: usage.Free[0]})
+	if err == nil {
+		t.Errorf("expected error sub-allocating address of another subnet")
+	}
+	free := expected.Free[0]
+	err = edge.AddSubAllocatedIpRange("external1", &types.IPRange{StartAddress: free, EndAddress: free})
+	if err != nil {
+		t.Fatalf("error sub-allocating IP range: %s", err)
+	}
+	puts := server.RequestsTo(http.MethodPut, edgePath)
+	if len(puts) != 1 {
+		t.Fatalf("expected one update of the edge gateway, got %d", len(puts))
+	}
+	body := puts[0].Body
+	ranges := `<IpRange><StartAddress>192.168.1.12</StartAddress><EndAddress>192.168.1.13</EndAddress></IpRange>` +
+		`<IpRange><StartAddress>192.168.1.14</StartAddress><EndAddress>192.168.1.14</EndAddress></IpRange>`
+	if !strings.Contains(strings.Join(strings.Fields(body), ""), ranges) {
+		t.Errorf("expected the new range after the current one:\n%s", body)
+	}
+	if puts[0].Header.Get("Content-Type") != types.MimeEdgeGateway || !strings.Contains(body, `xmlns="`+types.XMLNamespaceVCloud+`"`) ||
+		strings.Contains(body, "<Link") || strings.Contains(body, "NatService") || !strings.Contains(body, "internal1") {
+		t.Errorf("unexpected edge gateway update:\n%s", body)
+	}
+
+	server.ClearRequests()
+	if err = edge.RemoveSubAllocatedIpRange("external1", &types.IPRange{StartAddress: "192.168.1.12", EndAddress: "192.168.1.14"}); err == nil {
+		t.Errorf("expected error removing IP range which is not sub-allocated")
+	}
+	err = edge.RemoveSubAllocatedIpRange("external1", &types.IPRange{StartAddress: "192.168.1.12", EndAddress: "192.168.1.13"})
+	if err != nil {
+		t.Fatalf("error removing sub-allocated IP range: %s", err)
+	}
+	puts = server.RequestsTo(http.MethodPut, edgePath)
+	if len(puts) != 1 || strings.Contains(puts[0].Body, "<IpRanges>") {
+		t.Errorf("expected the sub-allocated IP ranges to be removed: %#v", puts)
+	}
+}
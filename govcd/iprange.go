@@ -0,0 +1,142 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// This file contains the arithmetic of the IP ranges used by the IP allocation functions. Addresses
+// are handled in their 16 bytes form, so that IPv4 and IPv6 ranges are processed the same way.
+
+// maxIpRangeExpansion is the maximum number of addresses listed from IP ranges, so that a large
+// IPv6 range cannot exhaust the memory
+const maxIpRangeExpansion = 65536
+
+// parseIp returns the 16 bytes form of address
+func parseIp(address string) (net.IP, error) {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address '%s'", address)
+	}
+	return ip.To16(), nil
+}
+
+// nextIp returns the address following ip
+func nextIp(ip net.IP) net.IP {
+	next := make(net.IP, len(ip))
+	copy(next, ip)
+	for index := len(next) - 1; index >= 0; index-- {
+		next[index]++
+		if next[index] != 0 {
+			break
+		}
+	}
+	return next
+}
+
+// previousIp returns the address preceding ip
+func previousIp(ip net.IP) net.IP {
+	previous := make(net.IP, len(ip))
+	copy(previous, ip)
+	for index := len(previous) - 1; index >= 0; index-- {
+		previous[index]--
+		if previous[index] != 0xff {
+			break
+		}
+	}
+	return previous
+}
+
+// parseIpRange returns the first and last addresses of ipRange, checking that they are in order
+func parseIpRange(ipRange types.OpenApiIPRange) (net.IP, net.IP, error) {
+	start, err := parseIp(ipRange.StartAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+	end := start
+	if ipRange.EndAddress != "" {
+		end, err = parseIp(ipRange.EndAddress)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if bytes.Compare(start, end) > 0 {
+		return nil, nil, fmt.Errorf("IP range %s-%s ends before its start", ipRange.StartAddress, ipRange.EndAddress)
+	}
+	return start, end, nil
+}
+
+// ipRangesAddresses returns the addresses of ipRanges, in order. An error is returned when the
+// ranges have more than maxIpRangeExpansion addresses.
+func ipRangesAddresses(ipRanges []types.OpenApiIPRange) ([]string, error) {
+	var addresses []string
+	for _, ipRange := range ipRanges {
+		start, end, err := parseIpRange(ipRange)
+		if err != nil {
+			return nil, err
+		}
+		for ip := start; bytes.Compare(ip, end) <= 0; ip = nextIp(ip) {
+			if len(addresses) == maxIpRangeExpansion {
+				return nil, fmt.Errorf("IP ranges have more than %d addresses", maxIpRangeExpansion)
+			}
+			addresses = append(addresses, ip.String())
+			if ip.Equal(end) {
+				break
+			}
+		}
+	}
+	return addresses, nil
+}
+
+// ipRangesContain returns true if address belongs to one of ipRanges
+func ipRangesContain(ipRanges []types.OpenApiIPRange, address string) bool {
+	ip, err := parseIp(address)
+	if err != nil {
+		return false
+	}
+	for _, ipRange := range ipRanges {
+		start, end, err := parseIpRange(ipRange)
+		if err == nil && bytes.Compare(start, ip) <= 0 && bytes.Compare(ip, end) <= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// removeFromIpRanges returns ipRanges without the given addresses, splitting the ranges where
+// needed. Addresses which do not belong to the ranges are ignored.
+func removeFromIpRanges(ipRanges []types.OpenApiIPRange, addresses []string) ([]types.OpenApiIPRange, error) {
+	result := ipRanges
+	for _, address := range addresses {
+		ip, err := parseIp(address)
+		if err != nil {
+			return nil, err
+		}
+		var remaining []types.OpenApiIPRange
+		for _, ipRange := range result {
+			start, end, err := parseIpRange(ipRange)
+			if err != nil {
+				return nil, err
+			}
+			if bytes.Compare(ip, start) < 0 || bytes.Compare(ip, end) > 0 {
+				remaining = append(remaining, ipRange)
+				continue
+			}
+			if !ip.Equal(start) {
+				remaining = append(remaining, types.OpenApiIPRange{StartAddress: start.String(), EndAddress: previousIp(ip).String()})
+			}
+			if !ip.Equal(end) {
+				remaining = append(remaining, types.OpenApiIPRange{StartAddress: nextIp(ip).String(), EndAddress: end.String()})
+			}
+		}
+		result = remaining
+	}
+	return result, nil
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+func TestIpRanges(t *testing.T) {
+	ranges := []types.OpenApiIPRange{
+		{StartAddress: "10.0.0.254", EndAddress: "10.0.1.1"},
+		{StartAddress: "2001:db8::ffff", EndAddress: "2001:db8::1:0"},
+		{StartAddress: "192.168.0.1"},
+	}
+	addresses, err := ipRangesAddresses(ranges)
+	if err != nil {
+		t.Fatalf("error listing addresses: %s", err)
+	}
+	expected := []string{"10.0.0.254", "10.0.0.255", "10.0.1.0", "10.0.1.1", "2001:db8::ffff", "2001:db8::1:0", "192.168.0.1"}
+	if !reflect.DeepEqual(addresses, expected) {
+		t.Errorf("expected addresses %v, got %v", expected, addresses)
+	}
+	if !ipRangesContain(ranges, "10.0.1.0") || ipRangesContain(ranges, "10.0.1.2") || ipRangesContain(ranges, "invalid") {
+		t.Errorf("unexpected result of ipRangesContain")
+	}
+
+	remaining, err := removeFromIpRanges(ranges, []string{"10.0.0.255", "10.0.1.1", "2001:db8::ffff", "192.168.0.1", "172.16.0.1"})
+	if err != nil {
+		t.Fatalf("error removing addresses: %s", err)
+	}
+	expectedRanges := []types.OpenApiIPRange{
+		{StartAddress: "10.0.0.254", EndAddress: "10.0.0.254"},
+		{StartAddress: "10.0.1.0", EndAddress: "10.0.1.0"},
+		{StartAddress: "2001:db8::1:0", EndAddress: "2001:db8::1:0"},
+	}
+	if !reflect.DeepEqual(remaining, expectedRanges) {
+		t.Errorf("expected ranges %v, got %v", expectedRanges, remaining)
+	}
+
+	invalidRanges := [][]types.OpenApiIPRange{
+		{{StartAddress: "10.0.0.10", EndAddress: "10.0.0.1"}},
+		{{StartAddress: "10.0.0.256"}},
+		{{StartAddress: "2001:db8::", EndAddress: "2001:db8::ffff:ffff"}},
+	}
+	for _, invalid := range invalidRanges {
+		if _, err = ipRangesAddresses(invalid); err == nil {
+			t.Errorf("expected error listing addresses of %v", invalid)
+		}
+	}
+}
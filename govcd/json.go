@@ -0,0 +1,263 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// The JSON representations below are a stable schema for external tooling, such as inventory
+// exporters: fields are only added, never renamed or removed. Lists are always present, empty
+// when there are no items. Statuses are strings (see types.VAppStatuses) instead of codes.
+
+// VAppJson is the JSON representation of a vApp, produced by VApp.MarshalJSON
+type VAppJson struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Href        string   `json:"href"`
+	Description string   `json:"description"`
+	Status      string   `json:"status"`   // e.g. POWERED_ON
+	Deployed    bool     `json:"deployed"` // True when the vApp is deployed
+	Networks    []string `json:"networks"` // Names of the vApp networks
+	VMs         []VMJson `json:"vms"`
+}
+
+// VMJson is the JSON representation of a VM, produced by VM.MarshalJSON
+type VMJson struct {
+	ID             string       `json:"id"`
+	Name           string       `json:"name"`
+	Href           string       `json:"href"`
+	Description    string       `json:"description"`
+	Status         string       `json:"status"` // e.g. POWERED_ON
+	Deployed       bool         `json:"deployed"`
+	CPUs           int          `json:"cpus"`             // Number of virtual CPUs
+	CoresPerSocket int          `json:"cores_per_socket"` // 0 when not known
+	MemoryMB       int          `json:"memory_mb"`
+	Disks          []VMDiskJson `json:"disks"`
+	Nics           []VMNicJson  `json:"nics"`
+	IPs            []string     `json:"ips"` // IP addresses of all the NICs, internal then external
+}
+
+// VMDiskJson is the JSON representation of a hard disk of a VM
+type VMDiskJson struct {
+	Name   string `json:"name"`
+	SizeMB int    `json:"size_mb"`
+}
+
+// VMNicJson is the JSON representation of a NIC of a VM
+type VMNicJson struct {
+	Index             int    `json:"index"`
+	Network           string `json:"network"`
+	IPAddress         string `json:"ip_address"`
+	ExternalIPAddress string `json:"external_ip_address"`
+	MACAddress        string `json:"mac_address"`
+	AllocationMode    string `json:"allocation_mode"` // One of POOL, DHCP, MANUAL, NONE
+	Connected         bool   `json:"connected"`
+	Primary           bool   `json:"primary"`
+}
+
+// NetworkJson is the JSON representation of an org VDC network, produced by OrgVDCNetwork.MarshalJSON
+type NetworkJson struct {
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	Href        string        `json:"href"`
+	Description string        `json:"description"`
+	FenceMode   string        `json:"fence_mode"` // One of isolated, bridged, natRouted
+	Shared      bool          `json:"shared"`
+	Gateway     string        `json:"gateway"`
+	Netmask     string        `json:"netmask"`
+	DNS1        string        `json:"dns1"`
+	DNS2        string        `json:"dns2"`
+	DNSSuffix   string        `json:"dns_suffix"`
+	IPRanges    []IPRangeJson `json:"ip_ranges"` // Static IP pools
+}
+
+// IPRangeJson is the JSON representation of an IP range
+type IPRangeJson struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// VdcJson is the JSON representation of a VDC, produced by Vdc.MarshalJSON
+type VdcJson struct {
+	ID              string   `json:"id"`
+	Name            string   `json:"name"`
+	Href            string   `json:"href"`
+	Description     string   `json:"description"`
+	AllocationModel string   `json:"allocation_model"`
+	Enabled         bool     `json:"enabled"`
+	VApps           []string `json:"vapps"`    // Names of the vApps
+	Networks        []string `json:"networks"` // Names of the available networks
+}
+
+// MarshalJSON returns the JSON representation of the vApp (see VAppJson)
+func (vapp VApp) MarshalJSON() ([]byte, error) {
+	if vapp.VApp == nil {
+		return nil, fmt.Errorf("cannot marshal vApp, Object is empty")
+	}
+	return json.Marshal(newVAppJson(vapp.VApp))
+}
+
+// MarshalJSON returns the JSON representation of the VM (see VMJson)
+func (vm VM) MarshalJSON() ([]byte, error) {
+	if vm.VM == nil {
+		return nil, fmt.Errorf("cannot marshal VM, Object is empty")
+	}
+	return json.Marshal(newVMJson(vm.VM))
+}
+
+// MarshalJSON returns the JSON representation of the org VDC network (see NetworkJson)
+func (orgVdcNet OrgVDCNetwork) MarshalJSON() ([]byte, error) {
+	if orgVdcNet.OrgVDCNetwork == nil {
+		return nil, fmt.Errorf("cannot marshal network, Object is empty")
+	}
+	return json.Marshal(newNetworkJson(orgVdcNet.OrgVDCNetwork))
+}
+
+// MarshalJSON returns the JSON representation of the VDC (see VdcJson)
+func (vdc Vdc) MarshalJSON() ([]byte, error) {
+	if vdc.Vdc == nil {
+		return nil, fmt.Errorf("cannot marshal VDC, Object is empty")
+	}
+	return json.Marshal(newVdcJson(vdc.Vdc))
+}
+
+func newVAppJson(vapp *types.VApp) VAppJson {
+	vappJson := VAppJson{
+		ID:          vapp.ID,
+		Name:        vapp.Name,
+		Href:        vapp.HREF,
+		Description: vapp.Description,
+		Status:      types.VAppStatuses[vapp.Status],
+		Deployed:    vapp.Deployed,
+		Networks:    []string{},
+		VMs:         []VMJson{},
+	}
+	if vapp.NetworkConfigSection != nil {
+		for _, networkConfig := range vapp.NetworkConfigSection.NetworkConfig {
+			if networkConfig.NetworkName != types.NoneNetwork {
+				vappJson.Networks = append(vappJson.Networks, networkConfig.NetworkName)
+			}
+		}
+	}
+	if vapp.Children != nil {
+		for _, vm := range vapp.Children.VM {
+			vappJson.VMs = append(vappJson.VMs, newVMJson(vm))
+		}
+	}
+	return vappJson
+}
+
+func newVMJson(vm *types.VM) VMJson {
+	vmJson := VMJson{
+		ID:          vm.ID,
+		Name:        vm.Name,
+		Href:        vm.HREF,
+		Description: vm.Description,
+		Status:      types.VAppStatuses[vm.Status],
+		Deployed:    vm.Deployed,
+		Disks:       []VMDiskJson{},
+		Nics:        []VMNicJson{},
+		IPs:         []string{},
+	}
+	if vm.VirtualHardwareSection != nil {
+		for _, item := range vm.VirtualHardwareSection.Item {
+			switch item.ResourceType {
+			case types.ResourceTypeProcessor:
+				vmJson.CPUs = item.VirtualQuantity
+				vmJson.CoresPerSocket = item.CoresPerSocket
+			case types.ResourceTypeMemory:
+				vmJson.MemoryMB = item.VirtualQuantity
+			case types.ResourceTypeDisk:
+				disk := VMDiskJson{Name: item.ElementName}
+				if len(item.HostResource) > 0 {
+					disk.SizeMB = item.HostResource[0].Capacity
+				}
+				vmJson.Disks = append(vmJson.Disks, disk)
+			}
+		}
+	}
+	if vm.NetworkConnectionSection != nil {
+		var externalIPs []string
+		for _, connection := range vm.NetworkConnectionSection.NetworkConnection {
+			vmJson.Nics = append(vmJson.Nics, VMNicJson{
+				Index:             connection.NetworkConnectionIndex,
+				Network:           connection.Network,
+				IPAddress:         connection.IPAddress,
+				ExternalIPAddress: connection.ExternalIPAddress,
+				MACAddress:        connection.MACAddress,
+				AllocationMode:    connection.IPAddressAllocationMode,
+				Connected:         connection.IsConnected,
+				Primary:           connection.NetworkConnectionIndex == vm.NetworkConnectionSection.PrimaryNetworkConnectionIndex,
+			})
+			if connection.IPAddress != "" {
+				vmJson.IPs = append(vmJson.IPs, connection.IPAddress)
+			}
+			if connection.ExternalIPAddress != "" {
+				externalIPs = append(externalIPs, connection.ExternalIPAddress)
+			}
+		}
+		vmJson.IPs = append(vmJson.IPs, externalIPs...)
+	}
+	return vmJson
+}
+
+func newNetworkJson(network *types.OrgVDCNetwork) NetworkJson {
+	networkJson := NetworkJson{
+		ID:          network.ID,
+		Name:        network.Name,
+		Href:        network.HREF,
+		Description: network.Description,
+		Shared:      network.IsShared,
+		IPRanges:    []IPRangeJson{},
+	}
+	if network.Configuration == nil {
+		return networkJson
+	}
+	networkJson.FenceMode = network.Configuration.FenceMode
+	if network.Configuration.IPScopes != nil {
+		ipScope := network.Configuration.IPScopes.IPScope
+		networkJson.Gateway = ipScope.Gateway
+		networkJson.Netmask = ipScope.Netmask
+		networkJson.DNS1 = ipScope.DNS1
+		networkJson.DNS2 = ipScope.DNS2
+		networkJson.DNSSuffix = ipScope.DNSSuffix
+		if ipScope.IPRanges != nil {
+			for _, ipRange := range ipScope.IPRanges.IPRange {
+				networkJson.IPRanges = append(networkJson.IPRanges, IPRangeJson{Start: ipRange.StartAddress, End: ipRange.EndAddress})
+			}
+		}
+	}
+	return networkJson
+}
+
+func newVdcJson(vdc *types.Vdc) VdcJson {
+	vdcJson := VdcJson{
+		ID:              vdc.ID,
+		Name:            vdc.Name,
+		Href:            vdc.HREF,
+		Description:     vdc.Description,
+		AllocationModel: vdc.AllocationModel,
+		Enabled:         vdc.IsEnabled,
+		VApps:           []string{},
+		Networks:        []string{},
+	}
+	for _, resourceEntities := range vdc.ResourceEntities {
+		for _, resourceEntity := range resourceEntities.ResourceEntity {
+			if resourceEntity.Type == types.MimeVApp {
+				vdcJson.VApps = append(vdcJson.VApps, resourceEntity.Name)
+			}
+		}
+	}
+	for _, availableNetworks := range vdc.AvailableNetworks {
+		for _, network := range availableNetworks.Network {
+			vdcJson.Networks = append(vdcJson.Networks, network.Name)
+		}
+	}
+	return vdcJson
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// Checks the JSON schema of vApps and VMs, including the computed fields
+func TestVAppMarshalJSON(t *testing.T) {
+	vm := &types.VM{
+		ID:       "urn:vcloud:vm:1",
+		Name:     "vm1",
+		HREF:     "https://vcd/api/vApp/vm-1",
+		Status:   4,
+		Deployed: true,
+		VirtualHardwareSection: &types.VirtualHardwareSection{Item: []*types.VirtualHardwareItem{
+			{ResourceType: types.ResourceTypeProcessor, VirtualQuantity: 2, CoresPerSocket: 1},
+			{ResourceType: types.ResourceTypeMemory, VirtualQuantity: 1024},
+			{ResourceType: types.ResourceTypeDisk, ElementName: "Hard disk 1",
+				HostResource: []*types.VirtualHardwareHostResource{{Capacity: 16384}}},
+		}},
+		NetworkConnectionSection: &types.NetworkConnectionSection{
+			PrimaryNetworkConnectionIndex: 1,
+			NetworkConnection: []*types.NetworkConnection{
+				{Network: "net1", NetworkConnectionIndex: 0, IPAddress: "192.168.1.10", ExternalIPAddress: "10.0.0.10",
+					MACAddress: "00:50:56:01:01:01", IPAddressAllocationMode: types.IPAllocationModePool, IsConnected: true},
+				{Network: "net2", NetworkConnectionIndex: 1, IPAddress: "192.168.2.10",
+					IPAddressAllocationMode: types.IPAllocationModeManual, IsConnected: true},
+			},
+		},
+	}
+	vapp := VApp{VApp: &types.VApp{
+		ID:     "urn:vcloud:vapp:1",
+		Name:   "vapp1",
+		HREF:   "https://vcd/api/vApp/vapp-1",
+		Status: 8,
+		NetworkConfigSection: &types.NetworkConfigSection{NetworkConfig: []types.VAppNetworkConfiguration{
+			{NetworkName: "net1"}, {NetworkName: types.NoneNetwork},
+		}},
+		Children: &types.VAppChildren{VM: []*types.VM{vm}},
+	}}
+
+	output, err := json.Marshal(vapp)
+	if err != nil {
+		t.Fatalf("error marshalling vApp: %s", err)
+	}
+	expected := `{"id":"urn:vcloud:vapp:1","name":"vapp1","href":"https://vcd/api/vApp/vapp-1","description":"",` +
+		`"status":"POWERED_OFF","deployed":false,"networks":["net1"],"vms":[` +
+		`{"id":"urn:vcloud:vm:1","name":"vm1","href":"https://vcd/api/vApp/vm-1","description":"","status":"POWERED_ON",` +
+		`"deployed":true,"cpus":2,"cores_per_socket":1,"memory_mb":1024,"disks":[{"name":"Hard disk 1","size_mb":16384}],` +
+		`"nics":[{"index":0,"network":"net1","ip_address":"192.168.1.10","external_ip_address":"10.0.0.10",` +
+		`"mac_address":"00:50:56:01:01:01","allocation_mode":"POOL","connected":true,"primary":false},` +
+		`{"index":1,"network":"net2","ip_address":"192.168.2.10","external_ip_address":"","mac_address":"",` +
+		`"allocation_mode":"MANUAL","connected":true,"primary":true}],` +
+		`"ips":["192.168.1.10","192.168.2.10","10.0.0.10"]}]}`
+	if string(output) != expected {
+		t.Errorf("unexpected JSON:\n%s\nexpected:\n%s", output, expected)
+	}
+
+	// Lists are empty, not null
+	output, err = json.Marshal(&VM{VM: &types.VM{Name: "empty"}})
+	if err != nil {
+		t.Fatalf("error marshalling VM: %s", err)
+	}
+	var decoded map[string]interface{}
+	_ = json.Unmarshal(output, &decoded)
+	for _, field := range []string{"disks", "nics", "ips"} {
+		if list, ok := decoded[field].([]interface{}); !ok || len(list) != 0 {
+			t.Errorf("expected empty list for %s, got %v", field, decoded[field])
+		}
+	}
+
+	_, err = json.Marshal(VApp{})
+	if err == nil {
+		t.Errorf("expected error for empty vApp")
+	}
+}
+
+// Checks the JSON schema of networks and VDCs
+func TestNetworkAndVdcMarshalJSON(t *testing.T) {
+	network := OrgVDCNetwork{OrgVDCNetwork: &types.OrgVDCNetwork{
+		Name:     "net1",
+		IsShared: true,
+		Configuration: &types.NetworkConfiguration{
+			FenceMode: types.FenceModeNAT,
+			IPScopes: &types.IPScopes{IPScope: types.IPScope{
+				Gateway: "192.168.1.1",
+				Netmask: "255.255.255.0",
+				DNS1:    "8.8.8.8",
+				IPRanges: &types.IPRanges{IPRange: []*types.IPRange{
+					{StartAddress: "192.168.1.10", EndAddress: "192.168.1.20"},
+				}},
+			}},
+		},
+	}}
+	output, err := json.Marshal(network)
+	if err != nil {
+		t.Fatalf("error marshalling network: %s", err)
+	}
+	expected := `{"id":"","name":"net1","href":"","description":"","fence_mode":"natRouted","shared":true,` +
+		`"gateway":"192.168.1.1","netmask":"255.255.255.0","dns1":"8.8.8.8","dns2":"","dns_suffix":"",` +
+		`"ip_ranges":[{"start":"192.168.1.10","end":"192.168.1.20"}]}`
+	if string(output) != expected {
+		t.Errorf("unexpected JSON:\n%s\nexpected:\n%s", output, expected)
+	}
+
+	vdc := Vdc{Vdc: &types.Vdc{
+		Name:            "vdc1",
+		AllocationModel: "AllocationVApp",
+		IsEnabled:       true,
+		ResourceEntities: []*types.ResourceEntities{{ResourceEntity: []*types.ResourceReference{
+			{Name: "vapp1", Type: types.MimeVApp},
+			{Name: "template1", Type: types.MimeVAppTemplate},
+		}}},
+		AvailableNetworks: []*types.AvailableNetworks{{Network: []*types.Reference{{Name: "net1"}}}},
+	}}
+	output, err = json.Marshal(vdc)
+	if err != nil {
+		t.Fatalf("error marshalling VDC: %s", err)
+	}
+	expected = `{"id":"","name":"vdc1","href":"","description":"","allocation_model":"AllocationVApp","enabled":true,` +
+		`"vapps":["vapp1"],"networks":["net1"]}`
+	if string(output) != expected {
+		t.Errorf("unexpected JSON:\n%s\nexpected:\n%s", output, expected)
+	}
+}
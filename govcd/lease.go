@@ -0,0 +1,202 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/util"
+)
+
+// RenewLease sets the runtime (deployment) and storage leases of the vApp. The new leases start
+// from the time of the renewal, which makes an expired vApp usable again.
+// A lease of 0 keeps the current value.
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/PUT-LeaseSettingsSection-vApp.html
+func (vapp *VApp) RenewLease(deploymentLeaseInSeconds, storageLeaseInSeconds int) (Task, error) {
+	util.Logger.Printf("[TRACE] VApp.RenewLease - renewing leases of vApp %s", vapp.VApp.Name)
+
+	if vapp.VApp.HREF == "" {
+		return Task{}, fmt.Errorf("cannot renew lease, Object is empty")
+	}
+	return renewLease(vapp.client, vapp.VApp.HREF, deploymentLeaseInSeconds, storageLeaseInSeconds)
+}
+
+// RenewLease sets the storage lease of the vApp template, starting from the time of the renewal
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/PUT-LeaseSettingsSection-vAppTemplate.html
+func (vAppTemplate *VAppTemplate) RenewLease(storageLeaseInSeconds int) (Task, error) {
+	util.Logger.Printf("[TRACE] VAppTemplate.RenewLease - renewing lease of vApp template %s", vAppTemplate.VAppTemplate.Name)
+
+	if vAppTemplate.VAppTemplate.HREF == "" {
+		return Task{}, fmt.Errorf("cannot renew lease, Object is empty")
+	}
+	return renewLease(vAppTemplate.client, vAppTemplate.VAppTemplate.HREF, 0, storageLeaseInSeconds)
+}
+
+// renewLease sends the lease settings section of the vApp or vApp template found at href
+// with the given leases. Leases of 0 are replaced by the current ones.
+func renewLease(client *Client, href string, deploymentLeaseInSeconds, storageLeaseInSeconds int) (Task, error) {
+	if deploymentLeaseInSeconds < 0 || storageLeaseInSeconds < 0 {
+		return Task{}, fmt.Errorf("leases cannot be negative")
+	}
+	leaseHREF, err := url.ParseRequestURI(href)
+	if err != nil {
+		return Task{}, fmt.Errorf("error parsing url: %s", err)
+	}
+	leaseHREF.Path += "/leaseSettingsSection/"
+
+	current := &types.LeaseSettingsSection{}
+	_, err = client.ExecuteRequest(leaseHREF.String(), http.MethodGet,
+		types.MimeLeaseSettingSection, "error retrieving lease settings: %s", nil, current)
+	if err != nil {
+		return Task{}, err
+	}
+
+	leaseSettings := &types.LeaseSettingsSection{
+		Xmlns:                    types.XMLNamespaceVCloud,
+		Ovf:                      types.XMLNamespaceOVF,
+		Info:                     "Lease settings section",
+		DeploymentLeaseInSeconds: current.DeploymentLeaseInSeconds,
+		StorageLeaseInSeconds:    current.StorageLeaseInSeconds,
+	}
+	if deploymentLeaseInSeconds > 0 {
+		leaseSettings.DeploymentLeaseInSeconds = deploymentLeaseInSeconds
+	}
+	if storageLeaseInSeconds > 0 {
+		leaseSettings.StorageLeaseInSeconds = storageLeaseInSeconds
+	}
+
+	return client.ExecuteTaskRequest(leaseHREF.String(), http.MethodPut,
+		types.MimeLeaseSettingSection, "error renewing lease: %s", leaseSettings)
+}
+
+// QueryExpiredVApps returns the query records of the vApps of the VDC whose lease has expired
+func (vdc *Vdc) QueryExpiredVApps() ([]*types.QueryResultVAppRecordType, error) {
+	records, err := vdc.client.QueryVApps(&QueryOptions{
+		Filter: NewQueryFilter().Equal("isExpired", "true").Equal("vdc", vdc.Vdc.HREF),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error querying expired vApps: %s", err)
+	}
+	return records, nil
+}
+
+// QueryExpiredVAppTemplates returns the query records of the vApp templates of the VDC whose
+// storage lease has expired
+func (vdc *Vdc) QueryExpiredVAppTemplates() ([]*types.QueryResultVAppTemplateRecordType, error) {
+	results, err := vdc.client.QueryAllPages(types.QtVappTemplate, &QueryOptions{
+		Filter: NewQueryFilter().Equal("isExpired", "true").Equal("vdc", vdc.Vdc.HREF),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error querying expired vApp templates: %s", err)
+	}
+	return results.Results.VAppTemplateRecord, nil
+}
+
+// RenewExpiredVApps renews the leases of all the expired vApps of the VDC (see VApp.RenewLease)
+// and returns the names of the renewed vApps
+func (vdc *Vdc) RenewExpiredVApps(deploymentLeaseInSeconds, storageLeaseInSeconds int) ([]string, error) {
+	records, err := vdc.QueryExpiredVApps()
+	if err != nil {
+		return nil, err
+	}
+	var renewed []string
+	for _, record := range records {
+		vapp := NewVApp(vdc.client)
+		vapp.VApp.HREF = record.HREF
+		vapp.VApp.Name = record.Name
+		task, err := vapp.RenewLease(deploymentLeaseInSeconds, storageLeaseInSeconds)
+		if err == nil {
+			err = task.WaitTaskCompletion()
+		}
+		if err != nil {
+			return renewed, fmt.Errorf("error renewing lease of vApp %s: %s", record.Name, err)
+		}
+		renewed = append(renewed, record.Name)
+	}
+	return renewed, nil
+}
+
+// RenewExpiredVAppTemplates renews the storage lease of all the expired vApp templates of the VDC
+// and returns the names of the renewed templates
+func (vdc *Vdc) RenewExpiredVAppTemplates(storageLeaseInSeconds int) ([]string, error) {
+	records, err := vdc.QueryExpiredVAppTemplates()
+	if err != nil {
+		return nil, err
+	}
+	var renewed []string
+	for _, record := range records {
+		vAppTemplate := NewVAppTemplate(vdc.client)
+		vAppTemplate.VAppTemplate.HREF = record.HREF
+		vAppTemplate.VAppTemplate.Name = record.Name
+		task, err := vAppTemplate.RenewLease(storageLeaseInSeconds)
+		if err == nil {
+			err = task.WaitTaskCompletion()
+		}
+		if err != nil {
+			return renewed, fmt.Errorf("error renewing lease of vApp template %s: %s", record.Name, err)
+		}
+		renewed = append(renewed, record.Name)
+	}
+	return renewed, nil
+}
+
+// PurgeExpiredVApps deletes all the expired vApps of the VDC, undeploying them first when needed,
+// and returns the names of the deleted vApps
+func (vdc *Vdc) PurgeExpiredVApps() ([]string, error) {
+	records, err := vdc.QueryExpiredVApps()
+	if err != nil {
+		return nil, err
+	}
+	var purged []string
+	for _, record := range records {
+		vapp := NewVApp(vdc.client)
+		vapp.VApp.HREF = record.HREF
+		vapp.VApp.Name = record.Name
+		if record.Deployed {
+			task, err := vapp.Undeploy()
+			if err == nil {
+				err = task.WaitTaskCompletion()
+			}
+			if err != nil {
+				return purged, fmt.Errorf("error undeploying vApp %s: %s", record.Name, err)
+			}
+		}
+		task, err := vapp.Delete()
+		if err == nil {
+			err = task.WaitTaskCompletion()
+		}
+		if err != nil {
+			return purged, fmt.Errorf("error deleting vApp %s: %s", record.Name, err)
+		}
+		purged = append(purged, record.Name)
+	}
+	return purged, nil
+}
+
+// PurgeExpiredVAppTemplates deletes all the expired vApp templates of the VDC, with their catalog
+// items, and returns the names of the deleted templates
+func (vdc *Vdc) PurgeExpiredVAppTemplates() ([]string, error) {
+	records, err := vdc.QueryExpiredVAppTemplates()
+	if err != nil {
+		return nil, err
+	}
+	var purged []string
+	for _, record := range records {
+		vAppTemplate := NewVAppTemplate(vdc.client)
+		vAppTemplate.VAppTemplate.HREF = record.HREF
+		task, err := vAppTemplate.Delete()
+		if err == nil {
+			err = task.WaitTaskCompletion()
+		}
+		if err != nil {
+			return purged, fmt.Errorf("error deleting vApp template %s: %s", record.Name, err)
+		}
+		purged = append(purged, record.Name)
+	}
+	return purged, nil
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"encoding/xml"
+	"net/http"
+	"testing"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// Renews the leases of the test vApp and checks that the expired vApps and templates can be queried
+func (vcd *TestVCD) Test_RenewVAppLease(check *C) {
+	if vcd.skipVappTests {
+		check.Skip("Skipping test because vapp was not successfully created at setup")
+	}
+	vapp, err := vcd.vdc.FindVAppByName(vcd.vapp.VApp.Name)
+	check.Assert(err, IsNil)
+	check.Assert(vapp.VApp.LeaseSettingsSection, NotNil)
+	storageLease := vapp.VApp.LeaseSettingsSection.StorageLeaseInSeconds
+
+	task, err := vapp.RenewLease(0, 0)
+	check.Assert(err, IsNil)
+	err = task.WaitTaskCompletion()
+	check.Assert(err, IsNil)
+
+	err = vapp.Refresh()
+	check.Assert(err, IsNil)
+	check.Assert(vapp.VApp.LeaseSettingsSection.StorageLeaseInSeconds, Equals, storageLease)
+
+	_, err = vapp.RenewLease(-1, 0)
+	check.Assert(err, NotNil)
+
+	expiredVApps, err := vcd.vdc.QueryExpiredVApps()
+	check.Assert(err, IsNil)
+	for _, record := range expiredVApps {
+		check.Assert(record.Name, Not(Equals), vcd.vapp.VApp.Name)
+	}
+
+	_, err = vcd.vdc.QueryExpiredVAppTemplates()
+	check.Assert(err, IsNil)
+}
+
+// Checks that renewLease keeps the current leases that are not given
+func TestRenewLease(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	const leasePath = "/api/vApp/vapp-1/leaseSettingsSection/"
+	server.HandleXML(http.MethodGet, leasePath, http.StatusOK, `<LeaseSettingsSection xmlns="http://www.vmware.com/vcloud/v1.5">
+<DeploymentLeaseInSeconds>3600</DeploymentLeaseInSeconds>
+<StorageLeaseInSeconds>7200</StorageLeaseInSeconds>
+</LeaseSettingsSection>`)
+	server.HandleXML(http.MethodPut, leasePath, http.StatusAccepted,
+		`<Task xmlns="http://www.vmware.com/vcloud/v1.5" href="{{server}}/api/task/1" status="running"/>`)
+
+	client := &newMockClient(t, server).Client
+	_, err := renewLease(client, server.URL()+"/api/vApp/vapp-1", 0, 86400)
+	if err != nil {
+		t.Fatalf("error renewing lease: %s", err)
+	}
+	puts := server.RequestsTo(http.MethodPut, leasePath)
+	if len(puts) != 1 {
+		t.Fatalf("lease settings not sent")
+	}
+	sent := &types.LeaseSettingsSection{}
+	err = xml.Unmarshal([]byte(puts[0].Body), sent)
+	if err != nil {
+		t.Fatalf("error decoding lease settings: %s", err)
+	}
+	if sent.DeploymentLeaseInSeconds != 3600 || sent.StorageLeaseInSeconds != 86400 {
+		t.Errorf("unexpected lease settings sent: %#v", sent)
+	}
+}
@@ -0,0 +1,200 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// Lookup by name or ID
+//
+// The GetXxxByNameOrId methods retrieve an entity from its container using either its name or its
+// ID, which can be a URN (e.g. urn:vcloud:vapp:55555555-5555-5555-5555-555555555555) or the bare
+// UUID. The ones of the VDCs, vApps and catalogs refresh the container first. An identifier shaped
+// like an ID is looked up as an ID, then as a name. A name shared by several entities is an error,
+// as the entity would be picked at random. When no entity matches, the error returned satisfies
+// errors.Is(err, ErrorEntityNotFound).
+
+// entityReference is the name, ID and HREF of an entity, as found in the links or the children
+// of its container
+type entityReference struct {
+	name string
+	id   string
+	href string
+}
+
+// uuidOf returns the UUID at the end of an ID or an HREF, lower case, or "" when there is none
+func uuidOf(idOrHref string) string {
+	return strings.ToLower(urnUuid.FindString(idOrHref))
+}
+
+// findEntityReference returns the reference whose ID or name is identifier. entityType and
+// container describe the entity and where it was looked for, in the error messages.
+func findEntityReference(entityType, container, identifier string, references []entityReference) (entityReference, error) {
+	if identifier == "" {
+		return entityReference{}, fmt.Errorf("empty %s name or ID", entityType)
+	}
+
+	// A bare UUID or a URN ending with one
+	uuid := uuidOf(identifier)
+	if uuid != "" && (len(identifier) == len(uuid) || strings.HasPrefix(identifier, "urn:")) {
+		for _, reference := range references {
+			if reference.id == identifier || uuidOf(reference.id) == uuid || uuidOf(reference.href) == uuid {
+				return reference, nil
+			}
+		}
+	}
+
+	var found []entityReference
+	for _, reference := range references {
+		if reference.name == identifier {
+			found = append(found, reference)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return entityReference{}, wrapErrorf(ErrorEntityNotFound, "%s '%s' not found in %s: %s",
+			entityType, identifier, container, ErrorEntityNotFound)
+	case 1:
+		return found[0], nil
+	default:
+		return entityReference{}, fmt.Errorf("%d entities of type %s named '%s' found in %s: use the ID instead",
+			len(found), entityType, identifier, container)
+	}
+}
+
+// linkReferences returns the references of the links with the given relation and type
+func linkReferences(links types.LinkList, rel, linkType string) []entityReference {
+	var references []entityReference
+	for _, link := range links {
+		if link.Rel == rel && link.Type == linkType {
+			references = append(references, entityReference{name: link.Name, id: link.ID, href: link.HREF})
+		}
+	}
+	return references
+}
+
+// GetVAppByNameOrId retrieves the vApp of the VDC with the given name or ID
+func (vdc *Vdc) GetVAppByNameOrId(identifier string) (*VApp, error) {
+	err := vdc.Refresh()
+	if err != nil {
+		return nil, fmt.Errorf("error refreshing vdc: %s", err)
+	}
+	var references []entityReference
+	for _, resourceEntities := range vdc.Vdc.ResourceEntities {
+		for _, resourceEntity := range resourceEntities.ResourceEntity {
+			if resourceEntity.Type == types.MimeVApp {
+				references = append(references, entityReference{
+					name: resourceEntity.Name, id: resourceEntity.ID, href: resourceEntity.HREF})
+			}
+		}
+	}
+	reference, err := findEntityReference("vApp", "VDC "+vdc.Vdc.Name, identifier, references)
+	if err != nil {
+		return nil, err
+	}
+
+	vapp := NewVApp(vdc.client)
+	_, err = vdc.client.ExecuteRequest(reference.href, http.MethodGet,
+		"", "error retrieving vApp: %s", nil, vapp.VApp)
+	if err != nil {
+		return nil, err
+	}
+	return vapp, nil
+}
+
+// GetVMByNameOrId retrieves the VM of the vApp with the given name or ID
+func (vapp *VApp) GetVMByNameOrId(identifier string) (*VM, error) {
+	err := vapp.Refresh()
+	if err != nil {
+		return nil, fmt.Errorf("error refreshing vApp: %s", err)
+	}
+	var references []entityReference
+	if vapp.VApp.Children != nil {
+		for _, child := range vapp.VApp.Children.VM {
+			references = append(references, entityReference{name: child.Name, id: child.ID, href: child.HREF})
+		}
+	}
+	reference, err := findEntityReference("VM", "vApp "+vapp.VApp.Name, identifier, references)
+	if err != nil {
+		return nil, err
+	}
+
+	vm := NewVM(vapp.client)
+	_, err = vapp.client.ExecuteRequest(reference.href, http.MethodGet,
+		"", "error retrieving VM: %s", nil, vm.VM)
+	if err != nil {
+		return nil, err
+	}
+	return vm, nil
+}
+
+// GetCatalogItemByNameOrId retrieves the item of the catalog with the given name or ID
+func (cat *Catalog) GetCatalogItemByNameOrId(identifier string) (*CatalogItem, error) {
+	refreshed := &types.Catalog{}
+	_, err := cat.client.ExecuteRequest(cat.Catalog.HREF, http.MethodGet,
+		"", "error refreshing catalog: %s", nil, refreshed)
+	if err != nil {
+		return nil, err
+	}
+	cat.Catalog = refreshed
+
+	var references []entityReference
+	for _, catalogItems := range cat.Catalog.CatalogItems {
+		for _, catalogItem := range catalogItems.CatalogItem {
+			references = append(references, entityReference{name: catalogItem.Name, id: catalogItem.ID, href: catalogItem.HREF})
+		}
+	}
+	reference, err := findEntityReference("catalog item", "catalog "+cat.Catalog.Name, identifier, references)
+	if err != nil {
+		return nil, err
+	}
+
+	catalogItem := NewCatalogItem(cat.client)
+	_, err = cat.client.ExecuteRequest(reference.href, http.MethodGet,
+		"", "error retrieving catalog item: %s", nil, catalogItem.CatalogItem)
+	if err != nil {
+		return nil, err
+	}
+	return catalogItem, nil
+}
+
+// GetCatalogByNameOrId retrieves the catalog of the org with the given name or ID
+func (org *Org) GetCatalogByNameOrId(identifier string) (*Catalog, error) {
+	reference, err := findEntityReference("catalog", "org "+org.Org.Name, identifier,
+		linkReferences(org.Org.Link, "down", types.MimeCatalog))
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := NewCatalog(org.client)
+	_, err = org.client.ExecuteRequest(reference.href, http.MethodGet,
+		"", "error retrieving catalog: %s", nil, catalog.Catalog)
+	if err != nil {
+		return nil, err
+	}
+	return catalog, nil
+}
+
+// GetVdcByNameOrId retrieves the VDC of the org with the given name or ID
+func (org *Org) GetVdcByNameOrId(identifier string) (*Vdc, error) {
+	reference, err := findEntityReference("VDC", "org "+org.Org.Name, identifier,
+		linkReferences(org.Org.Link, "down", types.MimeVDC))
+	if err != nil {
+		return nil, err
+	}
+
+	vdc := NewVdc(org.client)
+	_, err = org.client.ExecuteRequest(reference.href, http.MethodGet,
+		"", "error retrieving vdc: %s", nil, vdc.Vdc)
+	if err != nil {
+		return nil, err
+	}
+	return vdc, nil
+}
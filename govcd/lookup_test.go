@@ -0,0 +1,117 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// Tests the lookup of entities by name or ID against a fake vCD
+func TestGetByNameOrId(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	const vappId = "55555555-5555-5555-5555-555555555555"
+	const vmId = "66666666-6666-6666-6666-666666666666"
+	const itemId = "77777777-7777-7777-7777-777777777777"
+	const vappPath = "/api/vApp/vapp-" + vappId
+	const vmPath = "/api/vApp/vm-" + vmId
+	const itemPath = "/api/catalogItem/" + itemId
+	server.HandleXML(http.MethodGet, vcdtest.MockVdcPath, http.StatusOK,
+		`<Vdc xmlns="http://www.vmware.com/vcloud/v1.5" name="`+vcdtest.MockVdcName+`" href="{{server}}`+vcdtest.MockVdcPath+`">
+		  <ResourceEntities>
+		    <ResourceEntity type="application/vnd.vmware.vcloud.vApp+xml" name="app1" href="{{server}}`+vappPath+`"/>
+		    <ResourceEntity type="application/vnd.vmware.vcloud.vApp+xml" name="dup" href="{{server}}/api/vApp/vapp-88888888-8888-8888-8888-888888888888"/>
+		    <ResourceEntity type="application/vnd.vmware.vcloud.vApp+xml" name="dup" href="{{server}}/api/vApp/vapp-99999999-9999-9999-9999-999999999999"/>
+		    <ResourceEntity type="application/vnd.vmware.vcloud.vAppTemplate+xml" name="template1" href="{{server}}/api/vAppTemplate/vappTemplate-44444444-4444-4444-4444-444444444444"/>
+		  </ResourceEntities>
+		</Vdc>`)
+	server.HandleXML(http.MethodGet, vappPath, http.StatusOK,
+		`<VApp xmlns="http://www.vmware.com/vcloud/v1.5" name="app1" id="urn:vcloud:vapp:`+vappId+`" href="{{server}}`+vappPath+`">
+		  <Children>
+		    <Vm name="vm1" id="urn:vcloud:vm:`+vmId+`" href="{{server}}`+vmPath+`"/>
+		  </Children>
+		</VApp>`)
+	server.HandleXML(http.MethodGet, vmPath, http.StatusOK,
+		`<Vm xmlns="http://www.vmware.com/vcloud/v1.5" name="vm1" id="urn:vcloud:vm:`+vmId+`" href="{{server}}`+vmPath+`"/>`)
+	server.HandleXML(http.MethodGet, vcdtest.MockCatalogPath, http.StatusOK,
+		`<Catalog xmlns="http://www.vmware.com/vcloud/v1.5" name="`+vcdtest.MockCatalogName+`" href="{{server}}`+vcdtest.MockCatalogPath+`">
+		  <CatalogItems>
+		    <CatalogItem type="application/vnd.vmware.vcloud.catalogItem+xml" name="item1" id="`+itemId+`" href="{{server}}`+itemPath+`"/>
+		  </CatalogItems>
+		</Catalog>`)
+	server.HandleXML(http.MethodGet, itemPath, http.StatusOK,
+		`<CatalogItem xmlns="http://www.vmware.com/vcloud/v1.5" name="item1" id="urn:vcloud:catalogitem:`+itemId+`" href="{{server}}`+itemPath+`"/>`)
+
+	vcdClient := newMockClient(t, server)
+	org, err := GetOrgByName(vcdClient, vcdtest.MockOrgName)
+	if err != nil {
+		t.Fatalf("error retrieving org: %s", err)
+	}
+
+	vdc, err := org.GetVdcByNameOrId("urn:vcloud:vdc:" + vcdtest.MockVdcId)
+	if err != nil {
+		t.Fatalf("error retrieving VDC by ID: %s", err)
+	}
+	if vdc.Vdc.Name != vcdtest.MockVdcName {
+		t.Errorf("unexpected VDC: %#v", vdc.Vdc)
+	}
+	catalog, err := org.GetCatalogByNameOrId(vcdtest.MockCatalogName)
+	if err != nil {
+		t.Fatalf("error retrieving catalog by name: %s", err)
+	}
+
+	for _, identifier := range []string{"app1", vappId, "urn:vcloud:vapp:" + vappId} {
+		vapp, err := vdc.GetVAppByNameOrId(identifier)
+		if err != nil {
+			t.Fatalf("error retrieving vApp %s: %s", identifier, err)
+		}
+		if vapp.VApp.Name != "app1" {
+			t.Errorf("unexpected vApp for %s: %#v", identifier, vapp.VApp)
+		}
+	}
+	_, err = vdc.GetVAppByNameOrId("dup")
+	if err == nil || errors.Is(err, ErrorEntityNotFound) {
+		t.Errorf("expected error retrieving vApp with duplicate name, got %v", err)
+	}
+	for _, identifier := range []string{"template1", "unknown", "urn:vcloud:vapp:00000000-0000-0000-0000-000000000000"} {
+		_, err = vdc.GetVAppByNameOrId(identifier)
+		if !errors.Is(err, ErrorEntityNotFound) {
+			t.Errorf("expected entity not found error for vApp %s, got %v", identifier, err)
+		}
+	}
+
+	vapp, err := vdc.GetVAppByNameOrId("app1")
+	if err != nil {
+		t.Fatalf("error retrieving vApp: %s", err)
+	}
+	vm, err := vapp.GetVMByNameOrId("urn:vcloud:vm:" + vmId)
+	if err != nil {
+		t.Fatalf("error retrieving VM by ID: %s", err)
+	}
+	if vm.VM.Name != "vm1" {
+		t.Errorf("unexpected VM: %#v", vm.VM)
+	}
+	_, err = vapp.GetVMByNameOrId("vm2")
+	if !errors.Is(err, ErrorEntityNotFound) {
+		t.Errorf("expected entity not found error for VM, got %v", err)
+	}
+
+	item, err := catalog.GetCatalogItemByNameOrId(itemId)
+	if err != nil {
+		t.Fatalf("error retrieving catalog item by ID: %s", err)
+	}
+	if item.CatalogItem.Name != "item1" {
+		t.Errorf("unexpected catalog item: %#v", item.CatalogItem)
+	}
+	_, err = catalog.GetCatalogItemByNameOrId("")
+	if err == nil {
+		t.Errorf("expected error retrieving catalog item with empty name")
+	}
+}
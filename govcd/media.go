@@ -78,16 +78,23 @@ func (vdc *Vdc) UploadMediaImage(mediaName, mediaDescription, filePath string, u
 		return UploadTask{}, fmt.Errorf("[ERROR] Issue creating media: %#v", err)
 	}
 
-	return executeUpload(vdc.client, mediaItem, mediaFilePath, mediaName, fileSize, uploadPieceSize)
+	return executeUpload(vdc.client, mediaItem, mediaFilePath, mediaName, fileSize, uploadPieceSize, nil)
 }
 
-func executeUpload(client *Client, mediaItem *types.Media, mediaFilePath, mediaName string, fileSize, uploadPieceSize int64) (UploadTask, error) {
+func executeUpload(client *Client, mediaItem *types.Media, mediaFilePath, mediaName string, fileSize, uploadPieceSize int64, progress UploadProgressFunc) (UploadTask, error) {
 	uploadLink, err := getUploadLink(mediaItem.Files)
 	if err != nil {
 		return UploadTask{}, fmt.Errorf("[ERROR] Issue getting upload link: %#v", err)
 	}
 
 	callBack, uploadProgress := getCallBackFunction()
+	if progress != nil {
+		updateProgress := callBack
+		callBack = func(bytesUploaded, totalSize int64) {
+			updateProgress(bytesUploaded, totalSize)
+			progress(bytesUploaded, totalSize)
+		}
+	}
 
 	uploadError := *new(error)
 
@@ -337,3 +344,58 @@ func FindMediaAsCatalogItem(org *Org, catalogName, mediaName string) (CatalogIte
 	}
 	return media, nil
 }
+
+// QueryMediaList returns the media records of the catalog
+func (cat *Catalog) QueryMediaList() ([]*types.MediaRecordType, error) {
+	options := &QueryOptions{Filter: NewQueryFilter().Equal("catalog", cat.Catalog.HREF)}
+	if cat.client.IsSysAdmin {
+		return cat.client.QueryAdminMedia(options)
+	}
+	return cat.client.QueryMedia(options)
+}
+
+// GetMediaByName returns the media item of the catalog with the given name
+func (cat *Catalog) GetMediaByName(mediaName string) (*MediaItem, error) {
+	if mediaName == "" {
+		return nil, errors.New("media name is empty")
+	}
+	mediaList, err := cat.QueryMediaList()
+	if err != nil {
+		return nil, fmt.Errorf("error querying media of catalog %s: %s", cat.Catalog.Name, err)
+	}
+	for _, media := range mediaList {
+		if media.Name == mediaName {
+			mediaItem := NewMediaItem(cat.client)
+			mediaItem.MediaItem = media
+			return mediaItem, nil
+		}
+	}
+	return nil, fmt.Errorf("media %s not found in catalog %s", mediaName, cat.Catalog.Name)
+}
+
+// RemoveMediaIfExists deletes the media item of the catalog with the given name, if there is one,
+// and waits for the deletion to complete
+func (cat *Catalog) RemoveMediaIfExists(mediaName string) error {
+	mediaList, err := cat.QueryMediaList()
+	if err != nil {
+		return fmt.Errorf("error querying media of catalog %s: %s", cat.Catalog.Name, err)
+	}
+	for _, media := range mediaList {
+		if media.Name != mediaName {
+			continue
+		}
+		mediaItem := NewMediaItem(cat.client)
+		mediaItem.MediaItem = media
+		task, err := mediaItem.Delete()
+		if err != nil {
+			return fmt.Errorf("error deleting media %s: %s", mediaName, err)
+		}
+		err = task.WaitTaskCompletion()
+		if err != nil {
+			return fmt.Errorf("error waiting for the deletion of media %s: %s", mediaName, err)
+		}
+		return nil
+	}
+	util.Logger.Printf("[TRACE] media %s not found in catalog %s", mediaName, cat.Catalog.Name)
+	return nil
+}
@@ -6,8 +6,13 @@ package govcd
 
 import (
 	"io/ioutil"
+	"net/http"
 	"os"
+	"strings"
+	"testing"
 
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
 	. "gopkg.in/check.v1"
 )
 
@@ -164,3 +169,49 @@ func (vcd *TestVCD) Test_FindMediaAsCatalogItem(check *C) {
 	check.Assert(catalogItem.CatalogItem.Name, Equals, itemName)
 
 }
+
+// Checks the lookup and removal of the media of a catalog
+func TestCatalog_Media(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	server.HandleXML(http.MethodGet, "/api/query", http.StatusOK,
+		`<QueryResultRecords xmlns="http://www.vmware.com/vcloud/v1.5" total="2" page="1" pageSize="128">
+  <MediaRecord name="boot.iso" href="{{server}}/api/media/media-1" catalogName="`+vcdtest.MockCatalogName+`"/>
+  <MediaRecord name="tools.iso" href="{{server}}/api/media/media-2" catalogName="`+vcdtest.MockCatalogName+`"/>
+</QueryResultRecords>`)
+	server.HandleXML(http.MethodDelete, "/api/media/media-2", http.StatusAccepted,
+		`<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="running" href="{{server}}`+vcdtest.MockTaskPath+`"/>`)
+
+	vcdClient := newMockClient(t, server)
+	catalog := NewCatalog(&vcdClient.Client)
+	catalog.Catalog = &types.Catalog{Name: vcdtest.MockCatalogName, HREF: server.URL() + vcdtest.MockCatalogPath}
+
+	media, err := catalog.GetMediaByName("tools.iso")
+	if err != nil {
+		t.Fatalf("error retrieving media: %s", err)
+	}
+	if media.MediaItem.HREF != server.URL()+"/api/media/media-2" {
+		t.Errorf("unexpected media: %+v", media.MediaItem)
+	}
+	requests := server.RequestsTo(http.MethodGet, "/api/query")
+	if len(requests) != 1 || !strings.Contains(requests[0].RawQuery, "type=media") ||
+		!strings.Contains(requests[0].RawQuery, "catalog==") {
+		t.Errorf("unexpected media queries: %+v", requests)
+	}
+	_, err = catalog.GetMediaByName("missing.iso")
+	if err == nil {
+		t.Errorf("expected error retrieving missing media")
+	}
+
+	err = catalog.RemoveMediaIfExists("tools.iso")
+	if err != nil {
+		t.Fatalf("error removing media: %s", err)
+	}
+	err = catalog.RemoveMediaIfExists("missing.iso")
+	if err != nil {
+		t.Fatalf("error removing missing media: %s", err)
+	}
+	if deletions := server.RequestsTo(http.MethodDelete, "/api/media/media-2"); len(deletions) != 1 {
+		t.Errorf("expected 1 media deletion, got %d", len(deletions))
+	}
+}
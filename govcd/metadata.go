@@ -0,0 +1,649 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// The metadata of VApp and VM is managed by the methods in vapp.go and vm.go.
+// The functions below provide the shared implementation for all the entities that support metadata.
+// Entities which are only writable by administrators (Vdc, Catalog and OrgVDCNetwork) read the
+// metadata from their own HREF and modify it through the corresponding admin HREF.
+
+// GetMetadata returns the metadata of the VDC
+func (vdc *Vdc) GetMetadata() (*types.Metadata, error) {
+	return getMetadata(vdc.client, vdc.Vdc.HREF)
+}
+
+// AddMetadata adds or updates a metadata entry of the VDC. Requires system administrator privileges.
+func (vdc *Vdc) AddMetadata(key string, value string) (Task, error) {
+	return addMetadata(vdc.client, key, value, getAdminHref(vdc.Vdc.HREF))
+}
+
+// MergeMetadata adds or updates the given metadata entries of the VDC with a single task.
+// Requires system administrator privileges.
+func (vdc *Vdc) MergeMetadata(metadata map[string]string) (Task, error) {
+	return mergeMetadata(vdc.client, metadata, getAdminHref(vdc.Vdc.HREF))
+}
+
+// SetMetadataMap applies the given metadata entries to the VDC with a single task and, when
+// replaceAll is true, removes the entries which are not in the map.
+// Requires system administrator privileges.
+func (vdc *Vdc) SetMetadataMap(metadata map[string]types.TypedValue, replaceAll bool) error {
+	return setMetadataMap(vdc.client, metadata, replaceAll, getAdminHref(vdc.Vdc.HREF))
+}
+
+// DeleteMetadata removes a metadata entry of the VDC. Requires system administrator privileges.
+func (vdc *Vdc) DeleteMetadata(key string) (Task, error) {
+	return deleteMetadata(vdc.client, key, getAdminHref(vdc.Vdc.HREF))
+}
+
+// AddMetadataEntry adds or updates a metadata entry of any type (types.Metadata*Value) of the VDC.
+// A nil domain places the entry in the GENERAL domain, while the SYSTEM domain requires system
+// administrator privileges.
+// Requires system administrator privileges.
+func (vdc *Vdc) AddMetadataEntry(key string, value types.TypedValue, domain *types.MetadataDomainTag) (Task, error) {
+	return addMetadataEntry(vdc.client, key, value, domain, getAdminHref(vdc.Vdc.HREF))
+}
+
+// DeleteMetadataEntry removes a metadata entry of the VDC from the given domain
+// (types.MetadataDomainGeneral or types.MetadataDomainSystem).
+// Requires system administrator privileges.
+func (vdc *Vdc) DeleteMetadataEntry(key, domain string) (Task, error) {
+	return deleteMetadataEntry(vdc.client, key, domain, getAdminHref(vdc.Vdc.HREF))
+}
+
+// GetMetadata returns the metadata of the admin VDC
+func (adminVdc *AdminVdc) GetMetadata() (*types.Metadata, error) {
+	return getMetadata(adminVdc.client, adminVdc.AdminVdc.HREF)
+}
+
+// AddMetadata adds or updates a metadata entry of the admin VDC
+func (adminVdc *AdminVdc) AddMetadata(key string, value string) (Task, error) {
+	return addMetadata(adminVdc.client, key, value, adminVdc.AdminVdc.HREF)
+}
+
+// MergeMetadata adds or updates the given metadata entries of the admin VDC with a single task
+func (adminVdc *AdminVdc) MergeMetadata(metadata map[string]string) (Task, error) {
+	return mergeMetadata(adminVdc.client, metadata, adminVdc.AdminVdc.HREF)
+}
+
+// SetMetadataMap applies the given metadata entries to the admin VDC with a single task and, when
+// replaceAll is true, removes the entries which are not in the map.
+func (adminVdc *AdminVdc) SetMetadataMap(metadata map[string]types.TypedValue, replaceAll bool) error {
+	return setMetadataMap(adminVdc.client, metadata, replaceAll, adminVdc.AdminVdc.HREF)
+}
+
+// DeleteMetadata removes a metadata entry of the admin VDC
+func (adminVdc *AdminVdc) DeleteMetadata(key string) (Task, error) {
+	return deleteMetadata(adminVdc.client, key, adminVdc.AdminVdc.HREF)
+}
+
+// AddMetadataEntry adds or updates a metadata entry of any type (types.Metadata*Value) of the VDC.
+// A nil domain places the entry in the GENERAL domain, while the SYSTEM domain requires system
+// administrator privileges.
+func (adminVdc *AdminVdc) AddMetadataEntry(key string, value types.TypedValue, domain *types.MetadataDomainTag) (Task, error) {
+	return addMetadataEntry(adminVdc.client, key, value, domain, adminVdc.AdminVdc.HREF)
+}
+
+// DeleteMetadataEntry removes a metadata entry of the VDC from the given domain
+// (types.MetadataDomainGeneral or types.MetadataDomainSystem).
+func (adminVdc *AdminVdc) DeleteMetadataEntry(key, domain string) (Task, error) {
+	return deleteMetadataEntry(adminVdc.client, key, domain, adminVdc.AdminVdc.HREF)
+}
+
+// GetMetadata returns the metadata of the organization
+func (adminOrg *AdminOrg) GetMetadata() (*types.Metadata, error) {
+	return getMetadata(adminOrg.client, adminOrg.AdminOrg.HREF)
+}
+
+// AddMetadata adds or updates a metadata entry of the organization
+func (adminOrg *AdminOrg) AddMetadata(key string, value string) (Task, error) {
+	return addMetadata(adminOrg.client, key, value, adminOrg.AdminOrg.HREF)
+}
+
+// MergeMetadata adds or updates the given metadata entries of the organization with a single task
+func (adminOrg *AdminOrg) MergeMetadata(metadata map[string]string) (Task, error) {
+	return mergeMetadata(adminOrg.client, metadata, adminOrg.AdminOrg.HREF)
+}
+
+// SetMetadataMap applies the given metadata entries to the organization with a single task and, when
+// replaceAll is true, removes the entries which are not in the map.
+func (adminOrg *AdminOrg) SetMetadataMap(metadata map[string]types.TypedValue, replaceAll bool) error {
+	return setMetadataMap(adminOrg.client, metadata, replaceAll, adminOrg.AdminOrg.HREF)
+}
+
+// DeleteMetadata removes a metadata entry of the organization
+func (adminOrg *AdminOrg) DeleteMetadata(key string) (Task, error) {
+	return deleteMetadata(adminOrg.client, key, adminOrg.AdminOrg.HREF)
+}
+
+// AddMetadataEntry adds or updates a metadata entry of any type (types.Metadata*Value) of the organization.
+// A nil domain places the entry in the GENERAL domain, while the SYSTEM domain requires system
+// administrator privileges.
+func (adminOrg *AdminOrg) AddMetadataEntry(key string, value types.TypedValue, domain *types.MetadataDomainTag) (Task, error) {
+	return addMetadataEntry(adminOrg.client, key, value, domain, adminOrg.AdminOrg.HREF)
+}
+
+// DeleteMetadataEntry removes a metadata entry of the organization from the given domain
+// (types.MetadataDomainGeneral or types.MetadataDomainSystem).
+func (adminOrg *AdminOrg) DeleteMetadataEntry(key, domain string) (Task, error) {
+	return deleteMetadataEntry(adminOrg.client, key, domain, adminOrg.AdminOrg.HREF)
+}
+
+// GetMetadata returns the metadata of the catalog
+func (catalog *Catalog) GetMetadata() (*types.Metadata, error) {
+	return getMetadata(catalog.client, catalog.Catalog.HREF)
+}
+
+// AddMetadata adds or updates a metadata entry of the catalog. Requires organization administrator privileges.
+func (catalog *Catalog) AddMetadata(key string, value string) (Task, error) {
+	return addMetadata(catalog.client, key, value, getAdminHref(catalog.Catalog.HREF))
+}
+
+// MergeMetadata adds or updates the given metadata entries of the catalog with a single task.
+// Requires organization administrator privileges.
+func (catalog *Catalog) MergeMetadata(metadata map[string]string) (Task, error) {
+	return mergeMetadata(catalog.client, metadata, getAdminHref(catalog.Catalog.HREF))
+}
+
+// SetMetadataMap applies the given metadata entries to the catalog with a single task and, when
+// replaceAll is true, removes the entries which are not in the map.
+// Requires organization administrator privileges.
+func (catalog *Catalog) SetMetadataMap(metadata map[string]types.TypedValue, replaceAll bool) error {
+	return setMetadataMap(catalog.client, metadata, replaceAll, getAdminHref(catalog.Catalog.HREF))
+}
+
+// DeleteMetadata removes a metadata entry of the catalog. Requires organization administrator privileges.
+func (catalog *Catalog) DeleteMetadata(key string) (Task, error) {
+	return deleteMetadata(catalog.client, key, getAdminHref(catalog.Catalog.HREF))
+}
+
+// AddMetadataEntry adds or updates a metadata entry of any type (types.Metadata*Value) of the catalog.
+// A nil domain places the entry in the GENERAL domain, while the SYSTEM domain requires system
+// administrator privileges.
+// Requires organization administrator privileges.
+func (catalog *Catalog) AddMetadataEntry(key string, value types.TypedValue, domain *types.MetadataDomainTag) (Task, error) {
+	return addMetadataEntry(catalog.client, key, value, domain, getAdminHref(catalog.Catalog.HREF))
+}
+
+// DeleteMetadataEntry removes a metadata entry of the catalog from the given domain
+// (types.MetadataDomainGeneral or types.MetadataDomainSystem).
+// Requires organization administrator privileges.
+func (catalog *Catalog) DeleteMetadataEntry(key, domain string) (Task, error) {
+	return deleteMetadataEntry(catalog.client, key, domain, getAdminHref(catalog.Catalog.HREF))
+}
+
+// GetMetadata returns the metadata of the admin catalog
+func (adminCatalog *AdminCatalog) GetMetadata() (*types.Metadata, error) {
+	return getMetadata(adminCatalog.client, adminCatalog.AdminCatalog.HREF)
+}
+
+// AddMetadata adds or updates a metadata entry of the admin catalog
+func (adminCatalog *AdminCatalog) AddMetadata(key string, value string) (Task, error) {
+	return addMetadata(adminCatalog.client, key, value, adminCatalog.AdminCatalog.HREF)
+}
+
+// MergeMetadata adds or updates the given metadata entries of the admin catalog with a single task
+func (adminCatalog *AdminCatalog) MergeMetadata(metadata map[string]string) (Task, error) {
+	return mergeMetadata(adminCatalog.client, metadata, adminCatalog.AdminCatalog.HREF)
+}
+
+// SetMetadataMap applies the given metadata entries to the admin catalog with a single task and, when
+// replaceAll is true, removes the entries which are not in the map.
+func (adminCatalog *AdminCatalog) SetMetadataMap(metadata map[string]types.TypedValue, replaceAll bool) error {
+	return setMetadataMap(adminCatalog.client, metadata, replaceAll, adminCatalog.AdminCatalog.HREF)
+}
+
+// DeleteMetadata removes a metadata entry of the admin catalog
+func (adminCatalog *AdminCatalog) DeleteMetadata(key string) (Task, error) {
+	return deleteMetadata(adminCatalog.client, key, adminCatalog.AdminCatalog.HREF)
+}
+
+// AddMetadataEntry adds or updates a metadata entry of any type (types.Metadata*Value) of the catalog.
+// A nil domain places the entry in the GENERAL domain, while the SYSTEM domain requires system
+// administrator privileges.
+func (adminCatalog *AdminCatalog) AddMetadataEntry(key string, value types.TypedValue, domain *types.MetadataDomainTag) (Task, error) {
+	return addMetadataEntry(adminCatalog.client, key, value, domain, adminCatalog.AdminCatalog.HREF)
+}
+
+// DeleteMetadataEntry removes a metadata entry of the catalog from the given domain
+// (types.MetadataDomainGeneral or types.MetadataDomainSystem).
+func (adminCatalog *AdminCatalog) DeleteMetadataEntry(key, domain string) (Task, error) {
+	return deleteMetadataEntry(adminCatalog.client, key, domain, adminCatalog.AdminCatalog.HREF)
+}
+
+// GetMetadata returns the metadata of the media item
+func (mediaItem *MediaItem) GetMetadata() (*types.Metadata, error) {
+	return getMetadata(mediaItem.client, mediaItem.MediaItem.HREF)
+}
+
+// AddMetadata adds or updates a metadata entry of the media item
+func (mediaItem *MediaItem) AddMetadata(key string, value string) (Task, error) {
+	return addMetadata(mediaItem.client, key, value, mediaItem.MediaItem.HREF)
+}
+
+// MergeMetadata adds or updates the given metadata entries of the media item with a single task
+func (mediaItem *MediaItem) MergeMetadata(metadata map[string]string) (Task, error) {
+	return mergeMetadata(mediaItem.client, metadata, mediaItem.MediaItem.HREF)
+}
+
+// SetMetadataMap applies the given metadata entries to the media item with a single task and, when
+// replaceAll is true, removes the entries which are not in the map.
+func (mediaItem *MediaItem) SetMetadataMap(metadata map[string]types.TypedValue, replaceAll bool) error {
+	return setMetadataMap(mediaItem.client, metadata, replaceAll, mediaItem.MediaItem.HREF)
+}
+
+// DeleteMetadata removes a metadata entry of the media item
+func (mediaItem *MediaItem) DeleteMetadata(key string) (Task, error) {
+	return deleteMetadata(mediaItem.client, key, mediaItem.MediaItem.HREF)
+}
+
+// AddMetadataEntry adds or updates a metadata entry of any type (types.Metadata*Value) of the media item.
+// A nil domain places the entry in the GENERAL domain, while the SYSTEM domain requires system
+// administrator privileges.
+func (mediaItem *MediaItem) AddMetadataEntry(key string, value types.TypedValue, domain *types.MetadataDomainTag) (Task, error) {
+	return addMetadataEntry(mediaItem.client, key, value, domain, mediaItem.MediaItem.HREF)
+}
+
+// DeleteMetadataEntry removes a metadata entry of the media item from the given domain
+// (types.MetadataDomainGeneral or types.MetadataDomainSystem).
+func (mediaItem *MediaItem) DeleteMetadataEntry(key, domain string) (Task, error) {
+	return deleteMetadataEntry(mediaItem.client, key, domain, mediaItem.MediaItem.HREF)
+}
+
+// GetMetadata returns the metadata of the independent disk
+func (disk *Disk) GetMetadata() (*types.Metadata, error) {
+	return getMetadata(disk.client, disk.Disk.HREF)
+}
+
+// AddMetadata adds or updates a metadata entry of the independent disk
+func (disk *Disk) AddMetadata(key string, value string) (Task, error) {
+	return addMetadata(disk.client, key, value, disk.Disk.HREF)
+}
+
+// MergeMetadata adds or updates the given metadata entries of the independent disk with a single task
+func (disk *Disk) MergeMetadata(metadata map[string]string) (Task, error) {
+	return mergeMetadata(disk.client, metadata, disk.Disk.HREF)
+}
+
+// SetMetadataMap applies the given metadata entries to the independent disk with a single task and, when
+// replaceAll is true, removes the entries which are not in the map.
+func (disk *Disk) SetMetadataMap(metadata map[string]types.TypedValue, replaceAll bool) error {
+	return setMetadataMap(disk.client, metadata, replaceAll, disk.Disk.HREF)
+}
+
+// DeleteMetadata removes a metadata entry of the independent disk
+func (disk *Disk) DeleteMetadata(key string) (Task, error) {
+	return deleteMetadata(disk.client, key, disk.Disk.HREF)
+}
+
+// AddMetadataEntry adds or updates a metadata entry of any type (types.Metadata*Value) of the independent disk.
+// A nil domain places the entry in the GENERAL domain, while the SYSTEM domain requires system
+// administrator privileges.
+func (disk *Disk) AddMetadataEntry(key string, value types.TypedValue, domain *types.MetadataDomainTag) (Task, error) {
+	return addMetadataEntry(disk.client, key, value, domain, disk.Disk.HREF)
+}
+
+// DeleteMetadataEntry removes a metadata entry of the independent disk from the given domain
+// (types.MetadataDomainGeneral or types.MetadataDomainSystem).
+func (disk *Disk) DeleteMetadataEntry(key, domain string) (Task, error) {
+	return deleteMetadataEntry(disk.client, key, domain, disk.Disk.HREF)
+}
+
+// GetMetadata returns the metadata of the org VDC network
+func (orgVdcNet *OrgVDCNetwork) GetMetadata() (*types.Metadata, error) {
+	return getMetadata(orgVdcNet.client, orgVdcNet.OrgVDCNetwork.HREF)
+}
+
+// AddMetadata adds or updates a metadata entry of the org VDC network.
+// Requires organization administrator privileges.
+func (orgVdcNet *OrgVDCNetwork) AddMetadata(key string, value string) (Task, error) {
+	return addMetadata(orgVdcNet.client, key, value, getAdminHref(orgVdcNet.OrgVDCNetwork.HREF))
+}
+
+// MergeMetadata adds or updates the given metadata entries of the org VDC network with a single task.
+// Requires organization administrator privileges.
+func (orgVdcNet *OrgVDCNetwork) MergeMetadata(metadata map[string]string) (Task, error) {
+	return mergeMetadata(orgVdcNet.client, metadata, getAdminHref(orgVdcNet.OrgVDCNetwork.HREF))
+}
+
+// SetMetadataMap applies the given metadata entries to the org VDC network with a single task and, when
+// replaceAll is true, removes the entries which are not in the map.
+// Requires organization administrator privileges.
+func (orgVdcNet *OrgVDCNetwork) SetMetadataMap(metadata map[string]types.TypedValue, replaceAll bool) error {
+	return setMetadataMap(orgVdcNet.client, metadata, replaceAll, getAdminHref(orgVdcNet.OrgVDCNetwork.HREF))
+}
+
+// DeleteMetadata removes a metadata entry of the org VDC network.
+// Requires organization administrator privileges.
+func (orgVdcNet *OrgVDCNetwork) DeleteMetadata(key string) (Task, error) {
+	return deleteMetadata(orgVdcNet.client, key, getAdminHref(orgVdcNet.OrgVDCNetwork.HREF))
+}
+
+// AddMetadataEntry adds or updates a metadata entry of any type (types.Metadata*Value) of the org VDC network.
+// A nil domain places the entry in the GENERAL domain, while the SYSTEM domain requires system
+// administrator privileges.
+// Requires organization administrator privileges.
+func (orgVdcNet *OrgVDCNetwork) AddMetadataEntry(key string, value types.TypedValue, domain *types.MetadataDomainTag) (Task, error) {
+	return addMetadataEntry(orgVdcNet.client, key, value, domain, getAdminHref(orgVdcNet.OrgVDCNetwork.HREF))
+}
+
+// DeleteMetadataEntry removes a metadata entry of the org VDC network from the given domain
+// (types.MetadataDomainGeneral or types.MetadataDomainSystem).
+// Requires organization administrator privileges.
+func (orgVdcNet *OrgVDCNetwork) DeleteMetadataEntry(key, domain string) (Task, error) {
+	return deleteMetadataEntry(orgVdcNet.client, key, domain, getAdminHref(orgVdcNet.OrgVDCNetwork.HREF))
+}
+
+// GetMetadata returns the metadata of the organization. AdminOrg provides the methods to change it.
+func (org *Org) GetMetadata() (*types.Metadata, error) {
+	return getMetadata(org.client, org.Org.HREF)
+}
+
+// GetMetadata returns the metadata of the catalog item
+func (catalogItem *CatalogItem) GetMetadata() (*types.Metadata, error) {
+	return getMetadata(catalogItem.client, catalogItem.CatalogItem.HREF)
+}
+
+// AddMetadata adds or updates a metadata entry of the catalog item
+func (catalogItem *CatalogItem) AddMetadata(key string, value string) (Task, error) {
+	return addMetadata(catalogItem.client, key, value, catalogItem.CatalogItem.HREF)
+}
+
+// MergeMetadata adds or updates the given metadata entries of the catalog item with a single task
+func (catalogItem *CatalogItem) MergeMetadata(metadata map[string]string) (Task, error) {
+	return mergeMetadata(catalogItem.client, metadata, catalogItem.CatalogItem.HREF)
+}
+
+// SetMetadataMap applies the given metadata entries to the catalog item with a single task and,
+// when replaceAll is true, removes the entries which are not in the map
+func (catalogItem *CatalogItem) SetMetadataMap(metadata map[string]types.TypedValue, replaceAll bool) error {
+	return setMetadataMap(catalogItem.client, metadata, replaceAll, catalogItem.CatalogItem.HREF)
+}
+
+// DeleteMetadata removes a metadata entry of the catalog item
+func (catalogItem *CatalogItem) DeleteMetadata(key string) (Task, error) {
+	return deleteMetadata(catalogItem.client, key, catalogItem.CatalogItem.HREF)
+}
+
+// AddMetadataEntry adds or updates a metadata entry of any type (types.Metadata*Value) of the catalog item.
+// A nil domain places the entry in the GENERAL domain, while the SYSTEM domain requires system
+// administrator privileges.
+func (catalogItem *CatalogItem) AddMetadataEntry(key string, value types.TypedValue, domain *types.MetadataDomainTag) (Task, error) {
+	return addMetadataEntry(catalogItem.client, key, value, domain, catalogItem.CatalogItem.HREF)
+}
+
+// DeleteMetadataEntry removes a metadata entry of the catalog item from the given domain
+// (types.MetadataDomainGeneral or types.MetadataDomainSystem).
+func (catalogItem *CatalogItem) DeleteMetadataEntry(key, domain string) (Task, error) {
+	return deleteMetadataEntry(catalogItem.client, key, domain, catalogItem.CatalogItem.HREF)
+}
+
+// QueryVappsByMetadata returns the query records of the vApps of the VDC which have the metadata
+// entry key with the string value, in the GENERAL domain. QueryFilter.Metadata builds filters for
+// the SYSTEM domain and for values of other types.
+func (vdc *Vdc) QueryVappsByMetadata(key, value string) ([]*types.QueryResultVAppRecordType, error) {
+	if key == "" {
+		return nil, fmt.Errorf("metadata key is required to query vApps")
+	}
+	options := &QueryOptions{
+		Filter: NewQueryFilter().Equal("vdc", vdc.Vdc.HREF).
+			Metadata(key, types.TypedValue{XsiType: types.MetadataStringValue, Value: value}, types.MetadataDomainGeneral),
+	}
+	var records []*types.QueryResultVAppRecordType
+	var err error
+	if vdc.client.IsSysAdmin {
+		records, err = vdc.client.QueryAdminVApps(options)
+	} else {
+		records, err = vdc.client.QueryVApps(options)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error querying vApps by metadata %s: %s", key, err)
+	}
+	return records, nil
+}
+
+// QueryVmsByMetadata returns the query records of the VMs of the vApps of the VDC which have the
+// metadata entry key with the string value, in the GENERAL domain. The VMs of vApp templates are
+// excluded. QueryFilter.Metadata builds filters for the SYSTEM domain and for values of other types.
+func (vdc *Vdc) QueryVmsByMetadata(key, value string) ([]*types.QueryResultVMRecordType, error) {
+	if key == "" {
+		return nil, fmt.Errorf("metadata key is required to query VMs")
+	}
+	options := &QueryOptions{
+		Filter: NewQueryFilter().Equal("vdc", vdc.Vdc.HREF).Equal("isVAppTemplate", "false").
+			Metadata(key, types.TypedValue{XsiType: types.MetadataStringValue, Value: value}, types.MetadataDomainGeneral),
+	}
+	var records []*types.QueryResultVMRecordType
+	var err error
+	if vdc.client.IsSysAdmin {
+		records, err = vdc.client.QueryAdminVms(options)
+	} else {
+		records, err = vdc.client.QueryVms(options)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error querying VMs by metadata %s: %s", key, err)
+	}
+	return records, nil
+}
+
+// getMetadata retrieves the metadata of the entity found at requestUri
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/GET-Metadata.html
+func getMetadata(client *Client, requestUri string) (*types.Metadata, error) {
+	metadata := &types.Metadata{}
+
+	_, err := client.ExecuteRequest(requestUri+"/metadata/", http.MethodGet,
+		types.MimeMetaData, "error retrieving metadata: %s", nil, metadata)
+
+	return metadata, err
+}
+
+// deleteMetadata removes the metadata entry with the given key from the entity found at requestUri
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/DELETE-MetadataEntry.html
+func deleteMetadata(client *Client, key string, requestUri string) (Task, error) {
+	apiEndpoint, err := url.ParseRequestURI(requestUri)
+	if err != nil {
+		return Task{}, fmt.Errorf("error parsing url: %s", err)
+	}
+	apiEndpoint.Path += "/metadata/" + key
+
+	// Return the task
+	return client.ExecuteTaskRequest(apiEndpoint.String(), http.MethodDelete,
+		"", "error deleting metadata: %s", nil)
+}
+
+// addMetadata adds or updates a metadata entry (type MetadataStringValue) of the entity found at requestUri
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/PUT-MetadataValue.html
+func addMetadata(client *Client, key string, value string, requestUri string) (Task, error) {
+	return addMetadataEntry(client, key, types.TypedValue{XsiType: types.MetadataStringValue, Value: value}, nil, requestUri)
+}
+
+// addMetadataEntry adds or updates a metadata entry of any type of the entity found at requestUri.
+// A nil domain places the entry in the GENERAL domain.
+func addMetadataEntry(client *Client, key string, value types.TypedValue, domain *types.MetadataDomainTag, requestUri string) (Task, error) {
+	if key == "" {
+		return Task{}, fmt.Errorf("metadata key can not be empty")
+	}
+	err := validateMetadataValue(key, &value)
+	if err != nil {
+		return Task{}, err
+	}
+	err = validateMetadataDomain(key, domain)
+	if err != nil {
+		return Task{}, err
+	}
+	newMetadata := &types.MetadataValue{
+		Xmlns:      types.XMLNamespaceVCloud,
+		Xsi:        types.XMLNamespaceXSI,
+		Domain:     domain,
+		TypedValue: &value,
+	}
+
+	apiEndpoint, err := url.ParseRequestURI(requestUri)
+	if err != nil {
+		return Task{}, fmt.Errorf("error parsing url: %s", err)
+	}
+	apiEndpoint.Path += "/metadata/" + key
+
+	// Return the task
+	return client.ExecuteTaskRequest(apiEndpoint.String(), http.MethodPut,
+		types.MimeMetaDataValue, "error adding metadata: %s", newMetadata)
+}
+
+// deleteMetadataEntry removes the metadata entry with the given key from the GENERAL (empty
+// domain) or SYSTEM domain of the entity found at requestUri
+func deleteMetadataEntry(client *Client, key, domain string, requestUri string) (Task, error) {
+	switch domain {
+	case "", types.MetadataDomainGeneral:
+		return deleteMetadata(client, key, requestUri)
+	case types.MetadataDomainSystem:
+		return deleteMetadata(client, types.MetadataDomainSystem+"/"+key, requestUri)
+	}
+	return Task{}, fmt.Errorf("invalid domain '%s' for metadata %s", domain, key)
+}
+
+// validateMetadataValue checks the type of a metadata value, setting MetadataStringValue when empty
+func validateMetadataValue(key string, value *types.TypedValue) error {
+	if value.XsiType == "" {
+		value.XsiType = types.MetadataStringValue
+	}
+	switch value.XsiType {
+	case types.MetadataStringValue, types.MetadataNumberValue, types.MetadataBooleanValue, types.MetadataDateTimeValue:
+		return nil
+	}
+	return fmt.Errorf("unsupported type '%s' for metadata %s", value.XsiType, key)
+}
+
+// validateMetadataDomain checks that the visibility of a metadata entry matches its domain
+func validateMetadataDomain(key string, domain *types.MetadataDomainTag) error {
+	if domain == nil {
+		return nil
+	}
+	switch domain.Domain {
+	case types.MetadataDomainGeneral:
+		if domain.Visibility != types.MetadataVisibilityReadWrite {
+			return fmt.Errorf("metadata %s in the %s domain must have visibility %s", key,
+				types.MetadataDomainGeneral, types.MetadataVisibilityReadWrite)
+		}
+	case types.MetadataDomainSystem:
+		if domain.Visibility != types.MetadataVisibilityReadOnly && domain.Visibility != types.MetadataVisibilityPrivate {
+			return fmt.Errorf("metadata %s in the %s domain must have visibility %s or %s", key,
+				types.MetadataDomainSystem, types.MetadataVisibilityReadOnly, types.MetadataVisibilityPrivate)
+		}
+	default:
+		return fmt.Errorf("invalid domain '%s' for metadata %s", domain.Domain, key)
+	}
+	return nil
+}
+
+// mergeMetadata adds or updates several metadata entries (type MetadataStringValue) of the entity
+// found at requestUri with a single request. Keys which are not in the map are not changed.
+func mergeMetadata(client *Client, metadata map[string]string, requestUri string) (Task, error) {
+	typedMetadata := make(map[string]types.TypedValue)
+	for key, value := range metadata {
+		typedMetadata[key] = types.TypedValue{XsiType: types.MetadataStringValue, Value: value}
+	}
+	return mergeTypedMetadata(client, typedMetadata, requestUri)
+}
+
+// mergeTypedMetadata adds or updates several metadata entries of any type of the entity found at
+// requestUri with a single request. Keys which are not in the map are not changed.
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/POST-Metadata.html
+func mergeTypedMetadata(client *Client, metadata map[string]types.TypedValue, requestUri string) (Task, error) {
+	if len(metadata) == 0 {
+		return Task{}, fmt.Errorf("no metadata entries to merge")
+	}
+	newMetadata := &types.Metadata{
+		Xmlns: types.XMLNamespaceVCloud,
+		Xsi:   types.XMLNamespaceXSI,
+	}
+	// Sorted keys give a stable payload
+	var keys []string
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := metadata[key]
+		err := validateMetadataValue(key, &value)
+		if err != nil {
+			return Task{}, err
+		}
+		newMetadata.MetadataEntry = append(newMetadata.MetadataEntry, &types.MetadataEntry{
+			Xmlns:      types.XMLNamespaceVCloud,
+			Xsi:        types.XMLNamespaceXSI,
+			Key:        key,
+			TypedValue: &value,
+		})
+	}
+
+	apiEndpoint, err := url.ParseRequestURI(requestUri)
+	if err != nil {
+		return Task{}, fmt.Errorf("error parsing url: %s", err)
+	}
+	apiEndpoint.Path += "/metadata/"
+
+	// Return the task
+	return client.ExecuteTaskRequest(apiEndpoint.String(), http.MethodPost,
+		types.MimeMetaData, "error merging metadata: %s", newMetadata)
+}
+
+// setMetadataMap applies all the given metadata entries to the entity found at requestUri with a
+// single task and waits for its completion. When replaceAll is true, the entries whose key is not
+// in the map are removed afterwards, one task per key. Entries in the SYSTEM domain are never removed.
+func setMetadataMap(client *Client, metadata map[string]types.TypedValue, replaceAll bool, requestUri string) error {
+	if len(metadata) == 0 && !replaceAll {
+		return fmt.Errorf("no metadata entries to set")
+	}
+	if len(metadata) > 0 {
+		task, err := mergeTypedMetadata(client, metadata, requestUri)
+		if err != nil {
+			return err
+		}
+		err = task.WaitTaskCompletion()
+		if err != nil {
+			return fmt.Errorf("error setting metadata: %s", err)
+		}
+	}
+	if !replaceAll {
+		return nil
+	}
+
+	current, err := getMetadata(client, requestUri)
+	if err != nil {
+		return err
+	}
+	for _, entry := range current.MetadataEntry {
+		if _, found := metadata[entry.Key]; found || entry.IsSystem() {
+			continue
+		}
+		task, err := deleteMetadata(client, entry.Key, requestUri)
+		if err != nil {
+			return err
+		}
+		err = task.WaitTaskCompletion()
+		if err != nil {
+			return fmt.Errorf("error removing metadata %s: %s", entry.Key, err)
+		}
+	}
+	return nil
+}
+
+// getAdminHref returns the admin HREF of an entity, e.g. https://vcd/api/admin/vdc/id for
+// https://vcd/api/vdc/id. HREFs which are already admin HREFs are returned unchanged.
+func getAdminHref(href string) string {
+	if strings.Contains(href, "/api/admin/") {
+		return href
+	}
+	return strings.Replace(href, "/api/", "/api/admin/", 1)
+}
@@ -0,0 +1,303 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// metadataEntity is implemented by all the entities supporting metadata
+type metadataEntity interface {
+	GetMetadata() (*types.Metadata, error)
+	AddMetadata(key string, value string) (Task, error)
+	MergeMetadata(metadata map[string]string) (Task, error)
+	DeleteMetadata(key string) (Task, error)
+}
+
+// Adds, merges and removes metadata on the VDC, the catalog, the org VDC network and the organization
+func (vcd *TestVCD) Test_MetadataOnEntities(check *C) {
+	if vcd.skipAdminTests {
+		check.Skip(fmt.Sprintf(TestRequiresSysAdminPrivileges, check.TestName()))
+	}
+	adminOrg, err := GetAdminOrgByName(vcd.client, vcd.config.VCD.Org)
+	check.Assert(err, IsNil)
+	catalog, err := vcd.org.FindCatalog(vcd.config.VCD.Catalog.Name)
+	check.Assert(err, IsNil)
+	network, err := vcd.vdc.FindVDCNetwork(vcd.config.VCD.Networks[0])
+	check.Assert(err, IsNil)
+
+	entities := map[string]metadataEntity{
+		"vdc":     &vcd.vdc,
+		"catalog": &catalog,
+		"network": &network,
+		"org":     &adminOrg,
+	}
+	for name, entity := range entities {
+		fmt.Printf("Running: %s (%s)\n", check.TestName(), name)
+		testMetadataLifecycle(check, entity)
+	}
+}
+
+func testMetadataLifecycle(check *C, entity metadataEntity) {
+	task, err := entity.AddMetadata("key", "value")
+	check.Assert(err, IsNil)
+	err = task.WaitTaskCompletion()
+	check.Assert(err, IsNil)
+
+	task, err = entity.MergeMetadata(map[string]string{"key": "new value", "key2": "value2"})
+	check.Assert(err, IsNil)
+	err = task.WaitTaskCompletion()
+	check.Assert(err, IsNil)
+
+	metadata, err := entity.GetMetadata()
+	check.Assert(err, IsNil)
+	values := make(map[string]string)
+	for _, entry := range metadata.MetadataEntry {
+		values[entry.Key] = entry.TypedValue.Value
+	}
+	check.Assert(values["key"], Equals, "new value")
+	check.Assert(values["key2"], Equals, "value2")
+
+	for _, key := range []string{"key", "key2"} {
+		task, err = entity.DeleteMetadata(key)
+		check.Assert(err, IsNil)
+		err = task.WaitTaskCompletion()
+		check.Assert(err, IsNil)
+	}
+	metadata, err = entity.GetMetadata()
+	check.Assert(err, IsNil)
+	for _, entry := range metadata.MetadataEntry {
+		check.Assert(entry.Key, Not(Equals), "key")
+		check.Assert(entry.Key, Not(Equals), "key2")
+	}
+}
+
+func TestGetAdminHref(t *testing.T) {
+	tests := map[string]string{
+		"https://vcd.example.com/api/vdc/1234":          "https://vcd.example.com/api/admin/vdc/1234",
+		"https://vcd.example.com/api/network/1234":      "https://vcd.example.com/api/admin/network/1234",
+		"https://vcd.example.com/api/admin/catalog/123": "https://vcd.example.com/api/admin/catalog/123",
+	}
+	for href, expected := range tests {
+		if adminHref := getAdminHref(href); adminHref != expected {
+			t.Errorf("expected admin href %s for %s, got %s", expected, href, adminHref)
+		}
+	}
+}
+
+// Checks that mergeMetadata sends all the entries in one request
+func TestMergeMetadata(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	const metadataPath = "/api/admin/vdc/1234/metadata/"
+	const taskXml = `<Task xmlns="http://www.vmware.com/vcloud/v1.5" href="{{server}}` + vcdtest.MockTaskPath + `" status="running"/>`
+	server.HandleXML(http.MethodPost, metadataPath, http.StatusAccepted, taskXml)
+
+	client := &newMockClient(t, server).Client
+	_, err := mergeMetadata(client, map[string]string{"b": "2", "a": "1"}, getAdminHref(server.URL()+"/api/vdc/1234"))
+	if err != nil {
+		t.Fatalf("error merging metadata: %s", err)
+	}
+	posts := server.RequestsTo(http.MethodPost, metadataPath)
+	if len(posts) != 1 {
+		t.Fatalf("unexpected metadata requests: %#v", posts)
+	}
+	sent := &types.Metadata{}
+	_ = xml.Unmarshal([]byte(posts[0].Body), sent)
+	if sent == nil || len(sent.MetadataEntry) != 2 {
+		t.Fatalf("unexpected metadata sent: %#v", sent)
+	}
+	if sent.MetadataEntry[0].Key != "a" || sent.MetadataEntry[0].TypedValue.Value != "1" ||
+		sent.MetadataEntry[1].Key != "b" || sent.MetadataEntry[1].TypedValue.Value != "2" {
+		t.Errorf("unexpected metadata entries sent")
+	}
+
+	_, err = mergeMetadata(client, nil, server.URL()+"/api/vdc/1234")
+	if err == nil {
+		t.Errorf("expected error merging empty metadata")
+	}
+}
+
+// Checks that setMetadataMap merges the typed entries and removes the keys not in the map, except
+// the ones in the SYSTEM domain
+func TestSetMetadataMap(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	const metadataPath = "/api/disk/1234/metadata/"
+	const taskXml = `<Task xmlns="http://www.vmware.com/vcloud/v1.5" href="{{server}}` + vcdtest.MockTaskPath + `" status="success"/>`
+	server.HandleXML(http.MethodPost, metadataPath, http.StatusAccepted, taskXml)
+	server.HandleXML(http.MethodGet, metadataPath, http.StatusOK, `<Metadata xmlns="http://www.vmware.com/vcloud/v1.5">
+<MetadataEntry><Key>keep</Key><TypedValue><Value>1</Value></TypedValue></MetadataEntry>
+<MetadataEntry><Key>old</Key><TypedValue><Value>x</Value></TypedValue></MetadataEntry>
+<MetadataEntry><Domain>SYSTEM</Domain><Key>system</Key><TypedValue><Value>y</Value></TypedValue></MetadataEntry>
+</Metadata>`)
+	server.HandleXML(http.MethodDelete, metadataPath+"old", http.StatusAccepted, taskXml)
+
+	client := &newMockClient(t, server).Client
+	metadata := map[string]types.TypedValue{
+		"keep":  {XsiType: types.MetadataNumberValue, Value: "1"},
+		"added": {Value: "text"},
+	}
+	err := setMetadataMap(client, metadata, true, server.URL()+"/api/disk/1234")
+	if err != nil {
+		t.Fatalf("error setting metadata: %s", err)
+	}
+	posts := server.RequestsTo(http.MethodPost, metadataPath)
+	if len(posts) != 1 {
+		t.Fatalf("unexpected metadata requests: %#v", posts)
+	}
+	sentBody := posts[0].Body
+	sent := &types.Metadata{}
+	_ = xml.Unmarshal([]byte(sentBody), sent)
+	if len(sent.MetadataEntry) != 2 {
+		t.Fatalf("unexpected metadata sent: %#v", sent)
+	}
+	// xsi:type attributes are not unmarshalled, the raw payload is checked instead
+	if sent.MetadataEntry[0].Key != "added" || !strings.Contains(sentBody, `xsi:type="MetadataStringValue"`) ||
+		!strings.Contains(sentBody, `xsi:type="MetadataNumberValue"`) {
+		t.Errorf("unexpected metadata types sent: %s", sentBody)
+	}
+	if deleted := deletedPaths(server); len(deleted) != 1 || deleted[0] != metadataPath+"old" {
+		t.Errorf("unexpected deleted entries: %v", deleted)
+	}
+
+	err = setMetadataMap(client, map[string]types.TypedValue{"bad": {XsiType: "MetadataBlobValue"}}, false, server.URL()+"/api/disk/1234")
+	if err == nil {
+		t.Errorf("expected error setting metadata of unsupported type")
+	}
+}
+
+// Checks that typed metadata entries are sent with their domain and visibility, and removed from their domain
+func TestAddMetadataEntry(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	const metadataPath = "/api/catalogItem/1234/metadata/"
+	const taskXml = `<Task xmlns="http://www.vmware.com/vcloud/v1.5" href="{{server}}` + vcdtest.MockTaskPath + `" status="running"/>`
+	server.HandleXML(http.MethodPut, metadataPath+"expires", http.StatusAccepted, taskXml)
+	server.HandleXML(http.MethodDelete, metadataPath+"SYSTEM/expires", http.StatusAccepted, taskXml)
+	server.HandleXML(http.MethodDelete, metadataPath+"owner", http.StatusAccepted, taskXml)
+
+	catalogItem := NewCatalogItem(&newMockClient(t, server).Client)
+	catalogItem.CatalogItem.HREF = server.URL() + "/api/catalogItem/1234"
+
+	_, err := catalogItem.AddMetadataEntry("expires",
+		types.TypedValue{XsiType: types.MetadataDateTimeValue, Value: "2030-01-01T00:00:00.000Z"},
+		&types.MetadataDomainTag{Domain: types.MetadataDomainSystem, Visibility: types.MetadataVisibilityReadOnly})
+	if err != nil {
+		t.Fatalf("error adding metadata: %s", err)
+	}
+	sentBody := server.RequestsTo(http.MethodPut, metadataPath+"expires")[0].Body
+	for _, expected := range []string{`<Domain visibility="READONLY">SYSTEM</Domain>`, `xsi:type="MetadataDateTimeValue"`,
+		"<Value>2030-01-01T00:00:00.000Z</Value>"} {
+		if !strings.Contains(sentBody, expected) {
+			t.Errorf("expected %s in metadata payload:\n%s", expected, sentBody)
+		}
+	}
+
+	invalidDomains := []*types.MetadataDomainTag{
+		{Domain: types.MetadataDomainSystem, Visibility: types.MetadataVisibilityReadWrite},
+		{Domain: types.MetadataDomainGeneral, Visibility: types.MetadataVisibilityPrivate},
+		{Domain: "CUSTOM", Visibility: types.MetadataVisibilityReadWrite},
+	}
+	for _, domain := range invalidDomains {
+		_, err = catalogItem.AddMetadataEntry("expires", types.TypedValue{Value: "x"}, domain)
+		if err == nil {
+			t.Errorf("expected error with domain %+v", domain)
+		}
+	}
+
+	_, err = catalogItem.DeleteMetadataEntry("expires", types.MetadataDomainSystem)
+	if err != nil {
+		t.Fatalf("error removing metadata: %s", err)
+	}
+	_, err = catalogItem.DeleteMetadataEntry("owner", "")
+	if err != nil {
+		t.Fatalf("error removing metadata: %s", err)
+	}
+	deleted := deletedPaths(server)
+	if len(deleted) != 2 || deleted[0] != metadataPath+"SYSTEM/expires" || deleted[1] != metadataPath+"owner" {
+		t.Errorf("unexpected deleted entries: %v", deleted)
+	}
+}
+
+// Checks that vApps and VMs are queried in the VDC with the metadata filter
+func TestVdc_QueryByMetadata(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	server.HandleFunc(http.MethodGet, "/api/query", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.vmware.vcloud.query.records+xml")
+		switch r.URL.Query().Get("type") {
+		case types.QtVapp:
+			_, _ = fmt.Fprint(w, `<QueryResultRecords xmlns="http://www.vmware.com/vcloud/v1.5" total="1" page="1" pageSize="128">
+<VAppRecord name="vapp1"/></QueryResultRecords>`)
+		case types.QtAdminVm:
+			_, _ = fmt.Fprint(w, `<QueryResultRecords xmlns="http://www.vmware.com/vcloud/v1.5" total="2" page="1" pageSize="128">
+<AdminVMRecord name="vm1"/><AdminVMRecord name="vm2"/></QueryResultRecords>`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	queries := func() []string {
+		var rawQueries []string
+		for _, request := range server.RequestsTo(http.MethodGet, "/api/query") {
+			rawQueries = append(rawQueries, request.RawQuery)
+		}
+		return rawQueries
+	}
+
+	client := &newMockClient(t, server).Client
+	vdc := NewVdc(client)
+	vdc.Vdc.HREF = server.URL() + "/api/vdc/1234"
+	vdcFilter := "vdc==" + url.QueryEscape(vdc.Vdc.HREF)
+
+	vapps, err := vdc.QueryVappsByMetadata("env", "prod")
+	if err != nil {
+		t.Fatalf("error querying vApps by metadata: %s", err)
+	}
+	if len(vapps) != 1 || vapps[0].Name != "vapp1" {
+		t.Errorf("unexpected vApp records: %+v", vapps)
+	}
+	if !strings.Contains(queries()[0], "filter=("+vdcFilter+";metadata:env==STRING:prod)") {
+		t.Errorf("unexpected vApp query: %s", queries()[0])
+	}
+
+	// System administrators query the VMs of all orgs, restricted to the VDC
+	client.IsSysAdmin = true
+	vms, err := vdc.QueryVmsByMetadata("env", "prod")
+	if err != nil {
+		t.Fatalf("error querying VMs by metadata: %s", err)
+	}
+	if len(vms) != 2 || vms[1].Name != "vm2" {
+		t.Errorf("unexpected VM records: %+v", vms)
+	}
+	if !strings.Contains(queries()[1], "filter=("+vdcFilter+";isVAppTemplate==false;metadata:env==STRING:prod)") {
+		t.Errorf("unexpected VM query: %s", queries()[1])
+	}
+
+	if _, err = vdc.QueryVmsByMetadata("", "prod"); err == nil {
+		t.Errorf("expected error querying VMs without metadata key")
+	}
+}
+
+// deletedPaths returns the paths of the DELETE requests received by the fake vCD
+func deletedPaths(server *vcdtest.Server) []string {
+	var paths []string
+	for _, request := range server.Requests() {
+		if request.Method == http.MethodDelete {
+			paths = append(paths, request.Path)
+		}
+	}
+	return paths
+}
@@ -0,0 +1,23 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// newMockClient returns a client of the fake vCD server, with the given options, logged in to the
+// mock organization. The test fails when the login fails.
+func newMockClient(t *testing.T, server *vcdtest.Server, options ...VCDClientOption) *VCDClient {
+	t.Helper()
+	vcdClient := NewVCDClient(server.ApiURL(), true, options...)
+	err := vcdClient.Authenticate("user", "password", vcdtest.MockOrgName)
+	if err != nil {
+		t.Fatalf("error authenticating to the fake vCD: %s", err)
+	}
+	return vcdClient
+}
@@ -0,0 +1,650 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmware/go-vcloud-director/v2/util"
+)
+
+// MQTT message bus of vCD
+//
+// vCD 10.0+ publishes its notifications on an MQTT broker, reachable through a WebSocket
+// connection to /messaging/mqtt with the credentials of the session. MqttSource is a
+// MessageSource reading the notifications of this broker, so that they can be decoded and
+// dispatched by an EventSubscriber (see VCDClient.SubscribeEvents).
+
+// mqttPath is the path of the WebSocket endpoint of the MQTT broker of vCD
+const mqttPath = "/messaging/mqtt"
+
+// MqttDefaultTopic is the topic of all the notifications the user is allowed to receive
+const MqttDefaultTopic = "publish/#"
+
+// MqttOptions are the settings of the connection to the MQTT message bus
+type MqttOptions struct {
+	Topics    []string      // Topics to subscribe to. MqttDefaultTopic when empty
+	ClientId  string        // MQTT client identifier. A random one when empty
+	KeepAlive time.Duration // Interval of the pings which keep the connection open. 30 seconds when 0
+}
+
+// Types of the MQTT 3.1.1 control packets
+const (
+	mqttConnect     = 1
+	mqttConnack     = 2
+	mqttPublish     = 3
+	mqttPuback      = 4
+	mqttSubscribe   = 8
+	mqttSuback      = 9
+	mqttPingreq     = 12
+	mqttPingresp    = 13
+	mqttDisconnect  = 14
+	mqttMaxQos      = 1
+	mqttDefaultPing = 30 * time.Second
+)
+
+// MqttSource is a MessageSource which receives the messages published on the subscribed topics
+// of the MQTT broker of vCD. The topics are subscribed with QoS 1 at most, and the messages are
+// acknowledged as they are received.
+type MqttSource struct {
+	socket    *websocketConn
+	reader    *bufio.Reader
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewMqttSource connects to the MQTT broker of vCD with the credentials of the client and
+// subscribes to the topics of options, which can be nil. The connection uses the TLS configuration
+// and the HTTP proxy of the client. The client must be authenticated and use API 33.0 (vCD 10.0)
+// or later.
+func (vcdCli *VCDClient) NewMqttSource(options *MqttOptions) (*MqttSource, error) {
+	if !vcdCli.Client.APIClientVersionIs(">= 33.0") {
+		return nil, fmt.Errorf("the MQTT message bus requires API 33.0 (vCD 10.0) or later")
+	}
+	if vcdCli.Client.VCDToken == "" {
+		return nil, fmt.Errorf("the MQTT message bus requires an authenticated client")
+	}
+	mqttOptions := MqttOptions{}
+	if options != nil {
+		mqttOptions = *options
+	}
+	if len(mqttOptions.Topics) == 0 {
+		mqttOptions.Topics = []string{MqttDefaultTopic}
+	}
+	if mqttOptions.ClientId == "" {
+		mqttOptions.ClientId = "govcd-" + randomHex(8)
+	}
+	if mqttOptions.KeepAlive <= 0 {
+		mqttOptions.KeepAlive = mqttDefaultPing
+	}
+
+	socket, err := dialWebsocket(&vcdCli.Client, mqttPath, "mqtt")
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to the MQTT message bus: %s", err)
+	}
+	source := &MqttSource{
+		socket: socket,
+		reader: bufio.NewReader(socket),
+		done:   make(chan struct{}),
+	}
+	err = source.subscribe(mqttOptions)
+	if err != nil {
+		_ = socket.Close()
+		return nil, fmt.Errorf("error subscribing to the MQTT message bus: %s", err)
+	}
+	go source.keepAlive(mqttOptions.KeepAlive)
+	return source, nil
+}
+
+// SubscribeEvents connects to the MQTT message bus of vCD (see NewMqttSource) and returns a
+// subscriber which delivers the events of the given types (see NewEventSubscriber)
+func (vcdCli *VCDClient) SubscribeEvents(options *MqttOptions, eventTypes ...string) (*EventSubscriber, error) {
+	source, err := vcdCli.NewMqttSource(options)
+	if err != nil {
+		return nil, err
+	}
+	return NewEventSubscriber(source, eventTypes...), nil
+}
+
+// Receive returns the payload of the next message published on the subscribed topics
+func (source *MqttSource) Receive() ([]byte, error) {
+	for {
+		packetType, flags, body, err := readMqttPacket(source.reader)
+		if source.closed() {
+			return nil, io.EOF
+		}
+		if err == io.EOF {
+			return nil, fmt.Errorf("the MQTT message bus closed the connection")
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch packetType {
+		case mqttPublish:
+			payload, packetId, err := parseMqttPublish(flags, body)
+			if err != nil {
+				return nil, err
+			}
+			if packetId != nil {
+				err = source.write(mqttPuback<<4, packetId)
+				if err != nil {
+					return nil, fmt.Errorf("error acknowledging MQTT message: %s", err)
+				}
+			}
+			return payload, nil
+		case mqttPingresp, mqttSuback:
+			continue
+		default:
+			return nil, fmt.Errorf("unexpected MQTT packet of type %d", packetType)
+		}
+	}
+}
+
+// Close disconnects from the MQTT broker. A pending Receive returns io.EOF.
+func (source *MqttSource) Close() error {
+	var err error
+	source.closeOnce.Do(func() {
+		close(source.done)
+		_ = source.write(mqttDisconnect<<4, nil)
+		err = source.socket.Close()
+	})
+	return err
+}
+
+// subscribe sends the connection and subscription packets and checks their acknowledgments
+func (source *MqttSource) subscribe(options MqttOptions) error {
+	keepAlive := int(options.KeepAlive / time.Second)
+	if keepAlive > 0xffff {
+		keepAlive = 0xffff
+	}
+	// Protocol MQTT, level 4 (3.1.1), clean session, keep alive and client identifier
+	connect := append(mqttString("MQTT"), 4, 0x02, byte(keepAlive>>8), byte(keepAlive))
+	connect = append(connect, mqttString(options.ClientId)...)
+	err := source.write(mqttConnect<<4, connect)
+	if err != nil {
+		return err
+	}
+	packetType, _, body, err := readMqttPacket(source.reader)
+	if err != nil {
+		return err
+	}
+	if packetType != mqttConnack || len(body) != 2 {
+		return fmt.Errorf("unexpected MQTT packet of type %d instead of the connection acknowledgment", packetType)
+	}
+	if body[1] != 0 {
+		return fmt.Errorf("connection refused by the MQTT broker with code %d", body[1])
+	}
+
+	// Packet identifier 1, then the topics with their maximum QoS
+	subscribe := []byte{0, 1}
+	for _, topic := range options.Topics {
+		subscribe = append(subscribe, mqttString(topic)...)
+		subscribe = append(subscribe, mqttMaxQos)
+	}
+	err = source.write(mqttSubscribe<<4|0x02, subscribe)
+	if err != nil {
+		return err
+	}
+	packetType, _, body, err = readMqttPacket(source.reader)
+	if err != nil {
+		return err
+	}
+	if packetType != mqttSuback || len(body) != 2+len(options.Topics) {
+		return fmt.Errorf("unexpected MQTT packet of type %d instead of the subscription acknowledgment", packetType)
+	}
+	for i, code := range body[2:] {
+		if code == 0x80 {
+			return fmt.Errorf("subscription to topic %s refused by the MQTT broker", options.Topics[i])
+		}
+	}
+	return nil
+}
+
+// keepAlive pings the broker until the source is closed
+func (source *MqttSource) keepAlive(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-source.done:
+			return
+		case <-ticker.C:
+			err := source.write(mqttPingreq<<4, nil)
+			if err != nil {
+				util.Logger.Printf("[TRACE] MqttSource - error sending ping: %s", err)
+				return
+			}
+		}
+	}
+}
+
+// closed tells whether Close was called
+func (source *MqttSource) closed() bool {
+	select {
+	case <-source.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// write sends an MQTT packet with the given first byte (type and flags) and body
+func (source *MqttSource) write(header byte, body []byte) error {
+	packet := []byte{header}
+	length := len(body)
+	for {
+		digit := byte(length % 128)
+		length /= 128
+		if length > 0 {
+			digit |= 0x80
+		}
+		packet = append(packet, digit)
+		if length == 0 {
+			break
+		}
+	}
+	return source.socket.WriteMessage(append(packet, body...))
+}
+
+// readMqttPacket reads an MQTT packet and returns its type, its flags and its body
+func readMqttPacket(reader *bufio.Reader) (byte, byte, []byte, error) {
+	header, err := reader.ReadByte()
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	length, multiplier := 0, 1
+	for i := 0; ; i++ {
+		if i == 4 {
+			return 0, 0, nil, fmt.Errorf("invalid MQTT packet length")
+		}
+		digit, err := reader.ReadByte()
+		if err != nil {
+			return 0, 0, nil, err
+		}
+		length += int(digit&0x7f) * multiplier
+		multiplier *= 128
+		if digit&0x80 == 0 {
+			break
+		}
+	}
+	body := make([]byte, length)
+	_, err = io.ReadFull(reader, body)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	return header >> 4, header & 0x0f, body, nil
+}
+
+// parseMqttPublish returns the payload of a PUBLISH packet, and its packet identifier when the
+// packet must be acknowledged with a PUBACK (QoS 1). QoS 2 messages, which need another exchange,
+// are rejected: the broker does not send them, as the topics are subscribed with QoS 1 at most.
+func parseMqttPublish(flags byte, body []byte) ([]byte, []byte, error) {
+	if len(body) < 2 {
+		return nil, nil, fmt.Errorf("invalid MQTT message")
+	}
+	qos := (flags >> 1) & 0x03
+	if qos > mqttMaxQos {
+		return nil, nil, fmt.Errorf("unsupported MQTT message with QoS %d", qos)
+	}
+	offset := 2 + int(binary.BigEndian.Uint16(body))
+	var packetId []byte
+	if qos == 1 {
+		if len(body) < offset+2 {
+			return nil, nil, fmt.Errorf("invalid MQTT message")
+		}
+		packetId = body[offset : offset+2]
+		offset += 2
+	}
+	if len(body) < offset {
+		return nil, nil, fmt.Errorf("invalid MQTT message")
+	}
+	return body[offset:], packetId, nil
+}
+
+// mqttString encodes a string of an MQTT packet, prefixed by its length
+func mqttString(value string) []byte {
+	return append([]byte{byte(len(value) >> 8), byte(len(value))}, value...)
+}
+
+// randomHex returns size random bytes in hexadecimal
+func randomHex(size int) string {
+	random := make([]byte, size)
+	_, _ = rand.Read(random)
+	return hex.EncodeToString(random)
+}
+
+// WebSocket client (RFC 6455), limited to what the message bus needs: binary messages sent by the
+// client, data frames received from the server as a stream, and the control frames
+
+// websocketGuid is appended to the key of the handshake to compute the accept header
+const websocketGuid = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Opcodes of the WebSocket frames
+const (
+	websocketContinuation = 0x0
+	websocketText         = 0x1
+	websocketBinary       = 0x2
+	websocketClose        = 0x8
+	websocketPing         = 0x9
+	websocketPong         = 0xa
+)
+
+// websocketConn is a WebSocket connection. Read returns the payload of the data frames, as a
+// stream, and answers the pings of the server.
+type websocketConn struct {
+	conn       net.Conn
+	reader     *bufio.Reader
+	writeMutex sync.Mutex
+	remaining  uint64 // Bytes of the current data frame not read yet
+	mask       []byte // Mask of the current data frame, if any
+	maskOffset int
+}
+
+// dialWebsocket opens a WebSocket connection to the given path of the vCD of client, with the
+// credentials of the client and the given sub-protocol. The connection is made like the ones of
+// the HTTP transport of the client: with its dialer, through its proxy and with its TLS
+// configuration.
+func dialWebsocket(client *Client, path, protocol string) (*websocketConn, error) {
+	endpoint := client.VCDHREF
+	endpoint.Path = path
+	endpoint.RawQuery = ""
+
+	transport := innermostHttpTransport(&client.Http)
+	dial := (&net.Dialer{Timeout: 30 * time.Second}).DialContext
+	if transport != nil && transport.DialContext != nil {
+		dial = transport.DialContext
+	}
+	var proxyUrl *url.URL
+	if transport != nil && transport.Proxy != nil {
+		var err error
+		proxyUrl, err = transport.Proxy(&http.Request{Method: http.MethodGet, URL: &endpoint, Header: http.Header{}})
+		if err != nil {
+			return nil, fmt.Errorf("error finding the proxy: %s", err)
+		}
+	}
+
+	var conn net.Conn
+	var err error
+	if proxyUrl == nil {
+		conn, err = dial(client.Context(), "tcp", hostPort(&endpoint))
+	} else {
+		conn, err = dialThroughProxy(client.Context(), dial, proxyUrl, transport.ProxyConnectHeader, hostPort(&endpoint))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if endpoint.Scheme == "https" {
+		tlsConfig := &tls.Config{}
+		if transport != nil && transport.TLSClientConfig != nil {
+			tlsConfig = transport.TLSClientConfig.Clone()
+		}
+		if tlsConfig.ServerName == "" {
+			tlsConfig.ServerName = endpoint.Hostname()
+		}
+		tlsConfig.NextProtos = nil
+		tlsConn := tls.Client(conn, tlsConfig)
+		err = tlsConn.Handshake()
+		if err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+		conn = tlsConn
+	}
+
+	socket := &websocketConn{conn: conn, reader: bufio.NewReader(conn)}
+	err = socket.handshake(client, endpoint.String(), protocol)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return socket, nil
+}
+
+// dialThroughProxy opens a tunnel to address through the HTTP proxy with the given URL, with a
+// CONNECT request
+func dialThroughProxy(ctx context.Context, dial func(context.Context, string, string) (net.Conn, error),
+	proxyUrl *url.URL, header http.Header, address string) (net.Conn, error) {
+	if proxyUrl.Scheme != "http" {
+		return nil, fmt.Errorf("proxy scheme %s not supported for WebSocket connections", proxyUrl.Scheme)
+	}
+	conn, err := dial(ctx, "tcp", hostPort(proxyUrl))
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to the proxy: %s", err)
+	}
+	request := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: http.Header{},
+	}
+	for key, values := range header {
+		request.Header[key] = values
+	}
+	if proxyUrl.User != nil {
+		password, _ := proxyUrl.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(proxyUrl.User.Username() + ":" + password))
+		request.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	err = request.Write(conn)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("error sending the request to the proxy: %s", err)
+	}
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, request)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("error reading the response of the proxy: %s", err)
+	}
+	_ = response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		_ = conn.Close()
+		return nil, fmt.Errorf("connection refused by the proxy: %s", response.Status)
+	}
+	// Nothing is sent through the tunnel before the handshake, so no data of the tunnel can have
+	// been read with the response
+	if reader.Buffered() > 0 {
+		_ = conn.Close()
+		return nil, fmt.Errorf("unexpected data received from the proxy")
+	}
+	return conn, nil
+}
+
+// hostPort returns the host and port of the URL, with the default port of its scheme when it has
+// none
+func hostPort(address *url.URL) string {
+	if address.Port() != "" {
+		return address.Host
+	}
+	port := "80"
+	if address.Scheme == "https" {
+		port = "443"
+	}
+	return net.JoinHostPort(address.Hostname(), port)
+}
+
+// handshake upgrades the connection to the WebSocket protocol
+func (socket *websocketConn) handshake(client *Client, endpoint, protocol string) error {
+	request, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	key := make([]byte, 16)
+	_, _ = rand.Read(key)
+	encodedKey := base64.StdEncoding.EncodeToString(key)
+	request.Header.Set("Upgrade", "websocket")
+	request.Header.Set("Connection", "Upgrade")
+	request.Header.Set("Sec-WebSocket-Key", encodedKey)
+	request.Header.Set("Sec-WebSocket-Version", "13")
+	request.Header.Set("Sec-WebSocket-Protocol", protocol)
+	if client.VCDAuthHeader != "" && client.VCDToken != "" {
+		request.Header.Set(client.VCDAuthHeader, client.VCDToken)
+	}
+	if client.VCDAccessToken != "" {
+		request.Header.Set("Authorization", "Bearer "+client.VCDAccessToken)
+	}
+	client.setUserAgent(request)
+
+	err = request.Write(socket.conn)
+	if err != nil {
+		return err
+	}
+	response, err := http.ReadResponse(socket.reader, request)
+	if err != nil {
+		return err
+	}
+	_ = response.Body.Close()
+	if response.StatusCode != http.StatusSwitchingProtocols {
+		return fmt.Errorf("WebSocket handshake refused: %s", response.Status)
+	}
+	accept := sha1.Sum([]byte(encodedKey + websocketGuid))
+	if response.Header.Get("Sec-WebSocket-Accept") != base64.StdEncoding.EncodeToString(accept[:]) ||
+		!strings.EqualFold(response.Header.Get("Upgrade"), "websocket") {
+		return fmt.Errorf("invalid WebSocket handshake response")
+	}
+	return nil
+}
+
+// Read reads the payload of the data frames
+func (socket *websocketConn) Read(data []byte) (int, error) {
+	for socket.remaining == 0 {
+		err := socket.nextFrame()
+		if err != nil {
+			return 0, err
+		}
+	}
+	if uint64(len(data)) > socket.remaining {
+		data = data[:socket.remaining]
+	}
+	count, err := socket.reader.Read(data)
+	if socket.mask != nil {
+		for i := 0; i < count; i++ {
+			data[i] ^= socket.mask[(socket.maskOffset+i)%4]
+		}
+		socket.maskOffset += count
+	}
+	socket.remaining -= uint64(count)
+	return count, err
+}
+
+// nextFrame reads the header of the next data frame, handling the control frames before it.
+// It returns io.EOF when the server closes the connection.
+func (socket *websocketConn) nextFrame() error {
+	header := make([]byte, 2)
+	_, err := io.ReadFull(socket.reader, header)
+	if err != nil {
+		return err
+	}
+	opcode := header[0] & 0x0f
+	length := uint64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		extended := make([]byte, 2)
+		_, err = io.ReadFull(socket.reader, extended)
+		length = uint64(binary.BigEndian.Uint16(extended))
+	case 127:
+		extended := make([]byte, 8)
+		_, err = io.ReadFull(socket.reader, extended)
+		length = binary.BigEndian.Uint64(extended)
+	}
+	if err != nil {
+		return err
+	}
+	var mask []byte
+	if header[1]&0x80 != 0 {
+		mask = make([]byte, 4)
+		_, err = io.ReadFull(socket.reader, mask)
+		if err != nil {
+			return err
+		}
+	}
+
+	switch opcode {
+	case websocketContinuation, websocketText, websocketBinary:
+		socket.remaining = length
+		socket.mask = mask
+		socket.maskOffset = 0
+		return nil
+	case websocketPing, websocketPong, websocketClose:
+		if length > 125 {
+			return fmt.Errorf("invalid WebSocket control frame")
+		}
+		payload := make([]byte, length)
+		_, err = io.ReadFull(socket.reader, payload)
+		if err != nil {
+			return err
+		}
+		for i := range payload {
+			if mask != nil {
+				payload[i] ^= mask[i%4]
+			}
+		}
+		switch opcode {
+		case websocketPing:
+			return socket.writeFrame(websocketPong, payload)
+		case websocketClose:
+			return io.EOF
+		}
+		return nil
+	default:
+		return fmt.Errorf("unexpected WebSocket frame with opcode %d", opcode)
+	}
+}
+
+// WriteMessage sends data in a binary frame
+func (socket *websocketConn) WriteMessage(data []byte) error {
+	return socket.writeFrame(websocketBinary, data)
+}
+
+// writeFrame sends a single masked frame, as required from clients
+func (socket *websocketConn) writeFrame(opcode byte, payload []byte) error {
+	frame := []byte{0x80 | opcode}
+	length := len(payload)
+	switch {
+	case length < 126:
+		frame = append(frame, 0x80|byte(length))
+	case length <= 0xffff:
+		frame = append(frame, 0x80|126, byte(length>>8), byte(length))
+	default:
+		extended := make([]byte, 8)
+		binary.BigEndian.PutUint64(extended, uint64(length))
+		frame = append(append(frame, 0x80|127), extended...)
+	}
+	mask := make([]byte, 4)
+	_, err := rand.Read(mask)
+	if err != nil {
+		return err
+	}
+	frame = append(frame, mask...)
+	for i, value := range payload {
+		frame = append(frame, value^mask[i%4])
+	}
+
+	socket.writeMutex.Lock()
+	defer socket.writeMutex.Unlock()
+	_, err = socket.conn.Write(frame)
+	return err
+}
+
+// Close sends a close frame and closes the connection
+func (socket *websocketConn) Close() error {
+	_ = socket.writeFrame(websocketClose, nil)
+	return socket.conn.Close()
+}
@@ -0,0 +1,341 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// fakeMqttBroker is a minimal MQTT broker behind the WebSocket endpoint of the fake vCD. It
+// acknowledges the connection and the subscription, then publishes its messages, the first one
+// with QoS 1 and the others with QoS 0. Topics starting with "forbidden/" are refused.
+type fakeMqttBroker struct {
+	messages []string
+
+	mutex    sync.Mutex
+	received []string // Types of the packets received, with the client identifier or the topics
+	done     chan struct{}
+}
+
+// writeServerFrame writes an unmasked WebSocket frame, as servers do
+func writeServerFrame(writer *bufio.Writer, opcode byte, payload []byte) {
+	if len(payload) < 126 {
+		_, _ = writer.Write([]byte{0x80 | opcode, byte(len(payload))})
+	} else {
+		_, _ = writer.Write([]byte{0x80 | opcode, 126, byte(len(payload) >> 8), byte(len(payload))})
+	}
+	_, _ = writer.Write(payload)
+	_ = writer.Flush()
+}
+
+// mqttPacket encodes an MQTT packet whose body is shorter than 128 bytes or not
+func mqttPacket(header byte, body []byte) []byte {
+	packet := []byte{header}
+	length := len(body)
+	for {
+		digit := byte(length % 128)
+		length /= 128
+		if length > 0 {
+			digit |= 0x80
+		}
+		packet = append(packet, digit)
+		if length == 0 {
+			break
+		}
+	}
+	return append(packet, body...)
+}
+
+func (broker *fakeMqttBroker) record(packet string) {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+	broker.received = append(broker.received, packet)
+}
+
+func (broker *fakeMqttBroker) serve(w http.ResponseWriter, r *http.Request) {
+	defer close(broker.done)
+	if r.Header.Get("Upgrade") != "websocket" || r.Header.Get("Sec-WebSocket-Protocol") != "mqtt" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	conn, buffer, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	accept := sha1.Sum([]byte(r.Header.Get("Sec-WebSocket-Key") + websocketGuid))
+	_, _ = buffer.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Protocol: mqtt\r\nSec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(accept[:]) + "\r\n\r\n")
+	_ = buffer.Flush()
+
+	// The frames of the client are masked: they are read with the WebSocket client of the package
+	client := &websocketConn{conn: conn, reader: buffer.Reader}
+	reader := bufio.NewReader(client)
+	for {
+		packetType, _, body, err := readMqttPacket(reader)
+		if err != nil {
+			return
+		}
+		switch packetType {
+		case mqttConnect:
+			broker.record("CONNECT " + string(body[12:]))
+			writeServerFrame(buffer.Writer, websocketBinary, mqttPacket(mqttConnack<<4, []byte{0, 0}))
+		case mqttSubscribe:
+			ack := []byte{body[0], body[1]}
+			for offset := 2; offset < len(body); {
+				length := int(body[offset])<<8 | int(body[offset+1])
+				topic := string(body[offset+2 : offset+2+length])
+				broker.record("SUBSCRIBE " + topic)
+				if len(topic) > 10 && topic[:10] == "forbidden/" {
+					ack = append(ack, 0x80)
+				} else {
+					ack = append(ack, body[offset+2+length])
+				}
+				offset += 3 + length
+			}
+			writeServerFrame(buffer.Writer, websocketBinary, mqttPacket(mqttSuback<<4, ack))
+			if ack[len(ack)-1] == 0x80 {
+				continue
+			}
+			writeServerFrame(buffer.Writer, websocketPing, []byte("ping"))
+			for i, message := range broker.messages {
+				publish := mqttString("publish/" + vcdtest.MockOrgId + "/event")
+				header := byte(mqttPublish << 4)
+				if i == 0 {
+					header |= 0x02
+					publish = append(publish, 0, 7)
+				}
+				writeServerFrame(buffer.Writer, websocketBinary, mqttPacket(header, append(publish, message...)))
+			}
+		case mqttPuback:
+			broker.record("PUBACK " + string('0'+body[1]))
+		case mqttPingreq:
+			broker.record("PINGREQ")
+			writeServerFrame(buffer.Writer, websocketBinary, mqttPacket(mqttPingresp<<4, nil))
+		case mqttDisconnect:
+			broker.record("DISCONNECT")
+			return
+		}
+	}
+}
+
+// Checks the subscription to the MQTT message bus and the delivery of its events
+func TestVCDClient_SubscribeEvents(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	broker := &fakeMqttBroker{
+		messages: []string{vappCreateNotification, `{"type": "com/vmware/vcloud/event/vm/modify"}`, taskCompleteNotification},
+		done:     make(chan struct{}),
+	}
+	server.HandleFunc(http.MethodGet, mqttPath, broker.serve)
+
+	vcdClient := NewVCDClient(server.ApiURL(), true)
+	if _, err := vcdClient.NewMqttSource(nil); err == nil {
+		t.Errorf("expected error connecting to the message bus without authentication")
+	}
+	err := vcdClient.Authenticate("user", "password", vcdtest.MockOrgName)
+	if err != nil {
+		t.Fatalf("error authenticating: %s", err)
+	}
+	if _, err = vcdClient.NewMqttSource(nil); err == nil {
+		t.Errorf("expected error connecting to the message bus with API 27.0")
+	}
+	vcdClient.Client.APIVersion = "33.0"
+
+	topic := "publish/" + vcdtest.MockOrgId + "/#"
+	subscriber, err := vcdClient.SubscribeEvents(&MqttOptions{Topics: []string{topic}, ClientId: "client-1",
+		KeepAlive: 50 * time.Millisecond}, "com/vmware/vcloud/event/vapp/", "com/vmware/vcloud/event/task/")
+	if err != nil {
+		t.Fatalf("error subscribing to events: %s", err)
+	}
+	for _, expectedKind := range []string{EventKindEntityLifecycle, EventKindTaskCompletion} {
+		select {
+		case event := <-subscriber.Events:
+			if event == nil || event.Kind != expectedKind {
+				t.Errorf("expected event of kind %s, got %+v", expectedKind, event)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no event of kind %s received", expectedKind)
+		}
+	}
+	time.Sleep(120 * time.Millisecond)
+	err = subscriber.Close()
+	if err != nil {
+		t.Errorf("error closing subscriber: %s", err)
+	}
+	select {
+	case <-broker.done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the connection to the broker was not closed")
+	}
+
+	broker.mutex.Lock()
+	received := broker.received
+	broker.mutex.Unlock()
+	expected := map[string]bool{"CONNECT client-1": true, "SUBSCRIBE " + topic: true, "PUBACK 7": true, "PINGREQ": true, "DISCONNECT": true}
+	for _, packet := range received {
+		delete(expected, packet)
+	}
+	if len(expected) != 0 {
+		t.Errorf("missing packets %v in %v", expected, received)
+	}
+	requests := server.RequestsTo(http.MethodGet, mqttPath)
+	if len(requests) != 1 || requests[0].Header.Get("x-vcloud-authorization") != vcdtest.MockToken {
+		t.Errorf("unexpected connection requests: %#v", requests)
+	}
+
+	// A refused subscription
+	broker.done = make(chan struct{})
+	_, err = vcdClient.NewMqttSource(&MqttOptions{Topics: []string{"forbidden/#"}})
+	if err == nil {
+		t.Errorf("expected error subscribing to a forbidden topic")
+	}
+}
+
+// Checks that the connection to the message bus goes through the proxy of the client
+func TestVCDClient_NewMqttSourceWithProxy(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	broker := &fakeMqttBroker{messages: []string{vappCreateNotification}, done: make(chan struct{})}
+	server.HandleFunc(http.MethodGet, mqttPath, broker.serve)
+
+	var tunnels []string
+	var tunnelsMutex sync.Mutex
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		tunnelsMutex.Lock()
+		tunnels = append(tunnels, r.Host+" "+r.Header.Get("Proxy-Authorization"))
+		tunnelsMutex.Unlock()
+		target, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			_ = target.Close()
+			return
+		}
+		_, _ = conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+		go func() {
+			_, _ = io.Copy(target, conn)
+			_ = target.Close()
+		}()
+		_, _ = io.Copy(conn, target)
+		_ = conn.Close()
+	}))
+	defer proxy.Close()
+
+	vcdClient := NewVCDClient(server.ApiURL(), true)
+	err := vcdClient.Authenticate("user", "password", vcdtest.MockOrgName)
+	if err != nil {
+		t.Fatalf("error authenticating: %s", err)
+	}
+	vcdClient.Client.APIVersion = "33.0"
+	proxyUrl, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatalf("error parsing proxy URL: %s", err)
+	}
+	proxyUrl.User = url.UserPassword("proxy-user", "secret")
+	err = WithProxy(proxyUrl)(vcdClient)
+	if err != nil {
+		t.Fatalf("error setting the proxy: %s", err)
+	}
+
+	source, err := vcdClient.NewMqttSource(&MqttOptions{Topics: []string{"publish/" + vcdtest.MockOrgId + "/#"}})
+	if err != nil {
+		t.Fatalf("error connecting to the message bus: %s", err)
+	}
+	message, err := source.Receive()
+	if err != nil || string(message) != vappCreateNotification {
+		t.Errorf("unexpected message %q (error %v)", message, err)
+	}
+	err = source.Close()
+	if err != nil {
+		t.Errorf("error closing the message source: %s", err)
+	}
+
+	serverUrl, _ := url.Parse(server.URL())
+	expected := serverUrl.Host + " Basic " + base64.StdEncoding.EncodeToString([]byte("proxy-user:secret"))
+	tunnelsMutex.Lock()
+	defer tunnelsMutex.Unlock()
+	if len(tunnels) != 1 || tunnels[0] != expected {
+		t.Errorf("expected one tunnel %q, got %v", expected, tunnels)
+	}
+}
+
+// Checks that only messages with QoS 0 and 1 are accepted, the latter with their packet identifier
+func TestParseMqttPublish(t *testing.T) {
+	body := append(mqttString("topic"), 0, 7)
+	body = append(body, "payload"...)
+	tests := []struct {
+		flags    byte
+		payload  string
+		packetId []byte
+		wantErr  bool
+	}{
+		{flags: 0x00, payload: "\x00\x07payload"},
+		{flags: 0x02, payload: "payload", packetId: []byte{0, 7}},
+		{flags: 0x0b, payload: "payload", packetId: []byte{0, 7}},
+		{flags: 0x04, wantErr: true},
+		{flags: 0x06, wantErr: true},
+	}
+	for _, test := range tests {
+		payload, packetId, err := parseMqttPublish(test.flags, body)
+		if (err != nil) != test.wantErr {
+			t.Errorf("flags %#x: unexpected error %v", test.flags, err)
+			continue
+		}
+		if string(payload) != test.payload || string(packetId) != string(test.packetId) {
+			t.Errorf("flags %#x: unexpected payload %q and packet identifier %v", test.flags, payload, packetId)
+		}
+	}
+}
+
+// Checks that the WebSocket client reads frames of any length
+func TestWebsocketConn_Read(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+	payload := make([]byte, 300)
+	for i := range payload {
+		payload[i] = byte(i)
+	}
+	go func() {
+		writer := bufio.NewWriter(server)
+		writeServerFrame(writer, websocketBinary, payload[:100])
+		writeServerFrame(writer, websocketContinuation, payload[100:])
+		writeServerFrame(writer, websocketClose, nil)
+		_ = server.Close()
+	}()
+
+	socket := &websocketConn{conn: client, reader: bufio.NewReader(client)}
+	received := make([]byte, 0, len(payload))
+	buffer := make([]byte, 64)
+	for {
+		count, err := socket.Read(buffer)
+		received = append(received, buffer[:count]...)
+		if err != nil {
+			break
+		}
+	}
+	if string(received) != string(payload) {
+		t.Errorf("unexpected payload of %d bytes", len(received))
+	}
+}
@@ -0,0 +1,232 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/util"
+)
+
+// Prefix of the types of the vCD events, e.g. com/vmware/vcloud/event/vapp/create
+const eventTypePrefix = "com/vmware/vcloud/event/"
+
+// Kinds of events, see Event.Kind
+const (
+	EventKindTaskCompletion  = "taskCompletion"
+	EventKindEntityLifecycle = "entityLifecycle"
+	EventKindOther           = "other"
+)
+
+// eventLifecycleActions are the actions which create, change or remove an entity
+var eventLifecycleActions = map[string]bool{
+	"create": true, "modify": true, "delete": true, "import": true,
+	"deploy": true, "undeploy": true, "power_on": true, "power_off": true, "suspend": true,
+}
+
+// Event is a decoded vCD notification
+type Event struct {
+	Kind             string // One of the EventKind* constants
+	Type             string // Full type, e.g. com/vmware/vcloud/event/vapp/create
+	EntityType       string // e.g. vapp, vm, task
+	Action           string // e.g. create, modify, delete, complete
+	EventId          string
+	Timestamp        time.Time
+	OperationSuccess bool
+	Entity           *types.NotificationEntityLink // The entity of the event
+	Task             *types.NotificationEntityLink // The task of the event, if any
+	Org              *types.NotificationEntityLink
+	User             *types.NotificationEntityLink
+	Notification     *types.Notification
+}
+
+// DecodeEvent decodes a notification message, either XML (AMQP) or JSON (MQTT).
+// JSON messages wrapped in an envelope with a "payload" string are unwrapped.
+func DecodeEvent(message []byte) (*Event, error) {
+	message = bytes.TrimSpace(message)
+	notification := &types.Notification{}
+	var err error
+	if bytes.HasPrefix(message, []byte("{")) {
+		envelope := struct {
+			Payload string `json:"payload"`
+		}{}
+		err = json.Unmarshal(message, &envelope)
+		if err == nil && envelope.Payload != "" {
+			return DecodeEvent([]byte(envelope.Payload))
+		}
+		err = json.Unmarshal(message, notification)
+	} else {
+		err = xml.Unmarshal(message, notification)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error decoding notification: %s", err)
+	}
+	if notification.Type == "" {
+		return nil, fmt.Errorf("error decoding notification: missing event type")
+	}
+	return newEvent(notification), nil
+}
+
+// newEvent classifies a notification
+func newEvent(notification *types.Notification) *Event {
+	event := &Event{
+		Kind:             EventKindOther,
+		Type:             notification.Type,
+		EventId:          notification.EventId,
+		OperationSuccess: notification.OperationSuccess,
+		Notification:     notification,
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, notification.Timestamp)
+	if err == nil {
+		event.Timestamp = timestamp
+	}
+
+	for _, link := range notification.EntityLink {
+		switch {
+		case link.Rel == "entity":
+			event.Entity = link
+		case link.Rel == "task":
+			event.Task = link
+		case link.Rel == "up" && link.Type == "vcloud:org":
+			event.Org = link
+		case link.Rel == "down" && link.Type == "vcloud:user":
+			event.User = link
+		}
+	}
+
+	elements := strings.Split(strings.TrimPrefix(notification.Type, eventTypePrefix), "/")
+	if len(elements) >= 2 {
+		event.EntityType = elements[len(elements)-2]
+		event.Action = elements[len(elements)-1]
+	}
+	switch {
+	case event.EntityType == "task" && event.Action == "complete":
+		event.Kind = EventKindTaskCompletion
+	case eventLifecycleActions[event.Action]:
+		event.Kind = EventKindEntityLifecycle
+	}
+	return event
+}
+
+// MessageSource is a connection to the vCD message bus, e.g. an AMQP queue bound to the
+// notifications exchange, or an MQTT subscription to the publish/# topics. The client of the
+// message bus is left to the application, which wraps it in a MessageSource.
+type MessageSource interface {
+	// Receive blocks until a message is available. It returns io.EOF once the source is closed.
+	Receive() ([]byte, error)
+	// Close closes the source, and unblocks Receive
+	Close() error
+}
+
+// EventSubscriber reads the messages of a MessageSource and delivers the decoded events on
+// the Events channel. Messages which cannot be decoded are reported on the Errors channel
+// and skipped. Both channels are closed when the subscriber is closed, or when its source is
+// closed or fails.
+type EventSubscriber struct {
+	Events <-chan *Event
+	Errors <-chan error
+
+	source     MessageSource
+	eventTypes []string
+	done       chan struct{}
+	closeOnce  sync.Once
+}
+
+// NewEventSubscriber starts delivering the events received from source. When eventTypes are
+// given, only the events whose type starts with one of them are delivered, e.g.
+// "com/vmware/vcloud/event/vapp/" or "com/vmware/vcloud/event/task/complete".
+func NewEventSubscriber(source MessageSource, eventTypes ...string) *EventSubscriber {
+	events := make(chan *Event)
+	errors := make(chan error, 16)
+	subscriber := &EventSubscriber{
+		Events:     events,
+		Errors:     errors,
+		source:     source,
+		eventTypes: eventTypes,
+		done:       make(chan struct{}),
+	}
+	go subscriber.run(events, errors)
+	return subscriber
+}
+
+// Close stops the delivery of the events and closes the source of the subscriber. The events
+// which are not read yet are dropped.
+func (subscriber *EventSubscriber) Close() error {
+	var err error
+	subscriber.closeOnce.Do(func() {
+		close(subscriber.done)
+		err = subscriber.source.Close()
+	})
+	return err
+}
+
+// run reads the source until it is closed
+func (subscriber *EventSubscriber) run(events chan<- *Event, errors chan<- error) {
+	defer close(events)
+	defer close(errors)
+	for {
+		message, err := subscriber.source.Receive()
+		if err == io.EOF || subscriber.closed() {
+			return
+		}
+		if err != nil {
+			subscriber.report(errors, fmt.Errorf("error receiving notification: %s", err))
+			return
+		}
+		event, err := DecodeEvent(message)
+		if err != nil {
+			subscriber.report(errors, err)
+			continue
+		}
+		if !subscriber.wants(event) {
+			continue
+		}
+		select {
+		case events <- event:
+		case <-subscriber.done:
+			return
+		}
+	}
+}
+
+// closed tells whether Close was called
+func (subscriber *EventSubscriber) closed() bool {
+	select {
+	case <-subscriber.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// wants tells whether the event matches the types of the subscriber
+func (subscriber *EventSubscriber) wants(event *Event) bool {
+	if len(subscriber.eventTypes) == 0 {
+		return true
+	}
+	for _, eventType := range subscriber.eventTypes {
+		if strings.HasPrefix(event.Type, eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// report sends an error without blocking: errors are dropped when nobody reads them
+func (subscriber *EventSubscriber) report(errors chan<- error, err error) {
+	select {
+	case errors <- err:
+	default:
+		util.Logger.Printf("[TRACE] EventSubscriber - dropped error: %s", err)
+	}
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+const vappCreateNotification = `<?xml version="1.0" encoding="UTF-8"?>
+<vmext:Notification xmlns:vmext="http://www.vmware.com/vcloud/extension/v1.5" xmlns:vcloud="http://www.vmware.com/vcloud/v1.5" type="com/vmware/vcloud/event/vapp/create" eventId="event-1">
+  <vmext:Link rel="entityResolver" href="https://vcd/api/entity/"/>
+  <vmext:EntityLink rel="entity" type="vcloud:vapp" name="app1" id="urn:vcloud:vapp:55555555-5555-5555-5555-555555555555"/>
+  <vmext:EntityLink rel="up" type="vcloud:vdc" name="vdc1" id="urn:vcloud:vdc:22222222-2222-2222-2222-222222222222"/>
+  <vmext:EntityLink rel="up" type="vcloud:org" name="org1" id="urn:vcloud:org:11111111-1111-1111-1111-111111111111"/>
+  <vmext:EntityLink rel="down" type="vcloud:user" name="admin" id="urn:vcloud:user:33333333-3333-3333-3333-333333333333"/>
+  <vmext:EntityLink rel="task" type="vcloud:task" name="vdcComposeVapp" id="urn:vcloud:task:44444444-4444-4444-4444-444444444444"/>
+  <vmext:Timestamp>2019-08-01T10:00:00.000+02:00</vmext:Timestamp>
+  <vmext:OperationSuccess>true</vmext:OperationSuccess>
+</vmext:Notification>`
+
+const taskCompleteNotification = `{"payload": "{\"type\": \"com/vmware/vcloud/event/task/complete\", \"eventId\": \"event-2\",` +
+	` \"timestamp\": \"2019-08-01T10:01:00Z\", \"operationSuccess\": false,` +
+	` \"entityLinks\": [{\"rel\": \"entity\", \"type\": \"vcloud:task\", \"name\": \"vappDeploy\", \"id\": \"urn:vcloud:task:1\"}]}"}`
+
+// fakeMessageSource returns its messages, then io.EOF
+type fakeMessageSource struct {
+	messages chan []byte
+}
+
+func (source *fakeMessageSource) Receive() ([]byte, error) {
+	message, ok := <-source.messages
+	if !ok {
+		return nil, io.EOF
+	}
+	return message, nil
+}
+
+func (source *fakeMessageSource) Close() error {
+	close(source.messages)
+	return nil
+}
+
+// Checks the decoding of XML and JSON notifications
+func TestDecodeEvent(t *testing.T) {
+	event, err := DecodeEvent([]byte(vappCreateNotification))
+	if err != nil {
+		t.Fatalf("error decoding XML notification: %s", err)
+	}
+	if event.Kind != EventKindEntityLifecycle || event.EntityType != "vapp" || event.Action != "create" ||
+		!event.OperationSuccess || event.EventId != "event-1" || event.Timestamp.IsZero() {
+		t.Errorf("unexpected event: %+v", event)
+	}
+	if event.Entity == nil || event.Entity.Name != "app1" || event.Org == nil || event.Org.Name != "org1" ||
+		event.User == nil || event.User.Name != "admin" || event.Task == nil {
+		t.Errorf("unexpected entities: %+v", event)
+	}
+
+	event, err = DecodeEvent([]byte(taskCompleteNotification))
+	if err != nil {
+		t.Fatalf("error decoding JSON notification: %s", err)
+	}
+	if event.Kind != EventKindTaskCompletion || event.OperationSuccess || event.Entity == nil || event.Entity.Name != "vappDeploy" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+
+	for _, invalid := range []string{"", "<Notification/>", "{not json"} {
+		_, err = DecodeEvent([]byte(invalid))
+		if err == nil {
+			t.Errorf("expected error decoding '%s'", invalid)
+		}
+	}
+}
+
+// Checks that the subscriber filters the events, reports the invalid messages and stops with its source
+func TestEventSubscriber(t *testing.T) {
+	source := &fakeMessageSource{messages: make(chan []byte, 3)}
+	source.messages <- []byte(vappCreateNotification)
+	source.messages <- []byte("invalid")
+	source.messages <- []byte(taskCompleteNotification)
+	subscriber := NewEventSubscriber(source, "com/vmware/vcloud/event/task/")
+
+	event := <-subscriber.Events
+	if event == nil || event.Kind != EventKindTaskCompletion {
+		t.Fatalf("expected the task completion event, got %+v", event)
+	}
+	err := subscriber.Close()
+	if err != nil {
+		t.Fatalf("error closing subscriber: %s", err)
+	}
+	if _, ok := <-subscriber.Events; ok {
+		t.Errorf("expected events channel to be closed")
+	}
+	errors := 0
+	for range subscriber.Errors {
+		errors++
+	}
+	if errors != 1 {
+		t.Errorf("expected 1 decoding error, got %d", errors)
+	}
+}
+
+// Checks that closing a subscriber whose events are not read stops it
+func TestEventSubscriber_CloseWithoutReading(t *testing.T) {
+	source := &fakeMessageSource{messages: make(chan []byte, 2)}
+	source.messages <- []byte(vappCreateNotification)
+	source.messages <- []byte(vappCreateNotification)
+	subscriber := NewEventSubscriber(source)
+
+	err := subscriber.Close()
+	if err != nil {
+		t.Fatalf("error closing subscriber: %s", err)
+	}
+	select {
+	case _, ok := <-subscriber.Errors:
+		if ok {
+			t.Errorf("unexpected error from closed subscriber")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the subscriber did not stop after Close")
+	}
+	for range subscriber.Events {
+	}
+}
@@ -0,0 +1,412 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// NsxtEdgeGateway is an edge gateway backed by NSX-T. Unlike the NSX-V edge gateways (EdgeGateway),
+// NSX-T edge gateways are managed through OpenAPI and need API 34.0+ (vCD 10.1+).
+type NsxtEdgeGateway struct {
+	NsxtEdgeGateway *types.NsxtEdgeGateway
+	client          *Client
+}
+
+// vdcGroupUrnPrefix is the prefix of the IDs of VDC groups, which can own NSX-T edge gateways
+const vdcGroupUrnPrefix = "urn:vcloud:vdcGroup:"
+
+// GetAllNsxtEdgeGateways retrieves all the NSX-T edge gateways of the org. Query parameters can be
+// supplied to perform additional filtering (e.g. "filter" => "ownerRef.id==<VDC ID>")
+func (adminOrg *AdminOrg) GetAllNsxtEdgeGateways(queryParameters url.Values) ([]*NsxtEdgeGateway, error) {
+	client, err := adminOrg.tenantClient()
+	if err != nil {
+		return nil, err
+	}
+	return getAllNsxtEdgeGateways(client, queryParameters)
+}
+
+// GetNsxtEdgeGatewayByName retrieves the NSX-T edge gateway of the org with the given name
+func (adminOrg *AdminOrg) GetNsxtEdgeGatewayByName(name string) (*NsxtEdgeGateway, error) {
+	queryParams := url.Values{}
+	queryParams.Set("filter", fiqlEq("name", name))
+	edges, err := adminOrg.GetAllNsxtEdgeGateways(queryParams)
+	if err != nil {
+		return nil, err
+	}
+	return oneNsxtEdgeGateway(edges, name)
+}
+
+// GetNsxtEdgeGatewayById retrieves the NSX-T edge gateway of the org with the given ID
+func (adminOrg *AdminOrg) GetNsxtEdgeGatewayById(id string) (*NsxtEdgeGateway, error) {
+	client, err := adminOrg.tenantClient()
+	if err != nil {
+		return nil, err
+	}
+	return getNsxtEdgeGatewayById(client, id)
+}
+
+// GetAllNsxtEdgeGateways retrieves the NSX-T edge gateways owned by the VDC. Query parameters can
+// be supplied to perform additional filtering.
+func (vdc *Vdc) GetAllNsxtEdgeGateways(queryParameters url.Values) ([]*NsxtEdgeGateway, error) {
+	vdcUrn, err := entityUrn(vdc.Vdc.ID, vdc.Vdc.HREF)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving the ID of vdc %s: %s", vdc.Vdc.Name, err)
+	}
+	ownerField := "orgVdc.id"
+	if vdc.client.APIClientVersionIs(">= 35.0") {
+		ownerField = "ownerRef.id"
+	}
+	return getAllNsxtEdgeGateways(vdc.client, queryParameterFilterAnd(fiqlEq(ownerField, vdcUrn), queryParameters))
+}
+
+// GetNsxtEdgeGatewayByName retrieves the NSX-T edge gateway of the VDC with the given name
+func (vdc *Vdc) GetNsxtEdgeGatewayByName(name string) (*NsxtEdgeGateway, error) {
+	queryParams := url.Values{}
+	queryParams.Set("filter", fiqlEq("name", name))
+	edges, err := vdc.GetAllNsxtEdgeGateways(queryParams)
+	if err != nil {
+		return nil, err
+	}
+	return oneNsxtEdgeGateway(edges, name)
+}
+
+// CreateNsxtEdgeGateway creates an NSX-T edge gateway in the org. The owner is an org VDC or, with
+// API 35.0+, a VDC group (OwnerRef), and each uplink connects the edge gateway to an NSX-T backed
+// external network, allocating IP addresses from its subnets. Only system administrators can
+// create edge gateways.
+func (adminOrg *AdminOrg) CreateNsxtEdgeGateway(edgeGatewayConfig *types.NsxtEdgeGateway) (*NsxtEdgeGateway, error) {
+	err := validateNsxtEdgeGateway(edgeGatewayConfig)
+	if err != nil {
+		return nil, err
+	}
+	client, err := adminOrg.tenantClient()
+	if err != nil {
+		return nil, err
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointEdgeGateways
+	apiVersion, err := client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := nsxtEdgeGatewayPayload(client, edgeGatewayConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	edge := &NsxtEdgeGateway{NsxtEdgeGateway: &types.NsxtEdgeGateway{}, client: client}
+	err = client.OpenApiPostItem(apiVersion, urlRef, nil, payload, edge.NsxtEdgeGateway)
+	if err != nil {
+		return nil, fmt.Errorf("error creating NSX-T edge gateway: %s", err)
+	}
+	return edge, nil
+}
+
+// Refresh retrieves the current definition of the NSX-T edge gateway
+func (egw *NsxtEdgeGateway) Refresh() error {
+	refreshed, err := getNsxtEdgeGatewayById(egw.client, egw.NsxtEdgeGateway.ID)
+	if err != nil {
+		return err
+	}
+	egw.NsxtEdgeGateway = refreshed.NsxtEdgeGateway
+	return nil
+}
+
+// Update sends the current definition of the NSX-T edge gateway (name, description, uplinks and
+// their IP allocations, owner) to vCD
+func (egw *NsxtEdgeGateway) Update() error {
+	if egw.NsxtEdgeGateway.ID == "" {
+		return fmt.Errorf("cannot update NSX-T edge gateway without ID")
+	}
+	err := validateNsxtEdgeGateway(egw.NsxtEdgeGateway)
+	if err != nil {
+		return err
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointEdgeGateways
+	apiVersion, err := egw.client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return err
+	}
+	urlRef, err := egw.client.OpenApiBuildEndpoint(endpoint, egw.NsxtEdgeGateway.ID)
+	if err != nil {
+		return err
+	}
+	payload, err := nsxtEdgeGatewayPayload(egw.client, egw.NsxtEdgeGateway)
+	if err != nil {
+		return err
+	}
+
+	updated := &types.NsxtEdgeGateway{}
+	err = egw.client.OpenApiPutItem(apiVersion, urlRef, nil, payload, updated)
+	if err != nil {
+		return fmt.Errorf("error updating NSX-T edge gateway: %s", err)
+	}
+	egw.NsxtEdgeGateway = updated
+	return nil
+}
+
+// Delete removes the NSX-T edge gateway. It fails if org VDC networks are still connected to it.
+func (egw *NsxtEdgeGateway) Delete() error {
+	if egw.NsxtEdgeGateway.ID == "" {
+		return fmt.Errorf("cannot delete NSX-T edge gateway without ID")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointEdgeGateways
+	apiVersion, err := egw.client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return err
+	}
+	urlRef, err := egw.client.OpenApiBuildEndpoint(endpoint, egw.NsxtEdgeGateway.ID)
+	if err != nil {
+		return err
+	}
+	err = egw.client.OpenApiDeleteItem(apiVersion, urlRef, nil)
+	if err != nil {
+		return fmt.Errorf("error deleting NSX-T edge gateway: %s", err)
+	}
+	return nil
+}
+
+// GetDhcpForwarder retrieves the DHCP forwarder of the NSX-T edge gateway, which relays the DHCP
+// requests of its org VDC networks in NsxtDhcpModeRelay mode. Needs API 36.1+ (vCD 10.3.1+).
+func (egw *NsxtEdgeGateway) GetDhcpForwarder() (*types.NsxtEdgeGatewayDhcpForwarder, error) {
+	urlRef, apiVersion, err := egw.buildEndpoint(types.OpenApiEndpointEdgeGatewayDhcpForwarder)
+	if err != nil {
+		return nil, err
+	}
+	forwarder := &types.NsxtEdgeGatewayDhcpForwarder{}
+	err = egw.client.OpenApiGetItem(apiVersion, urlRef, nil, forwarder)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving DHCP forwarder of NSX-T edge gateway: %s", err)
+	}
+	return forwarder, nil
+}
+
+// UpdateDhcpForwarder sets the DHCP forwarder of the NSX-T edge gateway. An enabled forwarder needs
+// the IP addresses of the DHCP servers. When forwarderConfig has no version, the version of the
+// current configuration is used.
+func (egw *NsxtEdgeGateway) UpdateDhcpForwarder(forwarderConfig *types.NsxtEdgeGatewayDhcpForwarder) (*types.NsxtEdgeGatewayDhcpForwarder, error) {
+	if forwarderConfig == nil {
+		return nil, fmt.Errorf("no DHCP forwarder configuration given")
+	}
+	if forwarderConfig.Enabled && len(forwarderConfig.DhcpServers) == 0 {
+		return nil, fmt.Errorf("an enabled DHCP forwarder needs at least one DHCP server")
+	}
+	for _, server := range forwarderConfig.DhcpServers {
+		if net.ParseIP(server) == nil {
+			return nil, fmt.Errorf("invalid DHCP server IP address '%s'", server)
+		}
+	}
+	payload := *forwarderConfig
+	if payload.Version == nil {
+		current, err := egw.GetDhcpForwarder()
+		if err != nil {
+			return nil, err
+		}
+		payload.Version = current.Version
+	}
+
+	urlRef, apiVersion, err := egw.buildEndpoint(types.OpenApiEndpointEdgeGatewayDhcpForwarder)
+	if err != nil {
+		return nil, err
+	}
+	updated := &types.NsxtEdgeGatewayDhcpForwarder{}
+	err = egw.client.OpenApiPutItem(apiVersion, urlRef, nil, &payload, updated)
+	if err != nil {
+		return nil, fmt.Errorf("error updating DHCP forwarder of NSX-T edge gateway: %s", err)
+	}
+	return updated, nil
+}
+
+// GetSlaacProfile retrieves the SLAAC profile of the NSX-T edge gateway, which sets how the hosts
+// of its IPv6 org VDC networks get their addresses. Needs API 35.0+ (vCD 10.2+).
+func (egw *NsxtEdgeGateway) GetSlaacProfile() (*types.NsxtEdgeGatewaySlaacProfile, error) {
+	urlRef, apiVersion, err := egw.buildEndpoint(types.OpenApiEndpointEdgeGatewaySlaacProfile)
+	if err != nil {
+		return nil, err
+	}
+	profile := &types.NsxtEdgeGatewaySlaacProfile{}
+	err = egw.client.OpenApiGetItem(apiVersion, urlRef, nil, profile)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving SLAAC profile of NSX-T edge gateway: %s", err)
+	}
+	return profile, nil
+}
+
+// UpdateSlaacProfile sets the SLAAC profile of the NSX-T edge gateway. The DNS servers advertised in
+// NsxtSlaacModeSlaac mode must be IPv6 addresses. The org VDC networks of the edge gateway can only
+// run a DHCPv6 service when the profile is enabled in NsxtSlaacModeDhcpv6 mode.
+func (egw *NsxtEdgeGateway) UpdateSlaacProfile(profileConfig *types.NsxtEdgeGatewaySlaacProfile) (*types.NsxtEdgeGatewaySlaacProfile, error) {
+	if profileConfig == nil {
+		return nil, fmt.Errorf("no SLAAC profile configuration given")
+	}
+	switch profileConfig.Mode {
+	case types.NsxtSlaacModeSlaac, types.NsxtSlaacModeDhcpv6, types.NsxtSlaacModeDisabled:
+	default:
+		return nil, fmt.Errorf("invalid SLAAC profile mode '%s'", profileConfig.Mode)
+	}
+	if profileConfig.DNSConfig != nil {
+		for _, server := range profileConfig.DNSConfig.DNSServerIpv6Addresses {
+			ip := net.ParseIP(server)
+			if ip == nil || ip.To4() != nil {
+				return nil, fmt.Errorf("invalid IPv6 address of DNS server '%s'", server)
+			}
+		}
+	}
+
+	urlRef, apiVersion, err := egw.buildEndpoint(types.OpenApiEndpointEdgeGatewaySlaacProfile)
+	if err != nil {
+		return nil, err
+	}
+	updated := &types.NsxtEdgeGatewaySlaacProfile{}
+	err = egw.client.OpenApiPutItem(apiVersion, urlRef, nil, profileConfig, updated)
+	if err != nil {
+		return nil, fmt.Errorf("error updating SLAAC profile of NSX-T edge gateway: %s", err)
+	}
+	return updated, nil
+}
+
+// buildEndpoint returns the URL of an endpoint of the edge gateway, formatted with its ID and
+// followed by suffix, with the API version to use
+func (egw *NsxtEdgeGateway) buildEndpoint(endpointFormat string, suffix ...string) (*url.URL, string, error) {
+	if egw.NsxtEdgeGateway.ID == "" {
+		return nil, "", fmt.Errorf("NSX-T edge gateway %s has no ID", egw.NsxtEdgeGateway.Name)
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + endpointFormat
+	apiVersion, err := egw.client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, "", err
+	}
+	urlRef, err := egw.client.OpenApiBuildEndpoint(append([]string{fmt.Sprintf(endpoint, egw.NsxtEdgeGateway.ID)}, suffix...)...)
+	if err != nil {
+		return nil, "", err
+	}
+	return urlRef, apiVersion, nil
+}
+
+// getAllNsxtEdgeGateways retrieves the NSX-T edge gateways visible to client
+func getAllNsxtEdgeGateways(client *Client, queryParameters url.Values) ([]*NsxtEdgeGateway, error) {
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointEdgeGateways
+	apiVersion, err := client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var typeResponses []*types.NsxtEdgeGateway
+	err = client.OpenApiGetAllItems(apiVersion, urlRef, queryParameters, &typeResponses)
+	if err != nil {
+		return nil, err
+	}
+
+	edges := make([]*NsxtEdgeGateway, len(typeResponses))
+	for index, typeResponse := range typeResponses {
+		edges[index] = &NsxtEdgeGateway{NsxtEdgeGateway: typeResponse, client: client}
+	}
+	return edges, nil
+}
+
+// getNsxtEdgeGatewayById retrieves the NSX-T edge gateway with the given ID
+func getNsxtEdgeGatewayById(client *Client, id string) (*NsxtEdgeGateway, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty NSX-T edge gateway ID")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointEdgeGateways
+	apiVersion, err := client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint, id)
+	if err != nil {
+		return nil, err
+	}
+
+	edge := &NsxtEdgeGateway{NsxtEdgeGateway: &types.NsxtEdgeGateway{}, client: client}
+	err = client.OpenApiGetItem(apiVersion, urlRef, nil, edge.NsxtEdgeGateway)
+	if err != nil {
+		return nil, err
+	}
+	return edge, nil
+}
+
+// oneNsxtEdgeGateway returns the only edge gateway of a lookup by name
+func oneNsxtEdgeGateway(edges []*NsxtEdgeGateway, name string) (*NsxtEdgeGateway, error) {
+	if len(edges) == 0 {
+		return nil, fmt.Errorf("NSX-T edge gateway '%s' not found", name)
+	}
+	if len(edges) > 1 {
+		return nil, fmt.Errorf("more than one NSX-T edge gateway found with name '%s'", name)
+	}
+	return edges[0], nil
+}
+
+// validateNsxtEdgeGateway checks the fields needed to create or update an NSX-T edge gateway
+func validateNsxtEdgeGateway(edgeGatewayConfig *types.NsxtEdgeGateway) error {
+	if edgeGatewayConfig == nil || edgeGatewayConfig.Name == "" {
+		return fmt.Errorf("NSX-T edge gateway name is required")
+	}
+	if (edgeGatewayConfig.OwnerRef == nil || edgeGatewayConfig.OwnerRef.ID == "") &&
+		(edgeGatewayConfig.OrgVdc == nil || edgeGatewayConfig.OrgVdc.ID == "") {
+		return fmt.Errorf("NSX-T edge gateway %s needs an owner (org VDC or VDC group)", edgeGatewayConfig.Name)
+	}
+	if len(edgeGatewayConfig.EdgeGatewayUplinks) == 0 {
+		return fmt.Errorf("NSX-T edge gateway %s needs an uplink to an external network", edgeGatewayConfig.Name)
+	}
+	for _, uplink := range edgeGatewayConfig.EdgeGatewayUplinks {
+		if uplink.UplinkID == "" {
+			return fmt.Errorf("uplink of NSX-T edge gateway %s has no external network ID", edgeGatewayConfig.Name)
+		}
+		for _, subnet := range uplink.Subnets.Values {
+			if subnet.Gateway == "" || subnet.PrefixLength == 0 {
+				return fmt.Errorf("subnets of uplink %s need gateway and prefix length", uplink.UplinkID)
+			}
+		}
+	}
+	return nil
+}
+
+// nsxtEdgeGatewayPayload returns a copy of the edge gateway definition where the owner is set in
+// the field known by the API version of client
+func nsxtEdgeGatewayPayload(client *Client, edgeGatewayConfig *types.NsxtEdgeGateway) (*types.NsxtEdgeGateway, error) {
+	payload := *edgeGatewayConfig
+	err := adjustOpenApiOwner(client, &payload.OwnerRef, &payload.OrgVdc)
+	if err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
+
+// adjustOpenApiOwner moves the owner of an OpenAPI entity to the field known by the API version of
+// client: orgVdc before 35.0, ownerRef since then. VDC groups can only own entities with API 35.0+.
+func adjustOpenApiOwner(client *Client, ownerRef, orgVdc **types.OpenApiReference) error {
+	if client.APIClientVersionIs(">= 35.0") {
+		if *ownerRef == nil {
+			*ownerRef = *orgVdc
+		}
+		*orgVdc = nil
+		return nil
+	}
+
+	if *ownerRef != nil {
+		if strings.HasPrefix((*ownerRef).ID, vdcGroupUrnPrefix) {
+			return fmt.Errorf("VDC groups can own NSX-T entities with API version 35.0 or newer, the client uses %s",
+				client.APIVersion)
+		}
+		*orgVdc = *ownerRef
+	}
+	*ownerRef = nil
+	return nil
+}
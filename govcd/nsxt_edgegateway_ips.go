@@ -0,0 +1,290 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// IP addresses of the NSX-T edge gateways
+//
+// The IP addresses allocated to an edge gateway are the ranges of the subnets of its uplinks, taken
+// from the IP pools of the external networks. The services of the edge gateway (NAT, VPN, load
+// balancer) use some of them. The functions below claim and release allocated addresses, so that
+// automation can pick the addresses it uses deterministically. The functions taking an uplink ID
+// consider all the uplinks when it is empty.
+
+// GetUsedIpAddresses retrieves the IP addresses of the edge gateway used by its services. Query
+// parameters can be supplied to perform additional filtering (e.g. "filter" => "category==SNAT")
+func (egw *NsxtEdgeGateway) GetUsedIpAddresses(queryParameters url.Values) ([]*types.NsxtEdgeGatewayUsedIpAddress, error) {
+	urlRef, apiVersion, err := egw.buildEndpoint(types.OpenApiEndpointEdgeGatewayUsedIps)
+	if err != nil {
+		return nil, err
+	}
+	var usedIps []*types.NsxtEdgeGatewayUsedIpAddress
+	err = egw.client.OpenApiGetAllItems(apiVersion, urlRef, queryParameters, &usedIps)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving used IP addresses of NSX-T edge gateway: %s", err)
+	}
+	return usedIps, nil
+}
+
+// GetAllocatedIpAddresses refreshes the edge gateway and returns the IP addresses allocated to the
+// given uplink
+func (egw *NsxtEdgeGateway) GetAllocatedIpAddresses(uplinkId string) ([]string, error) {
+	err := egw.Refresh()
+	if err != nil {
+		return nil, err
+	}
+	return egw.allocatedIpAddresses(uplinkId)
+}
+
+// GetUnusedIpAddresses refreshes the edge gateway and returns the IP addresses allocated to the
+// given uplink which are neither used by a service nor the primary IP of a subnet
+func (egw *NsxtEdgeGateway) GetUnusedIpAddresses(uplinkId string) ([]string, error) {
+	allocated, err := egw.GetAllocatedIpAddresses(uplinkId)
+	if err != nil {
+		return nil, err
+	}
+	reserved, err := egw.reservedIpAddresses()
+	if err != nil {
+		return nil, err
+	}
+	var unused []string
+	for _, address := range allocated {
+		if !reserved[address] {
+			unused = append(unused, address)
+		}
+	}
+	return unused, nil
+}
+
+// AllocateIpAddresses allocates count more IP addresses to the uplink from the IP pool of its
+// external network, and returns the newly allocated ones
+func (egw *NsxtEdgeGateway) AllocateIpAddresses(uplinkId string, count int) ([]string, error) {
+	if uplinkId == "" || count <= 0 {
+		return nil, fmt.Errorf("an uplink ID and a positive number of IP addresses are required")
+	}
+	before, err := egw.GetAllocatedIpAddresses(uplinkId)
+	if err != nil {
+		return nil, err
+	}
+	err = egw.modifyUplink(uplinkId, func(uplink *types.NsxtEdgeGatewayUplink) error {
+		uplink.QuickAddAllocatedIPCount = count
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error allocating %d IP addresses to NSX-T edge gateway: %s", count, err)
+	}
+	after, err := egw.allocatedIpAddresses(uplinkId)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := make(map[string]bool, len(before))
+	for _, address := range before {
+		previous[address] = true
+	}
+	var allocated []string
+	for _, address := range after {
+		if !previous[address] {
+			allocated = append(allocated, address)
+		}
+	}
+	return allocated, nil
+}
+
+// AllocateIpRange allocates the given range of IP addresses to the uplink, in the subnet containing
+// them. The addresses must be free in the IP pool of the external network.
+func (egw *NsxtEdgeGateway) AllocateIpRange(uplinkId string, ipRange types.OpenApiIPRange) error {
+	start, end, err := parseIpRange(ipRange)
+	if err != nil {
+		return err
+	}
+	err = egw.Refresh()
+	if err != nil {
+		return err
+	}
+	err = egw.modifyUplink(uplinkId, func(uplink *types.NsxtEdgeGatewayUplink) error {
+		for index := range uplink.Subnets.Values {
+			subnet := &uplink.Subnets.Values[index]
+			_, network, err := net.ParseCIDR(fmt.Sprintf("%s/%d", subnet.Gateway, subnet.PrefixLength))
+			if err != nil || !network.Contains(start) || !network.Contains(end) {
+				continue
+			}
+			if subnet.IPRanges == nil {
+				subnet.IPRanges = &types.OpenApiIPRanges{}
+			}
+			if ipRangesContain(subnet.IPRanges.Values, ipRange.StartAddress) || ipRangesContain(subnet.IPRanges.Values, ipRange.EndAddress) {
+				return fmt.Errorf("IP range %s-%s is already allocated", ipRange.StartAddress, ipRange.EndAddress)
+			}
+			subnet.IPRanges.Values = append(subnet.IPRanges.Values, types.OpenApiIPRange{
+				StartAddress: start.String(),
+				EndAddress:   end.String(),
+			})
+			return nil
+		}
+		return fmt.Errorf("no subnet of uplink %s contains IP range %s-%s", uplink.UplinkID, ipRange.StartAddress, ipRange.EndAddress)
+	})
+	if err != nil {
+		return fmt.Errorf("error allocating IP range to NSX-T edge gateway: %s", err)
+	}
+	return nil
+}
+
+// ReleaseIpAddresses gives the given IP addresses of the edge gateway back to the IP pools of the
+// external networks. Addresses used by a service or primary IPs of a subnet are not released.
+func (egw *NsxtEdgeGateway) ReleaseIpAddresses(addresses ...string) error {
+	if len(addresses) == 0 {
+		return nil
+	}
+	allocated, err := egw.GetAllocatedIpAddresses("")
+	if err != nil {
+		return err
+	}
+	reserved, err := egw.reservedIpAddresses()
+	if err != nil {
+		return err
+	}
+	isAllocated := make(map[string]bool, len(allocated))
+	for _, address := range allocated {
+		isAllocated[address] = true
+	}
+	released := make([]string, len(addresses))
+	for index, address := range addresses {
+		ip, err := parseIp(address)
+		if err != nil {
+			return err
+		}
+		released[index] = ip.String()
+		if reserved[released[index]] {
+			return fmt.Errorf("IP address %s is used by NSX-T edge gateway %s", address, egw.NsxtEdgeGateway.Name)
+		}
+		if !isAllocated[released[index]] {
+			return fmt.Errorf("IP address %s is not allocated to NSX-T edge gateway %s", address, egw.NsxtEdgeGateway.Name)
+		}
+	}
+
+	err = egw.modifyUplink("", func(uplink *types.NsxtEdgeGatewayUplink) error {
+		for index := range uplink.Subnets.Values {
+			subnet := &uplink.Subnets.Values[index]
+			if subnet.IPRanges == nil {
+				continue
+			}
+			remaining, err := removeFromIpRanges(subnet.IPRanges.Values, released)
+			if err != nil {
+				return err
+			}
+			subnet.IPRanges.Values = remaining
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error releasing IP addresses of NSX-T edge gateway: %s", err)
+	}
+	return nil
+}
+
+// ReleaseUnusedIpAddresses releases the IP addresses of the uplink which are not used (see
+// GetUnusedIpAddresses) and returns them
+func (egw *NsxtEdgeGateway) ReleaseUnusedIpAddresses(uplinkId string) ([]string, error) {
+	unused, err := egw.GetUnusedIpAddresses(uplinkId)
+	if err != nil {
+		return nil, err
+	}
+	err = egw.ReleaseIpAddresses(unused...)
+	if err != nil {
+		return nil, err
+	}
+	return unused, nil
+}
+
+// allocatedIpAddresses returns the IP addresses allocated to the uplink in the current definition
+// of the edge gateway
+func (egw *NsxtEdgeGateway) allocatedIpAddresses(uplinkId string) ([]string, error) {
+	var ipRanges []types.OpenApiIPRange
+	found := false
+	for _, uplink := range egw.NsxtEdgeGateway.EdgeGatewayUplinks {
+		if uplinkId != "" && uplink.UplinkID != uplinkId {
+			continue
+		}
+		found = true
+		for _, subnet := range uplink.Subnets.Values {
+			if subnet.IPRanges != nil {
+				ipRanges = append(ipRanges, subnet.IPRanges.Values...)
+			}
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("uplink %s not found in NSX-T edge gateway %s", uplinkId, egw.NsxtEdgeGateway.Name)
+	}
+	return ipRangesAddresses(ipRanges)
+}
+
+// reservedIpAddresses returns the IP addresses used by the services of the edge gateway and the
+// primary IPs of its subnets, in their normalized form
+func (egw *NsxtEdgeGateway) reservedIpAddresses() (map[string]bool, error) {
+	usedIps, err := egw.GetUsedIpAddresses(nil)
+	if err != nil {
+		return nil, err
+	}
+	reserved := make(map[string]bool)
+	addReserved := func(address string) {
+		if ip := net.ParseIP(address); ip != nil {
+			reserved[ip.String()] = true
+		}
+	}
+	for _, usedIp := range usedIps {
+		addReserved(usedIp.IPAddress)
+	}
+	for _, uplink := range egw.NsxtEdgeGateway.EdgeGatewayUplinks {
+		for _, subnet := range uplink.Subnets.Values {
+			addReserved(subnet.PrimaryIP)
+		}
+	}
+	return reserved, nil
+}
+
+// modifyUplink applies modify to a copy of the uplink of the edge gateway with the given ID, or of
+// all its uplinks when uplinkId is empty, and updates the edge gateway with it. The definition of
+// the edge gateway is only changed when the update succeeds.
+func (egw *NsxtEdgeGateway) modifyUplink(uplinkId string, modify func(uplink *types.NsxtEdgeGatewayUplink) error) error {
+	definition := *egw.NsxtEdgeGateway
+	definition.EdgeGatewayUplinks = make([]types.NsxtEdgeGatewayUplink, len(egw.NsxtEdgeGateway.EdgeGatewayUplinks))
+	found := false
+	for index, uplink := range egw.NsxtEdgeGateway.EdgeGatewayUplinks {
+		uplink.Subnets.Values = append([]types.NsxtEdgeGatewaySubnet(nil), uplink.Subnets.Values...)
+		for subnetIndex, subnet := range uplink.Subnets.Values {
+			if subnet.IPRanges != nil {
+				uplink.Subnets.Values[subnetIndex].IPRanges = &types.OpenApiIPRanges{
+					Values: append([]types.OpenApiIPRange(nil), subnet.IPRanges.Values...),
+				}
+			}
+		}
+		if uplinkId == "" || uplink.UplinkID == uplinkId {
+			found = true
+			err := modify(&uplink)
+			if err != nil {
+				return err
+			}
+		}
+		definition.EdgeGatewayUplinks[index] = uplink
+	}
+	if !found {
+		return fmt.Errorf("uplink %s not found in NSX-T edge gateway %s", uplinkId, egw.NsxtEdgeGateway.Name)
+	}
+
+	updated := &NsxtEdgeGateway{NsxtEdgeGateway: &definition, client: egw.client}
+	err := updated.Update()
+	if err != nil {
+		return err
+	}
+	egw.NsxtEdgeGateway = updated.NsxtEdgeGateway
+	return nil
+}
@@ -0,0 +1,147 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// Checks the allocation and the release of the IP addresses of an NSX-T edge gateway against a fake vCD
+func TestNsxtEdgeGateway_IpAddresses(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	const edgeId = "urn:vcloud:gateway:33333333-3333-3333-3333-333333333333"
+	const uplinkId = "urn:vcloud:network:44444444-4444-4444-4444-444444444444"
+	const edgePath = "/cloudapi/1.0.0/edgeGateways/" + edgeId
+	edgeJson := func(ranges string) string {
+		return `{"id":"` + edgeId + `","name":"edge1","description":"",
+		"orgVdc":{"name":"` + vcdtest.MockVdcName + `","id":"urn:vcloud:vdc:` + vcdtest.MockVdcId + `"},
+		"edgeGatewayUplinks":[{"uplinkId":"` + uplinkId + `","connected":true,"dedicated":false,
+		"subnets":{"values":[{"gateway":"10.0.0.1","prefixLength":24,"enabled":true,"primaryIp":"10.0.0.2",
+		"ipRanges":{"values":[` + ranges + `]}}]}}]}`
+	}
+	server.HandleXML(http.MethodGet, vcdtest.MockTaskPath, http.StatusOK,
+		`<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="success" href="{{server}}`+vcdtest.MockTaskPath+`"/>`)
+	server.Handle(http.MethodPut, edgePath, vcdtest.Response{
+		Status: http.StatusAccepted,
+		Header: map[string]string{"Location": "{{server}}" + vcdtest.MockTaskPath},
+	})
+	server.HandleJSON(http.MethodGet, edgePath, http.StatusOK, edgeJson(`{"startAddress":"10.0.0.2","endAddress":"10.0.0.10"}`))
+	server.HandleJSON(http.MethodGet, edgePath+"/usedIpAddresses", http.StatusOK,
+		`{"resultTotal":1,"pageCount":1,"page":1,"pageSize":128,"values":[
+		{"networkRef":{"id":"`+uplinkId+`"},"ipAddress":"10.0.0.3","category":"SNAT"}]}`)
+
+	// vCD allocates two more addresses when asked for them
+	hook := func(req *http.Request, resp *http.Response, err error, elapsed time.Duration) {
+		if req.Method == http.MethodPut && req.URL.Path == edgePath && req.ContentLength > 0 {
+			body, _ := req.GetBody()
+			sent := types.NsxtEdgeGateway{}
+			if json.NewDecoder(body).Decode(&sent) == nil && sent.EdgeGatewayUplinks[0].QuickAddAllocatedIPCount == 2 {
+				server.HandleJSON(http.MethodGet, edgePath, http.StatusOK,
+					edgeJson(`{"startAddress":"10.0.0.2","endAddress":"10.0.0.10"},{"startAddress":"10.0.0.11","endAddress":"10.0.0.12"}`))
+			}
+		}
+	}
+	vcdClient := newMockClient(t, server, WithHttpHook(hook))
+	edge := &NsxtEdgeGateway{
+		NsxtEdgeGateway: &types.NsxtEdgeGateway{ID: edgeId, Name: "edge1"},
+		client:          &vcdClient.Client,
+	}
+
+	allocated, err := edge.GetAllocatedIpAddresses(uplinkId)
+	if err != nil {
+		t.Fatalf("error retrieving allocated IP addresses: %s", err)
+	}
+	if len(allocated) != 9 || allocated[0] != "10.0.0.2" || allocated[8] != "10.0.0.10" {
+		t.Errorf("unexpected allocated IP addresses: %v", allocated)
+	}
+	unused, err := edge.GetUnusedIpAddresses("")
+	if err != nil {
+		t.Fatalf("error retrieving unused IP addresses: %s", err)
+	}
+	if len(unused) != 7 || unused[0] != "10.0.0.4" {
+		t.Errorf("expected the primary and used IP addresses to be excluded, got %v", unused)
+	}
+	if _, err = edge.GetAllocatedIpAddresses("urn:vcloud:network:unknown"); err == nil {
+		t.Errorf("expected error with unknown uplink")
+	}
+
+	// Release
+	for _, address := range []string{"10.0.0.2", "10.0.0.3", "10.0.0.11", "10.0.0"} {
+		if err = edge.ReleaseIpAddresses(address); err == nil {
+			t.Errorf("expected error releasing IP address %s", address)
+		}
+	}
+	if len(server.RequestsTo(http.MethodPut, edgePath)) != 0 {
+		t.Fatalf("expected no update of the edge gateway after invalid releases")
+	}
+	err = edge.ReleaseIpAddresses("10.0.0.5", "10.0.0.10")
+	if err != nil {
+		t.Fatalf("error releasing IP addresses: %s", err)
+	}
+	ranges := sentIpRanges(t, server.RequestsTo(http.MethodPut, edgePath))
+	expected := []types.OpenApiIPRange{
+		{StartAddress: "10.0.0.2", EndAddress: "10.0.0.4"},
+		{StartAddress: "10.0.0.6", EndAddress: "10.0.0.9"},
+	}
+	if !reflect.DeepEqual(ranges, expected) {
+		t.Errorf("expected IP ranges %v, got %v", expected, ranges)
+	}
+
+	// Allocation of a range
+	server.ClearRequests()
+	for _, ipRange := range []types.OpenApiIPRange{{StartAddress: "10.1.0.1"}, {StartAddress: "10.0.0.5", EndAddress: "10.0.0.30"}} {
+		if err = edge.AllocateIpRange(uplinkId, ipRange); err == nil {
+			t.Errorf("expected error allocating IP range %v", ipRange)
+		}
+	}
+	err = edge.AllocateIpRange(uplinkId, types.OpenApiIPRange{StartAddress: "10.0.0.20", EndAddress: "10.0.0.25"})
+	if err != nil {
+		t.Fatalf("error allocating IP range: %s", err)
+	}
+	ranges = sentIpRanges(t, server.RequestsTo(http.MethodPut, edgePath))
+	if len(ranges) != 2 || ranges[1].StartAddress != "10.0.0.20" || ranges[1].EndAddress != "10.0.0.25" {
+		t.Errorf("unexpected IP ranges: %v", ranges)
+	}
+
+	// Allocation of a number of addresses
+	server.ClearRequests()
+	if _, err = edge.AllocateIpAddresses(uplinkId, 0); err == nil {
+		t.Errorf("expected error allocating no IP address")
+	}
+	newAddresses, err := edge.AllocateIpAddresses(uplinkId, 2)
+	if err != nil {
+		t.Fatalf("error allocating IP addresses: %s", err)
+	}
+	if !reflect.DeepEqual(newAddresses, []string{"10.0.0.11", "10.0.0.12"}) {
+		t.Errorf("unexpected new IP addresses: %v", newAddresses)
+	}
+	puts := server.RequestsTo(http.MethodPut, edgePath)
+	if len(puts) != 1 || !strings.Contains(puts[0].Body, `"quickAddAllocatedIpCount": 2`) {
+		t.Errorf("unexpected allocation request: %#v", puts)
+	}
+}
+
+// sentIpRanges returns the IP ranges of the subnet sent by the only update of the edge gateway
+func sentIpRanges(t *testing.T, puts []vcdtest.Request) []types.OpenApiIPRange {
+	if len(puts) != 1 {
+		t.Fatalf("expected one update of the edge gateway, got %d", len(puts))
+	}
+	sent := types.NsxtEdgeGateway{}
+	err := json.Unmarshal([]byte(puts[0].Body), &sent)
+	if err != nil {
+		t.Fatalf("error decoding payload: %s", err)
+	}
+	return sent.EdgeGatewayUplinks[0].Subnets.Values[0].IPRanges.Values
+}
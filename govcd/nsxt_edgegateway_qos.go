@@ -0,0 +1,97 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// Gateway QoS profiles (API 36.2+, vCD 10.3.2+) are defined in NSX-T by the provider. Assigned to an
+// NSX-T edge gateway, they limit the bandwidth of its ingress and egress traffic.
+
+// GetAllNsxtEdgeGatewayQosProfiles retrieves the gateway QoS profiles of the NSX-T manager with the
+// given ID. Query parameters can be supplied to perform additional filtering.
+func (client *Client) GetAllNsxtEdgeGatewayQosProfiles(nsxtManagerId string, queryParameters url.Values) ([]*types.NsxtEdgeGatewayQosProfile, error) {
+	if nsxtManagerId == "" {
+		return nil, fmt.Errorf("empty NSX-T manager ID")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointQosProfiles
+	apiVersion, err := client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var profiles []*types.NsxtEdgeGatewayQosProfile
+	err = client.OpenApiGetAllItems(apiVersion, urlRef,
+		queryParameterFilterAnd(fiqlEq("nsxTManagerRef.id", nsxtManagerId), queryParameters), &profiles)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving gateway QoS profiles: %s", err)
+	}
+	return profiles, nil
+}
+
+// GetNsxtEdgeGatewayQosProfileByDisplayName retrieves the gateway QoS profile of the NSX-T manager
+// with the given ID and display name
+func (client *Client) GetNsxtEdgeGatewayQosProfileByDisplayName(nsxtManagerId, name string) (*types.NsxtEdgeGatewayQosProfile, error) {
+	queryParams := url.Values{}
+	queryParams.Set("filter", fiqlEq("displayName", name))
+	profiles, err := client.GetAllNsxtEdgeGatewayQosProfiles(nsxtManagerId, queryParams)
+	if err != nil {
+		return nil, err
+	}
+	if len(profiles) == 0 {
+		return nil, wrapErrorf(ErrorEntityNotFound, "gateway QoS profile '%s' not found: %s", name, ErrorEntityNotFound)
+	}
+	if len(profiles) > 1 {
+		return nil, fmt.Errorf("more than one gateway QoS profile found with name '%s'", name)
+	}
+	return profiles[0], nil
+}
+
+// GetQosConfig retrieves the gateway QoS profiles assigned to the ingress and egress traffic of the
+// NSX-T edge gateway
+func (egw *NsxtEdgeGateway) GetQosConfig() (*types.NsxtEdgeGatewayQos, error) {
+	urlRef, apiVersion, err := egw.buildEndpoint(types.OpenApiEndpointEdgeGatewayQos)
+	if err != nil {
+		return nil, err
+	}
+	qos := &types.NsxtEdgeGatewayQos{}
+	err = egw.client.OpenApiGetItem(apiVersion, urlRef, nil, qos)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving QoS configuration of NSX-T edge gateway: %s", err)
+	}
+	return qos, nil
+}
+
+// UpdateQosConfig assigns the gateway QoS profiles of qosConfig to the ingress and egress traffic of
+// the NSX-T edge gateway. A nil profile removes the limit of the corresponding traffic.
+func (egw *NsxtEdgeGateway) UpdateQosConfig(qosConfig *types.NsxtEdgeGatewayQos) (*types.NsxtEdgeGatewayQos, error) {
+	if qosConfig == nil {
+		return nil, fmt.Errorf("no QoS configuration given")
+	}
+	for _, profile := range []*types.OpenApiReference{qosConfig.IngressProfile, qosConfig.EgressProfile} {
+		if profile != nil && profile.ID == "" {
+			return nil, fmt.Errorf("gateway QoS profiles need an ID")
+		}
+	}
+
+	urlRef, apiVersion, err := egw.buildEndpoint(types.OpenApiEndpointEdgeGatewayQos)
+	if err != nil {
+		return nil, err
+	}
+	updated := &types.NsxtEdgeGatewayQos{}
+	err = egw.client.OpenApiPutItem(apiVersion, urlRef, nil, qosConfig, updated)
+	if err != nil {
+		return nil, fmt.Errorf("error updating QoS configuration of NSX-T edge gateway: %s", err)
+	}
+	return updated, nil
+}
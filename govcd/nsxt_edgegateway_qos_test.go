@@ -0,0 +1,83 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// Checks the lookup of gateway QoS profiles and their assignment to an NSX-T edge gateway against a
+// fake vCD
+func TestNsxtEdgeGateway_Qos(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	const managerId = "urn:vcloud:nsxtmanager:11111111-1111-1111-1111-111111111111"
+	const edgeId = "urn:vcloud:gateway:33333333-3333-3333-3333-333333333333"
+	const profilesPath = "/cloudapi/1.0.0/nsxTResources/gatewayQoSProfiles"
+	const qosPath = "/cloudapi/1.0.0/edgeGateways/" + edgeId + "/qos"
+	server.HandleJSON(http.MethodGet, profilesPath, http.StatusOK,
+		`{"resultTotal":1,"pageCount":1,"page":1,"pageSize":128,"values":[{"id":"qos-100","displayName":"100M",
+		"nsxTManagerRef":{"id":"`+managerId+`"},"committedBandwidth":100,"burstSize":1000000,"excessAction":"DROP"}]}`)
+	server.HandleJSON(http.MethodGet, qosPath, http.StatusOK, `{"ingressProfile":null,"egressProfile":null}`)
+	server.HandleJSON(http.MethodPut, qosPath, http.StatusOK,
+		`{"ingressProfile":{"id":"qos-100","name":"100M"},"egressProfile":null}`)
+
+	vcdClient := newMockClient(t, server)
+	client := &vcdClient.Client
+
+	if _, err := client.GetAllNsxtEdgeGatewayQosProfiles("", nil); err == nil {
+		t.Errorf("expected error retrieving gateway QoS profiles without NSX-T manager")
+	}
+	profile, err := client.GetNsxtEdgeGatewayQosProfileByDisplayName(managerId, "100M")
+	if err != nil {
+		t.Fatalf("error retrieving gateway QoS profile: %s", err)
+	}
+	if profile.ID != "qos-100" || profile.CommittedBandwidth != 100 {
+		t.Errorf("unexpected gateway QoS profile: %+v", profile)
+	}
+	gets := server.RequestsTo(http.MethodGet, profilesPath)
+	query, _ := url.ParseQuery(gets[len(gets)-1].RawQuery)
+	if query.Get("filter") != "displayName==100M;nsxTManagerRef.id=="+managerId ||
+		gets[0].Header.Get("Accept") != "application/json;version=36.2" {
+		t.Errorf("unexpected request: %#v", gets[len(gets)-1])
+	}
+	server.HandleJSON(http.MethodGet, profilesPath, http.StatusOK, `{"resultTotal":0,"pageCount":0,"page":1,"pageSize":128,"values":[]}`)
+	_, err = client.GetNsxtEdgeGatewayQosProfileByDisplayName(managerId, "1G")
+	if !errors.Is(err, ErrorEntityNotFound) {
+		t.Errorf("expected entity not found error, got %v", err)
+	}
+
+	edge := &NsxtEdgeGateway{NsxtEdgeGateway: &types.NsxtEdgeGateway{ID: edgeId, Name: "edge1"}, client: client}
+	qos, err := edge.GetQosConfig()
+	if err != nil {
+		t.Fatalf("error retrieving QoS configuration: %s", err)
+	}
+	if qos.IngressProfile != nil || qos.EgressProfile != nil {
+		t.Errorf("unexpected QoS configuration: %+v", qos)
+	}
+	if _, err = edge.UpdateQosConfig(&types.NsxtEdgeGatewayQos{EgressProfile: &types.OpenApiReference{Name: "100M"}}); err == nil {
+		t.Errorf("expected error assigning a gateway QoS profile without ID")
+	}
+	qos, err = edge.UpdateQosConfig(&types.NsxtEdgeGatewayQos{IngressProfile: &types.OpenApiReference{ID: profile.ID}})
+	if err != nil {
+		t.Fatalf("error updating QoS configuration: %s", err)
+	}
+	if qos.IngressProfile == nil || qos.IngressProfile.Name != "100M" || qos.EgressProfile != nil {
+		t.Errorf("unexpected QoS configuration: %+v", qos)
+	}
+	// The unlimited egress traffic is sent as null
+	puts := server.RequestsTo(http.MethodPut, qosPath)
+	if len(puts) != 1 || !strings.Contains(puts[0].Body, `"id": "qos-100"`) || !strings.Contains(puts[0].Body, `"egressProfile": null`) {
+		t.Errorf("unexpected QoS updates: %#v", puts)
+	}
+}
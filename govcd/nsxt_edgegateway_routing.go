@@ -0,0 +1,483 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// Routing of the NSX-T edge gateways: static routes (API 37.0+, vCD 10.4+) and BGP, with its
+// neighbors and the IP prefix lists used as route filters (API 35.0+, vCD 10.2+).
+// vCD answers the creation of these entities with a task owned by the edge gateway, so the created
+// entity is looked up after the task completes.
+
+// NsxtEdgeGatewayStaticRoute is a static route of an NSX-T edge gateway
+type NsxtEdgeGatewayStaticRoute struct {
+	NsxtEdgeGatewayStaticRoute *types.NsxtEdgeGatewayStaticRoute
+	edge                       *NsxtEdgeGateway
+}
+
+// NsxtEdgeGatewayBgpNeighbor is a BGP neighbor of an NSX-T edge gateway
+type NsxtEdgeGatewayBgpNeighbor struct {
+	NsxtEdgeGatewayBgpNeighbor *types.NsxtEdgeGatewayBgpNeighbor
+	edge                       *NsxtEdgeGateway
+}
+
+// NsxtEdgeGatewayBgpIpPrefixList is a BGP IP prefix list of an NSX-T edge gateway
+type NsxtEdgeGatewayBgpIpPrefixList struct {
+	NsxtEdgeGatewayBgpIpPrefixList *types.NsxtEdgeGatewayBgpIpPrefixList
+	edge                           *NsxtEdgeGateway
+}
+
+// GetAllStaticRoutes retrieves the static routes of the edge gateway. Query parameters can be
+// supplied to perform additional filtering (e.g. "filter" => "name==route1")
+func (egw *NsxtEdgeGateway) GetAllStaticRoutes(queryParameters url.Values) ([]*NsxtEdgeGatewayStaticRoute, error) {
+	var typeResponses []*types.NsxtEdgeGatewayStaticRoute
+	err := egw.getAllRoutingItems(types.OpenApiEndpointEdgeGatewayStaticRoutes, queryParameters, &typeResponses)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving static routes: %s", err)
+	}
+	routes := make([]*NsxtEdgeGatewayStaticRoute, len(typeResponses))
+	for index, typeResponse := range typeResponses {
+		routes[index] = &NsxtEdgeGatewayStaticRoute{NsxtEdgeGatewayStaticRoute: typeResponse, edge: egw}
+	}
+	return routes, nil
+}
+
+// GetStaticRouteByName retrieves the static route of the edge gateway with the given name. As names
+// are not unique, an error is returned when several routes have this name.
+func (egw *NsxtEdgeGateway) GetStaticRouteByName(name string) (*NsxtEdgeGatewayStaticRoute, error) {
+	routes, err := egw.GetAllStaticRoutes(url.Values{"filter": []string{fiqlEq("name", name)}})
+	if err != nil {
+		return nil, err
+	}
+	if len(routes) == 0 {
+		return nil, wrapErrorf(ErrorEntityNotFound, "static route '%s' not found in NSX-T edge gateway %s: %s",
+			name, egw.NsxtEdgeGateway.Name, ErrorEntityNotFound)
+	}
+	if len(routes) > 1 {
+		return nil, fmt.Errorf("more than one static route found with name '%s': use the ID instead", name)
+	}
+	return routes[0], nil
+}
+
+// GetStaticRouteById retrieves the static route of the edge gateway with the given ID
+func (egw *NsxtEdgeGateway) GetStaticRouteById(id string) (*NsxtEdgeGatewayStaticRoute, error) {
+	route := &types.NsxtEdgeGatewayStaticRoute{}
+	err := egw.getRoutingItem(types.OpenApiEndpointEdgeGatewayStaticRoutes, id, route)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving static route: %s", err)
+	}
+	return &NsxtEdgeGatewayStaticRoute{NsxtEdgeGatewayStaticRoute: route, edge: egw}, nil
+}
+
+// CreateStaticRoute creates a static route in the edge gateway
+func (egw *NsxtEdgeGateway) CreateStaticRoute(routeConfig *types.NsxtEdgeGatewayStaticRoute) (*NsxtEdgeGatewayStaticRoute, error) {
+	err := validateNsxtStaticRoute(routeConfig)
+	if err != nil {
+		return nil, err
+	}
+	// Routes can share their name: the new one is the route with this name which did not exist before
+	existing, err := egw.GetAllStaticRoutes(url.Values{"filter": []string{fiqlEq("name", routeConfig.Name)}})
+	if err != nil {
+		return nil, err
+	}
+	existingIds := make(map[string]bool)
+	for _, route := range existing {
+		existingIds[route.NsxtEdgeGatewayStaticRoute.ID] = true
+	}
+
+	err = egw.createRoutingItem(types.OpenApiEndpointEdgeGatewayStaticRoutes, routeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error creating static route %s: %s", routeConfig.Name, err)
+	}
+	routes, err := egw.GetAllStaticRoutes(url.Values{"filter": []string{fiqlEq("name", routeConfig.Name)}})
+	if err != nil {
+		return nil, err
+	}
+	for _, route := range routes {
+		if !existingIds[route.NsxtEdgeGatewayStaticRoute.ID] {
+			return route, nil
+		}
+	}
+	return nil, fmt.Errorf("static route %s not found after its creation", routeConfig.Name)
+}
+
+// Refresh retrieves the current definition of the static route
+func (route *NsxtEdgeGatewayStaticRoute) Refresh() error {
+	refreshed, err := route.edge.GetStaticRouteById(route.NsxtEdgeGatewayStaticRoute.ID)
+	if err != nil {
+		return err
+	}
+	route.NsxtEdgeGatewayStaticRoute = refreshed.NsxtEdgeGatewayStaticRoute
+	return nil
+}
+
+// Update sends the current definition of the static route (name, description, network and next
+// hops) to vCD
+func (route *NsxtEdgeGatewayStaticRoute) Update() error {
+	err := validateNsxtStaticRoute(route.NsxtEdgeGatewayStaticRoute)
+	if err != nil {
+		return err
+	}
+	updated := &types.NsxtEdgeGatewayStaticRoute{}
+	err = route.edge.updateRoutingItem(types.OpenApiEndpointEdgeGatewayStaticRoutes,
+		route.NsxtEdgeGatewayStaticRoute.ID, route.NsxtEdgeGatewayStaticRoute, updated)
+	if err != nil {
+		return fmt.Errorf("error updating static route: %s", err)
+	}
+	route.NsxtEdgeGatewayStaticRoute = updated
+	return nil
+}
+
+// Delete removes the static route
+func (route *NsxtEdgeGatewayStaticRoute) Delete() error {
+	err := route.edge.deleteRoutingItem(types.OpenApiEndpointEdgeGatewayStaticRoutes, route.NsxtEdgeGatewayStaticRoute.ID)
+	if err != nil {
+		return fmt.Errorf("error deleting static route: %s", err)
+	}
+	return nil
+}
+
+// GetBgpConfiguration retrieves the BGP service of the edge gateway
+func (egw *NsxtEdgeGateway) GetBgpConfiguration() (*types.NsxtEdgeGatewayBgpConfig, error) {
+	bgpConfig := &types.NsxtEdgeGatewayBgpConfig{}
+	err := egw.getRoutingItem(types.OpenApiEndpointEdgeGatewayBgp, "", bgpConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving BGP configuration: %s", err)
+	}
+	return bgpConfig, nil
+}
+
+// UpdateBgpConfiguration sets the BGP service of the edge gateway: local autonomous system, ECMP and
+// graceful restart. When bgpConfig has no version, the version of the current configuration is used.
+func (egw *NsxtEdgeGateway) UpdateBgpConfiguration(bgpConfig *types.NsxtEdgeGatewayBgpConfig) (*types.NsxtEdgeGatewayBgpConfig, error) {
+	if bgpConfig == nil {
+		return nil, fmt.Errorf("no BGP configuration given")
+	}
+	if bgpConfig.Enabled && bgpConfig.LocalASNumber == "" {
+		return nil, fmt.Errorf("an enabled BGP service needs a local AS number")
+	}
+	if bgpConfig.GracefulRestart != nil {
+		err := validateBgpGracefulRestartMode(bgpConfig.GracefulRestart.Mode)
+		if err != nil {
+			return nil, err
+		}
+	}
+	payload := *bgpConfig
+	if payload.Version == nil {
+		current, err := egw.GetBgpConfiguration()
+		if err != nil {
+			return nil, err
+		}
+		payload.Version = current.Version
+	}
+
+	updated := &types.NsxtEdgeGatewayBgpConfig{}
+	err := egw.updateRoutingItem(types.OpenApiEndpointEdgeGatewayBgp, "", &payload, updated)
+	if err != nil {
+		return nil, fmt.Errorf("error updating BGP configuration: %s", err)
+	}
+	return updated, nil
+}
+
+// GetAllBgpNeighbors retrieves the BGP neighbors of the edge gateway. Query parameters can be
+// supplied to perform additional filtering.
+func (egw *NsxtEdgeGateway) GetAllBgpNeighbors(queryParameters url.Values) ([]*NsxtEdgeGatewayBgpNeighbor, error) {
+	var typeResponses []*types.NsxtEdgeGatewayBgpNeighbor
+	err := egw.getAllRoutingItems(types.OpenApiEndpointEdgeGatewayBgpNeighbors, queryParameters, &typeResponses)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving BGP neighbors: %s", err)
+	}
+	neighbors := make([]*NsxtEdgeGatewayBgpNeighbor, len(typeResponses))
+	for index, typeResponse := range typeResponses {
+		neighbors[index] = &NsxtEdgeGatewayBgpNeighbor{NsxtEdgeGatewayBgpNeighbor: typeResponse, edge: egw}
+	}
+	return neighbors, nil
+}
+
+// GetBgpNeighborByIp retrieves the BGP neighbor of the edge gateway with the given IP address
+func (egw *NsxtEdgeGateway) GetBgpNeighborByIp(neighborAddress string) (*NsxtEdgeGatewayBgpNeighbor, error) {
+	neighbors, err := egw.GetAllBgpNeighbors(nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, neighbor := range neighbors {
+		if neighbor.NsxtEdgeGatewayBgpNeighbor.NeighborAddress == neighborAddress {
+			return neighbor, nil
+		}
+	}
+	return nil, wrapErrorf(ErrorEntityNotFound, "BGP neighbor '%s' not found in NSX-T edge gateway %s: %s",
+		neighborAddress, egw.NsxtEdgeGateway.Name, ErrorEntityNotFound)
+}
+
+// GetBgpNeighborById retrieves the BGP neighbor of the edge gateway with the given ID
+func (egw *NsxtEdgeGateway) GetBgpNeighborById(id string) (*NsxtEdgeGatewayBgpNeighbor, error) {
+	neighbor := &types.NsxtEdgeGatewayBgpNeighbor{}
+	err := egw.getRoutingItem(types.OpenApiEndpointEdgeGatewayBgpNeighbors, id, neighbor)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving BGP neighbor: %s", err)
+	}
+	return &NsxtEdgeGatewayBgpNeighbor{NsxtEdgeGatewayBgpNeighbor: neighbor, edge: egw}, nil
+}
+
+// CreateBgpNeighbor creates a BGP neighbor in the edge gateway. The password, if any, is sent to
+// vCD but never returned.
+func (egw *NsxtEdgeGateway) CreateBgpNeighbor(neighborConfig *types.NsxtEdgeGatewayBgpNeighbor) (*NsxtEdgeGatewayBgpNeighbor, error) {
+	err := validateNsxtBgpNeighbor(neighborConfig)
+	if err != nil {
+		return nil, err
+	}
+	err = egw.createRoutingItem(types.OpenApiEndpointEdgeGatewayBgpNeighbors, neighborConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error creating BGP neighbor %s: %s", neighborConfig.NeighborAddress, err)
+	}
+	return egw.GetBgpNeighborByIp(neighborConfig.NeighborAddress)
+}
+
+// Refresh retrieves the current definition of the BGP neighbor
+func (neighbor *NsxtEdgeGatewayBgpNeighbor) Refresh() error {
+	refreshed, err := neighbor.edge.GetBgpNeighborById(neighbor.NsxtEdgeGatewayBgpNeighbor.ID)
+	if err != nil {
+		return err
+	}
+	neighbor.NsxtEdgeGatewayBgpNeighbor = refreshed.NsxtEdgeGatewayBgpNeighbor
+	return nil
+}
+
+// Update sends the current definition of the BGP neighbor to vCD. The password is kept when
+// NeighborPassword is empty.
+func (neighbor *NsxtEdgeGatewayBgpNeighbor) Update() error {
+	err := validateNsxtBgpNeighbor(neighbor.NsxtEdgeGatewayBgpNeighbor)
+	if err != nil {
+		return err
+	}
+	updated := &types.NsxtEdgeGatewayBgpNeighbor{}
+	err = neighbor.edge.updateRoutingItem(types.OpenApiEndpointEdgeGatewayBgpNeighbors,
+		neighbor.NsxtEdgeGatewayBgpNeighbor.ID, neighbor.NsxtEdgeGatewayBgpNeighbor, updated)
+	if err != nil {
+		return fmt.Errorf("error updating BGP neighbor: %s", err)
+	}
+	neighbor.NsxtEdgeGatewayBgpNeighbor = updated
+	return nil
+}
+
+// Delete removes the BGP neighbor
+func (neighbor *NsxtEdgeGatewayBgpNeighbor) Delete() error {
+	err := neighbor.edge.deleteRoutingItem(types.OpenApiEndpointEdgeGatewayBgpNeighbors, neighbor.NsxtEdgeGatewayBgpNeighbor.ID)
+	if err != nil {
+		return fmt.Errorf("error deleting BGP neighbor: %s", err)
+	}
+	return nil
+}
+
+// GetAllBgpIpPrefixLists retrieves the BGP IP prefix lists of the edge gateway. Query parameters
+// can be supplied to perform additional filtering.
+func (egw *NsxtEdgeGateway) GetAllBgpIpPrefixLists(queryParameters url.Values) ([]*NsxtEdgeGatewayBgpIpPrefixList, error) {
+	var typeResponses []*types.NsxtEdgeGatewayBgpIpPrefixList
+	err := egw.getAllRoutingItems(types.OpenApiEndpointEdgeGatewayBgpPrefixLists, queryParameters, &typeResponses)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving BGP IP prefix lists: %s", err)
+	}
+	prefixLists := make([]*NsxtEdgeGatewayBgpIpPrefixList, len(typeResponses))
+	for index, typeResponse := range typeResponses {
+		prefixLists[index] = &NsxtEdgeGatewayBgpIpPrefixList{NsxtEdgeGatewayBgpIpPrefixList: typeResponse, edge: egw}
+	}
+	return prefixLists, nil
+}
+
+// GetBgpIpPrefixListByName retrieves the BGP IP prefix list of the edge gateway with the given name
+func (egw *NsxtEdgeGateway) GetBgpIpPrefixListByName(name string) (*NsxtEdgeGatewayBgpIpPrefixList, error) {
+	prefixLists, err := egw.GetAllBgpIpPrefixLists(nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, prefixList := range prefixLists {
+		if prefixList.NsxtEdgeGatewayBgpIpPrefixList.Name == name {
+			return prefixList, nil
+		}
+	}
+	return nil, wrapErrorf(ErrorEntityNotFound, "BGP IP prefix list '%s' not found in NSX-T edge gateway %s: %s",
+		name, egw.NsxtEdgeGateway.Name, ErrorEntityNotFound)
+}
+
+// GetBgpIpPrefixListById retrieves the BGP IP prefix list of the edge gateway with the given ID
+func (egw *NsxtEdgeGateway) GetBgpIpPrefixListById(id string) (*NsxtEdgeGatewayBgpIpPrefixList, error) {
+	prefixList := &types.NsxtEdgeGatewayBgpIpPrefixList{}
+	err := egw.getRoutingItem(types.OpenApiEndpointEdgeGatewayBgpPrefixLists, id, prefixList)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving BGP IP prefix list: %s", err)
+	}
+	return &NsxtEdgeGatewayBgpIpPrefixList{NsxtEdgeGatewayBgpIpPrefixList: prefixList, edge: egw}, nil
+}
+
+// CreateBgpIpPrefixList creates a BGP IP prefix list in the edge gateway
+func (egw *NsxtEdgeGateway) CreateBgpIpPrefixList(prefixListConfig *types.NsxtEdgeGatewayBgpIpPrefixList) (*NsxtEdgeGatewayBgpIpPrefixList, error) {
+	err := validateNsxtBgpIpPrefixList(prefixListConfig)
+	if err != nil {
+		return nil, err
+	}
+	err = egw.createRoutingItem(types.OpenApiEndpointEdgeGatewayBgpPrefixLists, prefixListConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error creating BGP IP prefix list %s: %s", prefixListConfig.Name, err)
+	}
+	return egw.GetBgpIpPrefixListByName(prefixListConfig.Name)
+}
+
+// Reference returns the reference to the prefix list, as used in the route filters of the BGP
+// neighbors
+func (prefixList *NsxtEdgeGatewayBgpIpPrefixList) Reference() *types.OpenApiReference {
+	return &types.OpenApiReference{ID: prefixList.NsxtEdgeGatewayBgpIpPrefixList.ID, Name: prefixList.NsxtEdgeGatewayBgpIpPrefixList.Name}
+}
+
+// Refresh retrieves the current definition of the BGP IP prefix list
+func (prefixList *NsxtEdgeGatewayBgpIpPrefixList) Refresh() error {
+	refreshed, err := prefixList.edge.GetBgpIpPrefixListById(prefixList.NsxtEdgeGatewayBgpIpPrefixList.ID)
+	if err != nil {
+		return err
+	}
+	prefixList.NsxtEdgeGatewayBgpIpPrefixList = refreshed.NsxtEdgeGatewayBgpIpPrefixList
+	return nil
+}
+
+// Update sends the current definition of the BGP IP prefix list to vCD
+func (prefixList *NsxtEdgeGatewayBgpIpPrefixList) Update() error {
+	err := validateNsxtBgpIpPrefixList(prefixList.NsxtEdgeGatewayBgpIpPrefixList)
+	if err != nil {
+		return err
+	}
+	updated := &types.NsxtEdgeGatewayBgpIpPrefixList{}
+	err = prefixList.edge.updateRoutingItem(types.OpenApiEndpointEdgeGatewayBgpPrefixLists,
+		prefixList.NsxtEdgeGatewayBgpIpPrefixList.ID, prefixList.NsxtEdgeGatewayBgpIpPrefixList, updated)
+	if err != nil {
+		return fmt.Errorf("error updating BGP IP prefix list: %s", err)
+	}
+	prefixList.NsxtEdgeGatewayBgpIpPrefixList = updated
+	return nil
+}
+
+// Delete removes the BGP IP prefix list. It fails if the list is a route filter of a BGP neighbor.
+func (prefixList *NsxtEdgeGatewayBgpIpPrefixList) Delete() error {
+	err := prefixList.edge.deleteRoutingItem(types.OpenApiEndpointEdgeGatewayBgpPrefixLists, prefixList.NsxtEdgeGatewayBgpIpPrefixList.ID)
+	if err != nil {
+		return fmt.Errorf("error deleting BGP IP prefix list: %s", err)
+	}
+	return nil
+}
+
+// getAllRoutingItems retrieves all the items of a routing endpoint of the edge gateway into outType
+func (egw *NsxtEdgeGateway) getAllRoutingItems(endpointFormat string, queryParameters url.Values, outType interface{}) error {
+	urlRef, apiVersion, err := egw.buildEndpoint(endpointFormat)
+	if err != nil {
+		return err
+	}
+	return egw.client.OpenApiGetAllItems(apiVersion, urlRef, queryParameters, outType)
+}
+
+// getRoutingItem retrieves the item with the given ID of a routing endpoint of the edge gateway, or
+// the endpoint itself when id is empty
+func (egw *NsxtEdgeGateway) getRoutingItem(endpointFormat, id string, outType interface{}) error {
+	urlRef, apiVersion, err := egw.buildEndpoint(endpointFormat, id)
+	if err != nil {
+		return err
+	}
+	return egw.client.OpenApiGetItem(apiVersion, urlRef, nil, outType)
+}
+
+// createRoutingItem creates an item in a routing endpoint of the edge gateway and waits for its task
+func (egw *NsxtEdgeGateway) createRoutingItem(endpointFormat string, payload interface{}) error {
+	urlRef, apiVersion, err := egw.buildEndpoint(endpointFormat)
+	if err != nil {
+		return err
+	}
+	return egw.client.OpenApiPostItem(apiVersion, urlRef, nil, payload, nil)
+}
+
+// updateRoutingItem updates the item with the given ID of a routing endpoint of the edge gateway,
+// or the endpoint itself when id is empty, and retrieves the result into outType
+func (egw *NsxtEdgeGateway) updateRoutingItem(endpointFormat, id string, payload, outType interface{}) error {
+	urlRef, apiVersion, err := egw.buildEndpoint(endpointFormat, id)
+	if err != nil {
+		return err
+	}
+	return egw.client.OpenApiPutItem(apiVersion, urlRef, nil, payload, outType)
+}
+
+// deleteRoutingItem removes the item with the given ID of a routing endpoint of the edge gateway
+func (egw *NsxtEdgeGateway) deleteRoutingItem(endpointFormat, id string) error {
+	if id == "" {
+		return fmt.Errorf("cannot delete routing item without ID")
+	}
+	urlRef, apiVersion, err := egw.buildEndpoint(endpointFormat, id)
+	if err != nil {
+		return err
+	}
+	return egw.client.OpenApiDeleteItem(apiVersion, urlRef, nil)
+}
+
+// validateNsxtStaticRoute checks the fields needed to create or update a static route
+func validateNsxtStaticRoute(routeConfig *types.NsxtEdgeGatewayStaticRoute) error {
+	if routeConfig == nil || routeConfig.Name == "" {
+		return fmt.Errorf("static route name is required")
+	}
+	if _, _, err := net.ParseCIDR(routeConfig.NetworkCidr); err != nil {
+		return fmt.Errorf("static route %s needs a network in CIDR format: %s", routeConfig.Name, err)
+	}
+	if len(routeConfig.NextHops) == 0 {
+		return fmt.Errorf("static route %s needs a next hop", routeConfig.Name)
+	}
+	for _, nextHop := range routeConfig.NextHops {
+		if net.ParseIP(nextHop.IPAddress) == nil {
+			return fmt.Errorf("invalid next hop IP address '%s' in static route %s", nextHop.IPAddress, routeConfig.Name)
+		}
+		if nextHop.AdminDistance < 1 || nextHop.AdminDistance > 255 {
+			return fmt.Errorf("admin distance of next hop %s must be between 1 and 255", nextHop.IPAddress)
+		}
+	}
+	return nil
+}
+
+// validateNsxtBgpNeighbor checks the fields needed to create or update a BGP neighbor
+func validateNsxtBgpNeighbor(neighborConfig *types.NsxtEdgeGatewayBgpNeighbor) error {
+	if neighborConfig == nil || net.ParseIP(neighborConfig.NeighborAddress) == nil {
+		return fmt.Errorf("BGP neighbor needs a valid IP address")
+	}
+	if neighborConfig.RemoteASNumber == "" {
+		return fmt.Errorf("BGP neighbor %s needs a remote AS number", neighborConfig.NeighborAddress)
+	}
+	if neighborConfig.GracefulRestartMode != "" {
+		return validateBgpGracefulRestartMode(neighborConfig.GracefulRestartMode)
+	}
+	return nil
+}
+
+// validateNsxtBgpIpPrefixList checks the fields needed to create or update a BGP IP prefix list
+func validateNsxtBgpIpPrefixList(prefixListConfig *types.NsxtEdgeGatewayBgpIpPrefixList) error {
+	if prefixListConfig == nil || prefixListConfig.Name == "" {
+		return fmt.Errorf("BGP IP prefix list name is required")
+	}
+	for _, prefix := range prefixListConfig.Prefixes {
+		if _, _, err := net.ParseCIDR(prefix.Network); err != nil {
+			return fmt.Errorf("prefix of BGP IP prefix list %s needs a network in CIDR format: %s", prefixListConfig.Name, err)
+		}
+		if prefix.Action != types.NsxtBgpPrefixActionPermit && prefix.Action != types.NsxtBgpPrefixActionDeny {
+			return fmt.Errorf("invalid action '%s' for prefix %s", prefix.Action, prefix.Network)
+		}
+	}
+	return nil
+}
+
+// validateBgpGracefulRestartMode checks that mode is one of the NsxtBgpGracefulRestart* constants
+func validateBgpGracefulRestartMode(mode string) error {
+	switch mode {
+	case types.NsxtBgpGracefulRestartDisable, types.NsxtBgpGracefulRestartHelperOnly, types.NsxtBgpGracefulRestartGracefulAndHelper:
+		return nil
+	}
+	return fmt.Errorf("invalid BGP graceful restart mode '%s'", mode)
+}
@@ -0,0 +1,165 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// Checks the static routes and the BGP configuration of an NSX-T edge gateway against a fake vCD
+func TestNsxtEdgeGateway_Routing(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	const edgeId = "urn:vcloud:gateway:33333333-3333-3333-3333-333333333333"
+	const routingPath = "/cloudapi/1.0.0/edgeGateways/" + edgeId + "/routing/"
+	const routesPath = routingPath + "staticRoutes/"
+	const neighborsPath = routingPath + "bgp/neighbors/"
+	const prefixListsPath = routingPath + "bgp/prefixLists/"
+	oldRouteJson := `{"id":"route-1","name":"route1","networkCidr":"10.10.0.0/16","nextHops":[{"ipAddress":"10.0.0.254","adminDistance":1}]}`
+	newRouteJson := `{"id":"route-2","name":"route1","networkCidr":"10.20.0.0/16","nextHops":[{"ipAddress":"10.0.0.254","adminDistance":2}]}`
+	taskResponse := vcdtest.Response{
+		Status: http.StatusAccepted,
+		Header: map[string]string{"Location": "{{server}}" + vcdtest.MockTaskPath},
+	}
+	server.Handle(http.MethodPost, routesPath, taskResponse)
+	server.Handle(http.MethodPost, neighborsPath, taskResponse)
+	server.Handle(http.MethodPost, prefixListsPath, taskResponse)
+	server.Handle(http.MethodPut, routesPath+"route-2", taskResponse)
+	server.Handle(http.MethodDelete, routesPath+"route-2", taskResponse)
+	server.Handle(http.MethodPut, routingPath+"bgp", taskResponse)
+	server.HandleJSON(http.MethodGet, routesPath, http.StatusOK,
+		`{"resultTotal":1,"pageCount":1,"page":1,"pageSize":128,"values":[`+oldRouteJson+`]}`)
+	server.HandleJSON(http.MethodGet, routesPath+"route-2", http.StatusOK, newRouteJson)
+	server.HandleJSON(http.MethodGet, routingPath+"bgp", http.StatusOK,
+		`{"enabled":true,"localASNumber":"65000","ecmp":true,"gracefulRestart":{"mode":"HELPER_ONLY"},"version":{"version":7}}`)
+	server.HandleJSON(http.MethodGet, prefixListsPath, http.StatusOK,
+		`{"resultTotal":1,"pageCount":1,"page":1,"pageSize":128,"values":[
+		{"id":"list-1","name":"tenant-prefixes","prefixes":[{"network":"10.20.0.0/16","action":"PERMIT"}]}]}`)
+	server.HandleJSON(http.MethodGet, neighborsPath, http.StatusOK,
+		`{"resultTotal":1,"pageCount":1,"page":1,"pageSize":128,"values":[
+		{"id":"neighbor-1","neighborAddress":"10.0.0.1","remoteASNumber":"65001","outRoutesFilterRef":{"id":"list-1"}}]}`)
+
+	// The list of static routes includes the new route once it is created
+	hook := func(req *http.Request, resp *http.Response, err error, elapsed time.Duration) {
+		if req.Method == http.MethodPost && req.URL.Path == routesPath {
+			server.HandleJSON(http.MethodGet, routesPath, http.StatusOK,
+				`{"resultTotal":2,"pageCount":1,"page":1,"pageSize":128,"values":[`+oldRouteJson+`,`+newRouteJson+`]}`)
+		}
+	}
+	vcdClient := newMockClient(t, server, WithHttpHook(hook))
+	edge := &NsxtEdgeGateway{
+		NsxtEdgeGateway: &types.NsxtEdgeGateway{ID: edgeId, Name: "edge1"},
+		client:          &vcdClient.Client,
+	}
+
+	routeConfig := &types.NsxtEdgeGatewayStaticRoute{
+		Name:        "route1",
+		NetworkCidr: "10.20.0.0/16",
+		NextHops:    []types.NsxtEdgeGatewayStaticRouteNextHop{{IPAddress: "10.0.0.254", AdminDistance: 2}},
+	}
+	invalidRoutes := []*types.NsxtEdgeGatewayStaticRoute{
+		{Name: "route1", NetworkCidr: "10.20.0.0", NextHops: routeConfig.NextHops},
+		{Name: "route1", NetworkCidr: "10.20.0.0/16"},
+		{Name: "route1", NetworkCidr: "10.20.0.0/16", NextHops: []types.NsxtEdgeGatewayStaticRouteNextHop{{IPAddress: "10.0.0.254"}}},
+	}
+	for _, invalidRoute := range invalidRoutes {
+		if _, err := edge.CreateStaticRoute(invalidRoute); err == nil {
+			t.Errorf("expected error creating static route %#v", invalidRoute)
+		}
+	}
+	route, err := edge.CreateStaticRoute(routeConfig)
+	if err != nil {
+		t.Fatalf("error creating static route: %s", err)
+	}
+	if route.NsxtEdgeGatewayStaticRoute.ID != "route-2" {
+		t.Errorf("expected the new static route, got %#v", route.NsxtEdgeGatewayStaticRoute)
+	}
+	posts := server.RequestsTo(http.MethodPost, routesPath)
+	if len(posts) != 1 || posts[0].Header.Get("Accept") != "application/json;version=37.0" {
+		t.Errorf("unexpected static route creation: %#v", posts)
+	}
+	if _, err = edge.GetStaticRouteByName("route1"); err == nil || errors.Is(err, ErrorEntityNotFound) {
+		t.Errorf("expected error retrieving static route with duplicate name, got %v", err)
+	}
+	route.NsxtEdgeGatewayStaticRoute.Description = "updated"
+	err = route.Update()
+	if err != nil {
+		t.Fatalf("error updating static route: %s", err)
+	}
+	puts := server.RequestsTo(http.MethodPut, routesPath+"route-2")
+	if len(puts) != 1 || !strings.Contains(puts[0].Body, `"description": "updated"`) {
+		t.Errorf("unexpected static route update: %#v", puts)
+	}
+	err = route.Delete()
+	if err != nil {
+		t.Fatalf("error deleting static route: %s", err)
+	}
+
+	// BGP service, with the version of the current configuration
+	_, err = edge.UpdateBgpConfiguration(&types.NsxtEdgeGatewayBgpConfig{Enabled: true, LocalASNumber: "65000",
+		GracefulRestart: &types.NsxtEdgeGatewayBgpGracefulRestart{Mode: "SOMETIMES"}})
+	if err == nil {
+		t.Errorf("expected error with invalid graceful restart mode")
+	}
+	bgpConfig, err := edge.UpdateBgpConfiguration(&types.NsxtEdgeGatewayBgpConfig{Enabled: true, LocalASNumber: "65000", Ecmp: true,
+		GracefulRestart: &types.NsxtEdgeGatewayBgpGracefulRestart{Mode: types.NsxtBgpGracefulRestartHelperOnly}})
+	if err != nil {
+		t.Fatalf("error updating BGP configuration: %s", err)
+	}
+	if bgpConfig.LocalASNumber != "65000" || bgpConfig.GracefulRestart.Mode != types.NsxtBgpGracefulRestartHelperOnly {
+		t.Errorf("unexpected BGP configuration: %#v", bgpConfig)
+	}
+	puts = server.RequestsTo(http.MethodPut, routingPath+"bgp")
+	sentBgp := types.NsxtEdgeGatewayBgpConfig{}
+	if len(puts) != 1 || json.Unmarshal([]byte(puts[0].Body), &sentBgp) != nil || sentBgp.Version == nil || sentBgp.Version.Version != 7 {
+		t.Errorf("expected the version of the current BGP configuration to be sent, got %#v", puts)
+	}
+
+	// BGP neighbor, whose advertised routes are filtered with a prefix list
+	_, err = edge.CreateBgpIpPrefixList(&types.NsxtEdgeGatewayBgpIpPrefixList{Name: "tenant-prefixes",
+		Prefixes: []types.NsxtEdgeGatewayBgpIpPrefix{{Network: "10.20.0.0/16", Action: "ALLOW"}}})
+	if err == nil {
+		t.Errorf("expected error creating prefix list with invalid action")
+	}
+	prefixList, err := edge.CreateBgpIpPrefixList(&types.NsxtEdgeGatewayBgpIpPrefixList{Name: "tenant-prefixes",
+		Prefixes: []types.NsxtEdgeGatewayBgpIpPrefix{{Network: "10.20.0.0/16", Action: types.NsxtBgpPrefixActionPermit}}})
+	if err != nil {
+		t.Fatalf("error creating BGP IP prefix list: %s", err)
+	}
+	_, err = edge.CreateBgpNeighbor(&types.NsxtEdgeGatewayBgpNeighbor{NeighborAddress: "10.0.0.1"})
+	if err == nil {
+		t.Errorf("expected error creating BGP neighbor without remote AS number")
+	}
+	neighbor, err := edge.CreateBgpNeighbor(&types.NsxtEdgeGatewayBgpNeighbor{
+		NeighborAddress:    "10.0.0.1",
+		RemoteASNumber:     "65001",
+		NeighborPassword:   "secret",
+		OutRoutesFilterRef: prefixList.Reference(),
+	})
+	if err != nil {
+		t.Fatalf("error creating BGP neighbor: %s", err)
+	}
+	if neighbor.NsxtEdgeGatewayBgpNeighbor.ID != "neighbor-1" || neighbor.NsxtEdgeGatewayBgpNeighbor.NeighborPassword != "" {
+		t.Errorf("unexpected BGP neighbor: %#v", neighbor.NsxtEdgeGatewayBgpNeighbor)
+	}
+	posts = server.RequestsTo(http.MethodPost, neighborsPath)
+	if len(posts) != 1 || !strings.Contains(posts[0].Body, `"neighborPassword": "secret"`) ||
+		!strings.Contains(posts[0].Body, `"id": "list-1"`) || posts[0].Header.Get("Accept") != "application/json;version=35.0" {
+		t.Errorf("unexpected BGP neighbor creation: %#v", posts)
+	}
+	_, err = edge.GetBgpNeighborByIp("10.0.0.2")
+	if !errors.Is(err, ErrorEntityNotFound) {
+		t.Errorf("expected entity not found error for BGP neighbor, got %v", err)
+	}
+}
@@ -0,0 +1,234 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// Checks the lifecycle of an NSX-T edge gateway against a fake vCD: the asynchronous creation
+// returns the edge gateway owning the task, and the owner is sent in the field of the API version
+func TestNsxtEdgeGateway_Lifecycle(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	const edgeId = "urn:vcloud:gateway:33333333-3333-3333-3333-333333333333"
+	const edgesPath = "/cloudapi/1.0.0/edgeGateways/"
+	edgeJson := `{"id":"` + edgeId + `","name":"edge1","description":"",
+		"orgVdc":{"name":"` + vcdtest.MockVdcName + `","id":"urn:vcloud:vdc:` + vcdtest.MockVdcId + `"},
+		"edgeGatewayUplinks":[{"uplinkId":"urn:vcloud:network:44444444-4444-4444-4444-444444444444","connected":true,"dedicated":true,
+		"subnets":{"values":[{"gateway":"10.0.0.1","prefixLength":24,"enabled":true,"primaryIp":"10.0.0.2",
+		"ipRanges":{"values":[{"startAddress":"10.0.0.2","endAddress":"10.0.0.10"}]}}]}}]}`
+	server.HandleXML(http.MethodGet, vcdtest.MockTaskPath, http.StatusOK,
+		`<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="success" href="{{server}}`+vcdtest.MockTaskPath+`">
+		  <Owner type="application/json" name="edge1" id="`+edgeId+`" href="{{server}}`+edgesPath+edgeId+`"/>
+		</Task>`)
+	taskResponse := vcdtest.Response{
+		Status: http.StatusAccepted,
+		Header: map[string]string{"Location": "{{server}}" + vcdtest.MockTaskPath},
+	}
+	server.Handle(http.MethodPost, edgesPath, taskResponse)
+	server.Handle(http.MethodPut, edgesPath+edgeId, taskResponse)
+	server.Handle(http.MethodDelete, edgesPath+edgeId, taskResponse)
+	server.HandleJSON(http.MethodGet, edgesPath+edgeId, http.StatusOK, edgeJson)
+
+	vcdClient := newMockClient(t, server)
+	adminOrg, err := GetAdminOrgByName(vcdClient, vcdtest.MockOrgName)
+	if err != nil {
+		t.Fatalf("error retrieving admin org: %s", err)
+	}
+
+	config := &types.NsxtEdgeGateway{
+		Name:     "edge1",
+		OwnerRef: &types.OpenApiReference{ID: "urn:vcloud:vdc:" + vcdtest.MockVdcId},
+		EdgeGatewayUplinks: []types.NsxtEdgeGatewayUplink{{
+			UplinkID:  "urn:vcloud:network:44444444-4444-4444-4444-444444444444",
+			Connected: true,
+			Dedicated: true,
+			Subnets: types.NsxtEdgeGatewaySubnets{Values: []types.NsxtEdgeGatewaySubnet{{
+				Gateway:      "10.0.0.1",
+				PrefixLength: 24,
+				Enabled:      true,
+				PrimaryIP:    "10.0.0.2",
+				IPRanges: &types.OpenApiIPRanges{Values: []types.OpenApiIPRange{
+					{StartAddress: "10.0.0.2", EndAddress: "10.0.0.10"},
+				}},
+			}}},
+		}},
+	}
+
+	// Before API 35.0, VDC groups cannot own edge gateways
+	_, err = adminOrg.CreateNsxtEdgeGateway(&types.NsxtEdgeGateway{
+		Name:               "edge1",
+		OwnerRef:           &types.OpenApiReference{ID: "urn:vcloud:vdcGroup:55555555-5555-5555-5555-555555555555"},
+		EdgeGatewayUplinks: config.EdgeGatewayUplinks,
+	})
+	if err == nil || !strings.Contains(err.Error(), "35.0") {
+		t.Errorf("expected error creating edge gateway owned by VDC group, got %v", err)
+	}
+
+	edge, err := adminOrg.CreateNsxtEdgeGateway(config)
+	if err != nil {
+		t.Fatalf("error creating edge gateway: %s", err)
+	}
+	if edge.NsxtEdgeGateway.ID != edgeId || edge.NsxtEdgeGateway.EdgeGatewayUplinks[0].Subnets.Values[0].PrimaryIP != "10.0.0.2" {
+		t.Errorf("unexpected edge gateway: %#v", edge.NsxtEdgeGateway)
+	}
+
+	posts := server.RequestsTo(http.MethodPost, edgesPath)
+	if len(posts) != 1 {
+		t.Fatalf("expected one POST request, got %d", len(posts))
+	}
+	if posts[0].Header.Get("Accept") != "application/json;version=34.0" ||
+		posts[0].Header.Get("X-VMWARE-VCLOUD-TENANT-CONTEXT") != vcdtest.MockOrgId {
+		t.Errorf("unexpected headers: %v", posts[0].Header)
+	}
+	sent := types.NsxtEdgeGateway{}
+	err = json.Unmarshal([]byte(posts[0].Body), &sent)
+	if err != nil {
+		t.Fatalf("error decoding payload: %s", err)
+	}
+	if sent.OwnerRef != nil || sent.OrgVdc == nil || sent.OrgVdc.ID != config.OwnerRef.ID {
+		t.Errorf("expected owner in orgVdc before API 35.0, got payload %s", posts[0].Body)
+	}
+	if config.OrgVdc != nil {
+		t.Errorf("the configuration given to CreateNsxtEdgeGateway was modified")
+	}
+
+	// With API 35.0+, the owner is sent in ownerRef
+	edge.client.APIVersion = "35.0"
+	edge.NsxtEdgeGateway.Description = "updated"
+	err = edge.Update()
+	if err != nil {
+		t.Fatalf("error updating edge gateway: %s", err)
+	}
+	puts := server.RequestsTo(http.MethodPut, edgesPath+edgeId)
+	if len(puts) != 1 || puts[0].Header.Get("Accept") != "application/json;version=35.0" ||
+		!strings.Contains(puts[0].Body, `"ownerRef"`) || strings.Contains(puts[0].Body, `"orgVdc"`) ||
+		!strings.Contains(puts[0].Body, `"description": "updated"`) {
+		t.Errorf("unexpected PUT requests: %#v", puts)
+	}
+
+	err = edge.Delete()
+	if err != nil {
+		t.Fatalf("error deleting edge gateway: %s", err)
+	}
+	if len(server.RequestsTo(http.MethodDelete, edgesPath+edgeId)) != 1 {
+		t.Errorf("expected one DELETE request")
+	}
+	if len(server.RequestsTo(http.MethodGet, vcdtest.MockTaskPath)) != 3 {
+		t.Errorf("expected the tasks of the 3 operations to be waited for")
+	}
+
+	_, err = adminOrg.CreateNsxtEdgeGateway(&types.NsxtEdgeGateway{Name: "edge2", OwnerRef: config.OwnerRef})
+	if err == nil {
+		t.Errorf("expected error creating edge gateway without uplinks")
+	}
+}
+
+// Checks the configuration of the DHCP forwarder of an NSX-T edge gateway against a fake vCD
+func TestNsxtEdgeGateway_DhcpForwarder(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	const edgeId = "urn:vcloud:gateway:33333333-3333-3333-3333-333333333333"
+	const forwarderPath = "/cloudapi/1.0.0/edgeGateways/" + edgeId + "/dhcpForwarder"
+	server.HandleJSON(http.MethodGet, forwarderPath, http.StatusOK, `{"enabled":false,"dhcpServers":[],"version":{"version":3}}`)
+	server.HandleJSON(http.MethodPut, forwarderPath, http.StatusOK,
+		`{"enabled":true,"dhcpServers":["10.0.0.53","10.0.1.53"],"version":{"version":4}}`)
+
+	vcdClient := newMockClient(t, server)
+	edge := &NsxtEdgeGateway{
+		NsxtEdgeGateway: &types.NsxtEdgeGateway{ID: edgeId, Name: "edge1"},
+		client:          &vcdClient.Client,
+	}
+
+	invalid := []*types.NsxtEdgeGatewayDhcpForwarder{nil, {Enabled: true}, {Enabled: true, DhcpServers: []string{"dhcp.example.com"}}}
+	for _, forwarderConfig := range invalid {
+		if _, err := edge.UpdateDhcpForwarder(forwarderConfig); err == nil {
+			t.Errorf("expected error with DHCP forwarder %#v", forwarderConfig)
+		}
+	}
+
+	forwarder, err := edge.UpdateDhcpForwarder(&types.NsxtEdgeGatewayDhcpForwarder{
+		Enabled:     true,
+		DhcpServers: []string{"10.0.0.53", "10.0.1.53"},
+	})
+	if err != nil {
+		t.Fatalf("error updating DHCP forwarder: %s", err)
+	}
+	if !forwarder.Enabled || len(forwarder.DhcpServers) != 2 || forwarder.Version.Version != 4 {
+		t.Errorf("unexpected DHCP forwarder: %#v", forwarder)
+	}
+	puts := server.RequestsTo(http.MethodPut, forwarderPath)
+	sent := types.NsxtEdgeGatewayDhcpForwarder{}
+	if len(puts) != 1 || json.Unmarshal([]byte(puts[0].Body), &sent) != nil || sent.Version == nil || sent.Version.Version != 3 {
+		t.Errorf("expected the version of the current DHCP forwarder to be sent, got %#v", puts)
+	}
+	if puts[0].Header.Get("Accept") != "application/json;version=36.1" {
+		t.Errorf("unexpected headers: %v", puts[0].Header)
+	}
+}
+
+// Checks the update of the SLAAC profile of an NSX-T edge gateway against a fake vCD
+func TestNsxtEdgeGateway_SlaacProfile(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	const edgeId = "urn:vcloud:gateway:33333333-3333-3333-3333-333333333333"
+	const profilePath = "/cloudapi/1.0.0/edgeGateways/" + edgeId + "/slaacProfile"
+	server.HandleJSON(http.MethodGet, profilePath, http.StatusOK, `{"enabled":false,"mode":"DISABLED"}`)
+	server.HandleJSON(http.MethodPut, profilePath, http.StatusOK,
+		`{"enabled":true,"mode":"SLAAC","dnsConfig":{"dnsServerIpv6Addresses":["2001:db8::53"],"domainNames":["example.com"]}}`)
+
+	vcdClient := newMockClient(t, server)
+	edge := &NsxtEdgeGateway{
+		NsxtEdgeGateway: &types.NsxtEdgeGateway{ID: edgeId, Name: "edge1"},
+		client:          &vcdClient.Client,
+	}
+
+	profile, err := edge.GetSlaacProfile()
+	if err != nil {
+		t.Fatalf("error retrieving SLAAC profile: %s", err)
+	}
+	if profile.Enabled || profile.Mode != types.NsxtSlaacModeDisabled {
+		t.Errorf("unexpected SLAAC profile: %#v", profile)
+	}
+
+	invalid := []*types.NsxtEdgeGatewaySlaacProfile{nil, {Enabled: true, Mode: "STATEFUL"},
+		{Enabled: true, Mode: types.NsxtSlaacModeSlaac, DNSConfig: &types.NsxtEdgeGatewaySlaacProfileDNSConfig{
+			DNSServerIpv6Addresses: []string{"10.0.0.53"}}}}
+	for _, profileConfig := range invalid {
+		if _, err = edge.UpdateSlaacProfile(profileConfig); err == nil {
+			t.Errorf("expected error with SLAAC profile %#v", profileConfig)
+		}
+	}
+
+	profile, err = edge.UpdateSlaacProfile(&types.NsxtEdgeGatewaySlaacProfile{
+		Enabled: true,
+		Mode:    types.NsxtSlaacModeSlaac,
+		DNSConfig: &types.NsxtEdgeGatewaySlaacProfileDNSConfig{
+			DNSServerIpv6Addresses: []string{"2001:db8::53"},
+			DomainNames:            []string{"example.com"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("error updating SLAAC profile: %s", err)
+	}
+	if !profile.Enabled || profile.DNSConfig == nil || profile.DNSConfig.DomainNames[0] != "example.com" {
+		t.Errorf("unexpected SLAAC profile: %#v", profile)
+	}
+	puts := server.RequestsTo(http.MethodPut, profilePath)
+	if len(puts) != 1 || !strings.Contains(puts[0].Body, `"mode": "SLAAC"`) ||
+		puts[0].Header.Get("Accept") != "application/json;version=35.0" {
+		t.Errorf("unexpected SLAAC profile updates: %#v", puts)
+	}
+}
@@ -0,0 +1,199 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/util"
+)
+
+// Firewall of the NSX-T edge gateways (API 35.2+, vCD 10.2.2+). The user defined rules are an
+// ordered list, which vCD replaces as a whole. ExportPolicy and ApplyPolicy manage it declaratively,
+// as EdgeGateway.ExportPolicy and EdgeGateway.ApplyPolicy do for the NSX-V edge gateways.
+
+// NsxtFirewallPolicy is a declarative view of the firewall of an NSX-T edge gateway: the ordered
+// list of its user defined rules. It is what NsxtEdgeGateway.ExportPolicy returns and what
+// NsxtEdgeGateway.ApplyPolicy expects.
+type NsxtFirewallPolicy struct {
+	Rules []*types.NsxtFirewallRule
+}
+
+// NsxtFirewallPolicyDiff describes the changes needed to turn the current firewall policy of an
+// NSX-T edge gateway into the desired one
+type NsxtFirewallPolicyDiff struct {
+	Added     []*types.NsxtFirewallRule // rules present only in the desired policy
+	Removed   []*types.NsxtFirewallRule // rules present only in the current policy
+	Reordered bool                      // rules kept from the current policy change their relative order
+}
+
+// HasChanges returns true if applying the policy would modify the edge gateway
+func (diff NsxtFirewallPolicyDiff) HasChanges() bool {
+	return len(diff.Added) > 0 || len(diff.Removed) > 0 || diff.Reordered
+}
+
+// GetFirewallRules retrieves the firewall rules of the edge gateway: system, user defined and
+// default rules
+func (egw *NsxtEdgeGateway) GetFirewallRules() (*types.NsxtFirewallRules, error) {
+	urlRef, apiVersion, err := egw.buildEndpoint(types.OpenApiEndpointEdgeGatewayFirewallRules)
+	if err != nil {
+		return nil, err
+	}
+	rules := &types.NsxtFirewallRules{}
+	err = egw.client.OpenApiGetItem(apiVersion, urlRef, nil, rules)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving firewall rules of NSX-T edge gateway: %s", err)
+	}
+	return rules, nil
+}
+
+// UpdateFirewallRules replaces the user defined firewall rules of the edge gateway with the given
+// ordered list. Rules with the ID of an existing rule update it, the others are created; existing
+// rules missing from the list are removed. An empty list removes all the user defined rules.
+func (egw *NsxtEdgeGateway) UpdateFirewallRules(rules []*types.NsxtFirewallRule) (*types.NsxtFirewallRules, error) {
+	for _, rule := range rules {
+		err := validateNsxtFirewallRule(rule)
+		if err != nil {
+			return nil, err
+		}
+	}
+	urlRef, apiVersion, err := egw.buildEndpoint(types.OpenApiEndpointEdgeGatewayFirewallRules)
+	if err != nil {
+		return nil, err
+	}
+	payload := &types.NsxtFirewallRules{UserDefinedRules: rules}
+	if payload.UserDefinedRules == nil {
+		payload.UserDefinedRules = []*types.NsxtFirewallRule{}
+	}
+	updated := &types.NsxtFirewallRules{}
+	err = egw.client.OpenApiPutItem(apiVersion, urlRef, nil, payload, updated)
+	if err != nil {
+		return nil, fmt.Errorf("error updating firewall rules of NSX-T edge gateway: %s", err)
+	}
+	return updated, nil
+}
+
+// ExportPolicy returns the user defined firewall rules of the edge gateway as an
+// NsxtFirewallPolicy. Rule IDs and versions are removed, as they are assigned by vCD and are not
+// part of a desired state.
+func (egw *NsxtEdgeGateway) ExportPolicy() (*NsxtFirewallPolicy, error) {
+	rules, err := egw.GetFirewallRules()
+	if err != nil {
+		return nil, err
+	}
+	policy := &NsxtFirewallPolicy{}
+	for _, rule := range rules.UserDefinedRules {
+		policy.Rules = append(policy.Rules, nsxtFirewallRuleWithoutId(rule))
+	}
+	return policy, nil
+}
+
+// ApplyPolicy compares the user defined firewall rules of the edge gateway with the desired policy
+// and, only if they differ, replaces them in a single update. Rules that are unchanged keep their
+// vCD identifiers. The computed difference is returned in any case, so that callers can report what
+// was (or would have been) changed.
+func (egw *NsxtEdgeGateway) ApplyPolicy(desired *NsxtFirewallPolicy) (NsxtFirewallPolicyDiff, error) {
+	if desired == nil {
+		return NsxtFirewallPolicyDiff{}, fmt.Errorf("desired firewall policy must not be nil")
+	}
+	current, err := egw.GetFirewallRules()
+	if err != nil {
+		return NsxtFirewallPolicyDiff{}, err
+	}
+
+	diff, rules := diffNsxtFirewallPolicy(current.UserDefinedRules, desired.Rules)
+	if !diff.HasChanges() {
+		util.Logger.Printf("[TRACE] firewall policy of NSX-T edge gateway %s is up to date", egw.NsxtEdgeGateway.Name)
+		return diff, nil
+	}
+	_, err = egw.UpdateFirewallRules(rules)
+	if err != nil {
+		return diff, fmt.Errorf("error applying firewall policy: %s", err)
+	}
+	return diff, nil
+}
+
+// diffNsxtFirewallPolicy compares the current and the desired rules, regardless of their ID. Besides
+// the difference, it returns the rule list to be sent to vCD, where rules matching an existing one
+// re-use it (and its ID).
+func diffNsxtFirewallPolicy(current, desired []*types.NsxtFirewallRule) (NsxtFirewallPolicyDiff, []*types.NsxtFirewallRule) {
+	var diff NsxtFirewallPolicyDiff
+	matches, reordered := matchOrderedRules(len(current), len(desired), func(currentIndex, desiredIndex int) bool {
+		return nsxtFirewallRulesEqual(current[currentIndex], desired[desiredIndex])
+	})
+	diff.Reordered = reordered
+	used := make([]bool, len(current))
+	rules := []*types.NsxtFirewallRule{}
+	for desiredIndex, found := range matches {
+		if found < 0 {
+			diff.Added = append(diff.Added, desired[desiredIndex])
+			rules = append(rules, nsxtFirewallRuleWithoutId(desired[desiredIndex]))
+			continue
+		}
+		used[found] = true
+		rules = append(rules, current[found])
+	}
+	for i, existing := range current {
+		if !used[i] {
+			diff.Removed = append(diff.Removed, existing)
+		}
+	}
+	return diff, rules
+}
+
+// nsxtFirewallRulesEqual compares two rules ignoring their ID, their version and the names of the
+// referenced entities
+func nsxtFirewallRulesEqual(first, second *types.NsxtFirewallRule) bool {
+	return reflect.DeepEqual(nsxtFirewallRuleWithoutId(first), nsxtFirewallRuleWithoutId(second))
+}
+
+// nsxtFirewallRuleWithoutId returns a copy of the rule without ID nor version, whose references to
+// firewall groups and application port profiles only keep the ID
+func nsxtFirewallRuleWithoutId(rule *types.NsxtFirewallRule) *types.NsxtFirewallRule {
+	ruleCopy := *rule
+	ruleCopy.ID = ""
+	ruleCopy.Version = nil
+	ruleCopy.SourceFirewallGroups = referenceIds(rule.SourceFirewallGroups)
+	ruleCopy.DestinationFirewallGroups = referenceIds(rule.DestinationFirewallGroups)
+	ruleCopy.ApplicationPortProfiles = referenceIds(rule.ApplicationPortProfiles)
+	return &ruleCopy
+}
+
+// referenceIds returns a copy of the references without their names, or nil for an empty list
+func referenceIds(references []types.OpenApiReference) []types.OpenApiReference {
+	if len(references) == 0 {
+		return nil
+	}
+	ids := make([]types.OpenApiReference, len(references))
+	for i, reference := range references {
+		ids[i] = types.OpenApiReference{ID: reference.ID}
+	}
+	return ids
+}
+
+// validateNsxtFirewallRule checks the fields needed to create or update a firewall rule
+func validateNsxtFirewallRule(rule *types.NsxtFirewallRule) error {
+	if rule == nil || rule.Name == "" {
+		return fmt.Errorf("firewall rule name is required")
+	}
+	switch rule.ActionValue {
+	case types.NsxtFirewallRuleActionAllow, types.NsxtFirewallRuleActionDrop, types.NsxtFirewallRuleActionReject:
+	default:
+		return fmt.Errorf("invalid action '%s' for firewall rule %s", rule.ActionValue, rule.Name)
+	}
+	switch rule.IpProtocol {
+	case "", types.NsxtFirewallRuleIpv4, types.NsxtFirewallRuleIpv6, types.NsxtFirewallRuleIpv4AndIpv6:
+	default:
+		return fmt.Errorf("invalid IP protocol '%s' for firewall rule %s", rule.IpProtocol, rule.Name)
+	}
+	switch rule.Direction {
+	case "", types.NsxtFirewallRuleDirectionIn, types.NsxtFirewallRuleDirectionOut, types.NsxtFirewallRuleDirectionInOut:
+	default:
+		return fmt.Errorf("invalid direction '%s' for firewall rule %s", rule.Direction, rule.Name)
+	}
+	return nil
+}
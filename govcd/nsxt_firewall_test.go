@@ -0,0 +1,123 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// Checks the export of the firewall policy of an NSX-T edge gateway, and that applying a policy
+// only updates the rules when they differ, keeping the IDs of the existing rules
+func TestNsxtEdgeGateway_ApplyPolicy(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	const edgeId = "urn:vcloud:gateway:33333333-3333-3333-3333-333333333333"
+	const rulesPath = "/cloudapi/1.0.0/edgeGateways/" + edgeId + "/firewall/rules"
+	server.HandleJSON(http.MethodGet, rulesPath, http.StatusOK, `{
+"systemRules":[{"id":"system-1","name":"system","actionValue":"ALLOW","enabled":true}],
+"defaultRules":[{"id":"default-1","name":"default","actionValue":"DROP","enabled":true}],
+"userDefinedRules":[
+ {"id":"rule-1","name":"web","actionValue":"ALLOW","enabled":true,"direction":"IN","ipProtocol":"IPV4",
+  "destinationFirewallGroups":[{"id":"group-1","name":"web-servers"}],"version":{"version":3}},
+ {"id":"rule-2","name":"ssh","actionValue":"ALLOW","enabled":true,"direction":"IN","ipProtocol":"IPV4"},
+ {"id":"rule-3","name":"legacy","actionValue":"REJECT","enabled":false,"direction":"IN_OUT","ipProtocol":"IPV4_IPV6"}]}`)
+	server.Handle(http.MethodPut, rulesPath, vcdtest.Response{
+		Status: http.StatusAccepted,
+		Header: map[string]string{"Location": "{{server}}" + vcdtest.MockTaskPath},
+	})
+
+	vcdClient := newMockClient(t, server)
+	edge := &NsxtEdgeGateway{
+		NsxtEdgeGateway: &types.NsxtEdgeGateway{ID: edgeId, Name: "edge1"},
+		client:          &vcdClient.Client,
+	}
+
+	policy, err := edge.ExportPolicy()
+	if err != nil {
+		t.Fatalf("error exporting firewall policy: %s", err)
+	}
+	if len(policy.Rules) != 3 || policy.Rules[0].Name != "web" || policy.Rules[0].ID != "" || policy.Rules[0].Version != nil {
+		t.Fatalf("unexpected exported rules: %+v", policy.Rules)
+	}
+
+	// The exported policy is the current state
+	diff, err := edge.ApplyPolicy(policy)
+	if err != nil {
+		t.Fatalf("error applying the exported policy: %s", err)
+	}
+	if diff.HasChanges() || len(server.RequestsTo(http.MethodPut, rulesPath)) != 0 {
+		t.Errorf("unexpected changes applying the exported policy: %+v", diff)
+	}
+
+	// Names of the referenced groups are not part of the comparison
+	policy.Rules[0].DestinationFirewallGroups[0].Name = ""
+	// A rule removed, a rule added first and a rule changed
+	dns := &types.NsxtFirewallRule{Name: "dns", ActionValue: types.NsxtFirewallRuleActionAllow, Enabled: true,
+		Direction: types.NsxtFirewallRuleDirectionOut}
+	ssh := *policy.Rules[1]
+	ssh.Logging = true
+	desired := &NsxtFirewallPolicy{Rules: []*types.NsxtFirewallRule{dns, policy.Rules[0], &ssh}}
+	diff, err = edge.ApplyPolicy(desired)
+	if err != nil {
+		t.Fatalf("error applying policy: %s", err)
+	}
+	if len(diff.Added) != 2 || len(diff.Removed) != 2 || diff.Reordered ||
+		diff.Removed[0].ID != "rule-2" || diff.Removed[1].ID != "rule-3" {
+		t.Errorf("unexpected difference: %+v", diff)
+	}
+	puts := server.RequestsTo(http.MethodPut, rulesPath)
+	if len(puts) != 1 {
+		t.Fatalf("expected one update of the rules, got %d", len(puts))
+	}
+	var sent types.NsxtFirewallRules
+	err = json.Unmarshal([]byte(puts[0].Body), &sent)
+	if err != nil {
+		t.Fatalf("error decoding the rules sent: %s", err)
+	}
+	if len(sent.UserDefinedRules) != 3 || sent.SystemRules != nil || sent.DefaultRules != nil ||
+		sent.UserDefinedRules[0].ID != "" || sent.UserDefinedRules[0].Name != "dns" ||
+		sent.UserDefinedRules[1].ID != "rule-1" || sent.UserDefinedRules[1].Version == nil ||
+		sent.UserDefinedRules[2].ID != "" || !sent.UserDefinedRules[2].Logging {
+		t.Errorf("unexpected rules sent: %s", puts[0].Body)
+	}
+
+	// Emptying the policy removes all the user defined rules
+	server.ClearRequests()
+	diff, err = edge.ApplyPolicy(&NsxtFirewallPolicy{})
+	if err != nil {
+		t.Fatalf("error applying empty policy: %s", err)
+	}
+	puts = server.RequestsTo(http.MethodPut, rulesPath)
+	if len(diff.Removed) != 3 || len(puts) != 1 || puts[0].Body != "{\n  \"userDefinedRules\": []\n}" {
+		t.Errorf("unexpected removal of all the rules: %+v %#v", diff, puts)
+	}
+
+	invalid := &NsxtFirewallPolicy{Rules: []*types.NsxtFirewallRule{{Name: "bad", ActionValue: "PERMIT"}}}
+	if _, err = edge.ApplyPolicy(invalid); err == nil {
+		t.Errorf("expected error applying a rule with an invalid action")
+	}
+	if _, err = edge.ApplyPolicy(nil); err == nil {
+		t.Errorf("expected error applying a nil policy")
+	}
+}
+
+func TestDiffNsxtFirewallPolicy_Reordered(t *testing.T) {
+	first := &types.NsxtFirewallRule{ID: "rule-1", Name: "first", ActionValue: types.NsxtFirewallRuleActionAllow}
+	second := &types.NsxtFirewallRule{ID: "rule-2", Name: "second", ActionValue: types.NsxtFirewallRuleActionDrop}
+	diff, rules := diffNsxtFirewallPolicy([]*types.NsxtFirewallRule{first, second},
+		[]*types.NsxtFirewallRule{nsxtFirewallRuleWithoutId(second), nsxtFirewallRuleWithoutId(first)})
+	if !diff.Reordered || len(diff.Added) != 0 || len(diff.Removed) != 0 {
+		t.Errorf("unexpected difference: %+v", diff)
+	}
+	if len(rules) != 2 || rules[0].ID != "rule-2" || rules[1].ID != "rule-1" {
+		t.Errorf("unexpected rules: %+v", rules)
+	}
+}
@@ -0,0 +1,262 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// NsxtFirewallGroup is an NSX-T IP set or security group, owned by an NSX-T edge gateway or a VDC
+// group, which can be used as source or destination of firewall and NAT rules. Firewall groups are
+// managed through OpenAPI and need API 34.0+ (vCD 10.1+).
+type NsxtFirewallGroup struct {
+	NsxtFirewallGroup *types.NsxtFirewallGroup
+	client            *Client
+}
+
+// GetAllNsxtFirewallGroups retrieves the firewall groups of the org, whatever their owner. Query
+// parameters can be supplied to perform additional filtering (e.g. "filter" => "type==IP_SET")
+func (adminOrg *AdminOrg) GetAllNsxtFirewallGroups(queryParameters url.Values) ([]*NsxtFirewallGroup, error) {
+	client, err := adminOrg.tenantClient()
+	if err != nil {
+		return nil, err
+	}
+	return getAllNsxtFirewallGroups(client, queryParameters)
+}
+
+// GetNsxtFirewallGroupById retrieves the firewall group of the org with the given ID
+func (adminOrg *AdminOrg) GetNsxtFirewallGroupById(id string) (*NsxtFirewallGroup, error) {
+	client, err := adminOrg.tenantClient()
+	if err != nil {
+		return nil, err
+	}
+	return getNsxtFirewallGroupById(client, id)
+}
+
+// CreateNsxtFirewallGroup creates a firewall group in the org. OwnerRef is required, and refers to
+// an NSX-T edge gateway or, with API 35.0+, a VDC group.
+func (adminOrg *AdminOrg) CreateNsxtFirewallGroup(firewallGroupConfig *types.NsxtFirewallGroup) (*NsxtFirewallGroup, error) {
+	client, err := adminOrg.tenantClient()
+	if err != nil {
+		return nil, err
+	}
+	return createNsxtFirewallGroup(client, firewallGroupConfig)
+}
+
+// GetAllNsxtFirewallGroups retrieves the firewall groups owned by the edge gateway. Query
+// parameters can be supplied to perform additional filtering.
+func (egw *NsxtEdgeGateway) GetAllNsxtFirewallGroups(queryParameters url.Values) ([]*NsxtFirewallGroup, error) {
+	return getAllNsxtFirewallGroups(egw.client,
+		queryParameterFilterAnd(fiqlEq("ownerRef.id", egw.NsxtEdgeGateway.ID), queryParameters))
+}
+
+// GetNsxtFirewallGroupByName retrieves the firewall group of the edge gateway with the given name
+// and type (types.FirewallGroupTypeIpSet or types.FirewallGroupTypeSecurityGroup)
+func (egw *NsxtEdgeGateway) GetNsxtFirewallGroupByName(name, groupType string) (*NsxtFirewallGroup, error) {
+	queryParams := url.Values{}
+	queryParams.Set("filter", fiqlEq("name", name)+";"+fiqlEq("type", groupType))
+	groups, err := egw.GetAllNsxtFirewallGroups(queryParams)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("firewall group '%s' of type %s not found in edge gateway %s",
+			name, groupType, egw.NsxtEdgeGateway.Name)
+	}
+	if len(groups) > 1 {
+		return nil, fmt.Errorf("more than one firewall group found with name '%s'", name)
+	}
+	return getNsxtFirewallGroupById(egw.client, groups[0].NsxtFirewallGroup.ID)
+}
+
+// CreateNsxtFirewallGroup creates a firewall group owned by the edge gateway
+func (egw *NsxtEdgeGateway) CreateNsxtFirewallGroup(firewallGroupConfig *types.NsxtFirewallGroup) (*NsxtFirewallGroup, error) {
+	if firewallGroupConfig == nil {
+		return nil, fmt.Errorf("firewall group name is required")
+	}
+	payload := *firewallGroupConfig
+	payload.OwnerRef = &types.OpenApiReference{ID: egw.NsxtEdgeGateway.ID}
+	return createNsxtFirewallGroup(egw.client, &payload)
+}
+
+// IsIpSet returns true if the firewall group is an IP set
+func (group *NsxtFirewallGroup) IsIpSet() bool {
+	return group.NsxtFirewallGroup.Type == types.FirewallGroupTypeIpSet
+}
+
+// IsSecurityGroup returns true if the firewall group is a security group
+func (group *NsxtFirewallGroup) IsSecurityGroup() bool {
+	return group.NsxtFirewallGroup.Type == types.FirewallGroupTypeSecurityGroup
+}
+
+// Reference returns the reference to the firewall group, as used in the sources and destinations
+// of firewall and NAT rules
+func (group *NsxtFirewallGroup) Reference() types.OpenApiReference {
+	return types.OpenApiReference{ID: group.NsxtFirewallGroup.ID, Name: group.NsxtFirewallGroup.Name}
+}
+
+// Refresh retrieves the current definition of the firewall group
+func (group *NsxtFirewallGroup) Refresh() error {
+	refreshed, err := getNsxtFirewallGroupById(group.client, group.NsxtFirewallGroup.ID)
+	if err != nil {
+		return err
+	}
+	group.NsxtFirewallGroup = refreshed.NsxtFirewallGroup
+	return nil
+}
+
+// Update sends the current definition of the firewall group (name, description, IP addresses or
+// members) to vCD
+func (group *NsxtFirewallGroup) Update() error {
+	if group.NsxtFirewallGroup.ID == "" {
+		return fmt.Errorf("cannot update firewall group without ID")
+	}
+	err := validateNsxtFirewallGroup(group.client, group.NsxtFirewallGroup)
+	if err != nil {
+		return err
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointFirewallGroups
+	apiVersion, err := group.client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return err
+	}
+	urlRef, err := group.client.OpenApiBuildEndpoint(endpoint, group.NsxtFirewallGroup.ID)
+	if err != nil {
+		return err
+	}
+
+	updated := &types.NsxtFirewallGroup{}
+	err = group.client.OpenApiPutItem(apiVersion, urlRef, nil, group.NsxtFirewallGroup, updated)
+	if err != nil {
+		return fmt.Errorf("error updating firewall group: %s", err)
+	}
+	group.NsxtFirewallGroup = updated
+	return nil
+}
+
+// Delete removes the firewall group. It fails if the group is still used by firewall or NAT rules.
+func (group *NsxtFirewallGroup) Delete() error {
+	if group.NsxtFirewallGroup.ID == "" {
+		return fmt.Errorf("cannot delete firewall group without ID")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointFirewallGroups
+	apiVersion, err := group.client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return err
+	}
+	urlRef, err := group.client.OpenApiBuildEndpoint(endpoint, group.NsxtFirewallGroup.ID)
+	if err != nil {
+		return err
+	}
+	err = group.client.OpenApiDeleteItem(apiVersion, urlRef, nil)
+	if err != nil {
+		return fmt.Errorf("error deleting firewall group: %s", err)
+	}
+	return nil
+}
+
+// getAllNsxtFirewallGroups retrieves the firewall groups visible to client. The list comes from the
+// summaries endpoint, whose items have no IP addresses nor members: retrieve a group by ID to get them.
+func getAllNsxtFirewallGroups(client *Client, queryParameters url.Values) ([]*NsxtFirewallGroup, error) {
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointFirewallGroups
+	apiVersion, err := client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint, "summaries")
+	if err != nil {
+		return nil, err
+	}
+
+	var typeResponses []*types.NsxtFirewallGroup
+	err = client.OpenApiGetAllItems(apiVersion, urlRef, queryParameters, &typeResponses)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]*NsxtFirewallGroup, len(typeResponses))
+	for index, typeResponse := range typeResponses {
+		groups[index] = &NsxtFirewallGroup{NsxtFirewallGroup: typeResponse, client: client}
+	}
+	return groups, nil
+}
+
+// getNsxtFirewallGroupById retrieves the firewall group with the given ID
+func getNsxtFirewallGroupById(client *Client, id string) (*NsxtFirewallGroup, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty firewall group ID")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointFirewallGroups
+	apiVersion, err := client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint, id)
+	if err != nil {
+		return nil, err
+	}
+
+	group := &NsxtFirewallGroup{NsxtFirewallGroup: &types.NsxtFirewallGroup{}, client: client}
+	err = client.OpenApiGetItem(apiVersion, urlRef, nil, group.NsxtFirewallGroup)
+	if err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// createNsxtFirewallGroup creates a firewall group with the owner given in its definition
+func createNsxtFirewallGroup(client *Client, firewallGroupConfig *types.NsxtFirewallGroup) (*NsxtFirewallGroup, error) {
+	err := validateNsxtFirewallGroup(client, firewallGroupConfig)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointFirewallGroups
+	apiVersion, err := client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	group := &NsxtFirewallGroup{NsxtFirewallGroup: &types.NsxtFirewallGroup{}, client: client}
+	err = client.OpenApiPostItem(apiVersion, urlRef, nil, firewallGroupConfig, group.NsxtFirewallGroup)
+	if err != nil {
+		return nil, fmt.Errorf("error creating firewall group: %s", err)
+	}
+	return group, nil
+}
+
+// validateNsxtFirewallGroup checks the fields needed by each type of firewall group
+func validateNsxtFirewallGroup(client *Client, firewallGroupConfig *types.NsxtFirewallGroup) error {
+	if firewallGroupConfig == nil || firewallGroupConfig.Name == "" {
+		return fmt.Errorf("firewall group name is required")
+	}
+	if firewallGroupConfig.OwnerRef == nil || firewallGroupConfig.OwnerRef.ID == "" {
+		return fmt.Errorf("firewall group %s needs an owner (edge gateway or VDC group)", firewallGroupConfig.Name)
+	}
+	if !client.APIClientVersionIs(">= 35.0") && strings.HasPrefix(firewallGroupConfig.OwnerRef.ID, vdcGroupUrnPrefix) {
+		return fmt.Errorf("VDC groups can own firewall groups with API version 35.0 or newer, the client uses %s",
+			client.APIVersion)
+	}
+	switch firewallGroupConfig.Type {
+	case types.FirewallGroupTypeIpSet:
+		if len(firewallGroupConfig.Members) > 0 {
+			return fmt.Errorf("IP set %s cannot have members", firewallGroupConfig.Name)
+		}
+	case types.FirewallGroupTypeSecurityGroup:
+		if len(firewallGroupConfig.IpAddresses) > 0 {
+			return fmt.Errorf("security group %s cannot have IP addresses", firewallGroupConfig.Name)
+		}
+	default:
+		return fmt.Errorf("unsupported type '%s' for firewall group %s", firewallGroupConfig.Type, firewallGroupConfig.Name)
+	}
+	return nil
+}
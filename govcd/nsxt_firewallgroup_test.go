@@ -0,0 +1,103 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// Checks the creation and lookup of NSX-T firewall groups owned by an edge gateway, against a fake vCD
+func TestNsxtFirewallGroup_IpSet(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	const edgeId = "urn:vcloud:gateway:33333333-3333-3333-3333-333333333333"
+	const groupId = "urn:vcloud:firewallGroup:77777777-7777-7777-7777-777777777777"
+	const groupsPath = "/cloudapi/1.0.0/firewallGroups/"
+	groupJson := `{"id":"` + groupId + `","name":"ipset1","type":"IP_SET",
+		"ipAddresses":["10.0.0.1","10.0.1.0/24","10.0.2.1-10.0.2.10"],"ownerRef":{"id":"` + edgeId + `"}}`
+	server.HandleXML(http.MethodGet, vcdtest.MockTaskPath, http.StatusOK,
+		`<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="success" href="{{server}}`+vcdtest.MockTaskPath+`">
+		  <Owner type="application/json" name="ipset1" id="`+groupId+`" href="{{server}}`+groupsPath+groupId+`"/>
+		</Task>`)
+	server.Handle(http.MethodPost, groupsPath, vcdtest.Response{
+		Status: http.StatusAccepted,
+		Header: map[string]string{"Location": "{{server}}" + vcdtest.MockTaskPath},
+	})
+	server.HandleJSON(http.MethodGet, groupsPath+groupId, http.StatusOK, groupJson)
+	server.HandleJSON(http.MethodGet, groupsPath+"summaries", http.StatusOK,
+		`{"resultTotal":1,"pageCount":1,"page":1,"pageSize":128,"values":[{"id":"`+groupId+`","name":"ipset1","type":"IP_SET"}]}`)
+
+	vcdClient := newMockClient(t, server)
+	edge := &NsxtEdgeGateway{
+		NsxtEdgeGateway: &types.NsxtEdgeGateway{ID: edgeId, Name: "edge1"},
+		client:          &vcdClient.Client,
+	}
+
+	_, err := edge.CreateNsxtFirewallGroup(&types.NsxtFirewallGroup{Name: "ipset1", Type: types.FirewallGroupTypeIpSet,
+		Members: []types.OpenApiReference{{ID: "urn:vcloud:network:66666666-6666-6666-6666-666666666666"}}})
+	if err == nil {
+		t.Errorf("expected error creating IP set with members")
+	}
+	_, err = edge.CreateNsxtFirewallGroup(&types.NsxtFirewallGroup{Name: "ipset1", Type: "UNKNOWN"})
+	if err == nil {
+		t.Errorf("expected error creating firewall group of unknown type")
+	}
+
+	group, err := edge.CreateNsxtFirewallGroup(&types.NsxtFirewallGroup{
+		Name:        "ipset1",
+		Type:        types.FirewallGroupTypeIpSet,
+		IpAddresses: []string{"10.0.0.1", "10.0.1.0/24", "10.0.2.1-10.0.2.10"},
+	})
+	if err != nil {
+		t.Fatalf("error creating firewall group: %s", err)
+	}
+	if !group.IsIpSet() || group.IsSecurityGroup() || len(group.NsxtFirewallGroup.IpAddresses) != 3 {
+		t.Errorf("unexpected firewall group: %#v", group.NsxtFirewallGroup)
+	}
+	if reference := group.Reference(); reference.ID != groupId || reference.Name != "ipset1" {
+		t.Errorf("unexpected reference: %#v", reference)
+	}
+	posts := server.RequestsTo(http.MethodPost, groupsPath)
+	sent := types.NsxtFirewallGroup{}
+	if len(posts) != 1 || json.Unmarshal([]byte(posts[0].Body), &sent) != nil ||
+		sent.OwnerRef == nil || sent.OwnerRef.ID != edgeId {
+		t.Errorf("expected the edge gateway as owner of the firewall group, got %#v", posts)
+	}
+
+	byName, err := edge.GetNsxtFirewallGroupByName("ipset1", types.FirewallGroupTypeIpSet)
+	if err != nil {
+		t.Fatalf("error retrieving firewall group by name: %s", err)
+	}
+	// The summaries have no IP addresses: the group is retrieved again by ID
+	if len(byName.NsxtFirewallGroup.IpAddresses) != 3 {
+		t.Errorf("unexpected firewall group: %#v", byName.NsxtFirewallGroup)
+	}
+	gets := server.RequestsTo(http.MethodGet, groupsPath+"summaries")
+	query, _ := url.ParseQuery(gets[0].RawQuery)
+	if query.Get("filter") != "name==ipset1;type==IP_SET;ownerRef.id=="+edgeId {
+		t.Errorf("unexpected filter: %s", query.Get("filter"))
+	}
+
+	// Before API 35.0, VDC groups cannot own firewall groups
+	adminOrg, err := GetAdminOrgByName(vcdClient, vcdtest.MockOrgName)
+	if err != nil {
+		t.Fatalf("error retrieving admin org: %s", err)
+	}
+	_, err = adminOrg.CreateNsxtFirewallGroup(&types.NsxtFirewallGroup{
+		Name:     "sg1",
+		Type:     types.FirewallGroupTypeSecurityGroup,
+		OwnerRef: &types.OpenApiReference{ID: "urn:vcloud:vdcGroup:55555555-5555-5555-5555-555555555555"},
+	})
+	if err == nil {
+		t.Errorf("expected error creating firewall group owned by VDC group with API 31.0")
+	}
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// Network context profiles (API 35.0+, vCD 10.2+) match the traffic of layer 7 applications, by their
+// APP_ID, in the rules of the distributed firewall of the VDC groups. They are read-only: the SYSTEM
+// ones are predefined by NSX-T, the others are created in NSX-T by the provider.
+
+// GetAllNetworkContextProfiles retrieves the network context profiles visible to the client. Query
+// parameters can be supplied to perform additional filtering (e.g. "filter" => "scope==SYSTEM")
+func (client *Client) GetAllNetworkContextProfiles(queryParameters url.Values) ([]*types.NsxtNetworkContextProfile, error) {
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointNetworkContextProfiles
+	apiVersion, err := client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var profiles []*types.NsxtNetworkContextProfile
+	err = client.OpenApiGetAllItems(apiVersion, urlRef, queryParameters, &profiles)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving network context profiles: %s", err)
+	}
+	return profiles, nil
+}
+
+// GetAllNetworkContextProfiles retrieves the network context profiles usable in the distributed
+// firewall of the VDC group. Query parameters can be supplied to perform additional filtering.
+func (group *VdcGroup) GetAllNetworkContextProfiles(queryParameters url.Values) ([]*types.NsxtNetworkContextProfile, error) {
+	if group.VdcGroup.ID == "" {
+		return nil, fmt.Errorf("VDC group %s has no ID", group.VdcGroup.Name)
+	}
+	return group.client.GetAllNetworkContextProfiles(
+		queryParameterFilterAnd(fiqlEq("_context", group.VdcGroup.ID), queryParameters))
+}
+
+// GetNetworkContextProfileByName retrieves the network context profile of the VDC group with the
+// given name and scope (one of the types.NsxtNetworkContextProfileScope* constants)
+func (group *VdcGroup) GetNetworkContextProfileByName(name, scope string) (*types.NsxtNetworkContextProfile, error) {
+	if name == "" {
+		return nil, fmt.Errorf("network context profile name is required")
+	}
+	queryParams := url.Values{}
+	queryParams.Set("filter", fiqlEq("name", name)+";"+fiqlEq("scope", scope))
+	profiles, err := group.GetAllNetworkContextProfiles(queryParams)
+	if err != nil {
+		return nil, err
+	}
+	if len(profiles) == 0 {
+		return nil, wrapErrorf(ErrorEntityNotFound, "network context profile '%s' of scope %s not found: %s",
+			name, scope, ErrorEntityNotFound)
+	}
+	if len(profiles) > 1 {
+		return nil, fmt.Errorf("more than one network context profile found with name '%s'", name)
+	}
+	return profiles[0], nil
+}
+
+// GetNetworkContextProfilesByAppId retrieves the network context profiles of the VDC group which
+// match the application with the given APP_ID (e.g. "SSH" or "HTTP")
+func (group *VdcGroup) GetNetworkContextProfilesByAppId(appId string) ([]*types.NsxtNetworkContextProfile, error) {
+	if appId == "" {
+		return nil, fmt.Errorf("APP_ID is required")
+	}
+	profiles, err := group.GetAllNetworkContextProfiles(nil)
+	if err != nil {
+		return nil, err
+	}
+	var found []*types.NsxtNetworkContextProfile
+	for _, profile := range profiles {
+		if networkContextProfileHasAppId(profile, appId) {
+			found = append(found, profile)
+		}
+	}
+	return found, nil
+}
+
+// networkContextProfileHasAppId returns true if one of the APP_ID attributes of the profile has the
+// given value
+func networkContextProfileHasAppId(profile *types.NsxtNetworkContextProfile, appId string) bool {
+	for _, attribute := range profile.Attributes {
+		if attribute.Type != types.NsxtNetworkContextProfileAttributeAppId {
+			continue
+		}
+		for _, value := range attribute.Values {
+			if value == appId {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// Checks the lookup of the network context profiles of a VDC group, and their attachment to the
+// rules of its distributed firewall against a fake vCD
+func TestVdcGroup_NetworkContextProfiles(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	const groupId = "urn:vcloud:vdcGroup:44444444-4444-4444-4444-444444444444"
+	const profilesPath = "/cloudapi/1.0.0/networkContextProfiles/"
+	const policiesPath = "/cloudapi/1.0.0/vdcGroups/" + groupId + "/dfwPolicies"
+	const rulesPath = policiesPath + "/default-policy/rules"
+	sshProfile := `{"id":"urn:vcloud:networkContextProfile:ssh","name":"SSH","scope":"SYSTEM",
+		"attributes":[{"type":"APP_ID","values":["SSH"]}]}`
+	server.HandleJSON(http.MethodGet, profilesPath, http.StatusOK,
+		`{"resultTotal":3,"pageCount":1,"page":1,"pageSize":128,"values":[`+sshProfile+`,
+		{"id":"urn:vcloud:networkContextProfile:web","name":"WEB","scope":"PROVIDER",
+		"attributes":[{"type":"APP_ID","values":["HTTP","SSL"]}]},
+		{"id":"urn:vcloud:networkContextProfile:domains","name":"domains","scope":"TENANT",
+		"attributes":[{"type":"DOMAIN_NAME","values":["SSH"]}]}]}`)
+	server.HandleJSON(http.MethodGet, policiesPath, http.StatusOK, `{"enabled":false}`)
+	server.HandleJSON(http.MethodGet, rulesPath, http.StatusOK, `{"values":[
+		{"id":"rule-1","name":"admin","actionValue":"ALLOW","enabled":true,"direction":"IN",
+		 "networkContextProfiles":[{"id":"urn:vcloud:networkContextProfile:web","name":"WEB"}],"version":{"version":2}},
+		{"id":"rule-2","name":"other","actionValue":"DROP","enabled":true}]}`)
+	server.HandleJSON(http.MethodPut, rulesPath, http.StatusOK, `{"values":[]}`)
+
+	vcdClient := newMockClient(t, server)
+	group := &VdcGroup{VdcGroup: &types.VdcGroup{ID: groupId, Name: "group1"}, client: &vcdClient.Client}
+
+	byAppId, err := group.GetNetworkContextProfilesByAppId("SSH")
+	if err != nil {
+		t.Fatalf("error retrieving network context profiles by APP_ID: %s", err)
+	}
+	if len(byAppId) != 1 || byAppId[0].Name != "SSH" {
+		t.Errorf("unexpected network context profiles for APP_ID SSH: %+v", byAppId)
+	}
+	gets := server.RequestsTo(http.MethodGet, profilesPath)
+	query, _ := url.ParseQuery(gets[len(gets)-1].RawQuery)
+	if query.Get("filter") != "_context=="+groupId {
+		t.Errorf("unexpected filter: %s", query.Get("filter"))
+	}
+
+	server.HandleJSON(http.MethodGet, profilesPath, http.StatusOK,
+		`{"resultTotal":1,"pageCount":1,"page":1,"pageSize":128,"values":[`+sshProfile+`]}`)
+	ssh, err := group.GetNetworkContextProfileByName("SSH", types.NsxtNetworkContextProfileScopeSystem)
+	if err != nil {
+		t.Fatalf("error retrieving network context profile by name: %s", err)
+	}
+	gets = server.RequestsTo(http.MethodGet, profilesPath)
+	query, _ = url.ParseQuery(gets[len(gets)-1].RawQuery)
+	if ssh.ID != "urn:vcloud:networkContextProfile:ssh" || query.Get("filter") != "name==SSH;scope==SYSTEM;_context=="+groupId {
+		t.Errorf("unexpected profile %+v with filter %s", ssh, query.Get("filter"))
+	}
+
+	// The rules of the distributed firewall need it to be active
+	if _, err = group.AttachNetworkContextProfiles("rule-1", ssh); err == nil {
+		t.Errorf("expected error attaching a profile with the distributed firewall inactive")
+	}
+	server.HandleJSON(http.MethodGet, policiesPath, http.StatusOK,
+		`{"enabled":true,"defaultPolicy":{"id":"default-policy","name":"Default","version":{"version":1}}}`)
+
+	_, err = group.AttachNetworkContextProfiles("rule-3", ssh)
+	if !errors.Is(err, ErrorEntityNotFound) {
+		t.Errorf("expected entity not found error for a missing rule, got %v", err)
+	}
+	web := &types.NsxtNetworkContextProfile{ID: "urn:vcloud:networkContextProfile:web", Name: "WEB"}
+	_, err = group.AttachNetworkContextProfiles("rule-1", ssh, web)
+	if err != nil {
+		t.Fatalf("error attaching network context profiles: %s", err)
+	}
+	puts := server.RequestsTo(http.MethodPut, rulesPath)
+	if len(puts) != 1 {
+		t.Fatalf("expected one update of the rules, got %d", len(puts))
+	}
+	var sent types.DistributedFirewallRules
+	err = json.Unmarshal([]byte(puts[0].Body), &sent)
+	if err != nil {
+		t.Fatalf("error decoding the rules sent: %s", err)
+	}
+	if len(sent.Values) != 2 || len(sent.Values[0].NetworkContextProfiles) != 2 ||
+		sent.Values[0].NetworkContextProfiles[1].ID != ssh.ID || sent.Values[0].Version.Version != 2 ||
+		sent.Values[1].ID != "rule-2" || len(sent.Values[1].NetworkContextProfiles) != 0 {
+		t.Errorf("unexpected rules sent: %s", puts[0].Body)
+	}
+
+	invalid := []*types.DistributedFirewallRule{{Name: "bad", ActionValue: "PERMIT"}}
+	if _, err = group.UpdateDistributedFirewallRules(invalid); err == nil {
+		t.Errorf("expected error updating a rule with an invalid action")
+	}
+}
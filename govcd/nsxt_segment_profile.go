@@ -0,0 +1,282 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// Segment profiles (API 37.0+, vCD 10.4+) are defined in NSX-T, and set the IP and MAC discovery,
+// spoof guard, QoS and security of the NSX-T segments backing the org VDC networks. They are
+// applied to a network individually or through a segment profile template, which the provider
+// creates in vCD.
+
+// segmentProfileEndpoints holds the endpoint of each type of segment profile
+var segmentProfileEndpoints = map[string]string{
+	types.NsxtSegmentProfileIpDiscovery:  types.OpenApiEndpointSegmentIpDiscoveryProfiles,
+	types.NsxtSegmentProfileMacDiscovery: types.OpenApiEndpointSegmentMacDiscoveryProfiles,
+	types.NsxtSegmentProfileSpoofGuard:   types.OpenApiEndpointSegmentSpoofGuardProfiles,
+	types.NsxtSegmentProfileQos:          types.OpenApiEndpointSegmentQosProfiles,
+	types.NsxtSegmentProfileSecurity:     types.OpenApiEndpointSegmentSecurityProfiles,
+}
+
+// NsxtSegmentProfileTemplate is a segment profile template, which groups segment profiles of an
+// NSX-T manager. Templates are only managed by system administrators.
+type NsxtSegmentProfileTemplate struct {
+	NsxtSegmentProfileTemplate *types.NsxtSegmentProfileTemplate
+	client                     *Client
+}
+
+// GetAllNsxtSegmentProfiles retrieves the segment profiles of the given type (one of the
+// types.NsxtSegmentProfile* constants) of the NSX-T manager with the given ID. Query parameters can
+// be supplied to perform additional filtering.
+func (client *Client) GetAllNsxtSegmentProfiles(profileType, nsxtManagerId string, queryParameters url.Values) ([]*types.NsxtSegmentProfile, error) {
+	profileEndpoint, ok := segmentProfileEndpoints[profileType]
+	if !ok {
+		return nil, fmt.Errorf("invalid segment profile type '%s'", profileType)
+	}
+	if nsxtManagerId == "" {
+		return nil, fmt.Errorf("empty NSX-T manager ID")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + profileEndpoint
+	apiVersion, err := client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var profiles []*types.NsxtSegmentProfile
+	err = client.OpenApiGetAllItems(apiVersion, urlRef,
+		queryParameterFilterAnd(fiqlEq("nsxTManagerRef.id", nsxtManagerId), queryParameters), &profiles)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving segment profiles: %s", err)
+	}
+	return profiles, nil
+}
+
+// GetNsxtSegmentProfileByDisplayName retrieves the segment profile of the given type of the NSX-T
+// manager with the given ID and display name
+func (client *Client) GetNsxtSegmentProfileByDisplayName(profileType, nsxtManagerId, name string) (*types.NsxtSegmentProfile, error) {
+	queryParams := url.Values{}
+	queryParams.Set("filter", fiqlEq("displayName", name))
+	profiles, err := client.GetAllNsxtSegmentProfiles(profileType, nsxtManagerId, queryParams)
+	if err != nil {
+		return nil, err
+	}
+	if len(profiles) == 0 {
+		return nil, wrapErrorf(ErrorEntityNotFound, "segment profile '%s' of type %s not found: %s",
+			name, profileType, ErrorEntityNotFound)
+	}
+	if len(profiles) > 1 {
+		return nil, fmt.Errorf("more than one segment profile found with name '%s'", name)
+	}
+	return profiles[0], nil
+}
+
+// GetAllNsxtSegmentProfileTemplates retrieves the segment profile templates. Query parameters can
+// be supplied to perform additional filtering.
+func (client *Client) GetAllNsxtSegmentProfileTemplates(queryParameters url.Values) ([]*NsxtSegmentProfileTemplate, error) {
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointSegmentProfileTemplates
+	apiVersion, err := client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var typeResponses []*types.NsxtSegmentProfileTemplate
+	err = client.OpenApiGetAllItems(apiVersion, urlRef, queryParameters, &typeResponses)
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make([]*NsxtSegmentProfileTemplate, len(typeResponses))
+	for index, typeResponse := range typeResponses {
+		templates[index] = &NsxtSegmentProfileTemplate{NsxtSegmentProfileTemplate: typeResponse, client: client}
+	}
+	return templates, nil
+}
+
+// GetNsxtSegmentProfileTemplateByName retrieves the segment profile template with the given name
+func (client *Client) GetNsxtSegmentProfileTemplateByName(name string) (*NsxtSegmentProfileTemplate, error) {
+	queryParams := url.Values{}
+	queryParams.Set("filter", fiqlEq("name", name))
+	templates, err := client.GetAllNsxtSegmentProfileTemplates(queryParams)
+	if err != nil {
+		return nil, err
+	}
+	if len(templates) == 0 {
+		return nil, wrapErrorf(ErrorEntityNotFound, "segment profile template '%s' not found: %s", name, ErrorEntityNotFound)
+	}
+	if len(templates) > 1 {
+		return nil, fmt.Errorf("more than one segment profile template found with name '%s'", name)
+	}
+	return templates[0], nil
+}
+
+// CreateNsxtSegmentProfileTemplate creates a segment profile template with the given profiles of
+// the NSX-T manager of SourceNsxtManagerRef
+func (client *Client) CreateNsxtSegmentProfileTemplate(templateConfig *types.NsxtSegmentProfileTemplate) (*NsxtSegmentProfileTemplate, error) {
+	err := validateNsxtSegmentProfileTemplate(templateConfig)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointSegmentProfileTemplates
+	apiVersion, err := client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &NsxtSegmentProfileTemplate{NsxtSegmentProfileTemplate: &types.NsxtSegmentProfileTemplate{}, client: client}
+	err = client.OpenApiPostItem(apiVersion, urlRef, nil, templateConfig, template.NsxtSegmentProfileTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("error creating segment profile template: %s", err)
+	}
+	return template, nil
+}
+
+// Reference returns the reference to the segment profile template, as used by the org VDC networks
+func (template *NsxtSegmentProfileTemplate) Reference() *types.OpenApiReference {
+	return &types.OpenApiReference{ID: template.NsxtSegmentProfileTemplate.ID, Name: template.NsxtSegmentProfileTemplate.Name}
+}
+
+// Update sends the current definition of the segment profile template to vCD. The networks using
+// the template get its new profiles.
+func (template *NsxtSegmentProfileTemplate) Update() error {
+	if template.NsxtSegmentProfileTemplate.ID == "" {
+		return fmt.Errorf("cannot update segment profile template without ID")
+	}
+	err := validateNsxtSegmentProfileTemplate(template.NsxtSegmentProfileTemplate)
+	if err != nil {
+		return err
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointSegmentProfileTemplates
+	apiVersion, err := template.client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return err
+	}
+	urlRef, err := template.client.OpenApiBuildEndpoint(endpoint, template.NsxtSegmentProfileTemplate.ID)
+	if err != nil {
+		return err
+	}
+
+	updated := &types.NsxtSegmentProfileTemplate{}
+	err = template.client.OpenApiPutItem(apiVersion, urlRef, nil, template.NsxtSegmentProfileTemplate, updated)
+	if err != nil {
+		return fmt.Errorf("error updating segment profile template: %s", err)
+	}
+	template.NsxtSegmentProfileTemplate = updated
+	return nil
+}
+
+// Delete removes the segment profile template. It fails while networks use it.
+func (template *NsxtSegmentProfileTemplate) Delete() error {
+	if template.NsxtSegmentProfileTemplate.ID == "" {
+		return fmt.Errorf("cannot delete segment profile template without ID")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointSegmentProfileTemplates
+	apiVersion, err := template.client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return err
+	}
+	urlRef, err := template.client.OpenApiBuildEndpoint(endpoint, template.NsxtSegmentProfileTemplate.ID)
+	if err != nil {
+		return err
+	}
+	err = template.client.OpenApiDeleteItem(apiVersion, urlRef, nil)
+	if err != nil {
+		return fmt.Errorf("error deleting segment profile template: %s", err)
+	}
+	return nil
+}
+
+// GetSegmentProfiles retrieves the segment profiles of the NSX-T backed network
+func (network *OpenApiOrgVdcNetwork) GetSegmentProfiles() (*types.OrgVdcNetworkSegmentProfiles, error) {
+	urlRef, apiVersion, err := network.segmentProfilesEndpoint()
+	if err != nil {
+		return nil, err
+	}
+	profiles := &types.OrgVdcNetworkSegmentProfiles{}
+	err = network.client.OpenApiGetItem(apiVersion, urlRef, nil, profiles)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving segment profiles of org VDC network: %s", err)
+	}
+	return profiles, nil
+}
+
+// UpdateSegmentProfiles sets the segment profiles of the NSX-T backed network: either a segment
+// profile template or individual profiles, not both
+func (network *OpenApiOrgVdcNetwork) UpdateSegmentProfiles(profilesConfig *types.OrgVdcNetworkSegmentProfiles) (*types.OrgVdcNetworkSegmentProfiles, error) {
+	if profilesConfig == nil {
+		return nil, fmt.Errorf("no segment profiles given")
+	}
+	individual := []*types.OpenApiReference{profilesConfig.IPDiscoveryProfile, profilesConfig.MacDiscoveryProfile,
+		profilesConfig.SpoofGuardProfile, profilesConfig.QosProfile, profilesConfig.SegmentSecurityProfile}
+	for _, profile := range append(individual, profilesConfig.SegmentProfileTemplate) {
+		if profile != nil && profile.ID == "" {
+			return nil, fmt.Errorf("segment profiles and templates need an ID")
+		}
+	}
+	if profilesConfig.SegmentProfileTemplate != nil {
+		for _, profile := range individual {
+			if profile != nil {
+				return nil, fmt.Errorf("a network with a segment profile template cannot have individual segment profiles")
+			}
+		}
+	}
+
+	urlRef, apiVersion, err := network.segmentProfilesEndpoint()
+	if err != nil {
+		return nil, err
+	}
+	updated := &types.OrgVdcNetworkSegmentProfiles{}
+	err = network.client.OpenApiPutItem(apiVersion, urlRef, nil, profilesConfig, updated)
+	if err != nil {
+		return nil, fmt.Errorf("error updating segment profiles of org VDC network: %s", err)
+	}
+	return updated, nil
+}
+
+// segmentProfilesEndpoint returns the URL of the segment profiles of the network, with the API
+// version to use
+func (network *OpenApiOrgVdcNetwork) segmentProfilesEndpoint() (*url.URL, string, error) {
+	if network.OpenApiOrgVdcNetwork.ID == "" {
+		return nil, "", fmt.Errorf("org VDC network %s has no ID", network.OpenApiOrgVdcNetwork.Name)
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointOrgVdcNetworkSegmentProfiles
+	apiVersion, err := network.client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, "", err
+	}
+	urlRef, err := network.client.OpenApiBuildEndpoint(fmt.Sprintf(endpoint, network.OpenApiOrgVdcNetwork.ID))
+	if err != nil {
+		return nil, "", err
+	}
+	return urlRef, apiVersion, nil
+}
+
+// validateNsxtSegmentProfileTemplate checks the fields needed to create or update a segment profile
+// template
+func validateNsxtSegmentProfileTemplate(templateConfig *types.NsxtSegmentProfileTemplate) error {
+	if templateConfig == nil || templateConfig.Name == "" {
+		return fmt.Errorf("segment profile template name is required")
+	}
+	if templateConfig.SourceNsxtManagerRef == nil || templateConfig.SourceNsxtManagerRef.ID == "" {
+		return fmt.Errorf("segment profile template %s needs an NSX-T manager", templateConfig.Name)
+	}
+	return nil
+}
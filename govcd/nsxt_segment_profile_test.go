@@ -0,0 +1,124 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// Checks the lookup of segment profiles, their grouping in a template and their assignment to an
+// org VDC network against a fake vCD
+func TestNsxtSegmentProfiles(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	const managerId = "urn:vcloud:nsxtmanager:11111111-1111-1111-1111-111111111111"
+	const networkId = "urn:vcloud:network:66666666-6666-6666-6666-666666666666"
+	const spoofGuardPath = "/cloudapi/1.0.0/nsxTResources/segmentSpoofGuardProfiles"
+	const templatesPath = "/cloudapi/1.0.0/segmentProfileTemplates/"
+	const networkProfilesPath = "/cloudapi/1.0.0/orgVdcNetworks/" + networkId + "/segmentProfiles"
+	server.HandleJSON(http.MethodGet, spoofGuardPath, http.StatusOK,
+		`{"resultTotal":1,"pageCount":1,"page":1,"pageSize":128,"values":[{"id":"spoof-guard-1","displayName":"strict",
+		"nsxTManagerRef":{"id":"`+managerId+`"}}]}`)
+	server.HandleJSON(http.MethodGet, "/cloudapi/1.0.0/nsxTResources/segmentQoSProfiles", http.StatusOK,
+		`{"resultTotal":0,"pageCount":0,"page":1,"pageSize":128,"values":[]}`)
+	templateJson := `{"id":"template-1","name":"strict","sourceNsxTManagerRef":{"id":"` + managerId + `"},
+		"spoofGuardProfile":{"id":"spoof-guard-1"}}`
+	server.HandleJSON(http.MethodPost, templatesPath, http.StatusCreated, templateJson)
+	server.HandleJSON(http.MethodGet, templatesPath, http.StatusOK,
+		`{"resultTotal":1,"pageCount":1,"page":1,"pageSize":128,"values":[`+templateJson+`]}`)
+	server.HandleJSON(http.MethodPut, templatesPath+"template-1", http.StatusOK, templateJson)
+	server.Handle(http.MethodDelete, templatesPath+"template-1", vcdtest.Response{Status: http.StatusNoContent})
+	server.HandleJSON(http.MethodGet, networkProfilesPath, http.StatusOK,
+		`{"segmentProfileTemplate":null,"ipDiscoveryProfile":null,"macDiscoveryProfile":null,"spoofGuardProfile":null,
+		"qosProfile":null,"segmentSecurityProfile":null}`)
+	server.HandleJSON(http.MethodPut, networkProfilesPath, http.StatusOK,
+		`{"segmentProfileTemplate":{"id":"template-1","name":"strict"}}`)
+
+	vcdClient := newMockClient(t, server)
+	client := &vcdClient.Client
+
+	if _, err := client.GetAllNsxtSegmentProfiles("FIREWALL", managerId, nil); err == nil {
+		t.Errorf("expected error retrieving segment profiles of an invalid type")
+	}
+	spoofGuard, err := client.GetNsxtSegmentProfileByDisplayName(types.NsxtSegmentProfileSpoofGuard, managerId, "strict")
+	if err != nil {
+		t.Fatalf("error retrieving segment profile: %s", err)
+	}
+	gets := server.RequestsTo(http.MethodGet, spoofGuardPath)
+	query, _ := url.ParseQuery(gets[0].RawQuery)
+	if spoofGuard.ID != "spoof-guard-1" || query.Get("filter") != "displayName==strict;nsxTManagerRef.id=="+managerId ||
+		gets[0].Header.Get("Accept") != "application/json;version=37.0" {
+		t.Errorf("unexpected segment profile %+v retrieved with %#v", spoofGuard, gets[0])
+	}
+	_, err = client.GetNsxtSegmentProfileByDisplayName(types.NsxtSegmentProfileQos, managerId, "strict")
+	if !errors.Is(err, ErrorEntityNotFound) {
+		t.Errorf("expected entity not found error, got %v", err)
+	}
+
+	if _, err = client.CreateNsxtSegmentProfileTemplate(&types.NsxtSegmentProfileTemplate{Name: "strict"}); err == nil {
+		t.Errorf("expected error creating segment profile template without NSX-T manager")
+	}
+	_, err = client.CreateNsxtSegmentProfileTemplate(&types.NsxtSegmentProfileTemplate{
+		Name:                 "strict",
+		SourceNsxtManagerRef: &types.OpenApiReference{ID: managerId},
+		SpoofGuardProfile:    &types.OpenApiReference{ID: spoofGuard.ID},
+	})
+	if err != nil {
+		t.Fatalf("error creating segment profile template: %s", err)
+	}
+	template, err := client.GetNsxtSegmentProfileTemplateByName("strict")
+	if err != nil {
+		t.Fatalf("error retrieving segment profile template: %s", err)
+	}
+	template.NsxtSegmentProfileTemplate.Description = "strict spoof guard"
+	if err = template.Update(); err != nil {
+		t.Fatalf("error updating segment profile template: %s", err)
+	}
+	if puts := server.RequestsTo(http.MethodPut, templatesPath+"template-1"); len(puts) != 1 {
+		t.Errorf("expected one update of the segment profile template, got %d", len(puts))
+	}
+
+	network := &OpenApiOrgVdcNetwork{OpenApiOrgVdcNetwork: &types.OpenApiOrgVdcNetwork{ID: networkId, Name: "net1"}, client: client}
+	current, err := network.GetSegmentProfiles()
+	if err != nil {
+		t.Fatalf("error retrieving segment profiles of network: %s", err)
+	}
+	if current.SegmentProfileTemplate != nil || current.SpoofGuardProfile != nil {
+		t.Errorf("unexpected segment profiles: %+v", current)
+	}
+	mixed := &types.OrgVdcNetworkSegmentProfiles{SegmentProfileTemplate: template.Reference(),
+		QosProfile: &types.OpenApiReference{ID: "qos-1"}}
+	if _, err = network.UpdateSegmentProfiles(mixed); err == nil {
+		t.Errorf("expected error setting both a template and individual segment profiles")
+	}
+	updated, err := network.UpdateSegmentProfiles(&types.OrgVdcNetworkSegmentProfiles{SegmentProfileTemplate: template.Reference()})
+	if err != nil {
+		t.Fatalf("error updating segment profiles of network: %s", err)
+	}
+	if updated.SegmentProfileTemplate == nil || updated.SegmentProfileTemplate.ID != "template-1" {
+		t.Errorf("unexpected segment profiles: %+v", updated)
+	}
+	puts := server.RequestsTo(http.MethodPut, networkProfilesPath)
+	sent := map[string]interface{}{}
+	if len(puts) != 1 || json.Unmarshal([]byte(puts[0].Body), &sent) != nil {
+		t.Fatalf("unexpected segment profile updates: %#v", puts)
+	}
+	// The profiles missing from the template are sent as null, to reset them
+	if value, ok := sent["qosProfile"]; !ok || value != nil {
+		t.Errorf("expected a null QoS profile, got %s", puts[0].Body)
+	}
+
+	if err = template.Delete(); err != nil {
+		t.Fatalf("error deleting segment profile template: %s", err)
+	}
+}
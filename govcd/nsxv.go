@@ -0,0 +1,136 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/util"
+)
+
+// This file contains the functions used to talk to the NSX-V endpoints proxied by vCD for the edge
+// gateways with advanced networking (https://HOST/network/edges/<edge ID>/...). These endpoints
+// exchange XML without namespace and return NSX errors instead of vCD errors.
+
+// HasAdvancedNetworking returns true if the edge gateway uses advanced networking, which is
+// needed by the features managed through the NSX-V endpoints, such as the load balancer
+func (eGW *EdgeGateway) HasAdvancedNetworking() bool {
+	return eGW.EdgeGateway.Configuration != nil && eGW.EdgeGateway.Configuration.AdvancedNetworkingEnabled
+}
+
+// buildProxiedEdgeEndpointURL returns the URL of the NSX-V endpoint of the edge gateway at
+// the given path, e.g. https://HOST/network/edges/<edge ID>/loadbalancer/config/pools/
+func (eGW *EdgeGateway) buildProxiedEdgeEndpointURL(endpointPath string) (string, error) {
+	if !eGW.HasAdvancedNetworking() {
+		return "", fmt.Errorf("edge gateway %s does not have advanced networking enabled", eGW.EdgeGateway.Name)
+	}
+	edgeUrn, err := entityUrn(eGW.EdgeGateway.ID, eGW.EdgeGateway.HREF)
+	if err != nil {
+		return "", fmt.Errorf("error retrieving the ID of edge gateway %s: %s", eGW.EdgeGateway.Name, err)
+	}
+	_, edgeUuid, err := ParseUrn(edgeUrn)
+	if err != nil {
+		return "", err
+	}
+	return eGW.client.VCDHREF.Scheme + "://" + eGW.client.VCDHREF.Host + "/network/edges/" + edgeUuid + endpointPath, nil
+}
+
+// executeNsxvRequest sends a request with an optional XML payload to an NSX-V endpoint, and
+// returns the response when its status is 2XX. Otherwise the NSX error found in the body is
+// returned as an *APIError.
+func (client *Client) executeNsxvRequest(href, method string, payload interface{}) (*http.Response, error) {
+	requestUrl, err := url.ParseRequestURI(href)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing NSX-V URL %s: %s", href, err)
+	}
+
+	var body *bytes.Buffer
+	if payload != nil {
+		marshaledXml, err := xml.MarshalIndent(payload, "  ", "    ")
+		if err != nil {
+			return nil, fmt.Errorf("error marshalling xml data %v", err)
+		}
+		body = bytes.NewBuffer(marshaledXml)
+	}
+
+	var req *http.Request
+	if body != nil {
+		req = client.NewRequest(map[string]string{}, method, *requestUrl, body)
+		req.Header.Add("Content-Type", "application/xml")
+	} else {
+		req = client.NewRequest(map[string]string{}, method, *requestUrl, nil)
+	}
+
+	resp, err := client.Http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusAccepted, http.StatusNoContent:
+		return resp, nil
+	}
+
+	nsxError := types.NSXError{}
+	decodeErr := decodeBody(resp, &nsxError)
+	_ = resp.Body.Close()
+	if decodeErr != nil || nsxError.Details == "" {
+		return nil, fmt.Errorf("API Error: %s", resp.Status)
+	}
+	return nil, &APIError{
+		StatusCode:     resp.StatusCode,
+		MajorErrorCode: resp.StatusCode,
+		MinorErrorCode: nsxError.ErrorCode,
+		Message:        nsxError.Details,
+	}
+}
+
+// nsxvGet retrieves the XML found at an NSX-V endpoint into out
+func (client *Client) nsxvGet(href string, out interface{}) error {
+	resp, err := client.executeNsxvRequest(href, http.MethodGet, nil)
+	if err != nil {
+		return wrapError("error in HTTP GET request: %s", err)
+	}
+	if err = decodeBody(resp, out); err != nil {
+		return fmt.Errorf("error decoding response: %s", err)
+	}
+	return resp.Body.Close()
+}
+
+// nsxvCreate posts payload to an NSX-V endpoint and returns the ID of the created entity,
+// found at the end of the Location header of the response
+func (client *Client) nsxvCreate(href string, payload interface{}) (string, error) {
+	resp, err := client.executeNsxvRequest(href, http.MethodPost, payload)
+	if err != nil {
+		return "", wrapError("error in HTTP POST request: %s", err)
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		return "", fmt.Errorf("error closing response body: %s", err)
+	}
+	location := strings.TrimSuffix(resp.Header.Get("Location"), "/")
+	if location == "" {
+		return "", fmt.Errorf("no Location header in the response of %s", href)
+	}
+	id := path.Base(location)
+	util.Logger.Printf("[TRACE] created NSX-V entity %s at %s", id, href)
+	return id, nil
+}
+
+// nsxvSend sends payload to an NSX-V endpoint with the given method (PUT or DELETE), ignoring
+// the response body
+func (client *Client) nsxvSend(href, method string, payload interface{}) error {
+	resp, err := client.executeNsxvRequest(href, method, payload)
+	if err != nil {
+		return wrapError("error in HTTP "+method+" request: %s", err)
+	}
+	return resp.Body.Close()
+}
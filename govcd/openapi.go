@@ -0,0 +1,490 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/util"
+)
+
+// This file contains the generic functions used to talk to the OpenAPI (cloudapi) endpoints.
+// OpenAPI exchanges JSON payloads and its versions are set per endpoint, independently of
+// the XML API version used by the rest of the client.
+
+// openApiEndpointMinVersions holds the minimum API version needed by each OpenAPI endpoint
+var openApiEndpointMinVersions = map[string]string{
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointRights:             "31.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointGlobalRoles:        "33.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointRightsBundles:      "33.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointTokens:             "36.1",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointVdcComputePolicies: "32.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointRoles:              "31.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointEdgeGateways:       "34.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointOrgVdcNetworks:     "32.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointFirewallGroups:     "34.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointExternalNetworks:   "33.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointImportableTier0s:   "32.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointQosProfiles:        "36.2",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointAuditTrail:         "33.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointVdcGroups:          "35.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointVdcGroupCandidates: "35.0",
+
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointNetworkContextProfiles: "35.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointIpSpaces:               "37.1",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointIpSpaceSummaries:       "37.1",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointIpSpaceUplinks:         "37.1",
+
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointSegmentIpDiscoveryProfiles:  "37.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointSegmentMacDiscoveryProfiles: "37.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointSegmentSpoofGuardProfiles:   "37.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointSegmentQosProfiles:          "37.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointSegmentSecurityProfiles:     "37.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointSegmentProfileTemplates:     "37.0",
+
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointRdeInterfaces:  "35.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointRdeEntityTypes: "35.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointRdeEntities:    "35.0",
+
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointEdgeGatewayDhcpForwarder:  "36.1",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointEdgeGatewayStaticRoutes:   "37.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointEdgeGatewayBgp:            "35.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointEdgeGatewayBgpNeighbors:   "35.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointEdgeGatewayBgpPrefixLists: "35.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointEdgeGatewayUsedIps:        "34.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointEdgeGatewayFirewallRules:  "35.2",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointEdgeGatewaySlaacProfile:   "35.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointEdgeGatewayQos:            "36.2",
+
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointOrgVdcNetworkSegmentProfiles: "37.0",
+
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointExternalNetworkUsedIps: "33.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointVdcGroupDfwPolicies:    "35.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointVdcGroupDfwRules:       "35.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointIpSpaceAllocate:        "37.1",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointIpSpaceAllocations:     "37.1",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointIpSpaceOrgAssignments:  "37.1",
+
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointRdeEntityResolve:                    "35.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointRdeInterfaceBehaviors:               "37.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointRdeEntityTypeBehaviors:              "37.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointRdeEntityTypeBehaviorAccessControls: "37.0",
+	types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointRdeEntityBehaviorInvocations:        "37.0",
+}
+
+// getOpenApiVersion returns the API version to use for the given OpenAPI endpoint
+func getOpenApiVersion(endpoint string) (string, error) {
+	version, ok := openApiEndpointMinVersions[endpoint]
+	if !ok {
+		return "", fmt.Errorf("no API version defined for OpenAPI endpoint '%s'", endpoint)
+	}
+	return version, nil
+}
+
+// getOpenApiHighestElevatedVersion returns the API version to use for the given OpenAPI endpoint:
+// the minimum version of the endpoint, raised to the version of the client when the client uses a
+// newer one (see WithAPIVersion), so that the fields added by newer versions are available
+func (client *Client) getOpenApiHighestElevatedVersion(endpoint string) (string, error) {
+	minimumVersion, err := getOpenApiVersion(endpoint)
+	if err != nil {
+		return "", err
+	}
+	if client.APIVersion != "" && client.APIClientVersionIs("> "+minimumVersion) {
+		return client.APIVersion, nil
+	}
+	return minimumVersion, nil
+}
+
+// OpenApiBuildEndpoint builds the URL of an OpenAPI endpoint, using the host of the client.
+// E.g. OpenApiBuildEndpoint("1.0.0/", "globalRoles/") returns https://HOST/cloudapi/1.0.0/globalRoles/
+func (client *Client) OpenApiBuildEndpoint(endpoint ...string) (*url.URL, error) {
+	endpointString := client.VCDHREF.Scheme + "://" + client.VCDHREF.Host + "/cloudapi/" + strings.Join(endpoint, "")
+	urlRef, err := url.ParseRequestURI(endpointString)
+	if err != nil {
+		return nil, fmt.Errorf("error formatting OpenAPI endpoint: %s", err)
+	}
+	return urlRef, nil
+}
+
+// OpenApiGetItem retrieves a single item from an OpenAPI endpoint and unmarshals it into outType
+func (client *Client) OpenApiGetItem(apiVersion string, urlRef *url.URL, queryParams url.Values, outType interface{}) error {
+	_, err := client.openApiGetItemAndHeaders(apiVersion, urlRef, queryParams, outType)
+	return err
+}
+
+// openApiGetItemAndHeaders retrieves a single item from an OpenAPI endpoint into outType, and
+// returns the headers of the response
+func (client *Client) openApiGetItemAndHeaders(apiVersion string, urlRef *url.URL, queryParams url.Values, outType interface{}) (http.Header, error) {
+	util.Logger.Printf("[TRACE] Getting OpenAPI item from endpoint %s with expected response of type %T", urlRef.String(), outType)
+
+	req := client.newOpenApiRequest(apiVersion, queryParams, http.MethodGet, urlRef, nil)
+	resp, err := checkOpenApiResp(client.Http.Do(req))
+	if err != nil {
+		return nil, wrapError("error in HTTP GET request: %s", err)
+	}
+
+	if err = decodeJsonBody(resp, outType); err != nil {
+		return nil, fmt.Errorf("error decoding JSON response after GET: %s", err)
+	}
+
+	err = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error closing response body: %s", err)
+	}
+	return resp.Header, nil
+}
+
+// OpenApiGetAllItems retrieves all the items from an OpenAPI endpoint, following the "nextPage"
+// links of the responses, or the page count when the links are missing. outType must be a
+// pointer to a slice of the expected item type.
+func (client *Client) OpenApiGetAllItems(apiVersion string, urlRef *url.URL, queryParams url.Values, outType interface{}) error {
+	util.Logger.Printf("[TRACE] Getting all OpenAPI items from endpoint %s with expected response of type %T", urlRef.String(), outType)
+
+	params := copyUrlValues(queryParams)
+	if params.Get("pageSize") == "" {
+		params.Set("pageSize", "128")
+	}
+	params.Set("page", "1")
+
+	var allValues []json.RawMessage
+	pageUrl := urlRef
+	for page := 1; ; page++ {
+		pages := types.OpenApiPages{}
+		headers, err := client.openApiGetItemAndHeaders(apiVersion, pageUrl, params, &pages)
+		if err != nil {
+			return err
+		}
+		var pageValues []json.RawMessage
+		if len(pages.Values) > 0 {
+			if err = json.Unmarshal(pages.Values, &pageValues); err != nil {
+				return fmt.Errorf("error decoding values of page %d: %s", page, err)
+			}
+		}
+		allValues = append(allValues, pageValues...)
+
+		// The link to the next page keeps the query of the first request
+		nextPage := findLinkHeader(headers, "nextPage")
+		if nextPage != "" {
+			nextPageUrl, err := urlRef.Parse(nextPage)
+			if err != nil {
+				return fmt.Errorf("error parsing link to page %d: %s", page+1, err)
+			}
+			pageUrl = nextPageUrl
+			params = nil
+			continue
+		}
+		if page >= pages.PageCount || params == nil {
+			break
+		}
+		params.Set("page", strconv.Itoa(page+1))
+	}
+
+	// Marshal the collected values back into a single list and unmarshal it into the requested type
+	allValuesJson, err := json.Marshal(allValues)
+	if err != nil {
+		return fmt.Errorf("error marshalling collected values: %s", err)
+	}
+	if err = json.Unmarshal(allValuesJson, outType); err != nil {
+		return fmt.Errorf("error decoding values into type %T: %s", outType, err)
+	}
+	return nil
+}
+
+// OpenApiPostItem sends payload to an OpenAPI endpoint with POST and unmarshals the response
+// into outType, if it is not nil. When the operation is asynchronous, the task is waited for and
+// outType receives the entity owning the task, i.e. the created one.
+func (client *Client) OpenApiPostItem(apiVersion string, urlRef *url.URL, params url.Values, payload, outType interface{}) error {
+	return client.openApiSendItem(http.MethodPost, apiVersion, urlRef, params, payload, outType)
+}
+
+// OpenApiPutItem sends payload to an OpenAPI endpoint with PUT and unmarshals the response
+// into outType, if it is not nil. When the operation is asynchronous, the task is waited for and
+// outType receives the updated entity.
+func (client *Client) OpenApiPutItem(apiVersion string, urlRef *url.URL, params url.Values, payload, outType interface{}) error {
+	return client.openApiSendItem(http.MethodPut, apiVersion, urlRef, params, payload, outType)
+}
+
+// OpenApiDeleteItem deletes the item at the given OpenAPI endpoint, waiting for the task when the
+// operation is asynchronous
+func (client *Client) OpenApiDeleteItem(apiVersion string, urlRef *url.URL, params url.Values) error {
+	util.Logger.Printf("[TRACE] Deleting OpenAPI item at endpoint %s", urlRef.String())
+
+	req := client.newOpenApiRequest(apiVersion, params, http.MethodDelete, urlRef, nil)
+	resp, err := checkOpenApiResp(client.Http.Do(req))
+	if err != nil {
+		return wrapError("error in HTTP DELETE request: %s", err)
+	}
+	if resp.StatusCode == http.StatusAccepted {
+		_ = resp.Body.Close()
+		return client.openApiWaitTask(http.MethodDelete, apiVersion, urlRef, resp, nil)
+	}
+	return resp.Body.Close()
+}
+
+// openApiSendItem performs a POST or PUT request with a JSON payload
+func (client *Client) openApiSendItem(method, apiVersion string, urlRef *url.URL, params url.Values, payload, outType interface{}) error {
+	resp, err := client.openApiSendPayload(method, apiVersion, urlRef, params, payload)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode == http.StatusAccepted {
+		_ = resp.Body.Close()
+		return client.openApiWaitTask(method, apiVersion, urlRef, resp, outType)
+	}
+
+	if outType != nil {
+		if err = decodeJsonBody(resp, outType); err != nil {
+			return fmt.Errorf("error decoding JSON response after %s: %s", method, err)
+		}
+	}
+
+	err = resp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("error closing response body: %s", err)
+	}
+	return nil
+}
+
+// openApiPostItemForTaskResult sends payload to an OpenAPI endpoint with POST, for the operations
+// which return a value instead of creating an entity. When the operation is asynchronous, the task
+// is waited for and the JSON content of its result is unmarshalled into outType; otherwise the
+// response itself is.
+func (client *Client) openApiPostItemForTaskResult(apiVersion string, urlRef *url.URL, params url.Values, payload, outType interface{}) error {
+	content, err := client.openApiPostItemForTaskResultContent(apiVersion, urlRef, params, payload)
+	if err != nil {
+		return err
+	}
+	if content == "" {
+		return fmt.Errorf("no result for POST request to %s", urlRef.String())
+	}
+	err = json.Unmarshal([]byte(content), outType)
+	if err != nil {
+		return fmt.Errorf("error decoding the result of POST request to %s: %s", urlRef.String(), err)
+	}
+	return nil
+}
+
+// openApiPostItemForTaskResultContent is like openApiPostItemForTaskResult, but returns the result
+// as is, as it is not always JSON (e.g. the result of a behavior invocation). The result of an
+// operation without a value is empty.
+func (client *Client) openApiPostItemForTaskResultContent(apiVersion string, urlRef *url.URL, params url.Values, payload interface{}) (string, error) {
+	resp, err := client.openApiSendPayload(http.MethodPost, apiVersion, urlRef, params, payload)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusAccepted {
+		body, err := ioutil.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return "", fmt.Errorf("error reading response after POST: %s", err)
+		}
+		return string(body), nil
+	}
+	_ = resp.Body.Close()
+
+	taskHREF := resp.Header.Get("Location")
+	if taskHREF == "" {
+		return "", fmt.Errorf("no task in the response of asynchronous POST request to %s", urlRef.String())
+	}
+	task := NewTask(client)
+	task.Task.HREF = taskHREF
+	err = task.WaitTaskCompletion()
+	if err != nil {
+		return "", fmt.Errorf("error waiting for the task of POST request to %s: %s", urlRef.String(), err)
+	}
+	if task.Task.Result == nil {
+		return "", nil
+	}
+	return task.Task.Result.ResultContent, nil
+}
+
+// openApiSendPayload sends payload as JSON to an OpenAPI endpoint and returns the successful
+// response, whose body must be closed by the caller
+func (client *Client) openApiSendPayload(method, apiVersion string, urlRef *url.URL, params url.Values, payload interface{}) (*http.Response, error) {
+	util.Logger.Printf("[TRACE] %s OpenAPI item to endpoint %s with payload of type %T", method, urlRef.String(), payload)
+
+	marshaledJson, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling JSON data for %s request: %s", method, err)
+	}
+	body := bytes.NewBuffer(marshaledJson)
+
+	req := client.newOpenApiRequest(apiVersion, params, method, urlRef, body)
+	resp, err := checkOpenApiResp(client.Http.Do(req))
+	if err != nil {
+		return nil, wrapError("error in HTTP "+method+" request: %s", err)
+	}
+	return resp, nil
+}
+
+// openApiWaitTask waits for the task of an asynchronous OpenAPI operation, found in the Location
+// header of the response, then retrieves the resulting entity into outType, if it is not nil: the
+// owner of the task after a POST, the item at urlRef otherwise
+func (client *Client) openApiWaitTask(method, apiVersion string, urlRef *url.URL, resp *http.Response, outType interface{}) error {
+	taskHREF := resp.Header.Get("Location")
+	if taskHREF == "" {
+		return fmt.Errorf("no task in the response of asynchronous %s request to %s", method, urlRef.String())
+	}
+	task := NewTask(client)
+	task.Task.HREF = taskHREF
+	err := task.WaitTaskCompletion()
+	if err != nil {
+		return fmt.Errorf("error waiting for the task of %s request to %s: %s", method, urlRef.String(), err)
+	}
+	if outType == nil {
+		return nil
+	}
+
+	itemUrl := *urlRef
+	if method == http.MethodPost {
+		if task.Task.Owner == nil || task.Task.Owner.ID == "" {
+			return fmt.Errorf("task %s does not reference the created entity", taskHREF)
+		}
+		itemUrl.Path = strings.TrimSuffix(itemUrl.Path, "/") + "/" + task.Task.Owner.ID
+	}
+	return client.OpenApiGetItem(apiVersion, &itemUrl, nil, outType)
+}
+
+// newOpenApiRequest creates a new HTTP request for an OpenAPI endpoint, with JSON headers
+// for the given API version
+func (client *Client) newOpenApiRequest(apiVersion string, params url.Values, method string, reqUrl *url.URL, body io.Reader) *http.Request {
+	// Work on a copy of the URL, to avoid changing the one passed by the caller
+	reqUrlCopy := *reqUrl
+	if len(params) > 0 {
+		reqUrlCopy.RawQuery = params.Encode()
+	}
+
+	req, _ := http.NewRequest(method, reqUrlCopy.String(), body)
+	req = req.WithContext(client.Context())
+	client.setUserAgent(req)
+	client.clearEntityCacheForChange(method)
+
+	if client.VCDAuthHeader != "" && client.VCDToken != "" {
+		req.Header.Add(client.VCDAuthHeader, client.VCDToken)
+	}
+	req.Header.Add("Accept", "application/json;version="+apiVersion)
+	if client.tenantContext != "" {
+		req.Header.Add("X-VMWARE-VCLOUD-TENANT-CONTEXT", client.tenantContext)
+	}
+	if body != nil {
+		req.Header.Add("Content-Type", "application/json")
+	}
+
+	if util.LogHttpRequest {
+		payload := ""
+		if buffer, ok := body.(*bytes.Buffer); ok {
+			payload = buffer.String()
+		}
+		util.ProcessRequestOutput(util.FuncNameCallStack(), method, reqUrlCopy.String(), payload, req)
+	}
+	return req
+}
+
+// withTenantContext returns a copy of the client whose OpenAPI requests run in the org with the
+// given UUID, so that a system administrator can manage the entities of that org
+func (client *Client) withTenantContext(orgUuid string) *Client {
+	withTenantContext := *client
+	withTenantContext.tenantContext = orgUuid
+	return &withTenantContext
+}
+
+// checkOpenApiResp is the OpenAPI equivalent of checkResp: it passes back the response
+// when the status code is 2XX and returns the error found in the JSON body otherwise
+func checkOpenApiResp(resp *http.Response, err error) (*http.Response, error) {
+	if err != nil {
+		return resp, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusAccepted, http.StatusNoContent:
+		return resp, nil
+	}
+
+	openApiError := types.OpenApiError{}
+	decodeErr := decodeJsonBody(resp, &openApiError)
+	_ = resp.Body.Close()
+	if decodeErr != nil || openApiError.Message == "" {
+		return nil, fmt.Errorf("API Error: %s", resp.Status)
+	}
+	return nil, &APIError{
+		StatusCode:     resp.StatusCode,
+		MajorErrorCode: resp.StatusCode,
+		MinorErrorCode: openApiError.MinorErrorCode,
+		Message:        openApiError.Message,
+		openApi:        true,
+	}
+}
+
+// decodeJsonBody is used to JSON decode a response body
+func decodeJsonBody(resp *http.Response, out interface{}) error {
+	body, err := ioutil.ReadAll(resp.Body)
+	util.ProcessResponseOutput(util.FuncNameCallStack(), resp, fmt.Sprintf("%s", body))
+	if err != nil {
+		return err
+	}
+	if len(body) == 0 {
+		return nil
+	}
+	return json.Unmarshal(body, out)
+}
+
+// copyUrlValues returns a copy of the given query parameters, so that they can be changed
+// without side effects for the caller
+func copyUrlValues(in url.Values) url.Values {
+	out := url.Values{}
+	for key, values := range in {
+		for _, value := range values {
+			out.Add(key, value)
+		}
+	}
+	return out
+}
+
+// linkHeaderRegexp matches the links of a Link header, e.g. <https://HOST/cloudapi/1.0.0/roles?page=2>;rel="nextPage"
+var linkHeaderRegexp = regexp.MustCompile(`<([^>]*)>([^<]*)`)
+
+// findLinkHeader returns the URL of the link with the given relation in the Link headers, or an
+// empty string when there is none
+func findLinkHeader(headers http.Header, rel string) string {
+	for _, header := range headers["Link"] {
+		for _, match := range linkHeaderRegexp.FindAllStringSubmatch(header, -1) {
+			for _, param := range strings.Split(match[2], ";") {
+				if strings.TrimSpace(param) == `rel="`+rel+`"` || strings.TrimSpace(param) == "rel="+rel {
+					return match[1]
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// fiqlValueEscaper percent-encodes the characters of a value which have a meaning in FIQL filters
+var fiqlValueEscaper = strings.NewReplacer("%", "%25", "(", "%28", ")", "%29", ";", "%3B", ",", "%2C")
+
+// fiqlEq returns a FIQL condition matching the items whose field equals value, e.g. name==my%3Bname
+func fiqlEq(field, value string) string {
+	return field + "==" + fiqlValueEscaper.Replace(value)
+}
+
+// queryParameterFilterAnd returns a copy of queryParameters where filter is added, with a logical
+// AND, to the filter already present
+func queryParameterFilterAnd(filter string, queryParameters url.Values) url.Values {
+	params := copyUrlValues(queryParameters)
+	if params.Get("filter") != "" {
+		filter = params.Get("filter") + ";" + filter
+	}
+	params.Set("filter", filter)
+	return params
+}
@@ -0,0 +1,437 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// OpenApiOrgVdcNetwork is an org VDC network managed through OpenAPI, which is the only way to
+// manage the NSX-T backed networks: routed through an NSX-T edge gateway, isolated, or imported
+// from an existing NSX-T segment.
+type OpenApiOrgVdcNetwork struct {
+	OpenApiOrgVdcNetwork *types.OpenApiOrgVdcNetwork
+	client               *Client
+}
+
+// GetAllOpenApiOrgVdcNetworks retrieves the org VDC networks owned by the VDC. Query parameters can
+// be supplied to perform additional filtering (e.g. "filter" => "networkType==ISOLATED")
+func (vdc *Vdc) GetAllOpenApiOrgVdcNetworks(queryParameters url.Values) ([]*OpenApiOrgVdcNetwork, error) {
+	vdcUrn, err := entityUrn(vdc.Vdc.ID, vdc.Vdc.HREF)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving the ID of vdc %s: %s", vdc.Vdc.Name, err)
+	}
+	ownerField := "orgVdc.id"
+	if vdc.client.APIClientVersionIs(">= 35.0") {
+		ownerField = "ownerRef.id"
+	}
+
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointOrgVdcNetworks
+	apiVersion, err := vdc.client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := vdc.client.OpenApiBuildEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var typeResponses []*types.OpenApiOrgVdcNetwork
+	err = vdc.client.OpenApiGetAllItems(apiVersion, urlRef,
+		queryParameterFilterAnd(fiqlEq(ownerField, vdcUrn), queryParameters), &typeResponses)
+	if err != nil {
+		return nil, err
+	}
+
+	networks := make([]*OpenApiOrgVdcNetwork, len(typeResponses))
+	for index, typeResponse := range typeResponses {
+		networks[index] = &OpenApiOrgVdcNetwork{OpenApiOrgVdcNetwork: typeResponse, client: vdc.client}
+	}
+	return networks, nil
+}
+
+// GetOpenApiOrgVdcNetworkByName retrieves the org VDC network of the VDC with the given name
+func (vdc *Vdc) GetOpenApiOrgVdcNetworkByName(name string) (*OpenApiOrgVdcNetwork, error) {
+	queryParams := url.Values{}
+	queryParams.Set("filter", fiqlEq("name", name))
+	networks, err := vdc.GetAllOpenApiOrgVdcNetworks(queryParams)
+	if err != nil {
+		return nil, err
+	}
+	if len(networks) == 0 {
+		return nil, fmt.Errorf("org VDC network '%s' not found in vdc %s", name, vdc.Vdc.Name)
+	}
+	if len(networks) > 1 {
+		return nil, fmt.Errorf("more than one org VDC network found with name '%s'", name)
+	}
+	return networks[0], nil
+}
+
+// GetOpenApiOrgVdcNetworkById retrieves the org VDC network with the given ID
+func (vdc *Vdc) GetOpenApiOrgVdcNetworkById(id string) (*OpenApiOrgVdcNetwork, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty org VDC network ID")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointOrgVdcNetworks
+	apiVersion, err := vdc.client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := vdc.client.OpenApiBuildEndpoint(endpoint, id)
+	if err != nil {
+		return nil, err
+	}
+
+	network := &OpenApiOrgVdcNetwork{OpenApiOrgVdcNetwork: &types.OpenApiOrgVdcNetwork{}, client: vdc.client}
+	err = vdc.client.OpenApiGetItem(apiVersion, urlRef, nil, network.OpenApiOrgVdcNetwork)
+	if err != nil {
+		return nil, err
+	}
+	return network, nil
+}
+
+// CreateOpenApiOrgVdcNetwork creates an org VDC network. The network is owned by the VDC unless
+// another owner, such as a VDC group (API 35.0+), is given. NetworkType selects the kind of network:
+// a routed network (types.OrgVdcNetworkTypeRouted) needs the edge gateway in Connection.RouterRef,
+// an imported network (types.OrgVdcNetworkTypeImported) needs the ID of the NSX-T segment in
+// BackingNetworkId, and an isolated network (types.OrgVdcNetworkTypeIsolated) needs no other setting.
+func (vdc *Vdc) CreateOpenApiOrgVdcNetwork(networkConfig *types.OpenApiOrgVdcNetwork) (*OpenApiOrgVdcNetwork, error) {
+	err := validateOpenApiOrgVdcNetwork(networkConfig)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointOrgVdcNetworks
+	apiVersion, err := vdc.client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := vdc.client.OpenApiBuildEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := *networkConfig
+	if payload.OwnerRef == nil && payload.OrgVdc == nil {
+		vdcUrn, err := entityUrn(vdc.Vdc.ID, vdc.Vdc.HREF)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving the ID of vdc %s: %s", vdc.Vdc.Name, err)
+		}
+		payload.OwnerRef = &types.OpenApiReference{ID: vdcUrn}
+	}
+	err = adjustOpenApiOwner(vdc.client, &payload.OwnerRef, &payload.OrgVdc)
+	if err != nil {
+		return nil, err
+	}
+
+	network := &OpenApiOrgVdcNetwork{OpenApiOrgVdcNetwork: &types.OpenApiOrgVdcNetwork{}, client: vdc.client}
+	err = vdc.client.OpenApiPostItem(apiVersion, urlRef, nil, &payload, network.OpenApiOrgVdcNetwork)
+	if err != nil {
+		return nil, fmt.Errorf("error creating org VDC network: %s", err)
+	}
+	return network, nil
+}
+
+// IsRouted returns true if the network is routed through an edge gateway
+func (network *OpenApiOrgVdcNetwork) IsRouted() bool {
+	return network.OpenApiOrgVdcNetwork.NetworkType == types.OrgVdcNetworkTypeRouted
+}
+
+// IsIsolated returns true if the network is isolated
+func (network *OpenApiOrgVdcNetwork) IsIsolated() bool {
+	return network.OpenApiOrgVdcNetwork.NetworkType == types.OrgVdcNetworkTypeIsolated
+}
+
+// IsImported returns true if the network is an imported NSX-T segment
+func (network *OpenApiOrgVdcNetwork) IsImported() bool {
+	return network.OpenApiOrgVdcNetwork.NetworkType == types.OrgVdcNetworkTypeImported
+}
+
+// Refresh retrieves the current definition of the network
+func (network *OpenApiOrgVdcNetwork) Refresh() error {
+	urlRef, apiVersion, err := network.buildEndpoint("")
+	if err != nil {
+		return err
+	}
+	refreshed := &types.OpenApiOrgVdcNetwork{}
+	err = network.client.OpenApiGetItem(apiVersion, urlRef, nil, refreshed)
+	if err != nil {
+		return err
+	}
+	network.OpenApiOrgVdcNetwork = refreshed
+	return nil
+}
+
+// Update sends the current definition of the network (name, description, subnets with their
+// static IP pools and DNS settings, connection) to vCD
+func (network *OpenApiOrgVdcNetwork) Update() error {
+	err := validateOpenApiOrgVdcNetwork(network.OpenApiOrgVdcNetwork)
+	if err != nil {
+		return err
+	}
+	urlRef, apiVersion, err := network.buildEndpoint("")
+	if err != nil {
+		return err
+	}
+	payload := *network.OpenApiOrgVdcNetwork
+	err = adjustOpenApiOwner(network.client, &payload.OwnerRef, &payload.OrgVdc)
+	if err != nil {
+		return err
+	}
+
+	updated := &types.OpenApiOrgVdcNetwork{}
+	err = network.client.OpenApiPutItem(apiVersion, urlRef, nil, &payload, updated)
+	if err != nil {
+		return fmt.Errorf("error updating org VDC network: %s", err)
+	}
+	network.OpenApiOrgVdcNetwork = updated
+	return nil
+}
+
+// Delete removes the network. It fails if VMs are still connected to it.
+func (network *OpenApiOrgVdcNetwork) Delete() error {
+	urlRef, apiVersion, err := network.buildEndpoint("")
+	if err != nil {
+		return err
+	}
+	err = network.client.OpenApiDeleteItem(apiVersion, urlRef, nil)
+	if err != nil {
+		return fmt.Errorf("error deleting org VDC network: %s", err)
+	}
+	return nil
+}
+
+// GetDhcp retrieves the DHCP service of an NSX-T backed network
+func (network *OpenApiOrgVdcNetwork) GetDhcp() (*types.OpenApiOrgVdcNetworkDhcp, error) {
+	urlRef, apiVersion, err := network.buildEndpoint("/dhcp")
+	if err != nil {
+		return nil, err
+	}
+	dhcp := &types.OpenApiOrgVdcNetworkDhcp{}
+	err = network.client.OpenApiGetItem(apiVersion, urlRef, nil, dhcp)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving DHCP of org VDC network: %s", err)
+	}
+	return dhcp, nil
+}
+
+// UpdateDhcp sets the DHCP service of an NSX-T backed network, with its pools and DNS servers.
+// The pools must belong to the subnet of the network, outside of its static IP pools.
+func (network *OpenApiOrgVdcNetwork) UpdateDhcp(dhcp *types.OpenApiOrgVdcNetworkDhcp) (*types.OpenApiOrgVdcNetworkDhcp, error) {
+	if dhcp == nil {
+		return nil, fmt.Errorf("no DHCP configuration given")
+	}
+	for _, pool := range dhcp.DhcpPools {
+		if pool.IPRange.StartAddress == "" || pool.IPRange.EndAddress == "" {
+			return nil, fmt.Errorf("DHCP pools need start and end addresses")
+		}
+	}
+	if dhcp.LeaseTime != nil && *dhcp.LeaseTime < minimumDhcpLeaseTime {
+		return nil, fmt.Errorf("DHCP lease time must be at least %d seconds, got %d", minimumDhcpLeaseTime, *dhcp.LeaseTime)
+	}
+	urlRef, apiVersion, err := network.buildEndpoint("/dhcp")
+	if err != nil {
+		return nil, err
+	}
+	updated := &types.OpenApiOrgVdcNetworkDhcp{}
+	err = network.client.OpenApiPutItem(apiVersion, urlRef, nil, dhcp, updated)
+	if err != nil {
+		return nil, fmt.Errorf("error updating DHCP of org VDC network: %s", err)
+	}
+	return updated, nil
+}
+
+// DeleteDhcp removes the DHCP service of an NSX-T backed network
+func (network *OpenApiOrgVdcNetwork) DeleteDhcp() error {
+	urlRef, apiVersion, err := network.buildEndpoint("/dhcp")
+	if err != nil {
+		return err
+	}
+	err = network.client.OpenApiDeleteItem(apiVersion, urlRef, nil)
+	if err != nil {
+		return fmt.Errorf("error deleting DHCP of org VDC network: %s", err)
+	}
+	return nil
+}
+
+// minimumDhcpLeaseTime is the shortest DHCP lease accepted by NSX-T, in seconds
+const minimumDhcpLeaseTime = 60
+
+// EnableDhcp enables the DHCP service of the network, keeping its pools and lease time. In
+// NsxtDhcpModeEdge mode, which is the default, the network must be routed and have a DHCP pool.
+func (network *OpenApiOrgVdcNetwork) EnableDhcp() (*types.OpenApiOrgVdcNetworkDhcp, error) {
+	return network.modifyDhcp(func(dhcp *types.OpenApiOrgVdcNetworkDhcp) error {
+		if dhcp.Mode == "" || dhcp.Mode == types.NsxtDhcpModeEdge {
+			if !network.IsRouted() {
+				return fmt.Errorf("DHCP in %s mode needs a routed network", types.NsxtDhcpModeEdge)
+			}
+			if len(dhcp.DhcpPools) == 0 {
+				return fmt.Errorf("DHCP in %s mode needs a DHCP pool", types.NsxtDhcpModeEdge)
+			}
+		}
+		enabled := true
+		dhcp.Enabled = &enabled
+		return nil
+	})
+}
+
+// DisableDhcp disables the DHCP service of the network, keeping its pools and lease time
+func (network *OpenApiOrgVdcNetwork) DisableDhcp() (*types.OpenApiOrgVdcNetworkDhcp, error) {
+	return network.modifyDhcp(func(dhcp *types.OpenApiOrgVdcNetworkDhcp) error {
+		enabled := false
+		dhcp.Enabled = &enabled
+		return nil
+	})
+}
+
+// SetDhcpPools replaces the DHCP pools of the network, keeping the rest of its DHCP service.
+// The pools must belong to the subnet of the network, outside of its static IP pools.
+func (network *OpenApiOrgVdcNetwork) SetDhcpPools(pools []types.OpenApiOrgVdcNetworkDhcpPool) (*types.OpenApiOrgVdcNetworkDhcp, error) {
+	return network.modifyDhcp(func(dhcp *types.OpenApiOrgVdcNetworkDhcp) error {
+		dhcp.DhcpPools = pools
+		return nil
+	})
+}
+
+// SetDhcpLeaseTime sets the lease time of the addresses given by the DHCP service of the network,
+// in seconds. NSX-T accepts leases of 60 seconds or more.
+func (network *OpenApiOrgVdcNetwork) SetDhcpLeaseTime(leaseTime int) (*types.OpenApiOrgVdcNetworkDhcp, error) {
+	return network.modifyDhcp(func(dhcp *types.OpenApiOrgVdcNetworkDhcp) error {
+		dhcp.LeaseTime = &leaseTime
+		return nil
+	})
+}
+
+// EnableDhcpv6 enables the DHCPv6 service of a routed network with an IPv6 subnet, leasing the
+// addresses of pools and advertising dnsServers. The pools must belong to the IPv6 subnet. The edge
+// gateway of the network must have its SLAAC profile enabled in NsxtSlaacModeDhcpv6 mode (see
+// NsxtEdgeGateway.UpdateSlaacProfile), otherwise its hosts never ask for a DHCPv6 lease.
+func (network *OpenApiOrgVdcNetwork) EnableDhcpv6(pools []types.OpenApiOrgVdcNetworkDhcpPool, dnsServers []string) (*types.OpenApiOrgVdcNetworkDhcp, error) {
+	if !network.IsRouted() || network.OpenApiOrgVdcNetwork.Connection == nil {
+		return nil, fmt.Errorf("DHCPv6 needs a routed network")
+	}
+	subnet := network.ipv6Subnet()
+	if subnet == nil {
+		return nil, fmt.Errorf("DHCPv6 needs a network with an IPv6 subnet")
+	}
+	if len(pools) == 0 {
+		return nil, fmt.Errorf("DHCPv6 needs a DHCP pool")
+	}
+	for _, pool := range pools {
+		start, end, err := parseIpRange(pool.IPRange)
+		if err != nil {
+			return nil, err
+		}
+		if !subnet.Contains(start) || !subnet.Contains(end) {
+			return nil, fmt.Errorf("DHCPv6 pool %s-%s is outside of subnet %s", pool.IPRange.StartAddress, pool.IPRange.EndAddress, subnet)
+		}
+	}
+	for _, server := range dnsServers {
+		ip := net.ParseIP(server)
+		if ip == nil || ip.To4() != nil {
+			return nil, fmt.Errorf("invalid IPv6 address of DNS server '%s'", server)
+		}
+	}
+
+	edge := &NsxtEdgeGateway{
+		NsxtEdgeGateway: &types.NsxtEdgeGateway{
+			ID:   network.OpenApiOrgVdcNetwork.Connection.RouterRef.ID,
+			Name: network.OpenApiOrgVdcNetwork.Connection.RouterRef.Name,
+		},
+		client: network.client,
+	}
+	profile, err := edge.GetSlaacProfile()
+	if err != nil {
+		return nil, err
+	}
+	if !profile.Enabled || profile.Mode != types.NsxtSlaacModeDhcpv6 {
+		return nil, fmt.Errorf("DHCPv6 needs the SLAAC profile of the edge gateway to be enabled in %s mode", types.NsxtSlaacModeDhcpv6)
+	}
+
+	return network.modifyDhcp(func(dhcp *types.OpenApiOrgVdcNetworkDhcp) error {
+		enabled := true
+		dhcp.Enabled = &enabled
+		dhcp.DhcpPools = pools
+		dhcp.DnsServers = dnsServers
+		return nil
+	})
+}
+
+// ipv6Subnet returns the first IPv6 subnet of the network, or nil when it has none
+func (network *OpenApiOrgVdcNetwork) ipv6Subnet() *net.IPNet {
+	for _, subnet := range network.OpenApiOrgVdcNetwork.Subnets.Values {
+		gateway := net.ParseIP(subnet.Gateway)
+		if gateway == nil || gateway.To4() != nil {
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(fmt.Sprintf("%s/%d", subnet.Gateway, subnet.PrefixLength))
+		if err == nil {
+			return ipNet
+		}
+	}
+	return nil
+}
+
+// modifyDhcp retrieves the DHCP service of the network, applies modify to it and sends it back
+func (network *OpenApiOrgVdcNetwork) modifyDhcp(modify func(dhcp *types.OpenApiOrgVdcNetworkDhcp) error) (*types.OpenApiOrgVdcNetworkDhcp, error) {
+	dhcp, err := network.GetDhcp()
+	if err != nil {
+		return nil, err
+	}
+	err = modify(dhcp)
+	if err != nil {
+		return nil, err
+	}
+	return network.UpdateDhcp(dhcp)
+}
+
+// buildEndpoint returns the URL of the network followed by suffix, with the API version to use
+func (network *OpenApiOrgVdcNetwork) buildEndpoint(suffix string) (*url.URL, string, error) {
+	if network.OpenApiOrgVdcNetwork.ID == "" {
+		return nil, "", fmt.Errorf("org VDC network %s has no ID", network.OpenApiOrgVdcNetwork.Name)
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointOrgVdcNetworks
+	apiVersion, err := network.client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, "", err
+	}
+	urlRef, err := network.client.OpenApiBuildEndpoint(endpoint, network.OpenApiOrgVdcNetwork.ID, suffix)
+	if err != nil {
+		return nil, "", err
+	}
+	return urlRef, apiVersion, nil
+}
+
+// validateOpenApiOrgVdcNetwork checks the fields needed by each type of org VDC network
+func validateOpenApiOrgVdcNetwork(networkConfig *types.OpenApiOrgVdcNetwork) error {
+	if networkConfig == nil || networkConfig.Name == "" {
+		return fmt.Errorf("org VDC network name is required")
+	}
+	switch networkConfig.NetworkType {
+	case types.OrgVdcNetworkTypeRouted:
+		if networkConfig.Connection == nil || networkConfig.Connection.RouterRef.ID == "" {
+			return fmt.Errorf("routed network %s needs an edge gateway in Connection.RouterRef", networkConfig.Name)
+		}
+	case types.OrgVdcNetworkTypeImported:
+		if networkConfig.BackingNetworkId == "" {
+			return fmt.Errorf("imported network %s needs the ID of an NSX-T segment in BackingNetworkId", networkConfig.Name)
+		}
+	case types.OrgVdcNetworkTypeIsolated:
+	default:
+		return fmt.Errorf("unsupported type '%s' for org VDC network %s", networkConfig.NetworkType, networkConfig.Name)
+	}
+	if len(networkConfig.Subnets.Values) == 0 {
+		return fmt.Errorf("org VDC network %s needs a subnet", networkConfig.Name)
+	}
+	for _, subnet := range networkConfig.Subnets.Values {
+		if subnet.Gateway == "" || subnet.PrefixLength == 0 {
+			return fmt.Errorf("subnets of org VDC network %s need gateway and prefix length", networkConfig.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// Checks the creation, lookup and DHCP configuration of an NSX-T routed network against a fake vCD
+func TestOpenApiOrgVdcNetwork_Routed(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	const networkId = "urn:vcloud:network:66666666-6666-6666-6666-666666666666"
+	const edgeId = "urn:vcloud:gateway:33333333-3333-3333-3333-333333333333"
+	const networksPath = "/cloudapi/1.0.0/orgVdcNetworks/"
+	const vdcUrn = "urn:vcloud:vdc:" + vcdtest.MockVdcId
+	networkJson := `{"id":"` + networkId + `","name":"net1","description":"","networkType":"NAT_ROUTED",
+		"orgVdc":{"name":"` + vcdtest.MockVdcName + `","id":"` + vdcUrn + `"},
+		"connection":{"routerRef":{"id":"` + edgeId + `"},"connectionType":"INTERNAL"},
+		"subnets":{"values":[{"gateway":"192.168.1.1","prefixLength":24,"dnsServer1":"8.8.8.8","dnsServer2":"","dnsSuffix":"example.com",
+		"ipRanges":{"values":[{"startAddress":"192.168.1.10","endAddress":"192.168.1.20"}]}}]}}`
+	server.HandleXML(http.MethodGet, vcdtest.MockTaskPath, http.StatusOK,
+		`<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="success" href="{{server}}`+vcdtest.MockTaskPath+`">
+		  <Owner type="application/json" name="net1" id="`+networkId+`" href="{{server}}`+networksPath+networkId+`"/>
+		</Task>`)
+	server.Handle(http.MethodPost, networksPath, vcdtest.Response{
+		Status: http.StatusAccepted,
+		Header: map[string]string{"Location": "{{server}}" + vcdtest.MockTaskPath},
+	})
+	server.HandleJSON(http.MethodGet, networksPath+networkId, http.StatusOK, networkJson)
+	server.HandleJSON(http.MethodGet, networksPath, http.StatusOK,
+		`{"resultTotal":1,"pageCount":1,"page":1,"pageSize":128,"values":[`+networkJson+`]}`)
+	server.HandleJSON(http.MethodPut, networksPath+networkId+"/dhcp", http.StatusOK,
+		`{"enabled":true,"leaseTime":86400,"dnsServers":["8.8.8.8"],
+		"dhcpPools":[{"enabled":true,"ipRange":{"startAddress":"192.168.1.100","endAddress":"192.168.1.150"}}]}`)
+	server.HandleJSON(http.MethodGet, networksPath+networkId+"/dhcp", http.StatusOK,
+		`{"enabled":true,"leaseTime":86400,"mode":"EDGE",
+		"dhcpPools":[{"enabled":true,"ipRange":{"startAddress":"192.168.1.100","endAddress":"192.168.1.150"}}]}`)
+
+	vcdClient := newMockClient(t, server)
+	org, err := GetOrgByName(vcdClient, vcdtest.MockOrgName)
+	if err != nil {
+		t.Fatalf("error retrieving org: %s", err)
+	}
+	vdc, err := org.GetVdcByName(vcdtest.MockVdcName)
+	if err != nil {
+		t.Fatalf("error retrieving vdc: %s", err)
+	}
+
+	config := &types.OpenApiOrgVdcNetwork{
+		Name:        "net1",
+		NetworkType: types.OrgVdcNetworkTypeRouted,
+		Connection: &types.OpenApiOrgVdcNetworkConnection{
+			RouterRef:      types.OpenApiReference{ID: edgeId},
+			ConnectionType: "INTERNAL",
+		},
+		Subnets: types.OpenApiOrgVdcNetworkSubnets{Values: []types.OpenApiOrgVdcNetworkSubnet{{
+			Gateway:      "192.168.1.1",
+			PrefixLength: 24,
+			DNSServer1:   "8.8.8.8",
+			DNSSuffix:    "example.com",
+			IPRanges: types.OpenApiIPRanges{Values: []types.OpenApiIPRange{
+				{StartAddress: "192.168.1.10", EndAddress: "192.168.1.20"},
+			}},
+		}}},
+	}
+
+	// A routed network needs an edge gateway, an imported network needs an NSX-T segment
+	_, err = vdc.CreateOpenApiOrgVdcNetwork(&types.OpenApiOrgVdcNetwork{Name: "net1",
+		NetworkType: types.OrgVdcNetworkTypeRouted, Subnets: config.Subnets})
+	if err == nil {
+		t.Errorf("expected error creating routed network without edge gateway")
+	}
+	_, err = vdc.CreateOpenApiOrgVdcNetwork(&types.OpenApiOrgVdcNetwork{Name: "net1",
+		NetworkType: types.OrgVdcNetworkTypeImported, Subnets: config.Subnets})
+	if err == nil {
+		t.Errorf("expected error creating imported network without backing network")
+	}
+
+	network, err := vdc.CreateOpenApiOrgVdcNetwork(config)
+	if err != nil {
+		t.Fatalf("error creating network: %s", err)
+	}
+	if network.OpenApiOrgVdcNetwork.ID != networkId || !network.IsRouted() || network.IsIsolated() || network.IsImported() {
+		t.Errorf("unexpected network: %#v", network.OpenApiOrgVdcNetwork)
+	}
+	posts := server.RequestsTo(http.MethodPost, networksPath)
+	if len(posts) != 1 {
+		t.Fatalf("expected one POST request, got %d", len(posts))
+	}
+	sent := types.OpenApiOrgVdcNetwork{}
+	err = json.Unmarshal([]byte(posts[0].Body), &sent)
+	if err != nil {
+		t.Fatalf("error decoding payload: %s", err)
+	}
+	if sent.OrgVdc == nil || sent.OrgVdc.ID != vdcUrn || sent.OwnerRef != nil {
+		t.Errorf("expected the VDC as owner of the network, got payload %s", posts[0].Body)
+	}
+
+	byName, err := vdc.GetOpenApiOrgVdcNetworkByName("net1")
+	if err != nil {
+		t.Fatalf("error retrieving network by name: %s", err)
+	}
+	if byName.OpenApiOrgVdcNetwork.Subnets.Values[0].DNSSuffix != "example.com" {
+		t.Errorf("unexpected network: %#v", byName.OpenApiOrgVdcNetwork)
+	}
+	gets := server.RequestsTo(http.MethodGet, networksPath)
+	query, _ := url.ParseQuery(gets[len(gets)-1].RawQuery)
+	if query.Get("filter") != "name==net1;orgVdc.id=="+vdcUrn {
+		t.Errorf("unexpected filter: %s", query.Get("filter"))
+	}
+
+	enabled := true
+	dhcp, err := network.UpdateDhcp(&types.OpenApiOrgVdcNetworkDhcp{
+		Enabled:    &enabled,
+		DnsServers: []string{"8.8.8.8"},
+		DhcpPools: []types.OpenApiOrgVdcNetworkDhcpPool{{
+			Enabled: &enabled,
+			IPRange: types.OpenApiIPRange{StartAddress: "192.168.1.100", EndAddress: "192.168.1.150"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("error updating DHCP: %s", err)
+	}
+	if len(dhcp.DhcpPools) != 1 || dhcp.DhcpPools[0].IPRange.EndAddress != "192.168.1.150" {
+		t.Errorf("unexpected DHCP: %#v", dhcp)
+	}
+	puts := server.RequestsTo(http.MethodPut, networksPath+networkId+"/dhcp")
+	if len(puts) != 1 || !strings.Contains(puts[0].Body, `"startAddress": "192.168.1.100"`) {
+		t.Errorf("unexpected DHCP requests: %#v", puts)
+	}
+	_, err = network.UpdateDhcp(&types.OpenApiOrgVdcNetworkDhcp{
+		DhcpPools: []types.OpenApiOrgVdcNetworkDhcpPool{{IPRange: types.OpenApiIPRange{StartAddress: "192.168.1.100"}}},
+	})
+	if err == nil {
+		t.Errorf("expected error setting DHCP pool without end address")
+	}
+
+	// The DHCP service is modified keeping the rest of its configuration
+	server.ClearRequests()
+	_, err = network.DisableDhcp()
+	if err != nil {
+		t.Fatalf("error disabling DHCP: %s", err)
+	}
+	_, err = network.SetDhcpLeaseTime(3600)
+	if err != nil {
+		t.Fatalf("error setting DHCP lease time: %s", err)
+	}
+	puts = server.RequestsTo(http.MethodPut, networksPath+networkId+"/dhcp")
+	if len(puts) != 2 || !strings.Contains(puts[0].Body, `"enabled": false`) || !strings.Contains(puts[0].Body, `"192.168.1.100"`) ||
+		!strings.Contains(puts[1].Body, `"leaseTime": 3600`) || !strings.Contains(puts[1].Body, `"mode": "EDGE"`) {
+		t.Errorf("unexpected DHCP requests: %#v", puts)
+	}
+	if _, err = network.SetDhcpLeaseTime(30); err == nil {
+		t.Errorf("expected error setting DHCP lease time shorter than a minute")
+	}
+	isolated := &OpenApiOrgVdcNetwork{
+		OpenApiOrgVdcNetwork: &types.OpenApiOrgVdcNetwork{ID: networkId, Name: "net1", NetworkType: types.OrgVdcNetworkTypeIsolated},
+		client:               network.client,
+	}
+	if _, err = isolated.EnableDhcp(); err == nil {
+		t.Errorf("expected error enabling DHCP in EDGE mode on an isolated network")
+	}
+	if _, err = network.EnableDhcp(); err != nil {
+		t.Errorf("error enabling DHCP: %s", err)
+	}
+	if puts = server.RequestsTo(http.MethodPut, networksPath+networkId+"/dhcp"); len(puts) != 3 {
+		t.Errorf("expected 3 DHCP updates, got %d", len(puts))
+	}
+}
+
+// Checks that DHCPv6 is only enabled on a routed IPv6 network whose edge gateway assigns the
+// addresses through DHCPv6
+func TestOpenApiOrgVdcNetwork_EnableDhcpv6(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	const networkId = "urn:vcloud:network:66666666-6666-6666-6666-666666666666"
+	const edgeId = "urn:vcloud:gateway:33333333-3333-3333-3333-333333333333"
+	const dhcpPath = "/cloudapi/1.0.0/orgVdcNetworks/" + networkId + "/dhcp"
+	const profilePath = "/cloudapi/1.0.0/edgeGateways/" + edgeId + "/slaacProfile"
+	server.HandleJSON(http.MethodGet, profilePath, http.StatusOK, `{"enabled":true,"mode":"SLAAC"}`)
+	server.HandleJSON(http.MethodGet, dhcpPath, http.StatusOK, `{"enabled":false,"leaseTime":86400,"mode":"EDGE"}`)
+	server.HandleJSON(http.MethodPut, dhcpPath, http.StatusOK,
+		`{"enabled":true,"leaseTime":86400,"mode":"EDGE","dnsServers":["2001:db8::53"],
+		"dhcpPools":[{"ipRange":{"startAddress":"2001:db8:1::100","endAddress":"2001:db8:1::1ff"}}]}`)
+
+	vcdClient := newMockClient(t, server)
+	network := &OpenApiOrgVdcNetwork{
+		OpenApiOrgVdcNetwork: &types.OpenApiOrgVdcNetwork{
+			ID:          networkId,
+			Name:        "net6",
+			NetworkType: types.OrgVdcNetworkTypeRouted,
+			Connection:  &types.OpenApiOrgVdcNetworkConnection{RouterRef: types.OpenApiReference{ID: edgeId}},
+			Subnets: types.OpenApiOrgVdcNetworkSubnets{Values: []types.OpenApiOrgVdcNetworkSubnet{
+				{Gateway: "192.168.1.1", PrefixLength: 24},
+				{Gateway: "2001:db8:1::1", PrefixLength: 64},
+			}},
+		},
+		client: &vcdClient.Client,
+	}
+	pools := []types.OpenApiOrgVdcNetworkDhcpPool{
+		{IPRange: types.OpenApiIPRange{StartAddress: "2001:db8:1::100", EndAddress: "2001:db8:1::1ff"}},
+	}
+	dnsServers := []string{"2001:db8::53"}
+
+	invalid := map[string][]types.OpenApiOrgVdcNetworkDhcpPool{
+		"no pool":           nil,
+		"IPv4 pool":         {{IPRange: types.OpenApiIPRange{StartAddress: "192.168.1.100", EndAddress: "192.168.1.150"}}},
+		"pool out of range": {{IPRange: types.OpenApiIPRange{StartAddress: "2001:db8:2::100", EndAddress: "2001:db8:2::1ff"}}},
+	}
+	for name, invalidPools := range invalid {
+		if _, err := network.EnableDhcpv6(invalidPools, dnsServers); err == nil {
+			t.Errorf("expected error enabling DHCPv6 with %s", name)
+		}
+	}
+	if _, err := network.EnableDhcpv6(pools, []string{"8.8.8.8"}); err == nil {
+		t.Errorf("expected error enabling DHCPv6 with an IPv4 DNS server")
+	}
+
+	// The edge gateway assigns the addresses through SLAAC
+	if _, err := network.EnableDhcpv6(pools, dnsServers); err == nil {
+		t.Errorf("expected error enabling DHCPv6 with the SLAAC profile in SLAAC mode")
+	}
+	if len(server.RequestsTo(http.MethodPut, dhcpPath)) != 0 {
+		t.Errorf("unexpected DHCP update with an invalid configuration")
+	}
+
+	server.HandleJSON(http.MethodGet, profilePath, http.StatusOK, `{"enabled":true,"mode":"DHCPv6"}`)
+	dhcp, err := network.EnableDhcpv6(pools, dnsServers)
+	if err != nil {
+		t.Fatalf("error enabling DHCPv6: %s", err)
+	}
+	if dhcp.Enabled == nil || !*dhcp.Enabled || len(dhcp.DnsServers) != 1 {
+		t.Errorf("unexpected DHCP: %#v", dhcp)
+	}
+	puts := server.RequestsTo(http.MethodPut, dhcpPath)
+	if len(puts) != 1 || !strings.Contains(puts[0].Body, `"startAddress": "2001:db8:1::100"`) ||
+		!strings.Contains(puts[0].Body, `"leaseTime": 86400`) || !strings.Contains(puts[0].Body, `"2001:db8::53"`) {
+		t.Errorf("unexpected DHCP updates: %#v", puts)
+	}
+
+	ipv4Only := &OpenApiOrgVdcNetwork{
+		OpenApiOrgVdcNetwork: &types.OpenApiOrgVdcNetwork{ID: networkId, Name: "net4", NetworkType: types.OrgVdcNetworkTypeRouted,
+			Connection: network.OpenApiOrgVdcNetwork.Connection,
+			Subnets:    types.OpenApiOrgVdcNetworkSubnets{Values: network.OpenApiOrgVdcNetwork.Subnets.Values[:1]}},
+		client: network.client,
+	}
+	if _, err = ipv4Only.EnableDhcpv6(pools, dnsServers); err == nil {
+		t.Errorf("expected error enabling DHCPv6 on a network without IPv6 subnet")
+	}
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// Checks that OpenApiGetAllItems follows all the pages of a response, using a local test server
+func TestClient_OpenApiGetAllItems(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	server.HandleFunc(http.MethodGet, "/cloudapi/1.0.0/items/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "application/json;version=33.0" {
+			w.WriteHeader(http.StatusNotAcceptable)
+			return
+		}
+		page := r.URL.Query().Get("page")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"resultTotal":3,"pageCount":2,"page":%s,"pageSize":2,"values":[{"name":"item%s-a"}%s]}`,
+			page, page, map[string]string{"1": `,{"name":"item1-b"}`, "2": ""}[page])
+	})
+
+	client := &newMockClient(t, server).Client
+	urlRef, err := client.OpenApiBuildEndpoint("1.0.0/", "items/")
+	if err != nil {
+		t.Fatalf("error building endpoint: %s", err)
+	}
+	if urlRef.String() != server.URL()+"/cloudapi/1.0.0/items/" {
+		t.Errorf("unexpected endpoint: %s", urlRef.String())
+	}
+
+	var items []struct {
+		Name string `json:"name"`
+	}
+	err = client.OpenApiGetAllItems("33.0", urlRef, nil, &items)
+	if err != nil {
+		t.Fatalf("error retrieving items: %s", err)
+	}
+	if len(items) != 3 || items[0].Name != "item1-a" || items[2].Name != "item2-a" {
+		t.Errorf("unexpected items: %#v", items)
+	}
+}
+
+// Checks that errors returned by OpenAPI endpoints are parsed from the JSON body
+func TestClient_OpenApiError(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	server.HandleJSON(http.MethodPost, "/cloudapi/1.0.0/items/", http.StatusBadRequest,
+		`{"minorErrorCode":"BAD_REQUEST","message":"[ abc ] name is invalid"}`)
+
+	client := &newMockClient(t, server).Client
+	urlRef, _ := client.OpenApiBuildEndpoint("1.0.0/", "items/")
+
+	err := client.OpenApiPostItem("33.0", urlRef, nil, map[string]string{"name": "abc"}, nil)
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+	expected := "error in HTTP POST request: API Error: 400: BAD_REQUEST - [ abc ] name is invalid"
+	if err.Error() != expected {
+		t.Errorf("expected error '%s', got '%s'", expected, err)
+	}
+}
+
+// Checks that OpenApiGetAllItems follows the "nextPage" links of the responses, which keep the
+// query of the first request
+func TestClient_OpenApiGetAllItemsLinkHeader(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	server.HandleFunc(http.MethodGet, "/cloudapi/1.0.0/items/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("cursor") == "" {
+			w.Header().Add("Link", `<`+server.URL()+`/cloudapi/1.0.0/items/>;rel="up";type="application/json"`)
+			w.Header().Add("Link", `<`+server.URL()+`/cloudapi/1.0.0/items/?cursor=abc&filter=name%3D%3Da%2Cb>;rel="nextPage";type="application/json"`)
+			_, _ = fmt.Fprint(w, `{"resultTotal":2,"pageCount":0,"values":[{"name":"first"}]}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"resultTotal":2,"pageCount":0,"values":[{"name":"second"}]}`)
+	})
+
+	client := &newMockClient(t, server).Client
+	urlRef, _ := client.OpenApiBuildEndpoint("1.0.0/", "items/")
+
+	var items []struct {
+		Name string `json:"name"`
+	}
+	err := client.OpenApiGetAllItems("33.0", urlRef, url.Values{"filter": []string{fiqlEq("name", "a,b")}}, &items)
+	if err != nil {
+		t.Fatalf("error retrieving items: %s", err)
+	}
+	if len(items) != 2 || items[0].Name != "first" || items[1].Name != "second" {
+		t.Errorf("unexpected items: %#v", items)
+	}
+	queries := server.RequestsTo(http.MethodGet, "/cloudapi/1.0.0/items/")
+	if len(queries) != 2 || queries[1].RawQuery != "cursor=abc&filter=name%3D%3Da%2Cb" {
+		t.Errorf("unexpected queries: %#v", queries)
+	}
+}
+
+func TestFiqlFilters(t *testing.T) {
+	if filter := fiqlEq("name", "a;b,c(d)%"); filter != "name==a%3Bb%2Cc%28d%29%25" {
+		t.Errorf("unexpected escaped filter: %s", filter)
+	}
+
+	params := queryParameterFilterAnd("type==user", nil)
+	if params.Get("filter") != "type==user" {
+		t.Errorf("unexpected filter: %s", params.Get("filter"))
+	}
+
+	original := url.Values{"filter": []string{"name==abc"}, "pageSize": []string{"10"}}
+	params = queryParameterFilterAnd("type==user", original)
+	if params.Get("filter") != "name==abc;type==user" || params.Get("pageSize") != "10" {
+		t.Errorf("unexpected parameters: %v", params)
+	}
+	if original.Get("filter") != "name==abc" {
+		t.Errorf("original parameters were modified: %v", original)
+	}
+}
+
+func TestClient_getOpenApiHighestElevatedVersion(t *testing.T) {
+	endpoint := "1.0.0/roles/"
+	minimumVersion, err := getOpenApiVersion(endpoint)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	for clientVersion, expected := range map[string]string{
+		"":             minimumVersion,
+		"27.0":         minimumVersion,
+		minimumVersion: minimumVersion,
+		"99.0":         "99.0",
+	} {
+		client := &Client{APIVersion: clientVersion}
+		version, err := client.getOpenApiHighestElevatedVersion(endpoint)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		if version != expected {
+			t.Errorf("client version %q: expected %s, got %s", clientVersion, expected, version)
+		}
+	}
+	if _, err := (&Client{}).getOpenApiHighestElevatedVersion("1.0.0/unknown/"); err == nil {
+		t.Errorf("expected error for unknown endpoint")
+	}
+}
@@ -249,11 +249,26 @@ func validateVdcConfiguration(vdcDefinition *types.VdcConfiguration) error {
 	if vdcDefinition.ComputeCapacity[0].Memory.Units == "" {
 		return errors.New("VdcConfiguration missing required field: ComputeCapacity[0].Memory.Units")
 	}
-	if vdcDefinition.VdcStorageProfile == nil {
+	if len(vdcDefinition.VdcStorageProfile) == 0 {
 		return errors.New("VdcConfiguration missing required field: VdcStorageProfile")
 	}
-	if vdcDefinition.VdcStorageProfile.Units == "" {
-		return errors.New("VdcConfiguration missing required field: VdcStorageProfile.Units")
+	defaultStorageProfiles := 0
+	for i, storageProfile := range vdcDefinition.VdcStorageProfile {
+		if storageProfile == nil {
+			return fmt.Errorf("VdcConfiguration missing required field: VdcStorageProfile[%d]", i)
+		}
+		if storageProfile.Units == "" {
+			return fmt.Errorf("VdcConfiguration missing required field: VdcStorageProfile[%d].Units", i)
+		}
+		if storageProfile.ProviderVdcStorageProfile == nil || storageProfile.ProviderVdcStorageProfile.HREF == "" {
+			return fmt.Errorf("VdcConfiguration missing required field: VdcStorageProfile[%d].ProviderVdcStorageProfile.HREF", i)
+		}
+		if storageProfile.Default {
+			defaultStorageProfiles++
+		}
+	}
+	if defaultStorageProfiles != 1 {
+		return errors.New("VdcConfiguration invalid field: exactly one VdcStorageProfile must be the default")
 	}
 	if vdcDefinition.ProviderVdcReference == nil {
 		return errors.New("VdcConfiguration missing required field: ProviderVdcReference")
@@ -261,17 +276,37 @@ func validateVdcConfiguration(vdcDefinition *types.VdcConfiguration) error {
 	if vdcDefinition.ProviderVdcReference.HREF == "" {
 		return errors.New("VdcConfiguration missing required field: ProviderVdcReference.HREF")
 	}
+	if vdcDefinition.NetworkPoolReference != nil && vdcDefinition.NetworkPoolReference.HREF == "" {
+		return errors.New("VdcConfiguration missing required field: NetworkPoolReference.HREF")
+	}
+	switch vdcDefinition.AllocationModel {
+	case types.VdcAllocationModelAllocationVApp, types.VdcAllocationModelAllocationPool,
+		types.VdcAllocationModelReservationPool:
+		if vdcDefinition.IsElastic != nil || vdcDefinition.IncludeMemoryOverhead != nil {
+			return errors.New("VdcConfiguration invalid field: IsElastic and IncludeMemoryOverhead are only used by the Flex allocation model")
+		}
+	case types.VdcAllocationModelFlex:
+	default:
+		return fmt.Errorf("VdcConfiguration invalid field: unknown AllocationModel '%s'", vdcDefinition.AllocationModel)
+	}
 	return nil
 }
 
 // CreateVdc creates a VDC with the given params under the given organization.
-// Returns an AdminVdc.
+// The allocation model can be one of types.VdcAllocationModelAllocationVApp, AllocationPool,
+// ReservationPool or, when the client uses API 32.0+, Flex.
+// Every storage profile needs a limit (0 means unlimited) and exactly one must be the default.
+// Returns the creation task.
 // API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/POST-VdcConfiguration.html
 func (org *AdminOrg) CreateVdc(vdcConfiguration *types.VdcConfiguration) (Task, error) {
 	err := validateVdcConfiguration(vdcConfiguration)
 	if err != nil {
 		return Task{}, err
 	}
+	if vdcConfiguration.AllocationModel == types.VdcAllocationModelFlex && !org.client.APIClientVersionIs(">= 32.0") {
+		return Task{}, fmt.Errorf("the Flex allocation model requires API version 32.0 or newer, the client uses %s",
+			org.client.APIVersion)
+	}
 
 	vdcCreateHREF, err := url.ParseRequestURI(org.AdminOrg.HREF)
 	if err != nil {
@@ -282,7 +317,7 @@ func (org *AdminOrg) CreateVdc(vdcConfiguration *types.VdcConfiguration) (Task,
 	adminVdc := NewAdminVdc(org.client)
 
 	_, err = org.client.ExecuteRequest(vdcCreateHREF.String(), http.MethodPost,
-		"application/vnd.vmware.admin.createVdcParams+xml", "error retrieving vdc: %s", vdcConfiguration, adminVdc.AdminVdc)
+		types.MimeCreateVdcParams, "error creating vdc: %s", vdcConfiguration, adminVdc.AdminVdc)
 	if err != nil {
 		return Task{}, err
 	}
@@ -369,6 +404,18 @@ func (adminOrg *AdminOrg) Disable() error {
 	return adminOrg.client.ExecuteRequestWithoutResponse(orgHREF.String(), http.MethodPost, "", "error disabling organization: %s", nil)
 }
 
+// Enables the org. Returns an error if the call to vCD fails.
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/POST-EnableOrg.html
+func (adminOrg *AdminOrg) Enable() error {
+	orgHREF, err := url.ParseRequestURI(adminOrg.AdminOrg.HREF)
+	if err != nil {
+		return fmt.Errorf("error getting AdminOrg HREF %s : %v", adminOrg.AdminOrg.HREF, err)
+	}
+	orgHREF.Path += "/action/enable"
+
+	return adminOrg.client.ExecuteRequestWithoutResponse(orgHREF.String(), http.MethodPost, "", "error enabling organization: %s", nil)
+}
+
 //   Updates the Org definition from current org struct contents.
 //   Any differences that may be legally applied will be updated.
 //   Returns an error if the call to vCD fails.
@@ -379,6 +426,7 @@ func (adminOrg *AdminOrg) Update() (Task, error) {
 		Name:        adminOrg.AdminOrg.Name,
 		IsEnabled:   adminOrg.AdminOrg.IsEnabled,
 		FullName:    adminOrg.AdminOrg.FullName,
+		Description: adminOrg.AdminOrg.Description,
 		OrgSettings: adminOrg.AdminOrg.OrgSettings,
 	}
 
@@ -6,6 +6,7 @@ package govcd
 
 import (
 	"fmt"
+	"testing"
 	"time"
 
 	"github.com/vmware/go-vcloud-director/v2/types/v56"
@@ -117,6 +118,7 @@ func (vcd *TestVCD) Test_UpdateOrg(check *C) {
 	check.Assert(org.AdminOrg.Name, Equals, TestUpdateOrg)
 	check.Assert(org.AdminOrg.Description, Equals, TestUpdateOrg)
 	org.AdminOrg.OrgSettings.OrgGeneralSettings.DeployedVMQuota = 100
+	org.AdminOrg.Description = TestUpdateOrg + "_updated"
 	task, err = org.Update()
 	check.Assert(err, IsNil)
 	// Wait until update is complete
@@ -126,6 +128,18 @@ func (vcd *TestVCD) Test_UpdateOrg(check *C) {
 	err = org.Refresh()
 	check.Assert(err, IsNil)
 	check.Assert(org.AdminOrg.OrgSettings.OrgGeneralSettings.DeployedVMQuota, Equals, 100)
+	check.Assert(org.AdminOrg.Description, Equals, TestUpdateOrg+"_updated")
+	// Disable and enable again
+	err = org.Disable()
+	check.Assert(err, IsNil)
+	err = org.Refresh()
+	check.Assert(err, IsNil)
+	check.Assert(org.AdminOrg.IsEnabled, Equals, false)
+	err = org.Enable()
+	check.Assert(err, IsNil)
+	err = org.Refresh()
+	check.Assert(err, IsNil)
+	check.Assert(org.AdminOrg.IsEnabled, Equals, true)
 	// Delete, with force and recursive true
 	err = org.Delete(true, true)
 	check.Assert(err, IsNil)
@@ -238,7 +252,11 @@ func (vcd *TestVCD) Test_CreateVdc(check *C) {
 	}
 	networkPoolHref := results.Results.NetworkPoolRecord[0].HREF
 
-	allocationModels := []string{"AllocationVApp", "AllocationPool", "ReservationPool"}
+	allocationModels := []string{types.VdcAllocationModelAllocationVApp, types.VdcAllocationModelAllocationPool,
+		types.VdcAllocationModelReservationPool}
+	if vcd.client.APIClientVersionIs(">= 32.0") {
+		allocationModels = append(allocationModels, types.VdcAllocationModelFlex)
+	}
 	for i, allocationModel := range allocationModels {
 		vdcConfiguration := &types.VdcConfiguration{
 			Name:            fmt.Sprintf("%s%d", TestCreateOrgVdc, i),
@@ -257,13 +275,15 @@ func (vcd *TestVCD) Test_CreateVdc(check *C) {
 					},
 				},
 			},
-			VdcStorageProfile: &types.VdcStorageProfile{
-				Enabled: true,
-				Units:   "MB",
-				Limit:   1024,
-				Default: true,
-				ProviderVdcStorageProfile: &types.Reference{
-					HREF: providerVdcStorageProfileHref,
+			VdcStorageProfile: []*types.VdcStorageProfile{
+				&types.VdcStorageProfile{
+					Enabled: true,
+					Units:   "MB",
+					Limit:   1024,
+					Default: true,
+					ProviderVdcStorageProfile: &types.Reference{
+						HREF: providerVdcStorageProfileHref,
+					},
 				},
 			},
 			NetworkPoolReference: &types.Reference{
@@ -306,7 +326,17 @@ func (vcd *TestVCD) Test_CreateVdc(check *C) {
 		check.Assert(vdc.Vdc.IsEnabled, Equals, vdcConfiguration.IsEnabled)
 		check.Assert(vdc.Vdc.AllocationModel, Equals, vdcConfiguration.AllocationModel)
 
-		err = vdc.DeleteWait(true, true)
+		adminVdc, err := adminOrg.GetAdminVdcByName(vdcConfiguration.Name)
+		check.Assert(err, IsNil)
+		check.Assert(adminVdc.AdminVdc.ProviderVdcReference.HREF, Equals, providerVdcHref)
+		adminVdc.AdminVdc.Description = "updated description"
+		adminVdc.AdminVdc.NicQuota = 10
+		err = adminVdc.UpdateWait()
+		check.Assert(err, IsNil)
+		check.Assert(adminVdc.AdminVdc.Description, Equals, "updated description")
+		check.Assert(adminVdc.AdminVdc.NicQuota, Equals, 10)
+
+		err = adminVdc.DeleteWait(true, true)
 		check.Assert(err, IsNil)
 
 		err = adminOrg.Refresh()
@@ -417,3 +447,61 @@ func (vcd *TestVCD) Test_GetAdminCatalog(check *C) {
 		check.Assert(cat.AdminCatalog.Description, Equals, vcd.config.VCD.Catalog.Description)
 	}
 }
+
+// Checks the validation of storage profiles and allocation models in VDC configurations
+func TestValidateVdcConfiguration(t *testing.T) {
+	newConfiguration := func(allocationModel string, defaults ...bool) *types.VdcConfiguration {
+		vdcConfiguration := &types.VdcConfiguration{
+			Xmlns:           types.XMLNamespaceVCloud,
+			Name:            "vdc",
+			AllocationModel: allocationModel,
+			ComputeCapacity: []*types.ComputeCapacity{
+				&types.ComputeCapacity{
+					CPU:    &types.CapacityWithUsage{Units: "MHz"},
+					Memory: &types.CapacityWithUsage{Units: "MB"},
+				},
+			},
+			ProviderVdcReference: &types.Reference{HREF: "https://vcd/api/admin/providervdc/1"},
+		}
+		for _, isDefault := range defaults {
+			vdcConfiguration.VdcStorageProfile = append(vdcConfiguration.VdcStorageProfile, &types.VdcStorageProfile{
+				Units:                     "MB",
+				Default:                   isDefault,
+				ProviderVdcStorageProfile: &types.Reference{HREF: "https://vcd/api/admin/pvdcStorageProfile/1"},
+			})
+		}
+		return vdcConfiguration
+	}
+	elastic := true
+
+	flex := newConfiguration(types.VdcAllocationModelFlex, true, false)
+	flex.IsElastic = &elastic
+	pool := newConfiguration(types.VdcAllocationModelAllocationPool, true)
+	pool.IsElastic = &elastic
+	noNetworkPoolHref := newConfiguration(types.VdcAllocationModelAllocationPool, true)
+	noNetworkPoolHref.NetworkPoolReference = &types.Reference{Name: "pool"}
+
+	tests := []struct {
+		name          string
+		configuration *types.VdcConfiguration
+		valid         bool
+	}{
+		{"one default storage profile", newConfiguration(types.VdcAllocationModelAllocationVApp, false, true), true},
+		{"flex with elasticity", flex, true},
+		{"no storage profiles", newConfiguration(types.VdcAllocationModelAllocationVApp), false},
+		{"no default storage profile", newConfiguration(types.VdcAllocationModelReservationPool, false), false},
+		{"two default storage profiles", newConfiguration(types.VdcAllocationModelReservationPool, true, true), false},
+		{"elasticity outside of flex", pool, false},
+		{"unknown allocation model", newConfiguration("Unknown", true), false},
+		{"network pool without HREF", noNetworkPoolHref, false},
+	}
+	for _, test := range tests {
+		err := validateVdcConfiguration(test.configuration)
+		if test.valid && err != nil {
+			t.Errorf("%s: unexpected error: %s", test.name, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("%s: expected error, got nil", test.name)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/util"
+)
+
+// Org associations link organizations of different vCD sites (multisite). The association data of
+// the local org is exported, imported in the org of the other site, and vice versa.
+// All the functions handling org associations need a client using API 29.0+ (see WithAPIVersion).
+
+// GetLocalAssociationData retrieves the association data of the org, to be imported in the
+// org of another site
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/GET-OrgLocalAssociationData.html
+func (adminOrg *AdminOrg) GetLocalAssociationData() (*types.OrgAssociationMember, error) {
+	href, err := adminOrg.getAssociationsHref("/localAssociationData")
+	if err != nil {
+		return nil, err
+	}
+	member := &types.OrgAssociationMember{}
+	_, err = adminOrg.client.ExecuteRequest(href, http.MethodGet,
+		"", "error retrieving org local association data: %s", nil, member)
+	if err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// ExportLocalAssociationData returns the association data of the org as an XML document, which
+// can be stored in a file and given to the administrator of the other site
+func (adminOrg *AdminOrg) ExportLocalAssociationData() ([]byte, error) {
+	member, err := adminOrg.GetLocalAssociationData()
+	if err != nil {
+		return nil, err
+	}
+	member.Xmlns = types.XMLNamespaceVCloud
+	data, err := xml.MarshalIndent(member, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error encoding org association data: %s", err)
+	}
+	return data, nil
+}
+
+// ParseOrgAssociationData reads the association data of an org exported by ExportLocalAssociationData
+func ParseOrgAssociationData(data []byte) (*types.OrgAssociationMember, error) {
+	member := &types.OrgAssociationMember{}
+	err := xml.Unmarshal(data, member)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding org association data: %s", err)
+	}
+	if member.OrgId == "" || member.SiteId == "" || member.OrgPublicKey == "" {
+		return nil, fmt.Errorf("org association data is incomplete")
+	}
+	return member, nil
+}
+
+// GetAllAssociations retrieves the associations of the org with orgs of other sites
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/GET-OrgAssociations.html
+func (adminOrg *AdminOrg) GetAllAssociations() ([]*types.OrgAssociationMember, error) {
+	href, err := adminOrg.getAssociationsHref("")
+	if err != nil {
+		return nil, err
+	}
+	associations := &types.OrgAssociations{}
+	_, err = adminOrg.client.ExecuteRequest(href, http.MethodGet,
+		"", "error retrieving org associations: %s", nil, associations)
+	if err != nil {
+		return nil, err
+	}
+	return associations.OrgAssociationMember, nil
+}
+
+// CreateAssociation associates the org with the org of another site, described by the association
+// data exported from that site. The association becomes active once the other site has imported
+// the data of this org as well.
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/POST-OrgAssociations.html
+func (adminOrg *AdminOrg) CreateAssociation(member *types.OrgAssociationMember) (Task, error) {
+	if member == nil || member.OrgId == "" || member.SiteId == "" {
+		return Task{}, fmt.Errorf("org association data is incomplete")
+	}
+	util.Logger.Printf("[TRACE] AdminOrg.CreateAssociation - associating org %s with org %s of site %s",
+		adminOrg.AdminOrg.Name, member.OrgName, member.SiteId)
+
+	href, err := adminOrg.getAssociationsHref("")
+	if err != nil {
+		return Task{}, err
+	}
+	payload := *member
+	payload.Xmlns = types.XMLNamespaceVCloud
+	payload.HREF = ""
+	payload.Type = ""
+	payload.Link = nil
+	payload.Status = ""
+	return adminOrg.client.ExecuteTaskRequest(href, http.MethodPost,
+		types.MimeOrgAssociationMember, "error creating org association: %s", &payload)
+}
+
+// CreateAssociationWait associates the org with the org of another site and waits for the task to complete
+func (adminOrg *AdminOrg) CreateAssociationWait(member *types.OrgAssociationMember) error {
+	task, err := adminOrg.CreateAssociation(member)
+	if err != nil {
+		return err
+	}
+	err = task.WaitTaskCompletion()
+	if err != nil {
+		return fmt.Errorf("couldn't finish creating org association %#v", err)
+	}
+	return nil
+}
+
+// RemoveAssociation removes the association of the org with the given member, as returned by
+// GetAllAssociations. The other site must remove its side of the association as well.
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/DELETE-OrgAssociation.html
+func (adminOrg *AdminOrg) RemoveAssociation(member *types.OrgAssociationMember) (Task, error) {
+	if member == nil || member.HREF == "" {
+		return Task{}, fmt.Errorf("cannot remove org association without HREF")
+	}
+	util.Logger.Printf("[TRACE] AdminOrg.RemoveAssociation - removing association of org %s with org %s",
+		adminOrg.AdminOrg.Name, member.OrgName)
+	return adminOrg.client.ExecuteTaskRequest(member.HREF, http.MethodDelete,
+		"", "error removing org association: %s", nil)
+}
+
+// RemoveAssociationWait removes the association of the org with the given member and waits for
+// the task to complete
+func (adminOrg *AdminOrg) RemoveAssociationWait(member *types.OrgAssociationMember) error {
+	task, err := adminOrg.RemoveAssociation(member)
+	if err != nil {
+		return err
+	}
+	err = task.WaitTaskCompletion()
+	if err != nil {
+		return fmt.Errorf("couldn't finish removing org association %#v", err)
+	}
+	return nil
+}
+
+// getAssociationsHref returns the HREF of the org associations, followed by path
+func (adminOrg *AdminOrg) getAssociationsHref(path string) (string, error) {
+	if !adminOrg.client.APIClientVersionIs(">= 29.0") {
+		return "", fmt.Errorf("org associations require API version 29.0 or newer, the client uses %s",
+			adminOrg.client.APIVersion)
+	}
+	href, err := url.ParseRequestURI(adminOrg.AdminOrg.HREF)
+	if err != nil {
+		return "", fmt.Errorf("error parsing org url: %s", err)
+	}
+	href.Path += "/associations" + path
+	return href.String(), nil
+}
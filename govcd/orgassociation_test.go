@@ -0,0 +1,88 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"testing"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// Exports the association data of the test org and lists its associations.
+// Creating an association needs a second site, which the test environment does not provide.
+func (vcd *TestVCD) Test_OrgAssociations(check *C) {
+	if vcd.skipAdminTests {
+		check.Skip(fmt.Sprintf(TestRequiresSysAdminPrivileges, check.TestName()))
+	}
+	if !vcd.client.APIClientVersionIs(">= 29.0") {
+		check.Skip("org associations need a client using API 29.0+")
+	}
+	adminOrg, err := GetAdminOrgByName(vcd.client, vcd.config.VCD.Org)
+	check.Assert(err, IsNil)
+
+	localData, err := adminOrg.GetLocalAssociationData()
+	check.Assert(err, IsNil)
+	check.Assert(localData.OrgName, Equals, vcd.config.VCD.Org)
+	check.Assert(localData.SiteId, Not(Equals), "")
+
+	exported, err := adminOrg.ExportLocalAssociationData()
+	check.Assert(err, IsNil)
+	parsed, err := ParseOrgAssociationData(exported)
+	check.Assert(err, IsNil)
+	check.Assert(parsed.OrgId, Equals, localData.OrgId)
+	check.Assert(parsed.OrgPublicKey, Equals, localData.OrgPublicKey)
+
+	_, err = adminOrg.GetAllAssociations()
+	check.Assert(err, IsNil)
+
+	_, err = adminOrg.RemoveAssociation(&types.OrgAssociationMember{})
+	check.Assert(err, NotNil)
+}
+
+func TestParseOrgAssociationData(t *testing.T) {
+	data := `<OrgAssociationMember xmlns="http://www.vmware.com/vcloud/v1.5">
+  <OrgHref>https://site2/api/org/1</OrgHref>
+  <SiteId>site-2</SiteId>
+  <OrgName>org2</OrgName>
+  <OrgPublicKey>key</OrgPublicKey>
+  <OrgId>1</OrgId>
+</OrgAssociationMember>`
+
+	member, err := ParseOrgAssociationData([]byte(data))
+	if err != nil {
+		t.Fatalf("error parsing association data: %s", err)
+	}
+	if member.OrgName != "org2" || member.SiteId != "site-2" || member.OrgPublicKey != "key" {
+		t.Errorf("unexpected association data: %#v", member)
+	}
+
+	_, err = ParseOrgAssociationData([]byte(`<OrgAssociationMember><OrgName>org2</OrgName></OrgAssociationMember>`))
+	if err == nil {
+		t.Errorf("expected error for incomplete association data")
+	}
+}
+
+func TestAdminOrg_getAssociationsHref(t *testing.T) {
+	adminOrg := &AdminOrg{
+		AdminOrg: &types.AdminOrg{HREF: "https://vcd/api/admin/org/1"},
+		client:   &Client{APIVersion: "27.0"},
+	}
+	_, err := adminOrg.getAssociationsHref("")
+	if err == nil {
+		t.Errorf("expected error with API version 27.0")
+	}
+
+	adminOrg.client.APIVersion = "29.0"
+	href, err := adminOrg.getAssociationsHref("/localAssociationData")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if href != "https://vcd/api/admin/org/1/associations/localAssociationData" {
+		t.Errorf("unexpected associations HREF: %s", href)
+	}
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// GetFederationSettings retrieves the SAML federation settings of the org
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/GET-OrgFederationSettings.html
+func (adminOrg *AdminOrg) GetFederationSettings() (*types.OrgFederationSettings, error) {
+	settings := &types.OrgFederationSettings{}
+	err := adminOrg.getSettings("federation", "error retrieving org federation settings: %s", settings)
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// UpdateFederationSettings sets the SAML federation settings of the org and returns them
+// as stored by vCD
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/PUT-OrgFederationSettings.html
+func (adminOrg *AdminOrg) UpdateFederationSettings(settings *types.OrgFederationSettings) (*types.OrgFederationSettings, error) {
+	if settings == nil {
+		return nil, fmt.Errorf("federation settings must not be nil")
+	}
+	settings.Xmlns = types.XMLNamespaceVCloud
+	updated := &types.OrgFederationSettings{}
+	err := adminOrg.updateSettings("federation", types.MimeOrgFederationSettings,
+		"error updating org federation settings: %s", settings, updated)
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// SetSamlMetadata uploads the metadata document of the SAML identity provider,
+// leaving the other federation settings unchanged
+func (adminOrg *AdminOrg) SetSamlMetadata(metadata string) (*types.OrgFederationSettings, error) {
+	if metadata == "" {
+		return nil, fmt.Errorf("SAML metadata must not be empty")
+	}
+	settings, err := adminOrg.GetFederationSettings()
+	if err != nil {
+		return nil, err
+	}
+	settings.SAMLMetadata = metadata
+	return adminOrg.UpdateFederationSettings(settings)
+}
+
+// SetSamlMetadataFromUrl downloads the metadata document published by the SAML identity provider
+// at metadataUrl and uploads it to vCD. Use it to refresh the metadata after the identity
+// provider has rotated its certificates.
+func (adminOrg *AdminOrg) SetSamlMetadataFromUrl(metadataUrl string) (*types.OrgFederationSettings, error) {
+	_, err := url.ParseRequestURI(metadataUrl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SAML metadata URL %s: %s", metadataUrl, err)
+	}
+	resp, err := adminOrg.client.Http.Get(metadataUrl)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading SAML metadata from %s: %s", metadataUrl, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error downloading SAML metadata from %s: %s", metadataUrl, resp.Status)
+	}
+	metadata, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading SAML metadata from %s: %s", metadataUrl, err)
+	}
+	return adminOrg.SetSamlMetadata(string(metadata))
+}
+
+// SetSamlEntityId sets the entity ID that vCD uses as SAML service provider for this org.
+// Requires API version 29.0 or later.
+func (adminOrg *AdminOrg) SetSamlEntityId(entityId string) (*types.OrgFederationSettings, error) {
+	settings, err := adminOrg.GetFederationSettings()
+	if err != nil {
+		return nil, err
+	}
+	settings.SamlSPEntityId = entityId
+	return adminOrg.UpdateFederationSettings(settings)
+}
+
+// EnableFederation enables SAML authentication for the org. The identity provider
+// metadata must have been uploaded already.
+func (adminOrg *AdminOrg) EnableFederation() error {
+	return adminOrg.toggleFederation(true)
+}
+
+// DisableFederation disables SAML authentication for the org
+func (adminOrg *AdminOrg) DisableFederation() error {
+	return adminOrg.toggleFederation(false)
+}
+
+func (adminOrg *AdminOrg) toggleFederation(enabled bool) error {
+	settings, err := adminOrg.GetFederationSettings()
+	if err != nil {
+		return err
+	}
+	if enabled && settings.SAMLMetadata == "" {
+		return fmt.Errorf("cannot enable federation for org %s without SAML metadata", adminOrg.AdminOrg.Name)
+	}
+	settings.Enabled = enabled
+	_, err = adminOrg.UpdateFederationSettings(settings)
+	return err
+}
+
+// RegenerateFederationCertificate generates a new certificate for vCD acting as SAML service
+// provider for this org. The service provider metadata must then be uploaded again to the
+// identity provider.
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/POST-RegenerateFederationCertificate.html
+func (adminOrg *AdminOrg) RegenerateFederationCertificate() error {
+	href, err := adminOrg.getSettingsHref("federation")
+	if err != nil {
+		return err
+	}
+	return adminOrg.client.ExecuteRequestWithoutResponse(href+"/action/regenerateFederationCertificate",
+		http.MethodPost, "", "error regenerating federation certificate: %s", nil)
+}
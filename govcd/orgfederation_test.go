@@ -0,0 +1,34 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+
+	. "gopkg.in/check.v1"
+)
+
+// Reads the federation settings of the test org, checks that federation can't be
+// enabled without metadata and sets the same settings back
+func (vcd *TestVCD) Test_OrgFederationSettings(check *C) {
+	if vcd.skipAdminTests {
+		check.Skip(fmt.Sprintf(TestRequiresSysAdminPrivileges, check.TestName()))
+	}
+	adminOrg, err := GetAdminOrgByName(vcd.client, vcd.config.VCD.Org)
+	check.Assert(err, IsNil)
+
+	settings, err := adminOrg.GetFederationSettings()
+	check.Assert(err, IsNil)
+	check.Assert(settings.HREF, Not(Equals), "")
+
+	if settings.SAMLMetadata == "" {
+		err = adminOrg.EnableFederation()
+		check.Assert(err, NotNil)
+	}
+
+	updated, err := adminOrg.UpdateFederationSettings(settings)
+	check.Assert(err, IsNil)
+	check.Assert(updated.Enabled, Equals, settings.Enabled)
+}
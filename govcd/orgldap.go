@@ -0,0 +1,128 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// GetLdapConfiguration retrieves the LDAP settings of the org
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/GET-OrgLdapSettings.html
+func (adminOrg *AdminOrg) GetLdapConfiguration() (*types.OrgLdapSettingsType, error) {
+	settings := &types.OrgLdapSettingsType{}
+	err := adminOrg.getSettings("ldap", "error retrieving org LDAP settings: %s", settings)
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// LdapConfigure sets the LDAP settings of the org and returns them as stored by vCD.
+// For types.LdapModeCustom, CustomOrgLdapSettings must contain the connection details, the bind
+// credentials and the user and group attribute mappings. For types.LdapModeSystem, the org uses the
+// system LDAP server, optionally restricted to the organizational unit in CustomUsersOu.
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/PUT-OrgLdapSettings.html
+func (adminOrg *AdminOrg) LdapConfigure(settings *types.OrgLdapSettingsType) (*types.OrgLdapSettingsType, error) {
+	err := validateOrgLdapSettings(settings)
+	if err != nil {
+		return nil, err
+	}
+	payload := *settings
+	payload.Xmlns = types.XMLNamespaceVCloud
+	if payload.OrgLdapMode != types.LdapModeCustom {
+		payload.CustomOrgLdapSettings = nil
+	}
+	updated := &types.OrgLdapSettingsType{}
+	err = adminOrg.updateSettings("ldap", types.MimeOrgLdapSettings,
+		"error updating org LDAP settings: %s", &payload, updated)
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// LdapDisable removes the LDAP configuration of the org. The users and groups imported from LDAP
+// can no longer log in.
+func (adminOrg *AdminOrg) LdapDisable() error {
+	_, err := adminOrg.LdapConfigure(&types.OrgLdapSettingsType{OrgLdapMode: types.LdapModeNone})
+	return err
+}
+
+// validateOrgLdapSettings checks the fields needed by each LDAP mode
+func validateOrgLdapSettings(settings *types.OrgLdapSettingsType) error {
+	if settings == nil {
+		return fmt.Errorf("LDAP settings must not be nil")
+	}
+	switch settings.OrgLdapMode {
+	case types.LdapModeNone, types.LdapModeSystem:
+		return nil
+	case types.LdapModeCustom:
+	default:
+		return fmt.Errorf("unsupported LDAP mode '%s'", settings.OrgLdapMode)
+	}
+
+	custom := settings.CustomOrgLdapSettings
+	if custom == nil {
+		return fmt.Errorf("custom LDAP settings are required when LDAP mode is %s", types.LdapModeCustom)
+	}
+	if custom.HostName == "" || custom.Port <= 0 {
+		return fmt.Errorf("custom LDAP settings need host name and port")
+	}
+	if custom.ConnectorType != types.LdapConnectorActiveDirectory && custom.ConnectorType != types.LdapConnectorOpenLdap {
+		return fmt.Errorf("unsupported LDAP connector type '%s'", custom.ConnectorType)
+	}
+	switch custom.AuthenticationMechanism {
+	case types.LdapAuthenticationSimple, types.LdapAuthenticationKerberos, types.LdapAuthenticationMD5, types.LdapAuthenticationNTLM:
+	default:
+		return fmt.Errorf("unsupported LDAP authentication mechanism '%s'", custom.AuthenticationMechanism)
+	}
+	if custom.Username != "" && custom.Password == "" {
+		return fmt.Errorf("LDAP bind user %s needs a password", custom.Username)
+	}
+	if custom.UserAttributes == nil || custom.GroupAttributes == nil {
+		return fmt.Errorf("custom LDAP settings need user and group attribute mappings")
+	}
+	return nil
+}
+
+// CheckLdapConnection verifies that the server in a custom LDAP configuration accepts
+// connections, by opening a TCP connection (TLS if IsSsl is set) to HostName:Port.
+// The check runs from the machine using the SDK: vCD cells may reach the LDAP server through
+// a different network path, so success is a pre-requisite rather than a guarantee.
+func CheckLdapConnection(settings *types.CustomOrgLdapSettings, timeout time.Duration) error {
+	if settings == nil || settings.HostName == "" {
+		return fmt.Errorf("LDAP host name is required")
+	}
+	port := settings.Port
+	if port == 0 {
+		port = 389
+		if settings.IsSsl {
+			port = 636
+		}
+	}
+	address := net.JoinHostPort(settings.HostName, strconv.Itoa(port))
+
+	dialer := &net.Dialer{Timeout: timeout}
+	var conn net.Conn
+	var err error
+	if settings.IsSsl {
+		conn, err = tls.DialWithDialer(dialer, "tcp", address, &tls.Config{
+			ServerName:         settings.HostName,
+			InsecureSkipVerify: settings.IsSslAcceptAll,
+		})
+	} else {
+		conn, err = dialer.Dial("tcp", address)
+	}
+	if err != nil {
+		return fmt.Errorf("error connecting to LDAP server %s: %s", address, err)
+	}
+	return conn.Close()
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+	. "gopkg.in/check.v1"
+)
+
+// Reads the LDAP configuration of the test org and writes it back unchanged
+func (vcd *TestVCD) Test_OrgLdapConfiguration(check *C) {
+	if vcd.skipAdminTests {
+		check.Skip(fmt.Sprintf(TestRequiresSysAdminPrivileges, check.TestName()))
+	}
+	adminOrg, err := GetAdminOrgByName(vcd.client, vcd.config.VCD.Org)
+	check.Assert(err, IsNil)
+
+	ldapSettings, err := adminOrg.GetLdapConfiguration()
+	check.Assert(err, IsNil)
+	check.Assert(ldapSettings.OrgLdapMode, Not(Equals), "")
+
+	if ldapSettings.OrgLdapMode == types.LdapModeCustom {
+		check.Skip("test org uses a custom LDAP server whose password can't be read back")
+	}
+	updated, err := adminOrg.LdapConfigure(ldapSettings)
+	check.Assert(err, IsNil)
+	check.Assert(updated.OrgLdapMode, Equals, ldapSettings.OrgLdapMode)
+}
+
+func TestCheckLdapConnection(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error creating listener: %s", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	settings := &types.CustomOrgLdapSettings{HostName: "127.0.0.1", Port: port}
+	err = CheckLdapConnection(settings, time.Second)
+	if err != nil {
+		t.Errorf("expected connection to succeed: %s", err)
+	}
+
+	_ = listener.Close()
+	err = CheckLdapConnection(settings, time.Second)
+	if err == nil {
+		t.Errorf("expected connection to port %s to fail", strconv.Itoa(port))
+	}
+
+	err = CheckLdapConnection(&types.CustomOrgLdapSettings{}, time.Second)
+	if err == nil {
+		t.Errorf("expected error for empty host name")
+	}
+}
+
+// Checks the LDAP settings sent for each LDAP mode against a fake vCD
+func TestAdminOrg_LdapConfigure(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	const ldapPath = vcdtest.MockAdminOrgPath + "/settings/ldap"
+	server.HandleXML(http.MethodPut, ldapPath, http.StatusOK,
+		`<OrgLdapSettings xmlns="http://www.vmware.com/vcloud/v1.5"><OrgLdapMode>CUSTOM</OrgLdapMode></OrgLdapSettings>`)
+
+	vcdClient := newMockClient(t, server)
+	adminOrg, err := GetAdminOrgByName(vcdClient, vcdtest.MockOrgName)
+	if err != nil {
+		t.Fatalf("error retrieving admin org: %s", err)
+	}
+
+	custom := types.CustomOrgLdapSettings{
+		HostName:                "ldap.example.com",
+		Port:                    389,
+		SearchBase:              "dc=example,dc=com",
+		Username:                "cn=admin,dc=example,dc=com",
+		Password:                "secret",
+		AuthenticationMechanism: types.LdapAuthenticationSimple,
+		ConnectorType:           types.LdapConnectorOpenLdap,
+		UserAttributes:          &types.OrgLdapUserAttributes{ObjectClass: "inetOrgPerson", ObjectIdentifier: "uid"},
+		GroupAttributes:         &types.OrgLdapGroupAttributes{ObjectClass: "group", ObjectIdentifier: "cn"},
+	}
+	invalidSettings := []*types.OrgLdapSettingsType{
+		nil,
+		{OrgLdapMode: "EXTERNAL"},
+		{OrgLdapMode: types.LdapModeCustom},
+	}
+	for _, modify := range []func(settings *types.CustomOrgLdapSettings){
+		func(settings *types.CustomOrgLdapSettings) { settings.Port = 0 },
+		func(settings *types.CustomOrgLdapSettings) { settings.ConnectorType = "" },
+		func(settings *types.CustomOrgLdapSettings) { settings.AuthenticationMechanism = "PLAIN" },
+		func(settings *types.CustomOrgLdapSettings) { settings.Password = "" },
+		func(settings *types.CustomOrgLdapSettings) { settings.GroupAttributes = nil },
+	} {
+		invalid := custom
+		modify(&invalid)
+		invalidSettings = append(invalidSettings, &types.OrgLdapSettingsType{OrgLdapMode: types.LdapModeCustom, CustomOrgLdapSettings: &invalid})
+	}
+	for _, settings := range invalidSettings {
+		if _, err = adminOrg.LdapConfigure(settings); err == nil {
+			t.Errorf("expected error configuring LDAP with %#v", settings)
+		}
+	}
+	if len(server.RequestsTo(http.MethodPut, ldapPath)) != 0 {
+		t.Fatalf("expected no update of the LDAP settings after invalid configurations")
+	}
+
+	settings := &types.OrgLdapSettingsType{OrgLdapMode: types.LdapModeCustom, CustomOrgLdapSettings: &custom}
+	updated, err := adminOrg.LdapConfigure(settings)
+	if err != nil {
+		t.Fatalf("error configuring LDAP: %s", err)
+	}
+	if updated.OrgLdapMode != types.LdapModeCustom || settings.Xmlns != "" {
+		t.Errorf("unexpected LDAP settings: %#v, caller settings: %#v", updated, settings)
+	}
+	body := server.RequestsTo(http.MethodPut, ldapPath)[0].Body
+	if !strings.Contains(body, "<HostName>ldap.example.com</HostName>") || !strings.Contains(body, "<Password>secret</Password>") {
+		t.Errorf("unexpected custom LDAP settings sent:\n%s", body)
+	}
+
+	server.ClearRequests()
+	_, err = adminOrg.LdapConfigure(&types.OrgLdapSettingsType{OrgLdapMode: types.LdapModeSystem, CustomUsersOu: "ou=tenant1",
+		CustomOrgLdapSettings: &custom})
+	if err != nil {
+		t.Fatalf("error configuring system LDAP: %s", err)
+	}
+	body = server.RequestsTo(http.MethodPut, ldapPath)[0].Body
+	if !strings.Contains(body, "<CustomUsersOu>ou=tenant1</CustomUsersOu>") || strings.Contains(body, "CustomOrgLdapSettings") {
+		t.Errorf("unexpected system LDAP settings sent:\n%s", body)
+	}
+
+	server.ClearRequests()
+	err = adminOrg.LdapDisable()
+	if err != nil {
+		t.Fatalf("error disabling LDAP: %s", err)
+	}
+	body = server.RequestsTo(http.MethodPut, ldapPath)[0].Body
+	if !strings.Contains(body, "<OrgLdapMode>NONE</OrgLdapMode>") {
+		t.Errorf("unexpected LDAP settings sent to disable LDAP:\n%s", body)
+	}
+}
@@ -0,0 +1,232 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// GetGeneralSettings retrieves the general settings of the org (quotas, catalog publishing,
+// power on delay).
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/GET-OrgGeneralSettings.html
+func (adminOrg *AdminOrg) GetGeneralSettings() (*types.OrgGeneralSettings, error) {
+	settings := &types.OrgGeneralSettings{}
+	err := adminOrg.getSettings("general", "error retrieving org general settings: %s", settings)
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// UpdateGeneralSettings updates the general settings of the org and returns the settings
+// as stored by vCD.
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/PUT-OrgGeneralSettings.html
+func (adminOrg *AdminOrg) UpdateGeneralSettings(settings *types.OrgGeneralSettings) (*types.OrgGeneralSettings, error) {
+	if settings == nil {
+		return nil, fmt.Errorf("general settings must not be nil")
+	}
+	settings.Xmlns = types.XMLNamespaceVCloud
+	updated := &types.OrgGeneralSettings{}
+	err := adminOrg.updateSettings("general", types.MimeOrgGeneralSettings,
+		"error updating org general settings: %s", settings, updated)
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// OrgAccessPolicy holds the org-wide catalog sharing settings, which are part of the general settings.
+// Sharing individual catalogs and vApps is decided by their owners within these limits.
+type OrgAccessPolicy struct {
+	CanPublishCatalogs   bool // Catalogs can be shared with other orgs
+	CanPublishExternally bool // Catalogs can be published to subscribers outside vCD. API 31.0+
+	CanSubscribe         bool // The org can subscribe to external catalogs. API 31.0+
+}
+
+// GetAccessPolicy retrieves the catalog sharing settings of the org
+func (adminOrg *AdminOrg) GetAccessPolicy() (*OrgAccessPolicy, error) {
+	settings, err := adminOrg.GetGeneralSettings()
+	if err != nil {
+		return nil, err
+	}
+	policy := &OrgAccessPolicy{CanPublishCatalogs: settings.CanPublishCatalogs}
+	if settings.CanPublishExternally != nil {
+		policy.CanPublishExternally = *settings.CanPublishExternally
+	}
+	if settings.CanSubscribe != nil {
+		policy.CanSubscribe = *settings.CanSubscribe
+	}
+	return policy, nil
+}
+
+// SetAccessPolicy sets the catalog sharing settings of the org. The other general settings are left
+// unchanged. External publishing and subscription need a client using API 31.0+.
+func (adminOrg *AdminOrg) SetAccessPolicy(policy OrgAccessPolicy) error {
+	supportsExternal := adminOrg.client.APIClientVersionIs(">= 31.0")
+	if !supportsExternal && (policy.CanPublishExternally || policy.CanSubscribe) {
+		return fmt.Errorf("external catalog publishing and subscription require API version 31.0 or newer, the client uses %s",
+			adminOrg.client.APIVersion)
+	}
+	settings, err := adminOrg.GetGeneralSettings()
+	if err != nil {
+		return err
+	}
+	settings.CanPublishCatalogs = policy.CanPublishCatalogs
+	if supportsExternal {
+		settings.CanPublishExternally = &policy.CanPublishExternally
+		settings.CanSubscribe = &policy.CanSubscribe
+	}
+	_, err = adminOrg.UpdateGeneralSettings(settings)
+	return err
+}
+
+// GetVAppLeaseSettings retrieves the vApp lease policy of the org
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/GET-VAppLeaseSettings.html
+func (adminOrg *AdminOrg) GetVAppLeaseSettings() (*types.VAppLeaseSettings, error) {
+	settings := &types.VAppLeaseSettings{}
+	err := adminOrg.getSettings("vAppLeaseSettings", "error retrieving org vApp lease settings: %s", settings)
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// UpdateVAppLeaseSettings updates the vApp lease policy of the org. All fields are sent,
+// as vCD expects a complete lease definition.
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/PUT-VAppLeaseSettings.html
+func (adminOrg *AdminOrg) UpdateVAppLeaseSettings(settings *types.VAppLeaseSettings) (*types.VAppLeaseSettings, error) {
+	if settings == nil {
+		return nil, fmt.Errorf("vApp lease settings must not be nil")
+	}
+	settings.Xmlns = types.XMLNamespaceVCloud
+	updated := &types.VAppLeaseSettings{}
+	err := adminOrg.updateSettings("vAppLeaseSettings", types.MimeVAppLeaseSettings,
+		"error updating org vApp lease settings: %s", settings, updated)
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// GetVAppTemplateLeaseSettings retrieves the vApp template lease policy of the org
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/GET-VAppTemplateLeaseSettings.html
+func (adminOrg *AdminOrg) GetVAppTemplateLeaseSettings() (*types.VAppTemplateLeaseSettings, error) {
+	settings := &types.VAppTemplateLeaseSettings{}
+	err := adminOrg.getSettings("vAppTemplateLeaseSettings", "error retrieving org vApp template lease settings: %s", settings)
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// UpdateVAppTemplateLeaseSettings updates the vApp template lease policy of the org
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/PUT-VAppTemplateLeaseSettings.html
+func (adminOrg *AdminOrg) UpdateVAppTemplateLeaseSettings(settings *types.VAppTemplateLeaseSettings) (*types.VAppTemplateLeaseSettings, error) {
+	if settings == nil {
+		return nil, fmt.Errorf("vApp template lease settings must not be nil")
+	}
+	settings.Xmlns = types.XMLNamespaceVCloud
+	updated := &types.VAppTemplateLeaseSettings{}
+	err := adminOrg.updateSettings("vAppTemplateLeaseSettings", types.MimeVAppTemplateLeaseSettings,
+		"error updating org vApp template lease settings: %s", settings, updated)
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// GetOperationLimitsSettings retrieves the limits on resource intensive operations and
+// console connections of the org
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/GET-OrgOperationLimitsSettings.html
+func (adminOrg *AdminOrg) GetOperationLimitsSettings() (*types.OrgOperationLimitsSettings, error) {
+	settings := &types.OrgOperationLimitsSettings{}
+	err := adminOrg.getSettings("operationLimitsSettings", "error retrieving org operation limits: %s", settings)
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// UpdateOperationLimitsSettings updates the operation limits of the org. A value of 0
+// means no limit.
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/PUT-OrgOperationLimitsSettings.html
+func (adminOrg *AdminOrg) UpdateOperationLimitsSettings(settings *types.OrgOperationLimitsSettings) (*types.OrgOperationLimitsSettings, error) {
+	if settings == nil {
+		return nil, fmt.Errorf("operation limits settings must not be nil")
+	}
+	settings.Xmlns = types.XMLNamespaceVCloud
+	updated := &types.OrgOperationLimitsSettings{}
+	err := adminOrg.updateSettings("operationLimitsSettings", types.MimeOrgOperationLimitsSettings,
+		"error updating org operation limits: %s", settings, updated)
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// GetEmailSettings retrieves the SMTP and notification settings of the org
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/GET-OrgEmailSettings.html
+func (adminOrg *AdminOrg) GetEmailSettings() (*types.OrgEmailSettings, error) {
+	settings := &types.OrgEmailSettings{}
+	err := adminOrg.getSettings("email", "error retrieving org email settings: %s", settings)
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// UpdateEmailSettings updates the SMTP and notification settings of the org.
+// When IsDefaultSmtpServer is false, SmtpServerSettings must be provided.
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/PUT-OrgEmailSettings.html
+func (adminOrg *AdminOrg) UpdateEmailSettings(settings *types.OrgEmailSettings) (*types.OrgEmailSettings, error) {
+	if settings == nil {
+		return nil, fmt.Errorf("email settings must not be nil")
+	}
+	if !settings.IsDefaultSmtpServer && settings.SmtpServerSettings == nil {
+		return nil, fmt.Errorf("SMTP server settings are required when not using the default SMTP server")
+	}
+	settings.Xmlns = types.XMLNamespaceVCloud
+	updated := &types.OrgEmailSettings{}
+	err := adminOrg.updateSettings("email", types.MimeOrgEmailSettings,
+		"error updating org email settings: %s", settings, updated)
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// getSettingsHref returns the HREF of the given org settings section
+func (adminOrg *AdminOrg) getSettingsHref(section string) (string, error) {
+	settingsHREF, err := url.ParseRequestURI(adminOrg.AdminOrg.HREF)
+	if err != nil {
+		return "", fmt.Errorf("error getting AdminOrg HREF %s : %v", adminOrg.AdminOrg.HREF, err)
+	}
+	settingsHREF.Path += "/settings/" + section
+	return settingsHREF.String(), nil
+}
+
+// getSettings retrieves an org settings section into out
+func (adminOrg *AdminOrg) getSettings(section, errorMessage string, out interface{}) error {
+	href, err := adminOrg.getSettingsHref(section)
+	if err != nil {
+		return err
+	}
+	_, err = adminOrg.client.ExecuteRequest(href, http.MethodGet, "", errorMessage, nil, out)
+	return err
+}
+
+// updateSettings sends payload to an org settings section and unmarshals the result into out
+func (adminOrg *AdminOrg) updateSettings(section, contentType, errorMessage string, payload, out interface{}) error {
+	href, err := adminOrg.getSettingsHref(section)
+	if err != nil {
+		return err
+	}
+	_, err = adminOrg.client.ExecuteRequest(href, http.MethodPut, contentType, errorMessage, payload, out)
+	return err
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+
+	. "gopkg.in/check.v1"
+)
+
+// Retrieves all the settings sections of the test org, changes some values
+// and restores the original ones
+func (vcd *TestVCD) Test_OrgSettings(check *C) {
+	if vcd.skipAdminTests {
+		check.Skip(fmt.Sprintf(TestRequiresSysAdminPrivileges, check.TestName()))
+	}
+	adminOrg, err := GetAdminOrgByName(vcd.client, vcd.config.VCD.Org)
+	check.Assert(err, IsNil)
+
+	general, err := adminOrg.GetGeneralSettings()
+	check.Assert(err, IsNil)
+	originalDelay := general.DelayAfterPowerOnSeconds
+	general.DelayAfterPowerOnSeconds = originalDelay + 5
+	updatedGeneral, err := adminOrg.UpdateGeneralSettings(general)
+	check.Assert(err, IsNil)
+	check.Assert(updatedGeneral.DelayAfterPowerOnSeconds, Equals, originalDelay+5)
+	updatedGeneral.DelayAfterPowerOnSeconds = originalDelay
+	_, err = adminOrg.UpdateGeneralSettings(updatedGeneral)
+	check.Assert(err, IsNil)
+
+	leases, err := adminOrg.GetVAppLeaseSettings()
+	check.Assert(err, IsNil)
+	_, err = adminOrg.UpdateVAppLeaseSettings(leases)
+	check.Assert(err, IsNil)
+
+	templateLeases, err := adminOrg.GetVAppTemplateLeaseSettings()
+	check.Assert(err, IsNil)
+	_, err = adminOrg.UpdateVAppTemplateLeaseSettings(templateLeases)
+	check.Assert(err, IsNil)
+
+	limits, err := adminOrg.GetOperationLimitsSettings()
+	check.Assert(err, IsNil)
+	originalConsoles := limits.ConsolesPerVmLimit
+	limits.ConsolesPerVmLimit = originalConsoles + 1
+	updatedLimits, err := adminOrg.UpdateOperationLimitsSettings(limits)
+	check.Assert(err, IsNil)
+	check.Assert(updatedLimits.ConsolesPerVmLimit, Equals, originalConsoles+1)
+	updatedLimits.ConsolesPerVmLimit = originalConsoles
+	_, err = adminOrg.UpdateOperationLimitsSettings(updatedLimits)
+	check.Assert(err, IsNil)
+
+	policy, err := adminOrg.GetAccessPolicy()
+	check.Assert(err, IsNil)
+	newPolicy := *policy
+	newPolicy.CanPublishCatalogs = !policy.CanPublishCatalogs
+	err = adminOrg.SetAccessPolicy(newPolicy)
+	check.Assert(err, IsNil)
+	updatedPolicy, err := adminOrg.GetAccessPolicy()
+	check.Assert(err, IsNil)
+	check.Assert(*updatedPolicy, Equals, newPolicy)
+	err = adminOrg.SetAccessPolicy(*policy)
+	check.Assert(err, IsNil)
+
+	email, err := adminOrg.GetEmailSettings()
+	check.Assert(err, IsNil)
+	check.Assert(email.HREF, Not(Equals), "")
+}
@@ -40,14 +40,17 @@ func (orgVdcNet *OrgVDCNetwork) Refresh() error {
 
 	refreshUrl := orgVdcNet.OrgVDCNetwork.HREF
 
-	// Empty struct before a new unmarshal, otherwise we end up with duplicate
-	// elements in slices.
-	orgVdcNet.OrgVDCNetwork = &types.OrgVDCNetwork{}
+	// Unmarshal into an empty struct, otherwise we end up with duplicate elements in
+	// slices, and keep the current one when the request fails.
+	refreshed := &types.OrgVDCNetwork{}
 
 	_, err := orgVdcNet.client.ExecuteRequest(refreshUrl, http.MethodGet,
-		"", "error retrieving vDC network: %s", nil, orgVdcNet.OrgVDCNetwork)
-
-	return err
+		"", "error retrieving vDC network: %s", nil, refreshed)
+	if err != nil {
+		return err
+	}
+	orgVdcNet.OrgVDCNetwork = refreshed
+	return nil
 }
 
 // Delete a network. Fails if the network is busy.
@@ -183,3 +186,162 @@ func (vdc *Vdc) CreateOrgVDCNetwork(networkConfig *types.OrgVDCNetwork) (Task, e
 	}
 	return Task{}, fmt.Errorf("network creation failed: no operational link found")
 }
+
+// OrgVDCNetworkSettings describes an Org VDC network to be created with
+// CreateOrgVDCNetworkWithSettings
+type OrgVDCNetworkSettings struct {
+	Name        string
+	Description string
+	// FenceMode is one of types.FenceModeIsolated for an isolated network, types.FenceModeNAT for a
+	// network routed by EdgeGateway, or types.FenceModeBridged for a network connected directly
+	// to ParentNetwork
+	FenceMode     string
+	EdgeGateway   *types.Reference
+	ParentNetwork *types.Reference
+	// The IP scope of isolated and routed networks. Direct networks inherit the IP scope of
+	// their parent network.
+	Gateway     string
+	Netmask     string
+	DNS1        string
+	DNS2        string
+	DNSSuffix   string
+	StaticPools []*types.IPRange
+	// DhcpPool enables the DHCP service of an isolated network. The DHCP service of a routed
+	// network belongs to its edge gateway, see EdgeGateway.AddDhcpPool.
+	DhcpPool *OrgVDCNetworkDhcpPool
+	IsShared bool
+}
+
+// OrgVDCNetworkDhcpPool is the range of addresses leased by the DHCP service of an isolated
+// network. The lease times are in seconds and default to one and two hours.
+type OrgVDCNetworkDhcpPool struct {
+	StartAddress     string
+	EndAddress       string
+	DefaultLeaseTime int
+	MaxLeaseTime     int
+}
+
+// CreateOrgVDCNetworkWithSettings creates an isolated, routed or direct Org VDC network, waits for
+// its configuration and returns it
+func (vdc *Vdc) CreateOrgVDCNetworkWithSettings(settings *OrgVDCNetworkSettings) (*OrgVDCNetwork, error) {
+	networkConfig, err := newOrgVDCNetworkConfig(settings)
+	if err != nil {
+		return nil, err
+	}
+	err = vdc.CreateOrgVDCNetworkWait(networkConfig)
+	if err != nil {
+		return nil, err
+	}
+	network, err := vdc.FindVDCNetwork(settings.Name)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving network %s after creation: %s", settings.Name, err)
+	}
+	return &network, nil
+}
+
+// Update sends the changes made to the OrgVDCNetwork structure, such as the name, description,
+// DNS servers and static pools, to vCD. Returns a task to monitor the update.
+func (orgVdcNet *OrgVDCNetwork) Update() (Task, error) {
+	if orgVdcNet.OrgVDCNetwork.HREF == "" {
+		return Task{}, fmt.Errorf("cannot update a network without HREF")
+	}
+	pathArr := strings.Split(orgVdcNet.OrgVDCNetwork.HREF, "/")
+	apiEndpoint, _ := url.ParseRequestURI(orgVdcNet.OrgVDCNetwork.HREF)
+	apiEndpoint.Path = "/api/admin/network/" + pathArr[len(pathArr)-1]
+
+	// The tasks in progress are read-only
+	networkConfig := *orgVdcNet.OrgVDCNetwork
+	networkConfig.Xmlns = types.XMLNamespaceVCloud
+	networkConfig.Tasks = nil
+
+	return orgVdcNet.client.ExecuteTaskRequest(apiEndpoint.String(), http.MethodPut,
+		types.MimeOrgVdcNetwork, "error updating network: %s", &networkConfig)
+}
+
+// newOrgVDCNetworkConfig validates settings and builds the network configuration sent to vCD
+func newOrgVDCNetworkConfig(settings *OrgVDCNetworkSettings) (*types.OrgVDCNetwork, error) {
+	if settings == nil || settings.Name == "" {
+		return nil, fmt.Errorf("network name must not be empty")
+	}
+	networkConfig := &types.OrgVDCNetwork{
+		Xmlns:       types.XMLNamespaceVCloud,
+		Name:        settings.Name,
+		Description: settings.Description,
+		IsShared:    settings.IsShared,
+		Configuration: &types.NetworkConfiguration{
+			FenceMode:                 settings.FenceMode,
+			BackwardCompatibilityMode: true,
+		},
+	}
+
+	switch settings.FenceMode {
+	case types.FenceModeIsolated, types.FenceModeNAT:
+		if settings.Gateway == "" || settings.Netmask == "" {
+			return nil, fmt.Errorf("network %s needs a gateway and a network mask", settings.Name)
+		}
+		if settings.ParentNetwork != nil {
+			return nil, fmt.Errorf("network %s with fence mode %s cannot have a parent network", settings.Name, settings.FenceMode)
+		}
+		networkConfig.Configuration.IPScopes = &types.IPScopes{
+			IPScope: types.IPScope{
+				Gateway:   settings.Gateway,
+				Netmask:   settings.Netmask,
+				DNS1:      settings.DNS1,
+				DNS2:      settings.DNS2,
+				DNSSuffix: settings.DNSSuffix,
+				IsEnabled: true,
+			},
+		}
+		if len(settings.StaticPools) > 0 {
+			networkConfig.Configuration.IPScopes.IPScope.IPRanges = &types.IPRanges{IPRange: settings.StaticPools}
+		}
+	case types.FenceModeBridged:
+		if settings.ParentNetwork == nil || settings.ParentNetwork.HREF == "" {
+			return nil, fmt.Errorf("direct network %s needs a parent network", settings.Name)
+		}
+		if settings.Gateway != "" || len(settings.StaticPools) > 0 {
+			return nil, fmt.Errorf("direct network %s inherits the IP scope of its parent network", settings.Name)
+		}
+		networkConfig.Configuration.ParentNetwork = settings.ParentNetwork
+	default:
+		return nil, fmt.Errorf("invalid fence mode '%s' for network %s", settings.FenceMode, settings.Name)
+	}
+
+	if settings.FenceMode == types.FenceModeNAT {
+		if settings.EdgeGateway == nil || settings.EdgeGateway.HREF == "" {
+			return nil, fmt.Errorf("routed network %s needs an edge gateway", settings.Name)
+		}
+		networkConfig.EdgeGateway = settings.EdgeGateway
+	} else if settings.EdgeGateway != nil {
+		return nil, fmt.Errorf("network %s with fence mode %s cannot have an edge gateway", settings.Name, settings.FenceMode)
+	}
+
+	if settings.DhcpPool != nil {
+		if settings.FenceMode != types.FenceModeIsolated {
+			return nil, fmt.Errorf("DHCP pool of network %s can only be set for isolated networks", settings.Name)
+		}
+		if settings.DhcpPool.StartAddress == "" || settings.DhcpPool.EndAddress == "" {
+			return nil, fmt.Errorf("DHCP pool of network %s needs a start and an end address", settings.Name)
+		}
+		pool := &types.DhcpPoolService{
+			IsEnabled:        true,
+			DefaultLeaseTime: settings.DhcpPool.DefaultLeaseTime,
+			MaxLeaseTime:     settings.DhcpPool.MaxLeaseTime,
+			LowIPAddress:     settings.DhcpPool.StartAddress,
+			HighIPAddress:    settings.DhcpPool.EndAddress,
+		}
+		if pool.DefaultLeaseTime == 0 {
+			pool.DefaultLeaseTime = 3600
+		}
+		if pool.MaxLeaseTime == 0 {
+			pool.MaxLeaseTime = 7200
+		}
+		networkConfig.ServiceConfig = &types.GatewayFeatures{
+			GatewayDhcpService: &types.GatewayDhcpService{
+				IsEnabled: true,
+				Pool:      []*types.DhcpPoolService{pool},
+			},
+		}
+	}
+	return networkConfig, nil
+}
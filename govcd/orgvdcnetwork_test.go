@@ -6,8 +6,12 @@ package govcd
 
 import (
 	"fmt"
+	"net/http"
+	"strings"
+	"testing"
 
 	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
 	. "gopkg.in/check.v1"
 )
 
@@ -209,3 +213,107 @@ func (vcd *TestVCD) Test_CreateOrgVdcNetworkDirect(check *C) {
 	}
 	check.Assert(err, IsNil)
 }
+
+// Checks the network configurations built from the settings, and the creation and update requests
+func TestVdc_CreateOrgVDCNetworkWithSettings(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	server.HandleXML(http.MethodGet, vcdtest.MockVdcPath, http.StatusOK,
+		`<Vdc xmlns="http://www.vmware.com/vcloud/v1.5" name="`+vcdtest.MockVdcName+`" href="{{server}}`+vcdtest.MockVdcPath+`">
+  <Link rel="add" type="application/vnd.vmware.vcloud.orgVdcNetwork+xml" href="{{server}}/api/admin/vdc/`+vcdtest.MockVdcId+`/networks"/>
+  <AvailableNetworks>
+    <Network type="application/vnd.vmware.vcloud.network+xml" name="isolated" href="{{server}}/api/network/net-1"/>
+  </AvailableNetworks>
+</Vdc>`)
+	server.HandleXML(http.MethodPost, "/api/admin/vdc/"+vcdtest.MockVdcId+"/networks", http.StatusCreated,
+		`<OrgVdcNetwork xmlns="http://www.vmware.com/vcloud/v1.5" name="isolated" href="{{server}}/api/admin/network/net-1">
+  <Tasks><Task status="running" href="{{server}}`+vcdtest.MockTaskPath+`"/></Tasks>
+</OrgVdcNetwork>`)
+	server.HandleXML(http.MethodGet, "/api/network/net-1", http.StatusOK,
+		`<OrgVdcNetwork xmlns="http://www.vmware.com/vcloud/v1.5" name="isolated" href="{{server}}/api/network/net-1">
+  <Configuration><FenceMode>isolated</FenceMode></Configuration>
+  <Tasks><Task status="success" href="{{server}}`+vcdtest.MockTaskPath+`"/></Tasks>
+</OrgVdcNetwork>`)
+	server.HandleXML(http.MethodPut, "/api/admin/network/net-1", http.StatusAccepted,
+		`<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="running" href="{{server}}`+vcdtest.MockTaskPath+`"/>`)
+
+	vcdClient := newMockClient(t, server)
+	vdc := NewVdc(&vcdClient.Client)
+	vdc.Vdc.HREF = server.URL() + vcdtest.MockVdcPath
+	err := vdc.Refresh()
+	if err != nil {
+		t.Fatalf("error retrieving VDC: %s", err)
+	}
+
+	network, err := vdc.CreateOrgVDCNetworkWithSettings(&OrgVDCNetworkSettings{
+		Name:        "isolated",
+		FenceMode:   types.FenceModeIsolated,
+		Gateway:     "192.168.2.1",
+		Netmask:     "255.255.255.0",
+		DNS1:        "8.8.8.8",
+		StaticPools: []*types.IPRange{{StartAddress: "192.168.2.10", EndAddress: "192.168.2.50"}},
+		DhcpPool:    &OrgVDCNetworkDhcpPool{StartAddress: "192.168.2.100", EndAddress: "192.168.2.150"},
+	})
+	if err != nil {
+		t.Fatalf("error creating network: %s", err)
+	}
+	if network.OrgVDCNetwork.Name != "isolated" {
+		t.Errorf("unexpected network: %+v", network.OrgVDCNetwork)
+	}
+	requests := server.RequestsTo(http.MethodPost, "/api/admin/vdc/"+vcdtest.MockVdcId+"/networks")
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 creation request, got %d", len(requests))
+	}
+	for _, expected := range []string{
+		"<FenceMode>isolated</FenceMode>",
+		"<Gateway>192.168.2.1</Gateway>",
+		"<Dns1>8.8.8.8</Dns1>",
+		"<StartAddress>192.168.2.10</StartAddress>",
+		"<LowIpAddress>192.168.2.100</LowIpAddress>",
+		"<MaxLeaseTime>7200</MaxLeaseTime>",
+	} {
+		if !strings.Contains(requests[0].Body, expected) {
+			t.Errorf("expected %s in creation payload:\n%s", expected, requests[0].Body)
+		}
+	}
+
+	network.OrgVDCNetwork.Description = "updated"
+	_, err = network.Update()
+	if err != nil {
+		t.Fatalf("error updating network: %s", err)
+	}
+	requests = server.RequestsTo(http.MethodPut, "/api/admin/network/net-1")
+	if len(requests) != 1 || !strings.Contains(requests[0].Body, "<Description>updated</Description>") ||
+		strings.Contains(requests[0].Body, "<Tasks>") {
+		t.Errorf("unexpected update requests: %+v", requests)
+	}
+
+	edgeGateway := &types.Reference{HREF: server.URL() + "/api/admin/edgeGateway/edge-1"}
+	externalNetwork := &types.Reference{HREF: server.URL() + "/api/admin/extension/externalnet/ext-1"}
+	routed, err := newOrgVDCNetworkConfig(&OrgVDCNetworkSettings{Name: "routed", FenceMode: types.FenceModeNAT,
+		EdgeGateway: edgeGateway, Gateway: "10.10.1.1", Netmask: "255.255.255.0"})
+	if err != nil || routed.EdgeGateway != edgeGateway || routed.Configuration.IPScopes == nil {
+		t.Errorf("unexpected routed network configuration: %+v, %v", routed, err)
+	}
+	direct, err := newOrgVDCNetworkConfig(&OrgVDCNetworkSettings{Name: "direct", FenceMode: types.FenceModeBridged,
+		ParentNetwork: externalNetwork})
+	if err != nil || direct.Configuration.ParentNetwork != externalNetwork || direct.Configuration.IPScopes != nil {
+		t.Errorf("unexpected direct network configuration: %+v, %v", direct, err)
+	}
+
+	for _, invalid := range []*OrgVDCNetworkSettings{
+		{FenceMode: types.FenceModeIsolated, Gateway: "10.10.1.1", Netmask: "255.255.255.0"},
+		{Name: "net", FenceMode: "routed"},
+		{Name: "net", FenceMode: types.FenceModeIsolated},
+		{Name: "net", FenceMode: types.FenceModeNAT, Gateway: "10.10.1.1", Netmask: "255.255.255.0"},
+		{Name: "net", FenceMode: types.FenceModeBridged},
+		{Name: "net", FenceMode: types.FenceModeBridged, ParentNetwork: externalNetwork, Gateway: "10.10.1.1"},
+		{Name: "net", FenceMode: types.FenceModeNAT, EdgeGateway: edgeGateway, Gateway: "10.10.1.1", Netmask: "255.255.255.0",
+			DhcpPool: &OrgVDCNetworkDhcpPool{StartAddress: "10.10.1.100", EndAddress: "10.10.1.150"}},
+	} {
+		_, err = newOrgVDCNetworkConfig(invalid)
+		if err == nil {
+			t.Errorf("expected error for network settings %+v", invalid)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// Owners of vApps, media and independent disks
+//
+// Entities belong to the user who created them. When a service account creates entities on behalf
+// of end users, the functions below hand them over to these users, through the owner endpoint of
+// the entity. The new owner is given by the HREF of a user of the organization (see OrgUser).
+
+// ChangeOwner gives the vApp to the user with the given HREF and refreshes the vApp
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/PUT-VAppOwner.html
+func (vapp *VApp) ChangeOwner(userHref string) error {
+	err := changeOwner(vapp.client, vapp.VApp.HREF, userHref)
+	if err != nil {
+		return fmt.Errorf("error changing owner of vApp %s: %s", vapp.VApp.Name, err)
+	}
+	return vapp.Refresh()
+}
+
+// ChangeOwner gives the independent disk to the user with the given HREF and refreshes the disk
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/PUT-DiskOwner.html
+func (disk *Disk) ChangeOwner(userHref string) error {
+	err := changeOwner(disk.client, disk.Disk.HREF, userHref)
+	if err != nil {
+		return fmt.Errorf("error changing owner of disk %s: %s", disk.Disk.Name, err)
+	}
+	return disk.Refresh()
+}
+
+// ChangeOwner gives the media to the user with the given HREF, and updates the owner of the media
+// record
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/PUT-MediaOwner.html
+func (mediaItem *MediaItem) ChangeOwner(userHref string) error {
+	err := changeOwner(mediaItem.client, mediaItem.MediaItem.HREF, userHref)
+	if err != nil {
+		return fmt.Errorf("error changing owner of media %s: %s", mediaItem.MediaItem.Name, err)
+	}
+	owner, err := getOwner(mediaItem.client, mediaItem.MediaItem.HREF)
+	if err != nil {
+		return err
+	}
+	mediaItem.MediaItem.Owner = owner.User.HREF
+	mediaItem.MediaItem.OwnerName = owner.User.Name
+	return nil
+}
+
+// changeOwner sets the owner of the entity with the given HREF
+func changeOwner(client *Client, entityHref, userHref string) error {
+	if entityHref == "" {
+		return fmt.Errorf("cannot change owner of an entity without HREF")
+	}
+	if userHref == "" {
+		return fmt.Errorf("the HREF of the new owner is required")
+	}
+	owner := &types.Owner{
+		Xmlns: types.XMLNamespaceVCloud,
+		User:  &types.Reference{HREF: userHref},
+	}
+	return client.ExecuteRequestWithoutResponse(ownerHref(entityHref), http.MethodPut,
+		types.MimeOwner, "error setting owner: %s", owner)
+}
+
+// getOwner retrieves the owner of the entity with the given HREF
+func getOwner(client *Client, entityHref string) (*types.Owner, error) {
+	owner := &types.Owner{}
+	_, err := client.ExecuteRequest(ownerHref(entityHref), http.MethodGet,
+		"", "error retrieving owner: %s", nil, owner)
+	if err != nil {
+		return nil, err
+	}
+	if owner.User == nil {
+		return nil, fmt.Errorf("no owner found for %s", entityHref)
+	}
+	return owner, nil
+}
+
+// ownerHref returns the HREF of the owner endpoint of an entity
+func ownerHref(entityHref string) string {
+	return strings.TrimSuffix(entityHref, "/") + "/owner"
+}
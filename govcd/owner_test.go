@@ -0,0 +1,79 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// Checks the change of owner of a vApp, a disk and a media against a fake vCD
+func TestChangeOwner(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	const vappPath = "/api/vApp/vapp-55555555-5555-5555-5555-555555555555"
+	const diskPath = "/api/disk/66666666-6666-6666-6666-666666666666"
+	const mediaPath = "/api/media/77777777-7777-7777-7777-777777777777"
+	const userPath = "/api/admin/user/88888888-8888-8888-8888-888888888888"
+	ownerXml := `<Owner xmlns="http://www.vmware.com/vcloud/v1.5">
+  <User type="application/vnd.vmware.admin.user+xml" name="end-user" href="{{server}}` + userPath + `"/>
+</Owner>`
+	for _, path := range []string{vappPath, diskPath, mediaPath} {
+		server.Handle(http.MethodPut, path+"/owner", vcdtest.Response{Status: http.StatusNoContent})
+		server.HandleXML(http.MethodGet, path+"/owner", http.StatusOK, ownerXml)
+	}
+	server.HandleXML(http.MethodGet, vappPath, http.StatusOK,
+		`<VApp xmlns="http://www.vmware.com/vcloud/v1.5" name="vapp1" href="{{server}}`+vappPath+`">`+ownerXml+`</VApp>`)
+	server.HandleXML(http.MethodGet, diskPath, http.StatusOK,
+		`<Disk xmlns="http://www.vmware.com/vcloud/v1.5" name="disk1" size="1024" href="{{server}}`+diskPath+`">`+ownerXml+`</Disk>`)
+
+	vcdClient := newMockClient(t, server)
+	client := &vcdClient.Client
+	userHref := server.URL() + userPath
+
+	vapp := &VApp{VApp: &types.VApp{Name: "vapp1", HREF: server.URL() + vappPath}, client: client}
+	if err := vapp.ChangeOwner(""); err == nil {
+		t.Errorf("expected error changing owner without user HREF")
+	}
+	err := vapp.ChangeOwner(userHref)
+	if err != nil {
+		t.Fatalf("error changing owner of vApp: %s", err)
+	}
+	if vapp.VApp.Owner == nil || vapp.VApp.Owner.User.Name != "end-user" {
+		t.Errorf("unexpected owner of vApp after refresh: %#v", vapp.VApp.Owner)
+	}
+
+	disk := &Disk{Disk: &types.Disk{Name: "disk1", HREF: server.URL() + diskPath}, client: client}
+	err = disk.ChangeOwner(userHref)
+	if err != nil {
+		t.Fatalf("error changing owner of disk: %s", err)
+	}
+	if disk.Disk.Owner == nil || disk.Disk.Owner.User.HREF != userHref {
+		t.Errorf("unexpected owner of disk after refresh: %#v", disk.Disk.Owner)
+	}
+
+	mediaItem := &MediaItem{MediaItem: &types.MediaRecordType{Name: "media1", HREF: server.URL() + mediaPath,
+		OwnerName: "service-account"}, client: client}
+	err = mediaItem.ChangeOwner(userHref)
+	if err != nil {
+		t.Fatalf("error changing owner of media: %s", err)
+	}
+	if mediaItem.MediaItem.OwnerName != "end-user" || mediaItem.MediaItem.Owner != userHref {
+		t.Errorf("unexpected owner of media record: %#v", mediaItem.MediaItem)
+	}
+
+	for _, path := range []string{vappPath, diskPath, mediaPath} {
+		puts := server.RequestsTo(http.MethodPut, path+"/owner")
+		if len(puts) != 1 || puts[0].Header.Get("Content-Type") != types.MimeOwner ||
+			!strings.Contains(puts[0].Body, `<User href="`+userHref+`"`) {
+			t.Errorf("unexpected owner change of %s: %#v", path, puts)
+		}
+	}
+}
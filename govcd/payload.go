@@ -0,0 +1,167 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"net"
+	"reflect"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/util"
+)
+
+// payloadAdjustment changes the elements of a request payload which the API versions matching
+// constraint do not accept, or expect in a different form. adjust is called with a pointer to
+// every structure found in the payload, and ignores the types it does not handle.
+type payloadAdjustment struct {
+	constraint  string
+	description string
+	adjust      func(element interface{})
+}
+
+// payloadAdjustments are applied to the payloads of the POST and PUT requests, so that the methods
+// building them, such as VApp.AddVM, AddIsolatedNetwork and AdminVdc.Update, work unchanged with
+// every supported API version
+var payloadAdjustments = []payloadAdjustment{
+	{
+		constraint:  "< 32.0",
+		description: "compute policies are only known from API 32.0",
+		adjust: func(element interface{}) {
+			if vdc, ok := element.(*types.Vdc); ok {
+				vdc.DefaultComputePolicy = nil
+				vdc.MaxComputePolicy = nil
+			}
+		},
+	},
+	{
+		constraint:  "< 33.0",
+		description: "VMs have compute policies from API 33.0",
+		adjust: func(element interface{}) {
+			switch item := element.(type) {
+			case *types.SourcedCompositionItemParam:
+				item.ComputePolicy = nil
+			case *types.VM:
+				item.ComputePolicy = nil
+			}
+		},
+	},
+	{
+		constraint:  "< 34.0",
+		description: "IP scopes have a network mask before API 34.0",
+		adjust: func(element interface{}) {
+			if ipScope, ok := element.(*types.IPScope); ok && ipScope.SubnetPrefixLength > 0 {
+				if ipScope.Netmask == "" {
+					ipScope.Netmask = net.IP(net.CIDRMask(ipScope.SubnetPrefixLength, 32)).String()
+				}
+				ipScope.SubnetPrefixLength = 0
+			}
+		},
+	},
+	{
+		constraint:  ">= 34.0",
+		description: "IP scopes have a subnet prefix length instead of a network mask from API 34.0",
+		adjust: func(element interface{}) {
+			if ipScope, ok := element.(*types.IPScope); ok && ipScope.Netmask != "" {
+				if ipScope.SubnetPrefixLength == 0 {
+					mask := net.ParseIP(ipScope.Netmask).To4()
+					if mask == nil {
+						return
+					}
+					ipScope.SubnetPrefixLength, _ = net.IPv4Mask(mask[0], mask[1], mask[2], mask[3]).Size()
+				}
+				ipScope.Netmask = ""
+			}
+		},
+	},
+}
+
+// adjustPayload returns payload with the adjustments matching the API version of the client.
+// When an adjustment applies, a copy of the payload is adjusted, so that the structures of the
+// caller are left unchanged.
+func (client *Client) adjustPayload(payload interface{}) interface{} {
+	if payload == nil || client.APIVersion == "" {
+		return payload
+	}
+	var adjusted reflect.Value
+	for _, adjustment := range payloadAdjustments {
+		if !client.APIClientVersionIs(adjustment.constraint) {
+			continue
+		}
+		util.Logger.Printf("[TRACE] adjusting payload %T for API %s: %s", payload, client.APIVersion, adjustment.description)
+		if !adjusted.IsValid() {
+			adjusted = reflect.New(reflect.TypeOf(payload)).Elem()
+			copyPayload(adjusted, reflect.ValueOf(payload))
+		}
+		visitPayload(adjusted, adjustment.adjust)
+	}
+	if !adjusted.IsValid() {
+		return payload
+	}
+	return adjusted.Interface()
+}
+
+// visitPayload calls visit with a pointer to every addressable structure found in value
+func visitPayload(value reflect.Value, visit func(element interface{})) {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !value.IsNil() {
+			visitPayload(value.Elem(), visit)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			visitPayload(value.Index(i), visit)
+		}
+	case reflect.Struct:
+		if value.CanAddr() && value.Addr().CanInterface() {
+			visit(value.Addr().Interface())
+		}
+		for i := 0; i < value.NumField(); i++ {
+			if value.Type().Field(i).PkgPath == "" {
+				visitPayload(value.Field(i), visit)
+			}
+		}
+	}
+}
+
+// copyPayload copies source into destination, following the pointers, slices and exported
+// fields. Maps and unexported fields are shared, as payloads do not contain them.
+func copyPayload(destination, source reflect.Value) {
+	switch source.Kind() {
+	case reflect.Ptr:
+		if source.IsNil() {
+			return
+		}
+		destination.Set(reflect.New(source.Elem().Type()))
+		copyPayload(destination.Elem(), source.Elem())
+	case reflect.Interface:
+		if source.IsNil() {
+			return
+		}
+		element := reflect.New(source.Elem().Type()).Elem()
+		copyPayload(element, source.Elem())
+		destination.Set(element)
+	case reflect.Slice:
+		if source.IsNil() {
+			return
+		}
+		destination.Set(reflect.MakeSlice(source.Type(), source.Len(), source.Len()))
+		for i := 0; i < source.Len(); i++ {
+			copyPayload(destination.Index(i), source.Index(i))
+		}
+	case reflect.Array:
+		for i := 0; i < source.Len(); i++ {
+			copyPayload(destination.Index(i), source.Index(i))
+		}
+	case reflect.Struct:
+		destination.Set(source)
+		for i := 0; i < source.NumField(); i++ {
+			if source.Type().Field(i).PkgPath == "" {
+				copyPayload(destination.Field(i), source.Field(i))
+			}
+		}
+	default:
+		destination.Set(source)
+	}
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// newIsolatedNetworkPayload returns a network configuration with a network mask
+func newIsolatedNetworkPayload() *types.NetworkConfigSection {
+	return &types.NetworkConfigSection{NetworkConfig: []types.VAppNetworkConfiguration{{
+		NetworkName: "isolated",
+		Configuration: &types.NetworkConfiguration{
+			FenceMode: types.FenceModeIsolated,
+			IPScopes:  &types.IPScopes{IPScope: types.IPScope{Gateway: "192.168.10.1", Netmask: "255.255.255.0"}},
+		},
+	}}}
+}
+
+// Checks that the payloads are adjusted to the API version, without changing the original
+func TestClient_adjustPayload(t *testing.T) {
+	payload := newIsolatedNetworkPayload()
+
+	client := &Client{APIVersion: "33.0"}
+	adjusted := client.adjustPayload(payload).(*types.NetworkConfigSection)
+	ipScope := adjusted.NetworkConfig[0].Configuration.IPScopes.IPScope
+	if ipScope.SubnetPrefixLength != 0 || ipScope.Netmask != "255.255.255.0" {
+		t.Errorf("unexpected IP scope for API 33.0: %+v", ipScope)
+	}
+
+	client.APIVersion = "34.0"
+	adjusted = client.adjustPayload(payload).(*types.NetworkConfigSection)
+	ipScope = adjusted.NetworkConfig[0].Configuration.IPScopes.IPScope
+	if ipScope.SubnetPrefixLength != 24 || ipScope.Netmask != "" || ipScope.Gateway != "192.168.10.1" {
+		t.Errorf("unexpected IP scope for API 34.0: %+v", ipScope)
+	}
+	original := payload.NetworkConfig[0].Configuration.IPScopes.IPScope
+	if original.Netmask != "255.255.255.0" || original.SubnetPrefixLength != 0 {
+		t.Errorf("original payload changed: %+v", original)
+	}
+
+	payload.NetworkConfig[0].Configuration.IPScopes.IPScope = types.IPScope{SubnetPrefixLength: 20}
+	client.APIVersion = "31.0"
+	adjusted = client.adjustPayload(payload).(*types.NetworkConfigSection)
+	ipScope = adjusted.NetworkConfig[0].Configuration.IPScopes.IPScope
+	if ipScope.SubnetPrefixLength != 0 || ipScope.Netmask != "255.255.240.0" {
+		t.Errorf("unexpected IP scope for API 31.0: %+v", ipScope)
+	}
+
+	adminVdc := types.AdminVdc{Vdc: types.Vdc{Name: "vdc", DefaultComputePolicy: &types.Reference{ID: "policy"}}}
+	adjustedVdc := client.adjustPayload(&adminVdc).(*types.AdminVdc)
+	if adjustedVdc.DefaultComputePolicy != nil || adjustedVdc.Name != "vdc" || adminVdc.DefaultComputePolicy == nil {
+		t.Errorf("unexpected compute policies for API 31.0: %+v and %+v", adjustedVdc.Vdc, adminVdc.Vdc)
+	}
+}
+
+// Checks that the adjusted payload is the one sent to vCD
+func TestExecuteRequest_adjustedPayload(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	const networkConfigPath = "/api/vApp/vapp-1/networkConfigSection/"
+	server.Handle(http.MethodPut, networkConfigPath, vcdtest.Response{Status: http.StatusNoContent})
+
+	client := &newMockClient(t, server).Client
+	client.APIVersion = "34.0"
+	err := client.ExecuteRequestWithoutResponse(server.URL()+networkConfigPath, http.MethodPut,
+		types.MimeNetworkConfigSection, "error updating network: %s", newIsolatedNetworkPayload())
+	if err != nil {
+		t.Fatalf("error sending request: %s", err)
+	}
+	requests := server.RequestsTo(http.MethodPut, networkConfigPath)
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(requests))
+	}
+	body := requests[0].Body
+	if !strings.Contains(body, "<SubnetPrefixLength>24</SubnetPrefixLength>") || strings.Contains(body, "<Netmask>") {
+		t.Errorf("unexpected payload sent:\n%s", body)
+	}
+}
@@ -0,0 +1,278 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/util"
+)
+
+// Network backing types of a provider VDC
+const (
+	ProviderVdcBackingNsxV = "NSX-V"
+	ProviderVdcBackingNsxT = "NSX-T"
+)
+
+// ProviderVdc is the admin view of a provider VDC. Provider VDCs are only available to system
+// administrators.
+type ProviderVdc struct {
+	ProviderVdc *types.ProviderVdc
+	client      *Client
+}
+
+// NewProviderVdc creates a new provider VDC structure which still needs to have ProviderVdc
+// attribute populated
+func NewProviderVdc(cli *Client) *ProviderVdc {
+	return &ProviderVdc{
+		ProviderVdc: new(types.ProviderVdc),
+		client:      cli,
+	}
+}
+
+// QueryProviderVdcs returns the query records of all provider VDCs, which include the
+// CPU, memory and storage capacity, allocation and usage of each provider VDC
+func QueryProviderVdcs(vcdClient *VCDClient) ([]*types.QueryResultVMWProviderVdcRecordType, error) {
+	results, err := vcdClient.Client.QueryAllPages("providerVdc", nil)
+	if err != nil {
+		return nil, fmt.Errorf("error querying provider VDCs: %s", err)
+	}
+	return results.Results.VMWProviderVdcRecord, nil
+}
+
+// GetProviderVdcByName retrieves the provider VDC with the given name
+func GetProviderVdcByName(vcdClient *VCDClient, name string) (*ProviderVdc, error) {
+	records, err := QueryProviderVdcs(vcdClient)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if record.Name == name {
+			return GetProviderVdcByHref(vcdClient, record.HREF)
+		}
+	}
+	return nil, fmt.Errorf("provider VDC %s not found", name)
+}
+
+// GetProviderVdcByHref retrieves the provider VDC with the given admin HREF
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/GET-ProviderVdc.html
+func GetProviderVdcByHref(vcdClient *VCDClient, href string) (*ProviderVdc, error) {
+	providerVdc := NewProviderVdc(&vcdClient.Client)
+	_, err := vcdClient.Client.ExecuteRequest(href, http.MethodGet,
+		"", "error retrieving provider VDC: %s", nil, providerVdc.ProviderVdc)
+	if err != nil {
+		return nil, err
+	}
+	return providerVdc, nil
+}
+
+// Refresh retrieves the provider VDC again
+func (providerVdc *ProviderVdc) Refresh() error {
+	if providerVdc.ProviderVdc.HREF == "" {
+		return fmt.Errorf("cannot refresh, Object is empty")
+	}
+	href := providerVdc.ProviderVdc.HREF
+
+	// Unmarshal into an empty struct, otherwise we end up with duplicate elements in
+	// slices, and keep the current one when the request fails.
+	refreshed := &types.ProviderVdc{}
+
+	_, err := providerVdc.client.ExecuteRequest(href, http.MethodGet,
+		"", "error refreshing provider VDC: %s", nil, refreshed)
+	if err != nil {
+		return err
+	}
+	providerVdc.ProviderVdc = refreshed
+	return nil
+}
+
+// GetExtendedInfo retrieves the extension view of the provider VDC, which contains the
+// vSphere resource pools, datastores and hosts backing it
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/GET-VMWProviderVdc.html
+func (providerVdc *ProviderVdc) GetExtendedInfo() (*types.VMWProviderVdc, error) {
+	if providerVdc.ProviderVdc.HREF == "" {
+		return nil, fmt.Errorf("cannot retrieve extended info, Object is empty")
+	}
+	href := strings.Replace(providerVdc.ProviderVdc.HREF, "/api/admin/providervdc/", "/api/admin/extension/providervdc/", 1)
+
+	extendedInfo := &types.VMWProviderVdc{}
+	_, err := providerVdc.client.ExecuteRequest(href, http.MethodGet,
+		"", "error retrieving provider VDC extended info: %s", nil, extendedInfo)
+	if err != nil {
+		return nil, err
+	}
+	return extendedInfo, nil
+}
+
+// GetResourcePools returns the references to the vSphere resource pools backing the provider VDC
+func (providerVdc *ProviderVdc) GetResourcePools() ([]*types.VimObjectRef, error) {
+	extendedInfo, err := providerVdc.GetExtendedInfo()
+	if err != nil {
+		return nil, err
+	}
+	if extendedInfo.ResourcePoolRefs == nil {
+		return nil, nil
+	}
+	return extendedInfo.ResourcePoolRefs.VimObjectRef, nil
+}
+
+// GetStorageProfiles returns the query records of the storage profiles of the provider VDC,
+// with their capacity and usage
+func (providerVdc *ProviderVdc) GetStorageProfiles() ([]*types.QueryResultProviderVdcStorageProfileRecordType, error) {
+	results, err := providerVdc.client.QueryAllPages("providerVdcStorageProfile", &QueryOptions{
+		Filter: NewQueryFilter().Equal("providerVdc", providerVdc.ProviderVdc.HREF),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error querying provider VDC storage profiles: %s", err)
+	}
+	return results.Results.ProviderVdcStorageProfileRecord, nil
+}
+
+// GetNsxBackingType returns the network backing of the provider VDC: ProviderVdcBackingNsxT or
+// ProviderVdcBackingNsxV. NSX-T backing is only reported to clients using API 32.0+.
+func (providerVdc *ProviderVdc) GetNsxBackingType() (string, error) {
+	extendedInfo, err := providerVdc.GetExtendedInfo()
+	if err != nil {
+		return "", err
+	}
+	if extendedInfo.NsxTManagerReference != nil && extendedInfo.NsxTManagerReference.HREF != "" {
+		return ProviderVdcBackingNsxT, nil
+	}
+	return ProviderVdcBackingNsxV, nil
+}
+
+// CreateProviderVdc creates a provider VDC backed by the given vSphere resource pools and storage
+// profiles, waits for the creation to complete and returns the admin view of the new provider VDC.
+// NSX-T backed provider VDCs need NsxTManagerReference and a network pool.
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/POST-CreateProviderVdc.html
+func CreateProviderVdc(vcdClient *VCDClient, params *types.VMWProviderVdcParams) (*ProviderVdc, error) {
+	util.Logger.Printf("[TRACE] CreateProviderVdc - creating provider VDC %#v", params)
+
+	if params == nil || params.Name == "" {
+		return nil, fmt.Errorf("provider VDC name is required")
+	}
+	if params.ResourcePoolRefs == nil || len(params.ResourcePoolRefs.VimObjectRef) == 0 {
+		return nil, fmt.Errorf("provider VDC %s needs at least one resource pool", params.Name)
+	}
+	if len(params.VimServer) == 0 {
+		return nil, fmt.Errorf("provider VDC %s needs a vCenter server", params.Name)
+	}
+	if len(params.StorageProfile) == 0 {
+		return nil, fmt.Errorf("provider VDC %s needs at least one storage profile", params.Name)
+	}
+
+	createHREF := vcdClient.Client.VCDHREF
+	createHREF.Path += "/admin/extension/providervdcsparams"
+
+	created := &types.VMWProviderVdc{}
+	_, err := vcdClient.Client.ExecuteRequest(createHREF.String(), http.MethodPost,
+		types.MimeCreateProviderVdcParams, "error creating provider VDC: %s", params, created)
+	if err != nil {
+		return nil, err
+	}
+	if created.Tasks != nil {
+		for _, taskInProgress := range created.Tasks.Task {
+			task := NewTask(&vcdClient.Client)
+			task.Task = taskInProgress
+			err = task.WaitTaskCompletion()
+			if err != nil {
+				return nil, fmt.Errorf("error waiting for the creation of provider VDC %s: %s", params.Name, err)
+			}
+		}
+	}
+	return GetProviderVdcByHref(vcdClient, providerVdcAdminHref(created.HREF))
+}
+
+// Update sends the name and description of the provider VDC to vCD
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/PUT-ProviderVdc.html
+func (providerVdc *ProviderVdc) Update() error {
+	if providerVdc.ProviderVdc.HREF == "" {
+		return fmt.Errorf("cannot update, Object is empty")
+	}
+
+	// Read-only elements are not sent back
+	payload := *providerVdc.ProviderVdc
+	payload.Xmlns = types.XMLNamespaceVCloud
+	payload.Link = nil
+	payload.Tasks = nil
+	payload.ComputeCapacity = nil
+	payload.StorageProfiles = nil
+	payload.Capabilities = nil
+	payload.Vdcs = nil
+	payload.NetworkPoolReferences = nil
+
+	updated := NewProviderVdc(providerVdc.client)
+	_, err := providerVdc.client.ExecuteRequest(providerVdc.ProviderVdc.HREF, http.MethodPut,
+		types.MimeProviderVdc, "error updating provider VDC: %s", &payload, updated.ProviderVdc)
+	if err != nil {
+		return err
+	}
+	if updated.ProviderVdc.Tasks != nil {
+		for _, taskInProgress := range updated.ProviderVdc.Tasks.Task {
+			task := NewTask(providerVdc.client)
+			task.Task = taskInProgress
+			err = task.WaitTaskCompletion()
+			if err != nil {
+				return fmt.Errorf("error waiting for the update of provider VDC: %s", err)
+			}
+		}
+	}
+	return providerVdc.Refresh()
+}
+
+// Enable enables the provider VDC, so that org VDCs can be created on it
+func (providerVdc *ProviderVdc) Enable() error {
+	return providerVdc.setEnabled("enable")
+}
+
+// Disable disables the provider VDC. Provider VDCs must be disabled before removal.
+func (providerVdc *ProviderVdc) Disable() error {
+	return providerVdc.setEnabled("disable")
+}
+
+// setEnabled runs the enable or disable action on the provider VDC and refreshes it
+func (providerVdc *ProviderVdc) setEnabled(action string) error {
+	if providerVdc.ProviderVdc.HREF == "" {
+		return fmt.Errorf("cannot %s provider VDC, Object is empty", action)
+	}
+	err := providerVdc.client.ExecuteRequestWithoutResponse(providerVdc.ProviderVdc.HREF+"/action/"+action,
+		http.MethodPost, "", "error running "+action+" on provider VDC: %s", nil)
+	if err != nil {
+		return err
+	}
+	return providerVdc.Refresh()
+}
+
+// Delete removes the provider VDC, which must be disabled and have no org VDCs.
+// Returns the removal task.
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/DELETE-ProviderVdc.html
+func (providerVdc *ProviderVdc) Delete() (Task, error) {
+	if providerVdc.ProviderVdc.HREF == "" {
+		return Task{}, fmt.Errorf("cannot delete, Object is empty")
+	}
+	return providerVdc.client.ExecuteTaskRequest(providerVdc.ProviderVdc.HREF, http.MethodDelete,
+		"", "error deleting provider VDC: %s", nil)
+}
+
+// DeleteWait removes the provider VDC and waits for the task to complete
+func (providerVdc *ProviderVdc) DeleteWait() error {
+	task, err := providerVdc.Delete()
+	if err != nil {
+		return err
+	}
+	err = task.WaitTaskCompletion()
+	if err != nil {
+		return fmt.Errorf("couldn't finish removing provider VDC %#v", err)
+	}
+	return nil
+}
+
+// providerVdcAdminHref returns the admin HREF of a provider VDC from its extension HREF
+func providerVdcAdminHref(extensionHref string) string {
+	return strings.Replace(extensionHref, "/api/admin/extension/providervdc/", "/api/admin/providervdc/", 1)
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+	. "gopkg.in/check.v1"
+)
+
+func (vcd *TestVCD) Test_ProviderVdc(check *C) {
+	if vcd.skipAdminTests {
+		check.Skip(fmt.Sprintf(TestRequiresSysAdminPrivileges, check.TestName()))
+	}
+	if vcd.config.VCD.ProviderVdc.Name == "" {
+		check.Skip("no provider VDC found in configuration")
+	}
+
+	records, err := QueryProviderVdcs(vcd.client)
+	check.Assert(err, IsNil)
+	check.Assert(len(records), Not(Equals), 0)
+
+	providerVdc, err := GetProviderVdcByName(vcd.client, vcd.config.VCD.ProviderVdc.Name)
+	check.Assert(err, IsNil)
+	check.Assert(providerVdc.ProviderVdc.Name, Equals, vcd.config.VCD.ProviderVdc.Name)
+	check.Assert(providerVdc.ProviderVdc.ComputeCapacity, NotNil)
+	check.Assert(providerVdc.ProviderVdc.ComputeCapacity.Cpu.Total > 0, Equals, true)
+
+	err = providerVdc.Refresh()
+	check.Assert(err, IsNil)
+
+	resourcePools, err := providerVdc.GetResourcePools()
+	check.Assert(err, IsNil)
+	check.Assert(len(resourcePools), Not(Equals), 0)
+	check.Assert(resourcePools[0].MoRef, Not(Equals), "")
+
+	storageProfiles, err := providerVdc.GetStorageProfiles()
+	check.Assert(err, IsNil)
+	found := false
+	for _, storageProfile := range storageProfiles {
+		if storageProfile.Name == vcd.config.VCD.ProviderVdc.StorageProfile {
+			found = true
+		}
+	}
+	check.Assert(found, Equals, true)
+
+	backing, err := providerVdc.GetNsxBackingType()
+	check.Assert(err, IsNil)
+	check.Assert(backing == ProviderVdcBackingNsxV || backing == ProviderVdcBackingNsxT, Equals, true)
+}
+
+// Checks the creation of a provider VDC against a fake vCD: the creation tasks are waited for and
+// the admin view of the provider VDC is returned
+func TestCreateProviderVdc(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	const pvdcId = "88888888-8888-8888-8888-888888888888"
+	server.HandleXML(http.MethodPost, "/api/admin/extension/providervdcsparams", http.StatusCreated,
+		`<vmext:VMWProviderVdc xmlns:vmext="http://www.vmware.com/vcloud/extension/v1.5" xmlns="http://www.vmware.com/vcloud/v1.5"
+		  name="pvdc1" href="{{server}}/api/admin/extension/providervdc/`+pvdcId+`">
+		  <Tasks><Task status="running" href="{{server}}`+vcdtest.MockTaskPath+`"/></Tasks>
+		</vmext:VMWProviderVdc>`)
+	server.HandleXML(http.MethodGet, "/api/admin/providervdc/"+pvdcId, http.StatusOK,
+		`<ProviderVdc xmlns="http://www.vmware.com/vcloud/v1.5" name="pvdc1" href="{{server}}/api/admin/providervdc/`+pvdcId+`">
+		  <IsEnabled>true</IsEnabled>
+		</ProviderVdc>`)
+
+	vcdClient := newMockClient(t, server)
+
+	_, err := CreateProviderVdc(vcdClient, &types.VMWProviderVdcParams{Name: "pvdc1"})
+	if err == nil {
+		t.Errorf("expected error creating provider VDC without resource pools")
+	}
+
+	enabled := true
+	vcenter := &types.Reference{HREF: server.URL() + "/api/admin/extension/vimServer/vc1"}
+	providerVdc, err := CreateProviderVdc(vcdClient, &types.VMWProviderVdcParams{
+		Name: "pvdc1",
+		ResourcePoolRefs: &types.VimObjectRefs{VimObjectRef: []*types.VimObjectRef{{
+			VimServerRef:  vcenter,
+			MoRef:         "resgroup-8",
+			VimObjectType: "RESOURCE_POOL",
+		}}},
+		VimServer:            []*types.Reference{vcenter},
+		NsxTManagerReference: &types.Reference{HREF: server.URL() + "/api/admin/extension/nsxtManagers/nsxt1"},
+		IsEnabled:            &enabled,
+		StorageProfile:       []string{"*"},
+	})
+	if err != nil {
+		t.Fatalf("error creating provider VDC: %s", err)
+	}
+	if providerVdc.ProviderVdc.Name != "pvdc1" || providerVdc.ProviderVdc.IsEnabled == nil || !*providerVdc.ProviderVdc.IsEnabled {
+		t.Errorf("unexpected provider VDC: %#v", providerVdc.ProviderVdc)
+	}
+
+	posts := server.RequestsTo(http.MethodPost, "/api/admin/extension/providervdcsparams")
+	if len(posts) != 1 {
+		t.Fatalf("expected one creation request, got %d", len(posts))
+	}
+	for _, expected := range []string{
+		`<VMWProviderVdcParams xmlns="http://www.vmware.com/vcloud/extension/v1.5" name="pvdc1">`,
+		`<MoRef>resgroup-8</MoRef>`,
+		`<StorageProfile>*</StorageProfile>`,
+	} {
+		if !strings.Contains(posts[0].Body, expected) {
+			t.Errorf("expected %s in payload %s", expected, posts[0].Body)
+		}
+	}
+	if len(server.RequestsTo(http.MethodGet, vcdtest.MockTaskPath)) == 0 {
+		t.Errorf("expected the creation task to be waited for")
+	}
+}
@@ -7,6 +7,10 @@ package govcd
 import (
 	"fmt"
 	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
 
 	"github.com/vmware/go-vcloud-director/v2/types/v56"
 )
@@ -70,3 +74,374 @@ func getResult(client *Client, request *http.Request) (Results, error) {
 
 	return *results, nil
 }
+
+// QueryWithNotEncodedParams runs a query using the client, for functions which don't have access
+// to a VCDClient or a Vdc
+func (client *Client) QueryWithNotEncodedParams(params map[string]string, notEncodedParams map[string]string) (Results, error) {
+	queryUrl := client.VCDHREF
+	queryUrl.Path += "/query"
+	req := client.NewRequestWitNotEncodedParams(params, notEncodedParams, http.MethodGet, queryUrl, nil)
+	req.Header.Add("Accept", "vnd.vmware.vcloud.org+xml;version="+client.APIVersion)
+
+	return getResult(client, req)
+}
+
+// QueryOptions are the optional parameters of a query run with Client.Query
+type QueryOptions struct {
+	Filter   *QueryFilter // Conditions the records must satisfy
+	SortAsc  string       // Field to sort the records by, in ascending order
+	SortDesc string       // Field to sort the records by, in descending order
+	Fields   []string     // Fields to include in the records. All fields are returned when empty
+	PageSize int          // Number of records per page. The vCD default is used when 0
+	Page     int          // Page to retrieve, starting from 1. The first page is used when 0
+	// Maximum number of records retrieved by the functions following all the pages.
+	// DefaultQueryMaxRecords is used when 0
+	MaxRecords int
+}
+
+// Defaults of the functions following all the pages of a query
+const (
+	DefaultQueryPageSize   = 128
+	DefaultQueryMaxRecords = 10000
+)
+
+// QueryFilter builds the filter expression of a query. All the conditions must be satisfied.
+// Values are escaped, so they can contain the characters used by the filter syntax (; , ( )).
+// Example: NewQueryFilter().Equal("name", "my vm").Equal("isDeployed", "true")
+type QueryFilter struct {
+	conditions []string
+}
+
+// NewQueryFilter creates an empty query filter
+func NewQueryFilter() *QueryFilter {
+	return &QueryFilter{}
+}
+
+// Equal adds the condition field == value
+func (filter *QueryFilter) Equal(field, value string) *QueryFilter {
+	return filter.add(field, "==", value)
+}
+
+// NotEqual adds the condition field != value
+func (filter *QueryFilter) NotEqual(field, value string) *QueryFilter {
+	return filter.add(field, "!=", value)
+}
+
+// GreaterThan adds the condition field > value
+func (filter *QueryFilter) GreaterThan(field, value string) *QueryFilter {
+	return filter.add(field, "=gt=", value)
+}
+
+// GreaterOrEqual adds the condition field >= value
+func (filter *QueryFilter) GreaterOrEqual(field, value string) *QueryFilter {
+	return filter.add(field, "=ge=", value)
+}
+
+// LessThan adds the condition field < value
+func (filter *QueryFilter) LessThan(field, value string) *QueryFilter {
+	return filter.add(field, "=lt=", value)
+}
+
+// LessOrEqual adds the condition field <= value
+func (filter *QueryFilter) LessOrEqual(field, value string) *QueryFilter {
+	return filter.add(field, "=le=", value)
+}
+
+// metadataFilterTypes maps the types of metadata values to their names in query filters
+var metadataFilterTypes = map[string]string{
+	types.MetadataStringValue:   "STRING",
+	types.MetadataNumberValue:   "NUMBER",
+	types.MetadataBooleanValue:  "BOOLEAN",
+	types.MetadataDateTimeValue: "DATETIME",
+}
+
+// Metadata adds the condition metadata:key == value, matching the records of the entities which
+// have the metadata entry in the given domain (types.MetadataDomainGeneral, or "" for it, or
+// types.MetadataDomainSystem, which uses metadata@SYSTEM:key). The value is compared according to
+// its type. A value without type is compared as a string.
+func (filter *QueryFilter) Metadata(key string, value types.TypedValue, domain string) *QueryFilter {
+	field := "metadata"
+	if domain == types.MetadataDomainSystem {
+		field += "@" + types.MetadataDomainSystem
+	}
+	valueType, ok := metadataFilterTypes[value.XsiType]
+	if !ok {
+		valueType = "STRING"
+	}
+	filter.conditions = append(filter.conditions,
+		field+":"+escapeFilterValue(key)+"=="+valueType+":"+escapeFilterValue(value.Value))
+	return filter
+}
+
+// add appends a condition with the escaped value
+func (filter *QueryFilter) add(field, operator, value string) *QueryFilter {
+	filter.conditions = append(filter.conditions, field+operator+escapeFilterValue(value))
+	return filter
+}
+
+// String returns the filter expression, ready to be used as the (not encoded) filter parameter
+func (filter *QueryFilter) String() string {
+	if filter == nil || len(filter.conditions) == 0 {
+		return ""
+	}
+	return "(" + strings.Join(filter.conditions, ";") + ")"
+}
+
+// escapeFilterValue escapes the filter syntax characters of value as the OpenAPI filters do (see
+// fiqlEq), and URL-encodes it, as the filter is not encoded by the query functions
+func escapeFilterValue(value string) string {
+	return url.QueryEscape(fiqlValueEscaper.Replace(value))
+}
+
+// Query runs a query of the given type (see the Qt* constants in types) with the given options,
+// which can be nil. Only the requested page is returned.
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/GET-QueryList.html
+func (client *Client) Query(queryType string, options *QueryOptions) (Results, error) {
+	if queryType == "" {
+		return Results{}, fmt.Errorf("query type is required")
+	}
+	params, notEncodedParams, err := buildQueryParams(queryType, options)
+	if err != nil {
+		return Results{}, err
+	}
+	return client.QueryWithNotEncodedParams(params, notEncodedParams)
+}
+
+// QueryAllPages runs a query of the given type and follows the nextPage links until all the records
+// are retrieved. The records of all the pages are returned together. options.Page is ignored.
+// An error is returned if the query has more than options.MaxRecords records, instead of a
+// truncated result.
+func (client *Client) QueryAllPages(queryType string, options *QueryOptions) (Results, error) {
+	pageOptions := QueryOptions{}
+	if options != nil {
+		pageOptions = *options
+	}
+	pageOptions.Page = 0
+	if pageOptions.PageSize == 0 {
+		pageOptions.PageSize = DefaultQueryPageSize
+	}
+	maxRecords := pageOptions.MaxRecords
+	if maxRecords <= 0 {
+		maxRecords = DefaultQueryMaxRecords
+	}
+
+	results, err := client.Query(queryType, &pageOptions)
+	if err != nil {
+		return Results{}, err
+	}
+	if int(results.Results.Total) > maxRecords {
+		return Results{}, fmt.Errorf("query of type %s returns %d records, more than the maximum of %d",
+			queryType, int(results.Results.Total), maxRecords)
+	}
+
+	for nextPage := findQueryLink(results.Results.Link, types.RelNextPage); nextPage != ""; {
+		page, err := client.queryByHref(nextPage)
+		if err != nil {
+			return Results{}, err
+		}
+		appendQueryRecords(results.Results, page.Results)
+		nextPage = findQueryLink(page.Results.Link, types.RelNextPage)
+	}
+	results.Results.Link = nil
+	results.Results.Page = 1
+	results.Results.PageSize = int(results.Results.Total)
+	return results, nil
+}
+
+// queryByHref runs the query found at href, e.g. the nextPage link of a query result, keeping
+// its parameters as they are
+func (client *Client) queryByHref(href string) (Results, error) {
+	queryUrl, err := url.ParseRequestURI(href)
+	if err != nil {
+		return Results{}, fmt.Errorf("error parsing query url %s: %s", href, err)
+	}
+	notEncodedParams := map[string]string{}
+	for _, param := range strings.Split(queryUrl.RawQuery, "&") {
+		keyValue := strings.SplitN(param, "=", 2)
+		if len(keyValue) == 2 {
+			notEncodedParams[keyValue[0]] = keyValue[1]
+		}
+	}
+	queryUrl.RawQuery = ""
+	req := client.NewRequestWitNotEncodedParams(nil, notEncodedParams, http.MethodGet, *queryUrl, nil)
+	req.Header.Add("Accept", "vnd.vmware.vcloud.org+xml;version="+client.APIVersion)
+
+	return getResult(client, req)
+}
+
+// findQueryLink returns the HREF of the link with the given relation, or an empty string
+func findQueryLink(links []*types.Link, rel string) string {
+	for _, link := range links {
+		if link.Rel == rel {
+			return link.HREF
+		}
+	}
+	return ""
+}
+
+// appendQueryRecords appends the records of all types found in source to the ones in destination
+func appendQueryRecords(destination, source *types.QueryResultRecordsType) {
+	destinationValue := reflect.ValueOf(destination).Elem()
+	sourceValue := reflect.ValueOf(source).Elem()
+	for i := 0; i < destinationValue.NumField(); i++ {
+		field := destinationValue.Type().Field(i)
+		// Records are slices of pointers to structures, Link is excluded
+		if field.Type.Kind() != reflect.Slice || field.Name == "Link" {
+			continue
+		}
+		destinationValue.Field(i).Set(reflect.AppendSlice(destinationValue.Field(i), sourceValue.Field(i)))
+	}
+}
+
+// buildQueryParams returns the encoded and not encoded parameters of a query
+func buildQueryParams(queryType string, options *QueryOptions) (map[string]string, map[string]string, error) {
+	params := map[string]string{"type": queryType}
+	notEncodedParams := map[string]string{}
+	if options == nil {
+		return params, notEncodedParams, nil
+	}
+	if options.SortAsc != "" && options.SortDesc != "" {
+		return nil, nil, fmt.Errorf("only one of SortAsc and SortDesc can be set")
+	}
+	if options.PageSize < 0 || options.Page < 0 {
+		return nil, nil, fmt.Errorf("page and page size cannot be negative")
+	}
+	if options.SortAsc != "" {
+		params["sortAsc"] = options.SortAsc
+	}
+	if options.SortDesc != "" {
+		params["sortDesc"] = options.SortDesc
+	}
+	if len(options.Fields) > 0 {
+		params["fields"] = strings.Join(options.Fields, ",")
+	}
+	if options.PageSize > 0 {
+		params["pageSize"] = strconv.Itoa(options.PageSize)
+	}
+	if options.Page > 0 {
+		params["page"] = strconv.Itoa(options.Page)
+	}
+	if filter := options.Filter.String(); filter != "" {
+		notEncodedParams["filter"] = filter
+	}
+	return params, notEncodedParams, nil
+}
+
+// The following functions return the records of all the pages (see QueryAllPages)
+
+// QueryVms returns the VM records of the org
+func (client *Client) QueryVms(options *QueryOptions) ([]*types.QueryResultVMRecordType, error) {
+	results, err := client.QueryAllPages(types.QtVm, options)
+	if err != nil {
+		return nil, err
+	}
+	return results.Results.VMRecord, nil
+}
+
+// QueryAdminVms returns the VM records of all orgs. Only available to system administrators.
+func (client *Client) QueryAdminVms(options *QueryOptions) ([]*types.QueryResultVMRecordType, error) {
+	results, err := client.QueryAllPages(types.QtAdminVm, options)
+	if err != nil {
+		return nil, err
+	}
+	return results.Results.AdminVMRecord, nil
+}
+
+// QueryVApps returns the vApp records of the org
+func (client *Client) QueryVApps(options *QueryOptions) ([]*types.QueryResultVAppRecordType, error) {
+	results, err := client.QueryAllPages(types.QtVapp, options)
+	if err != nil {
+		return nil, err
+	}
+	return results.Results.VAppRecord, nil
+}
+
+// QueryAdminVApps returns the vApp records of all orgs. Only available to system administrators.
+func (client *Client) QueryAdminVApps(options *QueryOptions) ([]*types.QueryResultVAppRecordType, error) {
+	results, err := client.QueryAllPages(types.QtAdminVapp, options)
+	if err != nil {
+		return nil, err
+	}
+	return results.Results.AdminVAppRecord, nil
+}
+
+// QueryOrgVdcNetworks returns the org VDC network records of the org
+func (client *Client) QueryOrgVdcNetworks(options *QueryOptions) ([]*types.QueryResultOrgVdcNetworkRecordType, error) {
+	results, err := client.QueryAllPages(types.QtOrgVdcNetwork, options)
+	if err != nil {
+		return nil, err
+	}
+	return results.Results.OrgVdcNetworkRecord, nil
+}
+
+// QueryMedia returns the media records of the org
+func (client *Client) QueryMedia(options *QueryOptions) ([]*types.MediaRecordType, error) {
+	results, err := client.QueryAllPages(types.QtMedia, options)
+	if err != nil {
+		return nil, err
+	}
+	return results.Results.MediaRecord, nil
+}
+
+// QueryAdminMedia returns the media records of all orgs. Only available to system administrators.
+func (client *Client) QueryAdminMedia(options *QueryOptions) ([]*types.MediaRecordType, error) {
+	results, err := client.QueryAllPages(types.QtAdminMedia, options)
+	if err != nil {
+		return nil, err
+	}
+	return results.Results.AdminMediaRecord, nil
+}
+
+// QueryOrgVdcs returns the VDC records of the org
+func (client *Client) QueryOrgVdcs(options *QueryOptions) ([]*types.QueryResultOrgVdcRecordType, error) {
+	results, err := client.QueryAllPages(types.QtOrgVdc, options)
+	if err != nil {
+		return nil, err
+	}
+	return results.Results.OrgVdcRecord, nil
+}
+
+// QueryAdminOrgVdcs returns the VDC records of all orgs. Only available to system administrators.
+func (client *Client) QueryAdminOrgVdcs(options *QueryOptions) ([]*types.QueryResultOrgVdcRecordType, error) {
+	results, err := client.QueryAllPages(types.QtAdminOrgVdc, options)
+	if err != nil {
+		return nil, err
+	}
+	return results.Results.AdminOrgVdcRecord, nil
+}
+
+// QueryTasks returns the task records of the org
+func (client *Client) QueryTasks(options *QueryOptions) ([]*types.QueryResultTaskRecordType, error) {
+	results, err := client.QueryAllPages(types.QtTask, options)
+	if err != nil {
+		return nil, err
+	}
+	return results.Results.TaskRecord, nil
+}
+
+// QueryAdminTasks returns the task records of all orgs. Only available to system administrators.
+func (client *Client) QueryAdminTasks(options *QueryOptions) ([]*types.QueryResultTaskRecordType, error) {
+	results, err := client.QueryAllPages(types.QtAdminTask, options)
+	if err != nil {
+		return nil, err
+	}
+	return results.Results.AdminTaskRecord, nil
+}
+
+// QueryEvents returns the event records of the org
+func (client *Client) QueryEvents(options *QueryOptions) ([]*types.QueryResultEventRecordType, error) {
+	results, err := client.QueryAllPages(types.QtEvent, options)
+	if err != nil {
+		return nil, err
+	}
+	return results.Results.EventRecord, nil
+}
+
+// QueryAdminEvents returns the event records of all orgs. Only available to system administrators.
+func (client *Client) QueryAdminEvents(options *QueryOptions) ([]*types.QueryResultEventRecordType, error) {
+	results, err := client.QueryAllPages(types.QtAdminEvent, options)
+	if err != nil {
+		return nil, err
+	}
+	return results.Results.AdminEventRecord, nil
+}
@@ -6,7 +6,15 @@
 package govcd
 
 import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
 	. "gopkg.in/check.v1"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
 )
 
 // TODO: Need to add a check to check the contents of the query
@@ -15,3 +23,187 @@ func (vcd *TestVCD) Test_Query(check *C) {
 	_, err := vcd.client.Query(map[string]string{"type": "vm"})
 	check.Assert(err, IsNil)
 }
+
+func (vcd *TestVCD) Test_ClientQuery(check *C) {
+	if vcd.skipVappTests {
+		check.Skip("Skipping test because vapp was not successfully created at setup")
+	}
+	client := &vcd.client.Client
+
+	vapps, err := client.QueryVApps(&QueryOptions{
+		Filter:  NewQueryFilter().Equal("name", vcd.vapp.VApp.Name),
+		SortAsc: "name",
+	})
+	check.Assert(err, IsNil)
+	check.Assert(len(vapps), Equals, 1)
+	check.Assert(vapps[0].HREF, Equals, vcd.vapp.VApp.HREF)
+
+	vms, err := client.QueryVms(&QueryOptions{
+		Filter:   NewQueryFilter().Equal("container", vcd.vapp.VApp.HREF),
+		Fields:   []string{"name", "containerName"},
+		PageSize: 10,
+	})
+	check.Assert(err, IsNil)
+	for _, vm := range vms {
+		check.Assert(vm.VAppParentName, Equals, vcd.vapp.VApp.Name)
+	}
+
+	networks, err := client.QueryOrgVdcNetworks(&QueryOptions{Filter: NewQueryFilter().Equal("name", vcd.config.VCD.Networks[0])})
+	check.Assert(err, IsNil)
+	check.Assert(len(networks), Equals, 1)
+
+	// Names containing filter syntax characters are escaped
+	vapps, err = client.QueryVApps(&QueryOptions{Filter: NewQueryFilter().Equal("name", "no;such,vapp()")})
+	check.Assert(err, IsNil)
+	check.Assert(len(vapps), Equals, 0)
+
+	_, err = client.Query(types.QtVm, &QueryOptions{SortAsc: "name", SortDesc: "name"})
+	check.Assert(err, NotNil)
+}
+
+func TestQueryFilter(t *testing.T) {
+	tests := []struct {
+		filter   *QueryFilter
+		expected string
+	}{
+		{nil, ""},
+		{NewQueryFilter(), ""},
+		{NewQueryFilter().Equal("name", "vm1"), "(name==vm1)"},
+		{NewQueryFilter().Equal("name", "my vm").NotEqual("status", "POWERED_OFF"), "(name==my+vm;status!=POWERED_OFF)"},
+		{NewQueryFilter().GreaterThan("numberOfCpus", "2").LessOrEqual("memoryMB", "4096"), "(numberOfCpus=gt=2;memoryMB=le=4096)"},
+		{NewQueryFilter().Equal("name", "a;b,c(d)"), "(name==a%253Bb%252Cc%2528d%2529)"},
+		{NewQueryFilter().Metadata("env", types.TypedValue{XsiType: types.MetadataStringValue, Value: "prod"}, ""), "(metadata:env==STRING:prod)"},
+		{NewQueryFilter().Equal("vdc", "vdc1").Metadata("cost", types.TypedValue{XsiType: types.MetadataNumberValue, Value: "10"}, types.MetadataDomainSystem),
+			"(vdc==vdc1;metadata@SYSTEM:cost==NUMBER:10)"},
+		{NewQueryFilter().Metadata("my tag", types.TypedValue{Value: "a;b"}, types.MetadataDomainGeneral), "(metadata:my+tag==STRING:a%253Bb)"},
+	}
+	for _, test := range tests {
+		if got := test.filter.String(); got != test.expected {
+			t.Errorf("expected filter %s, got %s", test.expected, got)
+		}
+	}
+}
+
+// Checks that a value with the FIQL special characters is escaped the same way in the filters of the
+// query service and of OpenAPI
+func TestEscapeFilterValue(t *testing.T) {
+	name := "web,db;(prod)"
+	escaped := escapeFilterValue(name)
+	if escaped != "web%252Cdb%253B%2528prod%2529" {
+		t.Errorf("unexpected escaped value: %s", escaped)
+	}
+	// The query functions send the filter as is, while OpenAPI encodes the query parameters
+	queryFilter, err := url.QueryUnescape(NewQueryFilter().Equal("name", name).String())
+	if err != nil {
+		t.Fatalf("error decoding filter: %s", err)
+	}
+	if queryFilter != "("+fiqlEq("name", name)+")" {
+		t.Errorf("filters differ: %s for the query service, %s for OpenAPI", queryFilter, fiqlEq("name", name))
+	}
+}
+
+func TestBuildQueryParams(t *testing.T) {
+	params, notEncodedParams, err := buildQueryParams(types.QtVapp, &QueryOptions{
+		Filter:   NewQueryFilter().Equal("isDeployed", "true"),
+		SortDesc: "creationDate",
+		Fields:   []string{"name", "status"},
+		PageSize: 25,
+		Page:     2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := map[string]string{"type": "vApp", "sortDesc": "creationDate", "fields": "name,status", "pageSize": "25", "page": "2"}
+	for key, value := range expected {
+		if params[key] != value {
+			t.Errorf("expected %s=%s, got %s", key, value, params[key])
+		}
+	}
+	if notEncodedParams["filter"] != "(isDeployed==true)" {
+		t.Errorf("unexpected filter: %s", notEncodedParams["filter"])
+	}
+
+	_, _, err = buildQueryParams(types.QtVapp, &QueryOptions{SortAsc: "name", SortDesc: "name"})
+	if err == nil {
+		t.Errorf("expected error when sorting in both directions")
+	}
+	_, _, err = buildQueryParams(types.QtVapp, &QueryOptions{PageSize: -1})
+	if err == nil {
+		t.Errorf("expected error with a negative page size")
+	}
+}
+
+// Checks that QueryAllPages follows the nextPage links and refuses to return truncated results
+func TestClient_QueryAllPages(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	server.HandleFunc(http.MethodGet, "/api/query", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("type") != "vm" || r.URL.Query().Get("filter") != "(isDeployed==true)" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/vnd.vmware.vcloud.query.records+xml")
+		page := r.URL.Query().Get("page")
+		nextPage := ""
+		if page == "" || page == "1" {
+			page = "1"
+			nextPage = `<Link rel="nextPage" href="` + server.URL() + `/api/query?type=vm&amp;page=2&amp;pageSize=2&amp;filter=(isDeployed==true)"/>`
+		}
+		_, _ = fmt.Fprintf(w, `<QueryResultRecords xmlns="http://www.vmware.com/vcloud/v1.5" total="3" page="%s" pageSize="2">%s
+<VMRecord name="vm%s-1"/><VMRecord name="vm%s-2"/></QueryResultRecords>`, page, nextPage, page, page)
+	})
+
+	client := &newMockClient(t, server).Client
+	vms, err := client.QueryVms(&QueryOptions{Filter: NewQueryFilter().Equal("isDeployed", "true"), PageSize: 2})
+	if err != nil {
+		t.Fatalf("error querying all pages: %s", err)
+	}
+	if len(vms) != 4 || vms[0].Name != "vm1-1" || vms[3].Name != "vm2-2" {
+		t.Errorf("unexpected records: %d", len(vms))
+	}
+
+	_, err = client.QueryVms(&QueryOptions{Filter: NewQueryFilter().Equal("isDeployed", "true"), PageSize: 2, MaxRecords: 2})
+	if err == nil {
+		t.Errorf("expected error when the query has more records than the maximum")
+	}
+}
+
+// Checks that the org VDC and task records are decoded by their typed queries
+func TestClient_QueryOrgVdcsAndTasks(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	server.HandleFunc(http.MethodGet, "/api/query", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.vmware.vcloud.query.records+xml")
+		switch r.URL.Query().Get("type") {
+		case types.QtOrgVdc:
+			_, _ = fmt.Fprint(w, `<QueryResultRecords xmlns="http://www.vmware.com/vcloud/v1.5" total="1" page="1" pageSize="25">
+<OrgVdcRecord name="vdc1" orgName="org1" allocationModel="AllocationVApp" numberOfVApps="3" isEnabled="true"/></QueryResultRecords>`)
+		case types.QtTask:
+			if r.URL.Query().Get("sortDesc") != "startDate" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = fmt.Fprint(w, `<QueryResultRecords xmlns="http://www.vmware.com/vcloud/v1.5" total="2" page="1" pageSize="25">
+<TaskRecord name="task" status="running" objectName="vapp1"/><TaskRecord name="task" status="success" objectName="vapp2"/></QueryResultRecords>`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	client := &newMockClient(t, server).Client
+	vdcs, err := client.QueryOrgVdcs(nil)
+	if err != nil {
+		t.Fatalf("error querying org VDCs: %s", err)
+	}
+	if len(vdcs) != 1 || vdcs[0].Name != "vdc1" || vdcs[0].NumberOfVApps != 3 || !vdcs[0].IsEnabled {
+		t.Errorf("unexpected org VDC records: %+v", vdcs)
+	}
+
+	tasks, err := client.QueryTasks(&QueryOptions{SortDesc: "startDate"})
+	if err != nil {
+		t.Fatalf("error querying tasks: %s", err)
+	}
+	if len(tasks) != 2 || tasks[0].Status != "running" || tasks[1].ObjectName != "vapp2" {
+		t.Errorf("unexpected task records: %+v", tasks)
+	}
+}
@@ -0,0 +1,156 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// VmQuotas holds the maximum number of deployed (running) and stored VMs of an org or a user.
+// A value of 0 means unlimited.
+type VmQuotas struct {
+	DeployedVmQuota int
+	StoredVmQuota   int
+}
+
+// VmQuotaUsage holds the VM quotas of an org or a user together with the number of VMs
+// that count against them
+type VmQuotaUsage struct {
+	VmQuotas
+	DeployedVms int
+	StoredVms   int
+}
+
+// DeployedVmsRemaining returns how many more VMs can be deployed, or -1 if the quota is unlimited
+func (usage VmQuotaUsage) DeployedVmsRemaining() int {
+	return quotaRemaining(usage.DeployedVmQuota, usage.DeployedVms)
+}
+
+// StoredVmsRemaining returns how many more VMs can be stored, or -1 if the quota is unlimited
+func (usage VmQuotaUsage) StoredVmsRemaining() int {
+	return quotaRemaining(usage.StoredVmQuota, usage.StoredVms)
+}
+
+// quotaRemaining returns the part of quota not used yet, 0 if the quota is exceeded,
+// or -1 if the quota is unlimited
+func quotaRemaining(quota, used int) int {
+	if quota == 0 {
+		return -1
+	}
+	if used >= quota {
+		return 0
+	}
+	return quota - used
+}
+
+// GetVmQuotas retrieves the default VM quotas of the org, which are found in the general settings
+func (adminOrg *AdminOrg) GetVmQuotas() (*VmQuotas, error) {
+	settings, err := adminOrg.GetGeneralSettings()
+	if err != nil {
+		return nil, err
+	}
+	return &VmQuotas{DeployedVmQuota: settings.DeployedVMQuota, StoredVmQuota: settings.StoredVMQuota}, nil
+}
+
+// SetVmQuotas sets the VM quotas of the org. The other general settings are left unchanged.
+func (adminOrg *AdminOrg) SetVmQuotas(quotas VmQuotas) error {
+	if quotas.DeployedVmQuota < 0 || quotas.StoredVmQuota < 0 {
+		return fmt.Errorf("VM quotas cannot be negative")
+	}
+	settings, err := adminOrg.GetGeneralSettings()
+	if err != nil {
+		return err
+	}
+	settings.DeployedVMQuota = quotas.DeployedVmQuota
+	settings.StoredVMQuota = quotas.StoredVmQuota
+	_, err = adminOrg.UpdateGeneralSettings(settings)
+	return err
+}
+
+// GetVmQuotaUsage returns the VM quotas of the org and the number of VMs in the org
+func (adminOrg *AdminOrg) GetVmQuotaUsage() (*VmQuotaUsage, error) {
+	quotas, err := adminOrg.GetVmQuotas()
+	if err != nil {
+		return nil, err
+	}
+	usage := &VmQuotaUsage{VmQuotas: *quotas}
+	usage.StoredVms, usage.DeployedVms, err = adminOrg.countVms("")
+	if err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+// GetVmQuotas returns the VM quotas of the user
+func (user *OrgUser) GetVmQuotas() VmQuotas {
+	return VmQuotas{DeployedVmQuota: user.User.DeployedVmQuota, StoredVmQuota: user.User.StoredVmQuota}
+}
+
+// SetVmQuotas sets the VM quotas of the user
+func (user *OrgUser) SetVmQuotas(quotas VmQuotas) error {
+	if quotas.DeployedVmQuota < 0 || quotas.StoredVmQuota < 0 {
+		return fmt.Errorf("VM quotas cannot be negative")
+	}
+	user.User.DeployedVmQuota = quotas.DeployedVmQuota
+	user.User.StoredVmQuota = quotas.StoredVmQuota
+	return user.Update()
+}
+
+// GetVmQuotaUsage returns the VM quotas of the user and the number of VMs the user owns
+func (user *OrgUser) GetVmQuotaUsage() (*VmQuotaUsage, error) {
+	if user.AdminOrg == nil {
+		return nil, fmt.Errorf("user %s has no parent organization", user.User.Name)
+	}
+	usage := &VmQuotaUsage{VmQuotas: user.GetVmQuotas()}
+	var err error
+	usage.StoredVms, usage.DeployedVms, err = user.AdminOrg.countVms(user.User.Name)
+	if err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+// countVms returns the number of VMs (stored) and deployed VMs in the org, only counting
+// the VMs of owner when it is not empty
+func (adminOrg *AdminOrg) countVms(owner string) (int, int, error) {
+	queryType := types.QtVm
+	orgHref := ""
+	if adminOrg.client.IsSysAdmin {
+		// The admin query covers all orgs: VM records refer to the tenant view of the org
+		queryType = types.QtAdminVm
+		orgHref = strings.Replace(adminOrg.AdminOrg.HREF, "/api/admin/org/", "/api/org/", 1)
+	}
+
+	count := func(deployedOnly bool) (int, error) {
+		filter := NewQueryFilter().Equal("isVAppTemplate", "false")
+		if orgHref != "" {
+			filter.Equal("org", orgHref)
+		}
+		if owner != "" {
+			filter.Equal("ownerName", owner)
+		}
+		if deployedOnly {
+			filter.Equal("isDeployed", "true")
+		}
+		results, err := adminOrg.client.Query(queryType, &QueryOptions{Filter: filter, PageSize: 1})
+		if err != nil {
+			return 0, fmt.Errorf("error counting VMs: %s", err)
+		}
+		return int(results.Results.Total), nil
+	}
+
+	stored, err := count(false)
+	if err != nil {
+		return 0, 0, err
+	}
+	deployed, err := count(true)
+	if err != nil {
+		return 0, 0, err
+	}
+	return stored, deployed, nil
+}
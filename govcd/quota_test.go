@@ -0,0 +1,106 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+	. "gopkg.in/check.v1"
+)
+
+func (vcd *TestVCD) Test_VmQuotas(check *C) {
+	if vcd.skipAdminTests {
+		check.Skip(fmt.Sprintf(TestRequiresSysAdminPrivileges, check.TestName()))
+	}
+	adminOrg, err := GetAdminOrgByName(vcd.client, vcd.org.Org.Name)
+	check.Assert(err, IsNil)
+
+	initialQuotas, err := adminOrg.GetVmQuotas()
+	check.Assert(err, IsNil)
+
+	err = adminOrg.SetVmQuotas(VmQuotas{DeployedVmQuota: 10, StoredVmQuota: 20})
+	check.Assert(err, IsNil)
+	usage, err := adminOrg.GetVmQuotaUsage()
+	check.Assert(err, IsNil)
+	check.Assert(usage.DeployedVmQuota, Equals, 10)
+	check.Assert(usage.StoredVmQuota, Equals, 20)
+	check.Assert(usage.DeployedVms <= usage.StoredVms, Equals, true)
+
+	err = adminOrg.SetVmQuotas(*initialQuotas)
+	check.Assert(err, IsNil)
+
+	// User quotas are checked on the first user of the org, if any
+	if adminOrg.AdminOrg.Users == nil || len(adminOrg.AdminOrg.Users.UserReference) == 0 {
+		return
+	}
+	user, err := adminOrg.GetUserByName(adminOrg.AdminOrg.Users.UserReference[0].Name)
+	check.Assert(err, IsNil)
+	initialUserQuotas := user.GetVmQuotas()
+
+	err = user.SetVmQuotas(VmQuotas{DeployedVmQuota: 3, StoredVmQuota: 5})
+	check.Assert(err, IsNil)
+	userUsage, err := user.GetVmQuotaUsage()
+	check.Assert(err, IsNil)
+	check.Assert(userUsage.DeployedVmQuota, Equals, 3)
+	check.Assert(userUsage.StoredVmQuota, Equals, 5)
+
+	err = user.SetVmQuotas(initialUserQuotas)
+	check.Assert(err, IsNil)
+}
+
+func TestVmQuotaUsage_Remaining(t *testing.T) {
+	usage := VmQuotaUsage{VmQuotas: VmQuotas{DeployedVmQuota: 5, StoredVmQuota: 0}, DeployedVms: 3, StoredVms: 12}
+	if usage.DeployedVmsRemaining() != 2 {
+		t.Errorf("expected 2 deployed VMs remaining, got %d", usage.DeployedVmsRemaining())
+	}
+	if usage.StoredVmsRemaining() != -1 {
+		t.Errorf("expected unlimited stored VMs, got %d", usage.StoredVmsRemaining())
+	}
+	usage.DeployedVms = 7
+	if usage.DeployedVmsRemaining() != 0 {
+		t.Errorf("expected no deployed VMs remaining, got %d", usage.DeployedVmsRemaining())
+	}
+}
+
+// Checks that the owner and the org of the VM count are escaped in the query filter
+func TestAdminOrg_countVms(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	server.HandleXML(http.MethodGet, "/api/query", http.StatusOK,
+		`<QueryResultRecords xmlns="http://www.vmware.com/vcloud/v1.5" total="3" page="1" pageSize="1"/>`)
+
+	vcdClient := newMockClient(t, server)
+	vcdClient.Client.IsSysAdmin = true
+	adminOrg := NewAdminOrg(&vcdClient.Client)
+	adminOrg.AdminOrg.HREF = server.URL() + vcdtest.MockAdminOrgPath
+
+	stored, deployed, err := adminOrg.countVms("user;name,(x)")
+	if err != nil {
+		t.Fatalf("error counting VMs: %s", err)
+	}
+	if stored != 3 || deployed != 3 {
+		t.Errorf("unexpected VM count: %d stored, %d deployed", stored, deployed)
+	}
+	requests := server.RequestsTo(http.MethodGet, "/api/query")
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 queries, got %d", len(requests))
+	}
+	orgFilter := "org==" + escapeFilterValue(server.URL()+vcdtest.MockOrgPath)
+	ownerFilter := "ownerName==" + escapeFilterValue("user;name,(x)")
+	for i, request := range requests {
+		if !strings.Contains(request.RawQuery, "type="+types.QtAdminVm) || !strings.Contains(request.RawQuery, orgFilter) ||
+			!strings.Contains(request.RawQuery, ownerFilter) || strings.Contains(request.RawQuery, "isDeployed") != (i == 1) {
+			t.Errorf("unexpected VM count query: %s", request.RawQuery)
+		}
+	}
+	if !strings.Contains(ownerFilter, `user%253Bname%252C%2528x%2529`) {
+		t.Errorf("unexpected escaped owner: %s", ownerFilter)
+	}
+}
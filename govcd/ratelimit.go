@@ -0,0 +1,132 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// WithRateLimit limits the rate of the requests sent by the client to requestsPerSecond, with
+// bursts of up to burst requests, so that bulk operations (e.g. the creation of hundreds of VMs)
+// do not trip the throttling of the vCD cells. The requests over the limit wait for their turn,
+// or until their context is done. When a retry policy is set (see WithRetryPolicy), every retry
+// is subject to the limit too.
+func WithRateLimit(requestsPerSecond float64, burst int) VCDClientOption {
+	return func(vcdClient *VCDClient) error {
+		if requestsPerSecond <= 0 {
+			return fmt.Errorf("rate limit: the number of requests per second must be positive")
+		}
+		if burst < 1 {
+			return fmt.Errorf("rate limit: the burst must be at least 1")
+		}
+		limiter := newRateLimiter(requestsPerSecond, burst)
+
+		// The limiter goes below the retry transport, if any, to limit the retries as well
+		if retrying, ok := vcdClient.Client.Http.Transport.(*retryTransport); ok {
+			retrying.transport = withRateLimiter(retrying.transport, limiter)
+			return nil
+		}
+		vcdClient.Client.Http.Transport = withRateLimiter(vcdClient.Client.Http.Transport, limiter)
+		return nil
+	}
+}
+
+// withRateLimiter returns transport, whose requests are limited by limiter. A previous limiter of
+// transport is replaced.
+func withRateLimiter(transport http.RoundTripper, limiter *rateLimiter) http.RoundTripper {
+	if limited, ok := transport.(*rateLimitTransport); ok {
+		transport = limited.transport
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &rateLimitTransport{limiter: limiter, transport: transport}
+}
+
+// rateLimitTransport is an http.RoundTripper which waits for the permission of a rate limiter
+// before sending each request
+type rateLimitTransport struct {
+	limiter   *rateLimiter
+	transport http.RoundTripper
+}
+
+// RoundTrip sends the request when the rate limiter allows it
+func (limited *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	err := limited.limiter.wait(req.Context())
+	if err != nil {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return nil, err
+	}
+	return limited.transport.RoundTrip(req)
+}
+
+// rateLimiter is a token bucket, refilled at rate tokens per second up to burst tokens. Each
+// request takes a token, or reserves the next one and waits for it when the bucket is empty.
+type rateLimiter struct {
+	rate  float64
+	burst float64
+
+	mutex   sync.Mutex
+	tokens  float64
+	updated time.Time
+}
+
+// newRateLimiter returns a rate limiter whose bucket is full
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	return &rateLimiter{rate: rate, burst: float64(burst), tokens: float64(burst), updated: time.Now()}
+}
+
+// reserve takes a token and returns the delay after which it is available, 0 when the bucket was
+// not empty. The tokens taken in advance make the bucket negative, so that the following
+// requests wait longer.
+func (limiter *rateLimiter) reserve(now time.Time) time.Duration {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+	limiter.tokens += now.Sub(limiter.updated).Seconds() * limiter.rate
+	if limiter.tokens > limiter.burst {
+		limiter.tokens = limiter.burst
+	}
+	limiter.updated = now
+	limiter.tokens--
+	if limiter.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-limiter.tokens / limiter.rate * float64(time.Second))
+}
+
+// refund gives back a token reserved by a request which was not sent, so that the following
+// requests do not wait for it
+func (limiter *rateLimiter) refund() {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+	limiter.tokens++
+	if limiter.tokens > limiter.burst {
+		limiter.tokens = limiter.burst
+	}
+}
+
+// wait blocks until a token is available, or until ctx is done. In the latter case the reserved
+// token is given back and the error of the context is returned.
+func (limiter *rateLimiter) wait(ctx context.Context) error {
+	delay := limiter.reserve(time.Now())
+	if delay == 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		limiter.refund()
+		return ctx.Err()
+	}
+}
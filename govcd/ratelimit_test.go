@@ -0,0 +1,92 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// Tests the delays given by the token bucket of the rate limiter
+func TestRateLimiter(t *testing.T) {
+	limiter := newRateLimiter(2, 2)
+	start := limiter.updated
+
+	// The burst is served at once, then a token every half second
+	for i, expected := range []time.Duration{0, 0, 500 * time.Millisecond, time.Second} {
+		if delay := limiter.reserve(start); delay != expected {
+			t.Errorf("expected delay %s for request %d, got %s", expected, i, delay)
+		}
+	}
+	// After 3 seconds the bucket is full again, with no more than burst tokens
+	later := start.Add(3 * time.Second)
+	for i, expected := range []time.Duration{0, 0, 500 * time.Millisecond} {
+		if delay := limiter.reserve(later); delay != expected {
+			t.Errorf("expected delay %s for request %d after refill, got %s", expected, i, delay)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := limiter.wait(ctx); err != context.Canceled {
+		t.Errorf("expected canceled context error, got %v", err)
+	}
+
+	// The token reserved by a canceled wait is given back: the next request waits for one token only
+	limiter = newRateLimiter(1, 1)
+	limiter.reserve(time.Now())
+	if err := limiter.wait(ctx); err != context.Canceled {
+		t.Errorf("expected canceled context error, got %v", err)
+	}
+	if delay := limiter.reserve(time.Now()); delay > time.Second {
+		t.Errorf("expected a delay of at most 1s after a canceled wait, got %s", delay)
+	}
+}
+
+// Tests that the rate limit applies to the requests of the client, below the retry policy
+func TestWithRateLimit(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	if err := WithRateLimit(0, 1)(&VCDClient{}); err == nil {
+		t.Errorf("expected error with a rate of 0 requests per second")
+	}
+	if err := WithRateLimit(1, 0)(&VCDClient{}); err == nil {
+		t.Errorf("expected error with a burst of 0")
+	}
+
+	vcdClient := NewVCDClient(server.ApiURL(), true,
+		WithRetryPolicy(RetryPolicy{MaxRetries: 1}),
+		WithRateLimit(20, 1))
+	retrying, ok := vcdClient.Client.Http.Transport.(*retryTransport)
+	if !ok {
+		t.Fatalf("expected retry transport on top, got %T", vcdClient.Client.Http.Transport)
+	}
+	if _, ok := retrying.transport.(*rateLimitTransport); !ok {
+		t.Errorf("expected rate limit below the retry transport, got %T", retrying.transport)
+	}
+
+	// With a burst of 1, the requests after the first one are sent every 50 milliseconds
+	start := time.Now()
+	err := vcdClient.Authenticate("user", "password", vcdtest.MockOrgName)
+	if err != nil {
+		t.Fatalf("error authenticating: %s", err)
+	}
+	_, err = GetOrgByName(vcdClient, vcdtest.MockOrgName)
+	if err != nil {
+		t.Fatalf("error retrieving org: %s", err)
+	}
+	requests := len(server.Requests())
+	if minimum := time.Duration(requests-1) * 45 * time.Millisecond; time.Since(start) < minimum {
+		t.Errorf("expected %d requests to take at least %s, took %s", requests, minimum, time.Since(start))
+	}
+	if len(server.RequestsTo(http.MethodPost, "/api/sessions")) != 1 {
+		t.Errorf("expected one login request")
+	}
+}
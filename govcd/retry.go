@@ -0,0 +1,236 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/vmware/go-vcloud-director/v2/util"
+)
+
+// RetryPolicy defines how the requests of a client are retried when vCD rejects them with a
+// transient error: too many requests (429), bad gateway (502), service unavailable (503), gateway
+// timeout (504) and, optionally, BUSY_ENTITY errors. Only the idempotent requests (GET, HEAD, PUT
+// and DELETE) are retried on all of them: the other ones, e.g. POST, may have been processed and
+// are only retried on 429 and 503. The delay between two attempts starts at
+// InitialBackoff and doubles at every retry, up to MaxBackoff. A Retry-After header sent by vCD
+// is honored, within MaxBackoff.
+type RetryPolicy struct {
+	MaxRetries     int           // Number of retries after the first attempt, 0 to disable retries
+	InitialBackoff time.Duration // Delay before the first retry
+	MaxBackoff     time.Duration // Upper limit of the delay between two attempts
+	// Jitter is the fraction (0 to 1) of each delay which is randomized, so that the clients
+	// rejected at the same time do not retry at the same time
+	Jitter float64
+	// RetryStatusCodes are the HTTP status codes which are retried. The default ones are used when empty.
+	RetryStatusCodes []int
+	// RetryBusyEntity retries the requests rejected because the entity is busy with another operation
+	RetryBusyEntity bool
+}
+
+// DefaultRetryPolicy returns a policy retrying up to 5 times, with a delay from 1 to 30 seconds,
+// the requests rejected with a transient error or a BUSY_ENTITY error
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:      5,
+		InitialBackoff:  1 * time.Second,
+		MaxBackoff:      30 * time.Second,
+		Jitter:          0.2,
+		RetryBusyEntity: true,
+	}
+}
+
+// defaultRetryStatusCodes are the status codes retried when the policy does not set any
+var defaultRetryStatusCodes = []int{
+	http.StatusTooManyRequests,    // 429
+	http.StatusBadGateway,         // 502
+	http.StatusServiceUnavailable, // 503
+	http.StatusGatewayTimeout,     // 504
+}
+
+// WithRetryPolicy makes the client retry the requests rejected with a transient error, following
+// policy. Without this option, a request is never retried. The internal server errors (500) are
+// not retried by default, as vCD also uses them for errors which would fail again: add
+// http.StatusInternalServerError to RetryStatusCodes to retry them.
+// Whatever the policy, the requests which are not idempotent (POST and PATCH) are only retried on
+// too many requests (429) and service unavailable (503), which vCD returns without processing the
+// request: a bad gateway (502), a gateway timeout (504) or a BUSY_ENTITY error can come after an
+// operation was started, which a retry would start again.
+func WithRetryPolicy(policy RetryPolicy) VCDClientOption {
+	return func(vcdClient *VCDClient) error {
+		err := policy.validate()
+		if err != nil {
+			return err
+		}
+		transport := vcdClient.Client.Http.Transport
+		if retrying, ok := transport.(*retryTransport); ok {
+			transport = retrying.transport
+		}
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+		vcdClient.Client.Http.Transport = &retryTransport{policy: policy, transport: transport}
+		return nil
+	}
+}
+
+// validate checks that the values of the policy are consistent
+func (policy RetryPolicy) validate() error {
+	if policy.MaxRetries < 0 {
+		return fmt.Errorf("retry policy: the number of retries cannot be negative")
+	}
+	if policy.InitialBackoff < 0 || policy.MaxBackoff < policy.InitialBackoff {
+		return fmt.Errorf("retry policy: the maximum backoff (%s) must be greater than the initial backoff (%s)",
+			policy.MaxBackoff, policy.InitialBackoff)
+	}
+	if policy.Jitter < 0 || policy.Jitter > 1 {
+		return fmt.Errorf("retry policy: the jitter must be between 0 and 1, got %f", policy.Jitter)
+	}
+	return nil
+}
+
+// isRetryableStatus returns true if the requests rejected with the given status are retried
+func (policy RetryPolicy) isRetryableStatus(statusCode int) bool {
+	statusCodes := policy.RetryStatusCodes
+	if len(statusCodes) == 0 {
+		statusCodes = defaultRetryStatusCodes
+	}
+	for _, retryable := range statusCodes {
+		if statusCode == retryable {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the delay before the given retry (0 for the first one), with jitter
+func (policy RetryPolicy) backoff(retry int) time.Duration {
+	delay := policy.InitialBackoff
+	for i := 0; i < retry && delay < policy.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > policy.MaxBackoff {
+		delay = policy.MaxBackoff
+	}
+	if policy.Jitter > 0 {
+		delay -= time.Duration(rand.Float64() * policy.Jitter * float64(delay))
+	}
+	return delay
+}
+
+// retryTransport is an http.RoundTripper which retries the requests following a retry policy.
+// Being below http.Client, it applies to every request of the client, whatever the function
+// which sends it and checks its response.
+type retryTransport struct {
+	policy    RetryPolicy
+	transport http.RoundTripper
+}
+
+// RoundTrip sends the request and retries it while it is rejected with a transient error, the
+// policy allows it and the context of the request is not done. The response of the last attempt
+// is returned.
+func (retrying *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for retry := 0; ; retry++ {
+		resp, err := retrying.transport.RoundTrip(req)
+		if err != nil || retry >= retrying.policy.MaxRetries || !isRewindable(req) {
+			return resp, err
+		}
+		retryable, err := retrying.isRetryableResponse(req, resp)
+		if err != nil {
+			return nil, err
+		}
+		if !retryable {
+			return resp, nil
+		}
+
+		delay := retrying.policy.backoff(retry)
+		if retryAfter := retryAfterDelay(resp); retryAfter > delay {
+			delay = retryAfter
+			if delay > retrying.policy.MaxBackoff {
+				delay = retrying.policy.MaxBackoff
+			}
+		}
+		util.Logger.Printf("[TRACE] %s %s rejected with status %d, retrying in %s (retry %d of %d)\n",
+			req.Method, req.URL.String(), resp.StatusCode, delay, retry+1, retrying.policy.MaxRetries)
+		_ = resp.Body.Close()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("error rewinding the body of the request to retry it: %s", err)
+			}
+			retried := *req
+			retried.Body = body
+			req = &retried
+		}
+	}
+}
+
+// isRetryableResponse returns true if the response is a transient error to be retried. The body of
+// the errors is read to find BUSY_ENTITY, and put back into the response for the caller.
+func (retrying *retryTransport) isRetryableResponse(req *http.Request, resp *http.Response) (bool, error) {
+	if !isIdempotent(req.Method) {
+		return isUnprocessedStatus(resp.StatusCode) && retrying.policy.isRetryableStatus(resp.StatusCode), nil
+	}
+	if retrying.policy.isRetryableStatus(resp.StatusCode) {
+		return true, nil
+	}
+	if !retrying.policy.RetryBusyEntity || resp.StatusCode < http.StatusBadRequest {
+		return false, nil
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return false, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return bytes.Contains(body, []byte("BUSY_ENTITY")), nil
+}
+
+// isIdempotent returns true if sending the request several times has the same effect as sending it
+// once
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isUnprocessedStatus returns true if vCD rejects the requests with the given status before
+// processing them, so that the requests which are not idempotent can also be retried
+func isUnprocessedStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode == http.StatusServiceUnavailable
+}
+
+// isRewindable returns true if the request can be sent again: it has no body, or a body which
+// can be read again (e.g. the bytes.Buffer used by NewRequest). Streamed uploads are not retried.
+func isRewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// retryAfterDelay returns the delay requested by the Retry-After header of the response, in
+// seconds, or 0 when not set
+func retryAfterDelay(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,189 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// Tests that the requests rejected with a transient error are retried following the retry policy
+func TestRetryPolicy(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	const unavailablePath = "/api/unavailable"
+	const busyPath = "/api/busy"
+	server.Handle(http.MethodGet, unavailablePath, vcdtest.Response{Status: http.StatusServiceUnavailable})
+	server.HandleXML(http.MethodPut, busyPath, http.StatusBadRequest,
+		`<Error xmlns="http://www.vmware.com/vcloud/v1.5" majorErrorCode="400" minorErrorCode="BUSY_ENTITY" message="busy"/>`)
+
+	err := WithRetryPolicy(RetryPolicy{Jitter: 2})(&VCDClient{})
+	if err == nil {
+		t.Errorf("expected error with a jitter greater than 1")
+	}
+
+	vcdClient := NewVCDClient(server.ApiURL(), true, WithRetryPolicy(RetryPolicy{
+		MaxRetries:      2,
+		InitialBackoff:  time.Millisecond,
+		MaxBackoff:      5 * time.Millisecond,
+		Jitter:          0.5,
+		RetryBusyEntity: true,
+	}))
+	err = vcdClient.Authenticate("user", "password", vcdtest.MockOrgName)
+	if err != nil {
+		t.Fatalf("error authenticating: %s", err)
+	}
+
+	err = vcdClient.Client.ExecuteRequestWithoutResponse(server.URL()+unavailablePath, http.MethodGet, "",
+		"error retrieving: %s", nil)
+	if err == nil {
+		t.Errorf("expected error from an unavailable endpoint")
+	}
+	if requests := server.RequestsTo(http.MethodGet, unavailablePath); len(requests) != 3 {
+		t.Errorf("expected 3 attempts, got %d", len(requests))
+	}
+
+	// The body is sent again at every attempt, and the last error is returned to the caller
+	err = vcdClient.Client.ExecuteRequestWithoutResponse(server.URL()+busyPath, http.MethodPut,
+		types.MimeVM, "error updating: %s", &types.VM{Name: "vm1"})
+	if !errors.Is(err, ErrorBusyEntity) {
+		t.Errorf("expected busy entity error, got %v", err)
+	}
+	requests := server.RequestsTo(http.MethodPut, busyPath)
+	if len(requests) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(requests))
+	}
+	for _, request := range requests {
+		if !strings.Contains(request.Body, `name="vm1"`) {
+			t.Errorf("unexpected body: %s", request.Body)
+		}
+	}
+
+	// BUSY_ENTITY errors of requests which are not idempotent are not retried
+	server.HandleXML(http.MethodPost, busyPath, http.StatusBadRequest,
+		`<Error xmlns="http://www.vmware.com/vcloud/v1.5" majorErrorCode="400" minorErrorCode="BUSY_ENTITY" message="busy"/>`)
+	err = vcdClient.Client.ExecuteRequestWithoutResponse(server.URL()+busyPath, http.MethodPost,
+		types.MimeVM, "error creating: %s", &types.VM{Name: "vm1"})
+	if !errors.Is(err, ErrorBusyEntity) {
+		t.Errorf("expected busy entity error, got %v", err)
+	}
+	if requests := server.RequestsTo(http.MethodPost, busyPath); len(requests) != 1 {
+		t.Errorf("expected 1 attempt of a POST, got %d", len(requests))
+	}
+
+	// Without retry policy, the request is sent once
+	server.ClearRequests()
+	vcdClient = newMockClient(t, server)
+	_ = vcdClient.Client.ExecuteRequestWithoutResponse(server.URL()+unavailablePath, http.MethodGet, "",
+		"error retrieving: %s", nil)
+	if requests := server.RequestsTo(http.MethodGet, unavailablePath); len(requests) != 1 {
+		t.Errorf("expected 1 attempt, got %d", len(requests))
+	}
+}
+
+// sequenceTransport returns the given statuses, one per request, then 200
+type sequenceTransport struct {
+	statuses []int
+	attempts int
+}
+
+func (sequence *sequenceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	status := http.StatusOK
+	if sequence.attempts < len(sequence.statuses) {
+		status = sequence.statuses[sequence.attempts]
+	}
+	sequence.attempts++
+	return &http.Response{StatusCode: status, Header: http.Header{}, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+}
+
+// Tests the retried statuses and the delays of the retry transport
+func TestRetryTransport(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+	sequence := &sequenceTransport{statuses: []int{http.StatusTooManyRequests, http.StatusGatewayTimeout}}
+	client := http.Client{Transport: &retryTransport{policy: policy, transport: sequence}}
+	resp, err := client.Get("http://vcd.example.com/api/org")
+	if err != nil || resp.StatusCode != http.StatusOK || sequence.attempts != 3 {
+		t.Errorf("expected success at the third attempt, got %v after %d attempts", err, sequence.attempts)
+	}
+
+	// Internal server errors are retried only when requested
+	sequence = &sequenceTransport{statuses: []int{http.StatusInternalServerError}}
+	client.Transport = &retryTransport{policy: policy, transport: sequence}
+	resp, err = client.Get("http://vcd.example.com/api/org")
+	if err != nil || resp.StatusCode != http.StatusInternalServerError || sequence.attempts != 1 {
+		t.Errorf("expected no retry of internal server error, got %d attempts", sequence.attempts)
+	}
+	policy.RetryStatusCodes = []int{http.StatusInternalServerError}
+	sequence = &sequenceTransport{statuses: []int{http.StatusInternalServerError}}
+	client.Transport = &retryTransport{policy: policy, transport: sequence}
+	resp, err = client.Get("http://vcd.example.com/api/org")
+	if err != nil || resp.StatusCode != http.StatusOK || sequence.attempts != 2 {
+		t.Errorf("expected retry of internal server error, got %d attempts", sequence.attempts)
+	}
+
+	// Requests which are not idempotent are only retried when vCD did not process them
+	policy.RetryStatusCodes = nil
+	for _, method := range []string{http.MethodPost, http.MethodPatch} {
+		for status, attempts := range map[int]int{
+			http.StatusTooManyRequests:    2,
+			http.StatusServiceUnavailable: 2,
+			http.StatusBadGateway:         1,
+			http.StatusGatewayTimeout:     1,
+		} {
+			sequence = &sequenceTransport{statuses: []int{status}}
+			client.Transport = &retryTransport{policy: policy, transport: sequence}
+			req, _ := http.NewRequest(method, "http://vcd.example.com/api/vApp/vapp-1/action/deploy", nil)
+			_, err = client.Do(req)
+			if err != nil || sequence.attempts != attempts {
+				t.Errorf("expected %d attempts of %s rejected with %d, got %d (error %v)", attempts, method, status,
+					sequence.attempts, err)
+			}
+		}
+	}
+	for _, method := range []string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete} {
+		sequence = &sequenceTransport{statuses: []int{http.StatusBadGateway}}
+		client.Transport = &retryTransport{policy: policy, transport: sequence}
+		req, _ := http.NewRequest(method, "http://vcd.example.com/api/vApp/vapp-1", nil)
+		_, err = client.Do(req)
+		if err != nil || sequence.attempts != 2 {
+			t.Errorf("expected retry of %s rejected with bad gateway, got %d attempts (error %v)", method,
+				sequence.attempts, err)
+		}
+	}
+	policy.RetryStatusCodes = []int{http.StatusInternalServerError}
+	sequence = &sequenceTransport{statuses: []int{http.StatusInternalServerError}}
+	client.Transport = &retryTransport{policy: policy, transport: sequence}
+	resp, err = client.Post("http://vcd.example.com/api/vApp/vapp-1/action/deploy", types.MimeDeployVappParams, nil)
+	if err != nil || resp.StatusCode != http.StatusInternalServerError || sequence.attempts != 1 {
+		t.Errorf("expected no retry of a POST rejected with internal server error, got %d attempts", sequence.attempts)
+	}
+
+	// Streamed bodies cannot be sent again
+	sequence = &sequenceTransport{statuses: []int{http.StatusServiceUnavailable}}
+	client.Transport = &retryTransport{policy: policy, transport: sequence}
+	req, _ := http.NewRequest(http.MethodPut, "http://vcd.example.com/transfer/file", ioutil.NopCloser(strings.NewReader("data")))
+	resp, err = client.Do(req)
+	if err != nil || resp.StatusCode != http.StatusServiceUnavailable || sequence.attempts != 1 {
+		t.Errorf("expected no retry of a streamed upload, got %d attempts", sequence.attempts)
+	}
+
+	policy = RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	for retry, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second} {
+		if delay := policy.backoff(retry); delay != expected {
+			t.Errorf("expected delay %s before retry %d, got %s", expected, retry, delay)
+		}
+	}
+	policy.Jitter = 0.5
+	if delay := policy.backoff(2); delay > 4*time.Second || delay < 2*time.Second {
+		t.Errorf("unexpected delay with jitter: %s", delay)
+	}
+}
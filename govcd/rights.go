@@ -0,0 +1,180 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// GetAllRights retrieves all the rights defined in vCD. Query parameters can be supplied
+// to filter the results (e.g. "filter" => "category==...")
+func (client *Client) GetAllRights(queryParameters url.Values) ([]*types.Right, error) {
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointRights
+	apiVersion, err := getOpenApiVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var rights []*types.Right
+	err = client.OpenApiGetAllItems(apiVersion, urlRef, queryParameters, &rights)
+	if err != nil {
+		return nil, err
+	}
+	return rights, nil
+}
+
+// GetRightByName retrieves the right with the given name
+func (client *Client) GetRightByName(name string) (*types.Right, error) {
+	queryParams := url.Values{}
+	queryParams.Set("filter", fiqlEq("name", name))
+	rights, err := client.GetAllRights(queryParams)
+	if err != nil {
+		return nil, err
+	}
+	if len(rights) == 0 {
+		return nil, fmt.Errorf("right '%s' not found", name)
+	}
+	if len(rights) > 1 {
+		return nil, fmt.Errorf("more than one right found with name '%s'", name)
+	}
+	return rights[0], nil
+}
+
+// The functions below handle the collections of rights and tenants that global roles, roles
+// and rights bundles have in common. endpoint is the entity endpoint (e.g. "1.0.0/globalRoles/")
+// and id the entity ID.
+
+// getRightsCollection retrieves the rights of an entity
+func getRightsCollection(client *Client, endpoint, id string, queryParameters url.Values) ([]*types.Right, error) {
+	apiVersion, err := getOpenApiVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint, id, "/rights")
+	if err != nil {
+		return nil, err
+	}
+	var rights []*types.Right
+	err = client.OpenApiGetAllItems(apiVersion, urlRef, queryParameters, &rights)
+	if err != nil {
+		return nil, err
+	}
+	return rights, nil
+}
+
+// updateRightsCollection replaces the rights of an entity with the given list
+func updateRightsCollection(client *Client, endpoint, id string, rights types.OpenApiReferences) error {
+	apiVersion, err := getOpenApiVersion(endpoint)
+	if err != nil {
+		return err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint, id, "/rights")
+	if err != nil {
+		return err
+	}
+	return client.OpenApiPutItem(apiVersion, urlRef, nil, &types.OpenApiItems{Values: rights}, nil)
+}
+
+// addRightsToCollection adds the given rights to the ones already assigned to an entity
+func addRightsToCollection(client *Client, endpoint, id string, newRights types.OpenApiReferences) error {
+	current, err := getRightsCollection(client, endpoint, id, nil)
+	if err != nil {
+		return err
+	}
+	var rights types.OpenApiReferences
+	for _, right := range current {
+		rights = append(rights, types.OpenApiReference{Name: right.Name, ID: right.ID})
+	}
+	for _, newRight := range newRights {
+		if !referenceListContains(rights, newRight) {
+			rights = append(rights, newRight)
+		}
+	}
+	return updateRightsCollection(client, endpoint, id, rights)
+}
+
+// removeRightsFromCollection removes the given rights from the ones assigned to an entity
+func removeRightsFromCollection(client *Client, endpoint, id string, removedRights types.OpenApiReferences) error {
+	current, err := getRightsCollection(client, endpoint, id, nil)
+	if err != nil {
+		return err
+	}
+	rights := types.OpenApiReferences{}
+	for _, right := range current {
+		reference := types.OpenApiReference{Name: right.Name, ID: right.ID}
+		if !referenceListContains(removedRights, reference) {
+			rights = append(rights, reference)
+		}
+	}
+	return updateRightsCollection(client, endpoint, id, rights)
+}
+
+// getTenantsCollection retrieves the tenants an entity is published to
+func getTenantsCollection(client *Client, endpoint, id string, queryParameters url.Values) (types.OpenApiReferences, error) {
+	apiVersion, err := getOpenApiVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint, id, "/tenants")
+	if err != nil {
+		return nil, err
+	}
+	var tenants types.OpenApiReferences
+	err = client.OpenApiGetAllItems(apiVersion, urlRef, queryParameters, &tenants)
+	if err != nil {
+		return nil, err
+	}
+	return tenants, nil
+}
+
+// changeTenantsCollection runs a tenant action on an entity:
+// "" replaces the list of tenants, "publish" and "unpublish" add or remove the given tenants,
+// "publishAll" and "unpublishAll" affect every tenant and ignore the list.
+func changeTenantsCollection(client *Client, endpoint, id, action string, tenants types.OpenApiReferences) error {
+	apiVersion, err := getOpenApiVersion(endpoint)
+	if err != nil {
+		return err
+	}
+	path := "/tenants"
+	if action != "" {
+		path += "/" + action
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint, id, path)
+	if err != nil {
+		return err
+	}
+	if tenants == nil {
+		tenants = types.OpenApiReferences{}
+	}
+	payload := &types.OpenApiItems{Values: tenants}
+	if action == "" {
+		return client.OpenApiPutItem(apiVersion, urlRef, nil, payload, nil)
+	}
+	return client.OpenApiPostItem(apiVersion, urlRef, nil, payload, nil)
+}
+
+// referenceListContains returns true if the list contains a reference with the same ID
+// or, when IDs are not available, the same name
+func referenceListContains(list types.OpenApiReferences, reference types.OpenApiReference) bool {
+	for _, item := range list {
+		if item.ID != "" && reference.ID != "" {
+			if item.ID == reference.ID {
+				return true
+			}
+			continue
+		}
+		if item.Name == reference.Name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,233 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// RightsBundle is a set of rights that the provider makes available to tenants. Only the rights
+// included in a bundle published to an organization can be used in the roles of that organization.
+// Rights bundles are managed through OpenAPI and need system administrator privileges.
+type RightsBundle struct {
+	RightsBundle *types.RightsBundle
+	client       *Client
+}
+
+// NewRightsBundle creates a new rights bundle structure which still needs to have RightsBundle attribute populated
+func NewRightsBundle(cli *Client) *RightsBundle {
+	return &RightsBundle{
+		RightsBundle: new(types.RightsBundle),
+		client:       cli,
+	}
+}
+
+// GetAllRightsBundles retrieves all rights bundles. Query parameters can be supplied to perform
+// additional filtering
+func (client *Client) GetAllRightsBundles(queryParameters url.Values) ([]*RightsBundle, error) {
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointRightsBundles
+	apiVersion, err := getOpenApiVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var typeResponses []*types.RightsBundle
+	err = client.OpenApiGetAllItems(apiVersion, urlRef, queryParameters, &typeResponses)
+	if err != nil {
+		return nil, err
+	}
+
+	rightsBundles := make([]*RightsBundle, len(typeResponses))
+	for index, typeResponse := range typeResponses {
+		rightsBundles[index] = &RightsBundle{RightsBundle: typeResponse, client: client}
+	}
+	return rightsBundles, nil
+}
+
+// GetRightsBundleByName retrieves the rights bundle with the given name
+func (client *Client) GetRightsBundleByName(name string) (*RightsBundle, error) {
+	queryParams := url.Values{}
+	queryParams.Set("filter", fiqlEq("name", name))
+	rightsBundles, err := client.GetAllRightsBundles(queryParams)
+	if err != nil {
+		return nil, err
+	}
+	if len(rightsBundles) == 0 {
+		return nil, wrapErrorf(ErrorEntityNotFound, "rights bundle '%s' not found: %s", name, ErrorEntityNotFound)
+	}
+	if len(rightsBundles) > 1 {
+		return nil, fmt.Errorf("more than one rights bundle found with name '%s'", name)
+	}
+	return rightsBundles[0], nil
+}
+
+// GetRightsBundleById retrieves the rights bundle with the given ID
+func (client *Client) GetRightsBundleById(id string) (*RightsBundle, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty rights bundle ID")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointRightsBundles
+	apiVersion, err := getOpenApiVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rightsBundle := NewRightsBundle(client)
+	err = client.OpenApiGetItem(apiVersion, urlRef, nil, rightsBundle.RightsBundle)
+	if err != nil {
+		return nil, err
+	}
+	return rightsBundle, nil
+}
+
+// CreateRightsBundle creates a new rights bundle. The rights and the tenants need to be set
+// after creation, with AddRights and PublishTenants
+func (client *Client) CreateRightsBundle(newRightsBundle *types.RightsBundle) (*RightsBundle, error) {
+	if newRightsBundle == nil || newRightsBundle.Name == "" {
+		return nil, fmt.Errorf("rights bundle name is required")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointRightsBundles
+	apiVersion, err := getOpenApiVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	rightsBundle := NewRightsBundle(client)
+	err = client.OpenApiPostItem(apiVersion, urlRef, nil, newRightsBundle, rightsBundle.RightsBundle)
+	if err != nil {
+		return nil, fmt.Errorf("error creating rights bundle: %s", err)
+	}
+	return rightsBundle, nil
+}
+
+// Refresh retrieves the current definition of the rights bundle
+func (rightsBundle *RightsBundle) Refresh() error {
+	refreshed, err := rightsBundle.client.GetRightsBundleById(rightsBundle.RightsBundle.ID)
+	if err != nil {
+		return err
+	}
+	rightsBundle.RightsBundle = refreshed.RightsBundle
+	return nil
+}
+
+// Update sends the current definition of the rights bundle (name, description) to vCD
+func (rightsBundle *RightsBundle) Update() error {
+	if rightsBundle.RightsBundle.ID == "" {
+		return fmt.Errorf("cannot update rights bundle without ID")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointRightsBundles
+	apiVersion, err := getOpenApiVersion(endpoint)
+	if err != nil {
+		return err
+	}
+	urlRef, err := rightsBundle.client.OpenApiBuildEndpoint(endpoint, rightsBundle.RightsBundle.ID)
+	if err != nil {
+		return err
+	}
+
+	updated := &types.RightsBundle{}
+	err = rightsBundle.client.OpenApiPutItem(apiVersion, urlRef, nil, rightsBundle.RightsBundle, updated)
+	if err != nil {
+		return fmt.Errorf("error updating rights bundle: %s", err)
+	}
+	rightsBundle.RightsBundle = updated
+	return nil
+}
+
+// Delete removes the rights bundle
+func (rightsBundle *RightsBundle) Delete() error {
+	if rightsBundle.RightsBundle.ID == "" {
+		return fmt.Errorf("cannot delete rights bundle without ID")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointRightsBundles
+	apiVersion, err := getOpenApiVersion(endpoint)
+	if err != nil {
+		return err
+	}
+	urlRef, err := rightsBundle.client.OpenApiBuildEndpoint(endpoint, rightsBundle.RightsBundle.ID)
+	if err != nil {
+		return err
+	}
+	err = rightsBundle.client.OpenApiDeleteItem(apiVersion, urlRef, nil)
+	if err != nil {
+		return fmt.Errorf("error deleting rights bundle: %s", err)
+	}
+	return nil
+}
+
+// GetRights retrieves the rights of the rights bundle
+func (rightsBundle *RightsBundle) GetRights(queryParameters url.Values) ([]*types.Right, error) {
+	return getRightsCollection(rightsBundle.client, types.OpenApiPathVersion1_0_0+types.OpenApiEndpointRightsBundles,
+		rightsBundle.RightsBundle.ID, queryParameters)
+}
+
+// AddRights adds the given rights to the rights bundle
+func (rightsBundle *RightsBundle) AddRights(rights types.OpenApiReferences) error {
+	return addRightsToCollection(rightsBundle.client, types.OpenApiPathVersion1_0_0+types.OpenApiEndpointRightsBundles,
+		rightsBundle.RightsBundle.ID, rights)
+}
+
+// RemoveRights removes the given rights from the rights bundle
+func (rightsBundle *RightsBundle) RemoveRights(rights types.OpenApiReferences) error {
+	return removeRightsFromCollection(rightsBundle.client, types.OpenApiPathVersion1_0_0+types.OpenApiEndpointRightsBundles,
+		rightsBundle.RightsBundle.ID, rights)
+}
+
+// UpdateRights replaces the rights of the rights bundle with the given list
+func (rightsBundle *RightsBundle) UpdateRights(rights types.OpenApiReferences) error {
+	return updateRightsCollection(rightsBundle.client, types.OpenApiPathVersion1_0_0+types.OpenApiEndpointRightsBundles,
+		rightsBundle.RightsBundle.ID, rights)
+}
+
+// GetTenants retrieves the organizations the rights bundle is published to
+func (rightsBundle *RightsBundle) GetTenants(queryParameters url.Values) (types.OpenApiReferences, error) {
+	return getTenantsCollection(rightsBundle.client, types.OpenApiPathVersion1_0_0+types.OpenApiEndpointRightsBundles,
+		rightsBundle.RightsBundle.ID, queryParameters)
+}
+
+// PublishTenants publishes the rights bundle to the given organizations
+func (rightsBundle *RightsBundle) PublishTenants(tenants types.OpenApiReferences) error {
+	return changeTenantsCollection(rightsBundle.client, types.OpenApiPathVersion1_0_0+types.OpenApiEndpointRightsBundles,
+		rightsBundle.RightsBundle.ID, "publish", tenants)
+}
+
+// UnpublishTenants removes the rights bundle from the given organizations
+func (rightsBundle *RightsBundle) UnpublishTenants(tenants types.OpenApiReferences) error {
+	return changeTenantsCollection(rightsBundle.client, types.OpenApiPathVersion1_0_0+types.OpenApiEndpointRightsBundles,
+		rightsBundle.RightsBundle.ID, "unpublish", tenants)
+}
+
+// ReplacePublishedTenants publishes the rights bundle to exactly the given organizations
+func (rightsBundle *RightsBundle) ReplacePublishedTenants(tenants types.OpenApiReferences) error {
+	return changeTenantsCollection(rightsBundle.client, types.OpenApiPathVersion1_0_0+types.OpenApiEndpointRightsBundles,
+		rightsBundle.RightsBundle.ID, "", tenants)
+}
+
+// PublishAllTenants publishes the rights bundle to all organizations, including future ones
+func (rightsBundle *RightsBundle) PublishAllTenants() error {
+	return changeTenantsCollection(rightsBundle.client, types.OpenApiPathVersion1_0_0+types.OpenApiEndpointRightsBundles,
+		rightsBundle.RightsBundle.ID, "publishAll", nil)
+}
+
+// UnpublishAllTenants removes the rights bundle from all organizations
+func (rightsBundle *RightsBundle) UnpublishAllTenants() error {
+	return changeTenantsCollection(rightsBundle.client, types.OpenApiPathVersion1_0_0+types.OpenApiEndpointRightsBundles,
+		rightsBundle.RightsBundle.ID, "unpublishAll", nil)
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	. "gopkg.in/check.v1"
+)
+
+func (vcd *TestVCD) Test_RightsBundles(check *C) {
+	if vcd.skipAdminTests {
+		check.Skip(fmt.Sprintf(TestRequiresSysAdminPrivileges, check.TestName()))
+	}
+	if vcd.client.APIVCDMaxVersionIs("< 33.0") {
+		check.Skip("rights bundles require vCD 10.0+")
+	}
+	client := &vcd.client.Client
+
+	rightsBundles, err := client.GetAllRightsBundles(nil)
+	check.Assert(err, IsNil)
+	check.Assert(len(rightsBundles), Not(Equals), 0)
+
+	rightsBundle, err := client.CreateRightsBundle(&types.RightsBundle{
+		Name:        TestRightsBundle,
+		Description: TestRightsBundle,
+		BundleKey:   types.VcloudUndefinedKey,
+	})
+	check.Assert(err, IsNil)
+	AddToCleanupList(TestRightsBundle, "rightsBundle", "", "Test_RightsBundles")
+
+	right, err := client.GetRightByName("Catalog: View Private and Shared Catalogs")
+	check.Assert(err, IsNil)
+	err = rightsBundle.AddRights(types.OpenApiReferences{{Name: right.Name, ID: right.ID}})
+	check.Assert(err, IsNil)
+	rights, err := rightsBundle.GetRights(nil)
+	check.Assert(err, IsNil)
+	check.Assert(len(rights), Equals, 1)
+
+	rightsBundle.RightsBundle.Description = TestRightsBundle + " updated"
+	err = rightsBundle.Update()
+	check.Assert(err, IsNil)
+	check.Assert(rightsBundle.RightsBundle.Description, Equals, TestRightsBundle+" updated")
+
+	adminOrg, err := GetAdminOrgByName(vcd.client, vcd.config.VCD.Org)
+	check.Assert(err, IsNil)
+	tenant := types.OpenApiReferences{{Name: adminOrg.AdminOrg.Name, ID: adminOrg.AdminOrg.ID}}
+	err = rightsBundle.PublishTenants(tenant)
+	check.Assert(err, IsNil)
+	tenants, err := rightsBundle.GetTenants(nil)
+	check.Assert(err, IsNil)
+	check.Assert(len(tenants), Equals, 1)
+	err = rightsBundle.UnpublishTenants(tenant)
+	check.Assert(err, IsNil)
+
+	err = rightsBundle.RemoveRights(types.OpenApiReferences{{Name: right.Name, ID: right.ID}})
+	check.Assert(err, IsNil)
+
+	foundRole, err := client.GetRightsBundleByName(TestRightsBundle)
+	check.Assert(err, IsNil)
+	check.Assert(foundRole.RightsBundle.ID, Equals, rightsBundle.RightsBundle.ID)
+
+	err = rightsBundle.Delete()
+	check.Assert(err, IsNil)
+	_, err = client.GetRightsBundleById(rightsBundle.RightsBundle.ID)
+	check.Assert(err, NotNil)
+}
@@ -0,0 +1,273 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// Role is a set of rights given to the users and groups of an organization. Roles are managed
+// through OpenAPI, in the context of the org they belong to.
+type Role struct {
+	Role   *types.Role
+	client *Client // runs the requests in the context of the org of the role
+}
+
+// NewRole creates a new role structure which still needs to have Role attribute populated
+func NewRole(cli *Client) *Role {
+	return &Role{
+		Role:   new(types.Role),
+		client: cli,
+	}
+}
+
+// tenantClient returns a copy of the client which runs the OpenAPI requests in the context of the org
+func (adminOrg *AdminOrg) tenantClient() (*Client, error) {
+	orgUrn, err := entityUrn(adminOrg.AdminOrg.ID, adminOrg.AdminOrg.HREF)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving the ID of org %s: %s", adminOrg.AdminOrg.Name, err)
+	}
+	_, orgUuid, err := ParseUrn(orgUrn)
+	if err != nil {
+		return nil, err
+	}
+	return adminOrg.client.withTenantContext(orgUuid), nil
+}
+
+// GetAllRights retrieves the rights available in the org, i.e. the rights of the rights bundles
+// published to it. Query parameters can be supplied to perform additional filtering.
+// Client.GetAllRights returns all the rights defined in vCD.
+func (adminOrg *AdminOrg) GetAllRights(queryParameters url.Values) ([]*types.Right, error) {
+	client, err := adminOrg.tenantClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetAllRights(queryParameters)
+}
+
+// GetAllRoles retrieves all the roles of the org. Query parameters can be supplied to perform
+// additional filtering
+func (adminOrg *AdminOrg) GetAllRoles(queryParameters url.Values) ([]*Role, error) {
+	client, err := adminOrg.tenantClient()
+	if err != nil {
+		return nil, err
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointRoles
+	apiVersion, err := getOpenApiVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var typeResponses []*types.Role
+	err = client.OpenApiGetAllItems(apiVersion, urlRef, queryParameters, &typeResponses)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]*Role, len(typeResponses))
+	for index, typeResponse := range typeResponses {
+		roles[index] = &Role{Role: typeResponse, client: client}
+	}
+	return roles, nil
+}
+
+// GetRoleByName retrieves the role of the org with the given name
+func (adminOrg *AdminOrg) GetRoleByName(name string) (*Role, error) {
+	queryParams := url.Values{}
+	queryParams.Set("filter", fiqlEq("name", name))
+	roles, err := adminOrg.GetAllRoles(queryParams)
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) == 0 {
+		return nil, fmt.Errorf("role '%s' not found in org %s", name, adminOrg.AdminOrg.Name)
+	}
+	if len(roles) > 1 {
+		return nil, fmt.Errorf("more than one role found with name '%s'", name)
+	}
+	return roles[0], nil
+}
+
+// GetRoleById retrieves the role of the org with the given ID
+func (adminOrg *AdminOrg) GetRoleById(id string) (*Role, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty role ID")
+	}
+	client, err := adminOrg.tenantClient()
+	if err != nil {
+		return nil, err
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointRoles
+	apiVersion, err := getOpenApiVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint, id)
+	if err != nil {
+		return nil, err
+	}
+
+	role := NewRole(client)
+	err = client.OpenApiGetItem(apiVersion, urlRef, nil, role.Role)
+	if err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// CreateRole creates a new role in the org. The rights need to be set after creation, with
+// AddRights, or the role can be created with CreateRoleWithRights
+func (adminOrg *AdminOrg) CreateRole(newRole *types.Role) (*Role, error) {
+	client, err := adminOrg.tenantClient()
+	if err != nil {
+		return nil, err
+	}
+	return createRole(client, newRole)
+}
+
+// CreateRoleWithRights creates a new role in the org with the given rights. If the rights cannot
+// be set, the role is removed.
+func (adminOrg *AdminOrg) CreateRoleWithRights(newRole *types.Role, rights types.OpenApiReferences) (*Role, error) {
+	client, err := adminOrg.tenantClient()
+	if err != nil {
+		return nil, err
+	}
+	return createRoleWithRights(client, newRole, rights)
+}
+
+// Clone creates a new role in the same org, with the given name and description and the rights
+// of this role
+func (role *Role) Clone(name, description string) (*Role, error) {
+	rights, err := role.GetRights(nil)
+	if err != nil {
+		return nil, err
+	}
+	references := types.OpenApiReferences{}
+	for _, right := range rights {
+		references = append(references, types.OpenApiReference{Name: right.Name, ID: right.ID})
+	}
+	return createRoleWithRights(role.client, &types.Role{Name: name, Description: description}, references)
+}
+
+// Update sends the current definition of the role (name, description) to vCD
+func (role *Role) Update() error {
+	if role.Role.ID == "" {
+		return fmt.Errorf("cannot update role without ID")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointRoles
+	apiVersion, err := getOpenApiVersion(endpoint)
+	if err != nil {
+		return err
+	}
+	urlRef, err := role.client.OpenApiBuildEndpoint(endpoint, role.Role.ID)
+	if err != nil {
+		return err
+	}
+
+	updated := &types.Role{}
+	err = role.client.OpenApiPutItem(apiVersion, urlRef, nil, role.Role, updated)
+	if err != nil {
+		return fmt.Errorf("error updating role: %s", err)
+	}
+	role.Role = updated
+	return nil
+}
+
+// Delete removes the role. Roles given to users or groups cannot be removed.
+func (role *Role) Delete() error {
+	if role.Role.ID == "" {
+		return fmt.Errorf("cannot delete role without ID")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointRoles
+	apiVersion, err := getOpenApiVersion(endpoint)
+	if err != nil {
+		return err
+	}
+	urlRef, err := role.client.OpenApiBuildEndpoint(endpoint, role.Role.ID)
+	if err != nil {
+		return err
+	}
+	err = role.client.OpenApiDeleteItem(apiVersion, urlRef, nil)
+	if err != nil {
+		return fmt.Errorf("error deleting role: %s", err)
+	}
+	return nil
+}
+
+// GetRights retrieves the rights of the role
+func (role *Role) GetRights(queryParameters url.Values) ([]*types.Right, error) {
+	return getRightsCollection(role.client, types.OpenApiPathVersion1_0_0+types.OpenApiEndpointRoles,
+		role.Role.ID, queryParameters)
+}
+
+// AddRights adds the given rights to the role
+func (role *Role) AddRights(rights types.OpenApiReferences) error {
+	return addRightsToCollection(role.client, types.OpenApiPathVersion1_0_0+types.OpenApiEndpointRoles,
+		role.Role.ID, rights)
+}
+
+// RemoveRights removes the given rights from the role
+func (role *Role) RemoveRights(rights types.OpenApiReferences) error {
+	return removeRightsFromCollection(role.client, types.OpenApiPathVersion1_0_0+types.OpenApiEndpointRoles,
+		role.Role.ID, rights)
+}
+
+// UpdateRights replaces the rights of the role with the given list
+func (role *Role) UpdateRights(rights types.OpenApiReferences) error {
+	return updateRightsCollection(role.client, types.OpenApiPathVersion1_0_0+types.OpenApiEndpointRoles,
+		role.Role.ID, rights)
+}
+
+// createRole creates a role in the org of the tenant context of client
+func createRole(client *Client, newRole *types.Role) (*Role, error) {
+	if newRole == nil || newRole.Name == "" {
+		return nil, fmt.Errorf("role name is required")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointRoles
+	apiVersion, err := getOpenApiVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if newRole.BundleKey == "" {
+		newRole.BundleKey = types.VcloudUndefinedKey
+	}
+	role := NewRole(client)
+	err = client.OpenApiPostItem(apiVersion, urlRef, nil, newRole, role.Role)
+	if err != nil {
+		return nil, fmt.Errorf("error creating role: %s", err)
+	}
+	return role, nil
+}
+
+// createRoleWithRights creates a role with the given rights in the org of the tenant context of
+// client. The role is removed if its rights cannot be set.
+func createRoleWithRights(client *Client, newRole *types.Role, rights types.OpenApiReferences) (*Role, error) {
+	role, err := createRole(client, newRole)
+	if err != nil {
+		return nil, err
+	}
+	err = role.UpdateRights(rights)
+	if err != nil {
+		deleteErr := role.Delete()
+		if deleteErr != nil {
+			return nil, fmt.Errorf("error setting the rights of role %s: %s - the role could not be removed: %s",
+				newRole.Name, err, deleteErr)
+		}
+		return nil, fmt.Errorf("error setting the rights of role %s: %s", newRole.Name, err)
+	}
+	return role, nil
+}
@@ -0,0 +1,141 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+	. "gopkg.in/check.v1"
+)
+
+// Creates a custom role in the test org, changes its rights, clones it and deletes both roles
+func (vcd *TestVCD) Test_Roles(check *C) {
+	if vcd.skipAdminTests {
+		check.Skip(fmt.Sprintf(TestRequiresSysAdminPrivileges, check.TestName()))
+	}
+	adminOrg, err := GetAdminOrgByName(vcd.client, vcd.config.VCD.Org)
+	check.Assert(err, IsNil)
+
+	roles, err := adminOrg.GetAllRoles(nil)
+	check.Assert(err, IsNil)
+	check.Assert(len(roles), Not(Equals), 0)
+
+	orgRights, err := adminOrg.GetAllRights(nil)
+	check.Assert(err, IsNil)
+	check.Assert(len(orgRights), Not(Equals), 0)
+
+	viewRight, err := vcd.client.Client.GetRightByName("Catalog: View Private and Shared Catalogs")
+	check.Assert(err, IsNil)
+	vappRight, err := vcd.client.Client.GetRightByName("vApp: View ACL")
+	check.Assert(err, IsNil)
+
+	role, err := adminOrg.CreateRoleWithRights(&types.Role{Name: TestRole, Description: TestRole},
+		types.OpenApiReferences{{Name: viewRight.Name, ID: viewRight.ID}})
+	check.Assert(err, IsNil)
+	AddToCleanupList(TestRole, "role", vcd.config.VCD.Org, "Test_Roles")
+
+	err = role.AddRights(types.OpenApiReferences{{Name: vappRight.Name, ID: vappRight.ID}})
+	check.Assert(err, IsNil)
+	rights, err := role.GetRights(nil)
+	check.Assert(err, IsNil)
+	check.Assert(len(rights), Equals, 2)
+
+	err = role.RemoveRights(types.OpenApiReferences{{Name: viewRight.Name, ID: viewRight.ID}})
+	check.Assert(err, IsNil)
+	rights, err = role.GetRights(nil)
+	check.Assert(err, IsNil)
+	check.Assert(len(rights), Equals, 1)
+	check.Assert(rights[0].Name, Equals, vappRight.Name)
+
+	role.Role.Description = TestRole + " updated"
+	err = role.Update()
+	check.Assert(err, IsNil)
+	check.Assert(role.Role.Description, Equals, TestRole+" updated")
+
+	clone, err := role.Clone(TestRole+"-clone", "")
+	check.Assert(err, IsNil)
+	AddToCleanupList(TestRole+"-clone", "role", vcd.config.VCD.Org, "Test_Roles")
+	cloneRights, err := clone.GetRights(nil)
+	check.Assert(err, IsNil)
+	check.Assert(len(cloneRights), Equals, 1)
+
+	foundRole, err := adminOrg.GetRoleByName(TestRole)
+	check.Assert(err, IsNil)
+	check.Assert(foundRole.Role.ID, Equals, role.Role.ID)
+
+	err = clone.Delete()
+	check.Assert(err, IsNil)
+	err = role.Delete()
+	check.Assert(err, IsNil)
+	_, err = adminOrg.GetRoleById(role.Role.ID)
+	check.Assert(err, NotNil)
+}
+
+// Checks that the roles are managed in the context of their org, and that a clone gets the
+// rights of the original role
+func TestRole_Clone(t *testing.T) {
+	const orgUuid = "11111111-1111-1111-1111-111111111111"
+	var createdRole types.Role
+	var clonedRights types.OpenApiItems
+	server := vcdtest.NewServer()
+	defer server.Close()
+	inOrg := func(handler http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-VMWARE-VCLOUD-TENANT-CONTEXT") != orgUuid {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			handler(w, r)
+		}
+	}
+	server.HandleFunc(http.MethodGet, "/cloudapi/1.0.0/roles/urn:vcloud:role:1", inOrg(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"id":"urn:vcloud:role:1","name":"role1"}`)
+	}))
+	server.HandleFunc(http.MethodGet, "/cloudapi/1.0.0/roles/urn:vcloud:role:1/rights", inOrg(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"pageCount":1,"values":[{"name":"right1","id":"urn:vcloud:right:1"},{"name":"right2","id":"urn:vcloud:right:2"}]}`)
+	}))
+	server.HandleFunc(http.MethodPost, "/cloudapi/1.0.0/roles/", inOrg(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &createdRole)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprintf(w, `{"id":"urn:vcloud:role:2","name":"%s"}`, createdRole.Name)
+	}))
+	server.HandleFunc(http.MethodPut, "/cloudapi/1.0.0/roles/urn:vcloud:role:2/rights", inOrg(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &clonedRights)
+		_, _ = w.Write(body)
+	}))
+
+	client := &newMockClient(t, server).Client
+	adminOrg := NewAdminOrg(client)
+	adminOrg.AdminOrg.Name = "org1"
+	adminOrg.AdminOrg.HREF = server.URL() + "/api/admin/org/" + orgUuid
+
+	role, err := adminOrg.GetRoleById("urn:vcloud:role:1")
+	if err != nil {
+		t.Fatalf("error retrieving role: %s", err)
+	}
+	clone, err := role.Clone("role1-clone", "copy of role1")
+	if err != nil {
+		t.Fatalf("error cloning role: %s", err)
+	}
+	if clone.Role.ID != "urn:vcloud:role:2" || createdRole.Description != "copy of role1" ||
+		createdRole.BundleKey != types.VcloudUndefinedKey {
+		t.Errorf("unexpected clone %+v created from %+v", clone.Role, createdRole)
+	}
+	if fmt.Sprint(clonedRights.Values) != "[{right1 urn:vcloud:right:1} {right2 urn:vcloud:right:2}]" {
+		t.Errorf("unexpected rights of the clone: %v", clonedRights.Values)
+	}
+	if client.tenantContext != "" {
+		t.Errorf("tenant context set on the original client")
+	}
+}
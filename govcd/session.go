@@ -0,0 +1,157 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/util"
+)
+
+// SessionToken holds what a client needs to use an existing session, so that a session opened by
+// a process can be reused by another one, without logging in again (see ExportSessionToken and
+// ImportSessionToken). It must be kept as secret as a password.
+type SessionToken struct {
+	AuthHeader  string // Name of the authorization header, e.g. x-vcloud-authorization
+	Token       string // Value of the authorization header
+	AccessToken string // Bearer token returned by vCD 10.0+, needed by the OAuth endpoints
+}
+
+// sessionKeepAlive is the state of the goroutine which keeps the session alive
+type sessionKeepAlive struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+// GetSessionInfo retrieves the current session, with the user, the org and the roles of the user
+func (vcdCli *VCDClient) GetSessionInfo() (*types.Session, error) {
+	if vcdCli.Client.VCDToken == "" {
+		return nil, fmt.Errorf("cannot retrieve session: client is not authenticated")
+	}
+	sessionHREF := vcdCli.Client.VCDHREF
+	sessionHREF.Path += "/session"
+
+	session := &types.Session{}
+	_, err := vcdCli.Client.ExecuteRequest(sessionHREF.String(), http.MethodGet, "",
+		"error retrieving session: %s", nil, session)
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// ExportSessionToken returns the token of the current session, to be used by another client with
+// ImportSessionToken
+func (vcdCli *VCDClient) ExportSessionToken() (SessionToken, error) {
+	if vcdCli.Client.VCDToken == "" || vcdCli.Client.VCDAuthHeader == "" {
+		return SessionToken{}, fmt.Errorf("cannot export session token: client is not authenticated")
+	}
+	return SessionToken{
+		AuthHeader:  vcdCli.Client.VCDAuthHeader,
+		Token:       vcdCli.Client.VCDToken,
+		AccessToken: vcdCli.Client.VCDAccessToken,
+	}, nil
+}
+
+// ImportSessionToken makes the client use the session of the given token, as an alternative to
+// Authenticate. The session is checked with vCD, which also tells whether it belongs to a system
+// administrator.
+func (vcdCli *VCDClient) ImportSessionToken(token SessionToken) error {
+	if token.AuthHeader == "" || token.Token == "" {
+		return fmt.Errorf("cannot import session token: authorization header and token are required")
+	}
+	err := vcdCli.vcdloginurl()
+	if err != nil {
+		return fmt.Errorf("error finding LoginUrl: %s", err)
+	}
+	vcdCli.Client.VCDAuthHeader = token.AuthHeader
+	vcdCli.Client.VCDToken = token.Token
+	vcdCli.Client.VCDAccessToken = token.AccessToken
+
+	session, err := vcdCli.GetSessionInfo()
+	if err != nil {
+		vcdCli.Client.VCDAuthHeader = ""
+		vcdCli.Client.VCDToken = ""
+		vcdCli.Client.VCDAccessToken = ""
+		return fmt.Errorf("error importing session token: %s", err)
+	}
+	vcdCli.Client.IsSysAdmin = strings.ToLower(session.Org) == "system"
+	vcdCli.QueryHREF = vcdCli.Client.VCDHREF
+	vcdCli.QueryHREF.Path += "/query"
+	return nil
+}
+
+// StartSessionKeepAlive starts a goroutine which retrieves the session every interval, so that
+// vCD does not end it for inactivity while a long-running process is idle. interval must be shorter
+// than the idle session timeout of vCD (30 minutes by default). The goroutine stops with
+// StopSessionKeepAlive, Disconnect, the end of the context of the client, or when the session has
+// expired anyway.
+func (vcdCli *VCDClient) StartSessionKeepAlive(interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("session keepalive interval must be positive")
+	}
+	if vcdCli.Client.VCDToken == "" {
+		return fmt.Errorf("cannot keep the session alive: client is not authenticated")
+	}
+	vcdCli.sessionMutex.Lock()
+	defer vcdCli.sessionMutex.Unlock()
+	if vcdCli.keepAlive != nil {
+		return fmt.Errorf("session keepalive already started")
+	}
+	keepAlive := &sessionKeepAlive{stop: make(chan struct{}), done: make(chan struct{})}
+	vcdCli.keepAlive = keepAlive
+
+	go func() {
+		defer close(keepAlive.done)
+		// When the goroutine ends by itself, the keepalive can be started again, e.g. after a
+		// new authentication
+		defer func() {
+			vcdCli.sessionMutex.Lock()
+			if vcdCli.keepAlive == keepAlive {
+				vcdCli.keepAlive = nil
+			}
+			vcdCli.sessionMutex.Unlock()
+		}()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-keepAlive.stop:
+				return
+			case <-vcdCli.Client.Context().Done():
+				return
+			case <-ticker.C:
+				_, err := vcdCli.GetSessionInfo()
+				if errors.Is(err, ErrorUnauthorized) {
+					util.Logger.Printf("[ERROR] session expired, keepalive stopped: %s\n", err)
+					return
+				}
+				if err != nil {
+					util.Logger.Printf("[WARNING] session keepalive failed: %s\n", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// StopSessionKeepAlive stops the goroutine started by StartSessionKeepAlive, if any, and waits
+// for its end
+func (vcdCli *VCDClient) StopSessionKeepAlive() {
+	vcdCli.sessionMutex.Lock()
+	keepAlive := vcdCli.keepAlive
+	vcdCli.keepAlive = nil
+	vcdCli.sessionMutex.Unlock()
+	if keepAlive == nil {
+		return
+	}
+	close(keepAlive.stop)
+	<-keepAlive.done
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// Tests the retrieval, reuse and keepalive of a session against a fake vCD
+func TestVCDClient_Session(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	vcdClient := NewVCDClient(server.ApiURL(), true)
+	_, err := vcdClient.ExportSessionToken()
+	if err == nil {
+		t.Errorf("expected error exporting the token of a client not authenticated")
+	}
+	err = vcdClient.Authenticate("user", "password", vcdtest.MockOrgName)
+	if err != nil {
+		t.Fatalf("error authenticating: %s", err)
+	}
+	session, err := vcdClient.GetSessionInfo()
+	if err != nil {
+		t.Fatalf("error retrieving session: %s", err)
+	}
+	if session.User != "user" || session.Org != vcdtest.MockOrgName {
+		t.Errorf("unexpected session: %#v", session)
+	}
+
+	// The session is reused by another client, without login
+	token, err := vcdClient.ExportSessionToken()
+	if err != nil {
+		t.Fatalf("error exporting session token: %s", err)
+	}
+	server.ClearRequests()
+	otherClient := NewVCDClient(server.ApiURL(), true)
+	err = otherClient.ImportSessionToken(SessionToken{AuthHeader: token.AuthHeader, Token: "expired"})
+	if err == nil || otherClient.Client.VCDToken != "" {
+		t.Errorf("expected error importing an invalid session token")
+	}
+	err = otherClient.ImportSessionToken(token)
+	if err != nil {
+		t.Fatalf("error importing session token: %s", err)
+	}
+	_, err = GetOrgByName(otherClient, vcdtest.MockOrgName)
+	if err != nil {
+		t.Fatalf("error retrieving org with imported session: %s", err)
+	}
+	if logins := server.RequestsTo(http.MethodPost, "/api/sessions"); len(logins) != 0 {
+		t.Errorf("expected no login with imported session, got %d", len(logins))
+	}
+
+	// The keepalive retrieves the session until it is stopped by Disconnect
+	server.ClearRequests()
+	if err = vcdClient.StartSessionKeepAlive(0); err == nil {
+		t.Errorf("expected error with keepalive interval of 0")
+	}
+	err = vcdClient.StartSessionKeepAlive(5 * time.Millisecond)
+	if err != nil {
+		t.Fatalf("error starting keepalive: %s", err)
+	}
+	if err = vcdClient.StartSessionKeepAlive(5 * time.Millisecond); err == nil {
+		t.Errorf("expected error starting keepalive twice")
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(server.RequestsTo(http.MethodGet, "/api/session")) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	err = vcdClient.Disconnect()
+	if err != nil {
+		t.Fatalf("error disconnecting: %s", err)
+	}
+	keepAlives := len(server.RequestsTo(http.MethodGet, "/api/session"))
+	if keepAlives < 2 {
+		t.Errorf("expected at least 2 keepalive requests, got %d", keepAlives)
+	}
+	if vcdClient.Client.VCDToken != "" || vcdClient.keepAlive != nil {
+		t.Errorf("expected client without session after disconnection")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if len(server.RequestsTo(http.MethodGet, "/api/session")) != keepAlives {
+		t.Errorf("expected no keepalive request after disconnection")
+	}
+	if err = vcdClient.StartSessionKeepAlive(time.Second); err == nil {
+		t.Errorf("expected error starting keepalive without session")
+	}
+}
+
+// Checks that the keepalive stops by itself when the session expires, and can be started again
+// after a new authentication
+func TestVCDClient_SessionKeepAliveExpiry(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	vcdClient := newMockClient(t, server)
+	server.HandleXML(http.MethodGet, "/api/session", http.StatusUnauthorized,
+		`<Error xmlns="http://www.vmware.com/vcloud/v1.5" minorErrorCode="UNAUTHORIZED" message="session expired" majorErrorCode="401"/>`)
+	err := vcdClient.StartSessionKeepAlive(5 * time.Millisecond)
+	if err != nil {
+		t.Fatalf("error starting keepalive: %s", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		vcdClient.sessionMutex.Lock()
+		stopped := vcdClient.keepAlive == nil
+		vcdClient.sessionMutex.Unlock()
+		if stopped {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the keepalive to stop after the session expiry")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	err = vcdClient.Authenticate("user", "password", vcdtest.MockOrgName)
+	if err != nil {
+		t.Fatalf("error authenticating again: %s", err)
+	}
+	err = vcdClient.StartSessionKeepAlive(time.Second)
+	if err != nil {
+		t.Fatalf("error starting keepalive after a new authentication: %s", err)
+	}
+	vcdClient.StopSessionKeepAlive()
+}
@@ -87,6 +87,16 @@ func GetAdminOrgByName(vcdClient *VCDClient, orgName string) (AdminOrg, error) {
 	return *org, nil
 }
 
+// QueryCells returns the query records of the vCD cells, with their version and status.
+// Requires system administrator privileges.
+func QueryCells(vcdClient *VCDClient) ([]*types.QueryResultCellRecordType, error) {
+	results, err := vcdClient.Client.QueryAllPages("cell", nil)
+	if err != nil {
+		return nil, fmt.Errorf("error querying cells: %s", err)
+	}
+	return results.Results.CellRecord, nil
+}
+
 // Returns the HREF of the org with the name orgName
 func getOrgHREF(vcdClient *VCDClient, orgName string) (string, error) {
 	orgListHREF := vcdClient.Client.VCDHREF
@@ -6,8 +6,10 @@ package govcd
 
 import (
 	"fmt"
+	"testing"
 
 	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
 	. "gopkg.in/check.v1"
 )
 
@@ -104,3 +106,40 @@ func (vcd *TestVCD) Test_CreateOrg(check *C) {
 var INVALID_NAME = `*******************************************INVALID
 					****************************************************
 					************************`
+
+// Tests the login and the retrieval of the org and its VDC and catalog against the fake vCD
+func TestGetOrgByNameFakeVcd(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	vcdClient := newMockClient(t, server)
+
+	org, err := GetOrgByName(vcdClient, vcdtest.MockOrgName)
+	if err != nil {
+		t.Fatalf("error retrieving org: %s", err)
+	}
+	vdc, err := org.GetVdcByName(vcdtest.MockVdcName)
+	if err != nil || vdc.Vdc.Name != vcdtest.MockVdcName {
+		t.Errorf("error retrieving VDC: %v", err)
+	}
+	catalog, err := org.FindCatalog(vcdtest.MockCatalogName)
+	if err != nil || catalog.Catalog.Name != vcdtest.MockCatalogName {
+		t.Errorf("error retrieving catalog: %v", err)
+	}
+	adminOrg, err := GetAdminOrgByName(vcdClient, vcdtest.MockOrgName)
+	if err != nil || adminOrg.AdminOrg.Name != vcdtest.MockOrgName {
+		t.Errorf("error retrieving admin org: %v", err)
+	}
+	_, err = GetOrgByName(vcdClient, "missing-org")
+	if err == nil {
+		t.Errorf("expected error retrieving a missing org")
+	}
+
+	if len(server.RequestsTo("POST", "/api/sessions")) != 1 {
+		t.Errorf("expected one login request")
+	}
+	err = vcdClient.Disconnect()
+	if err != nil {
+		t.Errorf("error disconnecting: %s", err)
+	}
+}
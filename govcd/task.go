@@ -62,13 +62,15 @@ func (task *Task) Refresh() error {
 		return fmt.Errorf("error retrieving task: %s", err)
 	}
 
-	// Empty struct before a new unmarshal, otherwise we end up with duplicate
-	// elements in slices.
-	task.Task = &types.Task{}
+	// Unmarshal into an empty struct, otherwise we end up with duplicate elements in
+	// slices, and keep the current one when the response cannot be decoded.
+	refreshed := &types.Task{}
 
-	if err = decodeBody(resp, task.Task); err != nil {
-		return fmt.Errorf("error decoding task response: %s", task.getErrorMessage(err))
+	if err = decodeBody(resp, refreshed); err != nil {
+		decoded := Task{Task: refreshed}
+		return fmt.Errorf("error decoding task response: %s", decoded.getErrorMessage(err))
 	}
+	task.Task = refreshed
 
 	// The request was successful
 	return nil
@@ -112,6 +114,8 @@ func (task *Task) WaitInspectTaskCompletion(inspectionFunc InspectionFunc, delay
 
 		// If task is not in a waiting status we're done, check if there's an error and return it.
 		if task.Task.Status != "queued" && task.Task.Status != "preRunning" && task.Task.Status != "running" {
+			// The entities changed by the task must be retrieved again
+			task.client.ClearEntityCache()
 			if inspectionFunc != nil {
 				inspectionFunc(task.Task,
 					howManyTimesRefreshed,
@@ -135,8 +139,11 @@ func (task *Task) WaitInspectTaskCompletion(inspectionFunc InspectionFunc, delay
 			)
 		}
 
-		// Sleep for a given period and try again.
-		time.Sleep(delay)
+		// Sleep for a given period and try again, unless the context of the client is done.
+		err = task.client.sleep(delay)
+		if err != nil {
+			return fmt.Errorf("stopped waiting for task: %s", err)
+		}
 	}
 }
 
@@ -146,6 +153,32 @@ func (task *Task) WaitTaskCompletion() error {
 	return task.WaitInspectTaskCompletion(nil, 3*time.Second)
 }
 
+// WaitTaskCompletionWithProgress checks the status of the task every 3 seconds, like
+// WaitTaskCompletion, and sends the progress of the task (0-100) to progress every time it
+// changes. The channel is closed when the wait is over, so that callers can range over it in a
+// separate goroutine. The channel must be read, or the wait blocks.
+func (task *Task) WaitTaskCompletionWithProgress(progress chan<- int) error {
+	return task.waitTaskCompletionWithProgress(progress, 3*time.Second)
+}
+
+// waitTaskCompletionWithProgress implements WaitTaskCompletionWithProgress with the given delay
+// between the refreshes of the task
+func (task *Task) waitTaskCompletionWithProgress(progress chan<- int, delay time.Duration) error {
+	defer close(progress)
+	lastProgress := -1
+	return task.WaitInspectTaskCompletion(func(inspected *types.Task, howManyTimes int, elapsed time.Duration, first, last bool) {
+		currentProgress := inspected.Progress
+		// A completed task does not always report a progress of 100
+		if inspected.Status == "success" {
+			currentProgress = 100
+		}
+		if currentProgress != lastProgress {
+			progress <- currentProgress
+			lastProgress = currentProgress
+		}
+	}, delay)
+}
+
 func (task *Task) GetTaskProgress() (string, error) {
 	if task.Task == nil {
 		return "", fmt.Errorf("cannot refresh, Object is empty")
@@ -178,3 +211,24 @@ func (task *Task) CancelTask() error {
 	}
 	return nil
 }
+
+// Cancel requests the cancellation of the task and refreshes it. The cancellation is
+// asynchronous: the status of the task becomes "aborted" when vCD has stopped the operation,
+// which can be waited for with WaitTaskCompletion. Completed tasks cannot be cancelled.
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/POST-CancelTask.html
+func (task *Task) Cancel() error {
+	if task.Task == nil || task.Task.HREF == "" {
+		return fmt.Errorf("cannot cancel, Object is empty")
+	}
+	switch task.Task.Status {
+	case "success", "error", "aborted":
+		return fmt.Errorf("task %s cannot be cancelled, as its status is %s", task.Task.HREF, task.Task.Status)
+	}
+
+	err := task.client.ExecuteRequestWithoutResponse(task.Task.HREF+"/action/cancel", http.MethodPost,
+		"", "error cancelling task: %s", nil)
+	if err != nil {
+		return err
+	}
+	return task.Refresh()
+}
@@ -4,6 +4,16 @@
 
 package govcd
 
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
 /*
 TO BE REMOVED (or reintroduced with different scope) : task completion is tested as part of vdc_test.go
 import (
@@ -23,3 +33,65 @@ func (vcd *TestVCD) Test_WaitTaskCompletion(check *C) {
 
 }
 */
+
+// Checks the progress reported while waiting for a task, and the cancellation of a task
+func TestTask_ProgressAndCancel(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	const taskPath = "/api/task/1"
+	const cancelPath = taskPath + "/action/cancel"
+	server.Handle(http.MethodPost, cancelPath, vcdtest.Response{Status: http.StatusNoContent})
+	var lock sync.Mutex
+	refreshes := 0
+	cancelled := func() bool {
+		return len(server.RequestsTo(http.MethodPost, cancelPath)) > 0
+	}
+	server.HandleFunc(http.MethodGet, taskPath, func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		status, progress := "running", refreshes*40
+		if cancelled() {
+			status = "aborted"
+		} else if refreshes == 3 {
+			status, progress = "success", 0
+		}
+		refreshes++
+		w.Header().Set("Content-Type", "application/vnd.vmware.vcloud.task+xml")
+		_, _ = fmt.Fprintf(w, `<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="%s" href="%s%s"><Progress>%d</Progress></Task>`,
+			status, server.URL(), taskPath, progress)
+	})
+
+	task := NewTask(&newMockClient(t, server).Client)
+	task.Task.HREF = server.URL() + taskPath
+
+	progress := make(chan int)
+	var reported []int
+	done := make(chan bool)
+	go func() {
+		for value := range progress {
+			reported = append(reported, value)
+		}
+		done <- true
+	}()
+	err := task.waitTaskCompletionWithProgress(progress, time.Millisecond)
+	if err != nil {
+		t.Fatalf("error waiting for task: %s", err)
+	}
+	<-done
+	if fmt.Sprint(reported) != "[0 40 80 100]" {
+		t.Errorf("unexpected progress: %v", reported)
+	}
+
+	err = task.Cancel()
+	if err == nil {
+		t.Errorf("expected error when cancelling a completed task")
+	}
+	task.Task.Status = "running"
+	err = task.Cancel()
+	if err != nil {
+		t.Fatalf("error cancelling task: %s", err)
+	}
+	if !cancelled() || task.Task.Status != "aborted" {
+		t.Errorf("task not cancelled: %s", task.Task.Status)
+	}
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"net/http"
+	"time"
+)
+
+// HttpHook is called after every HTTP exchange of a client, e.g. to log or measure the requests.
+// resp is nil when err is not. The hook must not read the body of the response, nor change the
+// request or the response. When a retry policy is set, the hook is called for every attempt.
+type HttpHook func(req *http.Request, resp *http.Response, err error, elapsed time.Duration)
+
+// hookTransport is an http.RoundTripper which calls a hook after each exchange
+type hookTransport struct {
+	hook      HttpHook
+	transport http.RoundTripper
+}
+
+// RoundTrip sends the request and passes the outcome to the hook
+func (hooked *hookTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := hooked.transport.RoundTrip(req)
+	hooked.hook(req, resp, err, time.Since(start))
+	return resp, err
+}
+
+// innermostTransport returns the location of the transport of httpClient which sends the requests,
+// below the ones added by the options of this package (retry policy, rate limit and hooks). The
+// transport found there is nil when httpClient uses http.DefaultTransport.
+func innermostTransport(httpClient *http.Client) *http.RoundTripper {
+	location := &httpClient.Transport
+	for {
+		switch transport := (*location).(type) {
+		case *retryTransport:
+			location = &transport.transport
+		case *rateLimitTransport:
+			location = &transport.transport
+		case *hookTransport:
+			location = &transport.transport
+		default:
+			return location
+		}
+	}
+}
+
+// innermostHttpTransport returns the *http.Transport which sends the requests of httpClient, or
+// nil when a custom transport is used
+func innermostHttpTransport(httpClient *http.Client) *http.Transport {
+	transport, _ := (*innermostTransport(httpClient)).(*http.Transport)
+	return transport
+}
+
+// cloneInnermostHttpTransport replaces the *http.Transport which sends the requests of httpClient
+// with a copy and returns it, so that changing it alters neither http.DefaultTransport nor the
+// transport of an HTTP client given by the caller. It returns nil when a custom transport is used.
+func cloneInnermostHttpTransport(httpClient *http.Client) *http.Transport {
+	location := innermostTransport(httpClient)
+	if *location == nil {
+		*location = http.DefaultTransport
+	}
+	transport, ok := (*location).(*http.Transport)
+	if !ok {
+		return nil
+	}
+	transport = transport.Clone()
+	*location = transport
+	return transport
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// Tests the options of NewVCDClient which configure the HTTP client
+func TestVCDClientHttpOptions(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	var mutex sync.Mutex
+	var hooked []string
+	hook := func(req *http.Request, resp *http.Response, err error, elapsed time.Duration) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if err == nil {
+			hooked = append(hooked, req.Method+" "+req.URL.Path+" "+resp.Status)
+		}
+	}
+	proxyUrl, _ := url.Parse("http://proxy.example.com:3128")
+
+	vcdClient := NewVCDClient(server.ApiURL(), false,
+		WithTLSConfig(&tls.Config{InsecureSkipVerify: true, ServerName: "vcd.example.com"}),
+		WithProxy(proxyUrl),
+		WithRetryPolicy(RetryPolicy{MaxRetries: 1}),
+		WithHttpHook(hook),
+		WithUserAgent("vcdtest-agent/1.0"),
+		WithHttpTimeout(time.Minute))
+	transport := innermostHttpTransport(&vcdClient.Client.Http)
+	if transport == nil || transport.TLSClientConfig.ServerName != "vcd.example.com" {
+		t.Errorf("expected the TLS configuration in the HTTP transport")
+	}
+	if proxy, err := transport.Proxy(&http.Request{URL: &url.URL{Scheme: "https", Host: "vcd.example.com"}}); err != nil ||
+		proxy.String() != proxyUrl.String() {
+		t.Errorf("expected proxy %s, got %v", proxyUrl, proxy)
+	}
+	if vcdClient.Client.Http.Timeout != time.Minute {
+		t.Errorf("unexpected HTTP timeout: %s", vcdClient.Client.Http.Timeout)
+	}
+
+	// An injected HTTP client keeps the retry policy and the hooks
+	httpClient := &http.Client{Transport: &http.Transport{}, Timeout: 2 * time.Minute}
+	err := WithHttpClient(httpClient)(vcdClient)
+	if err != nil {
+		t.Fatalf("error setting HTTP client: %s", err)
+	}
+	if _, ok := vcdClient.Client.Http.Transport.(*retryTransport); !ok || vcdClient.Client.Http.Timeout != 2*time.Minute {
+		t.Errorf("unexpected HTTP client: %#v", vcdClient.Client.Http)
+	}
+	if *innermostTransport(&vcdClient.Client.Http) != httpClient.Transport {
+		t.Errorf("expected the transport of the injected HTTP client to send the requests")
+	}
+	if httpClient.Transport != (*innermostTransport(httpClient)) {
+		t.Errorf("the injected HTTP client must not be changed")
+	}
+
+	err = vcdClient.Authenticate("user", "password", vcdtest.MockOrgName)
+	if err != nil {
+		t.Fatalf("error authenticating: %s", err)
+	}
+	_, err = GetOrgByName(vcdClient, vcdtest.MockOrgName)
+	if err != nil {
+		t.Fatalf("error retrieving org: %s", err)
+	}
+	requests := server.Requests()
+	if len(hooked) != len(requests) || hooked[0] != "GET /api/versions 200 OK" {
+		t.Errorf("expected a hook call per request, got %v", hooked)
+	}
+	for _, request := range requests {
+		if request.Header.Get("User-Agent") != "vcdtest-agent/1.0" {
+			t.Errorf("unexpected user agent for %s: %s", request.Path, request.Header.Get("User-Agent"))
+		}
+	}
+
+	// TLS and proxy are set on a copy of the transport, leaving the shared ones unchanged
+	defaultTransport := http.DefaultTransport.(*http.Transport)
+	ownTransport := &http.Transport{}
+	for _, httpClient := range []*http.Client{{}, {Transport: http.DefaultTransport}, {Transport: ownTransport}} {
+		vcdClient = NewVCDClient(server.ApiURL(), true, WithHttpClient(httpClient),
+			WithTLSConfig(&tls.Config{ServerName: "vcd.example.com"}), WithProxy(proxyUrl))
+		transport = innermostHttpTransport(&vcdClient.Client.Http)
+		if transport == nil || transport == defaultTransport || transport == ownTransport ||
+			transport.TLSClientConfig.ServerName != "vcd.example.com" || transport.Proxy == nil {
+			t.Errorf("expected the TLS configuration and the proxy in a copy of the transport")
+		}
+	}
+	request := &http.Request{URL: &url.URL{Scheme: "https", Host: "vcd.example.com"}}
+	for name, shared := range map[string]*http.Transport{"http.DefaultTransport": defaultTransport, "injected transport": ownTransport} {
+		if shared.TLSClientConfig != nil && shared.TLSClientConfig.ServerName != "" {
+			t.Errorf("the TLS configuration of the %s must not be changed", name)
+		}
+		if shared.Proxy != nil {
+			if proxy, _ := shared.Proxy(request); proxy != nil && proxy.String() == proxyUrl.String() {
+				t.Errorf("the proxy of the %s must not be changed", name)
+			}
+		}
+	}
+	if http.DefaultTransport != defaultTransport {
+		t.Errorf("http.DefaultTransport must not be replaced")
+	}
+
+	// TLS and proxy cannot be set on a custom transport
+	vcdClient = NewVCDClient(server.ApiURL(), true, WithHttpClient(&http.Client{Transport: &sequenceTransport{}}))
+	if err = WithTLSConfig(&tls.Config{})(vcdClient); err == nil {
+		t.Errorf("expected error setting the TLS configuration of a custom transport")
+	}
+	if err = WithProxy(nil)(vcdClient); err == nil {
+		t.Errorf("expected error setting the proxy of a custom transport")
+	}
+}
@@ -143,7 +143,9 @@ func uploadPartFile(client *Client, part []byte, partDataSize int64, uDetails up
 		return err
 	}
 
-	response, err := checkResp(client.Http.Do(request))
+	client.setUserAgent(request)
+	client.clearEntityCacheForChange(request.Method)
+	response, err := checkResp(client.Http.Do(request.WithContext(client.Context())))
 	if err != nil {
 		return fmt.Errorf("File upload failed. Err: %s \n", err)
 	}
@@ -194,6 +196,10 @@ func createTaskForVcdImport(client *Client, taskHREF string) (Task, error) {
 	return *task, nil
 }
 
+// UploadProgressFunc receives the progress of an upload: the bytes uploaded so far and the total
+// size of the files to upload
+type UploadProgressFunc func(bytesUploaded, totalSize int64)
+
 func getCallBackFunction() (func(int64, int64), *float64) {
 	var uploadProgress float64
 	callback := func(bytesUploaded, totalSize int64) {
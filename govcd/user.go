@@ -0,0 +1,229 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// OrgUser defines a user of an organization
+type OrgUser struct {
+	User     *types.User
+	client   *Client
+	AdminOrg *AdminOrg // the organization the user belongs to
+}
+
+// OrgUserConfiguration is a simplified definition of a local user, used by CreateUserSimple
+type OrgUserConfiguration struct {
+	Name            string // mandatory
+	Password        string // mandatory
+	RoleName        string // mandatory: name of an org role, such as OrgUserRoleVappUser
+	FullName        string
+	Description     string
+	EmailAddress    string
+	Telephone       string
+	IsEnabled       bool
+	DeployedVmQuota int // 0 means unlimited
+	StoredVmQuota   int // 0 means unlimited
+}
+
+// NewUser creates a new user structure which still needs to have User attribute populated
+func NewUser(cli *Client, org *AdminOrg) *OrgUser {
+	return &OrgUser{
+		User:     new(types.User),
+		client:   cli,
+		AdminOrg: org,
+	}
+}
+
+// CreateUser creates a user in the organization.
+// Name, ProviderType (one of types.OrgUserProviderIntegrated, types.OrgUserProviderSAML,
+// types.OrgUserProviderOAUTH) and Role are mandatory. Password is mandatory for local users.
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/POST-CreateUser.html
+func (adminOrg *AdminOrg) CreateUser(user *types.User) (*OrgUser, error) {
+	if user == nil || user.Name == "" {
+		return nil, fmt.Errorf("user name is required")
+	}
+	if user.ProviderType == "" {
+		return nil, fmt.Errorf("user provider type is required")
+	}
+	if user.Role == nil || user.Role.HREF == "" {
+		return nil, fmt.Errorf("user role is required")
+	}
+	if user.ProviderType == types.OrgUserProviderIntegrated && !user.IsExternal && user.Password == "" {
+		return nil, fmt.Errorf("password is required for local user %s", user.Name)
+	}
+	user.Xmlns = types.XMLNamespaceVCloud
+
+	usersHREF, err := url.ParseRequestURI(adminOrg.AdminOrg.HREF)
+	if err != nil {
+		return nil, fmt.Errorf("error getting AdminOrg HREF %s : %v", adminOrg.AdminOrg.HREF, err)
+	}
+	usersHREF.Path += "/users"
+
+	orgUser := NewUser(adminOrg.client, adminOrg)
+	_, err = adminOrg.client.ExecuteRequest(usersHREF.String(), http.MethodPost,
+		types.MimeAdminUser, "error creating user: %s", user, orgUser.User)
+	if err != nil {
+		return nil, err
+	}
+	return orgUser, nil
+}
+
+// CreateUserSimple creates a local user from a simplified configuration, looking up the role by name
+func (adminOrg *AdminOrg) CreateUserSimple(userConfiguration OrgUserConfiguration) (*OrgUser, error) {
+	if userConfiguration.Name == "" || userConfiguration.Password == "" || userConfiguration.RoleName == "" {
+		return nil, fmt.Errorf("user name, password and role name are required")
+	}
+	role, err := adminOrg.GetRoleReference(userConfiguration.RoleName)
+	if err != nil {
+		return nil, err
+	}
+	return adminOrg.CreateUser(&types.User{
+		Name:            userConfiguration.Name,
+		Password:        userConfiguration.Password,
+		Role:            &types.Reference{HREF: role.HREF},
+		ProviderType:    types.OrgUserProviderIntegrated,
+		FullName:        userConfiguration.FullName,
+		Description:     userConfiguration.Description,
+		EmailAddress:    userConfiguration.EmailAddress,
+		Telephone:       userConfiguration.Telephone,
+		IsEnabled:       userConfiguration.IsEnabled,
+		DeployedVmQuota: userConfiguration.DeployedVmQuota,
+		StoredVmQuota:   userConfiguration.StoredVmQuota,
+	})
+}
+
+// GetAllRoleReferences refreshes the org and returns the references of the roles available in it
+func (adminOrg *AdminOrg) GetAllRoleReferences() ([]*types.Reference, error) {
+	err := adminOrg.Refresh()
+	if err != nil {
+		return nil, err
+	}
+	if adminOrg.AdminOrg.RoleReferences == nil {
+		return nil, nil
+	}
+	return adminOrg.AdminOrg.RoleReferences.RoleReference, nil
+}
+
+// GetUserByHref retrieves a user by its HREF
+func (adminOrg *AdminOrg) GetUserByHref(href string) (*OrgUser, error) {
+	orgUser := NewUser(adminOrg.client, adminOrg)
+	_, err := adminOrg.client.ExecuteRequest(href, http.MethodGet,
+		"", "error retrieving user: %s", nil, orgUser.User)
+	if err != nil {
+		return nil, err
+	}
+	return orgUser, nil
+}
+
+// GetUserByName refreshes the org and retrieves the user with the given name.
+// Returns an error if the user is not found.
+func (adminOrg *AdminOrg) GetUserByName(name string) (*OrgUser, error) {
+	err := adminOrg.Refresh()
+	if err != nil {
+		return nil, err
+	}
+	if adminOrg.AdminOrg.Users != nil {
+		for _, userRef := range adminOrg.AdminOrg.Users.UserReference {
+			if userRef.Name == name {
+				return adminOrg.GetUserByHref(userRef.HREF)
+			}
+		}
+	}
+	return nil, fmt.Errorf("user %s not found in org %s", name, adminOrg.AdminOrg.Name)
+}
+
+// Refresh retrieves the user again
+func (user *OrgUser) Refresh() error {
+	if user.User == nil || user.User.HREF == "" {
+		return fmt.Errorf("cannot refresh, Object is empty")
+	}
+	href := user.User.HREF
+
+	// Unmarshal into an empty struct, otherwise we end up with duplicate elements in
+	// slices, and keep the current one when the request fails.
+	refreshed := &types.User{}
+
+	_, err := user.client.ExecuteRequest(href, http.MethodGet,
+		"", "error retrieving user: %s", nil, refreshed)
+	if err != nil {
+		return err
+	}
+	user.User = refreshed
+	return nil
+}
+
+// Update sends the current user definition to vCD
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/PUT-User.html
+func (user *OrgUser) Update() error {
+	if user.User.HREF == "" {
+		return fmt.Errorf("cannot update user without HREF")
+	}
+	payload := *user.User
+	payload.Xmlns = types.XMLNamespaceVCloud
+	payload.Link = nil
+	payload.Tasks = nil
+
+	updated := &types.User{}
+	_, err := user.client.ExecuteRequest(user.User.HREF, http.MethodPut,
+		types.MimeAdminUser, "error updating user: %s", &payload, updated)
+	if err != nil {
+		return err
+	}
+	user.User = updated
+	return nil
+}
+
+// ChangeRole assigns the org role with the given name to the user
+func (user *OrgUser) ChangeRole(roleName string) error {
+	if user.AdminOrg == nil {
+		return fmt.Errorf("user %s has no parent organization", user.User.Name)
+	}
+	role, err := user.AdminOrg.GetRoleReference(roleName)
+	if err != nil {
+		return err
+	}
+	user.User.Role = &types.Reference{HREF: role.HREF, Name: role.Name}
+	return user.Update()
+}
+
+// Enable enables the user, allowing it to log in
+func (user *OrgUser) Enable() error {
+	user.User.IsEnabled = true
+	return user.Update()
+}
+
+// Disable disables the user, preventing it from logging in
+func (user *OrgUser) Disable() error {
+	user.User.IsEnabled = false
+	return user.Update()
+}
+
+// ChangePassword changes the password of a local user
+func (user *OrgUser) ChangePassword(newPassword string) error {
+	if newPassword == "" {
+		return fmt.Errorf("the new password of user %s cannot be empty", user.User.Name)
+	}
+	user.User.Password = newPassword
+	err := user.Update()
+	// The password is not kept in memory, whatever the outcome of the update
+	user.User.Password = ""
+	return err
+}
+
+// Delete removes the user from the organization
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/DELETE-User.html
+func (user *OrgUser) Delete() error {
+	if user.User.HREF == "" {
+		return fmt.Errorf("cannot delete user without HREF")
+	}
+	return user.client.ExecuteRequestWithoutResponse(user.User.HREF, http.MethodDelete,
+		"", "error deleting user: %s", nil)
+}
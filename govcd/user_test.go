@@ -0,0 +1,65 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+
+	. "gopkg.in/check.v1"
+)
+
+// Creates a local user in the test org, changes its role, status and password, and deletes it
+func (vcd *TestVCD) Test_UserCRUD(check *C) {
+	if vcd.skipAdminTests {
+		check.Skip(fmt.Sprintf(TestRequiresSysAdminPrivileges, check.TestName()))
+	}
+	adminOrg, err := GetAdminOrgByName(vcd.client, vcd.config.VCD.Org)
+	check.Assert(err, IsNil)
+
+	roles, err := adminOrg.GetAllRoleReferences()
+	check.Assert(err, IsNil)
+	check.Assert(len(roles) > 0, Equals, true)
+
+	_, err = adminOrg.CreateUserSimple(OrgUserConfiguration{Name: TestCreateUser, RoleName: OrgUserRoleVappUser})
+	check.Assert(err, NotNil)
+
+	user, err := adminOrg.CreateUserSimple(OrgUserConfiguration{
+		Name:         TestCreateUser,
+		Password:     "Test-User-Passw0rd",
+		RoleName:     OrgUserRoleVappUser,
+		FullName:     "Test User",
+		EmailAddress: "test.user@example.com",
+		IsEnabled:    true,
+	})
+	check.Assert(err, IsNil)
+	AddToCleanupList(TestCreateUser, "user", vcd.config.VCD.Org, "Test_UserCRUD")
+	check.Assert(user.User.Name, Equals, TestCreateUser)
+	check.Assert(user.User.IsEnabled, Equals, true)
+	check.Assert(user.User.Role, NotNil)
+	check.Assert(user.User.Role.Name, Equals, OrgUserRoleVappUser)
+
+	user, err = adminOrg.GetUserByName(TestCreateUser)
+	check.Assert(err, IsNil)
+
+	err = user.ChangeRole(OrgUserRoleCatalogAuthor)
+	check.Assert(err, IsNil)
+	check.Assert(user.User.Role.Name, Equals, OrgUserRoleCatalogAuthor)
+
+	err = user.Disable()
+	check.Assert(err, IsNil)
+	check.Assert(user.User.IsEnabled, Equals, false)
+	err = user.Enable()
+	check.Assert(err, IsNil)
+	check.Assert(user.User.IsEnabled, Equals, true)
+
+	err = user.ChangePassword("Changed-Passw0rd")
+	check.Assert(err, IsNil)
+	check.Assert(user.User.Password, Equals, "")
+
+	err = user.Delete()
+	check.Assert(err, IsNil)
+	_, err = adminOrg.GetUserByName(TestCreateUser)
+	check.Assert(err, NotNil)
+}
@@ -44,6 +44,10 @@ type VappNetworkSettings struct {
 	GuestVLANAllowed *bool
 	StaticIPRanges   []*types.IPRange
 	DhcpSettings     *DhcpSettings
+	// The following settings are only used by AddRoutedNetwork
+	NatService         *types.NatService // NAT service of the router. vCD translates the IP addresses when nil
+	FirewallEnabled    *bool             // Enables the firewall, allowing the outgoing traffic only. vCD default when nil
+	RetainIpMacEnabled bool              // Keeps the external IP addresses of the router across deployments
 }
 
 // struct type used to pass information for vApp network DHCP
@@ -80,15 +84,16 @@ func (vapp *VApp) Refresh() error {
 	}
 
 	url := vapp.VApp.HREF
-	// Empty struct before a new unmarshal, otherwise we end up with duplicate
-	// elements in slices.
-	vapp.VApp = &types.VApp{}
+	// Unmarshal into an empty struct, otherwise we end up with duplicate elements in
+	// slices, and keep the current one when the request fails.
+	refreshed := &types.VApp{}
 
-	_, err := vapp.client.ExecuteRequest(url, http.MethodGet,
-		"", "error refreshing vApp: %s", nil, vapp.VApp)
-
-	// The request was successful
-	return err
+	err := vapp.client.executeCachedRequest(url, "", "error refreshing vApp: %s", refreshed)
+	if err != nil {
+		return err
+	}
+	vapp.VApp = refreshed
+	return nil
 }
 
 // Function create vm in vApp using vApp template
@@ -97,16 +102,126 @@ func (vapp *VApp) Refresh() error {
 // vappTemplate - vApp Template which will be used for VM creation.
 // name - name for VM.
 // acceptAllEulas - setting allows to automatically accept or not Eulas.
+// See AddVMWithParams for more options.
 func (vapp *VApp) AddVM(orgVdcNetworks []*types.OrgVDCNetwork, vappNetworkName string, vappTemplate VAppTemplate, name string, acceptAllEulas bool) (Task, error) {
+	params := AddVMParams{
+		Name:           name,
+		Template:       vappTemplate,
+		AcceptAllEulas: acceptAllEulas,
+	}
+	for _, orgVdcNetwork := range orgVdcNetworks {
+		params.Networks = append(params.Networks, VMNetworkConnection{Network: orgVdcNetwork.Name})
+	}
+	if vappNetworkName != "" {
+		params.Networks = append(params.Networks, VMNetworkConnection{Network: vappNetworkName})
+	}
+	return vapp.AddVMWithParams(params)
+}
+
+// AddVMParams are the parameters of a VM added to a vApp by AddVMWithParams
+type AddVMParams struct {
+	Name        string       // Name of the VM
+	Description string       // Optional description
+	Template    VAppTemplate // vApp template whose first VM is copied, which must be resolved (status 8)
+
+	// Network connections, in the order of the NICs. The networks must be available in the vApp.
+	Networks []VMNetworkConnection
 
-	if vappTemplate == (VAppTemplate{}) || vappTemplate.VAppTemplate == nil {
-		return Task{}, fmt.Errorf("vApp Template can not be empty")
+	StorageProfile *types.Reference                 // Storage profile of the VM, the VDC default when nil
+	ComputePolicy  *types.ComputePolicy             // Sizing and placement policies, API 33.0+
+	Customization  *types.GuestCustomizationSection // Guest customization, the template one when nil
+	AcceptAllEulas bool
+}
+
+// VMNetworkConnection is a network connection of a VM added with AddVMWithParams
+type VMNetworkConnection struct {
+	Network        string // Name of the vApp or org VDC network
+	AllocationMode string // One of the types.IPAllocationMode* constants, POOL when empty
+	IP             string // IP address, only with MANUAL allocation
+	AdapterType    string // e.g. VMXNET3 or E1000, the template one when empty
+	IsPrimary      bool   // Primary NIC, the first one when none is primary
+	Disconnected   bool   // NIC not connected at deployment
+}
+
+// validIPAllocationModes are the allocation modes accepted by VMNetworkConnection
+var validIPAllocationModes = map[string]bool{
+	types.IPAllocationModeDHCP:   true,
+	types.IPAllocationModeManual: true,
+	types.IPAllocationModeNone:   true,
+	types.IPAllocationModePool:   true,
+}
+
+// AddVMWithParams creates a VM in the vApp, from the first VM of a vApp template, with the given
+// networks, storage profile, compute policy and customization. Returns the recompose task.
+func (vapp *VApp) AddVMWithParams(params AddVMParams) (Task, error) {
+	return vapp.AddVMs([]AddVMParams{params})
+}
+
+// AddVMs creates several VMs in the vApp with a single recompose task, instead of one task per
+// VM, which would be serialized by the lock vCD puts on the vApp. The EULAs are accepted only
+// when all the VMs accept them.
+func (vapp *VApp) AddVMs(vms []AddVMParams) (Task, error) {
+	if len(vms) == 0 {
+		return Task{}, fmt.Errorf("no VM to add to vApp %s", vapp.VApp.Name)
+	}
+	sourcedItems, acceptAllEulas, err := buildSourcedItems(vms)
+	if err != nil {
+		return Task{}, err
 	}
 
-	// Status 8 means The object is resolved and powered off.
-	// https://vdc-repo.vmware.com/vmwb-repository/dcr-public/94b8bd8d-74ff-4fe3-b7a4-41ae31516ed7/1b42f3b5-8b31-4279-8b3f-547f6c7c5aa8/doc/GUID-843BE3AD-5EF6-4442-B864-BCAE44A51867.html
-	if vappTemplate.VAppTemplate.Status != 8 {
-		return Task{}, fmt.Errorf("vApp Template shape is not ok")
+	vcomp := &types.ReComposeVAppParams{
+		Ovf:              types.XMLNamespaceOVF,
+		Xsi:              types.XMLNamespaceXSI,
+		Xmlns:            types.XMLNamespaceVCloud,
+		Deploy:           false,
+		Name:             vapp.VApp.Name,
+		PowerOn:          false,
+		Description:      vapp.VApp.Description,
+		SourcedItem:      sourcedItems,
+		AllEULAsAccepted: acceptAllEulas,
+	}
+
+	apiEndpoint, _ := url.ParseRequestURI(vapp.VApp.HREF)
+	apiEndpoint.Path += "/action/recomposeVApp"
+
+	// Return the task
+	return vapp.client.ExecuteTaskRequest(apiEndpoint.String(), http.MethodPost,
+		types.MimeRecomposeVappParams, "error instantiating a new VM: %s", vcomp)
+}
+
+// CopyVM adds to the vApp a copy of the given VM, which can belong to another vApp, under the
+// given name. The networks of the VM are connected to the vApp networks with the same names, which
+// need to exist in the vApp. Disconnected network adapters are copied as they are. Returns the recompose task.
+func (vapp *VApp) CopyVM(vm VM, name string) (Task, error) {
+	if vm.VM == nil || vm.VM.HREF == "" {
+		return Task{}, fmt.Errorf("VM can not be empty")
+	}
+	if name == "" {
+		return Task{}, fmt.Errorf("VM name can not be empty")
+	}
+
+	sourcedItem := &types.SourcedCompositionItemParam{
+		Source: &types.Reference{
+			HREF: vm.VM.HREF,
+		},
+		VMGeneralParams: &types.VMGeneralParams{
+			Name: name,
+		},
+	}
+	if vm.VM.NetworkConnectionSection != nil {
+		assigned := make(map[string]bool)
+		for _, connection := range vm.VM.NetworkConnectionSection.NetworkConnection {
+			if connection.Network == "" || connection.Network == types.NoneNetwork || assigned[connection.Network] {
+				continue
+			}
+			assigned[connection.Network] = true
+			sourcedItem.NetworkAssignment = append(sourcedItem.NetworkAssignment,
+				&types.NetworkAssignment{
+					InnerNetwork:     connection.Network,
+					ContainerNetwork: connection.Network,
+				},
+			)
+		}
 	}
 
 	vcomp := &types.ReComposeVAppParams{
@@ -117,62 +232,197 @@ func (vapp *VApp) AddVM(orgVdcNetworks []*types.OrgVDCNetwork, vappNetworkName s
 		Name:        vapp.VApp.Name,
 		PowerOn:     false,
 		Description: vapp.VApp.Description,
-		SourcedItem: &types.SourcedCompositionItemParam{
-			Source: &types.Reference{
-				HREF: vappTemplate.VAppTemplate.Children.VM[0].HREF,
-				Name: name,
-			},
-			InstantiationParams: &types.InstantiationParams{
-				NetworkConnectionSection: &types.NetworkConnectionSection{
-					Info:                          "Network config for sourced item",
-					PrimaryNetworkConnectionIndex: 0,
-				},
-			},
-		},
-		AllEULAsAccepted: acceptAllEulas,
+		SourcedItem: []*types.SourcedCompositionItemParam{sourcedItem},
 	}
 
-	for index, orgVdcNetwork := range orgVdcNetworks {
-		vcomp.SourcedItem.InstantiationParams.NetworkConnectionSection.NetworkConnection = append(vcomp.SourcedItem.InstantiationParams.NetworkConnectionSection.NetworkConnection,
+	apiEndpoint, err := url.ParseRequestURI(vapp.VApp.HREF)
+	if err != nil {
+		return Task{}, fmt.Errorf("error getting vApp href: %v", err)
+	}
+	apiEndpoint.Path += "/action/recomposeVApp"
+
+	return vapp.client.ExecuteTaskRequest(apiEndpoint.String(), http.MethodPost,
+		types.MimeRecomposeVappParams, fmt.Sprintf("error copying VM %s: %%s", vm.VM.Name), vcomp)
+}
+
+// buildSourcedItems returns the composition items of the given VMs, and whether all their EULAs are accepted
+func buildSourcedItems(vms []AddVMParams) ([]*types.SourcedCompositionItemParam, bool, error) {
+	sourcedItems := make([]*types.SourcedCompositionItemParam, len(vms))
+	acceptAllEulas := true
+	names := make(map[string]bool)
+	for index, params := range vms {
+		if names[params.Name] {
+			return nil, false, fmt.Errorf("VM name %s is used more than once", params.Name)
+		}
+		names[params.Name] = true
+		sourcedItem, err := params.sourcedItem()
+		if err != nil {
+			return nil, false, err
+		}
+		sourcedItems[index] = sourcedItem
+		acceptAllEulas = acceptAllEulas && params.AcceptAllEulas
+	}
+	return sourcedItems, acceptAllEulas, nil
+}
+
+// sourcedItem validates the parameters and returns the composition item creating the VM
+func (params AddVMParams) sourcedItem() (*types.SourcedCompositionItemParam, error) {
+	if params.Template == (VAppTemplate{}) || params.Template.VAppTemplate == nil {
+		return nil, fmt.Errorf("vApp Template can not be empty")
+	}
+
+	// Status 8 means The object is resolved and powered off.
+	// https://vdc-repo.vmware.com/vmwb-repository/dcr-public/94b8bd8d-74ff-4fe3-b7a4-41ae31516ed7/1b42f3b5-8b31-4279-8b3f-547f6c7c5aa8/doc/GUID-843BE3AD-5EF6-4442-B864-BCAE44A51867.html
+	if params.Template.VAppTemplate.Status != 8 {
+		return nil, fmt.Errorf("vApp Template shape is not ok")
+	}
+	if params.Template.VAppTemplate.Children == nil || len(params.Template.VAppTemplate.Children.VM) == 0 {
+		return nil, fmt.Errorf("vApp Template %s has no VM", params.Template.VAppTemplate.Name)
+	}
+	if params.Name == "" {
+		return nil, fmt.Errorf("VM name can not be empty")
+	}
+
+	networkConnectionSection := &types.NetworkConnectionSection{
+		Info:                          "Network config for sourced item",
+		PrimaryNetworkConnectionIndex: 0,
+	}
+	var networkAssignments []*types.NetworkAssignment
+	for index, connection := range params.Networks {
+		if connection.Network == "" {
+			return nil, fmt.Errorf("network connection %d has no network", index)
+		}
+		allocationMode := connection.AllocationMode
+		if allocationMode == "" {
+			allocationMode = types.IPAllocationModePool
+		}
+		if !validIPAllocationModes[allocationMode] {
+			return nil, fmt.Errorf("network connection %d has an invalid IP allocation mode %s", index, allocationMode)
+		}
+		if (allocationMode == types.IPAllocationModeManual) != (connection.IP != "") {
+			return nil, fmt.Errorf("network connection %d must have an IP address only with the %s allocation mode",
+				index, types.IPAllocationModeManual)
+		}
+		if connection.IsPrimary {
+			networkConnectionSection.PrimaryNetworkConnectionIndex = index
+		}
+		networkConnectionSection.NetworkConnection = append(networkConnectionSection.NetworkConnection,
 			&types.NetworkConnection{
-				Network:                 orgVdcNetwork.Name,
+				Network:                 connection.Network,
 				NetworkConnectionIndex:  index,
-				IsConnected:             true,
-				IPAddressAllocationMode: types.IPAllocationModePool,
+				IsConnected:             !connection.Disconnected,
+				IPAddressAllocationMode: allocationMode,
+				IPAddress:               connection.IP,
+				NetworkAdapterType:      connection.AdapterType,
 			},
 		)
-		vcomp.SourcedItem.NetworkAssignment = append(vcomp.SourcedItem.NetworkAssignment,
+		networkAssignments = append(networkAssignments,
 			&types.NetworkAssignment{
-				InnerNetwork:     orgVdcNetwork.Name,
-				ContainerNetwork: orgVdcNetwork.Name,
+				InnerNetwork:     connection.Network,
+				ContainerNetwork: connection.Network,
 			},
 		)
 	}
 
-	if vappNetworkName != "" {
-		vcomp.SourcedItem.InstantiationParams.NetworkConnectionSection.NetworkConnection = append(vcomp.SourcedItem.InstantiationParams.NetworkConnectionSection.NetworkConnection,
-			&types.NetworkConnection{
-				Network:                 vappNetworkName,
-				NetworkConnectionIndex:  len(orgVdcNetworks),
-				IsConnected:             true,
-				IPAddressAllocationMode: types.IPAllocationModePool,
-			},
-		)
-		vcomp.SourcedItem.NetworkAssignment = append(vcomp.SourcedItem.NetworkAssignment,
-			&types.NetworkAssignment{
-				InnerNetwork:     vappNetworkName,
-				ContainerNetwork: vappNetworkName,
-			},
-		)
+	instantiationParams := &types.InstantiationParams{
+		NetworkConnectionSection: networkConnectionSection,
+	}
+	if params.Customization != nil {
+		customization := *params.Customization
+		customization.Xmlns = types.XMLNamespaceVCloud
+		customization.Ovf = types.XMLNamespaceOVF
+		if customization.Info == "" {
+			customization.Info = "Specifies Guest OS Customization Settings"
+		}
+		instantiationParams.GuestCustomizationSection = &customization
 	}
 
-	apiEndpoint, _ := url.ParseRequestURI(vapp.VApp.HREF)
-	apiEndpoint.Path += "/action/recomposeVApp"
+	sourcedItem := &types.SourcedCompositionItemParam{
+		Source: &types.Reference{
+			HREF: params.Template.VAppTemplate.Children.VM[0].HREF,
+			Name: params.Name,
+		},
+		InstantiationParams: instantiationParams,
+		NetworkAssignment:   networkAssignments,
+		StorageProfile:      params.StorageProfile,
+		ComputePolicy:       params.ComputePolicy,
+	}
+	if params.Description != "" || params.Customization != nil {
+		sourcedItem.VMGeneralParams = &types.VMGeneralParams{
+			Name:               params.Name,
+			Description:        params.Description,
+			NeedsCustomization: params.Customization != nil,
+		}
+	}
+	return sourcedItem, nil
+}
 
-	// Return the task
-	return vapp.client.ExecuteTaskRequest(apiEndpoint.String(), http.MethodPost,
-		types.MimeRecomposeVappParams, "error instantiating a new VM: %s", vcomp)
+// Modes of CaptureVApp, which either keeps the captured vApp or removes it once captured
+const (
+	CaptureVAppCopy = "copy"
+	CaptureVAppMove = "move"
+)
 
+// captureVApp captures the vApp into a vApp template through the captureVApp action at
+// captureHref, which belongs to a VDC or a catalog. The VDC action returns the vApp template
+// being created, while the catalog action returns a task: responseIsTask tells them apart.
+// With CaptureVAppMove, the function waits for the capture and returns the task removing the vApp.
+func captureVApp(client *Client, captureHref string, responseIsTask bool, vapp VApp, name, description, copyOrMove string, customizeOnInstantiate bool) (Task, error) {
+	if vapp.VApp == nil || vapp.VApp.HREF == "" {
+		return Task{}, fmt.Errorf("vApp can not be empty")
+	}
+	if name == "" {
+		return Task{}, fmt.Errorf("vApp template name can not be empty")
+	}
+	if copyOrMove != CaptureVAppCopy && copyOrMove != CaptureVAppMove {
+		return Task{}, fmt.Errorf("invalid capture mode '%s': must be '%s' or '%s'", copyOrMove, CaptureVAppCopy, CaptureVAppMove)
+	}
+
+	captureParams := &types.CaptureVAppParams{
+		Xmlns:       types.XMLNamespaceVCloud,
+		Ovf:         types.XMLNamespaceOVF,
+		Name:        name,
+		Description: description,
+		Source: &types.Reference{
+			HREF: vapp.VApp.HREF,
+		},
+		CustomizationSection: &types.CustomizationSection{
+			Info:                   "VApp template customization section",
+			CustomizeOnInstantiate: customizeOnInstantiate,
+		},
+	}
+
+	errorMessage := fmt.Sprintf("error capturing vApp %s: %%s", vapp.VApp.Name)
+	var task Task
+	var err error
+	if responseIsTask {
+		task, err = client.ExecuteTaskRequest(captureHref, http.MethodPost,
+			types.MimeCaptureVappParams, errorMessage, captureParams)
+		if err != nil {
+			return Task{}, err
+		}
+	} else {
+		vappTemplate := &types.VAppTemplate{}
+		_, err = client.ExecuteRequest(captureHref, http.MethodPost,
+			types.MimeCaptureVappParams, errorMessage, captureParams, vappTemplate)
+		if err != nil {
+			return Task{}, err
+		}
+		if vappTemplate.Tasks == nil || len(vappTemplate.Tasks.Task) == 0 {
+			return Task{}, fmt.Errorf("no capture task found for vApp template %s", name)
+		}
+		task = *NewTask(client)
+		task.Task = vappTemplate.Tasks.Task[0]
+	}
+	if copyOrMove == CaptureVAppCopy {
+		return task, nil
+	}
+
+	err = task.WaitTaskCompletion()
+	if err != nil {
+		return Task{}, fmt.Errorf("error capturing vApp %s: %s", vapp.VApp.Name, err)
+	}
+	return vapp.Delete()
 }
 
 func (vapp *VApp) RemoveVM(vm VM) error {
@@ -329,30 +579,14 @@ func (vapp *VApp) Customize(computername, script string, changeSid bool) (Task,
 	}
 
 	// Check if VApp Children is populated
-	if vapp.VApp.Children == nil {
+	if vapp.VApp.Children == nil || len(vapp.VApp.Children.VM) == 0 {
 		return Task{}, fmt.Errorf("vApp doesn't contain any children, aborting customization")
 	}
 
-	vu := &types.GuestCustomizationSection{
-		Ovf:   types.XMLNamespaceOVF,
-		Xsi:   types.XMLNamespaceXSI,
-		Xmlns: types.XMLNamespaceVCloud,
-
-		HREF:                vapp.VApp.Children.VM[0].HREF,
-		Type:                types.MimeGuestCustomizationSection,
-		Info:                "Specifies Guest OS Customization Settings",
-		Enabled:             true,
-		ComputerName:        computername,
-		CustomizationScript: script,
-		ChangeSid:           false,
-	}
-
-	apiEndpoint, _ := url.ParseRequestURI(vapp.VApp.Children.VM[0].HREF)
-	apiEndpoint.Path += "/guestCustomizationSection/"
-
-	// Return the task
-	return vapp.client.ExecuteTaskRequest(apiEndpoint.String(), http.MethodPut,
-		types.MimeGuestCustomizationSection, "error customizing VM: %s", vu)
+	// Only the first VM is customized: VM.Customize applies to any VM of the vApp
+	vm := NewVM(vapp.client)
+	vm.VM = vapp.VApp.Children.VM[0]
+	return vm.Customize(computername, script, changeSid)
 }
 
 func (vapp *VApp) GetStatus() (string, error) {
@@ -396,8 +630,8 @@ func (vapp *VApp) GetNetworkConnectionSection() (*types.NetworkConnectionSection
 		return networkConnectionSection, fmt.Errorf("cannot refresh, Object is empty")
 	}
 
-	_, err := vapp.client.ExecuteRequest(vapp.VApp.Children.VM[0].HREF+"/networkConnectionSection/", http.MethodGet,
-		types.MimeNetworkConnectionSection, "error retrieving network connection: %s", nil, networkConnectionSection)
+	err := vapp.client.executeCachedSectionRequest(vapp.VApp.Children.VM[0].HREF, "NetworkConnectionSection",
+		types.MimeNetworkConnectionSection, "error retrieving network connection: %s", networkConnectionSection)
 
 	// The request was successful
 	return networkConnectionSection, err
@@ -516,30 +750,23 @@ func (vapp *VApp) GetMetadata() (*types.Metadata, error) {
 	return getMetadata(vapp.client, vapp.VApp.HREF)
 }
 
-func getMetadata(client *Client, requestUri string) (*types.Metadata, error) {
-	metadata := &types.Metadata{}
-
-	_, err := client.ExecuteRequest(requestUri+"/metadata/", http.MethodGet,
-		types.MimeMetaData, "error retrieving metadata: %s", nil, metadata)
-
-	return metadata, err
-}
-
 // DeleteMetadata() function calls private function deleteMetadata() with vapp.client and vapp.VApp.HREF
 // which deletes metadata depending on key provided as input from vApp.
 func (vapp *VApp) DeleteMetadata(key string) (Task, error) {
 	return deleteMetadata(vapp.client, key, vapp.VApp.HREF)
 }
 
-// Deletes metadata (type MetadataStringValue) from the vApp
-// TODO: Support all MetadataTypedValue types with this function
-func deleteMetadata(client *Client, key string, requestUri string) (Task, error) {
-	apiEndpoint, _ := url.ParseRequestURI(requestUri)
-	apiEndpoint.Path += "/metadata/" + key
+// AddMetadataEntry adds or updates a metadata entry of any type (types.Metadata*Value) of the vApp.
+// A nil domain places the entry in the GENERAL domain, while the SYSTEM domain requires system
+// administrator privileges.
+func (vapp *VApp) AddMetadataEntry(key string, value types.TypedValue, domain *types.MetadataDomainTag) (Task, error) {
+	return addMetadataEntry(vapp.client, key, value, domain, vapp.VApp.HREF)
+}
 
-	// Return the task
-	return client.ExecuteTaskRequest(apiEndpoint.String(), http.MethodDelete,
-		"", "error deleting metadata: %s", nil)
+// DeleteMetadataEntry removes a metadata entry of the vApp from the given domain
+// (types.MetadataDomainGeneral or types.MetadataDomainSystem).
+func (vapp *VApp) DeleteMetadataEntry(key, domain string) (Task, error) {
+	return deleteMetadataEntry(vapp.client, key, domain, vapp.VApp.HREF)
 }
 
 // AddMetadata() function calls private function addMetadata() with vapp.client and vapp.VApp.HREF
@@ -548,24 +775,16 @@ func (vapp *VApp) AddMetadata(key string, value string) (Task, error) {
 	return addMetadata(vapp.client, key, value, vapp.VApp.HREF)
 }
 
-// Adds metadata (type MetadataStringValue) to the vApp
-// TODO: Support all MetadataTypedValue types with this function
-func addMetadata(client *Client, key string, value string, requestUri string) (Task, error) {
-	newMetadata := &types.MetadataValue{
-		Xmlns: types.XMLNamespaceVCloud,
-		Xsi:   types.XMLNamespaceXSI,
-		TypedValue: &types.TypedValue{
-			XsiType: "MetadataStringValue",
-			Value:   value,
-		},
-	}
-
-	apiEndpoint, _ := url.ParseRequestURI(requestUri)
-	apiEndpoint.Path += "/metadata/" + key
+// MergeMetadata adds or updates the given metadata entries of the vApp with a single task.
+// Existing keys which are not in the map are left untouched.
+func (vapp *VApp) MergeMetadata(metadata map[string]string) (Task, error) {
+	return mergeMetadata(vapp.client, metadata, vapp.VApp.HREF)
+}
 
-	// Return the task
-	return client.ExecuteTaskRequest(apiEndpoint.String(), http.MethodPut,
-		types.MimeMetaDataValue, "error adding metadata: %s", newMetadata)
+// SetMetadataMap applies the given metadata entries to the vApp with a single task and, when
+// replaceAll is true, removes the entries which are not in the map
+func (vapp *VApp) SetMetadataMap(metadata map[string]types.TypedValue, replaceAll bool) error {
+	return setMetadataMap(vapp.client, metadata, replaceAll, vapp.VApp.HREF)
 }
 
 func (vapp *VApp) SetOvf(parameters map[string]string) (Task, error) {
@@ -710,8 +929,8 @@ func (vapp *VApp) GetNetworkConfig() (*types.NetworkConfigSection, error) {
 		return networkConfig, fmt.Errorf("cannot refresh, Object is empty")
 	}
 
-	_, err := vapp.client.ExecuteRequest(vapp.VApp.HREF+"/networkConfigSection/", http.MethodGet,
-		types.MimeNetworkConfigSection, "error retrieving network config: %s", nil, networkConfig)
+	err := vapp.client.executeCachedSectionRequest(vapp.VApp.HREF, "NetworkConfigSection",
+		types.MimeNetworkConfigSection, "error retrieving network config: %s", networkConfig)
 
 	// The request was successful
 	return networkConfig, err
@@ -827,6 +1046,167 @@ func (vapp *VApp) AddIsolatedNetwork(newIsolatedNetworkSettings *VappNetworkSett
 
 }
 
+// AddRoutedNetwork creates a vApp network connected to the Org VDC network orgNetwork through a
+// router, with the natRouted fence mode. Besides the settings of isolated networks, it uses the
+// NAT service, firewall and retain IP settings.
+func (vapp *VApp) AddRoutedNetwork(networkSettings *VappNetworkSettings, orgNetwork *types.OrgVDCNetwork) (Task, error) {
+	err := validateNetworkConfigSettings(networkSettings)
+	if err != nil {
+		return Task{}, err
+	}
+	if orgNetwork == nil || orgNetwork.HREF == "" {
+		return Task{}, errors.New("parent network of routed network is missing")
+	}
+
+	if networkSettings.DhcpSettings != nil && networkSettings.DhcpSettings.IPRange.EndAddress == "" {
+		networkSettings.DhcpSettings.IPRange.EndAddress = networkSettings.DhcpSettings.IPRange.StartAddress
+	}
+
+	networkFeatures := &types.NetworkFeatures{NatService: networkSettings.NatService}
+	if networkSettings.DhcpSettings != nil {
+		networkFeatures.DhcpService = newVappNetworkDhcpService(networkSettings.DhcpSettings)
+	}
+	if networkSettings.FirewallEnabled != nil {
+		networkFeatures.FirewallService = newVappNetworkFirewallService(*networkSettings.FirewallEnabled)
+	}
+	if networkFeatures.DhcpService == nil && networkFeatures.FirewallService == nil && networkFeatures.NatService == nil {
+		networkFeatures = nil
+	}
+
+	networkConfigurations := vapp.VApp.NetworkConfigSection.NetworkConfig
+	networkConfigurations = append(networkConfigurations,
+		types.VAppNetworkConfiguration{
+			NetworkName: networkSettings.Name,
+			Configuration: &types.NetworkConfiguration{
+				ParentNetwork:                  &types.Reference{HREF: orgNetwork.HREF, Name: orgNetwork.Name},
+				FenceMode:                      types.FenceModeNAT,
+				RetainNetInfoAcrossDeployments: networkSettings.RetainIpMacEnabled,
+				GuestVlanAllowed:               networkSettings.GuestVLANAllowed,
+				Features:                       networkFeatures,
+				IPScopes: &types.IPScopes{IPScope: types.IPScope{IsInherited: false, Gateway: networkSettings.Gateway,
+					Netmask: networkSettings.NetMask, DNS1: networkSettings.DNS1,
+					DNS2: networkSettings.DNS2, DNSSuffix: networkSettings.DNSSuffix, IsEnabled: true,
+					IPRanges: &types.IPRanges{IPRange: networkSettings.StaticIPRanges}}},
+			},
+			IsDeployed: false,
+		})
+
+	return updateNetworkConfigurations(vapp, networkConfigurations)
+}
+
+// UpdateNetwork changes in place the IP scope, static IP ranges and DHCP service of the vApp network
+// networkName, without disconnecting the VMs. The name of the network changes when networkSettings
+// has a different one. The DHCP service is left unchanged when DhcpSettings is nil, and disabled when
+// DhcpSettings.IsEnabled is false. For routed networks, the NAT service and the firewall are changed
+// as well when they are set, and the retain IP setting always is.
+func (vapp *VApp) UpdateNetwork(networkName string, networkSettings *VappNetworkSettings) (Task, error) {
+	if networkSettings == nil {
+		return Task{}, errors.New("network settings are missing")
+	}
+	settings := *networkSettings
+	if settings.Name == "" {
+		settings.Name = networkName
+	}
+	err := validateNetworkConfigSettings(&settings)
+	if err != nil {
+		return Task{}, err
+	}
+	if settings.DhcpSettings != nil && settings.DhcpSettings.IPRange.EndAddress == "" {
+		dhcpSettings := *settings.DhcpSettings
+		dhcpSettings.IPRange = &types.IPRange{StartAddress: dhcpSettings.IPRange.StartAddress, EndAddress: dhcpSettings.IPRange.StartAddress}
+		settings.DhcpSettings = &dhcpSettings
+	}
+
+	networkConfigSection, err := vapp.GetNetworkConfig()
+	if err != nil {
+		return Task{}, fmt.Errorf("error getting vApp networks: %s", err)
+	}
+	networkConfigurations := networkConfigSection.NetworkConfig
+	index := -1
+	for i, networkConfig := range networkConfigurations {
+		if networkConfig.NetworkName == networkName {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return Task{}, fmt.Errorf("network to update %s, wasn't found", networkName)
+	}
+
+	networkConfig := &networkConfigurations[index]
+	if networkConfig.Configuration == nil || networkConfig.Configuration.FenceMode == types.FenceModeBridged {
+		return Task{}, fmt.Errorf("network %s is connected directly to its parent network and has no IP scope to update", networkName)
+	}
+	configuration := networkConfig.Configuration
+	networkConfig.NetworkName = settings.Name
+
+	var ipScope types.IPScope
+	if configuration.IPScopes != nil {
+		ipScope = configuration.IPScopes.IPScope
+	}
+	ipScope.Gateway = settings.Gateway
+	ipScope.Netmask = settings.NetMask
+	ipScope.SubnetPrefixLength = 0
+	ipScope.DNS1 = settings.DNS1
+	ipScope.DNS2 = settings.DNS2
+	ipScope.DNSSuffix = settings.DNSSuffix
+	ipScope.IsEnabled = true
+	ipScope.IPRanges = &types.IPRanges{IPRange: settings.StaticIPRanges}
+	configuration.IPScopes = &types.IPScopes{IPScope: ipScope}
+	if settings.GuestVLANAllowed != nil {
+		configuration.GuestVlanAllowed = settings.GuestVLANAllowed
+	}
+
+	if configuration.Features == nil {
+		configuration.Features = &types.NetworkFeatures{}
+	}
+	if settings.DhcpSettings != nil {
+		configuration.Features.DhcpService = newVappNetworkDhcpService(settings.DhcpSettings)
+	}
+	if configuration.FenceMode == types.FenceModeNAT {
+		if settings.NatService != nil {
+			configuration.Features.NatService = settings.NatService
+		}
+		if settings.FirewallEnabled != nil {
+			configuration.Features.FirewallService = newVappNetworkFirewallService(*settings.FirewallEnabled)
+		}
+		configuration.RetainNetInfoAcrossDeployments = settings.RetainIpMacEnabled
+	}
+
+	return updateNetworkConfigurations(vapp, networkConfigurations)
+}
+
+// newVappNetworkDhcpService returns the DHCP service of a vApp network
+func newVappNetworkDhcpService(dhcpSettings *DhcpSettings) *types.DhcpService {
+	return &types.DhcpService{
+		IsEnabled:        dhcpSettings.IsEnabled,
+		DefaultLeaseTime: dhcpSettings.DefaultLeaseTime,
+		MaxLeaseTime:     dhcpSettings.MaxLeaseTime,
+		IPRange:          dhcpSettings.IPRange}
+}
+
+// newVappNetworkFirewallService returns the firewall service of a routed vApp network. When enabled,
+// the firewall only allows the outgoing traffic, as vCD does for new networks.
+func newVappNetworkFirewallService(enabled bool) *types.FirewallService {
+	firewallService := &types.FirewallService{
+		IsEnabled:     enabled,
+		DefaultAction: "drop",
+	}
+	if enabled {
+		firewallService.FirewallRule = []*types.FirewallRule{{
+			IsEnabled:            true,
+			Description:          "Allow all outgoing traffic",
+			Policy:               "allow",
+			Protocols:            &types.FirewallRuleProtocols{Any: true},
+			DestinationPortRange: "Any",
+			DestinationIP:        "external",
+			SourcePortRange:      "Any",
+			SourceIP:             "internal",
+		}}
+	}
+	return firewallService
+}
+
 func validateNetworkConfigSettings(networkSettings *VappNetworkSettings) error {
 	if networkSettings.Name == "" {
 		return errors.New("network name is missing")
@@ -0,0 +1,439 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/util"
+)
+
+// VAppSpec is the desired state of a vApp, reconciled with VApp.Apply. Fields left empty (nil
+// slices and maps, zero sizes) are not managed: the live values are kept as they are.
+type VAppSpec struct {
+	// Names of the org VDC networks attached to the vApp. Missing networks are attached, extra
+	// networks are kept, as VMs may use them.
+	Networks []string
+	// VMs of the vApp, identified by name. Missing VMs are created from their template.
+	VMs []VMSpec
+	// When true, the VMs of the vApp which are not in VMs are removed
+	RemoveUnlistedVMs bool
+	// String metadata of the vApp. When not nil, it replaces all the GENERAL domain entries.
+	Metadata map[string]string
+}
+
+// VMSpec is the desired state of a VM of a vApp
+type VMSpec struct {
+	Name string
+	// Template used to create the VM when it does not exist. Its first VM is used.
+	Template *VAppTemplate
+	// Number of virtual CPUs, with optional cores per socket
+	CPUs           int
+	CoresPerSocket *int
+	MemoryMB       int
+	// NICs of the VM, in the order of their index
+	Networks []VMNetworkSpec
+	// HREFs of the independent disks attached to the VM. Missing ones are attached, extra ones
+	// are detached.
+	IndependentDisks []string
+	// String metadata of the VM. When not nil, it replaces all the GENERAL domain entries.
+	Metadata map[string]string
+}
+
+// VMNetworkSpec is the desired state of a NIC
+type VMNetworkSpec struct {
+	Network          string // Name of the vApp network, or types.NoneNetwork
+	IPAllocationMode string // One of the types.IPAllocationMode* constants, POOL when empty
+	IP               string // IP address, only for types.IPAllocationModeManual
+}
+
+// ApplyAction is an operation planned by VApp.PlanApply to reconcile a vApp with its spec
+type ApplyAction struct {
+	Description string // e.g. "change memory of VM web to 2048 MB"
+	run         func() (Task, error)
+}
+
+// maxApplyRounds bounds the number of plan and execution rounds run by VApp.Apply. A second
+// round sets the VMs created by the first one.
+const maxApplyRounds = 3
+
+// Apply reconciles the vApp with the desired spec, running the minimal set of operations: only
+// the differences between the spec and the live vApp are changed. It can be called repeatedly,
+// and does nothing when the vApp already matches the spec. The executed actions are returned,
+// including on error.
+// Some changes, such as memory and CPU changes without hot add, need the VMs to be powered off.
+func (vapp *VApp) Apply(spec VAppSpec) ([]ApplyAction, error) {
+	var executed []ApplyAction
+	for round := 0; round < maxApplyRounds; round++ {
+		actions, err := vapp.PlanApply(spec)
+		if err != nil {
+			return executed, err
+		}
+		if len(actions) == 0 {
+			return executed, nil
+		}
+		for _, action := range actions {
+			util.Logger.Printf("[TRACE] VApp.Apply - %s: %s", vapp.VApp.Name, action.Description)
+			task, err := action.run()
+			if err == nil && task.Task != nil {
+				err = task.WaitTaskCompletion()
+			}
+			if err != nil {
+				return executed, fmt.Errorf("error applying spec to vApp %s, could not %s: %s", vapp.VApp.Name, action.Description, err)
+			}
+			executed = append(executed, action)
+		}
+	}
+	return executed, fmt.Errorf("vApp %s does not match its spec after %d rounds", vapp.VApp.Name, maxApplyRounds)
+}
+
+// PlanApply returns the actions needed to reconcile the vApp with the desired spec, without
+// running them. The VMs which do not exist yet are planned for creation only: their settings are
+// planned once they exist.
+func (vapp *VApp) PlanApply(spec VAppSpec) ([]ApplyAction, error) {
+	err := vapp.Refresh()
+	if err != nil {
+		return nil, fmt.Errorf("error refreshing vApp: %s", err)
+	}
+
+	var actions []ApplyAction
+	networkActions, err := vapp.planNetworks(spec.Networks)
+	if err != nil {
+		return nil, err
+	}
+	actions = append(actions, networkActions...)
+
+	liveVms := map[string]*types.VM{}
+	if vapp.VApp.Children != nil {
+		for _, vm := range vapp.VApp.Children.VM {
+			liveVms[vm.Name] = vm
+		}
+	}
+	specVms := map[string]bool{}
+	for _, vmSpec := range spec.VMs {
+		if vmSpec.Name == "" {
+			return nil, fmt.Errorf("VM name is required in spec of vApp %s", vapp.VApp.Name)
+		}
+		specVms[vmSpec.Name] = true
+
+		liveVm, found := liveVms[vmSpec.Name]
+		if !found {
+			action, err := vapp.planVmCreation(vmSpec)
+			if err != nil {
+				return nil, err
+			}
+			actions = append(actions, action)
+			continue
+		}
+		vm := NewVM(vapp.client)
+		vm.VM = liveVm
+		vmActions, err := vm.planApply(vmSpec)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, vmActions...)
+	}
+
+	if spec.RemoveUnlistedVMs {
+		var names []string
+		for name := range liveVms {
+			if !specVms[name] {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			vm := NewVM(vapp.client)
+			vm.VM = liveVms[name]
+			actions = append(actions, ApplyAction{
+				Description: fmt.Sprintf("remove VM %s", name),
+				run: func() (Task, error) {
+					return Task{}, vapp.RemoveVM(*vm)
+				},
+			})
+		}
+	}
+
+	if spec.Metadata != nil {
+		action, err := planMetadata(vapp.client, vapp.VApp.HREF, "vApp "+vapp.VApp.Name, spec.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		if action != nil {
+			actions = append(actions, *action)
+		}
+	}
+	return actions, nil
+}
+
+// planNetworks plans the attachment of the org VDC networks missing from the vApp
+func (vapp *VApp) planNetworks(networkNames []string) ([]ApplyAction, error) {
+	attached := map[string]bool{}
+	if vapp.VApp.NetworkConfigSection != nil {
+		for _, networkConfig := range vapp.VApp.NetworkConfigSection.NetworkConfig {
+			attached[networkConfig.NetworkName] = true
+		}
+	}
+	var missing []string
+	for _, name := range networkNames {
+		if !attached[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		return nil, nil
+	}
+
+	vdc, err := vapp.getParentVDC()
+	if err != nil {
+		return nil, err
+	}
+	var networks []*types.OrgVDCNetwork
+	for _, name := range missing {
+		network, err := vdc.FindVDCNetwork(name)
+		if err != nil {
+			return nil, err
+		}
+		networks = append(networks, network.OrgVDCNetwork)
+	}
+	return []ApplyAction{{
+		Description: fmt.Sprintf("attach networks %v", missing),
+		run: func() (Task, error) {
+			return vapp.AddRAWNetworkConfig(networks)
+		},
+	}}, nil
+}
+
+// planVmCreation plans the creation of a VM from its template
+func (vapp *VApp) planVmCreation(vmSpec VMSpec) (ApplyAction, error) {
+	if vmSpec.Template == nil || vmSpec.Template.VAppTemplate == nil {
+		return ApplyAction{}, fmt.Errorf("VM %s does not exist and has no template", vmSpec.Name)
+	}
+	return ApplyAction{
+		Description: fmt.Sprintf("create VM %s", vmSpec.Name),
+		run: func() (Task, error) {
+			return vapp.AddVM(nil, "", *vmSpec.Template, vmSpec.Name, true)
+		},
+	}, nil
+}
+
+// planApply plans the changes of an existing VM
+func (vm *VM) planApply(vmSpec VMSpec) ([]ApplyAction, error) {
+	var actions []ApplyAction
+	cpus, coresPerSocket, memory := 0, 0, 0
+	attachedDisks := map[string]bool{}
+	if vm.VM.VirtualHardwareSection != nil {
+		for _, item := range vm.VM.VirtualHardwareSection.Item {
+			switch item.ResourceType {
+			case types.ResourceTypeProcessor:
+				cpus, coresPerSocket = item.VirtualQuantity, item.CoresPerSocket
+			case types.ResourceTypeMemory:
+				memory = item.VirtualQuantity
+			case types.ResourceTypeDisk:
+				for _, hostResource := range item.HostResource {
+					if hostResource.Disk != "" {
+						attachedDisks[hostResource.Disk] = true
+					}
+				}
+			}
+		}
+	}
+
+	if (vmSpec.CPUs > 0 && vmSpec.CPUs != cpus) || (vmSpec.CoresPerSocket != nil && *vmSpec.CoresPerSocket != coresPerSocket) {
+		newCpus := vmSpec.CPUs
+		if newCpus == 0 {
+			newCpus = cpus
+		}
+		actions = append(actions, ApplyAction{
+			Description: fmt.Sprintf("change CPUs of VM %s to %d", vm.VM.Name, newCpus),
+			run: func() (Task, error) {
+				return vm.ChangeCPUCountWithCore(newCpus, vmSpec.CoresPerSocket)
+			},
+		})
+	}
+	if vmSpec.MemoryMB > 0 && vmSpec.MemoryMB != memory {
+		actions = append(actions, ApplyAction{
+			Description: fmt.Sprintf("change memory of VM %s to %d MB", vm.VM.Name, vmSpec.MemoryMB),
+			run: func() (Task, error) {
+				return vm.ChangeMemorySize(vmSpec.MemoryMB)
+			},
+		})
+	}
+
+	if vmSpec.Networks != nil && !vmNetworksMatch(vm.VM.NetworkConnectionSection, vmSpec.Networks) {
+		actions = append(actions, ApplyAction{
+			Description: fmt.Sprintf("change networks of VM %s", vm.VM.Name),
+			run: func() (Task, error) {
+				return vm.setNetworkConnections(vmSpec.Networks)
+			},
+		})
+	}
+
+	if vmSpec.IndependentDisks != nil {
+		specDisks := map[string]bool{}
+		for _, diskHref := range vmSpec.IndependentDisks {
+			specDisks[diskHref] = true
+			if !attachedDisks[diskHref] {
+				diskHref := diskHref
+				actions = append(actions, ApplyAction{
+					Description: fmt.Sprintf("attach disk %s to VM %s", diskHref, vm.VM.Name),
+					run: func() (Task, error) {
+						return vm.AttachDisk(&types.DiskAttachOrDetachParams{Disk: &types.Reference{HREF: diskHref}})
+					},
+				})
+			}
+		}
+		var extraDisks []string
+		for diskHref := range attachedDisks {
+			if !specDisks[diskHref] {
+				extraDisks = append(extraDisks, diskHref)
+			}
+		}
+		sort.Strings(extraDisks)
+		for _, diskHref := range extraDisks {
+			diskHref := diskHref
+			actions = append(actions, ApplyAction{
+				Description: fmt.Sprintf("detach disk %s from VM %s", diskHref, vm.VM.Name),
+				run: func() (Task, error) {
+					return vm.DetachDisk(&types.DiskAttachOrDetachParams{Disk: &types.Reference{HREF: diskHref}})
+				},
+			})
+		}
+	}
+
+	if vmSpec.Metadata != nil {
+		action, err := planMetadata(vm.client, vm.VM.HREF, "VM "+vm.VM.Name, vmSpec.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		if action != nil {
+			actions = append(actions, *action)
+		}
+	}
+	return actions, nil
+}
+
+// vmNetworksMatch returns true when the NICs of the VM match the spec
+func vmNetworksMatch(section *types.NetworkConnectionSection, networks []VMNetworkSpec) bool {
+	if section == nil {
+		return len(networks) == 0
+	}
+	if len(section.NetworkConnection) != len(networks) {
+		return false
+	}
+	for _, connection := range section.NetworkConnection {
+		index := connection.NetworkConnectionIndex
+		if index < 0 || index >= len(networks) {
+			return false
+		}
+		network := networks[index]
+		if connection.Network != network.Network || connection.IPAddressAllocationMode != ipAllocationMode(network) {
+			return false
+		}
+		if network.IPAllocationMode == types.IPAllocationModeManual && connection.IPAddress != network.IP {
+			return false
+		}
+	}
+	return true
+}
+
+// setNetworkConnections replaces the NICs of the VM with the ones of the spec, keeping the MAC
+// addresses of the existing NICs. The first NIC is the primary one.
+func (vm *VM) setNetworkConnections(networks []VMNetworkSpec) (Task, error) {
+	current, err := vm.GetNetworkConnectionSection()
+	if err != nil {
+		return Task{}, err
+	}
+	macAddresses := map[int]string{}
+	for _, connection := range current.NetworkConnection {
+		macAddresses[connection.NetworkConnectionIndex] = connection.MACAddress
+	}
+
+	networkSection := &types.NetworkConnectionSection{
+		Xmlns:                         types.XMLNamespaceVCloud,
+		Ovf:                           types.XMLNamespaceOVF,
+		Info:                          "Specifies the available VM network connections",
+		PrimaryNetworkConnectionIndex: 0,
+	}
+	for index, network := range networks {
+		connection := &types.NetworkConnection{
+			Network:                 network.Network,
+			NetworkConnectionIndex:  index,
+			IsConnected:             network.Network != types.NoneNetwork,
+			IPAddressAllocationMode: ipAllocationMode(network),
+			MACAddress:              macAddresses[index],
+			NeedsCustomization:      true,
+		}
+		if connection.IPAddressAllocationMode == types.IPAllocationModeManual {
+			connection.IPAddress = network.IP
+		}
+		networkSection.NetworkConnection = append(networkSection.NetworkConnection, connection)
+	}
+
+	apiEndpoint, _ := url.ParseRequestURI(vm.VM.HREF)
+	apiEndpoint.Path += "/networkConnectionSection/"
+
+	return vm.client.ExecuteTaskRequest(apiEndpoint.String(), http.MethodPut,
+		types.MimeNetworkConnectionSection, "error changing network config: %s", networkSection)
+}
+
+// ipAllocationMode returns the IP allocation mode of a NIC spec, POOL by default
+func ipAllocationMode(network VMNetworkSpec) string {
+	if network.IPAllocationMode == "" {
+		return types.IPAllocationModePool
+	}
+	return network.IPAllocationMode
+}
+
+// planMetadata plans the update of the string metadata of an entity, or returns nil when the
+// metadata already match
+func planMetadata(client *Client, href, entityName string, metadata map[string]string) (*ApplyAction, error) {
+	current, err := getMetadata(client, href)
+	if err != nil {
+		return nil, err
+	}
+	currentValues := map[string]string{}
+	hasExtraKeys := false
+	for _, entry := range current.MetadataEntry {
+		if entry.IsSystem() {
+			continue
+		}
+		if entry.TypedValue != nil {
+			currentValues[entry.Key] = entry.TypedValue.Value
+		}
+		if _, found := metadata[entry.Key]; !found {
+			hasExtraKeys = true
+		}
+	}
+
+	changed := map[string]types.TypedValue{}
+	for key, value := range metadata {
+		if currentValue, found := currentValues[key]; !found || currentValue != value {
+			changed[key] = types.TypedValue{XsiType: types.MetadataStringValue, Value: value}
+		}
+	}
+	if len(changed) == 0 && !hasExtraKeys {
+		return nil, nil
+	}
+
+	toSet := changed
+	if hasExtraKeys {
+		// Replacing all the entries needs all of them, not only the changed ones
+		toSet = map[string]types.TypedValue{}
+		for key, value := range metadata {
+			toSet[key] = types.TypedValue{XsiType: types.MetadataStringValue, Value: value}
+		}
+	}
+	return &ApplyAction{
+		Description: fmt.Sprintf("update metadata of %s", entityName),
+		run: func() (Task, error) {
+			return Task{}, setMetadataMap(client, toSet, hasExtraKeys, href)
+		},
+	}, nil
+}
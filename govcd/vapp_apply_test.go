@@ -0,0 +1,122 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+const (
+	applyVAppPath = "/api/vApp/vapp-66666666-6666-6666-6666-666666666666"
+	applyVmPath   = "/api/vApp/vm-77777777-7777-7777-7777-777777777777"
+)
+
+const applyVAppXml = `<?xml version="1.0" encoding="UTF-8"?>
+<VApp xmlns="http://www.vmware.com/vcloud/v1.5" xmlns:ovf="http://schemas.dmtf.org/ovf/envelope/1" xmlns:rasd="http://schemas.dmtf.org/wbem/wscim/1/cim-schema/2/CIM_ResourceAllocationSettingData" status="8" name="app" type="application/vnd.vmware.vcloud.vApp+xml" href="{{server}}` + applyVAppPath + `">
+  <NetworkConfigSection>
+    <NetworkConfig networkName="net1"><IsDeployed>false</IsDeployed></NetworkConfig>
+  </NetworkConfigSection>
+  <Children>
+    <Vm status="8" name="web" href="{{server}}` + applyVmPath + `">
+      <ovf:VirtualHardwareSection>
+        <ovf:Item><rasd:ResourceType>3</rasd:ResourceType><rasd:VirtualQuantity>2</rasd:VirtualQuantity></ovf:Item>
+        <ovf:Item><rasd:ResourceType>4</rasd:ResourceType><rasd:VirtualQuantity>1024</rasd:VirtualQuantity></ovf:Item>
+      </ovf:VirtualHardwareSection>
+      <NetworkConnectionSection>
+        <PrimaryNetworkConnectionIndex>0</PrimaryNetworkConnectionIndex>
+        <NetworkConnection network="net1">
+          <NetworkConnectionIndex>0</NetworkConnectionIndex>
+          <IsConnected>true</IsConnected>
+          <IpAddressAllocationMode>POOL</IpAddressAllocationMode>
+        </NetworkConnection>
+      </NetworkConnectionSection>
+    </Vm>
+  </Children>
+</VApp>`
+
+const applyMetadataXml = `<?xml version="1.0" encoding="UTF-8"?>
+<Metadata xmlns="http://www.vmware.com/vcloud/v1.5" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+  <MetadataEntry><Key>owner</Key><TypedValue xsi:type="MetadataStringValue"><Value>team1</Value></TypedValue></MetadataEntry>
+</Metadata>`
+
+// Checks that PlanApply only plans the differences between the spec and the live vApp
+func TestVApp_PlanApply(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	server.HandleXML(http.MethodGet, applyVAppPath, http.StatusOK, applyVAppXml)
+	server.HandleXML(http.MethodGet, applyVAppPath+"/metadata/", http.StatusOK, applyMetadataXml)
+	server.HandleXML(http.MethodGet, applyVmPath+"/metadata/", http.StatusOK, applyMetadataXml)
+
+	vcdClient := newMockClient(t, server)
+	vapp := NewVApp(&vcdClient.Client)
+	vapp.VApp.HREF = server.URL() + applyVAppPath
+
+	matching := VAppSpec{
+		Networks: []string{"net1"},
+		VMs: []VMSpec{{
+			Name:     "web",
+			CPUs:     2,
+			MemoryMB: 1024,
+			Networks: []VMNetworkSpec{{Network: "net1"}},
+			Metadata: map[string]string{"owner": "team1"},
+		}},
+		Metadata: map[string]string{"owner": "team1"},
+	}
+	actions, err := vapp.PlanApply(matching)
+	if err != nil {
+		t.Fatalf("error planning: %s", err)
+	}
+	if len(actions) != 0 {
+		t.Errorf("expected no action for a matching spec, got %v", descriptions(actions))
+	}
+	executed, err := vapp.Apply(matching)
+	if err != nil || len(executed) != 0 {
+		t.Errorf("expected nothing applied for a matching spec, got %v and %v", descriptions(executed), err)
+	}
+
+	changed := matching
+	changed.VMs = []VMSpec{{
+		Name:     "web",
+		MemoryMB: 2048,
+		Networks: []VMNetworkSpec{{Network: "net1", IPAllocationMode: types.IPAllocationModeManual, IP: "192.168.1.10"}},
+	}}
+	changed.Metadata = map[string]string{"owner": "team2"}
+	changed.RemoveUnlistedVMs = true
+	actions, err = vapp.PlanApply(changed)
+	if err != nil {
+		t.Fatalf("error planning: %s", err)
+	}
+	expected := []string{
+		"change memory of VM web to 2048 MB",
+		"change networks of VM web",
+		"update metadata of vApp app",
+	}
+	if got := descriptions(actions); len(got) != len(expected) {
+		t.Fatalf("expected actions %v, got %v", expected, got)
+	} else {
+		for i := range expected {
+			if got[i] != expected[i] {
+				t.Errorf("expected action %d '%s', got '%s'", i, expected[i], got[i])
+			}
+		}
+	}
+
+	_, err = vapp.PlanApply(VAppSpec{VMs: []VMSpec{{Name: "db"}}})
+	if err == nil {
+		t.Errorf("expected error for a missing VM without template")
+	}
+}
+
+func descriptions(actions []ApplyAction) []string {
+	var result []string
+	for _, action := range actions {
+		result = append(result, action.Description)
+	}
+	return result
+}
@@ -6,9 +6,14 @@ package govcd
 
 import (
 	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
 	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
 	. "gopkg.in/check.v1"
-	"regexp"
 )
 
 // Tests the helper function getParentVDC with the vapp
@@ -519,3 +524,499 @@ func (vcd *TestVCD) Test_AddAndRemoveIsolatedNetwork(check *C) {
 	}
 	check.Assert(isExist, Equals, false)
 }
+
+// Checks the recompose payload built from typed VM parameters, and their validation
+func TestVApp_AddVMWithParams(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	vappPath := "/api/vApp/vapp-1"
+	server.HandleXML(http.MethodPost, vappPath+"/action/recomposeVApp", http.StatusAccepted,
+		`<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="running" href="{{server}}`+vcdtest.MockTaskPath+`"/>`)
+
+	vcdClient := newMockClient(t, server)
+	vcdClient.Client.APIVersion = "33.0"
+	vapp := NewVApp(&vcdClient.Client)
+	vapp.VApp.Name = "app"
+	vapp.VApp.HREF = server.URL() + vappPath
+	template := VAppTemplate{VAppTemplate: &types.VAppTemplate{Name: "template", Status: 8,
+		Children: &types.VAppTemplateChildren{VM: []*types.VAppTemplate{{HREF: server.URL() + "/api/vAppTemplate/vm-1"}}}}}
+
+	params := AddVMParams{
+		Name:     "web",
+		Template: template,
+		Networks: []VMNetworkConnection{
+			{Network: "net1"},
+			{Network: "net2", AllocationMode: types.IPAllocationModeManual, IP: "192.168.2.10", AdapterType: "VMXNET3", IsPrimary: true},
+		},
+		StorageProfile: &types.Reference{HREF: server.URL() + "/api/vdcStorageProfile/1", Name: "gold"},
+		ComputePolicy:  &types.ComputePolicy{VmSizingPolicy: &types.Reference{HREF: server.URL() + "/cloudapi/policy/1"}},
+		Customization:  &types.GuestCustomizationSection{Enabled: true, ComputerName: "web"},
+	}
+	_, err := vapp.AddVMWithParams(params)
+	if err != nil {
+		t.Fatalf("error adding VM: %s", err)
+	}
+	requests := server.RequestsTo(http.MethodPost, vappPath+"/action/recomposeVApp")
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 recompose request, got %d", len(requests))
+	}
+	for _, expected := range []string{
+		"<PrimaryNetworkConnectionIndex>1</PrimaryNetworkConnectionIndex>",
+		"<IpAddress>192.168.2.10</IpAddress>",
+		"<IpAddressAllocationMode>MANUAL</IpAddressAllocationMode>",
+		"<NetworkAdapterType>VMXNET3</NetworkAdapterType>",
+		"<StorageProfile",
+		"<VmSizingPolicy",
+		"<NeedsCustomization>true</NeedsCustomization>",
+		"<ComputerName>web</ComputerName>",
+	} {
+		if !strings.Contains(requests[0].Body, expected) {
+			t.Errorf("expected %s in recompose payload:\n%s", expected, requests[0].Body)
+		}
+	}
+
+	for _, invalid := range []VMNetworkConnection{
+		{Network: "net1", AllocationMode: "STATIC"},
+		{Network: "net1", AllocationMode: types.IPAllocationModeManual},
+		{Network: "net1", IP: "192.168.1.10"},
+		{},
+	} {
+		params.Networks = []VMNetworkConnection{invalid}
+		_, err = vapp.AddVMWithParams(params)
+		if err == nil {
+			t.Errorf("expected error for network connection %+v", invalid)
+		}
+	}
+}
+
+// Checks that several VMs are added, and a vApp is composed with several VMs, with a single request
+func TestVApp_AddVMs(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	vappPath := "/api/vApp/vapp-1"
+	vdcPath := "/api/vdc/vdc-1"
+	task := `<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="running" href="{{server}}` + vcdtest.MockTaskPath + `"/>`
+	server.HandleXML(http.MethodPost, vappPath+"/action/recomposeVApp", http.StatusAccepted, task)
+	server.HandleXML(http.MethodPost, vdcPath+"/action/composeVApp", http.StatusAccepted, task)
+
+	vcdClient := newMockClient(t, server)
+	vapp := NewVApp(&vcdClient.Client)
+	vapp.VApp.Name = "app"
+	vapp.VApp.HREF = server.URL() + vappPath
+	template := VAppTemplate{VAppTemplate: &types.VAppTemplate{Name: "template", Status: 8,
+		Children: &types.VAppTemplateChildren{VM: []*types.VAppTemplate{{HREF: server.URL() + "/api/vAppTemplate/vm-1"}}}}}
+
+	var vms []AddVMParams
+	for i := 1; i <= 20; i++ {
+		vms = append(vms, AddVMParams{
+			Name:           fmt.Sprintf("web%02d", i),
+			Template:       template,
+			Networks:       []VMNetworkConnection{{Network: "net1"}},
+			AcceptAllEulas: true,
+		})
+	}
+	_, err := vapp.AddVMs(vms)
+	if err != nil {
+		t.Fatalf("error adding VMs: %s", err)
+	}
+	requests := server.RequestsTo(http.MethodPost, vappPath+"/action/recomposeVApp")
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 recompose request, got %d", len(requests))
+	}
+	if count := strings.Count(requests[0].Body, "<SourcedItem>"); count != 20 {
+		t.Errorf("expected 20 sourced items, got %d", count)
+	}
+	if !strings.Contains(requests[0].Body, "<AllEULAsAccepted>true</AllEULAsAccepted>") {
+		t.Errorf("expected EULAs to be accepted:\n%s", requests[0].Body)
+	}
+
+	vdc := NewVdc(&vcdClient.Client)
+	vdc.Vdc.HREF = server.URL() + vdcPath
+	network := &types.OrgVDCNetwork{Name: "net1", HREF: server.URL() + "/api/network/net-1"}
+	vms[1].AcceptAllEulas = false
+	_, err = vdc.ComposeVAppWithVMs("app2", "two VMs", []*types.OrgVDCNetwork{network}, vms[:2])
+	if err != nil {
+		t.Fatalf("error composing vApp: %s", err)
+	}
+	requests = server.RequestsTo(http.MethodPost, vdcPath+"/action/composeVApp")
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 compose request, got %d", len(requests))
+	}
+	for _, expected := range []string{`name="app2"`, `networkName="net1"`, `name="web01"`, `name="web02"`} {
+		if !strings.Contains(requests[0].Body, expected) {
+			t.Errorf("expected %s in compose payload:\n%s", expected, requests[0].Body)
+		}
+	}
+	if strings.Contains(requests[0].Body, "AllEULAsAccepted") {
+		t.Errorf("expected EULAs not to be accepted when a VM does not accept them:\n%s", requests[0].Body)
+	}
+
+	vms[1].Name = vms[0].Name
+	_, err = vapp.AddVMs(vms[:2])
+	if err == nil {
+		t.Errorf("expected error with duplicate VM names")
+	}
+	_, err = vapp.AddVMs(nil)
+	if err == nil {
+		t.Errorf("expected error without VMs")
+	}
+}
+
+// Checks the capture of a vApp into a catalog and into a VDC
+func TestVApp_Capture(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	vappPath := "/api/vApp/vapp-1"
+	catalogPath := "/api/catalog/cat-1"
+	vdcPath := "/api/vdc/vdc-1"
+	task := `<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="running" href="{{server}}` + vcdtest.MockTaskPath + `"/>`
+	server.HandleXML(http.MethodPost, catalogPath+"/action/captureVApp", http.StatusAccepted, task)
+	server.HandleXML(http.MethodPost, vdcPath+"/action/captureVApp", http.StatusCreated,
+		`<VAppTemplate xmlns="http://www.vmware.com/vcloud/v1.5" name="golden" status="0">
+  <Tasks>`+task+`</Tasks>
+</VAppTemplate>`)
+	server.HandleXML(http.MethodDelete, vappPath, http.StatusAccepted, task)
+
+	vcdClient := newMockClient(t, server)
+	vapp := NewVApp(&vcdClient.Client)
+	vapp.VApp.Name = "app"
+	vapp.VApp.HREF = server.URL() + vappPath
+
+	catalog := NewCatalog(&vcdClient.Client)
+	catalog.Catalog.HREF = server.URL() + catalogPath
+	_, err := catalog.CaptureVApp(*vapp, "golden", "golden image", CaptureVAppCopy, true)
+	if err != nil {
+		t.Fatalf("error capturing vApp into catalog: %s", err)
+	}
+	requests := server.RequestsTo(http.MethodPost, catalogPath+"/action/captureVApp")
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 capture request, got %d", len(requests))
+	}
+	for _, expected := range []string{`name="golden"`, "<Description>golden image</Description>",
+		`<Source href="` + vapp.VApp.HREF + `"`, "<CustomizeOnInstantiate>true</CustomizeOnInstantiate>"} {
+		if !strings.Contains(requests[0].Body, expected) {
+			t.Errorf("expected %s in capture payload:\n%s", expected, requests[0].Body)
+		}
+	}
+	if len(server.RequestsTo(http.MethodDelete, vappPath)) != 0 {
+		t.Errorf("expected the vApp to be kept when copied")
+	}
+
+	vdc := NewVdc(&vcdClient.Client)
+	vdc.Vdc.HREF = server.URL() + vdcPath
+	deleteTask, err := vdc.CaptureVApp(*vapp, "golden", "", CaptureVAppMove, false)
+	if err != nil {
+		t.Fatalf("error capturing vApp into VDC: %s", err)
+	}
+	err = deleteTask.WaitTaskCompletion()
+	if err != nil {
+		t.Fatalf("error removing captured vApp: %s", err)
+	}
+	requests = server.RequestsTo(http.MethodPost, vdcPath+"/action/captureVApp")
+	if len(requests) != 1 || !strings.Contains(requests[0].Body, "<CustomizeOnInstantiate>false</CustomizeOnInstantiate>") {
+		t.Errorf("expected 1 capture request without customization, got %d", len(requests))
+	}
+	if len(server.RequestsTo(http.MethodDelete, vappPath)) != 1 {
+		t.Errorf("expected the vApp to be removed when moved")
+	}
+
+	_, err = vdc.CaptureVApp(*vapp, "golden", "", "clone", false)
+	if err == nil {
+		t.Errorf("expected error with an invalid capture mode")
+	}
+}
+
+// Checks the copy of a vApp into a VDC, with linked clones and removal of the source
+func TestVdc_CloneVApp(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	vappPath := "/api/vApp/vapp-1"
+	copyPath := "/api/vApp/vapp-2"
+	server.HandleXML(http.MethodPost, vcdtest.MockVdcPath+"/action/cloneVApp", http.StatusCreated,
+		`<VApp xmlns="http://www.vmware.com/vcloud/v1.5" name="app-copy" status="0" href="{{server}}`+copyPath+`">
+  <Tasks><Task status="running" href="{{server}}`+vcdtest.MockTaskPath+`"/></Tasks>
+</VApp>`)
+	server.HandleXML(http.MethodGet, copyPath, http.StatusOK,
+		`<VApp xmlns="http://www.vmware.com/vcloud/v1.5" name="app-copy" status="8" href="{{server}}`+copyPath+`"/>`)
+
+	vcdClient := newMockClient(t, server)
+	vapp := NewVApp(&vcdClient.Client)
+	vapp.VApp.Name = "app"
+	vapp.VApp.HREF = server.URL() + vappPath
+	vdc := NewVdc(&vcdClient.Client)
+	vdc.Vdc.HREF = server.URL() + vcdtest.MockVdcPath
+
+	_, err := vdc.CloneVApp(*vapp, "", "", false, false)
+	if err == nil {
+		t.Errorf("expected error copying vApp without name")
+	}
+
+	vappCopy, err := vdc.CloneVAppWait(*vapp, "app-copy", "copy of app", true, true)
+	if err != nil {
+		t.Fatalf("error copying vApp: %s", err)
+	}
+	if vappCopy.VApp.Name != "app-copy" || vappCopy.VApp.Status != 8 {
+		t.Errorf("unexpected vApp copy: %#v", vappCopy.VApp)
+	}
+	requests := server.RequestsTo(http.MethodPost, vcdtest.MockVdcPath+"/action/cloneVApp")
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 copy request, got %d", len(requests))
+	}
+	for _, expected := range []string{`name="app-copy"`, `linkedClone="true"`, "<Description>copy of app</Description>",
+		`<Source href="` + vapp.VApp.HREF + `"`, "<IsSourceDelete>true</IsSourceDelete>"} {
+		if !strings.Contains(requests[0].Body, expected) {
+			t.Errorf("expected %s in copy payload:\n%s", expected, requests[0].Body)
+		}
+	}
+}
+
+// Checks the recompose request copying a VM into another vApp
+func TestVApp_CopyVM(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	vappPath := "/api/vApp/vapp-2"
+	server.HandleXML(http.MethodPost, vappPath+"/action/recomposeVApp", http.StatusAccepted,
+		`<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="running" href="{{server}}`+vcdtest.MockTaskPath+`"/>`)
+
+	vcdClient := newMockClient(t, server)
+	vapp := NewVApp(&vcdClient.Client)
+	vapp.VApp.Name = "app2"
+	vapp.VApp.HREF = server.URL() + vappPath
+	vm := NewVM(&vcdClient.Client)
+	vm.VM.Name = "vm1"
+	vm.VM.HREF = server.URL() + "/api/vApp/vm-1"
+	vm.VM.NetworkConnectionSection = &types.NetworkConnectionSection{
+		NetworkConnection: []*types.NetworkConnection{
+			{Network: "net1", NetworkConnectionIndex: 0},
+			{Network: "net1", NetworkConnectionIndex: 1},
+			{Network: "none", NetworkConnectionIndex: 2},
+		},
+	}
+
+	_, err := vapp.CopyVM(*vm, "")
+	if err == nil {
+		t.Errorf("expected error copying VM without name")
+	}
+	task, err := vapp.CopyVM(*vm, "vm1-copy")
+	if err != nil {
+		t.Fatalf("error copying VM: %s", err)
+	}
+	err = task.WaitTaskCompletion()
+	if err != nil {
+		t.Fatalf("error waiting for VM copy: %s", err)
+	}
+	requests := server.RequestsTo(http.MethodPost, vappPath+"/action/recomposeVApp")
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 recompose request, got %d", len(requests))
+	}
+	body := requests[0].Body
+	for _, expected := range []string{`<Source href="` + vm.VM.HREF + `"`, "<Name>vm1-copy</Name>",
+		`<NetworkAssignment innerNetwork="net1" containerNetwork="net1">`} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("expected %s in recompose payload:\n%s", expected, body)
+		}
+	}
+	if strings.Count(body, "<NetworkAssignment") != 1 || strings.Contains(body, "sourceDelete") {
+		t.Errorf("unexpected recompose payload:\n%s", body)
+	}
+}
+
+// Checks the network configuration sent for a routed vApp network
+func TestVApp_AddRoutedNetwork(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	networkPath := "/api/vApp/vapp-1/networkConfigSection/"
+	server.HandleXML(http.MethodPut, networkPath, http.StatusAccepted,
+		`<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="running" href="{{server}}`+vcdtest.MockTaskPath+`"/>`)
+
+	vcdClient := newMockClient(t, server)
+	vapp := NewVApp(&vcdClient.Client)
+	vapp.VApp.HREF = server.URL() + "/api/vApp/vapp-1"
+	vapp.VApp.NetworkConfigSection = &types.NetworkConfigSection{NetworkConfig: []types.VAppNetworkConfiguration{
+		{NetworkName: "isolated", Configuration: &types.NetworkConfiguration{FenceMode: types.FenceModeIsolated}},
+	}}
+	orgNetwork := &types.OrgVDCNetwork{Name: "routed-org-net", HREF: server.URL() + "/api/network/net-1"}
+
+	firewallEnabled := true
+	_, err := vapp.AddRoutedNetwork(&VappNetworkSettings{
+		Name:               "routed",
+		Gateway:            "192.168.3.1",
+		NetMask:            "255.255.255.0",
+		StaticIPRanges:     []*types.IPRange{{StartAddress: "192.168.3.10", EndAddress: "192.168.3.20"}},
+		NatService:         &types.NatService{IsEnabled: true, NatType: "ipTranslation", Policy: "allowTrafficIn"},
+		FirewallEnabled:    &firewallEnabled,
+		RetainIpMacEnabled: true,
+	}, orgNetwork)
+	if err != nil {
+		t.Fatalf("error adding routed network: %s", err)
+	}
+	requests := server.RequestsTo(http.MethodPut, networkPath)
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 network configuration request, got %d", len(requests))
+	}
+	for _, expected := range []string{
+		`networkName="isolated"`,
+		`networkName="routed"`,
+		"<FenceMode>natRouted</FenceMode>",
+		`<ParentNetwork href="` + orgNetwork.HREF + `"`,
+		"<RetainNetInfoAcrossDeployments>true</RetainNetInfoAcrossDeployments>",
+		"<NatType>ipTranslation</NatType>",
+		"<DefaultAction>drop</DefaultAction>",
+		"<Policy>allow</Policy>",
+	} {
+		if !strings.Contains(requests[0].Body, expected) {
+			t.Errorf("expected %s in network configuration:\n%s", expected, requests[0].Body)
+		}
+	}
+
+	_, err = vapp.AddRoutedNetwork(&VappNetworkSettings{Name: "routed", Gateway: "192.168.3.1", NetMask: "255.255.255.0"}, nil)
+	if err == nil {
+		t.Errorf("expected error for routed network without parent network")
+	}
+}
+
+// Checks that a vApp network is changed in place, keeping the other networks and settings
+func TestVApp_UpdateNetwork(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	networkPath := "/api/vApp/vapp-1/networkConfigSection/"
+	server.HandleXML(http.MethodGet, networkPath, http.StatusOK,
+		`<NetworkConfigSection xmlns="http://www.vmware.com/vcloud/v1.5" xmlns:ovf="http://schemas.dmtf.org/ovf/envelope/1">
+  <ovf:Info>The configuration parameters for logical networks</ovf:Info>
+  <NetworkConfig networkName="org-net">
+    <Configuration>
+      <ParentNetwork href="{{server}}/api/network/net-1"/>
+      <FenceMode>bridged</FenceMode>
+    </Configuration>
+  </NetworkConfig>
+  <NetworkConfig networkName="app-net">
+    <Configuration>
+      <IpScopes><IpScope>
+        <IsInherited>false</IsInherited>
+        <Gateway>192.168.5.1</Gateway>
+        <Netmask>255.255.255.0</Netmask>
+        <IsEnabled>true</IsEnabled>
+        <IpRanges><IpRange><StartAddress>192.168.5.10</StartAddress><EndAddress>192.168.5.20</EndAddress></IpRange></IpRanges>
+      </IpScope></IpScopes>
+      <FenceMode>isolated</FenceMode>
+      <Features>
+        <DhcpService>
+          <IsEnabled>true</IsEnabled>
+          <DefaultLeaseTime>3600</DefaultLeaseTime>
+          <MaxLeaseTime>7200</MaxLeaseTime>
+          <IpRange><StartAddress>192.168.5.100</StartAddress><EndAddress>192.168.5.150</EndAddress></IpRange>
+        </DhcpService>
+      </Features>
+    </Configuration>
+    <IsDeployed>true</IsDeployed>
+  </NetworkConfig>
+</NetworkConfigSection>`)
+	server.HandleXML(http.MethodPut, networkPath, http.StatusAccepted,
+		`<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="running" href="{{server}}`+vcdtest.MockTaskPath+`"/>`)
+
+	vcdClient := newMockClient(t, server)
+	vapp := NewVApp(&vcdClient.Client)
+	vapp.VApp.HREF = server.URL() + "/api/vApp/vapp-1"
+
+	settings := &VappNetworkSettings{
+		Gateway:        "192.168.5.1",
+		NetMask:        "255.255.255.0",
+		DNS1:           "8.8.4.4",
+		StaticIPRanges: []*types.IPRange{{StartAddress: "192.168.5.10", EndAddress: "192.168.5.50"}},
+	}
+	_, err := vapp.UpdateNetwork("app-net", settings)
+	if err != nil {
+		t.Fatalf("error updating network: %s", err)
+	}
+	requests := server.RequestsTo(http.MethodPut, networkPath)
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 network configuration request, got %d", len(requests))
+	}
+	for _, expected := range []string{
+		`networkName="org-net"`,
+		`networkName="app-net"`,
+		"<Dns1>8.8.4.4</Dns1>",
+		"<EndAddress>192.168.5.50</EndAddress>",
+		"<StartAddress>192.168.5.100</StartAddress>",
+	} {
+		if !strings.Contains(requests[0].Body, expected) {
+			t.Errorf("expected %s in network configuration:\n%s", expected, requests[0].Body)
+		}
+	}
+
+	settings.Name = "renamed-net"
+	settings.DhcpSettings = &DhcpSettings{IsEnabled: false, IPRange: &types.IPRange{StartAddress: "192.168.5.100"}}
+	_, err = vapp.UpdateNetwork("app-net", settings)
+	if err != nil {
+		t.Fatalf("error updating network: %s", err)
+	}
+	requests = server.RequestsTo(http.MethodPut, networkPath)
+	body := requests[len(requests)-1].Body
+	if !strings.Contains(body, `networkName="renamed-net"`) || strings.Contains(body, "<EndAddress>192.168.5.150</EndAddress>") {
+		t.Errorf("unexpected network configuration:\n%s", body)
+	}
+	if settings.DhcpSettings.IPRange.EndAddress != "" {
+		t.Errorf("settings of the caller changed: %+v", settings.DhcpSettings.IPRange)
+	}
+
+	for name, invalid := range map[string]*VappNetworkSettings{
+		"missing-net": settings,
+		"org-net":     settings,
+		"app-net":     {Gateway: "192.168.5.1"},
+	} {
+		_, err = vapp.UpdateNetwork(name, invalid)
+		if err == nil {
+			t.Errorf("expected error updating network %s with %+v", name, invalid)
+		}
+	}
+}
+
+// Tests that copies of a vApp can be refreshed by concurrent goroutines, and that a failed refresh
+// keeps the current data
+func TestVApp_RefreshCopies(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	const vappPath = "/api/vApp/vapp-55555555-5555-5555-5555-555555555555"
+	server.HandleXML(http.MethodGet, vappPath, http.StatusOK,
+		`<VApp xmlns="http://www.vmware.com/vcloud/v1.5" name="app1" status="4" href="{{server}}`+vappPath+`">
+		  <Children><Vm name="vm1" href="{{server}}/api/vApp/vm-66666666-6666-6666-6666-666666666666"/></Children>
+		</VApp>`)
+
+	vcdClient := newMockClient(t, server, WithRateLimit(1000, 10))
+	vapp := NewVApp(&vcdClient.Client)
+	vapp.VApp.HREF = server.URL() + vappPath
+	original := vapp.VApp
+
+	errs := make(chan error, 10)
+	for i := 0; i < cap(errs); i++ {
+		vappCopy := *vapp
+		go func() {
+			err := vappCopy.Refresh()
+			if err == nil && (vappCopy.VApp.Name != "app1" || len(vappCopy.VApp.Children.VM) != 1) {
+				err = fmt.Errorf("unexpected vApp: %#v", vappCopy.VApp)
+			}
+			errs <- err
+		}()
+	}
+	for i := 0; i < cap(errs); i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("error refreshing vApp copy: %s", err)
+		}
+	}
+	if vapp.VApp != original || original.Name != "" {
+		t.Errorf("the refresh of the copies must not change the original vApp")
+	}
+
+	err := vapp.Refresh()
+	if err != nil {
+		t.Fatalf("error refreshing vApp: %s", err)
+	}
+	server.Handle(http.MethodGet, vappPath, vcdtest.Response{Status: http.StatusServiceUnavailable})
+	err = vapp.Refresh()
+	if err == nil {
+		t.Errorf("expected error refreshing unavailable vApp")
+	}
+	if vapp.VApp.Name != "app1" {
+		t.Errorf("expected the vApp data to be kept after a failed refresh, got %#v", vapp.VApp)
+	}
+}
@@ -49,3 +49,33 @@ func (vdc *Vdc) InstantiateVAppTemplate(template *types.InstantiateVAppTemplateP
 	}
 	return nil
 }
+
+// Refresh retrieves the vApp template again
+func (vAppTemplate *VAppTemplate) Refresh() error {
+	if vAppTemplate.VAppTemplate.HREF == "" {
+		return fmt.Errorf("cannot refresh, Object is empty")
+	}
+	href := vAppTemplate.VAppTemplate.HREF
+
+	// Unmarshal into an empty struct, otherwise we end up with duplicate elements in
+	// slices, and keep the current one when the request fails.
+	refreshed := &types.VAppTemplate{}
+
+	_, err := vAppTemplate.client.ExecuteRequest(href, http.MethodGet,
+		"", "error refreshing vApp template: %s", nil, refreshed)
+	if err != nil {
+		return err
+	}
+	vAppTemplate.VAppTemplate = refreshed
+	return nil
+}
+
+// Delete deletes the vApp template, together with the catalog item referring to it
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/DELETE-VAppTemplate.html
+func (vAppTemplate *VAppTemplate) Delete() (Task, error) {
+	if vAppTemplate.VAppTemplate.HREF == "" {
+		return Task{}, fmt.Errorf("cannot delete, Object is empty")
+	}
+	return vAppTemplate.client.ExecuteTaskRequest(vAppTemplate.VAppTemplate.HREF, http.MethodDelete,
+		"", "error deleting vApp template: %s", nil)
+}
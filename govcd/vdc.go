@@ -141,21 +141,25 @@ func (vdc *Vdc) Delete(force bool, recursive bool) (Task, error) {
 	if vdc.Vdc.HREF == "" {
 		return Task{}, fmt.Errorf("cannot delete, Object is empty")
 	}
+	return deleteVdc(vdc.client, vdc.Vdc.HREF, force, recursive)
+}
 
-	vdcUrl, err := url.ParseRequestURI(vdc.Vdc.HREF)
+// deleteVdc deletes the VDC with the given user or admin HREF
+func deleteVdc(client *Client, href string, force bool, recursive bool) (Task, error) {
+	vdcUrl, err := url.ParseRequestURI(href)
 	if err != nil {
 		return Task{}, fmt.Errorf("error parsing vdc url: %s", err)
 	}
 
-	req := vdc.client.NewRequest(map[string]string{
+	req := client.NewRequest(map[string]string{
 		"force":     strconv.FormatBool(force),
 		"recursive": strconv.FormatBool(recursive),
 	}, http.MethodDelete, *vdcUrl, nil)
-	resp, err := checkResp(vdc.client.Http.Do(req))
+	resp, err := checkResp(client.Http.Do(req))
 	if err != nil {
 		return Task{}, fmt.Errorf("error deleting vdc: %s", err)
 	}
-	task := NewTask(vdc.client)
+	task := NewTask(client)
 	if err = decodeBody(resp, task.Task); err != nil {
 		return Task{}, fmt.Errorf("error decoding task response: %s", err)
 	}
@@ -178,6 +182,50 @@ func (vdc *Vdc) DeleteWait(force bool, recursive bool) error {
 	return nil
 }
 
+// Update sends to vCD the settings of the VDC which can be changed from its user view: name,
+// description, compute capacity, quotas and enabled state. The other settings are taken from the
+// admin view of the VDC, which is retrieved first, so this requires system administrator rights.
+// Use AdminVdc.Update to change the admin settings as well.
+// Returns the update task.
+func (vdc *Vdc) Update() (Task, error) {
+	util.Logger.Printf("[TRACE] Vdc.Update - updating VDC %s", vdc.Vdc.Name)
+
+	if vdc.Vdc.HREF == "" {
+		return Task{}, fmt.Errorf("cannot update, Object is empty")
+	}
+
+	adminVdc := NewAdminVdc(vdc.client)
+	_, err := vdc.client.ExecuteRequest(getAdminHref(vdc.Vdc.HREF), http.MethodGet,
+		"", "error retrieving admin vdc: %s", nil, adminVdc.AdminVdc)
+	if err != nil {
+		return Task{}, err
+	}
+
+	adminVdc.AdminVdc.Name = vdc.Vdc.Name
+	adminVdc.AdminVdc.Description = vdc.Vdc.Description
+	adminVdc.AdminVdc.ComputeCapacity = vdc.Vdc.ComputeCapacity
+	adminVdc.AdminVdc.NicQuota = vdc.Vdc.NicQuota
+	adminVdc.AdminVdc.NetworkQuota = vdc.Vdc.NetworkQuota
+	adminVdc.AdminVdc.VMQuota = vdc.Vdc.VMQuota
+	adminVdc.AdminVdc.IsEnabled = vdc.Vdc.IsEnabled
+	return adminVdc.Update()
+}
+
+// UpdateWait updates the VDC, waits for the task to complete and refreshes the VDC
+func (vdc *Vdc) UpdateWait() error {
+	task, err := vdc.Update()
+	if err != nil {
+		return err
+	}
+	if task != (Task{}) {
+		err = task.WaitTaskCompletion()
+		if err != nil {
+			return fmt.Errorf("couldn't finish updating vdc %#v", err)
+		}
+	}
+	return vdc.Refresh()
+}
+
 func (vdc *Vdc) FindVDCNetwork(network string) (OrgVDCNetwork, error) {
 
 	err := vdc.Refresh()
@@ -311,6 +359,18 @@ func (vdc *Vdc) ComposeVApp(orgvdcnetworks []*types.OrgVDCNetwork, vapptemplate
 		return Task{}, fmt.Errorf("can't compose a new vApp, objects passed are not valid")
 	}
 	// Build request XML
+	sourcedItem := &types.SourcedCompositionItemParam{
+		Source: &types.Reference{
+			HREF: vapptemplate.VAppTemplate.Children.VM[0].HREF,
+			Name: vapptemplate.VAppTemplate.Children.VM[0].Name,
+		},
+		InstantiationParams: &types.InstantiationParams{
+			NetworkConnectionSection: &types.NetworkConnectionSection{
+				Info:                          "Network config for sourced item",
+				PrimaryNetworkConnectionIndex: 0,
+			},
+		},
+	}
 	vcomp := &types.ComposeVAppParams{
 		Ovf:         types.XMLNamespaceOVF,
 		Xsi:         types.XMLNamespaceXSI,
@@ -325,18 +385,7 @@ func (vdc *Vdc) ComposeVApp(orgvdcnetworks []*types.OrgVDCNetwork, vapptemplate
 			},
 		},
 		AllEULAsAccepted: acceptalleulas,
-		SourcedItem: &types.SourcedCompositionItemParam{
-			Source: &types.Reference{
-				HREF: vapptemplate.VAppTemplate.Children.VM[0].HREF,
-				Name: vapptemplate.VAppTemplate.Children.VM[0].Name,
-			},
-			InstantiationParams: &types.InstantiationParams{
-				NetworkConnectionSection: &types.NetworkConnectionSection{
-					Info:                          "Network config for sourced item",
-					PrimaryNetworkConnectionIndex: 0,
-				},
-			},
-		},
+		SourcedItem:      []*types.SourcedCompositionItemParam{sourcedItem},
 	}
 	for index, orgvdcnetwork := range orgvdcnetworks {
 		vcomp.InstantiationParams.NetworkConfigSection.NetworkConfig = append(vcomp.InstantiationParams.NetworkConfigSection.NetworkConfig,
@@ -352,7 +401,7 @@ func (vdc *Vdc) ComposeVApp(orgvdcnetworks []*types.OrgVDCNetwork, vapptemplate
 				},
 			},
 		)
-		vcomp.SourcedItem.InstantiationParams.NetworkConnectionSection.NetworkConnection = append(vcomp.SourcedItem.InstantiationParams.NetworkConnectionSection.NetworkConnection,
+		sourcedItem.InstantiationParams.NetworkConnectionSection.NetworkConnection = append(sourcedItem.InstantiationParams.NetworkConnectionSection.NetworkConnection,
 			&types.NetworkConnection{
 				Network:                 orgvdcnetwork.Name,
 				NetworkConnectionIndex:  index,
@@ -360,7 +409,7 @@ func (vdc *Vdc) ComposeVApp(orgvdcnetworks []*types.OrgVDCNetwork, vapptemplate
 				IPAddressAllocationMode: types.IPAllocationModePool,
 			},
 		)
-		vcomp.SourcedItem.NetworkAssignment = append(vcomp.SourcedItem.NetworkAssignment,
+		sourcedItem.NetworkAssignment = append(sourcedItem.NetworkAssignment,
 			&types.NetworkAssignment{
 				InnerNetwork:     orgvdcnetwork.Name,
 				ContainerNetwork: orgvdcnetwork.Name,
@@ -368,7 +417,7 @@ func (vdc *Vdc) ComposeVApp(orgvdcnetworks []*types.OrgVDCNetwork, vapptemplate
 		)
 	}
 	if storageprofileref.HREF != "" {
-		vcomp.SourcedItem.StorageProfile = &storageprofileref
+		sourcedItem.StorageProfile = &storageprofileref
 	}
 
 	vdcHref, err := url.ParseRequestURI(vdc.Vdc.HREF)
@@ -381,6 +430,149 @@ func (vdc *Vdc) ComposeVApp(orgvdcnetworks []*types.OrgVDCNetwork, vapptemplate
 		types.MimeComposeVappParams, "error instantiating a new vApp: %s", vcomp)
 }
 
+// ComposeVAppWithVMs creates a vApp with the given name and description, connected to the given
+// org VDC networks, and with all the given VMs, using a single compose task. The VMs can only
+// use the given networks. Returns the compose task.
+func (vdc *Vdc) ComposeVAppWithVMs(name, description string, orgVdcNetworks []*types.OrgVDCNetwork, vms []AddVMParams) (Task, error) {
+	if name == "" {
+		return Task{}, fmt.Errorf("vApp name can not be empty")
+	}
+	if len(vms) == 0 {
+		return Task{}, fmt.Errorf("no VM to compose vApp %s with", name)
+	}
+	sourcedItems, acceptAllEulas, err := buildSourcedItems(vms)
+	if err != nil {
+		return Task{}, err
+	}
+
+	vcomp := &types.ComposeVAppParams{
+		Ovf:              types.XMLNamespaceOVF,
+		Xsi:              types.XMLNamespaceXSI,
+		Xmlns:            types.XMLNamespaceVCloud,
+		Deploy:           false,
+		Name:             name,
+		PowerOn:          false,
+		Description:      description,
+		SourcedItem:      sourcedItems,
+		AllEULAsAccepted: acceptAllEulas,
+	}
+	if len(orgVdcNetworks) > 0 {
+		vcomp.InstantiationParams = &types.InstantiationParams{
+			NetworkConfigSection: &types.NetworkConfigSection{
+				Info: "Configuration parameters for logical networks",
+			},
+		}
+	}
+	for _, orgVdcNetwork := range orgVdcNetworks {
+		vcomp.InstantiationParams.NetworkConfigSection.NetworkConfig = append(vcomp.InstantiationParams.NetworkConfigSection.NetworkConfig,
+			types.VAppNetworkConfiguration{
+				NetworkName: orgVdcNetwork.Name,
+				Configuration: &types.NetworkConfiguration{
+					FenceMode: types.FenceModeBridged,
+					ParentNetwork: &types.Reference{
+						HREF: orgVdcNetwork.HREF,
+						Name: orgVdcNetwork.Name,
+						Type: orgVdcNetwork.Type,
+					},
+				},
+			},
+		)
+	}
+
+	vdcHref, err := url.ParseRequestURI(vdc.Vdc.HREF)
+	if err != nil {
+		return Task{}, fmt.Errorf("error getting vdc href: %v", err)
+	}
+	vdcHref.Path += "/action/composeVApp"
+
+	return vdc.client.ExecuteTaskRequest(vdcHref.String(), http.MethodPost,
+		types.MimeComposeVappParams, "error instantiating a new vApp: %s", vcomp)
+}
+
+// CaptureVApp creates a vApp template in the VDC from the given vApp, with copyOrMove either
+// CaptureVAppCopy or CaptureVAppMove (the vApp is removed once captured, and needs to be
+// undeployed). When customizeOnInstantiate is true, the VMs instantiated from the template are
+// customized, otherwise they are identical copies. Returns the capture task, or with
+// CaptureVAppMove the task removing the vApp.
+func (vdc *Vdc) CaptureVApp(vapp VApp, name, description, copyOrMove string, customizeOnInstantiate bool) (Task, error) {
+	vdcHref, err := url.ParseRequestURI(vdc.Vdc.HREF)
+	if err != nil {
+		return Task{}, fmt.Errorf("error getting vdc href: %v", err)
+	}
+	vdcHref.Path += "/action/captureVApp"
+
+	return captureVApp(vdc.client, vdcHref.String(), false, vapp, name, description, copyOrMove, customizeOnInstantiate)
+}
+
+// CloneVApp copies the vApp into the VDC, under the given name and description. With linkedClone,
+// the VMs of the copy are linked clones sharing the disks of the source VMs, when the storage
+// supports it. With deleteSource, the source vApp is removed once copied, which moves it: it then
+// needs to be undeployed. Returns the copy task.
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/POST-CloneVApp.html
+func (vdc *Vdc) CloneVApp(vapp VApp, name, description string, linkedClone, deleteSource bool) (Task, error) {
+	_, task, err := vdc.cloneVApp(vapp, name, description, linkedClone, deleteSource)
+	return task, err
+}
+
+// CloneVAppWait copies the vApp like CloneVApp, waits for the copy to complete and returns the new vApp
+func (vdc *Vdc) CloneVAppWait(vapp VApp, name, description string, linkedClone, deleteSource bool) (*VApp, error) {
+	newVApp, task, err := vdc.cloneVApp(vapp, name, description, linkedClone, deleteSource)
+	if err != nil {
+		return nil, err
+	}
+	err = task.WaitTaskCompletion()
+	if err != nil {
+		return nil, fmt.Errorf("couldn't finish copying vApp %s: %s", vapp.VApp.Name, err)
+	}
+	err = newVApp.Refresh()
+	if err != nil {
+		return nil, err
+	}
+	return newVApp, nil
+}
+
+// cloneVApp sends the cloneVApp request and returns the new vApp, as returned while being
+// created, with the copy task
+func (vdc *Vdc) cloneVApp(vapp VApp, name, description string, linkedClone, deleteSource bool) (*VApp, Task, error) {
+	if vapp.VApp == nil || vapp.VApp.HREF == "" {
+		return nil, Task{}, fmt.Errorf("vApp can not be empty")
+	}
+	if name == "" {
+		return nil, Task{}, fmt.Errorf("vApp name can not be empty")
+	}
+	vdcHref, err := url.ParseRequestURI(vdc.Vdc.HREF)
+	if err != nil {
+		return nil, Task{}, fmt.Errorf("error getting vdc href: %v", err)
+	}
+	vdcHref.Path += "/action/cloneVApp"
+
+	cloneParams := &types.CloneVAppParams{
+		Xmlns:       types.XMLNamespaceVCloud,
+		Name:        name,
+		Deploy:      false,
+		PowerOn:     false,
+		LinkedClone: linkedClone,
+		Description: description,
+		Source: &types.Reference{
+			HREF: vapp.VApp.HREF,
+		},
+		IsSourceDelete: deleteSource,
+	}
+
+	newVApp := NewVApp(vdc.client)
+	_, err = vdc.client.ExecuteRequest(vdcHref.String(), http.MethodPost, types.MimeCloneVappParams,
+		fmt.Sprintf("error copying vApp %s: %%s", vapp.VApp.Name), cloneParams, newVApp.VApp)
+	if err != nil {
+		return nil, Task{}, err
+	}
+	if newVApp.VApp.Tasks == nil || len(newVApp.VApp.Tasks.Task) == 0 {
+		return nil, Task{}, fmt.Errorf("no copy task found for vApp %s", name)
+	}
+	task := NewTask(vdc.client)
+	task.Task = newVApp.VApp.Tasks.Task[0]
+	return newVApp, *task, nil
+}
+
 func (vdc *Vdc) FindVAppByName(vapp string) (VApp, error) {
 
 	err := vdc.Refresh()
@@ -5,8 +5,14 @@
 package govcd
 
 import (
+	"encoding/xml"
 	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
 	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
 	. "gopkg.in/check.v1"
 )
 
@@ -223,3 +229,73 @@ func (vcd *TestVCD) Test_QueryVM(check *C) {
 
 	check.Assert(vm.VM.Name, Equals, vmName)
 }
+
+// Tests that PowerOnAllVApps powers on the vApp created by the test suite
+func (vcd *TestVCD) Test_PowerOnAllVApps(check *C) {
+	if vcd.skipVappTests {
+		check.Skip("Skipping test because vapp was not successfully created at setup")
+	}
+	err := vcd.vdc.PowerOnAllVApps(2)
+	check.Assert(err, IsNil)
+
+	status, err := vcd.vapp.GetStatus()
+	check.Assert(err, IsNil)
+	check.Assert(status, Equals, "POWERED_ON")
+}
+
+// Checks that Vdc.Update merges the user view settings into the admin view of the VDC, against a fake vCD
+func TestVdc_Update(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	const adminVdcPath = "/api/admin/vdc/" + vcdtest.MockVdcId
+	server.HandleXML(http.MethodGet, adminVdcPath, http.StatusOK,
+		`<AdminVdc xmlns="http://www.vmware.com/vcloud/v1.5" name="`+vcdtest.MockVdcName+`" href="{{server}}`+adminVdcPath+`">
+		  <AllocationModel>AllocationPool</AllocationModel>
+		  <NicQuota>0</NicQuota>
+		  <NetworkQuota>5</NetworkQuota>
+		  <VmQuota>0</VmQuota>
+		  <IsEnabled>true</IsEnabled>
+		  <ResourceGuaranteedMemory>0.5</ResourceGuaranteedMemory>
+		  <NetworkPoolReference name="pool1" href="{{server}}/api/admin/extension/networkPool/1"/>
+		  <ProviderVdcReference name="pvdc1" href="{{server}}/api/admin/providervdc/1"/>
+		</AdminVdc>`)
+	server.HandleXML(http.MethodPut, adminVdcPath, http.StatusAccepted,
+		`<AdminVdc xmlns="http://www.vmware.com/vcloud/v1.5" name="`+vcdtest.MockVdcName+`" href="{{server}}`+adminVdcPath+`">
+		  <Tasks><Task status="running" href="{{server}}`+vcdtest.MockTaskPath+`"/></Tasks>
+		</AdminVdc>`)
+
+	vcdClient := newMockClient(t, server)
+	org, err := GetOrgByName(vcdClient, vcdtest.MockOrgName)
+	if err != nil {
+		t.Fatalf("error retrieving org: %s", err)
+	}
+	vdc, err := org.GetVdcByName(vcdtest.MockVdcName)
+	if err != nil {
+		t.Fatalf("error retrieving vdc: %s", err)
+	}
+
+	vdc.Vdc.Description = "updated description"
+	vdc.Vdc.NicQuota = 10
+	err = vdc.UpdateWait()
+	if err != nil {
+		t.Fatalf("error updating vdc: %s", err)
+	}
+	puts := server.RequestsTo(http.MethodPut, adminVdcPath)
+	if len(puts) != 1 {
+		t.Fatalf("expected one PUT request, got %d", len(puts))
+	}
+	sent := types.AdminVdc{}
+	err = xml.Unmarshal([]byte(puts[0].Body), &sent)
+	if err != nil {
+		t.Fatalf("error decoding payload: %s", err)
+	}
+	if sent.Description != "updated description" || sent.NicQuota != 10 {
+		t.Errorf("expected the user view settings in the payload, got %s", puts[0].Body)
+	}
+	// The admin settings are kept
+	if sent.ResourceGuaranteedMemory != 0.5 || sent.NetworkPoolReference == nil ||
+		sent.ProviderVdcReference == nil || !strings.HasSuffix(sent.ProviderVdcReference.HREF, "/providervdc/1") {
+		t.Errorf("expected the admin settings in the payload, got %s", puts[0].Body)
+	}
+}
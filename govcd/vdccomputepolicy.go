@@ -0,0 +1,348 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// VdcComputePolicy is a VM sizing or placement policy, which the provider can assign to org VDCs.
+// Compute policies are managed through OpenAPI and need API 32.0+ (vCD 9.7+).
+type VdcComputePolicy struct {
+	VdcComputePolicy *types.VdcComputePolicy
+	client           *Client
+}
+
+// NewVdcComputePolicy creates a new compute policy structure which still needs to have
+// VdcComputePolicy attribute populated
+func NewVdcComputePolicy(cli *Client) *VdcComputePolicy {
+	return &VdcComputePolicy{
+		VdcComputePolicy: new(types.VdcComputePolicy),
+		client:           cli,
+	}
+}
+
+// GetAllVdcComputePolicies retrieves all VDC compute policies. Query parameters can be supplied to
+// perform additional filtering (e.g. "filter" => "isSizingOnly==true")
+func (client *Client) GetAllVdcComputePolicies(queryParameters url.Values) ([]*VdcComputePolicy, error) {
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointVdcComputePolicies
+	apiVersion, err := getOpenApiVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var typeResponses []*types.VdcComputePolicy
+	err = client.OpenApiGetAllItems(apiVersion, urlRef, queryParameters, &typeResponses)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make([]*VdcComputePolicy, len(typeResponses))
+	for index, typeResponse := range typeResponses {
+		policies[index] = &VdcComputePolicy{VdcComputePolicy: typeResponse, client: client}
+	}
+	return policies, nil
+}
+
+// GetVdcComputePolicyByName retrieves the VDC compute policy with the given name
+func (client *Client) GetVdcComputePolicyByName(name string) (*VdcComputePolicy, error) {
+	queryParams := url.Values{}
+	queryParams.Set("filter", fiqlEq("name", name))
+	policies, err := client.GetAllVdcComputePolicies(queryParams)
+	if err != nil {
+		return nil, err
+	}
+	if len(policies) == 0 {
+		return nil, fmt.Errorf("VDC compute policy '%s' not found", name)
+	}
+	if len(policies) > 1 {
+		return nil, fmt.Errorf("more than one VDC compute policy found with name '%s'", name)
+	}
+	return policies[0], nil
+}
+
+// GetVdcComputePolicyById retrieves the VDC compute policy with the given ID
+func (client *Client) GetVdcComputePolicyById(id string) (*VdcComputePolicy, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty VDC compute policy ID")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointVdcComputePolicies
+	apiVersion, err := getOpenApiVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint, id)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := NewVdcComputePolicy(client)
+	err = client.OpenApiGetItem(apiVersion, urlRef, nil, policy.VdcComputePolicy)
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// CreateVdcComputePolicy creates a new VDC compute policy.
+// A sizing policy sets CPU and memory values and IsSizingOnly. A placement policy refers to a
+// provider VDC compute policy through PvdcComputePolicy.
+func (client *Client) CreateVdcComputePolicy(newPolicy *types.VdcComputePolicy) (*VdcComputePolicy, error) {
+	if newPolicy == nil || newPolicy.Name == "" {
+		return nil, fmt.Errorf("VDC compute policy name is required")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointVdcComputePolicies
+	apiVersion, err := getOpenApiVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := NewVdcComputePolicy(client)
+	err = client.OpenApiPostItem(apiVersion, urlRef, nil, newPolicy, policy.VdcComputePolicy)
+	if err != nil {
+		return nil, fmt.Errorf("error creating VDC compute policy: %s", err)
+	}
+	return policy, nil
+}
+
+// Update sends the current definition of the VDC compute policy to vCD
+func (policy *VdcComputePolicy) Update() error {
+	if policy.VdcComputePolicy.ID == "" {
+		return fmt.Errorf("cannot update VDC compute policy without ID")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointVdcComputePolicies
+	apiVersion, err := getOpenApiVersion(endpoint)
+	if err != nil {
+		return err
+	}
+	urlRef, err := policy.client.OpenApiBuildEndpoint(endpoint, policy.VdcComputePolicy.ID)
+	if err != nil {
+		return err
+	}
+
+	updated := &types.VdcComputePolicy{}
+	err = policy.client.OpenApiPutItem(apiVersion, urlRef, nil, policy.VdcComputePolicy, updated)
+	if err != nil {
+		return fmt.Errorf("error updating VDC compute policy: %s", err)
+	}
+	policy.VdcComputePolicy = updated
+	return nil
+}
+
+// Delete removes the VDC compute policy. It fails if the policy is still assigned to a VDC.
+func (policy *VdcComputePolicy) Delete() error {
+	if policy.VdcComputePolicy.ID == "" {
+		return fmt.Errorf("cannot delete VDC compute policy without ID")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointVdcComputePolicies
+	apiVersion, err := getOpenApiVersion(endpoint)
+	if err != nil {
+		return err
+	}
+	urlRef, err := policy.client.OpenApiBuildEndpoint(endpoint, policy.VdcComputePolicy.ID)
+	if err != nil {
+		return err
+	}
+	err = policy.client.OpenApiDeleteItem(apiVersion, urlRef, nil)
+	if err != nil {
+		return fmt.Errorf("error deleting VDC compute policy: %s", err)
+	}
+	return nil
+}
+
+// reference returns the XML reference to the policy, as used in VDC definitions
+func (policy *VdcComputePolicy) reference() (*types.Reference, error) {
+	urlRef, err := policy.client.OpenApiBuildEndpoint(types.OpenApiPathVersion1_0_0+types.OpenApiEndpointVdcComputePolicies,
+		policy.VdcComputePolicy.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &types.Reference{HREF: urlRef.String(), ID: policy.VdcComputePolicy.ID, Name: policy.VdcComputePolicy.Name}, nil
+}
+
+// GetAllAssignedVdcComputePolicies retrieves the compute policies assigned to the VDC.
+// The functions handling the policies of a VDC need a client using API 32.0+ (see WithAPIVersion).
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/GET-VdcComputePolicies.html
+func (adminVdc *AdminVdc) GetAllAssignedVdcComputePolicies() ([]*VdcComputePolicy, error) {
+	references, err := adminVdc.getComputePolicyReferences()
+	if err != nil {
+		return nil, err
+	}
+	var policies []*VdcComputePolicy
+	for _, reference := range references.VdcComputePolicyReference {
+		policy, err := adminVdc.client.GetVdcComputePolicyById(reference.ID)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+// SetAssignedComputePolicies replaces the compute policies assigned to the VDC with the given ones.
+// The default compute policy of the VDC must be among them.
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/PUT-VdcComputePolicies.html
+func (adminVdc *AdminVdc) SetAssignedComputePolicies(policies []*VdcComputePolicy) error {
+	references, err := adminVdc.getComputePolicyReferences()
+	if err != nil {
+		return err
+	}
+	payload := &types.VdcComputePolicyReferences{Xmlns: types.XMLNamespaceVCloud}
+	for _, policy := range policies {
+		reference, err := policy.reference()
+		if err != nil {
+			return err
+		}
+		payload.VdcComputePolicyReference = append(payload.VdcComputePolicyReference, reference)
+	}
+	return adminVdc.client.ExecuteRequestWithoutResponse(references.HREF, http.MethodPut,
+		types.MimeVdcComputePolicyReferences, "error setting compute policies of vdc: %s", payload)
+}
+
+// AssignComputePolicies publishes the given compute policies to the VDC, keeping the ones
+// already assigned
+func (adminVdc *AdminVdc) AssignComputePolicies(policies ...*VdcComputePolicy) error {
+	assigned, err := adminVdc.GetAllAssignedVdcComputePolicies()
+	if err != nil {
+		return err
+	}
+	for _, policy := range policies {
+		if !computePolicyListContains(assigned, policy) {
+			assigned = append(assigned, policy)
+		}
+	}
+	return adminVdc.SetAssignedComputePolicies(assigned)
+}
+
+// UnassignComputePolicies removes the given compute policies from the VDC
+func (adminVdc *AdminVdc) UnassignComputePolicies(policies ...*VdcComputePolicy) error {
+	assigned, err := adminVdc.GetAllAssignedVdcComputePolicies()
+	if err != nil {
+		return err
+	}
+	var remaining []*VdcComputePolicy
+	for _, policy := range assigned {
+		if !computePolicyListContains(policies, policy) {
+			remaining = append(remaining, policy)
+		}
+	}
+	return adminVdc.SetAssignedComputePolicies(remaining)
+}
+
+// SetDefaultComputePolicy sets the compute policy used by the VMs of the VDC when no policy is
+// given. The policy must be assigned to the VDC.
+func (adminVdc *AdminVdc) SetDefaultComputePolicy(policy *VdcComputePolicy) error {
+	if !adminVdc.client.APIClientVersionIs(">= 32.0") {
+		return fmt.Errorf("setting the default compute policy requires API version 32.0 or newer, the client uses %s",
+			adminVdc.client.APIVersion)
+	}
+	reference, err := policy.reference()
+	if err != nil {
+		return err
+	}
+	adminVdc.AdminVdc.DefaultComputePolicy = reference
+	return adminVdc.UpdateWait()
+}
+
+// getComputePolicyReferences retrieves the references to the compute policies of the VDC
+func (adminVdc *AdminVdc) getComputePolicyReferences() (*types.VdcComputePolicyReferences, error) {
+	if adminVdc.AdminVdc.HREF == "" {
+		return nil, fmt.Errorf("cannot retrieve compute policies, Object is empty")
+	}
+	if !adminVdc.client.APIClientVersionIs(">= 32.0") {
+		return nil, fmt.Errorf("VDC compute policies require API version 32.0 or newer, the client uses %s",
+			adminVdc.client.APIVersion)
+	}
+	policiesHREF, err := url.ParseRequestURI(adminVdc.AdminVdc.HREF)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing vdc url: %s", err)
+	}
+	policiesHREF.Path += "/computePolicies"
+
+	references := &types.VdcComputePolicyReferences{}
+	_, err = adminVdc.client.ExecuteRequest(policiesHREF.String(), http.MethodGet,
+		"", "error retrieving compute policies of vdc: %s", nil, references)
+	if err != nil {
+		return nil, err
+	}
+	if references.HREF == "" {
+		references.HREF = policiesHREF.String()
+	}
+	return references, nil
+}
+
+// computePolicyListContains returns true if the list contains a policy with the same ID
+func computePolicyListContains(list []*VdcComputePolicy, policy *VdcComputePolicy) bool {
+	for _, item := range list {
+		if item.VdcComputePolicy.ID == policy.VdcComputePolicy.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateComputePolicy sets the VM sizing policy and the VM placement policy of the VM, so that its
+// CPU and memory are governed by the policies. A nil policy keeps the current one. The policies
+// must be assigned to the VDC of the VM, and the client must use API 33.0+ (see WithAPIVersion).
+// Returns the task reconfiguring the VM.
+func (vm *VM) UpdateComputePolicy(sizingPolicy, placementPolicy *VdcComputePolicy) (Task, error) {
+	if !vm.client.APIClientVersionIs(">= 33.0") {
+		return Task{}, fmt.Errorf("updating the compute policy of a VM requires API version 33.0 or newer, the client uses %s",
+			vm.client.APIVersion)
+	}
+	if sizingPolicy == nil && placementPolicy == nil {
+		return Task{}, fmt.Errorf("no compute policy to set")
+	}
+	err := vm.Refresh()
+	if err != nil {
+		return Task{}, fmt.Errorf("error refreshing VM before updating its compute policy: %s", err)
+	}
+
+	computePolicy := &types.ComputePolicy{}
+	if vm.VM.ComputePolicy != nil {
+		*computePolicy = *vm.VM.ComputePolicy
+	}
+	if sizingPolicy != nil {
+		computePolicy.VmSizingPolicy, err = sizingPolicy.reference()
+		if err != nil {
+			return Task{}, err
+		}
+	}
+	if placementPolicy != nil {
+		computePolicy.VmPlacementPolicy, err = placementPolicy.reference()
+		if err != nil {
+			return Task{}, err
+		}
+	}
+
+	vmPayload := &types.VM{
+		Xmlns:         types.XMLNamespaceVCloud,
+		Name:          vm.VM.Name,
+		Description:   vm.VM.Description,
+		ComputePolicy: computePolicy,
+	}
+
+	apiEndpoint, err := url.ParseRequestURI(vm.VM.HREF)
+	if err != nil {
+		return Task{}, fmt.Errorf("error parsing VM HREF: %s", err)
+	}
+	apiEndpoint.Path += "/action/reconfigureVm"
+
+	// Return the task
+	return vm.client.ExecuteTaskRequest(apiEndpoint.String(), http.MethodPost,
+		types.MimeVM, "error updating VM compute policy: %s", vmPayload)
+}
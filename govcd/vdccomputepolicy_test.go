@@ -0,0 +1,122 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+	. "gopkg.in/check.v1"
+)
+
+func (vcd *TestVCD) Test_VdcComputePolicies(check *C) {
+	if vcd.skipAdminTests {
+		check.Skip(fmt.Sprintf(TestRequiresSysAdminPrivileges, check.TestName()))
+	}
+	if vcd.client.APIVCDMaxVersionIs("< 32.0") {
+		check.Skip("VDC compute policies require vCD 9.7+")
+	}
+	client := &vcd.client.Client
+
+	cpuCount := 2
+	memory := 2048
+	policy, err := client.CreateVdcComputePolicy(&types.VdcComputePolicy{
+		Name:         TestVdcComputePolicy,
+		Description:  TestVdcComputePolicy,
+		CPUCount:     &cpuCount,
+		Memory:       &memory,
+		IsSizingOnly: true,
+	})
+	check.Assert(err, IsNil)
+	AddToCleanupList(TestVdcComputePolicy, "vdcComputePolicy", "", "Test_VdcComputePolicies")
+	check.Assert(policy.VdcComputePolicy.ID, Not(Equals), "")
+	check.Assert(*policy.VdcComputePolicy.CPUCount, Equals, cpuCount)
+
+	policyByName, err := client.GetVdcComputePolicyByName(TestVdcComputePolicy)
+	check.Assert(err, IsNil)
+	check.Assert(policyByName.VdcComputePolicy.ID, Equals, policy.VdcComputePolicy.ID)
+
+	policy.VdcComputePolicy.Description = "updated description"
+	err = policy.Update()
+	check.Assert(err, IsNil)
+	check.Assert(policy.VdcComputePolicy.Description, Equals, "updated description")
+
+	// Assigning policies to a VDC needs a client using API 32.0+
+	if vcd.client.APIClientVersionIs(">= 32.0") {
+		adminOrg, err := GetAdminOrgByName(vcd.client, vcd.org.Org.Name)
+		check.Assert(err, IsNil)
+		adminVdc, err := adminOrg.GetAdminVdcByName(vcd.vdc.Vdc.Name)
+		check.Assert(err, IsNil)
+
+		err = adminVdc.AssignComputePolicies(policy)
+		check.Assert(err, IsNil)
+		assigned, err := adminVdc.GetAllAssignedVdcComputePolicies()
+		check.Assert(err, IsNil)
+		check.Assert(computePolicyListContains(assigned, policy), Equals, true)
+
+		err = adminVdc.UnassignComputePolicies(policy)
+		check.Assert(err, IsNil)
+		assigned, err = adminVdc.GetAllAssignedVdcComputePolicies()
+		check.Assert(err, IsNil)
+		check.Assert(computePolicyListContains(assigned, policy), Equals, false)
+	}
+
+	err = policy.Delete()
+	check.Assert(err, IsNil)
+	_, err = client.GetVdcComputePolicyById(policy.VdcComputePolicy.ID)
+	check.Assert(err, NotNil)
+}
+
+// Checks that the sizing policy of a VM is changed and its placement policy is kept
+func TestVM_UpdateComputePolicy(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	vmPath := "/api/vApp/vm-1"
+	server.HandleXML(http.MethodGet, vmPath, http.StatusOK,
+		`<Vm xmlns="http://www.vmware.com/vcloud/v1.5" name="web" href="{{server}}`+vmPath+`">
+  <ComputePolicy>
+    <VmPlacementPolicy href="{{server}}/cloudapi/1.0.0/vdcComputePolicies/urn:vcloud:vdcComputePolicy:placement" id="urn:vcloud:vdcComputePolicy:placement"/>
+    <VmSizingPolicy href="{{server}}/cloudapi/1.0.0/vdcComputePolicies/urn:vcloud:vdcComputePolicy:small" id="urn:vcloud:vdcComputePolicy:small"/>
+  </ComputePolicy>
+</Vm>`)
+	server.HandleXML(http.MethodPost, vmPath+"/action/reconfigureVm", http.StatusAccepted,
+		`<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="running" href="{{server}}`+vcdtest.MockTaskPath+`"/>`)
+
+	vcdClient := newMockClient(t, server)
+	vm := NewVM(&vcdClient.Client)
+	vm.VM.HREF = server.URL() + vmPath
+	large := NewVdcComputePolicy(&vcdClient.Client)
+	large.VdcComputePolicy.ID = "urn:vcloud:vdcComputePolicy:large"
+	large.VdcComputePolicy.Name = "large"
+
+	vcdClient.Client.APIVersion = "32.0"
+	_, err := vm.UpdateComputePolicy(large, nil)
+	if err == nil {
+		t.Errorf("expected error updating compute policy with API 32.0")
+	}
+
+	vcdClient.Client.APIVersion = "33.0"
+	_, err = vm.UpdateComputePolicy(large, nil)
+	if err != nil {
+		t.Fatalf("error updating compute policy: %s", err)
+	}
+	requests := server.RequestsTo(http.MethodPost, vmPath+"/action/reconfigureVm")
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 reconfigure request, got %d", len(requests))
+	}
+	for _, expected := range []string{`name="web"`, "vdcComputePolicies/urn:vcloud:vdcComputePolicy:large",
+		`id="urn:vcloud:vdcComputePolicy:placement"`} {
+		if !strings.Contains(requests[0].Body, expected) {
+			t.Errorf("expected %s in reconfigure payload:\n%s", expected, requests[0].Body)
+		}
+	}
+	if strings.Contains(requests[0].Body, "urn:vcloud:vdcComputePolicy:small") {
+		t.Errorf("expected the previous sizing policy to be replaced:\n%s", requests[0].Body)
+	}
+}
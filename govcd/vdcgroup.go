@@ -0,0 +1,515 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// VdcGroup is a group of org VDCs (data center group), whose members share the networks and the
+// edge gateways owned by the group, protected by a distributed firewall. VDC groups are managed
+// through OpenAPI and need API 35.0+ (vCD 10.2+).
+type VdcGroup struct {
+	VdcGroup *types.VdcGroup
+	client   *Client
+}
+
+// GetAllVdcGroups retrieves the VDC groups of the org. Query parameters can be supplied to perform
+// additional filtering (e.g. "filter" => "name==group1")
+func (adminOrg *AdminOrg) GetAllVdcGroups(queryParameters url.Values) ([]*VdcGroup, error) {
+	client, err := adminOrg.tenantClient()
+	if err != nil {
+		return nil, err
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointVdcGroups
+	apiVersion, err := client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var typeResponses []*types.VdcGroup
+	err = client.OpenApiGetAllItems(apiVersion, urlRef, queryParameters, &typeResponses)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]*VdcGroup, len(typeResponses))
+	for index, typeResponse := range typeResponses {
+		groups[index] = &VdcGroup{VdcGroup: typeResponse, client: client}
+	}
+	return groups, nil
+}
+
+// GetVdcGroupByName retrieves the VDC group of the org with the given name
+func (adminOrg *AdminOrg) GetVdcGroupByName(name string) (*VdcGroup, error) {
+	queryParams := url.Values{}
+	queryParams.Set("filter", fiqlEq("name", name))
+	groups, err := adminOrg.GetAllVdcGroups(queryParams)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return nil, wrapErrorf(ErrorEntityNotFound, "VDC group '%s' not found: %s", name, ErrorEntityNotFound)
+	}
+	if len(groups) > 1 {
+		return nil, fmt.Errorf("more than one VDC group found with name '%s'", name)
+	}
+	return groups[0], nil
+}
+
+// GetVdcGroupById retrieves the VDC group of the org with the given ID
+func (adminOrg *AdminOrg) GetVdcGroupById(id string) (*VdcGroup, error) {
+	client, err := adminOrg.tenantClient()
+	if err != nil {
+		return nil, err
+	}
+	return getVdcGroupById(client, id)
+}
+
+// GetVdcGroupCandidateVdcs retrieves the org VDCs which can be in a VDC group with the given VDC,
+// i.e. the VDCs sharing its network provider. Query parameters can be supplied to perform
+// additional filtering.
+func (adminOrg *AdminOrg) GetVdcGroupCandidateVdcs(vdcId string, queryParameters url.Values) ([]*types.CandidateVdc, error) {
+	client, err := adminOrg.tenantClient()
+	if err != nil {
+		return nil, err
+	}
+	return getVdcGroupCandidateVdcs(client, vdcId, queryParameters)
+}
+
+// CreateVdcGroup creates a VDC group in the org. The group needs at least one participating VDC,
+// and OrgID defaults to the ID of the org. NewParticipatingOrgVdc builds the participants from
+// the candidate VDCs.
+func (adminOrg *AdminOrg) CreateVdcGroup(vdcGroupConfig *types.VdcGroup) (*VdcGroup, error) {
+	err := validateVdcGroup(vdcGroupConfig)
+	if err != nil {
+		return nil, err
+	}
+	client, err := adminOrg.tenantClient()
+	if err != nil {
+		return nil, err
+	}
+	payload := *vdcGroupConfig
+	if payload.OrgID == "" {
+		payload.OrgID, err = entityUrn(adminOrg.AdminOrg.ID, adminOrg.AdminOrg.HREF)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving the ID of org %s: %s", adminOrg.AdminOrg.Name, err)
+		}
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointVdcGroups
+	apiVersion, err := client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	group := &VdcGroup{VdcGroup: &types.VdcGroup{}, client: client}
+	err = client.OpenApiPostItem(apiVersion, urlRef, nil, &payload, group.VdcGroup)
+	if err != nil {
+		return nil, fmt.Errorf("error creating VDC group: %s", err)
+	}
+	return group, nil
+}
+
+// NewParticipatingOrgVdc returns the participant of a VDC group for the candidate VDC
+func NewParticipatingOrgVdc(candidate *types.CandidateVdc) types.ParticipatingOrgVdc {
+	orgRef := candidate.OrgRef
+	siteRef := candidate.SiteRef
+	return types.ParticipatingOrgVdc{
+		VdcRef:               types.OpenApiReference{ID: candidate.ID, Name: candidate.Name},
+		OrgRef:               &orgRef,
+		SiteRef:              &siteRef,
+		NetworkProviderScope: candidate.NetworkProviderScope,
+		FaultDomainTag:       candidate.FaultDomainTag,
+	}
+}
+
+// Reference returns the reference to the VDC group, as used to make it the owner of an edge
+// gateway, of an org VDC network or of a firewall group
+func (group *VdcGroup) Reference() *types.OpenApiReference {
+	return &types.OpenApiReference{ID: group.VdcGroup.ID, Name: group.VdcGroup.Name}
+}
+
+// Refresh retrieves the current definition of the VDC group
+func (group *VdcGroup) Refresh() error {
+	refreshed, err := getVdcGroupById(group.client, group.VdcGroup.ID)
+	if err != nil {
+		return err
+	}
+	group.VdcGroup = refreshed.VdcGroup
+	return nil
+}
+
+// Update sends the current definition of the VDC group (name, description, participating VDCs,
+// local egress) to vCD
+func (group *VdcGroup) Update() error {
+	if group.VdcGroup.ID == "" {
+		return fmt.Errorf("cannot update VDC group without ID")
+	}
+	err := validateVdcGroup(group.VdcGroup)
+	if err != nil {
+		return err
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointVdcGroups
+	apiVersion, err := group.client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return err
+	}
+	urlRef, err := group.client.OpenApiBuildEndpoint(endpoint, group.VdcGroup.ID)
+	if err != nil {
+		return err
+	}
+
+	updated := &types.VdcGroup{}
+	err = group.client.OpenApiPutItem(apiVersion, urlRef, nil, group.VdcGroup, updated)
+	if err != nil {
+		return fmt.Errorf("error updating VDC group: %s", err)
+	}
+	group.VdcGroup = updated
+	return nil
+}
+
+// Delete removes the VDC group. It fails if networks or edge gateways are still owned by the group.
+func (group *VdcGroup) Delete() error {
+	if group.VdcGroup.ID == "" {
+		return fmt.Errorf("cannot delete VDC group without ID")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointVdcGroups
+	apiVersion, err := group.client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return err
+	}
+	urlRef, err := group.client.OpenApiBuildEndpoint(endpoint, group.VdcGroup.ID)
+	if err != nil {
+		return err
+	}
+	err = group.client.OpenApiDeleteItem(apiVersion, urlRef, nil)
+	if err != nil {
+		return fmt.Errorf("error deleting VDC group: %s", err)
+	}
+	return nil
+}
+
+// AddParticipatingVdcs adds the VDCs with the given IDs to the VDC group. The VDCs must be
+// candidates for the group (see AdminOrg.GetVdcGroupCandidateVdcs).
+func (group *VdcGroup) AddParticipatingVdcs(vdcIds ...string) error {
+	if len(group.VdcGroup.ParticipatingOrgVdcs) == 0 {
+		return fmt.Errorf("VDC group %s has no participating VDC", group.VdcGroup.Name)
+	}
+	candidates, err := getVdcGroupCandidateVdcs(group.client, group.VdcGroup.ParticipatingOrgVdcs[0].VdcRef.ID, nil)
+	if err != nil {
+		return err
+	}
+	participants := append([]types.ParticipatingOrgVdc(nil), group.VdcGroup.ParticipatingOrgVdcs...)
+	for _, vdcId := range vdcIds {
+		for _, participant := range participants {
+			if participant.VdcRef.ID == vdcId {
+				return fmt.Errorf("VDC %s already participates in VDC group %s", vdcId, group.VdcGroup.Name)
+			}
+		}
+		var candidate *types.CandidateVdc
+		for _, candidateVdc := range candidates {
+			if candidateVdc.ID == vdcId {
+				candidate = candidateVdc
+				break
+			}
+		}
+		if candidate == nil {
+			return fmt.Errorf("VDC %s is not a candidate for VDC group %s", vdcId, group.VdcGroup.Name)
+		}
+		participants = append(participants, NewParticipatingOrgVdc(candidate))
+	}
+	return group.updateParticipants(participants)
+}
+
+// RemoveParticipatingVdcs removes the VDCs with the given IDs from the VDC group. A group keeps at
+// least one participating VDC.
+func (group *VdcGroup) RemoveParticipatingVdcs(vdcIds ...string) error {
+	participants := append([]types.ParticipatingOrgVdc(nil), group.VdcGroup.ParticipatingOrgVdcs...)
+	for _, vdcId := range vdcIds {
+		found := false
+		for index := range participants {
+			if participants[index].VdcRef.ID == vdcId {
+				participants = append(participants[:index], participants[index+1:]...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("VDC %s does not participate in VDC group %s", vdcId, group.VdcGroup.Name)
+		}
+	}
+	return group.updateParticipants(participants)
+}
+
+// GetDfwPolicies retrieves the state of the distributed firewall of the VDC group
+func (group *VdcGroup) GetDfwPolicies() (*types.VdcGroupDfwPolicies, error) {
+	urlRef, apiVersion, err := group.dfwPoliciesEndpoint()
+	if err != nil {
+		return nil, err
+	}
+	policies := &types.VdcGroupDfwPolicies{}
+	err = group.client.OpenApiGetItem(apiVersion, urlRef, nil, policies)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving distributed firewall of VDC group: %s", err)
+	}
+	return policies, nil
+}
+
+// ActivateDfw activates the distributed firewall of the VDC group, which is needed by the firewall
+// rules between the VDCs of an NSX-T backed group, and refreshes the group
+func (group *VdcGroup) ActivateDfw() error {
+	return group.setDfw(true)
+}
+
+// DeactivateDfw deactivates the distributed firewall of the VDC group and refreshes the group
+func (group *VdcGroup) DeactivateDfw() error {
+	return group.setDfw(false)
+}
+
+// setDfw sets the activation of the distributed firewall of the VDC group
+func (group *VdcGroup) setDfw(enabled bool) error {
+	urlRef, apiVersion, err := group.dfwPoliciesEndpoint()
+	if err != nil {
+		return err
+	}
+	err = group.client.OpenApiPutItem(apiVersion, urlRef, nil, &types.VdcGroupDfwPolicies{Enabled: enabled}, nil)
+	if err != nil {
+		return fmt.Errorf("error setting distributed firewall of VDC group %s: %s", group.VdcGroup.Name, err)
+	}
+	return group.Refresh()
+}
+
+// GetDistributedFirewallRules retrieves the ordered rules of the default policy of the distributed
+// firewall of the VDC group, which must be active (see ActivateDfw)
+func (group *VdcGroup) GetDistributedFirewallRules() (*types.DistributedFirewallRules, error) {
+	urlRef, apiVersion, err := group.dfwRulesEndpoint()
+	if err != nil {
+		return nil, err
+	}
+	rules := &types.DistributedFirewallRules{}
+	err = group.client.OpenApiGetItem(apiVersion, urlRef, nil, rules)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving distributed firewall rules of VDC group: %s", err)
+	}
+	return rules, nil
+}
+
+// UpdateDistributedFirewallRules replaces the rules of the default policy of the distributed
+// firewall of the VDC group with the given ordered list. Rules with the ID of an existing rule update
+// it, the others are created; existing rules missing from the list are removed.
+func (group *VdcGroup) UpdateDistributedFirewallRules(rules []*types.DistributedFirewallRule) (*types.DistributedFirewallRules, error) {
+	for _, rule := range rules {
+		err := validateDistributedFirewallRule(rule)
+		if err != nil {
+			return nil, err
+		}
+	}
+	urlRef, apiVersion, err := group.dfwRulesEndpoint()
+	if err != nil {
+		return nil, err
+	}
+	payload := &types.DistributedFirewallRules{Values: rules}
+	if payload.Values == nil {
+		payload.Values = []*types.DistributedFirewallRule{}
+	}
+	updated := &types.DistributedFirewallRules{}
+	err = group.client.OpenApiPutItem(apiVersion, urlRef, nil, payload, updated)
+	if err != nil {
+		return nil, fmt.Errorf("error updating distributed firewall rules of VDC group: %s", err)
+	}
+	return updated, nil
+}
+
+// AttachNetworkContextProfiles adds the network context profiles to the distributed firewall rule
+// with the given ID, so that the rule only matches the traffic of their applications. Profiles
+// already attached to the rule are skipped; the other rules are left unchanged.
+func (group *VdcGroup) AttachNetworkContextProfiles(ruleId string, profiles ...*types.NsxtNetworkContextProfile) (*types.DistributedFirewallRules, error) {
+	if len(profiles) == 0 {
+		return nil, fmt.Errorf("no network context profile given")
+	}
+	current, err := group.GetDistributedFirewallRules()
+	if err != nil {
+		return nil, err
+	}
+	var rule *types.DistributedFirewallRule
+	for _, candidate := range current.Values {
+		if candidate.ID == ruleId {
+			rule = candidate
+			break
+		}
+	}
+	if rule == nil {
+		return nil, wrapErrorf(ErrorEntityNotFound, "distributed firewall rule '%s' not found in VDC group %s: %s",
+			ruleId, group.VdcGroup.Name, ErrorEntityNotFound)
+	}
+
+	for _, profile := range profiles {
+		if profile == nil || profile.ID == "" {
+			return nil, fmt.Errorf("network context profiles need an ID")
+		}
+		attached := false
+		for _, reference := range rule.NetworkContextProfiles {
+			attached = attached || reference.ID == profile.ID
+		}
+		if !attached {
+			rule.NetworkContextProfiles = append(rule.NetworkContextProfiles,
+				types.OpenApiReference{ID: profile.ID, Name: profile.Name})
+		}
+	}
+	return group.UpdateDistributedFirewallRules(current.Values)
+}
+
+// dfwRulesEndpoint returns the URL of the rules of the default policy of the distributed firewall
+// of the VDC group, with the API version to use
+func (group *VdcGroup) dfwRulesEndpoint() (*url.URL, string, error) {
+	policies, err := group.GetDfwPolicies()
+	if err != nil {
+		return nil, "", err
+	}
+	if !policies.Enabled || policies.DefaultPolicy == nil || policies.DefaultPolicy.ID == "" {
+		return nil, "", fmt.Errorf("distributed firewall of VDC group %s is not active", group.VdcGroup.Name)
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointVdcGroupDfwRules
+	apiVersion, err := group.client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, "", err
+	}
+	urlRef, err := group.client.OpenApiBuildEndpoint(fmt.Sprintf(endpoint, group.VdcGroup.ID, policies.DefaultPolicy.ID))
+	if err != nil {
+		return nil, "", err
+	}
+	return urlRef, apiVersion, nil
+}
+
+// dfwPoliciesEndpoint returns the URL of the distributed firewall of the VDC group, with the API
+// version to use
+func (group *VdcGroup) dfwPoliciesEndpoint() (*url.URL, string, error) {
+	if group.VdcGroup.ID == "" {
+		return nil, "", fmt.Errorf("VDC group %s has no ID", group.VdcGroup.Name)
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointVdcGroupDfwPolicies
+	apiVersion, err := group.client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, "", err
+	}
+	urlRef, err := group.client.OpenApiBuildEndpoint(fmt.Sprintf(endpoint, group.VdcGroup.ID))
+	if err != nil {
+		return nil, "", err
+	}
+	return urlRef, apiVersion, nil
+}
+
+// updateParticipants updates the VDC group with the given participants. The definition of the
+// group is only changed when the update succeeds.
+func (group *VdcGroup) updateParticipants(participants []types.ParticipatingOrgVdc) error {
+	definition := *group.VdcGroup
+	definition.ParticipatingOrgVdcs = participants
+	updated := &VdcGroup{VdcGroup: &definition, client: group.client}
+	err := updated.Update()
+	if err != nil {
+		return err
+	}
+	group.VdcGroup = updated.VdcGroup
+	return nil
+}
+
+// getVdcGroupById retrieves the VDC group with the given ID
+func getVdcGroupById(client *Client, id string) (*VdcGroup, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty VDC group ID")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointVdcGroups
+	apiVersion, err := client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint, id)
+	if err != nil {
+		return nil, err
+	}
+
+	group := &VdcGroup{VdcGroup: &types.VdcGroup{}, client: client}
+	err = client.OpenApiGetItem(apiVersion, urlRef, nil, group.VdcGroup)
+	if err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// getVdcGroupCandidateVdcs retrieves the VDCs which can be in a VDC group with the given VDC
+func getVdcGroupCandidateVdcs(client *Client, vdcId string, queryParameters url.Values) ([]*types.CandidateVdc, error) {
+	if vdcId == "" {
+		return nil, fmt.Errorf("empty VDC ID")
+	}
+	endpoint := types.OpenApiPathVersion1_0_0 + types.OpenApiEndpointVdcGroupCandidates
+	apiVersion, err := client.getOpenApiHighestElevatedVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	urlRef, err := client.OpenApiBuildEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	// The candidates are computed in the context of the VDC, among the VDCs of the site
+	queryParams := queryParameterFilterAnd(fiqlEq("_context", vdcId)+";"+fiqlEq("_context", types.VdcGroupTypeLocal), queryParameters)
+	var candidates []*types.CandidateVdc
+	err = client.OpenApiGetAllItems(apiVersion, urlRef, queryParams, &candidates)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving candidate VDCs for VDC group: %s", err)
+	}
+	return candidates, nil
+}
+
+// validateDistributedFirewallRule checks the fields needed to create or update a rule of the
+// distributed firewall
+func validateDistributedFirewallRule(rule *types.DistributedFirewallRule) error {
+	if rule == nil || rule.Name == "" {
+		return fmt.Errorf("distributed firewall rule name is required")
+	}
+	switch rule.ActionValue {
+	case types.NsxtFirewallRuleActionAllow, types.NsxtFirewallRuleActionDrop, types.NsxtFirewallRuleActionReject:
+	default:
+		return fmt.Errorf("invalid action '%s' for distributed firewall rule %s", rule.ActionValue, rule.Name)
+	}
+	switch rule.Direction {
+	case "", types.NsxtFirewallRuleDirectionIn, types.NsxtFirewallRuleDirectionOut, types.NsxtFirewallRuleDirectionInOut:
+	default:
+		return fmt.Errorf("invalid direction '%s' for distributed firewall rule %s", rule.Direction, rule.Name)
+	}
+	return nil
+}
+
+// validateVdcGroup checks the fields needed to create or update a VDC group
+func validateVdcGroup(vdcGroupConfig *types.VdcGroup) error {
+	if vdcGroupConfig == nil || vdcGroupConfig.Name == "" {
+		return fmt.Errorf("VDC group name is required")
+	}
+	if len(vdcGroupConfig.ParticipatingOrgVdcs) == 0 {
+		return fmt.Errorf("VDC group %s needs at least one participating VDC", vdcGroupConfig.Name)
+	}
+	for _, participant := range vdcGroupConfig.ParticipatingOrgVdcs {
+		if participant.VdcRef.ID == "" {
+			return fmt.Errorf("participating VDCs of VDC group %s need an ID", vdcGroupConfig.Name)
+		}
+	}
+	switch vdcGroupConfig.Type {
+	case "", types.VdcGroupTypeLocal, types.VdcGroupTypeUniversal:
+	default:
+		return fmt.Errorf("unsupported type '%s' for VDC group %s", vdcGroupConfig.Type, vdcGroupConfig.Name)
+	}
+	return nil
+}
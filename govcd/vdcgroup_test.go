@@ -0,0 +1,176 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// Checks the creation, the membership and the distributed firewall of VDC groups against a fake vCD
+func TestVdcGroup(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	const groupId = "urn:vcloud:vdcGroup:55555555-5555-5555-5555-555555555555"
+	const groupsPath = "/cloudapi/1.0.0/vdcGroups/"
+	const vdc1 = "urn:vcloud:vdc:" + vcdtest.MockVdcId
+	const vdc2 = "urn:vcloud:vdc:66666666-6666-6666-6666-666666666666"
+	const vdc3 = "urn:vcloud:vdc:77777777-7777-7777-7777-777777777777"
+	groupJson := `{"id":"` + groupId + `","name":"group1","orgId":"urn:vcloud:org:` + vcdtest.MockOrgId + `","type":"LOCAL",
+		"networkProviderType":"NSX_T","dfwEnabled":false,"status":"REALIZED",
+		"participatingOrgVdcs":[{"vdcRef":{"id":"` + vdc1 + `"},"orgRef":{"id":"urn:vcloud:org:` + vcdtest.MockOrgId + `"},"status":"REALIZED"}]}`
+	taskResponse := vcdtest.Response{
+		Status: http.StatusAccepted,
+		Header: map[string]string{"Location": "{{server}}" + vcdtest.MockTaskPath},
+	}
+	server.HandleXML(http.MethodGet, vcdtest.MockTaskPath, http.StatusOK,
+		`<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="success" href="{{server}}`+vcdtest.MockTaskPath+`">
+		  <Owner type="application/json" name="group1" id="`+groupId+`" href="{{server}}`+groupsPath+groupId+`"/>
+		</Task>`)
+	server.Handle(http.MethodPost, groupsPath, taskResponse)
+	server.Handle(http.MethodPut, groupsPath+groupId, taskResponse)
+	server.Handle(http.MethodDelete, groupsPath+groupId, taskResponse)
+	server.Handle(http.MethodPut, groupsPath+groupId+"/dfwPolicies", taskResponse)
+	server.HandleJSON(http.MethodGet, groupsPath+groupId, http.StatusOK, groupJson)
+	server.HandleJSON(http.MethodGet, groupsPath+groupId+"/dfwPolicies", http.StatusOK,
+		`{"enabled":true,"defaultPolicy":{"id":"policy-1","name":"Default","enabled":true,"version":{"version":1}}}`)
+	server.HandleJSON(http.MethodGet, groupsPath, http.StatusOK,
+		`{"resultTotal":1,"pageCount":1,"page":1,"pageSize":128,"values":[`+groupJson+`]}`)
+	server.HandleJSON(http.MethodGet, groupsPath+"networkingCandidateVdcs", http.StatusOK,
+		`{"resultTotal":2,"pageCount":1,"page":1,"pageSize":128,"values":[
+		{"id":"`+vdc1+`","name":"`+vcdtest.MockVdcName+`","orgRef":{"id":"urn:vcloud:org:`+vcdtest.MockOrgId+`"},"siteRef":{"id":"urn:vcloud:site:1"},"networkProviderScope":"nsxt1"},
+		{"id":"`+vdc2+`","name":"vdc2","orgRef":{"id":"urn:vcloud:org:`+vcdtest.MockOrgId+`"},"siteRef":{"id":"urn:vcloud:site:1"},"networkProviderScope":"nsxt1"}]}`)
+
+	vcdClient := newMockClient(t, server)
+	adminOrg, err := GetAdminOrgByName(vcdClient, vcdtest.MockOrgName)
+	if err != nil {
+		t.Fatalf("error retrieving admin org: %s", err)
+	}
+
+	candidates, err := adminOrg.GetVdcGroupCandidateVdcs(vdc1, nil)
+	if err != nil {
+		t.Fatalf("error retrieving candidate VDCs: %s", err)
+	}
+	if len(candidates) != 2 || candidates[1].ID != vdc2 {
+		t.Errorf("unexpected candidate VDCs: %#v", candidates)
+	}
+	requests := server.RequestsTo(http.MethodGet, groupsPath+"networkingCandidateVdcs")
+	if len(requests) != 1 || !strings.Contains(requests[0].RawQuery, "filter=_context%3D%3D"+strings.Replace(vdc1, ":", "%3A", -1)+"%3B_context%3D%3DLOCAL") {
+		t.Errorf("unexpected candidate VDCs request: %#v", requests)
+	}
+
+	invalidGroups := []*types.VdcGroup{
+		{Name: "group1"},
+		{Name: "group1", ParticipatingOrgVdcs: []types.ParticipatingOrgVdc{{}}},
+		{Name: "group1", Type: "GLOBAL", ParticipatingOrgVdcs: []types.ParticipatingOrgVdc{NewParticipatingOrgVdc(candidates[0])}},
+	}
+	for _, invalidGroup := range invalidGroups {
+		if _, err = adminOrg.CreateVdcGroup(invalidGroup); err == nil {
+			t.Errorf("expected error creating VDC group %#v", invalidGroup)
+		}
+	}
+	group, err := adminOrg.CreateVdcGroup(&types.VdcGroup{
+		Name:                 "group1",
+		Type:                 types.VdcGroupTypeLocal,
+		ParticipatingOrgVdcs: []types.ParticipatingOrgVdc{NewParticipatingOrgVdc(candidates[0])},
+	})
+	if err != nil {
+		t.Fatalf("error creating VDC group: %s", err)
+	}
+	if group.VdcGroup.ID != groupId || group.Reference().ID != groupId {
+		t.Errorf("unexpected VDC group: %#v", group.VdcGroup)
+	}
+	posts := server.RequestsTo(http.MethodPost, groupsPath)
+	sent := types.VdcGroup{}
+	if len(posts) != 1 || json.Unmarshal([]byte(posts[0].Body), &sent) != nil ||
+		sent.OrgID != "urn:vcloud:org:"+vcdtest.MockOrgId || sent.ParticipatingOrgVdcs[0].NetworkProviderScope != "nsxt1" {
+		t.Errorf("unexpected VDC group creation: %#v", posts)
+	}
+	if posts[0].Header.Get("Accept") != "application/json;version=35.0" ||
+		posts[0].Header.Get("X-VMWARE-VCLOUD-TENANT-CONTEXT") != vcdtest.MockOrgId {
+		t.Errorf("unexpected headers: %v", posts[0].Header)
+	}
+
+	byName, err := adminOrg.GetVdcGroupByName("group1")
+	if err != nil || byName.VdcGroup.ID != groupId {
+		t.Errorf("error retrieving VDC group by name: %v", err)
+	}
+
+	// Membership
+	err = group.AddParticipatingVdcs(vdc3)
+	if err == nil {
+		t.Errorf("expected error adding VDC which is not a candidate")
+	}
+	err = group.AddParticipatingVdcs(vdc1)
+	if err == nil {
+		t.Errorf("expected error adding VDC which already participates")
+	}
+	if len(server.RequestsTo(http.MethodPut, groupsPath+groupId)) != 0 {
+		t.Fatalf("expected no update of the VDC group after invalid additions")
+	}
+	err = group.AddParticipatingVdcs(vdc2)
+	if err != nil {
+		t.Fatalf("error adding VDC to VDC group: %s", err)
+	}
+	puts := server.RequestsTo(http.MethodPut, groupsPath+groupId)
+	sent = types.VdcGroup{}
+	if len(puts) != 1 || json.Unmarshal([]byte(puts[0].Body), &sent) != nil || len(sent.ParticipatingOrgVdcs) != 2 ||
+		sent.ParticipatingOrgVdcs[1].VdcRef.ID != vdc2 || sent.ParticipatingOrgVdcs[1].SiteRef.ID != "urn:vcloud:site:1" {
+		t.Errorf("unexpected VDC group update: %#v", puts)
+	}
+
+	server.ClearRequests()
+	if err = group.RemoveParticipatingVdcs(vdc3); err == nil {
+		t.Errorf("expected error removing VDC which does not participate")
+	}
+	if err = group.RemoveParticipatingVdcs(vdc1); err == nil {
+		t.Errorf("expected error removing the last VDC of the group")
+	}
+	group.VdcGroup.ParticipatingOrgVdcs = append(group.VdcGroup.ParticipatingOrgVdcs, NewParticipatingOrgVdc(candidates[1]))
+	err = group.RemoveParticipatingVdcs(vdc1)
+	if err != nil {
+		t.Fatalf("error removing VDC from VDC group: %s", err)
+	}
+	puts = server.RequestsTo(http.MethodPut, groupsPath+groupId)
+	sent = types.VdcGroup{}
+	if len(puts) != 1 || json.Unmarshal([]byte(puts[0].Body), &sent) != nil || len(sent.ParticipatingOrgVdcs) != 1 ||
+		sent.ParticipatingOrgVdcs[0].VdcRef.ID != vdc2 {
+		t.Errorf("unexpected VDC group update: %#v", puts)
+	}
+
+	// Distributed firewall
+	err = group.ActivateDfw()
+	if err != nil {
+		t.Fatalf("error activating distributed firewall: %s", err)
+	}
+	puts = server.RequestsTo(http.MethodPut, groupsPath+groupId+"/dfwPolicies")
+	if len(puts) != 1 || !strings.Contains(puts[0].Body, `"enabled": true`) {
+		t.Errorf("unexpected distributed firewall activation: %#v", puts)
+	}
+	policies, err := group.GetDfwPolicies()
+	if err != nil {
+		t.Fatalf("error retrieving distributed firewall: %s", err)
+	}
+	if !policies.Enabled || policies.DefaultPolicy == nil || policies.DefaultPolicy.Version.Version != 1 {
+		t.Errorf("unexpected distributed firewall: %#v", policies)
+	}
+
+	err = group.Delete()
+	if err != nil {
+		t.Fatalf("error deleting VDC group: %s", err)
+	}
+	server.HandleJSON(http.MethodGet, groupsPath, http.StatusOK, `{"resultTotal":0,"pageCount":0,"page":1,"pageSize":128,"values":[]}`)
+	_, err = adminOrg.GetVdcGroupByName("group1")
+	if !errors.Is(err, ErrorEntityNotFound) {
+		t.Errorf("expected entity not found error, got %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/util"
+)
+
+// VdcUsageSummary is the utilization of an org VDC, as returned by Vdc.GetUsageSummary
+type VdcUsageSummary struct {
+	VdcName         string
+	VdcHref         string
+	AllocationModel string
+
+	Cpu    ComputeUsage // Usually in MHz
+	Memory ComputeUsage // Usually in MB
+
+	// Only filled for system administrators, who can read the admin view of the VDC
+	ResourceGuaranteedCpu    float64
+	ResourceGuaranteedMemory float64
+	VCpuInMhz                int64
+
+	StorageProfiles []StorageProfileUsage
+	StorageUsedMB   int64 // Total of the storage profiles
+	StorageLimitMB  int64 // Total of the storage profiles, 0 when one of them is unlimited
+
+	VAppCount        int
+	VmCount          int
+	PoweredOnVmCount int
+	VmCpus           int   // Total of the CPUs of the VMs
+	VmMemoryMB       int64 // Total of the memory of the VMs
+}
+
+// ComputeUsage is the capacity and the usage of the CPU or the memory of a VDC
+type ComputeUsage struct {
+	Units     string
+	Allocated int64
+	Limit     int64
+	Reserved  int64
+	Used      int64
+	Overhead  int64
+}
+
+// StorageProfileUsage is the usage of a storage profile of a VDC
+type StorageProfileUsage struct {
+	Name      string
+	Href      string
+	Enabled   bool
+	IsDefault bool
+	UsedMB    int64
+	LimitMB   int64 // 0 when unlimited
+}
+
+// GetUsageSummary returns the compute capacity, the storage profile usage and the VM counts of
+// the VDC in one report. The VDC is refreshed, and the storage profiles and VMs are retrieved with
+// the query service. System administrators also get the guarantees of the admin view.
+func (vdc *Vdc) GetUsageSummary() (*VdcUsageSummary, error) {
+	util.Logger.Printf("[TRACE] Vdc.GetUsageSummary - VDC %s", vdc.Vdc.Name)
+	err := vdc.Refresh()
+	if err != nil {
+		return nil, fmt.Errorf("error refreshing VDC: %s", err)
+	}
+
+	summary := &VdcUsageSummary{
+		VdcName:         vdc.Vdc.Name,
+		VdcHref:         vdc.Vdc.HREF,
+		AllocationModel: vdc.Vdc.AllocationModel,
+		StorageProfiles: []StorageProfileUsage{},
+	}
+	for _, computeCapacity := range vdc.Vdc.ComputeCapacity {
+		summary.Cpu = newComputeUsage(computeCapacity.CPU)
+		summary.Memory = newComputeUsage(computeCapacity.Memory)
+	}
+	for _, resourceEntities := range vdc.Vdc.ResourceEntities {
+		for _, resourceEntity := range resourceEntities.ResourceEntity {
+			if resourceEntity.Type == types.MimeVApp {
+				summary.VAppCount++
+			}
+		}
+	}
+
+	if vdc.client.IsSysAdmin {
+		adminVdc := NewAdminVdc(vdc.client)
+		_, err = vdc.client.ExecuteRequest(getAdminHref(vdc.Vdc.HREF), http.MethodGet,
+			"", "error retrieving admin VDC: %s", nil, adminVdc.AdminVdc)
+		if err != nil {
+			return nil, err
+		}
+		summary.ResourceGuaranteedCpu = adminVdc.AdminVdc.ResourceGuaranteedCpu
+		summary.ResourceGuaranteedMemory = adminVdc.AdminVdc.ResourceGuaranteedMemory
+		summary.VCpuInMhz = adminVdc.AdminVdc.VCpuInMhz
+	}
+
+	err = vdc.addStorageProfileUsage(summary)
+	if err != nil {
+		return nil, err
+	}
+	err = vdc.addVmUsage(summary)
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// addStorageProfileUsage adds the usage of the storage profiles of the VDC to summary
+func (vdc *Vdc) addStorageProfileUsage(summary *VdcUsageSummary) error {
+	queryType := types.QtOrgVdcStorageProfile
+	if vdc.client.IsSysAdmin {
+		queryType = types.QtAdminOrgVdcStorageProfile
+	}
+	results, err := vdc.client.QueryAllPages(queryType, &QueryOptions{
+		Filter: NewQueryFilter().Equal("vdc", vdc.Vdc.HREF),
+	})
+	if err != nil {
+		return fmt.Errorf("error querying storage profiles of VDC %s: %s", vdc.Vdc.Name, err)
+	}
+	records := results.Results.OrgVdcStorageProfileRecord
+	if vdc.client.IsSysAdmin {
+		records = results.Results.AdminOrgVdcStorageProfileRecord
+	}
+
+	unlimited := false
+	for _, record := range records {
+		summary.StorageProfiles = append(summary.StorageProfiles, StorageProfileUsage{
+			Name:      record.Name,
+			Href:      record.HREF,
+			Enabled:   record.IsEnabled,
+			IsDefault: record.IsDefaultStorageProfile,
+			UsedMB:    int64(record.StorageUsedMB),
+			LimitMB:   int64(record.StorageLimitMB),
+		})
+		summary.StorageUsedMB += int64(record.StorageUsedMB)
+		summary.StorageLimitMB += int64(record.StorageLimitMB)
+		unlimited = unlimited || record.StorageLimitMB == 0
+	}
+	if unlimited {
+		summary.StorageLimitMB = 0
+	}
+	return nil
+}
+
+// addVmUsage adds the VM counts and the resources of the VMs of the VDC to summary.
+// VMs of vApp templates are not counted.
+func (vdc *Vdc) addVmUsage(summary *VdcUsageSummary) error {
+	options := &QueryOptions{
+		Filter: NewQueryFilter().Equal("vdc", vdc.Vdc.HREF).Equal("isVAppTemplate", "false"),
+	}
+	var records []*types.QueryResultVMRecordType
+	var err error
+	if vdc.client.IsSysAdmin {
+		records, err = vdc.client.QueryAdminVms(options)
+	} else {
+		records, err = vdc.client.QueryVms(options)
+	}
+	if err != nil {
+		return fmt.Errorf("error querying VMs of VDC %s: %s", vdc.Vdc.Name, err)
+	}
+
+	for _, record := range records {
+		summary.VmCount++
+		if record.Status == "POWERED_ON" {
+			summary.PoweredOnVmCount++
+		}
+		summary.VmCpus += record.Cpus
+		summary.VmMemoryMB += int64(record.MemoryMB)
+	}
+	return nil
+}
+
+// newComputeUsage copies a capacity, which can be nil
+func newComputeUsage(capacity *types.CapacityWithUsage) ComputeUsage {
+	if capacity == nil {
+		return ComputeUsage{}
+	}
+	return ComputeUsage{
+		Units:     capacity.Units,
+		Allocated: capacity.Allocated,
+		Limit:     capacity.Limit,
+		Reserved:  capacity.Reserved,
+		Used:      capacity.Used,
+		Overhead:  capacity.Overhead,
+	}
+}
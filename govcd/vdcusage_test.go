@@ -0,0 +1,79 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+const usageVdcXml = `<?xml version="1.0" encoding="UTF-8"?>
+<Vdc xmlns="http://www.vmware.com/vcloud/v1.5" status="1" name="` + vcdtest.MockVdcName + `" href="{{server}}` + vcdtest.MockVdcPath + `">
+  <AllocationModel>AllocationPool</AllocationModel>
+  <ComputeCapacity>
+    <Cpu><Units>MHz</Units><Allocated>4000</Allocated><Limit>4000</Limit><Reserved>2000</Reserved><Used>1000</Used></Cpu>
+    <Memory><Units>MB</Units><Allocated>8192</Allocated><Limit>8192</Limit><Reserved>4096</Reserved><Used>3072</Used></Memory>
+  </ComputeCapacity>
+  <ResourceEntities>
+    <ResourceEntity type="application/vnd.vmware.vcloud.vApp+xml" name="app1" href="{{server}}/api/vApp/vapp-1"/>
+    <ResourceEntity type="application/vnd.vmware.vcloud.vAppTemplate+xml" name="template1" href="{{server}}/api/vAppTemplate/vappTemplate-1"/>
+  </ResourceEntities>
+  <IsEnabled>true</IsEnabled>
+</Vdc>`
+
+// Both queries get the same response: each one only reads its own records
+const usageQueryXml = `<?xml version="1.0" encoding="UTF-8"?>
+<QueryResultRecords xmlns="http://www.vmware.com/vcloud/v1.5" total="2" page="1" pageSize="25">
+  <OrgVdcStorageProfileRecord name="gold" isEnabled="true" isDefaultStorageProfile="true" storageUsedMB="1024" storageLimitMB="10240"/>
+  <OrgVdcStorageProfileRecord name="silver" isEnabled="true" storageUsedMB="512" storageLimitMB="0"/>
+  <VMRecord name="vm1" status="POWERED_ON" numberOfCpus="2" memoryMB="2048"/>
+  <VMRecord name="vm2" status="POWERED_OFF" numberOfCpus="1" memoryMB="1024"/>
+</QueryResultRecords>`
+
+// Checks that GetUsageSummary combines the VDC capacity, the storage profiles and the VMs
+func TestVdc_GetUsageSummary(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	server.HandleXML(http.MethodGet, vcdtest.MockVdcPath, http.StatusOK, usageVdcXml)
+	server.HandleXML(http.MethodGet, "/api/query", http.StatusOK, usageQueryXml)
+
+	vcdClient := newMockClient(t, server)
+	vdc := NewVdc(&vcdClient.Client)
+	vdc.Vdc.HREF = server.URL() + vcdtest.MockVdcPath
+
+	summary, err := vdc.GetUsageSummary()
+	if err != nil {
+		t.Fatalf("error getting usage summary: %s", err)
+	}
+	if summary.AllocationModel != "AllocationPool" || summary.Cpu.Used != 1000 || summary.Memory.Reserved != 4096 ||
+		summary.Memory.Units != "MB" {
+		t.Errorf("unexpected compute usage: %+v %+v", summary.Cpu, summary.Memory)
+	}
+	if len(summary.StorageProfiles) != 2 || !summary.StorageProfiles[0].IsDefault ||
+		summary.StorageUsedMB != 1536 || summary.StorageLimitMB != 0 {
+		t.Errorf("unexpected storage usage: %+v", summary)
+	}
+	if summary.VAppCount != 1 || summary.VmCount != 2 || summary.PoweredOnVmCount != 1 ||
+		summary.VmCpus != 3 || summary.VmMemoryMB != 3072 {
+		t.Errorf("unexpected VM usage: %+v", summary)
+	}
+
+	queries := server.RequestsTo(http.MethodGet, "/api/query")
+	if len(queries) != 2 {
+		t.Fatalf("expected 2 queries, got %d", len(queries))
+	}
+	for i, queryType := range []string{types.QtOrgVdcStorageProfile, types.QtVm} {
+		if !strings.Contains(queries[i].RawQuery, "type="+queryType) {
+			t.Errorf("expected query of type %s, got %s", queryType, queries[i].RawQuery)
+		}
+		if !strings.Contains(queries[i].RawQuery, "vdc==") {
+			t.Errorf("expected query filtered by VDC, got %s", queries[i].RawQuery)
+		}
+	}
+}
@@ -6,10 +6,13 @@ package govcd
 
 import (
 	"fmt"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/kr/pretty"
 
@@ -58,15 +61,16 @@ func (vm *VM) Refresh() error {
 
 	refreshUrl := vm.VM.HREF
 
-	// Empty struct before a new unmarshal, otherwise we end up with duplicate
-	// elements in slices.
-	vm.VM = &types.VM{}
+	// Unmarshal into an empty struct, otherwise we end up with duplicate elements in
+	// slices, and keep the current one when the request fails.
+	refreshed := &types.VM{}
 
-	_, err := vm.client.ExecuteRequest(refreshUrl, http.MethodGet,
-		"", "error refreshing VM: %s", nil, vm.VM)
-
-	// The request was successful
-	return err
+	err := vm.client.executeCachedRequest(refreshUrl, "", "error refreshing VM: %s", refreshed)
+	if err != nil {
+		return err
+	}
+	vm.VM = refreshed
+	return nil
 }
 
 // AppendNetworkConnection appends a network connection from OrgVDCNetwork to VM
@@ -117,8 +121,8 @@ func (vm *VM) GetNetworkConnectionSection() (*types.NetworkConnectionSection, er
 		return networkConnectionSection, fmt.Errorf("cannot refresh, Object is empty")
 	}
 
-	_, err := vm.client.ExecuteRequest(vm.VM.HREF+"/networkConnectionSection/", http.MethodGet,
-		types.MimeNetworkConnectionSection, "error retrieving network connection: %s", nil, networkConnectionSection)
+	err := vm.client.executeCachedSectionRequest(vm.VM.HREF, "NetworkConnectionSection",
+		types.MimeNetworkConnectionSection, "error retrieving network connection: %s", networkConnectionSection)
 
 	// The request was successful
 	return networkConnectionSection, err
@@ -156,6 +160,44 @@ func (vm *VM) PowerOff() (Task, error) {
 		"", "error powering off VM: %s", nil)
 }
 
+// Shutdown shuts down the guest OS of the VM. VMware Tools need to be running in the VM
+func (vm *VM) Shutdown() (Task, error) {
+	return vm.powerAction("/power/action/shutdown", "error shutting down VM: %s")
+}
+
+// Reboot reboots the guest OS of the VM. VMware Tools need to be running in the VM
+func (vm *VM) Reboot() (Task, error) {
+	return vm.powerAction("/power/action/reboot", "error rebooting VM: %s")
+}
+
+// Reset resets the VM, like a hardware reset
+func (vm *VM) Reset() (Task, error) {
+	return vm.powerAction("/power/action/reset", "error resetting VM: %s")
+}
+
+// Suspend suspends the VM
+func (vm *VM) Suspend() (Task, error) {
+	return vm.powerAction("/power/action/suspend", "error suspending VM: %s")
+}
+
+// DiscardSuspendedState discards the suspended state of a suspended VM, which is left powered off
+func (vm *VM) DiscardSuspendedState() (Task, error) {
+	return vm.powerAction("/action/discardSuspendedState", "error discarding suspended state of VM: %s")
+}
+
+// powerAction posts to the action at actionPath, relative to the VM HREF, and returns the task
+func (vm *VM) powerAction(actionPath, errorMessage string) (Task, error) {
+	apiEndpoint, err := url.ParseRequestURI(vm.VM.HREF)
+	if err != nil {
+		return Task{}, fmt.Errorf(errorMessage, err)
+	}
+	apiEndpoint.Path += actionPath
+
+	// Return the task
+	return vm.client.ExecuteTaskRequest(apiEndpoint.String(), http.MethodPost,
+		"", errorMessage, nil)
+}
+
 // Sets number of available virtual logical processors
 // (i.e. CPUs x cores per socket)
 // Cpu cores count is inherited from template.
@@ -175,6 +217,20 @@ func (vm *VM) ChangeCPUCountWithCore(virtualCpuCount int, coresPerSocket *int) (
 		return Task{}, fmt.Errorf("error refreshing VM before running customization: %v", err)
 	}
 
+	newCpu := vm.newCpuItem(virtualCpuCount, coresPerSocket)
+
+	apiEndpoint, _ := url.ParseRequestURI(vm.VM.HREF)
+	apiEndpoint.Path += "/virtualHardwareSection/cpu"
+
+	// Return the task
+	return vm.client.ExecuteTaskRequest(apiEndpoint.String(), http.MethodPut,
+		types.MimeRasdItem, "error changing CPU count: %s", newCpu)
+
+}
+
+// newCpuItem returns the CPU item of the VM with the given number of virtual CPUs, keeping the
+// reservation, limit and shares of its current CPU item
+func (vm *VM) newCpuItem(virtualCpuCount int, coresPerSocket *int) *types.OVFItem {
 	newCpu := &types.OVFItem{
 		XmlnsRasd:       types.XMLNamespaceRASD,
 		XmlnsVCloud:     types.XMLNamespaceVCloud,
@@ -197,14 +253,12 @@ func (vm *VM) ChangeCPUCountWithCore(virtualCpuCount int, coresPerSocket *int) (
 			Type: types.MimeRasdItem,
 		},
 	}
-
-	apiEndpoint, _ := url.ParseRequestURI(vm.VM.HREF)
-	apiEndpoint.Path += "/virtualHardwareSection/cpu"
-
-	// Return the task
-	return vm.client.ExecuteTaskRequest(apiEndpoint.String(), http.MethodPut,
-		types.MimeRasdItem, "error changing CPU count: %s", newCpu)
-
+	if current := vm.hardwareItem(types.ResourceTypeProcessor); current != nil {
+		newCpu.Reservation = current.Reservation
+		newCpu.Limit = current.Limit
+		newCpu.Weight = current.Weight
+	}
+	return newCpu
 }
 
 func (vm *VM) updateNicParameters(networks []map[string]interface{}, networkSection *types.NetworkConnectionSection) {
@@ -299,6 +353,222 @@ func (vm *VM) ChangeNetworkConfig(networks []map[string]interface{}) (Task, erro
 		types.MimeNetworkConnectionSection, "error changing network config: %s", networkSection)
 }
 
+// UpdateNetworkConnectionSection replaces the network connections of the VM with the given ones,
+// waits for the task to complete and refreshes the VM. The networks of the connections must be
+// networks of the parent vApp, or types.NoneNetwork for NICs which are not attached to any network.
+// NICs can be added and removed while the VM is powered on, when the guest OS supports it.
+func (vm *VM) UpdateNetworkConnectionSection(networkConnectionSection *types.NetworkConnectionSection) error {
+	if vm.VM.HREF == "" {
+		return fmt.Errorf("cannot update network connections, Object is empty")
+	}
+	vapp, err := vm.getParentVApp()
+	if err != nil {
+		return err
+	}
+	networkConfig, err := vapp.GetNetworkConfig()
+	if err != nil {
+		return err
+	}
+	err = validateNetworkConnectionSection(networkConnectionSection, networkConfig)
+	if err != nil {
+		return fmt.Errorf("invalid network connections for VM %s: %s", vm.VM.Name, err)
+	}
+
+	payload := *networkConnectionSection
+	payload.Xmlns = types.XMLNamespaceVCloud
+	payload.Ovf = types.XMLNamespaceOVF
+	payload.Type = types.MimeNetworkConnectionSection
+	payload.Link = nil
+	if payload.Info == "" {
+		payload.Info = "Specifies the available VM network connections"
+	}
+
+	apiEndpoint, err := url.ParseRequestURI(vm.VM.HREF)
+	if err != nil {
+		return fmt.Errorf("error getting VM href: %v", err)
+	}
+	apiEndpoint.Path += "/networkConnectionSection/"
+
+	task, err := vm.client.ExecuteTaskRequest(apiEndpoint.String(), http.MethodPut,
+		types.MimeNetworkConnectionSection, "error updating VM network connections: %s", &payload)
+	if err != nil {
+		return err
+	}
+	err = task.WaitTaskCompletion()
+	if err != nil {
+		return fmt.Errorf("couldn't finish updating network connections of VM %s: %s", vm.VM.Name, err)
+	}
+	return vm.Refresh()
+}
+
+// AddNetworkConnection adds a NIC to the VM, in the slot following the last used one, with the
+// given network, IP allocation and adapter type (e.g. types.NetworkAdapterTypeVmxnet3, E1000E or
+// Sriov). The new NIC becomes the primary one when it is the only NIC or IsPrimary is set.
+func (vm *VM) AddNetworkConnection(connection VMNetworkConnection) error {
+	networkConnectionSection, err := vm.GetNetworkConnectionSection()
+	if err != nil {
+		return err
+	}
+
+	allocationMode := connection.AllocationMode
+	if allocationMode == "" {
+		allocationMode = types.IPAllocationModePool
+	}
+	index := 0
+	for _, networkConnection := range networkConnectionSection.NetworkConnection {
+		if networkConnection.NetworkConnectionIndex >= index {
+			index = networkConnection.NetworkConnectionIndex + 1
+		}
+	}
+	if connection.IsPrimary || len(networkConnectionSection.NetworkConnection) == 0 {
+		networkConnectionSection.PrimaryNetworkConnectionIndex = index
+	}
+	networkConnectionSection.NetworkConnection = append(networkConnectionSection.NetworkConnection,
+		&types.NetworkConnection{
+			Network:                 connection.Network,
+			NetworkConnectionIndex:  index,
+			IsConnected:             !connection.Disconnected,
+			IPAddressAllocationMode: allocationMode,
+			IPAddress:               connection.IP,
+			NetworkAdapterType:      connection.AdapterType,
+		},
+	)
+	return vm.UpdateNetworkConnectionSection(networkConnectionSection)
+}
+
+// RemoveNetworkConnection removes the NIC in the given slot. When it was the primary NIC, the NIC
+// in the lowest remaining slot becomes the primary one.
+func (vm *VM) RemoveNetworkConnection(index int) error {
+	networkConnectionSection, err := vm.GetNetworkConnectionSection()
+	if err != nil {
+		return err
+	}
+	_, err = findNetworkConnection(networkConnectionSection, index)
+	if err != nil {
+		return err
+	}
+
+	var remaining []*types.NetworkConnection
+	for _, networkConnection := range networkConnectionSection.NetworkConnection {
+		if networkConnection.NetworkConnectionIndex != index {
+			remaining = append(remaining, networkConnection)
+		}
+	}
+	networkConnectionSection.NetworkConnection = remaining
+	if networkConnectionSection.PrimaryNetworkConnectionIndex == index {
+		networkConnectionSection.PrimaryNetworkConnectionIndex = 0
+		for position, networkConnection := range remaining {
+			if position == 0 || networkConnection.NetworkConnectionIndex < networkConnectionSection.PrimaryNetworkConnectionIndex {
+				networkConnectionSection.PrimaryNetworkConnectionIndex = networkConnection.NetworkConnectionIndex
+			}
+		}
+	}
+	return vm.UpdateNetworkConnectionSection(networkConnectionSection)
+}
+
+// SetNetworkConnectionAllocationMode changes the IP allocation mode of the NIC in the given slot.
+// ip is only used, and required, with types.IPAllocationModeManual.
+func (vm *VM) SetNetworkConnectionAllocationMode(index int, allocationMode, ip string) error {
+	networkConnectionSection, err := vm.GetNetworkConnectionSection()
+	if err != nil {
+		return err
+	}
+	networkConnection, err := findNetworkConnection(networkConnectionSection, index)
+	if err != nil {
+		return err
+	}
+	networkConnection.IPAddressAllocationMode = allocationMode
+	networkConnection.IPAddress = ip
+	return vm.UpdateNetworkConnectionSection(networkConnectionSection)
+}
+
+// SetNetworkConnectionMacAddress sets the MAC address of the NIC in the given slot. An empty
+// address clears the current one, and lets vCD generate a new one.
+func (vm *VM) SetNetworkConnectionMacAddress(index int, macAddress string) error {
+	networkConnectionSection, err := vm.GetNetworkConnectionSection()
+	if err != nil {
+		return err
+	}
+	networkConnection, err := findNetworkConnection(networkConnectionSection, index)
+	if err != nil {
+		return err
+	}
+	networkConnection.MACAddress = macAddress
+	return vm.UpdateNetworkConnectionSection(networkConnectionSection)
+}
+
+// findNetworkConnection returns the network connection of the NIC in the given slot
+func findNetworkConnection(networkConnectionSection *types.NetworkConnectionSection, index int) (*types.NetworkConnection, error) {
+	for _, networkConnection := range networkConnectionSection.NetworkConnection {
+		if networkConnection.NetworkConnectionIndex == index {
+			return networkConnection, nil
+		}
+	}
+	return nil, fmt.Errorf("no network connection found with index %d", index)
+}
+
+// validateNetworkConnectionSection checks the network connections of a VM against the networks
+// of its vApp
+func validateNetworkConnectionSection(networkConnectionSection *types.NetworkConnectionSection, networkConfig *types.NetworkConfigSection) error {
+	if networkConnectionSection == nil {
+		return fmt.Errorf("network connection section can not be empty")
+	}
+	vappNetworks := make(map[string]bool)
+	for _, vappNetwork := range networkConfig.NetworkConfig {
+		vappNetworks[vappNetwork.NetworkName] = true
+	}
+
+	indexes := make(map[int]bool)
+	for _, connection := range networkConnectionSection.NetworkConnection {
+		index := connection.NetworkConnectionIndex
+		if index < 0 || indexes[index] {
+			return fmt.Errorf("network connection index %d is invalid or used more than once", index)
+		}
+		indexes[index] = true
+		if connection.Network == "" {
+			return fmt.Errorf("network connection %d has no network", index)
+		}
+		if connection.Network != types.NoneNetwork && !vappNetworks[connection.Network] {
+			return fmt.Errorf("network connection %d uses network %s, which is not a vApp network", index, connection.Network)
+		}
+		if !validIPAllocationModes[connection.IPAddressAllocationMode] {
+			return fmt.Errorf("network connection %d has an invalid IP allocation mode '%s'", index,
+				connection.IPAddressAllocationMode)
+		}
+		if connection.IPAddressAllocationMode == types.IPAllocationModeManual && connection.IPAddress == "" {
+			return fmt.Errorf("network connection %d needs an IP address with the %s allocation mode", index,
+				types.IPAllocationModeManual)
+		}
+		if connection.MACAddress != "" {
+			_, err := net.ParseMAC(connection.MACAddress)
+			if err != nil {
+				return fmt.Errorf("network connection %d has an invalid MAC address: %s", index, err)
+			}
+		}
+	}
+	if len(indexes) > 0 && !indexes[networkConnectionSection.PrimaryNetworkConnectionIndex] {
+		return fmt.Errorf("primary network connection index %d is not used by any network connection",
+			networkConnectionSection.PrimaryNetworkConnectionIndex)
+	}
+	return nil
+}
+
+// getParentVApp retrieves the vApp containing the VM
+func (vm *VM) getParentVApp() (*VApp, error) {
+	for _, link := range vm.VM.Link {
+		if link.Rel == "up" && link.Type == types.MimeVApp {
+			vapp := NewVApp(vm.client)
+			vapp.VApp.HREF = link.HREF
+			err := vapp.Refresh()
+			if err != nil {
+				return nil, fmt.Errorf("error retrieving parent vApp of VM %s: %s", vm.VM.Name, err)
+			}
+			return vapp, nil
+		}
+	}
+	return nil, fmt.Errorf("could not find the parent vApp of VM %s", vm.VM.Name)
+}
+
 func (vm *VM) ChangeMemorySize(size int) (Task, error) {
 
 	err := vm.Refresh()
@@ -306,6 +576,19 @@ func (vm *VM) ChangeMemorySize(size int) (Task, error) {
 		return Task{}, fmt.Errorf("error refreshing VM before running customization: %v", err)
 	}
 
+	newMem := vm.newMemoryItem(size)
+
+	apiEndpoint, _ := url.ParseRequestURI(vm.VM.HREF)
+	apiEndpoint.Path += "/virtualHardwareSection/memory"
+
+	// Return the task
+	return vm.client.ExecuteTaskRequest(apiEndpoint.String(), http.MethodPut,
+		types.MimeRasdItem, "error changing memory size: %s", newMem)
+}
+
+// newMemoryItem returns the memory item of the VM with the given size in MB, keeping the
+// reservation, limit and shares of its current memory item
+func (vm *VM) newMemoryItem(size int) *types.OVFItem {
 	newMem := &types.OVFItem{
 		XmlnsRasd:       types.XMLNamespaceRASD,
 		XmlnsVCloud:     types.XMLNamespaceVCloud,
@@ -326,45 +609,226 @@ func (vm *VM) ChangeMemorySize(size int) (Task, error) {
 			Type: types.MimeRasdItem,
 		},
 	}
+	if current := vm.hardwareItem(types.ResourceTypeMemory); current != nil {
+		newMem.Reservation = current.Reservation
+		newMem.Limit = current.Limit
+		newMem.Weight = current.Weight
+	}
+	return newMem
+}
 
-	apiEndpoint, _ := url.ParseRequestURI(vm.VM.HREF)
-	apiEndpoint.Path += "/virtualHardwareSection/memory"
-
-	// Return the task
-	return vm.client.ExecuteTaskRequest(apiEndpoint.String(), http.MethodPut,
-		types.MimeRasdItem, "error changing memory size: %s", newMem)
+// hardwareItem returns the first item of the virtual hardware section of the VM with the given
+// resource type, or nil
+func (vm *VM) hardwareItem(resourceType int) *types.VirtualHardwareItem {
+	if vm.VM.VirtualHardwareSection == nil {
+		return nil
+	}
+	for _, item := range vm.VM.VirtualHardwareSection.Item {
+		if item.ResourceType == resourceType {
+			return item
+		}
+	}
+	return nil
 }
 
 func (vm *VM) RunCustomizationScript(computername, script string) (Task, error) {
 	return vm.Customize(computername, script, false)
 }
 
+// Customize enables the guest customization of the VM with the given computer name, script and
+// Windows SID change, keeping the other customization settings, and returns the update task.
+// The customization is applied at the next power on.
 func (vm *VM) Customize(computername, script string, changeSid bool) (Task, error) {
-	err := vm.Refresh()
+	section, err := vm.GetGuestCustomizationSection()
 	if err != nil {
-		return Task{}, fmt.Errorf("error refreshing VM before running customization: %v", err)
+		return Task{}, fmt.Errorf("error retrieving VM guest customization before running customization: %s", err)
 	}
+	section.Enabled = true
+	section.ComputerName = computername
+	section.CustomizationScript = script
+	section.ChangeSid = changeSid
+	return vm.updateGuestCustomizationSection(section)
+}
 
-	vu := &types.GuestCustomizationSection{
-		Ovf:   types.XMLNamespaceOVF,
-		Xsi:   types.XMLNamespaceXSI,
-		Xmlns: types.XMLNamespaceVCloud,
+// GetGuestCustomizationSection retrieves the guest customization settings of the VM
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/GET-GuestCustomizationSection.html
+func (vm *VM) GetGuestCustomizationSection() (*types.GuestCustomizationSection, error) {
+	if vm.VM.HREF == "" {
+		return nil, fmt.Errorf("cannot retrieve guest customization, VM HREF is unset")
+	}
+	section := &types.GuestCustomizationSection{}
+	err := vm.client.executeCachedSectionRequest(vm.VM.HREF, "GuestCustomizationSection",
+		types.MimeGuestCustomizationSection, "error retrieving guest customization section: %s", section)
+	if err != nil {
+		return nil, err
+	}
+	return section, nil
+}
 
-		HREF:                vm.VM.HREF,
-		Type:                types.MimeGuestCustomizationSection,
-		Info:                "Specifies Guest OS Customization Settings",
-		Enabled:             true,
-		ComputerName:        computername,
-		CustomizationScript: script,
-		ChangeSid:           false,
+// SetGuestCustomizationSection replaces the guest customization settings of the VM with the given
+// ones, waits for the update and returns the settings stored by vCD. All the settings are sent:
+// the section is best retrieved with GetGuestCustomizationSection and changed.
+// The administrator password is either generated (AdminPasswordAuto) or the given AdminPassword.
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/PUT-GuestCustomizationSection.html
+func (vm *VM) SetGuestCustomizationSection(section *types.GuestCustomizationSection) (*types.GuestCustomizationSection, error) {
+	err := validateGuestCustomizationSection(section)
+	if err != nil {
+		return nil, err
+	}
+	task, err := vm.updateGuestCustomizationSection(section)
+	if err != nil {
+		return nil, err
+	}
+	err = task.WaitTaskCompletion()
+	if err != nil {
+		return nil, fmt.Errorf("error updating guest customization section: %s", err)
 	}
+	return vm.GetGuestCustomizationSection()
+}
 
-	apiEndpoint, _ := url.ParseRequestURI(vm.VM.HREF)
-	apiEndpoint.Path += "/guestCustomizationSection/"
+// updateGuestCustomizationSection sends the guest customization settings of the VM, leaving
+// the given section unchanged
+func (vm *VM) updateGuestCustomizationSection(section *types.GuestCustomizationSection) (Task, error) {
+	if vm.VM.HREF == "" {
+		return Task{}, fmt.Errorf("cannot update guest customization, VM HREF is unset")
+	}
+	payload := *section
+	payload.Ovf = types.XMLNamespaceOVF
+	payload.Xsi = types.XMLNamespaceXSI
+	payload.Xmlns = types.XMLNamespaceVCloud
+	payload.HREF = vm.VM.HREF
+	payload.Type = types.MimeGuestCustomizationSection
+	payload.Link = nil
+	if payload.Info == "" {
+		payload.Info = "Specifies Guest OS Customization Settings"
+	}
 
-	// Return the task
-	return vm.client.ExecuteTaskRequest(apiEndpoint.String(), http.MethodPut,
-		types.MimeGuestCustomizationSection, "error customizing VM: %s", vu)
+	return vm.client.ExecuteTaskRequest(vm.VM.HREF+"/guestCustomizationSection/", http.MethodPut,
+		types.MimeGuestCustomizationSection, "error customizing VM: %s", &payload)
+}
+
+// validateGuestCustomizationSection checks the consistency of the administrator and domain settings
+func validateGuestCustomizationSection(section *types.GuestCustomizationSection) error {
+	if section == nil {
+		return fmt.Errorf("guest customization section is required")
+	}
+	if section.AdminPasswordAuto && section.AdminPassword != "" {
+		return fmt.Errorf("an administrator password cannot be given when it is generated automatically")
+	}
+	if section.AdminAutoLogonEnabled && (section.AdminAutoLogonCount < 1 || section.AdminAutoLogonCount > 100) {
+		return fmt.Errorf("administrator auto logon count must be between 1 and 100, got %d", section.AdminAutoLogonCount)
+	}
+	if !section.AdminAutoLogonEnabled && section.AdminAutoLogonCount != 0 {
+		return fmt.Errorf("administrator auto logon count must be 0 when auto logon is disabled")
+	}
+	if section.JoinDomainEnabled && !section.UseOrgSettings && section.DomainName == "" {
+		return fmt.Errorf("a domain name is required to join a domain without the org settings")
+	}
+	return nil
+}
+
+// GetProductSectionList retrieves the product section of the VM, whose properties are the OVF
+// environment properties (guest properties) passed to the guest OS
+func (vm *VM) GetProductSectionList() (*types.ProductSectionList, error) {
+	if vm.VM.HREF == "" {
+		return nil, fmt.Errorf("cannot retrieve product section, VM HREF is unset")
+	}
+	productSectionList := &types.ProductSectionList{}
+	_, err := vm.client.ExecuteRequest(vm.VM.HREF+"/productSections/", http.MethodGet,
+		types.MimeProductSection, "error retrieving product section: %s", nil, productSectionList)
+	if err != nil {
+		return nil, err
+	}
+	return productSectionList, nil
+}
+
+// SetProductSectionList replaces the product section of the VM with the given one, waits for the
+// update and returns the product section stored by vCD. Properties with new keys are added, and the
+// properties missing from the given section are removed: the section is best retrieved with
+// GetProductSectionList and changed. Properties without type are sent as strings.
+func (vm *VM) SetProductSectionList(productSectionList *types.ProductSectionList) (*types.ProductSectionList, error) {
+	if vm.VM.HREF == "" {
+		return nil, fmt.Errorf("cannot update product section, VM HREF is unset")
+	}
+	if productSectionList == nil || productSectionList.ProductSection == nil {
+		return nil, fmt.Errorf("product section can not be empty")
+	}
+
+	productSection := &types.ProductSection{Info: productSectionList.ProductSection.Info}
+	keys := make(map[string]bool)
+	for _, property := range productSectionList.ProductSection.Property {
+		if property == nil || property.Key == "" {
+			return nil, fmt.Errorf("product section properties need a key")
+		}
+		if keys[property.Key] {
+			return nil, fmt.Errorf("product section property %s is used more than once", property.Key)
+		}
+		keys[property.Key] = true
+		newProperty := *property
+		if newProperty.Type == "" {
+			newProperty.Type = "string"
+		}
+		productSection.Property = append(productSection.Property, &newProperty)
+	}
+	if productSection.Info == "" {
+		productSection.Info = "Custom properties"
+	}
+	payload := &types.ProductSectionList{
+		Xmlns:          types.XMLNamespaceVCloud,
+		Ovf:            types.XMLNamespaceOVF,
+		ProductSection: productSection,
+	}
+
+	task, err := vm.client.ExecuteTaskRequest(vm.VM.HREF+"/productSections/", http.MethodPut,
+		types.MimeProductSection, "error updating product section: %s", payload)
+	if err != nil {
+		return nil, err
+	}
+	err = task.WaitTaskCompletion()
+	if err != nil {
+		return nil, fmt.Errorf("error updating product section: %s", err)
+	}
+	return vm.GetProductSectionList()
+}
+
+// GetVmCapabilities retrieves the capabilities of the VM, i.e. whether memory and CPUs can be
+// added while it is powered on
+func (vm *VM) GetVmCapabilities() (*types.VMCapabilities, error) {
+	if vm.VM.HREF == "" {
+		return nil, fmt.Errorf("cannot retrieve VM capabilities, VM HREF is unset")
+	}
+	capabilities := &types.VMCapabilities{}
+	_, err := vm.client.ExecuteRequest(vm.VM.HREF+"/vmCapabilities/", http.MethodGet,
+		types.MimeVmCapabilities, "error retrieving VM capabilities: %s", nil, capabilities)
+	if err != nil {
+		return nil, err
+	}
+	return capabilities, nil
+}
+
+// UpdateVmCapabilities enables or disables the hot add of memory and CPUs, which not all the guest
+// OSes support, waits for the update and returns the capabilities stored by vCD. The VM needs to
+// be powered off to change them.
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/PUT-VmCapabilitiesSection.html
+func (vm *VM) UpdateVmCapabilities(memoryHotAdd, cpuHotAdd bool) (*types.VMCapabilities, error) {
+	if vm.VM.HREF == "" {
+		return nil, fmt.Errorf("cannot update VM capabilities, VM HREF is unset")
+	}
+	payload := &types.VMCapabilities{
+		Xmlns:               types.XMLNamespaceVCloud,
+		MemoryHotAddEnabled: memoryHotAdd,
+		CPUHotAddEnabled:    cpuHotAdd,
+	}
+	task, err := vm.client.ExecuteTaskRequest(vm.VM.HREF+"/vmCapabilities/", http.MethodPut,
+		types.MimeVmCapabilities, "error updating VM capabilities: %s", payload)
+	if err != nil {
+		return nil, err
+	}
+	err = task.WaitTaskCompletion()
+	if err != nil {
+		return nil, fmt.Errorf("error updating VM capabilities: %s", err)
+	}
+	return vm.GetVmCapabilities()
 }
 
 func (vm *VM) Undeploy() (Task, error) {
@@ -382,6 +846,65 @@ func (vm *VM) Undeploy() (Task, error) {
 		types.MimeUndeployVappParams, "error undeploy vApp: %s", vu)
 }
 
+// AcquireTicket retrieves a ticket to open the console of the VM, in the form
+// mks://host/vm-moref/ticket. The VM must be powered on.
+func (vm *VM) AcquireTicket() (*types.ScreenTicket, error) {
+	apiEndpoint, err := url.ParseRequestURI(vm.VM.HREF)
+	if err != nil {
+		return nil, fmt.Errorf("error acquiring screen ticket: %s", err)
+	}
+	apiEndpoint.Path += "/screen/action/acquireTicket"
+
+	ticket := &types.ScreenTicket{}
+	_, err = vm.client.ExecuteRequest(apiEndpoint.String(), http.MethodPost,
+		"", "error acquiring screen ticket: %s", nil, ticket)
+	if err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+// AcquireMksTicket retrieves a ticket to open the console of the VM with WebMKS, as the vCD
+// portal does. The VM must be powered on, and the ticket is valid for a short time.
+func (vm *VM) AcquireMksTicket() (*types.MksTicket, error) {
+	apiEndpoint, err := url.ParseRequestURI(vm.VM.HREF)
+	if err != nil {
+		return nil, fmt.Errorf("error acquiring MKS ticket: %s", err)
+	}
+	apiEndpoint.Path += "/screen/action/acquireMksTicket"
+
+	ticket := &types.MksTicket{}
+	_, err = vm.client.ExecuteRequest(apiEndpoint.String(), http.MethodPost,
+		"", "error acquiring MKS ticket: %s", nil, ticket)
+	if err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+// GetScreenThumbnail retrieves a thumbnail of the screen of the VM, as a PNG image
+func (vm *VM) GetScreenThumbnail() ([]byte, error) {
+	apiEndpoint, err := url.ParseRequestURI(vm.VM.HREF)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving screen thumbnail: %s", err)
+	}
+	apiEndpoint.Path += "/screen"
+
+	req := vm.client.NewRequest(map[string]string{}, http.MethodGet, *apiEndpoint, nil)
+	req.Header.Set("Accept", "image/png")
+	resp, err := checkResp(vm.client.Http.Do(req))
+	if err != nil {
+		return nil, wrapError("error retrieving screen thumbnail: %s", err)
+	}
+	defer resp.Body.Close()
+
+	thumbnail, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading screen thumbnail: %s", err)
+	}
+	return thumbnail, nil
+}
+
 // Attach or detach an independent disk
 // Use the disk/action/attach or disk/action/detach links in a Vm to attach or detach an independent disk.
 // Reference: vCloud API Programming Guide for Service Providers vCloud API 30.0 PDF Page 164 - 165,
@@ -482,6 +1005,36 @@ func (vm *VM) HandleEjectMedia(org *Org, catalogName, mediaName string) (EjectTa
 	return task, err
 }
 
+// InsertMediaFromCatalog inserts the media of the catalog with the given name into the CD-ROM
+// drive of the VM. Returns the insert task.
+func (vm *VM) InsertMediaFromCatalog(catalog *Catalog, mediaName string) (Task, error) {
+	media, err := catalog.GetMediaByName(mediaName)
+	if err != nil {
+		return Task{}, err
+	}
+	return vm.InsertMedia(&types.MediaInsertOrEjectParams{
+		Media: &types.Reference{
+			HREF: media.MediaItem.HREF,
+			Name: media.MediaItem.Name,
+		},
+	})
+}
+
+// EjectMediaFromCatalog ejects the media of the catalog with the given name from the VM. The
+// guest OS may lock the CD-ROM drive, in which case vCD asks whether to override the lock: the
+// returned task answers the question when waited with EjectTask.WaitTaskCompletion.
+func (vm *VM) EjectMediaFromCatalog(catalog *Catalog, mediaName string) (EjectTask, error) {
+	media, err := catalog.GetMediaByName(mediaName)
+	if err != nil {
+		return EjectTask{}, err
+	}
+	return vm.EjectMedia(&types.MediaInsertOrEjectParams{
+		Media: &types.Reference{
+			HREF: media.MediaItem.HREF,
+		},
+	})
+}
+
 // Insert media for VM
 // Call insertOrEjectMedia with media and types.RelMediaInsertMedia to insert media from VM.
 func (vm *VM) InsertMedia(mediaParams *types.MediaInsertOrEjectParams) (Task, error) {
@@ -564,6 +1117,9 @@ func (vm *VM) GetQuestion() (types.VmPendingQuestion, error) {
 	req := vm.client.NewRequest(map[string]string{}, http.MethodGet, *apiEndpoint, nil)
 
 	resp, err := vm.client.Http.Do(req)
+	if err != nil {
+		return types.VmPendingQuestion{}, fmt.Errorf("error getting question: %s", err)
+	}
 
 	// vCD security feature - on no question return 403 access error
 	if http.StatusForbidden == resp.StatusCode {
@@ -571,10 +1127,6 @@ func (vm *VM) GetQuestion() (types.VmPendingQuestion, error) {
 		return types.VmPendingQuestion{}, nil
 	}
 
-	if err != nil {
-		return types.VmPendingQuestion{}, fmt.Errorf("error getting question: %s", err)
-	}
-
 	if http.StatusOK != resp.StatusCode {
 		return types.VmPendingQuestion{}, fmt.Errorf("error getting question: %s", ParseErr(resp))
 	}
@@ -602,12 +1154,37 @@ func (vm *VM) DeleteMetadata(key string) (Task, error) {
 	return deleteMetadata(vm.client, key, vm.VM.HREF)
 }
 
+// AddMetadataEntry adds or updates a metadata entry of any type (types.Metadata*Value) of the VM.
+// A nil domain places the entry in the GENERAL domain, while the SYSTEM domain requires system
+// administrator privileges.
+func (vm *VM) AddMetadataEntry(key string, value types.TypedValue, domain *types.MetadataDomainTag) (Task, error) {
+	return addMetadataEntry(vm.client, key, value, domain, vm.VM.HREF)
+}
+
+// DeleteMetadataEntry removes a metadata entry of the VM from the given domain
+// (types.MetadataDomainGeneral or types.MetadataDomainSystem).
+func (vm *VM) DeleteMetadataEntry(key, domain string) (Task, error) {
+	return deleteMetadataEntry(vm.client, key, domain, vm.VM.HREF)
+}
+
 // AddMetadata() function calls private function addMetadata() with vm.client and vm.VM.HREF
 // which adds metadata key, value pair provided as input to VM.
 func (vm *VM) AddMetadata(key string, value string) (Task, error) {
 	return addMetadata(vm.client, key, value, vm.VM.HREF)
 }
 
+// MergeMetadata adds or updates the given metadata entries of the VM with a single task.
+// Existing keys which are not in the map are left untouched.
+func (vm *VM) MergeMetadata(metadata map[string]string) (Task, error) {
+	return mergeMetadata(vm.client, metadata, vm.VM.HREF)
+}
+
+// SetMetadataMap applies the given metadata entries to the VM with a single task and, when
+// replaceAll is true, removes the entries which are not in the map
+func (vm *VM) SetMetadataMap(metadata map[string]types.TypedValue, replaceAll bool) error {
+	return setMetadataMap(vm.client, metadata, replaceAll, vm.VM.HREF)
+}
+
 // Use the provide answer to existing VM question for operation which need additional response
 // Reference:
 // https://code.vmware.com/apis/287/vcloud#/doc/doc/operations/POST-AnswerVmPendingQuestion.html
@@ -628,7 +1205,42 @@ func (vm *VM) AnswerQuestion(questionId string, choiceId int) error {
 	apiEndpoint.Path += "/question/action/answer"
 
 	return vm.client.ExecuteRequestWithoutResponse(apiEndpoint.String(), http.MethodPost,
-		"", "error asnwering question: %s", answer)
+		"", "error answering question: %s", answer)
+}
+
+// AnswerPendingQuestion answers the pending question of the VM, if there is one, with the choice
+// whose text contains choiceText, regardless of the case (e.g. "yes"). Operations like powering
+// off the VM or ejecting media can stall until the hypervisor question is answered.
+// Returns false when the VM has no pending question.
+func (vm *VM) AnswerPendingQuestion(choiceText string) (bool, error) {
+	question, err := vm.GetQuestion()
+	if err != nil {
+		return false, err
+	}
+	if question.QuestionId == "" {
+		return false, nil
+	}
+	choice := findQuestionChoice(question, choiceText)
+	if choice == nil {
+		return false, fmt.Errorf("no choice matching '%s' for question '%s' of VM %s", choiceText,
+			question.Question, vm.VM.Name)
+	}
+	err = vm.AnswerQuestion(question.QuestionId, choice.Id)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// findQuestionChoice returns the first choice of the question whose text contains the given text,
+// regardless of the case
+func findQuestionChoice(question types.VmPendingQuestion, text string) *types.VmQuestionAnswerChoiceType {
+	for _, choice := range question.Choices {
+		if strings.Contains(strings.ToLower(choice.Text), strings.ToLower(text)) {
+			return choice
+		}
+	}
+	return nil
 }
 
 // ToggleHardwareVirtualization allows to either enable or disable hardware assisted
@@ -653,3 +1265,463 @@ func (vm *VM) ToggleHardwareVirtualization(isEnabled bool) (Task, error) {
 	return vm.client.ExecuteTaskRequest(apiEndpoint.String(), http.MethodPost,
 		"", errMessage, nil)
 }
+
+// ToggleNestedHypervisor enables or disables the hardware assisted CPU virtualization of the VM,
+// needed to run hypervisors in it, waits for the change and refreshes the VM. Nothing is done when
+// the VM is already in the requested state. The VM must be powered off.
+func (vm *VM) ToggleNestedHypervisor(isEnabled bool) error {
+	err := vm.Refresh()
+	if err != nil {
+		return fmt.Errorf("error refreshing VM before toggling hypervisor nesting: %s", err)
+	}
+	if vm.VM.NestedHypervisorEnabled == isEnabled {
+		return nil
+	}
+	task, err := vm.ToggleHardwareVirtualization(isEnabled)
+	if err != nil {
+		return err
+	}
+	err = task.WaitTaskCompletion()
+	if err != nil {
+		return fmt.Errorf("error toggling hypervisor nesting feature to %t for VM: %s", isEnabled, err)
+	}
+	return vm.Refresh()
+}
+
+// VmResourceAllocation is the share of the resources of its host guaranteed to a VM (reservation),
+// the maximum it can use (limit) and its priority (shares) when competing with other VMs. CPU values
+// are in MHz and memory values in MB. When updating, the nil values are left unchanged.
+type VmResourceAllocation struct {
+	CpuReservation    *int
+	CpuLimit          *int // -1 for unlimited
+	CpuShares         *int
+	MemoryReservation *int
+	MemoryLimit       *int // -1 for unlimited
+	MemoryShares      *int
+}
+
+// GetResourceAllocation refreshes the VM and returns the reservation, limit and shares of its CPU
+// and memory. The limits are nil when vCD does not return them.
+func (vm *VM) GetResourceAllocation() (*VmResourceAllocation, error) {
+	err := vm.Refresh()
+	if err != nil {
+		return nil, fmt.Errorf("error refreshing VM before retrieving its resource allocation: %s", err)
+	}
+	cpu := vm.hardwareItem(types.ResourceTypeProcessor)
+	memory := vm.hardwareItem(types.ResourceTypeMemory)
+	if cpu == nil || memory == nil {
+		return nil, fmt.Errorf("VM %s has no CPU or memory item in its virtual hardware section", vm.VM.Name)
+	}
+	cpuReservation, cpuShares := cpu.Reservation, cpu.Weight
+	memoryReservation, memoryShares := memory.Reservation, memory.Weight
+	return &VmResourceAllocation{
+		CpuReservation:    &cpuReservation,
+		CpuLimit:          cpu.Limit,
+		CpuShares:         &cpuShares,
+		MemoryReservation: &memoryReservation,
+		MemoryLimit:       memory.Limit,
+		MemoryShares:      &memoryShares,
+	}, nil
+}
+
+// UpdateResourceAllocation changes the reservation, limit and shares of the CPU and the memory of
+// the VM, keeping the values which are nil in allocation, waits for the changes and refreshes the
+// VM. The number of CPUs and the memory size are unchanged.
+func (vm *VM) UpdateResourceAllocation(allocation VmResourceAllocation) error {
+	err := validateVmResourceAllocation(allocation)
+	if err != nil {
+		return err
+	}
+	err = vm.Refresh()
+	if err != nil {
+		return fmt.Errorf("error refreshing VM before updating its resource allocation: %s", err)
+	}
+	cpu := vm.hardwareItem(types.ResourceTypeProcessor)
+	memory := vm.hardwareItem(types.ResourceTypeMemory)
+	if cpu == nil || memory == nil {
+		return fmt.Errorf("VM %s has no CPU or memory item in its virtual hardware section", vm.VM.Name)
+	}
+
+	if allocation.CpuReservation != nil || allocation.CpuLimit != nil || allocation.CpuShares != nil {
+		var coresPerSocket *int
+		if cpu.CoresPerSocket != 0 {
+			cores := cpu.CoresPerSocket
+			coresPerSocket = &cores
+		}
+		newCpu := vm.newCpuItem(cpu.VirtualQuantity, coresPerSocket)
+		setResourceAllocation(newCpu, allocation.CpuReservation, allocation.CpuLimit, allocation.CpuShares)
+		err = vm.updateHardwareItem("cpu", newCpu)
+		if err != nil {
+			return err
+		}
+	}
+	if allocation.MemoryReservation != nil || allocation.MemoryLimit != nil || allocation.MemoryShares != nil {
+		newMem := vm.newMemoryItem(memory.VirtualQuantity)
+		setResourceAllocation(newMem, allocation.MemoryReservation, allocation.MemoryLimit, allocation.MemoryShares)
+		err = vm.updateHardwareItem("memory", newMem)
+		if err != nil {
+			return err
+		}
+	}
+	return vm.Refresh()
+}
+
+// validateVmResourceAllocation checks that the values of allocation are in range and that the
+// limits are not lower than the reservations given with them
+func validateVmResourceAllocation(allocation VmResourceAllocation) error {
+	for _, value := range []*int{allocation.CpuReservation, allocation.CpuShares, allocation.MemoryReservation, allocation.MemoryShares} {
+		if value != nil && *value < 0 {
+			return fmt.Errorf("resource reservations and shares can not be negative")
+		}
+	}
+	for _, limit := range []*int{allocation.CpuLimit, allocation.MemoryLimit} {
+		if limit != nil && *limit < -1 {
+			return fmt.Errorf("resource limits must be -1 (unlimited) or positive")
+		}
+	}
+	if allocation.CpuReservation != nil && allocation.CpuLimit != nil && *allocation.CpuLimit != -1 &&
+		*allocation.CpuLimit < *allocation.CpuReservation {
+		return fmt.Errorf("CPU limit %d is lower than the CPU reservation %d", *allocation.CpuLimit, *allocation.CpuReservation)
+	}
+	if allocation.MemoryReservation != nil && allocation.MemoryLimit != nil && *allocation.MemoryLimit != -1 &&
+		*allocation.MemoryLimit < *allocation.MemoryReservation {
+		return fmt.Errorf("memory limit %d is lower than the memory reservation %d", *allocation.MemoryLimit, *allocation.MemoryReservation)
+	}
+	return nil
+}
+
+// setResourceAllocation sets the given reservation, limit and shares of a CPU or memory item,
+// when they are not nil
+func setResourceAllocation(item *types.OVFItem, reservation, limit, shares *int) {
+	if reservation != nil {
+		item.Reservation = *reservation
+	}
+	if limit != nil {
+		item.Limit = limit
+	}
+	if shares != nil {
+		item.Weight = *shares
+	}
+}
+
+// updateHardwareItem sends the CPU or memory item of the VM, given with the name of its section, and
+// waits for the update
+func (vm *VM) updateHardwareItem(name string, item *types.OVFItem) error {
+	task, err := vm.client.ExecuteTaskRequest(vm.VM.HREF+"/virtualHardwareSection/"+name, http.MethodPut,
+		types.MimeRasdItem, "error updating VM "+name+" allocation: %s", item)
+	if err != nil {
+		return err
+	}
+	err = task.WaitTaskCompletion()
+	if err != nil {
+		return fmt.Errorf("error updating VM %s allocation: %s", name, err)
+	}
+	return nil
+}
+
+// ChangeHardwareVersion changes the virtual hardware version of the VM, given with its vSphere name
+// (e.g. "vmx-14"), through the VM spec section. The VM must be powered off, and the version can only
+// be upgraded, up to the highest version supported by the provider VDC.
+// Returns the task reconfiguring the VM.
+func (vm *VM) ChangeHardwareVersion(version string) (Task, error) {
+	newVersion, err := parseHardwareVersion(version)
+	if err != nil {
+		return Task{}, err
+	}
+	vmStatus, err := vm.GetStatus()
+	if err != nil {
+		return Task{}, fmt.Errorf("unable to change hardware version: %s", err)
+	}
+	if vmStatus != "POWERED_OFF" {
+		return Task{}, fmt.Errorf("hardware version can be changed from powered off state, status: %s", vmStatus)
+	}
+	if vm.VM.VmSpecSection == nil {
+		return Task{}, fmt.Errorf("VM %s has no VM spec section", vm.VM.Name)
+	}
+	if vm.VM.VmSpecSection.HardwareVersion != nil {
+		currentVersion, err := parseHardwareVersion(vm.VM.VmSpecSection.HardwareVersion.Value)
+		if err == nil && newVersion < currentVersion {
+			return Task{}, fmt.Errorf("hardware version of VM %s can not be downgraded from %s to %s", vm.VM.Name,
+				vm.VM.VmSpecSection.HardwareVersion.Value, version)
+		}
+	}
+
+	return vm.updateVmSpecSection(func(vmSpecSection *types.VmSpecSection) {
+		vmSpecSection.HardwareVersion = &types.HardwareVersion{Value: version}
+	}, "error changing VM hardware version: %s")
+}
+
+// ChangeGuestOsType changes the guest OS type of the VM, given with its vSphere identifier
+// (e.g. "ubuntu64Guest" or "windows9Server64Guest"), through the VM spec section.
+// Returns the task reconfiguring the VM.
+func (vm *VM) ChangeGuestOsType(osType string) (Task, error) {
+	if osType == "" {
+		return Task{}, fmt.Errorf("guest OS type can not be empty")
+	}
+	err := vm.Refresh()
+	if err != nil {
+		return Task{}, fmt.Errorf("error refreshing VM before changing its guest OS type: %s", err)
+	}
+	if vm.VM.VmSpecSection == nil {
+		return Task{}, fmt.Errorf("VM %s has no VM spec section", vm.VM.Name)
+	}
+
+	return vm.updateVmSpecSection(func(vmSpecSection *types.VmSpecSection) {
+		vmSpecSection.OsType = osType
+	}, "error changing VM guest OS type: %s")
+}
+
+// updateVmSpecSection reconfigures the VM with a copy of its current VM spec section, changed by
+// update. The VM must have been refreshed, so that the other elements of the section are sent
+// with their current values.
+func (vm *VM) updateVmSpecSection(update func(*types.VmSpecSection), errorMessage string) (Task, error) {
+	vmSpecSection := *vm.VM.VmSpecSection
+	update(&vmSpecSection)
+	modified := true
+	vmSpecSection.Modified = &modified
+	vmSpecSection.Info = "Virtual Machine specification"
+
+	return vm.reconfigure(&types.VM{VmSpecSection: &vmSpecSection}, errorMessage)
+}
+
+// reconfigure sends the sections and elements set in vmPayload to the reconfigureVm action of the
+// VM. The name and description of the VM are added, as vCD requires them.
+func (vm *VM) reconfigure(vmPayload *types.VM, errorMessage string) (Task, error) {
+	vmPayload.Xmlns = types.XMLNamespaceVCloud
+	vmPayload.Ovf = types.XMLNamespaceOVF
+	vmPayload.Name = vm.VM.Name
+	vmPayload.Description = vm.VM.Description
+
+	apiEndpoint, err := url.ParseRequestURI(vm.VM.HREF)
+	if err != nil {
+		return Task{}, fmt.Errorf("error parsing VM HREF: %s", err)
+	}
+	apiEndpoint.Path += "/action/reconfigureVm"
+
+	return vm.client.ExecuteTaskRequest(apiEndpoint.String(), http.MethodPost,
+		types.MimeVM, errorMessage, vmPayload)
+}
+
+// VmBootOptions is the boot configuration of a VM: its firmware, from the VM spec section, and its
+// boot options
+type VmBootOptions struct {
+	Firmware    string // types.VmFirmwareBios or types.VmFirmwareEfi. Empty before API 33.0
+	BootOptions types.BootOptions
+}
+
+// GetBootOptions refreshes the VM and returns its boot configuration
+func (vm *VM) GetBootOptions() (*VmBootOptions, error) {
+	err := vm.Refresh()
+	if err != nil {
+		return nil, fmt.Errorf("error refreshing VM before retrieving its boot options: %s", err)
+	}
+	bootOptions := &VmBootOptions{}
+	if vm.VM.VmSpecSection != nil {
+		bootOptions.Firmware = vm.VM.VmSpecSection.Firmware
+	}
+	if vm.VM.BootOptions != nil {
+		bootOptions.BootOptions = *vm.VM.BootOptions
+	}
+	return bootOptions, nil
+}
+
+// UpdateBootOptions changes the boot configuration of the VM, waits for the reconfiguration and
+// returns the new boot configuration. The elements of bootOptions which are omitted (empty firmware,
+// nil boot options) are left unchanged. Changing the firmware requires the VM to be powered off,
+// and secure boot requires the efi firmware.
+func (vm *VM) UpdateBootOptions(bootOptions *VmBootOptions) (*VmBootOptions, error) {
+	if bootOptions == nil {
+		return nil, fmt.Errorf("boot options are required")
+	}
+	current, err := vm.GetBootOptions()
+	if err != nil {
+		return nil, err
+	}
+	err = validateVmBootOptions(vm.client, current, bootOptions)
+	if err != nil {
+		return nil, err
+	}
+	if bootOptions.Firmware != "" && bootOptions.Firmware != current.Firmware {
+		vmStatus, err := vm.GetStatus()
+		if err != nil {
+			return nil, fmt.Errorf("unable to change firmware: %s", err)
+		}
+		if vmStatus != "POWERED_OFF" {
+			return nil, fmt.Errorf("firmware can be changed from powered off state, status: %s", vmStatus)
+		}
+	}
+
+	vmPayload := &types.VM{BootOptions: &types.BootOptions{
+		BootDelay:            bootOptions.BootOptions.BootDelay,
+		EnterBiosSetup:       bootOptions.BootOptions.EnterBiosSetup,
+		BootRetryEnabled:     bootOptions.BootOptions.BootRetryEnabled,
+		BootRetryDelay:       bootOptions.BootOptions.BootRetryDelay,
+		EfiSecureBootEnabled: bootOptions.BootOptions.EfiSecureBootEnabled,
+		NetworkBootProtocol:  bootOptions.BootOptions.NetworkBootProtocol,
+	}}
+	if bootOptions.Firmware != "" {
+		modified := true
+		vmPayload.VmSpecSection = &types.VmSpecSection{
+			Modified: &modified,
+			Info:     "Virtual Machine specification",
+			Firmware: bootOptions.Firmware,
+		}
+	}
+	task, err := vm.reconfigure(vmPayload, "error updating VM boot options: %s")
+	if err != nil {
+		return nil, err
+	}
+	err = task.WaitTaskCompletion()
+	if err != nil {
+		return nil, fmt.Errorf("error updating VM boot options: %s", err)
+	}
+	return vm.GetBootOptions()
+}
+
+// validateVmBootOptions checks the boot options requested for a VM, given its current boot
+// configuration and the API version of the client
+func validateVmBootOptions(client *Client, current, requested *VmBootOptions) error {
+	options := requested.BootOptions
+	switch requested.Firmware {
+	case "", types.VmFirmwareBios, types.VmFirmwareEfi:
+	default:
+		return fmt.Errorf("invalid firmware '%s': expected %s or %s", requested.Firmware, types.VmFirmwareBios, types.VmFirmwareEfi)
+	}
+	if options.BootDelay != nil && *options.BootDelay < 0 {
+		return fmt.Errorf("boot delay can not be negative")
+	}
+	if options.BootRetryDelay != nil && *options.BootRetryDelay < 0 {
+		return fmt.Errorf("boot retry delay can not be negative")
+	}
+	if (requested.Firmware != "" || options.BootRetryEnabled != nil || options.BootRetryDelay != nil ||
+		options.EfiSecureBootEnabled != nil || options.NetworkBootProtocol != "") && !client.APIClientVersionIs(">= 33.0") {
+		return fmt.Errorf("firmware, boot retry, secure boot and network boot protocol require API version 33.0 or higher")
+	}
+
+	firmware := requested.Firmware
+	if firmware == "" {
+		firmware = current.Firmware
+	}
+	secureBoot := options.EfiSecureBootEnabled
+	if secureBoot == nil {
+		secureBoot = current.BootOptions.EfiSecureBootEnabled
+	}
+	if secureBoot != nil && *secureBoot && firmware != types.VmFirmwareEfi {
+		return fmt.Errorf("secure boot requires the %s firmware", types.VmFirmwareEfi)
+	}
+	return nil
+}
+
+// GetExtraConfig refreshes the VM and returns the entries of its extra configuration (the advanced
+// VMX settings). Requires API version 37.1 or higher.
+func (vm *VM) GetExtraConfig() ([]*types.ExtraConfig, error) {
+	if !vm.client.APIClientVersionIs(">= 37.1") {
+		return nil, fmt.Errorf("VM extra configuration requires API version 37.1 or higher")
+	}
+	err := vm.Refresh()
+	if err != nil {
+		return nil, fmt.Errorf("error refreshing VM before retrieving its extra configuration: %s", err)
+	}
+	if vm.VM.VirtualHardwareSection == nil {
+		return nil, nil
+	}
+	return vm.VM.VirtualHardwareSection.ExtraConfig, nil
+}
+
+// GetExtraConfigValue returns the value of the extra configuration entry of the VM with the given
+// key. Returns ErrorEntityNotFound if the VM has no such entry.
+func (vm *VM) GetExtraConfigValue(key string) (string, error) {
+	extraConfig, err := vm.GetExtraConfig()
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range extraConfig {
+		if entry.Key == key {
+			return entry.Value, nil
+		}
+	}
+	return "", wrapErrorf(ErrorEntityNotFound, "extra configuration entry %s not found in VM %s: %s", key, vm.VM.Name, ErrorEntityNotFound)
+}
+
+// UpdateExtraConfig adds or changes the given entries of the extra configuration of the VM, such as
+// "disk.EnableUUID" => "TRUE", waits for the reconfiguration and returns the new extra
+// configuration. The other entries are left unchanged. Requires API version 37.1 or higher.
+func (vm *VM) UpdateExtraConfig(entries map[string]string) ([]*types.ExtraConfig, error) {
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no extra configuration entries to update")
+	}
+	for key, value := range entries {
+		if key == "" || value == "" {
+			return nil, fmt.Errorf("extra configuration entries need a key and a value, use DeleteExtraConfig to remove entries")
+		}
+	}
+	return vm.reconfigureExtraConfig(entries, "error updating VM extra configuration: %s")
+}
+
+// DeleteExtraConfig removes the entries of the extra configuration of the VM with the given keys,
+// waits for the reconfiguration and returns the new extra configuration. Requires API version 37.1
+// or higher.
+func (vm *VM) DeleteExtraConfig(keys ...string) ([]*types.ExtraConfig, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no extra configuration entries to delete")
+	}
+	entries := make(map[string]string, len(keys))
+	for _, key := range keys {
+		if key == "" {
+			return nil, fmt.Errorf("extra configuration entries to delete need a key")
+		}
+		// vCD removes the entries whose value is empty
+		entries[key] = ""
+	}
+	return vm.reconfigureExtraConfig(entries, "error deleting VM extra configuration: %s")
+}
+
+// reconfigureExtraConfig sends the given extra configuration entries, sorted by key, to the
+// reconfigureVm action of the VM and returns the resulting extra configuration
+func (vm *VM) reconfigureExtraConfig(entries map[string]string, errorMessage string) ([]*types.ExtraConfig, error) {
+	if !vm.client.APIClientVersionIs(">= 37.1") {
+		return nil, fmt.Errorf("VM extra configuration requires API version 37.1 or higher")
+	}
+	err := vm.Refresh()
+	if err != nil {
+		return nil, fmt.Errorf("error refreshing VM before changing its extra configuration: %s", err)
+	}
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	section := &types.VirtualHardwareExtraConfig{Info: "Virtual hardware requirements"}
+	for _, key := range keys {
+		section.ExtraConfig = append(section.ExtraConfig, &types.ExtraConfig{Key: key, Value: entries[key]})
+	}
+
+	vmPayload := &types.VmExtraConfigUpdate{
+		Xmlns:                  types.XMLNamespaceVCloud,
+		Name:                   vm.VM.Name,
+		Description:            vm.VM.Description,
+		VirtualHardwareSection: section,
+	}
+	task, err := vm.client.ExecuteTaskRequest(vm.VM.HREF+"/action/reconfigureVm", http.MethodPost,
+		types.MimeVM, errorMessage, vmPayload)
+	if err != nil {
+		return nil, err
+	}
+	err = task.WaitTaskCompletion()
+	if err != nil {
+		return nil, fmt.Errorf(errorMessage, err)
+	}
+	return vm.GetExtraConfig()
+}
+
+// parseHardwareVersion returns the number of a hardware version in the vmx-NN format
+func parseHardwareVersion(version string) (int, error) {
+	if !strings.HasPrefix(version, "vmx-") {
+		return 0, fmt.Errorf("invalid hardware version '%s': expected format vmx-NN", version)
+	}
+	number, err := strconv.Atoi(strings.TrimPrefix(version, "vmx-"))
+	if err != nil || number <= 0 {
+		return 0, fmt.Errorf("invalid hardware version '%s': expected format vmx-NN", version)
+	}
+	return number, nil
+}
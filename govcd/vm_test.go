@@ -6,12 +6,17 @@
 package govcd
 
 import (
+	"encoding/xml"
 	"errors"
 	"fmt"
+	"net/http"
+	"strconv"
 	"strings"
+	"testing"
 	"time"
 
 	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
 	. "gopkg.in/check.v1"
 )
 
@@ -1200,3 +1205,873 @@ func (vcd *TestVCD) Test_updateNicParameters_singleNIC(check *C) {
 		check.Assert(vcdCfg.NetworkConnection[0].IPAddress, Equals, tableTest.expectedIPAddress)
 	}
 }
+
+// Checks that the guest customization of a VM keeps the settings which are not changed, and that
+// the whole section is sent when it is replaced
+func TestVM_GuestCustomizationSection(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	sectionPath := "/api/vApp/vm-1/guestCustomizationSection/"
+	server.HandleXML(http.MethodGet, sectionPath, http.StatusOK,
+		`<GuestCustomizationSection xmlns="http://www.vmware.com/vcloud/v1.5" xmlns:ovf="http://schemas.dmtf.org/ovf/envelope/1">
+  <ovf:Info>Specifies Guest OS Customization Settings</ovf:Info>
+  <Enabled>false</Enabled>
+  <ChangeSid>false</ChangeSid>
+  <JoinDomainEnabled>false</JoinDomainEnabled>
+  <UseOrgSettings>false</UseOrgSettings>
+  <AdminPasswordEnabled>true</AdminPasswordEnabled>
+  <AdminPasswordAuto>true</AdminPasswordAuto>
+  <AdminAutoLogonEnabled>false</AdminAutoLogonEnabled>
+  <AdminAutoLogonCount>0</AdminAutoLogonCount>
+  <ResetPasswordRequired>false</ResetPasswordRequired>
+  <ComputerName>vm1</ComputerName>
+</GuestCustomizationSection>`)
+	server.HandleXML(http.MethodPut, sectionPath, http.StatusAccepted,
+		`<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="running" href="{{server}}`+vcdtest.MockTaskPath+`"/>`)
+
+	vcdClient := newMockClient(t, server)
+	vm := NewVM(&vcdClient.Client)
+	vm.VM.HREF = server.URL() + "/api/vApp/vm-1"
+
+	_, err := vm.Customize("web", "echo hello", true)
+	if err != nil {
+		t.Fatalf("error customizing VM: %s", err)
+	}
+	requests := server.RequestsTo(http.MethodPut, sectionPath)
+	if len(requests) != 1 {
+		t.Fatalf("expected one update, got %d", len(requests))
+	}
+	for _, expected := range []string{"<Enabled>true</Enabled>", "<ChangeSid>true</ChangeSid>",
+		"<AdminPasswordAuto>true</AdminPasswordAuto>", "<ComputerName>web</ComputerName>"} {
+		if !strings.Contains(requests[0].Body, expected) {
+			t.Errorf("expected %s in payload:\n%s", expected, requests[0].Body)
+		}
+	}
+
+	section, err := vm.GetGuestCustomizationSection()
+	if err != nil {
+		t.Fatalf("error retrieving guest customization: %s", err)
+	}
+	section.AdminPassword = "Passw0rd"
+	_, err = vm.SetGuestCustomizationSection(section)
+	if err == nil {
+		t.Errorf("expected error with an explicit password generated automatically")
+	}
+	section.AdminPasswordAuto = false
+	section.AdminAutoLogonEnabled = true
+	section.AdminAutoLogonCount = 3
+	_, err = vm.SetGuestCustomizationSection(section)
+	if err != nil {
+		t.Fatalf("error setting guest customization: %s", err)
+	}
+	requests = server.RequestsTo(http.MethodPut, sectionPath)
+	payload := requests[len(requests)-1].Body
+	for _, expected := range []string{"<AdminPasswordAuto>false</AdminPasswordAuto>", "<AdminPassword>Passw0rd</AdminPassword>",
+		"<AdminAutoLogonCount>3</AdminAutoLogonCount>", "<Enabled>false</Enabled>"} {
+		if !strings.Contains(payload, expected) {
+			t.Errorf("expected %s in payload:\n%s", expected, payload)
+		}
+	}
+}
+
+// Checks that the power operations of a VM are sent to the VM and not to its vApp
+func TestVM_PowerOperations(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	task := `<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="running" href="{{server}}` + vcdtest.MockTaskPath + `"/>`
+
+	vcdClient := newMockClient(t, server)
+	vm := NewVM(&vcdClient.Client)
+	vm.VM.HREF = server.URL() + "/api/vApp/vm-1"
+
+	operations := []struct {
+		path      string
+		operation func() (Task, error)
+	}{
+		{"/api/vApp/vm-1/power/action/powerOn", vm.PowerOn},
+		{"/api/vApp/vm-1/power/action/powerOff", vm.PowerOff},
+		{"/api/vApp/vm-1/power/action/shutdown", vm.Shutdown},
+		{"/api/vApp/vm-1/power/action/reboot", vm.Reboot},
+		{"/api/vApp/vm-1/power/action/reset", vm.Reset},
+		{"/api/vApp/vm-1/power/action/suspend", vm.Suspend},
+		{"/api/vApp/vm-1/action/discardSuspendedState", vm.DiscardSuspendedState},
+		{"/api/vApp/vm-1/action/undeploy", vm.Undeploy},
+	}
+	for _, op := range operations {
+		server.HandleXML(http.MethodPost, op.path, http.StatusAccepted, task)
+		powerTask, err := op.operation()
+		if err != nil {
+			t.Errorf("error running %s: %s", op.path, err)
+			continue
+		}
+		err = powerTask.WaitTaskCompletion()
+		if err != nil {
+			t.Errorf("error waiting for %s: %s", op.path, err)
+		}
+		if len(server.RequestsTo(http.MethodPost, op.path)) != 1 {
+			t.Errorf("expected one request to %s", op.path)
+		}
+	}
+}
+
+// Checks the console tickets and the screen thumbnail of a VM
+func TestVM_Console(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	server.HandleXML(http.MethodPost, "/api/vApp/vm-1/screen/action/acquireTicket", http.StatusOK,
+		`<ScreenTicket xmlns="http://www.vmware.com/vcloud/v1.5">mks://10.0.0.1/vm-42/abcdef</ScreenTicket>`)
+	server.HandleXML(http.MethodPost, "/api/vApp/vm-1/screen/action/acquireMksTicket", http.StatusOK,
+		`<MksTicket xmlns="http://www.vmware.com/vcloud/v1.5">
+  <Host>10.0.0.1</Host>
+  <Vmx>[datastore1] vm-1/vm-1.vmx</Vmx>
+  <Ticket>abcdef</Ticket>
+  <Port>902</Port>
+</MksTicket>`)
+	server.Handle(http.MethodGet, "/api/vApp/vm-1/screen", vcdtest.Response{
+		Status:      http.StatusOK,
+		ContentType: "image/png",
+		Body:        "\x89PNG",
+	})
+
+	vcdClient := newMockClient(t, server)
+	vm := NewVM(&vcdClient.Client)
+	vm.VM.HREF = server.URL() + "/api/vApp/vm-1"
+
+	ticket, err := vm.AcquireTicket()
+	if err != nil {
+		t.Fatalf("error acquiring ticket: %s", err)
+	}
+	if ticket.Value != "mks://10.0.0.1/vm-42/abcdef" {
+		t.Errorf("unexpected screen ticket %s", ticket.Value)
+	}
+
+	mksTicket, err := vm.AcquireMksTicket()
+	if err != nil {
+		t.Fatalf("error acquiring MKS ticket: %s", err)
+	}
+	if mksTicket.Host != "10.0.0.1" || mksTicket.Port != 902 || mksTicket.Ticket != "abcdef" {
+		t.Errorf("unexpected MKS ticket %+v", mksTicket)
+	}
+
+	thumbnail, err := vm.GetScreenThumbnail()
+	if err != nil {
+		t.Fatalf("error retrieving thumbnail: %s", err)
+	}
+	if string(thumbnail) != "\x89PNG" {
+		t.Errorf("unexpected thumbnail %q", thumbnail)
+	}
+	requests := server.RequestsTo(http.MethodGet, "/api/vApp/vm-1/screen")
+	if len(requests) != 1 || requests[0].Header.Get("Accept") != "image/png" {
+		t.Errorf("expected one thumbnail request accepting PNG images")
+	}
+}
+
+// Checks the NIC helpers of a VM and their validation against the networks of the vApp
+func TestVM_NetworkConnections(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	vmPath := "/api/vApp/vm-1"
+	vappPath := "/api/vApp/vapp-1"
+	sectionPath := vmPath + "/networkConnectionSection/"
+	server.HandleXML(http.MethodGet, vmPath, http.StatusOK,
+		`<Vm xmlns="http://www.vmware.com/vcloud/v1.5" name="vm1" href="{{server}}`+vmPath+`">
+  <Link rel="up" type="application/vnd.vmware.vcloud.vApp+xml" href="{{server}}`+vappPath+`"/>
+</Vm>`)
+	server.HandleXML(http.MethodGet, vappPath, http.StatusOK,
+		`<VApp xmlns="http://www.vmware.com/vcloud/v1.5" name="app" href="{{server}}`+vappPath+`"/>`)
+	server.HandleXML(http.MethodGet, vappPath+"/networkConfigSection/", http.StatusOK,
+		`<NetworkConfigSection xmlns="http://www.vmware.com/vcloud/v1.5" xmlns:ovf="http://schemas.dmtf.org/ovf/envelope/1">
+  <ovf:Info>The configuration parameters for logical networks</ovf:Info>
+  <NetworkConfig networkName="net1"><IsDeployed>true</IsDeployed></NetworkConfig>
+  <NetworkConfig networkName="net2"><IsDeployed>true</IsDeployed></NetworkConfig>
+</NetworkConfigSection>`)
+	server.HandleXML(http.MethodGet, sectionPath, http.StatusOK,
+		`<NetworkConnectionSection xmlns="http://www.vmware.com/vcloud/v1.5" xmlns:ovf="http://schemas.dmtf.org/ovf/envelope/1">
+  <ovf:Info>Specifies the available VM network connections</ovf:Info>
+  <PrimaryNetworkConnectionIndex>0</PrimaryNetworkConnectionIndex>
+  <NetworkConnection network="net1">
+    <NetworkConnectionIndex>0</NetworkConnectionIndex>
+    <IpAddress>192.168.1.10</IpAddress>
+    <IsConnected>true</IsConnected>
+    <MACAddress>00:50:56:01:02:03</MACAddress>
+    <IpAddressAllocationMode>POOL</IpAddressAllocationMode>
+    <NetworkAdapterType>VMXNET3</NetworkAdapterType>
+  </NetworkConnection>
+  <NetworkConnection network="none">
+    <NetworkConnectionIndex>2</NetworkConnectionIndex>
+    <IsConnected>false</IsConnected>
+    <IpAddressAllocationMode>NONE</IpAddressAllocationMode>
+    <NetworkAdapterType>E1000</NetworkAdapterType>
+  </NetworkConnection>
+</NetworkConnectionSection>`)
+	server.HandleXML(http.MethodPut, sectionPath, http.StatusAccepted,
+		`<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="running" href="{{server}}`+vcdtest.MockTaskPath+`"/>`)
+
+	vcdClient := newMockClient(t, server)
+	vm, err := vcdClient.Client.FindVMByHREF(server.URL() + vmPath)
+	if err != nil {
+		t.Fatalf("error retrieving VM: %s", err)
+	}
+	lastPayload := func() string {
+		requests := server.RequestsTo(http.MethodPut, sectionPath)
+		if len(requests) == 0 {
+			return ""
+		}
+		return requests[len(requests)-1].Body
+	}
+
+	err = vm.AddNetworkConnection(VMNetworkConnection{Network: "net2", AdapterType: types.NetworkAdapterTypeSriov, IsPrimary: true})
+	if err != nil {
+		t.Fatalf("error adding NIC: %s", err)
+	}
+	for _, expected := range []string{`<NetworkConnection network="net2">`, "<NetworkConnectionIndex>3</NetworkConnectionIndex>",
+		"<PrimaryNetworkConnectionIndex>3</PrimaryNetworkConnectionIndex>", "<NetworkAdapterType>SRIOVETHERNETCARD</NetworkAdapterType>"} {
+		if !strings.Contains(lastPayload(), expected) {
+			t.Errorf("expected %s in payload:\n%s", expected, lastPayload())
+		}
+	}
+
+	err = vm.RemoveNetworkConnection(0)
+	if err != nil {
+		t.Fatalf("error removing NIC: %s", err)
+	}
+	if strings.Contains(lastPayload(), `network="net1"`) ||
+		!strings.Contains(lastPayload(), "<PrimaryNetworkConnectionIndex>2</PrimaryNetworkConnectionIndex>") {
+		t.Errorf("unexpected payload after removing the primary NIC:\n%s", lastPayload())
+	}
+
+	err = vm.SetNetworkConnectionAllocationMode(0, types.IPAllocationModeManual, "192.168.1.50")
+	if err != nil {
+		t.Fatalf("error changing allocation mode: %s", err)
+	}
+	if !strings.Contains(lastPayload(), "<IpAddress>192.168.1.50</IpAddress>") ||
+		!strings.Contains(lastPayload(), "<IpAddressAllocationMode>MANUAL</IpAddressAllocationMode>") {
+		t.Errorf("unexpected payload after changing allocation mode:\n%s", lastPayload())
+	}
+
+	err = vm.SetNetworkConnectionMacAddress(0, "")
+	if err != nil {
+		t.Fatalf("error clearing MAC address: %s", err)
+	}
+	if strings.Contains(lastPayload(), "00:50:56:01:02:03") {
+		t.Errorf("expected the MAC address to be cleared:\n%s", lastPayload())
+	}
+
+	updates := len(server.RequestsTo(http.MethodPut, sectionPath))
+	invalid := []func() error{
+		func() error { return vm.AddNetworkConnection(VMNetworkConnection{Network: "net3"}) },
+		func() error { return vm.SetNetworkConnectionAllocationMode(0, types.IPAllocationModeManual, "") },
+		func() error { return vm.SetNetworkConnectionAllocationMode(0, "STATIC", "") },
+		func() error { return vm.SetNetworkConnectionMacAddress(0, "not-a-mac") },
+		func() error { return vm.RemoveNetworkConnection(1) },
+	}
+	for index, update := range invalid {
+		if update() == nil {
+			t.Errorf("expected error with invalid update %d", index)
+		}
+	}
+	if len(server.RequestsTo(http.MethodPut, sectionPath)) != updates {
+		t.Errorf("expected no request with invalid updates")
+	}
+}
+
+// Checks the VM spec sections sent to change the hardware version and the guest OS type of a VM
+func TestVM_ChangeHardwareVersionAndGuestOsType(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	vmPath := "/api/vApp/vm-1"
+	reconfigurePath := vmPath + "/action/reconfigureVm"
+	server.HandleXML(http.MethodGet, vmPath, http.StatusOK,
+		`<Vm xmlns="http://www.vmware.com/vcloud/v1.5" xmlns:ovf="http://schemas.dmtf.org/ovf/envelope/1" name="vm1" status="8" href="{{server}}`+vmPath+`">
+  <VmSpecSection Modified="false">
+    <ovf:Info>Virtual Machine specification</ovf:Info>
+    <OsType>otherGuest64</OsType>
+    <NumCpus>2</NumCpus>
+    <HardwareVersion href="{{server}}/api/vdc/vdc-1/hwv/vmx-13">vmx-13</HardwareVersion>
+  </VmSpecSection>
+</Vm>`)
+	server.HandleXML(http.MethodPost, reconfigurePath, http.StatusAccepted,
+		`<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="running" href="{{server}}`+vcdtest.MockTaskPath+`"/>`)
+
+	vcdClient := newMockClient(t, server)
+	vm := NewVM(&vcdClient.Client)
+	vm.VM.HREF = server.URL() + vmPath
+
+	for _, version := range []string{"14", "vmx-", "vmx-11"} {
+		_, err := vm.ChangeHardwareVersion(version)
+		if err == nil {
+			t.Errorf("expected error changing hardware version to %s", version)
+		}
+	}
+	if len(server.RequestsTo(http.MethodPost, reconfigurePath)) != 0 {
+		t.Fatalf("expected no request with invalid hardware versions")
+	}
+
+	task, err := vm.ChangeHardwareVersion("vmx-14")
+	if err != nil {
+		t.Fatalf("error changing hardware version: %s", err)
+	}
+	err = task.WaitTaskCompletion()
+	if err != nil {
+		t.Fatalf("error waiting for hardware version change: %s", err)
+	}
+	_, err = vm.ChangeGuestOsType("ubuntu64Guest")
+	if err != nil {
+		t.Fatalf("error changing guest OS type: %s", err)
+	}
+
+	requests := server.RequestsTo(http.MethodPost, reconfigurePath)
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 reconfigure requests, got %d", len(requests))
+	}
+	for index, expected := range []string{"<HardwareVersion>vmx-14</HardwareVersion>", "<OsType>ubuntu64Guest</OsType>"} {
+		body := requests[index].Body
+		if !strings.Contains(body, expected) || !strings.Contains(body, `<VmSpecSection Modified="true">`) {
+			t.Errorf("expected %s in modified VM spec section:\n%s", expected, body)
+		}
+		if !strings.Contains(body, "<NumCpus>2</NumCpus>") {
+			t.Errorf("expected the other VM spec elements to be sent with their current values:\n%s", body)
+		}
+	}
+	if !strings.Contains(requests[0].Body, "<OsType>otherGuest64</OsType>") {
+		t.Errorf("expected the current guest OS type when changing the hardware version:\n%s", requests[0].Body)
+	}
+	if !strings.Contains(requests[1].Body, ">vmx-13</HardwareVersion>") {
+		t.Errorf("expected the current hardware version when changing the guest OS type:\n%s", requests[1].Body)
+	}
+	if vm.VM.VmSpecSection.OsType != "otherGuest64" || vm.VM.VmSpecSection.Modified == nil || *vm.VM.VmSpecSection.Modified {
+		t.Errorf("expected the VM spec section of the VM to be left unchanged: %+v", vm.VM.VmSpecSection)
+	}
+}
+
+// Checks that the hot add capabilities of a VM can be disabled
+func TestVM_VmCapabilities(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	capabilitiesPath := "/api/vApp/vm-1/vmCapabilities/"
+	server.HandleXML(http.MethodGet, capabilitiesPath, http.StatusOK,
+		`<VmCapabilities xmlns="http://www.vmware.com/vcloud/v1.5" href="{{server}}`+capabilitiesPath+`">
+  <MemoryHotAddEnabled>false</MemoryHotAddEnabled>
+  <CpuHotAddEnabled>true</CpuHotAddEnabled>
+</VmCapabilities>`)
+	server.HandleXML(http.MethodPut, capabilitiesPath, http.StatusAccepted,
+		`<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="running" href="{{server}}`+vcdtest.MockTaskPath+`"/>`)
+
+	vcdClient := newMockClient(t, server)
+	vm := NewVM(&vcdClient.Client)
+	vm.VM.HREF = server.URL() + "/api/vApp/vm-1"
+
+	capabilities, err := vm.UpdateVmCapabilities(false, true)
+	if err != nil {
+		t.Fatalf("error updating VM capabilities: %s", err)
+	}
+	if capabilities.MemoryHotAddEnabled || !capabilities.CPUHotAddEnabled {
+		t.Errorf("unexpected VM capabilities: %#v", capabilities)
+	}
+	requests := server.RequestsTo(http.MethodPut, capabilitiesPath)
+	if len(requests) != 1 {
+		t.Fatalf("expected one update, got %d", len(requests))
+	}
+	// Disabled capabilities must be sent explicitly
+	for _, expected := range []string{"<MemoryHotAddEnabled>false</MemoryHotAddEnabled>", "<CpuHotAddEnabled>true</CpuHotAddEnabled>"} {
+		if !strings.Contains(requests[0].Body, expected) {
+			t.Errorf("expected %s in payload:\n%s", expected, requests[0].Body)
+		}
+	}
+}
+
+// Checks the insertion and ejection of catalog media, and the answer to the CD-ROM lock question
+func TestVM_MediaFromCatalog(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	vmPath := "/api/vApp/vm-1"
+	server.HandleXML(http.MethodGet, "/api/query", http.StatusOK,
+		`<QueryResultRecords xmlns="http://www.vmware.com/vcloud/v1.5" total="1" page="1" pageSize="128">
+  <MediaRecord name="boot.iso" href="{{server}}/api/media/media-1" catalogName="`+vcdtest.MockCatalogName+`"/>
+</QueryResultRecords>`)
+	task := `<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="running" href="{{server}}` + vcdtest.MockTaskPath + `"/>`
+	server.HandleXML(http.MethodPost, vmPath+"/media/action/insertMedia", http.StatusAccepted, task)
+	server.HandleXML(http.MethodPost, vmPath+"/media/action/ejectMedia", http.StatusAccepted, task)
+	server.HandleXML(http.MethodGet, vmPath+"/question", http.StatusForbidden, "")
+
+	vcdClient := newMockClient(t, server)
+	catalog := NewCatalog(&vcdClient.Client)
+	catalog.Catalog = &types.Catalog{Name: vcdtest.MockCatalogName, HREF: server.URL() + vcdtest.MockCatalogPath}
+	vm := NewVM(&vcdClient.Client)
+	vm.VM.HREF = server.URL() + vmPath
+	for _, rel := range []string{types.RelMediaInsertMedia, types.RelMediaEjectMedia} {
+		action := strings.TrimPrefix(rel, "media:")
+		vm.VM.Link = append(vm.VM.Link, &types.Link{Rel: rel, Type: types.MimeMediaInsertOrEjectParams,
+			HREF: server.URL() + vmPath + "/media/action/" + action})
+	}
+
+	_, err := vm.InsertMediaFromCatalog(catalog, "missing.iso")
+	if err == nil {
+		t.Errorf("expected error inserting missing media")
+	}
+	insertTask, err := vm.InsertMediaFromCatalog(catalog, "boot.iso")
+	if err != nil {
+		t.Fatalf("error inserting media: %s", err)
+	}
+	err = insertTask.WaitTaskCompletion()
+	if err != nil {
+		t.Fatalf("error waiting for media insertion: %s", err)
+	}
+	ejectTask, err := vm.EjectMediaFromCatalog(catalog, "boot.iso")
+	if err != nil {
+		t.Fatalf("error ejecting media: %s", err)
+	}
+	err = ejectTask.WaitTaskCompletion(true)
+	if err != nil {
+		t.Fatalf("error waiting for media ejection: %s", err)
+	}
+	for _, action := range []string{"insertMedia", "ejectMedia"} {
+		requests := server.RequestsTo(http.MethodPost, vmPath+"/media/action/"+action)
+		if len(requests) != 1 || !strings.Contains(requests[0].Body, `href="`+server.URL()+`/api/media/media-1"`) {
+			t.Errorf("unexpected %s requests: %+v", action, requests)
+		}
+	}
+
+	// No pending question is reported as an access error
+	question, err := vm.GetQuestion()
+	if err != nil || question.QuestionId != "" {
+		t.Errorf("expected no question, got %+v, %v", question, err)
+	}
+
+	question = types.VmPendingQuestion{
+		QuestionId: "50",
+		Question:   questionMessage,
+		Choices: []*types.VmQuestionAnswerChoiceType{
+			{Id: 0, Text: "Yes"},
+			{Id: 1, Text: "No"},
+		},
+	}
+	if choice := findEjectAnswer(question, true); choice == nil || choice.Id != 0 {
+		t.Errorf("expected the yes choice, got %+v", choice)
+	}
+	if choice := findEjectAnswer(question, false); choice == nil || choice.Id != 1 {
+		t.Errorf("expected the no choice, got %+v", choice)
+	}
+}
+
+// Checks that the pending question of a VM is answered with the matching choice
+func TestVM_AnswerPendingQuestion(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	vmPath := "/api/vApp/vm-1"
+	server.HandleXML(http.MethodGet, vmPath+"/question", http.StatusOK,
+		`<VmPendingQuestion xmlns="http://www.vmware.com/vcloud/v1.5" href="{{server}}`+vmPath+`/question">
+  <Question>The guest OS has locked the CD-ROM door. Disconnect anyway and override the lock?</Question>
+  <QuestionId>50</QuestionId>
+  <Choices><Id>0</Id><Text>Yes</Text></Choices>
+  <Choices><Id>1</Id><Text>No</Text></Choices>
+</VmPendingQuestion>`)
+	server.HandleXML(http.MethodPost, vmPath+"/question/action/answer", http.StatusNoContent, "")
+
+	vcdClient := newMockClient(t, server)
+	vm := NewVM(&vcdClient.Client)
+	vm.VM.HREF = server.URL() + vmPath
+
+	_, err := vm.AnswerPendingQuestion("maybe")
+	if err == nil {
+		t.Errorf("expected error answering with an unknown choice")
+	}
+	answered, err := vm.AnswerPendingQuestion("no")
+	if err != nil || !answered {
+		t.Fatalf("expected the question to be answered, got %t, %v", answered, err)
+	}
+	requests := server.RequestsTo(http.MethodPost, vmPath+"/question/action/answer")
+	if len(requests) != 1 || !strings.Contains(requests[0].Body, "<ChoiceId>1</ChoiceId>") ||
+		!strings.Contains(requests[0].Body, "<QuestionId>50</QuestionId>") {
+		t.Errorf("unexpected answers: %+v", requests)
+	}
+
+	// Without pending question, vCD denies the access to the question
+	server.HandleXML(http.MethodGet, vmPath+"/question", http.StatusForbidden, "")
+	answered, err = vm.AnswerPendingQuestion("yes")
+	if err != nil || answered {
+		t.Errorf("expected no question to answer, got %t, %v", answered, err)
+	}
+}
+
+// Checks that the guest properties of a single VM can be updated and added
+func TestVM_ProductSectionList(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	sectionPath := "/api/vApp/vm-1/productSections/"
+	server.HandleXML(http.MethodGet, sectionPath, http.StatusOK,
+		`<ProductSectionList xmlns="http://www.vmware.com/vcloud/v1.5" xmlns:ovf="http://schemas.dmtf.org/ovf/envelope/1">
+  <ovf:ProductSection>
+    <ovf:Info>Custom properties</ovf:Info>
+    <ovf:Property ovf:key="hostname" ovf:type="string" ovf:userConfigurable="true" ovf:value="">
+      <ovf:Value ovf:value="vm1"/>
+    </ovf:Property>
+  </ovf:ProductSection>
+</ProductSectionList>`)
+	server.HandleXML(http.MethodPut, sectionPath, http.StatusAccepted,
+		`<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="running" href="{{server}}`+vcdtest.MockTaskPath+`"/>`)
+
+	vcdClient := newMockClient(t, server)
+	vm := NewVM(&vcdClient.Client)
+	vm.VM.HREF = server.URL() + "/api/vApp/vm-1"
+
+	productSectionList, err := vm.GetProductSectionList()
+	if err != nil {
+		t.Fatalf("error retrieving product section: %s", err)
+	}
+	properties := productSectionList.ProductSection.Property
+	if len(properties) != 1 || properties[0].Key != "hostname" || properties[0].Value.Value != "vm1" {
+		t.Fatalf("unexpected product section: %+v", productSectionList.ProductSection)
+	}
+
+	properties[0].Value = &types.Value{Value: "web1"}
+	productSectionList.ProductSection.Property = append(properties,
+		&types.Property{Key: "user-data", UserConfigurable: true, Value: &types.Value{Value: "I2Nsb3VkLWNvbmZpZw=="}})
+	_, err = vm.SetProductSectionList(productSectionList)
+	if err != nil {
+		t.Fatalf("error setting product section: %s", err)
+	}
+	requests := server.RequestsTo(http.MethodPut, sectionPath)
+	if len(requests) != 1 {
+		t.Fatalf("expected one update, got %d", len(requests))
+	}
+	for _, expected := range []string{`key="hostname"`, `value="web1"`, `key="user-data"`, `type="string"`, `value="I2Nsb3VkLWNvbmZpZw=="`} {
+		if !strings.Contains(requests[0].Body, expected) {
+			t.Errorf("expected %s in payload:\n%s", expected, requests[0].Body)
+		}
+	}
+
+	productSectionList.ProductSection.Property = append(productSectionList.ProductSection.Property,
+		&types.Property{Key: "hostname"})
+	_, err = vm.SetProductSectionList(productSectionList)
+	if err == nil {
+		t.Errorf("expected error with a duplicate property key")
+	}
+}
+
+// Checks the boot options sent to reconfigure a VM and their validation
+func TestVM_UpdateBootOptions(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	vmPath := "/api/vApp/vm-1"
+	reconfigurePath := vmPath + "/action/reconfigureVm"
+	vmXml := func(status int) string {
+		return `<Vm xmlns="http://www.vmware.com/vcloud/v1.5" xmlns:ovf="http://schemas.dmtf.org/ovf/envelope/1" name="vm1" status="` +
+			strconv.Itoa(status) + `" href="{{server}}` + vmPath + `">
+  <VmSpecSection Modified="false">
+    <ovf:Info>Virtual Machine specification</ovf:Info>
+    <OsType>otherGuest64</OsType>
+    <Firmware>bios</Firmware>
+  </VmSpecSection>
+  <BootOptions>
+    <BootDelay>0</BootDelay>
+    <EnterBIOSSetup>false</EnterBIOSSetup>
+    <BootRetryEnabled>false</BootRetryEnabled>
+    <BootRetryDelay>10000</BootRetryDelay>
+    <EfiSecureBootEnabled>false</EfiSecureBootEnabled>
+  </BootOptions>
+</Vm>`
+	}
+	server.HandleXML(http.MethodGet, vmPath, http.StatusOK, vmXml(8))
+	server.HandleXML(http.MethodPost, reconfigurePath, http.StatusAccepted,
+		`<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="running" href="{{server}}`+vcdtest.MockTaskPath+`"/>`)
+
+	vcdClient := newMockClient(t, server)
+	vm := NewVM(&vcdClient.Client)
+	vm.VM.HREF = server.URL() + vmPath
+
+	current, err := vm.GetBootOptions()
+	if err != nil {
+		t.Fatalf("error retrieving boot options: %s", err)
+	}
+	if current.Firmware != types.VmFirmwareBios || current.BootOptions.BootRetryDelay == nil || *current.BootOptions.BootRetryDelay != 10000 {
+		t.Errorf("unexpected boot options: %#v", current)
+	}
+
+	enabled := true
+	negative := -1
+	_, err = vm.UpdateBootOptions(&VmBootOptions{BootOptions: types.BootOptions{BootRetryEnabled: &enabled}})
+	if err == nil {
+		t.Errorf("expected error enabling boot retry with API version %s", vcdClient.Client.APIVersion)
+	}
+	vcdClient.Client.APIVersion = "33.0"
+	invalidOptions := []*VmBootOptions{
+		nil,
+		{Firmware: "uefi"},
+		{BootOptions: types.BootOptions{BootDelay: &negative}},
+		{BootOptions: types.BootOptions{EfiSecureBootEnabled: &enabled}},
+		{Firmware: types.VmFirmwareBios, BootOptions: types.BootOptions{EfiSecureBootEnabled: &enabled}},
+	}
+	for _, options := range invalidOptions {
+		if _, err = vm.UpdateBootOptions(options); err == nil {
+			t.Errorf("expected error updating boot options with %#v", options)
+		}
+	}
+	if len(server.RequestsTo(http.MethodPost, reconfigurePath)) != 0 {
+		t.Fatalf("expected no request with invalid boot options")
+	}
+
+	delay := 5000
+	_, err = vm.UpdateBootOptions(&VmBootOptions{
+		Firmware:    types.VmFirmwareEfi,
+		BootOptions: types.BootOptions{BootDelay: &delay, EnterBiosSetup: &enabled, EfiSecureBootEnabled: &enabled},
+	})
+	if err != nil {
+		t.Fatalf("error updating boot options: %s", err)
+	}
+	_, err = vm.UpdateBootOptions(&VmBootOptions{BootOptions: types.BootOptions{BootRetryEnabled: &enabled}})
+	if err != nil {
+		t.Fatalf("error enabling boot retry: %s", err)
+	}
+
+	requests := server.RequestsTo(http.MethodPost, reconfigurePath)
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 reconfigure requests, got %d", len(requests))
+	}
+	for _, expected := range []string{"<Firmware>efi</Firmware>", "<BootDelay>5000</BootDelay>", "<EnterBIOSSetup>true</EnterBIOSSetup>",
+		"<EfiSecureBootEnabled>true</EfiSecureBootEnabled>", `<VmSpecSection Modified="true">`} {
+		if !strings.Contains(requests[0].Body, expected) {
+			t.Errorf("expected %s in boot options update:\n%s", expected, requests[0].Body)
+		}
+	}
+	if strings.Contains(requests[0].Body, "<OsType>") || strings.Contains(requests[0].Body, "<BootRetryDelay>") {
+		t.Errorf("expected the other elements to be omitted:\n%s", requests[0].Body)
+	}
+	if strings.Contains(requests[1].Body, "VmSpecSection") || !strings.Contains(requests[1].Body, "<BootRetryEnabled>true</BootRetryEnabled>") {
+		t.Errorf("unexpected boot retry update:\n%s", requests[1].Body)
+	}
+
+	// The firmware of a powered on VM can not be changed
+	server.HandleXML(http.MethodGet, vmPath, http.StatusOK, vmXml(4))
+	_, err = vm.UpdateBootOptions(&VmBootOptions{Firmware: types.VmFirmwareEfi})
+	if err == nil {
+		t.Errorf("expected error changing the firmware of a powered on VM")
+	}
+}
+
+// Checks the reading and the changes of the extra configuration of a VM
+func TestVM_ExtraConfig(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	vmPath := "/api/vApp/vm-1"
+	reconfigurePath := vmPath + "/action/reconfigureVm"
+	server.HandleXML(http.MethodGet, vmPath, http.StatusOK,
+		`<Vm xmlns="http://www.vmware.com/vcloud/v1.5" xmlns:ovf="http://schemas.dmtf.org/ovf/envelope/1"
+    xmlns:vmw="http://www.vmware.com/schema/ovf" name="vm1" status="8" href="{{server}}`+vmPath+`">
+  <ovf:VirtualHardwareSection>
+    <ovf:Info>Virtual hardware requirements</ovf:Info>
+    <vmw:ExtraConfig ovf:required="false" vmw:key="disk.EnableUUID" vmw:value="TRUE"/>
+    <vmw:ExtraConfig ovf:required="false" vmw:key="tools.guest.desktop.autolock" vmw:value="FALSE"/>
+  </ovf:VirtualHardwareSection>
+</Vm>`)
+	server.HandleXML(http.MethodPost, reconfigurePath, http.StatusAccepted,
+		`<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="running" href="{{server}}`+vcdtest.MockTaskPath+`"/>`)
+
+	vcdClient := newMockClient(t, server)
+	vm := NewVM(&vcdClient.Client)
+	vm.VM.HREF = server.URL() + vmPath
+
+	_, err := vm.GetExtraConfig()
+	if err == nil {
+		t.Errorf("expected error retrieving extra configuration with API version %s", vcdClient.Client.APIVersion)
+	}
+	vcdClient.Client.APIVersion = "37.1"
+	extraConfig, err := vm.GetExtraConfig()
+	if err != nil {
+		t.Fatalf("error retrieving extra configuration: %s", err)
+	}
+	if len(extraConfig) != 2 || extraConfig[0].Key != "disk.EnableUUID" || extraConfig[0].Value != "TRUE" {
+		t.Errorf("unexpected extra configuration: %#v", extraConfig)
+	}
+	value, err := vm.GetExtraConfigValue("tools.guest.desktop.autolock")
+	if err != nil || value != "FALSE" {
+		t.Errorf("unexpected extra configuration value %s: %v", value, err)
+	}
+	_, err = vm.GetExtraConfigValue("isolation.tools.copy.disable")
+	if !errors.Is(err, ErrorEntityNotFound) {
+		t.Errorf("expected entity not found error for extra configuration entry, got %v", err)
+	}
+
+	for _, entries := range []map[string]string{nil, {"": "TRUE"}, {"disk.EnableUUID": ""}} {
+		if _, err = vm.UpdateExtraConfig(entries); err == nil {
+			t.Errorf("expected error updating extra configuration with %v", entries)
+		}
+	}
+	if _, err = vm.DeleteExtraConfig(); err == nil {
+		t.Errorf("expected error deleting no extra configuration entry")
+	}
+	if len(server.RequestsTo(http.MethodPost, reconfigurePath)) != 0 {
+		t.Fatalf("expected no request with invalid extra configuration entries")
+	}
+
+	_, err = vm.UpdateExtraConfig(map[string]string{"isolation.tools.copy.disable": "TRUE", "disk.EnableUUID": "FALSE"})
+	if err != nil {
+		t.Fatalf("error updating extra configuration: %s", err)
+	}
+	_, err = vm.DeleteExtraConfig("tools.guest.desktop.autolock")
+	if err != nil {
+		t.Fatalf("error deleting extra configuration: %s", err)
+	}
+
+	requests := server.RequestsTo(http.MethodPost, reconfigurePath)
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 reconfigure requests, got %d", len(requests))
+	}
+	for index, expected := range [][]*types.ExtraConfig{
+		{{Key: "disk.EnableUUID", Value: "FALSE"}, {Key: "isolation.tools.copy.disable", Value: "TRUE"}},
+		{{Key: "tools.guest.desktop.autolock"}},
+	} {
+		sent := types.VM{}
+		err = xml.Unmarshal([]byte(requests[index].Body), &sent)
+		if err != nil {
+			t.Fatalf("error decoding reconfiguration: %s", err)
+		}
+		if sent.Name != "vm1" || sent.VirtualHardwareSection == nil || len(sent.VirtualHardwareSection.ExtraConfig) != len(expected) {
+			t.Fatalf("unexpected reconfiguration:\n%s", requests[index].Body)
+		}
+		for entryIndex, entry := range expected {
+			if *sent.VirtualHardwareSection.ExtraConfig[entryIndex] != *entry {
+				t.Errorf("expected extra configuration entry %#v, got %#v", entry, sent.VirtualHardwareSection.ExtraConfig[entryIndex])
+			}
+		}
+		if strings.Contains(requests[index].Body, "<Item") {
+			t.Errorf("expected the virtual hardware items to be omitted:\n%s", requests[index].Body)
+		}
+	}
+}
+
+// Checks the CPU and memory items sent to change the resource allocation of a VM, and the nested
+// hypervisor toggle
+func TestVM_ResourceAllocation(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	vmPath := "/api/vApp/vm-1"
+	cpuPath := vmPath + "/virtualHardwareSection/cpu"
+	memoryPath := vmPath + "/virtualHardwareSection/memory"
+	enablePath := vmPath + "/action/enableNestedHypervisor"
+	server.HandleXML(http.MethodGet, vmPath, http.StatusOK,
+		`<Vm xmlns="http://www.vmware.com/vcloud/v1.5" xmlns:ovf="http://schemas.dmtf.org/ovf/envelope/1"
+    xmlns:rasd="http://schemas.dmtf.org/wbem/wscim/1/cim-schema/2/CIM_ResourceAllocationSettingData"
+    xmlns:vmw="http://www.vmware.com/schema/ovf"
+    name="vm1" status="8" nestedHypervisorEnabled="false" href="{{server}}`+vmPath+`">
+  <ovf:VirtualHardwareSection>
+    <ovf:Info>Virtual hardware requirements</ovf:Info>
+    <ovf:Item>
+      <rasd:AllocationUnits>hertz * 10^6</rasd:AllocationUnits>
+      <rasd:InstanceID>4</rasd:InstanceID>
+      <rasd:Limit>-1</rasd:Limit>
+      <rasd:Reservation>500</rasd:Reservation>
+      <rasd:ResourceType>3</rasd:ResourceType>
+      <rasd:VirtualQuantity>2</rasd:VirtualQuantity>
+      <rasd:Weight>2000</rasd:Weight>
+      <vmw:CoresPerSocket ovf:required="false">2</vmw:CoresPerSocket>
+    </ovf:Item>
+    <ovf:Item>
+      <rasd:AllocationUnits>byte * 2^20</rasd:AllocationUnits>
+      <rasd:InstanceID>5</rasd:InstanceID>
+      <rasd:Limit>4096</rasd:Limit>
+      <rasd:Reservation>1024</rasd:Reservation>
+      <rasd:ResourceType>4</rasd:ResourceType>
+      <rasd:VirtualQuantity>4096</rasd:VirtualQuantity>
+      <rasd:Weight>40960</rasd:Weight>
+    </ovf:Item>
+  </ovf:VirtualHardwareSection>
+</Vm>`)
+	taskXml := `<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="running" href="{{server}}` + vcdtest.MockTaskPath + `"/>`
+	for _, path := range []string{cpuPath, memoryPath} {
+		server.HandleXML(http.MethodPut, path, http.StatusAccepted, taskXml)
+	}
+	server.HandleXML(http.MethodPost, enablePath, http.StatusAccepted, taskXml)
+
+	vcdClient := newMockClient(t, server)
+	vm := NewVM(&vcdClient.Client)
+	vm.VM.HREF = server.URL() + vmPath
+
+	allocation, err := vm.GetResourceAllocation()
+	if err != nil {
+		t.Fatalf("error retrieving resource allocation: %s", err)
+	}
+	if *allocation.CpuReservation != 500 || *allocation.CpuLimit != -1 || *allocation.CpuShares != 2000 ||
+		*allocation.MemoryReservation != 1024 || *allocation.MemoryLimit != 4096 || *allocation.MemoryShares != 40960 {
+		t.Errorf("unexpected resource allocation: %#v", allocation)
+	}
+
+	negative, tooLow, reservation := -5, 100, 2048
+	invalidAllocations := []VmResourceAllocation{
+		{CpuReservation: &negative},
+		{MemoryShares: &negative},
+		{CpuLimit: &negative},
+		{MemoryReservation: &reservation, MemoryLimit: &tooLow},
+	}
+	for _, invalidAllocation := range invalidAllocations {
+		if err = vm.UpdateResourceAllocation(invalidAllocation); err == nil {
+			t.Errorf("expected error updating resource allocation with %#v", invalidAllocation)
+		}
+	}
+	if len(server.RequestsTo(http.MethodPut, cpuPath))+len(server.RequestsTo(http.MethodPut, memoryPath)) != 0 {
+		t.Fatalf("expected no request with invalid resource allocations")
+	}
+
+	// Only the memory item is sent, with the current values of what is not changed
+	err = vm.UpdateResourceAllocation(VmResourceAllocation{MemoryReservation: &reservation})
+	if err != nil {
+		t.Fatalf("error updating resource allocation: %s", err)
+	}
+	if len(server.RequestsTo(http.MethodPut, cpuPath)) != 0 {
+		t.Errorf("expected the CPU item to be left unchanged")
+	}
+	memoryPuts := server.RequestsTo(http.MethodPut, memoryPath)
+	if len(memoryPuts) != 1 {
+		t.Fatalf("expected 1 memory update, got %d", len(memoryPuts))
+	}
+	for _, expected := range []string{"<rasd:Reservation>2048</rasd:Reservation>", "<rasd:Limit>4096</rasd:Limit>",
+		"<rasd:Weight>40960</rasd:Weight>", "<rasd:VirtualQuantity>4096</rasd:VirtualQuantity>"} {
+		if !strings.Contains(memoryPuts[0].Body, expected) {
+			t.Errorf("expected %s in memory update:\n%s", expected, memoryPuts[0].Body)
+		}
+	}
+
+	// Changing the number of CPUs keeps their allocation
+	_, err = vm.ChangeCPUCount(4)
+	if err != nil {
+		t.Fatalf("error changing CPU count: %s", err)
+	}
+	cpuPuts := server.RequestsTo(http.MethodPut, cpuPath)
+	if len(cpuPuts) != 1 || !strings.Contains(cpuPuts[0].Body, "<rasd:Reservation>500</rasd:Reservation>") ||
+		!strings.Contains(cpuPuts[0].Body, "<rasd:Weight>2000</rasd:Weight>") || !strings.Contains(cpuPuts[0].Body, "<rasd:VirtualQuantity>4</rasd:VirtualQuantity>") {
+		t.Errorf("unexpected CPU count change: %#v", cpuPuts)
+	}
+
+	// Changing the CPU allocation keeps the number of CPUs and their cores per socket
+	shares := 4000
+	err = vm.UpdateResourceAllocation(VmResourceAllocation{CpuShares: &shares})
+	if err != nil {
+		t.Fatalf("error updating resource allocation: %s", err)
+	}
+	cpuPuts = server.RequestsTo(http.MethodPut, cpuPath)
+	if len(cpuPuts) != 2 {
+		t.Fatalf("expected 2 CPU updates, got %d", len(cpuPuts))
+	}
+	for _, expected := range []string{"<rasd:Weight>4000</rasd:Weight>", "<rasd:Reservation>500</rasd:Reservation>",
+		"<rasd:VirtualQuantity>2</rasd:VirtualQuantity>", "<vmw:CoresPerSocket>2</vmw:CoresPerSocket>"} {
+		if !strings.Contains(cpuPuts[1].Body, expected) {
+			t.Errorf("expected %s in CPU update:\n%s", expected, cpuPuts[1].Body)
+		}
+	}
+
+	// Nested hypervisor, only enabled when it is disabled
+	err = vm.ToggleNestedHypervisor(false)
+	if err != nil {
+		t.Fatalf("error disabling nested hypervisor: %s", err)
+	}
+	if len(server.RequestsTo(http.MethodPost, vmPath+"/action/disableNestedHypervisor")) != 0 {
+		t.Errorf("expected no request to disable an already disabled nested hypervisor")
+	}
+	err = vm.ToggleNestedHypervisor(true)
+	if err != nil {
+		t.Fatalf("error enabling nested hypervisor: %s", err)
+	}
+	if len(server.RequestsTo(http.MethodPost, enablePath)) != 1 {
+		t.Errorf("expected 1 request to enable the nested hypervisor")
+	}
+}
@@ -0,0 +1,329 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/util"
+)
+
+// defaultBusSubTypes are the controller models used when a disk is added to a new controller
+// without a bus sub type
+var defaultBusSubTypes = map[int]string{
+	types.ResourceTypeIDE:  "ide",
+	types.ResourceTypeSCSI: "lsilogic",
+	types.ResourceTypeSATA: "vmware.sata.ahci",
+}
+
+// InternalDisk is a hard disk of a VM, found in the disks section of its virtual hardware.
+// A disk is identified by its bus type, bus number and unit number.
+type InternalDisk struct {
+	// Id is the instance ID of the disk item. It is ignored when adding a disk.
+	Id int
+	// BusType is the resource type of the controller: types.ResourceTypeIDE, types.ResourceTypeSCSI
+	// or types.ResourceTypeSATA
+	BusType int
+	// BusSubType is the controller model, such as "lsilogic", "VirtualSCSI" or "ide". When adding a
+	// disk, it defaults to the model of the existing controller.
+	BusSubType string
+	// BusNumber is the address of the controller
+	BusNumber int
+	// UnitNumber is the address of the disk on its controller
+	UnitNumber int
+	SizeMb     int
+	// StorageProfile is the storage profile of the disk. When nil, the disk uses the storage
+	// profile of the VM.
+	StorageProfile *types.Reference
+	Iops           int
+}
+
+// GetInternalDisks returns the hard disks of the VM. The independent disks attached with AttachDisk
+// are not included.
+func (vm *VM) GetInternalDisks() ([]*InternalDisk, error) {
+	disks, err := vm.getDiskItems()
+	if err != nil {
+		return nil, err
+	}
+
+	var internalDisks []*InternalDisk
+	for _, item := range disks.Item {
+		if !isInternalDiskItem(item) {
+			continue
+		}
+		internalDisk, err := newInternalDisk(item, findParentItem(disks, item))
+		if err != nil {
+			return nil, err
+		}
+		internalDisks = append(internalDisks, internalDisk)
+	}
+	return internalDisks, nil
+}
+
+// AddInternalDisk adds a hard disk to the VM. When no controller of the bus type has the bus
+// number of the disk, a controller is added as well.
+func (vm *VM) AddInternalDisk(disk InternalDisk) (Task, error) {
+	if _, ok := defaultBusSubTypes[disk.BusType]; !ok {
+		return Task{}, fmt.Errorf("invalid bus type %d for internal disk", disk.BusType)
+	}
+	if disk.SizeMb <= 0 {
+		return Task{}, fmt.Errorf("invalid size %d MB for internal disk", disk.SizeMb)
+	}
+
+	disks, err := vm.getDiskItems()
+	if err != nil {
+		return Task{}, err
+	}
+	_, existing, err := findInternalDiskItem(disks, disk.BusType, disk.BusNumber, disk.UnitNumber)
+	if err != nil {
+		return Task{}, err
+	}
+	if existing != nil {
+		return Task{}, fmt.Errorf("VM %s already has a disk with bus type %d, bus number %d and unit number %d",
+			vm.VM.Name, disk.BusType, disk.BusNumber, disk.UnitNumber)
+	}
+
+	payload := newDiskItemsPayload(disks)
+	nextId := 0
+	for _, item := range payload.Item {
+		if item.InstanceID >= nextId {
+			nextId = item.InstanceID + 1
+		}
+	}
+
+	controller := findControllerItem(disks, disk.BusType, disk.BusNumber)
+	controllerId := 0
+	if controller != nil {
+		controllerId = controller.InstanceID
+		if disk.BusSubType == "" {
+			disk.BusSubType = controller.ResourceSubType
+		}
+	} else {
+		if disk.BusSubType == "" {
+			disk.BusSubType = defaultBusSubTypes[disk.BusType]
+		}
+		controllerId = nextId
+		nextId++
+		util.Logger.Printf("[TRACE] adding controller %s %d to VM %s", disk.BusSubType, disk.BusNumber, vm.VM.Name)
+		payload.Item = append(payload.Item, &types.OVFRasdItem{
+			Address:         strconv.Itoa(disk.BusNumber),
+			Description:     "Disk controller",
+			ElementName:     fmt.Sprintf("Disk controller %d", disk.BusNumber),
+			InstanceID:      controllerId,
+			ResourceSubType: disk.BusSubType,
+			ResourceType:    disk.BusType,
+		})
+	}
+
+	unitNumber := disk.UnitNumber
+	item := &types.OVFRasdItem{
+		AddressOnParent: &unitNumber,
+		Description:     "Hard disk",
+		ElementName:     "Hard disk",
+		HostResource:    []*types.OVFHostResource{{BusType: disk.BusType, BusSubType: disk.BusSubType}},
+		InstanceID:      nextId,
+		Parent:          controllerId,
+		ResourceType:    types.ResourceTypeDisk,
+	}
+	setInternalDiskBacking(item.HostResource[0], disk)
+	payload.Item = append(payload.Item, item)
+
+	return vm.updateDiskItems(payload, "error adding internal disk: %s")
+}
+
+// UpdateInternalDisk changes the size, storage profile and IOPS of the hard disk of the VM with
+// the bus type, bus number and unit number of disk
+func (vm *VM) UpdateInternalDisk(disk InternalDisk) (Task, error) {
+	if disk.SizeMb <= 0 {
+		return Task{}, fmt.Errorf("invalid size %d MB for internal disk", disk.SizeMb)
+	}
+
+	disks, err := vm.getDiskItems()
+	if err != nil {
+		return Task{}, err
+	}
+	index, existing, err := findInternalDiskItem(disks, disk.BusType, disk.BusNumber, disk.UnitNumber)
+	if err != nil {
+		return Task{}, err
+	}
+	if existing == nil {
+		return Task{}, fmt.Errorf("disk with bus type %d, bus number %d and unit number %d not found in VM %s",
+			disk.BusType, disk.BusNumber, disk.UnitNumber, vm.VM.Name)
+	}
+	if disk.SizeMb < existing.HostResource[0].Capacity {
+		return Task{}, fmt.Errorf("internal disk size cannot be reduced from %d MB to %d MB",
+			existing.HostResource[0].Capacity, disk.SizeMb)
+	}
+
+	payload := newDiskItemsPayload(disks)
+	setInternalDiskBacking(payload.Item[index].HostResource[0], disk)
+
+	return vm.updateDiskItems(payload, "error updating internal disk: %s")
+}
+
+// DeleteInternalDisk removes the hard disk of the VM with the given bus type, bus number and unit
+// number. The controller of the disk is left in place.
+func (vm *VM) DeleteInternalDisk(busType, busNumber, unitNumber int) (Task, error) {
+	disks, err := vm.getDiskItems()
+	if err != nil {
+		return Task{}, err
+	}
+	index, existing, err := findInternalDiskItem(disks, busType, busNumber, unitNumber)
+	if err != nil {
+		return Task{}, err
+	}
+	if existing == nil {
+		return Task{}, fmt.Errorf("disk with bus type %d, bus number %d and unit number %d not found in VM %s",
+			busType, busNumber, unitNumber, vm.VM.Name)
+	}
+
+	payload := newDiskItemsPayload(disks)
+	payload.Item = append(payload.Item[:index], payload.Item[index+1:]...)
+
+	return vm.updateDiskItems(payload, "error deleting internal disk: %s")
+}
+
+// getDiskItems retrieves the disks and controllers of the VM
+func (vm *VM) getDiskItems() (*types.RasdItemsList, error) {
+	if vm.VM.HREF == "" {
+		return nil, fmt.Errorf("cannot retrieve the disks of a VM without HREF")
+	}
+	disks := &types.RasdItemsList{}
+	_, err := vm.client.ExecuteRequest(vm.VM.HREF+"/virtualHardwareSection/disks", http.MethodGet,
+		types.MimeRasdItemsList, "error retrieving disks: %s", nil, disks)
+	if err != nil {
+		return nil, err
+	}
+	return disks, nil
+}
+
+// updateDiskItems sends the complete list of disks and controllers of the VM
+func (vm *VM) updateDiskItems(payload *types.OVFRasdItemsList, errorMessage string) (Task, error) {
+	payload.VCloudHREF = vm.VM.HREF + "/virtualHardwareSection/disks"
+	payload.VCloudType = types.MimeRasdItemsList
+	return vm.client.ExecuteTaskRequest(payload.VCloudHREF, http.MethodPut,
+		types.MimeRasdItemsList, errorMessage, payload)
+}
+
+// newDiskItemsPayload converts the items retrieved from vCD into the payload used to update them.
+// The items keep their positions.
+func newDiskItemsPayload(disks *types.RasdItemsList) *types.OVFRasdItemsList {
+	payload := &types.OVFRasdItemsList{
+		XmlnsRasd:   types.XMLNamespaceRASD,
+		XmlnsVCloud: types.XMLNamespaceVCloud,
+	}
+	for _, item := range disks.Item {
+		payloadItem := &types.OVFRasdItem{
+			Address:         item.Address,
+			Description:     item.Description,
+			ElementName:     item.ElementName,
+			InstanceID:      item.InstanceID,
+			Parent:          item.Parent,
+			ResourceSubType: item.ResourceSubType,
+			ResourceType:    item.ResourceType,
+		}
+		if item.ResourceType == types.ResourceTypeDisk {
+			unitNumber := item.AddressOnParent
+			payloadItem.AddressOnParent = &unitNumber
+		}
+		for _, hostResource := range item.HostResource {
+			payloadItem.HostResource = append(payloadItem.HostResource, &types.OVFHostResource{
+				BusSubType:        hostResource.BusSubType,
+				BusType:           hostResource.BusType,
+				Capacity:          hostResource.Capacity,
+				Disk:              hostResource.Disk,
+				Iops:              hostResource.Iops,
+				StorageProfile:    hostResource.StorageProfile,
+				OverrideVmDefault: hostResource.OverrideVmDefault,
+			})
+		}
+		payload.Item = append(payload.Item, payloadItem)
+	}
+	return payload
+}
+
+// setInternalDiskBacking sets the size, storage profile and IOPS of disk in hostResource
+func setInternalDiskBacking(hostResource *types.OVFHostResource, disk InternalDisk) {
+	hostResource.Capacity = disk.SizeMb
+	hostResource.Iops = disk.Iops
+	hostResource.StorageProfile = ""
+	hostResource.OverrideVmDefault = false
+	if disk.StorageProfile != nil && disk.StorageProfile.HREF != "" {
+		hostResource.StorageProfile = disk.StorageProfile.HREF
+		hostResource.OverrideVmDefault = true
+	}
+}
+
+// isInternalDiskItem tells whether item is a hard disk, rather than a controller or an
+// independent disk
+func isInternalDiskItem(item *types.VirtualHardwareItem) bool {
+	return item.ResourceType == types.ResourceTypeDisk && len(item.HostResource) > 0 && item.HostResource[0].Disk == ""
+}
+
+// findParentItem returns the controller of item, or nil when it is not in disks
+func findParentItem(disks *types.RasdItemsList, item *types.VirtualHardwareItem) *types.VirtualHardwareItem {
+	for _, parent := range disks.Item {
+		if parent.ResourceType != types.ResourceTypeDisk && parent.InstanceID == item.Parent {
+			return parent
+		}
+	}
+	return nil
+}
+
+// findControllerItem returns the controller with the given bus type and bus number, or nil
+func findControllerItem(disks *types.RasdItemsList, busType, busNumber int) *types.VirtualHardwareItem {
+	for _, item := range disks.Item {
+		if item.ResourceType == busType && item.Address == strconv.Itoa(busNumber) {
+			return item
+		}
+	}
+	return nil
+}
+
+// findInternalDiskItem returns the position and the item of the hard disk with the given bus type,
+// bus number and unit number. The item is nil when there is no such disk.
+func findInternalDiskItem(disks *types.RasdItemsList, busType, busNumber, unitNumber int) (int, *types.VirtualHardwareItem, error) {
+	for index, item := range disks.Item {
+		if !isInternalDiskItem(item) || item.HostResource[0].BusType != busType || item.AddressOnParent != unitNumber {
+			continue
+		}
+		disk, err := newInternalDisk(item, findParentItem(disks, item))
+		if err != nil {
+			return -1, nil, err
+		}
+		if disk.BusNumber == busNumber {
+			return index, item, nil
+		}
+	}
+	return -1, nil, nil
+}
+
+// newInternalDisk builds the description of the disk item, attached to the controller item
+func newInternalDisk(item, controller *types.VirtualHardwareItem) (*InternalDisk, error) {
+	if controller == nil {
+		return nil, fmt.Errorf("controller %d of disk %s not found", item.Parent, item.ElementName)
+	}
+	busNumber, err := strconv.Atoi(controller.Address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address '%s' for controller %s: %s", controller.Address, controller.ElementName, err)
+	}
+	hostResource := item.HostResource[0]
+	disk := &InternalDisk{
+		Id:         item.InstanceID,
+		BusType:    hostResource.BusType,
+		BusSubType: hostResource.BusSubType,
+		BusNumber:  busNumber,
+		UnitNumber: item.AddressOnParent,
+		SizeMb:     hostResource.Capacity,
+		Iops:       hostResource.Iops,
+	}
+	if hostResource.StorageProfile != "" {
+		disk.StorageProfile = &types.Reference{HREF: hostResource.StorageProfile}
+	}
+	return disk, nil
+}
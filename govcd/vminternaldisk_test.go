@@ -0,0 +1,154 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+const vmDiskItems = `<RasdItemsList xmlns="http://www.vmware.com/vcloud/v1.5" xmlns:rasd="http://schemas.dmtf.org/wbem/wscim/1/cim-schema/2/CIM_ResourceAllocationSettingData" type="application/vnd.vmware.vcloud.rasdItemsList+xml">
+  <Item>
+    <rasd:Address>0</rasd:Address>
+    <rasd:Description>SCSI Controller</rasd:Description>
+    <rasd:ElementName>SCSI Controller 0</rasd:ElementName>
+    <rasd:InstanceID>2</rasd:InstanceID>
+    <rasd:ResourceSubType>lsilogic</rasd:ResourceSubType>
+    <rasd:ResourceType>6</rasd:ResourceType>
+  </Item>
+  <Item>
+    <rasd:AddressOnParent>0</rasd:AddressOnParent>
+    <rasd:Description>Hard disk</rasd:Description>
+    <rasd:ElementName>Hard disk 1</rasd:ElementName>
+    <rasd:HostResource xmlns:vcloud="http://www.vmware.com/vcloud/v1.5" vcloud:capacity="16384" vcloud:busSubType="lsilogic" vcloud:busType="6" vcloud:iops="0" vcloud:storageProfileOverrideVmDefault="false"/>
+    <rasd:InstanceID>2000</rasd:InstanceID>
+    <rasd:Parent>2</rasd:Parent>
+    <rasd:ResourceType>17</rasd:ResourceType>
+  </Item>
+  <Item>
+    <rasd:AddressOnParent>1</rasd:AddressOnParent>
+    <rasd:Description>Hard disk</rasd:Description>
+    <rasd:ElementName>Hard disk 2</rasd:ElementName>
+    <rasd:HostResource xmlns:vcloud="http://www.vmware.com/vcloud/v1.5" vcloud:capacity="1024" vcloud:busSubType="lsilogic" vcloud:busType="6" vcloud:disk="https://vcd/api/disk/1"/>
+    <rasd:InstanceID>2001</rasd:InstanceID>
+    <rasd:Parent>2</rasd:Parent>
+    <rasd:ResourceType>17</rasd:ResourceType>
+  </Item>
+</RasdItemsList>`
+
+// Checks the payloads sent to add, update and delete the internal disks of a VM
+func TestVM_InternalDisks(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	disksPath := "/api/vApp/vm-1/virtualHardwareSection/disks"
+	server.HandleXML(http.MethodGet, disksPath, http.StatusOK, vmDiskItems)
+	server.HandleXML(http.MethodPut, disksPath, http.StatusAccepted,
+		`<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="running" href="{{server}}`+vcdtest.MockTaskPath+`"/>`)
+
+	vcdClient := newMockClient(t, server)
+	vm := NewVM(&vcdClient.Client)
+	vm.VM.Name = "web"
+	vm.VM.HREF = server.URL() + "/api/vApp/vm-1"
+
+	disks, err := vm.GetInternalDisks()
+	if err != nil {
+		t.Fatalf("error retrieving internal disks: %s", err)
+	}
+	if len(disks) != 1 || disks[0].Id != 2000 || disks[0].BusType != types.ResourceTypeSCSI ||
+		disks[0].BusNumber != 0 || disks[0].UnitNumber != 0 || disks[0].SizeMb != 16384 {
+		t.Fatalf("unexpected internal disks: %+v", disks)
+	}
+
+	// lastPayload returns the last update of the disks, without the indentation between the elements
+	lastPayload := func() string {
+		requests := server.RequestsTo(http.MethodPut, disksPath)
+		if len(requests) == 0 {
+			t.Fatalf("no update of the disks sent")
+		}
+		return regexp.MustCompile(`>\s+<`).ReplaceAllString(requests[len(requests)-1].Body, "><")
+	}
+
+	storageProfile := &types.Reference{HREF: server.URL() + "/api/vdcStorageProfile/1"}
+	_, err = vm.AddInternalDisk(InternalDisk{BusType: types.ResourceTypeSCSI, BusNumber: 0, UnitNumber: 2,
+		SizeMb: 2048, StorageProfile: storageProfile, Iops: 500})
+	if err != nil {
+		t.Fatalf("error adding internal disk: %s", err)
+	}
+	payload := lastPayload()
+	for _, expected := range []string{
+		`vcloud:capacity="2048"`,
+		`vcloud:iops="500"`,
+		`vcloud:storageProfileHref="` + storageProfile.HREF + `"`,
+		"<rasd:AddressOnParent>2</rasd:AddressOnParent>",
+		"<rasd:InstanceID>2002</rasd:InstanceID><rasd:Parent>2</rasd:Parent>",
+		`vcloud:disk="https://vcd/api/disk/1"`,
+	} {
+		if !strings.Contains(payload, expected) {
+			t.Errorf("expected %s in payload:\n%s", expected, payload)
+		}
+	}
+
+	_, err = vm.AddInternalDisk(InternalDisk{BusType: types.ResourceTypeSATA, BusNumber: 1, UnitNumber: 0, SizeMb: 1024})
+	if err != nil {
+		t.Fatalf("error adding internal disk: %s", err)
+	}
+	payload = lastPayload()
+	for _, expected := range []string{
+		"<rasd:Address>1</rasd:Address>",
+		"<rasd:ResourceSubType>vmware.sata.ahci</rasd:ResourceSubType><rasd:ResourceType>20</rasd:ResourceType>",
+		"<rasd:InstanceID>2003</rasd:InstanceID><rasd:Parent>2002</rasd:Parent>",
+	} {
+		if !strings.Contains(payload, expected) {
+			t.Errorf("expected %s in payload:\n%s", expected, payload)
+		}
+	}
+
+	_, err = vm.UpdateInternalDisk(InternalDisk{BusType: types.ResourceTypeSCSI, BusNumber: 0, UnitNumber: 0, SizeMb: 32768})
+	if err != nil {
+		t.Fatalf("error updating internal disk: %s", err)
+	}
+	payload = lastPayload()
+	if !strings.Contains(payload, `vcloud:capacity="32768"`) || strings.Contains(payload, `vcloud:capacity="16384"`) {
+		t.Errorf("unexpected update payload:\n%s", payload)
+	}
+
+	_, err = vm.DeleteInternalDisk(types.ResourceTypeSCSI, 0, 0)
+	if err != nil {
+		t.Fatalf("error deleting internal disk: %s", err)
+	}
+	payload = lastPayload()
+	if strings.Contains(payload, "Hard disk 1") || !strings.Contains(payload, "Hard disk 2") {
+		t.Errorf("unexpected delete payload:\n%s", payload)
+	}
+
+	requestCount := len(server.RequestsTo(http.MethodPut, disksPath))
+	for _, invalid := range []func() (Task, error){
+		func() (Task, error) {
+			return vm.AddInternalDisk(InternalDisk{BusType: types.ResourceTypeSCSI, UnitNumber: 0, SizeMb: 1024})
+		},
+		func() (Task, error) {
+			return vm.AddInternalDisk(InternalDisk{BusType: types.ResourceTypeUSB, SizeMb: 1024})
+		},
+		func() (Task, error) {
+			return vm.UpdateInternalDisk(InternalDisk{BusType: types.ResourceTypeSCSI, UnitNumber: 0, SizeMb: 1024})
+		},
+		func() (Task, error) {
+			return vm.DeleteInternalDisk(types.ResourceTypeSCSI, 0, 1)
+		},
+	} {
+		_, err = invalid()
+		if err == nil {
+			t.Errorf("expected error for invalid disk change")
+		}
+	}
+	if count := len(server.RequestsTo(http.MethodPut, disksPath)); count != requestCount {
+		t.Errorf("expected no update for invalid disk changes, got %d", count-requestCount)
+	}
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// VCenter is a vCenter server attached to vCD. vCenters are only available to system administrators.
+type VCenter struct {
+	VCenter *types.QueryResultVirtualCenterRecordType
+	client  *Client
+}
+
+// QueryVCenters returns the query records of all the vCenters attached to vCD
+func QueryVCenters(vcdClient *VCDClient) ([]*types.QueryResultVirtualCenterRecordType, error) {
+	results, err := vcdClient.Client.QueryAllPages("virtualCenter", nil)
+	if err != nil {
+		return nil, fmt.Errorf("error querying vCenters: %s", err)
+	}
+	return results.Results.VirtualCenterRecord, nil
+}
+
+// GetVCenterByName retrieves the vCenter with the given name
+func GetVCenterByName(vcdClient *VCDClient, name string) (*VCenter, error) {
+	records, err := QueryVCenters(vcdClient)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if record.Name == name {
+			return &VCenter{VCenter: record, client: &vcdClient.Client}, nil
+		}
+	}
+	return nil, fmt.Errorf("vCenter %s not found", name)
+}
+
+// GetAllResourcePools retrieves the resource pools of the vCenter which are not used by a
+// provider VDC yet, and can back a new one
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/GET-ResourcePoolList.html
+func (vcenter *VCenter) GetAllResourcePools() ([]*types.VMWResourcePool, error) {
+	href, err := vcenter.buildHref("/resourcePoolList")
+	if err != nil {
+		return nil, err
+	}
+	resourcePools := &types.VMWResourcePoolList{}
+	_, err = vcenter.client.ExecuteRequest(href, http.MethodGet,
+		"", "error retrieving resource pools: %s", nil, resourcePools)
+	if err != nil {
+		return nil, err
+	}
+	return resourcePools.ResourcePool, nil
+}
+
+// GetAllStorageProfiles retrieves the storage profiles of the vCenter, with their capacity
+// API Documentation: https://code.vmware.com/apis/220/vcloud#/doc/doc/operations/GET-StorageProfiles.html
+func (vcenter *VCenter) GetAllStorageProfiles() ([]*types.VMWStorageProfile, error) {
+	href, err := vcenter.buildHref("/storageProfiles")
+	if err != nil {
+		return nil, err
+	}
+	storageProfiles := &types.VMWStorageProfiles{}
+	_, err = vcenter.client.ExecuteRequest(href, http.MethodGet,
+		"", "error retrieving storage profiles: %s", nil, storageProfiles)
+	if err != nil {
+		return nil, err
+	}
+	return storageProfiles.VMWStorageProfile, nil
+}
+
+// QueryHosts returns the query records of the ESXi hosts managed by the vCenter
+func (vcenter *VCenter) QueryHosts() ([]*types.QueryResultHostRecordType, error) {
+	results, err := vcenter.client.QueryAllPages("host", &QueryOptions{
+		Filter: NewQueryFilter().Equal("vc", vcenter.VCenter.HREF),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error querying hosts: %s", err)
+	}
+	return results.Results.HostRecord, nil
+}
+
+// QueryDatastores returns the query records of the datastores of the vCenter
+func (vcenter *VCenter) QueryDatastores() ([]*types.QueryResultDatastoreRecordType, error) {
+	results, err := vcenter.client.QueryAllPages("datastore", &QueryOptions{
+		Filter: NewQueryFilter().Equal("vc", vcenter.VCenter.HREF),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error querying datastores: %s", err)
+	}
+	return results.Results.DatastoreRecord, nil
+}
+
+// VimServerRef returns the reference to the vCenter, as used in the vSphere object references
+// of provider VDCs and external networks
+func (vcenter *VCenter) VimServerRef() *types.Reference {
+	return &types.Reference{HREF: vcenter.VCenter.HREF, Name: vcenter.VCenter.Name, Type: "application/vnd.vmware.admin.vmwvirtualcenter+xml"}
+}
+
+// buildHref returns the HREF of a sub-resource of the vCenter
+func (vcenter *VCenter) buildHref(path string) (string, error) {
+	if vcenter.VCenter == nil || vcenter.VCenter.HREF == "" {
+		return "", fmt.Errorf("vCenter HREF is empty")
+	}
+	href, err := url.ParseRequestURI(vcenter.VCenter.HREF)
+	if err != nil {
+		return "", fmt.Errorf("error parsing vCenter url: %s", err)
+	}
+	href.Path += path
+	return href.String(), nil
+}
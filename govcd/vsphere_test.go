@@ -0,0 +1,50 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+
+	. "gopkg.in/check.v1"
+)
+
+func (vcd *TestVCD) Test_VSphereBrowsing(check *C) {
+	if vcd.skipAdminTests {
+		check.Skip(fmt.Sprintf(TestRequiresSysAdminPrivileges, check.TestName()))
+	}
+
+	records, err := QueryVCenters(vcd.client)
+	check.Assert(err, IsNil)
+	check.Assert(len(records), Not(Equals), 0)
+
+	vcenter, err := GetVCenterByName(vcd.client, records[0].Name)
+	check.Assert(err, IsNil)
+	check.Assert(vcenter.VCenter.HREF, Equals, records[0].HREF)
+	check.Assert(vcenter.VimServerRef().HREF, Equals, records[0].HREF)
+
+	_, err = GetVCenterByName(vcd.client, "non-existing-vcenter")
+	check.Assert(err, NotNil)
+
+	// All resource pools may already back provider VDCs, so the list can be empty
+	resourcePools, err := vcenter.GetAllResourcePools()
+	check.Assert(err, IsNil)
+	for _, resourcePool := range resourcePools {
+		check.Assert(resourcePool.MoRef, Not(Equals), "")
+	}
+
+	storageProfiles, err := vcenter.GetAllStorageProfiles()
+	check.Assert(err, IsNil)
+	check.Assert(len(storageProfiles), Not(Equals), 0)
+
+	hosts, err := vcenter.QueryHosts()
+	check.Assert(err, IsNil)
+	check.Assert(len(hosts), Not(Equals), 0)
+	check.Assert(hosts[0].VcHREF, Equals, vcenter.VCenter.HREF)
+
+	datastores, err := vcenter.QueryDatastores()
+	check.Assert(err, IsNil)
+	check.Assert(len(datastores), Not(Equals), 0)
+	check.Assert(datastores[0].MoRef, Not(Equals), "")
+}
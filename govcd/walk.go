@@ -0,0 +1,128 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// The walkers in this file retrieve the items of large inventories lazily, one item or one
+// query page at a time, and pass them to a callback. Only the current item or page is held in
+// memory. The walk stops at the first error returned by the callback, which is returned by the
+// walker, except for ErrStopWalk which stops the walk without error.
+
+// ErrStopWalk can be returned by a walker callback to stop the walk early without error
+var ErrStopWalk = errors.New("stop walk")
+
+// WalkQuery runs a query of the given type and passes its pages, one at a time, to walkFn,
+// following the nextPage links. options.MaxRecords is ignored, as the records are not held
+// together in memory. The first page is options.Page, or page 1 when 0.
+func (client *Client) WalkQuery(queryType string, options *QueryOptions, walkFn func(page *types.QueryResultRecordsType) error) error {
+	pageOptions := QueryOptions{}
+	if options != nil {
+		pageOptions = *options
+	}
+	if pageOptions.PageSize == 0 {
+		pageOptions.PageSize = DefaultQueryPageSize
+	}
+
+	results, err := client.Query(queryType, &pageOptions)
+	for {
+		if err != nil {
+			return err
+		}
+		err = walkFn(results.Results)
+		if err != nil {
+			return stopWalk(err)
+		}
+		nextPage := findQueryLink(results.Results.Link, types.RelNextPage)
+		if nextPage == "" {
+			return nil
+		}
+		results, err = client.queryByHref(nextPage)
+	}
+}
+
+// WalkVms passes the VM records of the org to walkFn, one query page at a time
+func (client *Client) WalkVms(options *QueryOptions, walkFn func(vm *types.QueryResultVMRecordType) error) error {
+	return client.WalkQuery(types.QtVm, options, func(page *types.QueryResultRecordsType) error {
+		for _, record := range page.VMRecord {
+			if err := walkFn(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// WalkVApps passes the vApp records of the org to walkFn, one query page at a time
+func (client *Client) WalkVApps(options *QueryOptions, walkFn func(vapp *types.QueryResultVAppRecordType) error) error {
+	return client.WalkQuery(types.QtVapp, options, func(page *types.QueryResultRecordsType) error {
+		for _, record := range page.VAppRecord {
+			if err := walkFn(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// WalkVApps retrieves the vApps of the VDC one at a time and passes them to walkFn
+func (vdc *Vdc) WalkVApps(walkFn func(vapp *VApp) error) error {
+	err := vdc.Refresh()
+	if err != nil {
+		return fmt.Errorf("error refreshing vdc: %s", err)
+	}
+	for _, resourceEntities := range vdc.Vdc.ResourceEntities {
+		for _, resourceEntity := range resourceEntities.ResourceEntity {
+			if resourceEntity.Type != types.MimeVApp {
+				continue
+			}
+			vapp := NewVApp(vdc.client)
+			_, err := vdc.client.ExecuteRequest(resourceEntity.HREF, http.MethodGet,
+				"", "error retrieving vApp: %s", nil, vapp.VApp)
+			if err != nil {
+				return err
+			}
+			err = walkFn(vapp)
+			if err != nil {
+				return stopWalk(err)
+			}
+		}
+	}
+	return nil
+}
+
+// WalkCatalogs retrieves the catalogs of the org one at a time and passes them to walkFn
+func (org *Org) WalkCatalogs(walkFn func(catalog *Catalog) error) error {
+	for _, link := range org.Org.Link {
+		if link.Rel != "down" || link.Type != types.MimeCatalog {
+			continue
+		}
+		catalog := NewCatalog(org.client)
+		_, err := org.client.ExecuteRequest(link.HREF, http.MethodGet,
+			"", "error retrieving catalog: %s", nil, catalog.Catalog)
+		if err != nil {
+			return err
+		}
+		err = walkFn(catalog)
+		if err != nil {
+			return stopWalk(err)
+		}
+	}
+	return nil
+}
+
+// stopWalk returns the error to return from a walker when the callback returned err
+func stopWalk(err error) error {
+	if err == ErrStopWalk {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"github.com/vmware/go-vcloud-director/v2/vcdtest"
+)
+
+// Checks that the query walkers retrieve the pages one at a time and stop when asked
+func TestClient_WalkVms(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+	server.HandleFunc(http.MethodGet, "/api/query", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("type") != "vm" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/vnd.vmware.vcloud.query.records+xml")
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		nextPage := ""
+		if page < 3 {
+			nextPage = fmt.Sprintf(`<Link rel="nextPage" href="http://%s/api/query?type=vm&amp;page=%d&amp;pageSize=2"/>`, r.Host, page+1)
+		}
+		_, _ = fmt.Fprintf(w, `<QueryResultRecords xmlns="http://www.vmware.com/vcloud/v1.5" total="6" page="%d" pageSize="2">%s
+<VMRecord name="vm%d-1"/><VMRecord name="vm%d-2"/></QueryResultRecords>`, page, nextPage, page, page)
+	})
+	client := &newMockClient(t, server).Client
+	requests := func() int {
+		return len(server.RequestsTo(http.MethodGet, "/api/query"))
+	}
+
+	var names []string
+	err := client.WalkVms(&QueryOptions{PageSize: 2}, func(vm *types.QueryResultVMRecordType) error {
+		names = append(names, vm.Name)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("error walking VMs: %s", err)
+	}
+	if len(names) != 6 || names[0] != "vm1-1" || names[5] != "vm3-2" {
+		t.Errorf("unexpected records: %v", names)
+	}
+	if requests() != 3 {
+		t.Errorf("expected 3 requests, got %d", requests())
+	}
+
+	// Stopping on the first record of the second page does not retrieve the third page
+	server.ClearRequests()
+	err = client.WalkVms(&QueryOptions{PageSize: 2}, func(vm *types.QueryResultVMRecordType) error {
+		if vm.Name == "vm2-1" {
+			return ErrStopWalk
+		}
+		return nil
+	})
+	if err != nil || requests() != 2 {
+		t.Errorf("expected walk stopped after 2 requests without error, got %d requests and %v", requests(), err)
+	}
+
+	walkErr := fmt.Errorf("walk error")
+	err = client.WalkQuery(types.QtVm, nil, func(page *types.QueryResultRecordsType) error {
+		return walkErr
+	})
+	if err != walkErr {
+		t.Errorf("expected callback error, got %v", err)
+	}
+}
+
+// Checks that the catalogs of the org are walked against the fake vCD
+func TestOrg_WalkCatalogs(t *testing.T) {
+	server := vcdtest.NewServer()
+	defer server.Close()
+
+	vcdClient := newMockClient(t, server)
+	org, err := GetOrgByName(vcdClient, vcdtest.MockOrgName)
+	if err != nil {
+		t.Fatalf("error retrieving org: %s", err)
+	}
+
+	var names []string
+	err = org.WalkCatalogs(func(catalog *Catalog) error {
+		names = append(names, catalog.Catalog.Name)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("error walking catalogs: %s", err)
+	}
+	if len(names) != 1 || names[0] != vcdtest.MockCatalogName {
+		t.Errorf("unexpected catalogs: %v", names)
+	}
+}
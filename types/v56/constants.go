@@ -68,8 +68,14 @@ const (
 	MimeNetworkConnectionSection = "application/vnd.vmware.vcloud.networkConnectionSection+xml"
 	// Mime for Item
 	MimeRasdItem = "application/vnd.vmware.vcloud.rasdItem+xml"
+	// Mime for a list of items, such as the disks of a VM
+	MimeRasdItemsList = "application/vnd.vmware.vcloud.rasdItemsList+xml"
+	// Mime for Org VDC network
+	MimeOrgVdcNetwork = "application/vnd.vmware.vcloud.orgVdcNetwork+xml"
 	// Mime for guest customization section
 	MimeGuestCustomizationSection = "application/vnd.vmware.vcloud.guestCustomizationSection+xml"
+	// Mime for the capabilities of a VM
+	MimeVmCapabilities = "application/vnd.vmware.vcloud.vmCapabilitiesSection+xml"
 	// Mime for network config section
 	MimeNetworkConfigSection = "application/vnd.vmware.vcloud.networkconfigsection+xml"
 	// Mime for recompose vApp params
@@ -90,12 +96,301 @@ const (
 	MimeMetaData = "application/vnd.vmware.vcloud.metadata+xml"
 	// Mime for metadata value
 	MimeMetaDataValue = "application/vnd.vmware.vcloud.metadata.value+xml"
+	// Mime for organization general settings
+	MimeOrgGeneralSettings = "application/vnd.vmware.admin.organizationGeneralSettings+xml"
+	// Mime for organization vApp lease settings
+	MimeVAppLeaseSettings = "application/vnd.vmware.admin.vAppLeaseSettings+xml"
+	// Mime for organization vApp template lease settings
+	MimeVAppTemplateLeaseSettings = "application/vnd.vmware.admin.vAppTemplateLeaseSettings+xml"
+	// Mime for the lease settings section of a vApp or vApp template
+	MimeLeaseSettingSection = "application/vnd.vmware.vcloud.leaseSettingsSection+xml"
+	// Mime for organization operation limits settings
+	MimeOrgOperationLimitsSettings = "application/vnd.vmware.admin.organizationOperationLimitsSettings+xml"
+	// Mime for organization email settings
+	MimeOrgEmailSettings = "application/vnd.vmware.admin.organizationEmailSettings+xml"
+	// Mime for organization LDAP settings
+	MimeOrgLdapSettings = "application/vnd.vmware.admin.organizationLdapSettings+xml"
+	// Mime for organization federation settings
+	MimeOrgFederationSettings = "application/vnd.vmware.admin.organizationFederationSettings+xml"
+	// Mime for an organization group
+	MimeAdminGroup = "application/vnd.vmware.admin.group+xml"
+	// Mime for an organization user
+	MimeAdminUser = "application/vnd.vmware.admin.user+xml"
+	// Mime for the owner of a vApp, media or independent disk
+	MimeOwner = "application/vnd.vmware.vcloud.owner+xml"
+	// Mime for an organization association member (multisite)
+	MimeOrgAssociationMember = "application/vnd.vmware.admin.organizationAssociationMember+xml"
+	// Mime for an external network
+	MimeExternalNetwork = "application/vnd.vmware.admin.vmwexternalnet+xml"
+	// Mime for an edge gateway
+	MimeEdgeGateway = "application/vnd.vmware.admin.edgeGateway+xml"
+	// Mime for a provider VDC
+	MimeProviderVdc = "application/vnd.vmware.admin.providervdc+xml"
+	// Mime for create provider VDC params
+	MimeCreateProviderVdcParams = "application/vnd.vmware.admin.createProviderVdcParams+xml"
+	// Mime for the AMQP broker settings
+	MimeAmqpSettings = "application/vnd.vmware.admin.amqpSettings+xml"
+	// Mime for an AMQP settings test
+	MimeAmqpSettingsTest = "application/vnd.vmware.admin.amqpSettingsTest+xml"
+	// Mime for an extension service
+	MimeAdminService = "application/vnd.vmware.admin.service+xml"
+	// Mime for an API filter of an extension service
+	MimeApiFilter = "application/vnd.vmware.admin.apiFilter+xml"
+	// Mime for an admin VDC
+	MimeAdminVdc = "application/vnd.vmware.admin.vdc+xml"
+	// Mime for create VDC params
+	MimeCreateVdcParams = "application/vnd.vmware.admin.createVdcParams+xml"
+	// Mime for the compute policies assigned to a VDC
+	MimeVdcComputePolicyReferences = "application/vnd.vmware.vcloud.vdcComputePolicyReferences+xml"
+	// Mime to capture a vApp into a vApp template
+	MimeCaptureVappParams = "application/vnd.vmware.vcloud.captureVAppParams+xml"
+	// Mime to copy or move a vApp within a VDC
+	MimeCloneVappParams = "application/vnd.vmware.vcloud.cloneVAppParams+xml"
+	// Mime for the access controls of a resource
+	MimeControlAccess = "application/vnd.vmware.vcloud.controlAccess+xml"
+	// Mime to publish a catalog to external organizations
+	MimePublishExternalCatalog = "application/vnd.vmware.admin.publishExternalCatalogParams+xml"
+	// Mime for the console ticket of a VM
+	MimeScreenTicket = "application/vnd.vmware.vcloud.screenTicket+xml"
+	// Mime for the WebMKS console ticket of a VM
+	MimeMksTicket = "application/vnd.vmware.vcloud.mksTicket+xml"
+)
+
+// Access levels of the subjects a resource is shared with
+const (
+	ControlAccessReadOnly    = "ReadOnly"
+	ControlAccessReadWrite   = "Change"
+	ControlAccessFullControl = "FullControl"
+)
+
+// Allocation models of an organization VDC
+const (
+	VdcAllocationModelAllocationVApp  = "AllocationVApp"  // Pay as you go
+	VdcAllocationModelAllocationPool  = "AllocationPool"  // Allocation pool
+	VdcAllocationModelReservationPool = "ReservationPool" // Reservation pool
+	VdcAllocationModelFlex            = "Flex"            // Flex, available from API 32.0
 )
 
 const (
 	VMsCDResourceSubType = "vmware.cdrom.iso"
 )
 
+// VcloudUndefinedKey is the bundle key for global roles and rights bundles created by users
+const VcloudUndefinedKey = "com.vmware.vcloud.undefined.key"
+
+// OpenAPI (cloudapi) paths and endpoints
+const (
+	OpenApiPathVersion1_0_0           = "1.0.0/"
+	OpenApiEndpointRights             = "rights/"
+	OpenApiEndpointGlobalRoles        = "globalRoles/"
+	OpenApiEndpointRightsBundles      = "rightsBundles/"
+	OpenApiEndpointTokens             = "tokens/"
+	OpenApiEndpointVdcComputePolicies = "vdcComputePolicies/"
+	OpenApiEndpointRoles              = "roles/"
+	OpenApiEndpointEdgeGateways       = "edgeGateways/"
+	OpenApiEndpointOrgVdcNetworks     = "orgVdcNetworks/"
+	OpenApiEndpointFirewallGroups     = "firewallGroups/"
+	OpenApiEndpointExternalNetworks   = "externalNetworks/"
+	OpenApiEndpointImportableTier0s   = "nsxTResources/importableTier0Routers"
+	OpenApiEndpointQosProfiles        = "nsxTResources/gatewayQoSProfiles"
+	OpenApiEndpointAuditTrail         = "auditTrail/"
+	OpenApiEndpointVdcGroups          = "vdcGroups/"
+	OpenApiEndpointVdcGroupCandidates = "vdcGroups/networkingCandidateVdcs"
+
+	// Network context profiles, which the rules of the distributed firewall can refer to
+	OpenApiEndpointNetworkContextProfiles = "networkContextProfiles/"
+
+	// IP spaces, API 37.1+
+	OpenApiEndpointIpSpaces         = "ipSpaces/"
+	OpenApiEndpointIpSpaceSummaries = "ipSpaces/summaries"
+	OpenApiEndpointIpSpaceUplinks   = "ipSpaceUplinks/"
+
+	// Segment profiles of the NSX-T managers, and the templates grouping them, API 37.0+
+	OpenApiEndpointSegmentIpDiscoveryProfiles  = "nsxTResources/segmentIpDiscoveryProfiles"
+	OpenApiEndpointSegmentMacDiscoveryProfiles = "nsxTResources/segmentMacDiscoveryProfiles"
+	OpenApiEndpointSegmentSpoofGuardProfiles   = "nsxTResources/segmentSpoofGuardProfiles"
+	OpenApiEndpointSegmentQosProfiles          = "nsxTResources/segmentQoSProfiles"
+	OpenApiEndpointSegmentSecurityProfiles     = "nsxTResources/segmentSecurityProfiles"
+	OpenApiEndpointSegmentProfileTemplates     = "segmentProfileTemplates/"
+
+	// Runtime defined entities (RDE), with their types and the interfaces the types implement
+	OpenApiEndpointRdeInterfaces  = "interfaces/"
+	OpenApiEndpointRdeEntityTypes = "entityTypes/"
+	OpenApiEndpointRdeEntities    = "entities/"
+
+	// Endpoints of an NSX-T edge gateway, formatted with its ID
+	OpenApiEndpointEdgeGatewayDhcpForwarder  = "edgeGateways/%s/dhcpForwarder"
+	OpenApiEndpointEdgeGatewayStaticRoutes   = "edgeGateways/%s/routing/staticRoutes/"
+	OpenApiEndpointEdgeGatewayBgp            = "edgeGateways/%s/routing/bgp"
+	OpenApiEndpointEdgeGatewayBgpNeighbors   = "edgeGateways/%s/routing/bgp/neighbors/"
+	OpenApiEndpointEdgeGatewayBgpPrefixLists = "edgeGateways/%s/routing/bgp/prefixLists/"
+	OpenApiEndpointEdgeGatewayUsedIps        = "edgeGateways/%s/usedIpAddresses"
+	OpenApiEndpointEdgeGatewayFirewallRules  = "edgeGateways/%s/firewall/rules"
+	OpenApiEndpointEdgeGatewaySlaacProfile   = "edgeGateways/%s/slaacProfile"
+	OpenApiEndpointEdgeGatewayQos            = "edgeGateways/%s/qos"
+
+	// Endpoints of an org VDC network, formatted with its ID
+	OpenApiEndpointOrgVdcNetworkSegmentProfiles = "orgVdcNetworks/%s/segmentProfiles"
+
+	// Endpoints of an external network, formatted with its ID
+	OpenApiEndpointExternalNetworkUsedIps = "externalNetworks/%s/usedIpAddresses"
+
+	// Endpoints of a VDC group, formatted with its ID
+	OpenApiEndpointVdcGroupDfwPolicies = "vdcGroups/%s/dfwPolicies"
+	// Endpoints of an IP space, formatted with its ID
+	OpenApiEndpointIpSpaceAllocate       = "ipSpaces/%s/allocate"
+	OpenApiEndpointIpSpaceAllocations    = "ipSpaces/%s/allocations/"
+	OpenApiEndpointIpSpaceOrgAssignments = "ipSpaces/%s/orgAssignments/"
+
+	// Endpoints of an RDE, of an RDE type or of an RDE interface, formatted with its ID
+	OpenApiEndpointRdeEntityResolve                    = "entities/%s/resolve"
+	OpenApiEndpointRdeInterfaceBehaviors               = "interfaces/%s/behaviors/"
+	OpenApiEndpointRdeEntityTypeBehaviors              = "entityTypes/%s/behaviors/"
+	OpenApiEndpointRdeEntityTypeBehaviorAccessControls = "entityTypes/%s/behaviorAccessControls"
+
+	// Rules of a policy of the distributed firewall of a VDC group, formatted with the IDs of the
+	// group and of the policy
+	OpenApiEndpointVdcGroupDfwRules = "vdcGroups/%s/dfwPolicies/%s/rules"
+
+	// Invocations of a behavior of an RDE, formatted with the IDs of the RDE and of the behavior
+	OpenApiEndpointRdeEntityBehaviorInvocations = "entities/%s/behaviors/%s/invocations"
+)
+
+// Types of the org VDC networks managed through OpenAPI
+const (
+	OrgVdcNetworkTypeRouted   = "NAT_ROUTED"
+	OrgVdcNetworkTypeIsolated = "ISOLATED"
+	OrgVdcNetworkTypeImported = "OPAQUE"
+)
+
+// Modes of the DHCP service of the NSX-T backed org VDC networks
+const (
+	NsxtDhcpModeEdge    = "EDGE"    // DHCP served by the edge gateway of a routed network
+	NsxtDhcpModeNetwork = "NETWORK" // DHCP served on the network itself, API 36.1+
+	NsxtDhcpModeRelay   = "RELAY"   // DHCP requests relayed to the DHCP forwarder of the edge gateway, API 36.1+
+)
+
+// Modes of the IPv6 address assignment of the NSX-T edge gateways (NsxtEdgeGatewaySlaacProfile)
+const (
+	NsxtSlaacModeSlaac    = "SLAAC"    // addresses configured by the hosts from the router advertisements
+	NsxtSlaacModeDhcpv6   = "DHCPv6"   // addresses leased by the DHCPv6 service of the networks
+	NsxtSlaacModeDisabled = "DISABLED" // no router advertisements
+)
+
+// Scopes of the NSX-T network context profiles
+const (
+	NsxtNetworkContextProfileScopeSystem   = "SYSTEM"
+	NsxtNetworkContextProfileScopeProvider = "PROVIDER"
+	NsxtNetworkContextProfileScopeTenant   = "TENANT"
+)
+
+// Types of the attributes of the NSX-T network context profiles
+const (
+	NsxtNetworkContextProfileAttributeAppId      = "APP_ID"
+	NsxtNetworkContextProfileAttributeDomainName = "DOMAIN_NAME"
+)
+
+// Types of the IP spaces
+const (
+	IpSpacePublic  = "PUBLIC"          // managed by the provider, shared by all the orgs
+	IpSpaceShared  = "SHARED_SERVICES" // managed by the provider, for the services it offers to the orgs
+	IpSpacePrivate = "PRIVATE"         // managed by the org owning it
+)
+
+// Types of the allocations of an IP space, and their usage states
+const (
+	IpSpaceAllocationFloatingIp = "FLOATING_IP" // an IP address of the ranges of the IP space
+	IpSpaceAllocationIpPrefix   = "IP_PREFIX"   // a prefix of the IP space
+
+	IpSpaceAllocationUnused     = "UNUSED"
+	IpSpaceAllocationUsed       = "USED"        // used by an entity of vCD, e.g. in a NAT rule of an edge gateway
+	IpSpaceAllocationUsedManual = "USED_MANUAL" // reserved by the org for a use outside of vCD
+)
+
+// Types of the NSX-T segment profiles
+const (
+	NsxtSegmentProfileIpDiscovery  = "IP_DISCOVERY"
+	NsxtSegmentProfileMacDiscovery = "MAC_DISCOVERY"
+	NsxtSegmentProfileSpoofGuard   = "SPOOF_GUARD"
+	NsxtSegmentProfileQos          = "QOS"
+	NsxtSegmentProfileSecurity     = "SEGMENT_SECURITY"
+)
+
+// States of the runtime defined entities (RDE). An RDE is created PRE_CREATED, and can only be used
+// once resolving it has validated its content against the schema of its type.
+const (
+	RdeStatePreCreated      = "PRE_CREATED"
+	RdeStateResolved        = "RESOLVED"
+	RdeStateResolutionError = "RESOLUTION_ERROR"
+)
+
+// Types of execution of the behaviors of RDE interfaces and types
+const (
+	RdeBehaviorExecutionNoop    = "noop" // does nothing, for tests and for behaviors to be overridden
+	RdeBehaviorExecutionWebhook = "WebHook"
+	RdeBehaviorExecutionMqtt    = "MQTT"
+	RdeBehaviorExecutionVro     = "VRO" // runs a vRealize Orchestrator workflow
+)
+
+// Access levels needed to invoke the behaviors of an RDE type
+const (
+	RdeAccessLevelReadOnly    = "urn:vcloud:accessLevel:ReadOnly"
+	RdeAccessLevelReadWrite   = "urn:vcloud:accessLevel:ReadWrite"
+	RdeAccessLevelFullControl = "urn:vcloud:accessLevel:FullControl"
+)
+
+// Graceful restart modes of the BGP service of the NSX-T edge gateways and of their neighbors
+const (
+	NsxtBgpGracefulRestartDisable           = "DISABLE"
+	NsxtBgpGracefulRestartHelperOnly        = "HELPER_ONLY"
+	NsxtBgpGracefulRestartGracefulAndHelper = "GRACEFUL_AND_HELPER"
+)
+
+// Actions of the prefixes of the BGP IP prefix lists, used as route filters of the BGP neighbors
+const (
+	NsxtBgpPrefixActionPermit = "PERMIT"
+	NsxtBgpPrefixActionDeny   = "DENY"
+)
+
+// Types of the NSX-T firewall groups
+const (
+	FirewallGroupTypeIpSet         = "IP_SET"
+	FirewallGroupTypeSecurityGroup = "SECURITY_GROUP"
+)
+
+// Actions, IP protocols and directions of the firewall rules of the NSX-T edge gateways
+const (
+	NsxtFirewallRuleActionAllow  = "ALLOW"
+	NsxtFirewallRuleActionDrop   = "DROP"
+	NsxtFirewallRuleActionReject = "REJECT"
+
+	NsxtFirewallRuleIpv4        = "IPV4"
+	NsxtFirewallRuleIpv6        = "IPV6"
+	NsxtFirewallRuleIpv4AndIpv6 = "IPV4_IPV6"
+
+	NsxtFirewallRuleDirectionIn    = "IN"
+	NsxtFirewallRuleDirectionOut   = "OUT"
+	NsxtFirewallRuleDirectionInOut = "IN_OUT"
+)
+
+// Scopes of the VDC groups
+const (
+	VdcGroupTypeLocal     = "LOCAL"     // VDCs of a single vCD site
+	VdcGroupTypeUniversal = "UNIVERSAL" // VDCs of several vCD sites, for NSX-V cross-VC networking
+)
+
+// Backing types of the external networks managed through OpenAPI
+const (
+	ExternalNetworkBackingTypeNsxtTier0Router    = "NSXT_TIER0"
+	ExternalNetworkBackingTypeNsxtVrfTier0Router = "NSXT_VRF_TIER0"
+	ExternalNetworkBackingTypeNsxtSegment        = "IMPORTED_T_LOGICAL_SWITCH"
+	ExternalNetworkBackingTypeDvPortgroup        = "DV_PORTGROUP"
+	ExternalNetworkBackingTypeNetwork            = "NETWORK"
+)
+
+// ApiTokenType is the type of the tokens listed by the OpenAPI tokens endpoint that are API tokens
+const ApiTokenType = "REFRESH"
+
 // https://blogs.vmware.com/vapp/2009/11/virtual-hardware-in-ovf-part-1.html
 
 const (
@@ -109,6 +404,7 @@ const (
 	ResourceTypeCD        int = 15
 	ResourceTypeDVD       int = 16
 	ResourceTypeDisk      int = 17
+	ResourceTypeSATA      int = 20
 	ResourceTypeUSB       int = 23
 )
 
@@ -125,6 +421,70 @@ const (
 	IPAllocationModePool   = "POOL"
 )
 
+// Adapter types of VM network connections
+const (
+	NetworkAdapterTypeVmxnet3 = "VMXNET3"
+	NetworkAdapterTypeE1000   = "E1000"
+	NetworkAdapterTypeE1000E  = "E1000E"
+	NetworkAdapterTypeSriov   = "SRIOVETHERNETCARD"
+)
+
+// Boot firmware of VMs
+const (
+	VmFirmwareBios = "bios"
+	VmFirmwareEfi  = "efi"
+)
+
+// Provider types for organization users and groups
+const (
+	OrgUserProviderIntegrated = "INTEGRATED" // Local users and LDAP users and groups
+	OrgUserProviderSAML       = "SAML"
+	OrgUserProviderOAUTH      = "OAUTH"
+)
+
+// LDAP modes, connector types and authentication mechanisms for organization LDAP settings
+const (
+	LdapModeNone   = "NONE"
+	LdapModeSystem = "SYSTEM"
+	LdapModeCustom = "CUSTOM"
+
+	LdapConnectorActiveDirectory = "ACTIVE_DIRECTORY"
+	LdapConnectorOpenLdap        = "OPEN_LDAP"
+
+	LdapAuthenticationSimple   = "SIMPLE"
+	LdapAuthenticationKerberos = "KERBEROS"
+	LdapAuthenticationMD5      = "MD5DIGEST"
+	LdapAuthenticationNTLM     = "NTLM"
+)
+
+// Types of the vSphere port groups backing external networks
+const (
+	PortGroupTypeDistributed = "DV_PORTGROUP" // distributed port group
+	PortGroupTypeStandard    = "NETWORK"      // standard switch port group
+)
+
+// Types of metadata values (xsi:type of TypedValue)
+const (
+	MetadataStringValue   = "MetadataStringValue"
+	MetadataNumberValue   = "MetadataNumberValue"
+	MetadataBooleanValue  = "MetadataBooleanValue"
+	MetadataDateTimeValue = "MetadataDateTimeValue"
+)
+
+// Metadata domains. Entries in the SYSTEM domain can only be changed by system administrators.
+const (
+	MetadataDomainGeneral = "GENERAL"
+	MetadataDomainSystem  = "SYSTEM"
+)
+
+// Visibility of metadata entries. GENERAL entries are READWRITE, SYSTEM entries are PRIVATE (only
+// visible to system administrators) or READONLY.
+const (
+	MetadataVisibilityReadWrite = "READWRITE"
+	MetadataVisibilityReadOnly  = "READONLY"
+	MetadataVisibilityPrivate   = "PRIVATE"
+)
+
 // NoneNetwork is a special type of network in vCD which represents a network card which is not
 // attached to any network.
 const (
@@ -132,10 +492,43 @@ const (
 )
 
 const (
-	XMLNamespaceVCloud = "http://www.vmware.com/vcloud/v1.5"
-	XMLNamespaceOVF    = "http://schemas.dmtf.org/ovf/envelope/1"
-	XMLNamespaceVMW    = "http://www.vmware.com/schema/ovf"
-	XMLNamespaceXSI    = "http://www.w3.org/2001/XMLSchema-instance"
-	XMLNamespaceRASD   = "http://schemas.dmtf.org/wbem/wscim/1/cim-schema/2/CIM_ResourceAllocationSettingData"
-	XMLNamespaceVSSD   = "http://schemas.dmtf.org/wbem/wscim/1/cim-schema/2/CIM_VirtualSystemSettingData"
+	XMLNamespaceVCloud    = "http://www.vmware.com/vcloud/v1.5"
+	XMLNamespaceExtension = "http://www.vmware.com/vcloud/extension/v1.5"
+	XMLNamespaceOVF       = "http://schemas.dmtf.org/ovf/envelope/1"
+	XMLNamespaceVMW       = "http://www.vmware.com/schema/ovf"
+	XMLNamespaceXSI       = "http://www.w3.org/2001/XMLSchema-instance"
+	XMLNamespaceRASD      = "http://schemas.dmtf.org/wbem/wscim/1/cim-schema/2/CIM_ResourceAllocationSettingData"
+	XMLNamespaceVSSD      = "http://schemas.dmtf.org/wbem/wscim/1/cim-schema/2/CIM_VirtualSystemSettingData"
+)
+
+// Query types of the query service (/api/query)
+const (
+	QtVm                        = "vm"                        // VMs of the org
+	QtAdminVm                   = "adminVM"                   // VMs of all orgs (system administrator)
+	QtVapp                      = "vApp"                      // vApps of the org
+	QtAdminVapp                 = "adminVApp"                 // vApps of all orgs (system administrator)
+	QtVappTemplate              = "vAppTemplate"              // vApp templates of the org
+	QtOrgVdcNetwork             = "orgVdcNetwork"             // Org VDC networks of the org
+	QtEdgeGateway               = "edgeGateway"               // Edge gateways of the org
+	QtMedia                     = "media"                     // Media of the org
+	QtAdminMedia                = "adminMedia"                // Media of all orgs (system administrator)
+	QtOrgVdcStorageProfile      = "orgVdcStorageProfile"      // Storage profiles of the org VDCs
+	QtAdminOrgVdcStorageProfile = "adminOrgVdcStorageProfile" // Storage profiles of the VDCs of all orgs (system administrator)
+	QtOrgVdc                    = "orgVdc"                    // VDCs of the org
+	QtAdminOrgVdc               = "adminOrgVdc"               // VDCs of all orgs (system administrator)
+	QtTask                      = "task"                      // Tasks of the org
+	QtAdminTask                 = "adminTask"                 // Tasks of all orgs (system administrator)
+	QtEvent                     = "event"                     // Events of the org
+	QtAdminEvent                = "adminEvent"                // Events of all orgs (system administrator)
+	QtNsxtManager               = "nsxTManager"               // NSX-T managers registered in vCD (system administrator)
+)
+
+// Paths of the NSX-V load balancer endpoints, relative to the proxied edge gateway (/network/edges/<id>)
+const (
+	EdgeLbConfigPath         = "/loadbalancer/config/"
+	EdgeLbAppProfilePath     = EdgeLbConfigPath + "applicationprofiles/"
+	EdgeLbServiceMonitorPath = EdgeLbConfigPath + "monitors/"
+	EdgeLbServerPoolPath     = EdgeLbConfigPath + "pools/"
+	EdgeLbAppRulePath        = EdgeLbConfigPath + "applicationrules/"
+	EdgeLbVirtualServerPath  = EdgeLbConfigPath + "virtualservers/"
 )
@@ -0,0 +1,125 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package types
+
+import "encoding/xml"
+
+// The types in this file are used by the NSX-V endpoints proxied by vCD (/network/edges/...),
+// which are available for the edge gateways with advanced networking. Their XML has no namespace.
+
+// NSXError is the error returned by the NSX-V endpoints
+type NSXError struct {
+	XMLName    xml.Name `xml:"error"`
+	ErrorCode  string   `xml:"errorCode"`
+	Details    string   `xml:"details"`
+	ModuleName string   `xml:"moduleName"`
+}
+
+// LbAppProfile is a load balancer application profile, which defines the behavior of the
+// traffic handled by a virtual server (persistence, SSL, HTTP redirection)
+type LbAppProfile struct {
+	XMLName                       xml.Name                  `xml:"applicationProfile"`
+	ID                            string                    `xml:"applicationProfileId,omitempty"`
+	Name                          string                    `xml:"name,omitempty"`
+	Template                      string                    `xml:"template,omitempty"` // TCP, UDP, HTTP or HTTPS
+	Persistence                   *LbAppProfilePersistence  `xml:"persistence,omitempty"`
+	HttpRedirect                  *LbAppProfileHttpRedirect `xml:"httpRedirect,omitempty"`
+	SslPassthrough                bool                      `xml:"sslPassthrough"`
+	InsertXForwardedForHttpHeader bool                      `xml:"insertXForwardedFor"`
+	ServerSslEnabled              bool                      `xml:"serverSslEnabled"`
+}
+
+// LbAppProfilePersistence defines how the clients are kept on the same pool member
+type LbAppProfilePersistence struct {
+	Method     string `xml:"method,omitempty"` // cookie, ssl_sessionid or sourceip
+	CookieName string `xml:"cookieName,omitempty"`
+	CookieMode string `xml:"cookieMode,omitempty"` // insert, prefix or app
+	Expire     int    `xml:"expire,omitempty"`
+}
+
+// LbAppProfileHttpRedirect defines the URL the HTTP requests are redirected to
+type LbAppProfileHttpRedirect struct {
+	To string `xml:"to,omitempty"`
+}
+
+// LbServiceMonitor is a load balancer service monitor, which checks the health of the pool members
+type LbServiceMonitor struct {
+	XMLName    xml.Name `xml:"monitor"`
+	ID         string   `xml:"monitorId,omitempty"`
+	Name       string   `xml:"name,omitempty"`
+	Type       string   `xml:"type"` // http, https, tcp, icmp or udp
+	Interval   int      `xml:"interval,omitempty"`
+	Timeout    int      `xml:"timeout,omitempty"`
+	MaxRetries int      `xml:"maxRetries,omitempty"`
+	Method     string   `xml:"method,omitempty"` // HTTP method of http and https monitors
+	URL        string   `xml:"url,omitempty"`
+	Expected   string   `xml:"expected,omitempty"`
+	Send       string   `xml:"send,omitempty"`
+	Receive    string   `xml:"receive,omitempty"`
+	Extension  string   `xml:"extension,omitempty"`
+}
+
+// LbPool is a load balancer server pool
+type LbPool struct {
+	XMLName             xml.Name        `xml:"pool"`
+	ID                  string          `xml:"poolId,omitempty"`
+	Name                string          `xml:"name"`
+	Description         string          `xml:"description,omitempty"`
+	Algorithm           string          `xml:"algorithm"` // round-robin, ip-hash, uri, leastconn, url or httpheader
+	AlgorithmParameters string          `xml:"algorithmParameters,omitempty"`
+	Transparent         bool            `xml:"transparent"`
+	MonitorId           string          `xml:"monitorId,omitempty"`
+	Members             []*LbPoolMember `xml:"member,omitempty"`
+}
+
+// LbPoolMember is a member of a load balancer server pool
+type LbPoolMember struct {
+	ID             string `xml:"memberId,omitempty"`
+	Name           string `xml:"name"`
+	IpAddress      string `xml:"ipAddress"`
+	Weight         int    `xml:"weight,omitempty"`
+	MonitorPort    int    `xml:"monitorPort,omitempty"`
+	Port           int    `xml:"port"`
+	MaxConnections int    `xml:"maxConn,omitempty"`
+	MinConnections int    `xml:"minConn,omitempty"`
+	Condition      string `xml:"condition,omitempty"` // enabled or disabled
+}
+
+// LbAppRule is a load balancer application rule, a script manipulating the traffic of a virtual server
+type LbAppRule struct {
+	XMLName xml.Name `xml:"applicationRule"`
+	ID      string   `xml:"applicationRuleId,omitempty"`
+	Name    string   `xml:"name,omitempty"`
+	Script  string   `xml:"script,omitempty"`
+}
+
+// LbVirtualServer is a load balancer virtual server, the entry point of the balanced traffic
+type LbVirtualServer struct {
+	XMLName              xml.Name `xml:"virtualServer"`
+	ID                   string   `xml:"virtualServerId,omitempty"`
+	Name                 string   `xml:"name,omitempty"`
+	Description          string   `xml:"description,omitempty"`
+	Enabled              bool     `xml:"enabled"`
+	IpAddress            string   `xml:"ipAddress"`
+	Protocol             string   `xml:"protocol"` // tcp, udp, http or https
+	Port                 int      `xml:"port"`
+	AccelerationEnabled  bool     `xml:"accelerationEnabled"`
+	ConnectionLimit      int      `xml:"connectionLimit,omitempty"`
+	ConnectionRateLimit  int      `xml:"connectionRateLimit,omitempty"`
+	ApplicationProfileId string   `xml:"applicationProfileId,omitempty"`
+	DefaultPoolId        string   `xml:"defaultPoolId,omitempty"`
+	ApplicationRuleIds   []string `xml:"applicationRuleId,omitempty"`
+}
+
+// LbConfig is the list of the load balancer entities of an edge gateway, as returned by the
+// NSX-V endpoints listing them
+type LbConfig struct {
+	XMLName             xml.Name            `xml:"loadBalancer"`
+	ApplicationProfiles []*LbAppProfile     `xml:"applicationProfile"`
+	Monitors            []*LbServiceMonitor `xml:"monitor"`
+	Pools               []*LbPool           `xml:"pool"`
+	ApplicationRules    []*LbAppRule        `xml:"applicationRule"`
+	VirtualServers      []*LbVirtualServer  `xml:"virtualServer"`
+}
@@ -0,0 +1,851 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package types
+
+import "encoding/json"
+
+// Types used with the OpenAPI (cloudapi) endpoints. Unlike the rest of this package,
+// these structures are exchanged in JSON format.
+
+// OpenApiPages unwraps pagination for "Get All" endpoints in OpenAPI. Values kept in json.RawMessage
+// helps to decouple marshalling paging related information from exact type related information.
+type OpenApiPages struct {
+	ResultTotal int             `json:"resultTotal"`
+	PageCount   int             `json:"pageCount"`
+	Page        int             `json:"page"`
+	PageSize    int             `json:"pageSize"`
+	Values      json.RawMessage `json:"values"`
+}
+
+// OpenApiError helps to marshal and provider meaningful Error() method for OpenAPI errors
+type OpenApiError struct {
+	MinorErrorCode string `json:"minorErrorCode"`
+	Message        string `json:"message"`
+	StackTrace     string `json:"stackTrace"`
+}
+
+// OpenApiReference is a generic reference type commonly used throughout OpenAPI endpoints
+type OpenApiReference struct {
+	Name string `json:"name,omitempty"`
+	ID   string `json:"id,omitempty"`
+}
+
+// OpenApiReferences is a list of OpenApiReference
+type OpenApiReferences []OpenApiReference
+
+// OpenApiItems wraps a list of references, as used when updating the rights or the tenants of a
+// global role or rights bundle
+type OpenApiItems struct {
+	Values OpenApiReferences `json:"values"`
+}
+
+// Right is a single right, which can be given to roles, global roles and rights bundles
+type Right struct {
+	Name             string            `json:"name"`
+	ID               string            `json:"id,omitempty"`
+	Description      string            `json:"description,omitempty"`
+	BundleKey        string            `json:"bundleKey,omitempty"`        // key used for internationalization
+	Category         string            `json:"category,omitempty"`         // Category ID
+	ServiceNamespace string            `json:"serviceNamespace,omitempty"` // Not used
+	RightType        string            `json:"rightType,omitempty"`        // VIEW or MODIFY
+	ImpliedRights    OpenApiReferences `json:"impliedRights,omitempty"`
+}
+
+// Role is a set of rights which can be given to the users and groups of an organization
+type Role struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	BundleKey   string `json:"bundleKey"`
+	ReadOnly    bool   `json:"readOnly"`
+}
+
+// GlobalRole is a role template defined by the provider, which can be published to tenants
+type GlobalRole struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	BundleKey   string `json:"bundleKey"`
+	ReadOnly    bool   `json:"readOnly"`
+	PublishAll  *bool  `json:"publishAll,omitempty"`
+}
+
+// RightsBundle is a set of rights that the provider makes available to tenants
+type RightsBundle struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	BundleKey   string `json:"bundleKey"`
+	ReadOnly    bool   `json:"readOnly"`
+	PublishAll  *bool  `json:"publishAll,omitempty"`
+}
+
+// ApiTokenClient is the OAuth client registered to create an API token
+type ApiTokenClient struct {
+	ClientID        string   `json:"client_id,omitempty"`
+	ClientName      string   `json:"client_name"`
+	GrantTypes      []string `json:"grant_types,omitempty"`
+	SoftwareID      string   `json:"software_id,omitempty"`
+	SoftwareVersion string   `json:"software_version,omitempty"`
+}
+
+// ApiTokenRefresh is the response of the OAuth token endpoint. The RefreshToken is the API token
+type ApiTokenRefresh struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Token is an entry of the OpenAPI tokens endpoint. API tokens have type ApiTokenType
+type Token struct {
+	ID     string            `json:"id,omitempty"`
+	Name   string            `json:"name"`
+	Token  string            `json:"token,omitempty"`
+	Expiry string            `json:"expiry,omitempty"`
+	Type   string            `json:"type"`
+	Owner  *OpenApiReference `json:"owner,omitempty"`
+	Org    *OpenApiReference `json:"org,omitempty"`
+}
+
+// VdcComputePolicy is a VDC compute policy, which can be a VM sizing policy (IsSizingOnly) or a
+// VM placement policy, which refers to a provider VDC compute policy (PvdcComputePolicy)
+type VdcComputePolicy struct {
+	ID                         string            `json:"id,omitempty"`
+	Description                string            `json:"description,omitempty"`
+	Name                       string            `json:"name"`
+	CPUSpeed                   *int              `json:"cpuSpeed,omitempty"`
+	Memory                     *int              `json:"memory,omitempty"`
+	CPUCount                   *int              `json:"cpuCount,omitempty"`
+	CoresPerSocket             *int              `json:"coresPerSocket,omitempty"`
+	MemoryReservationGuarantee *float64          `json:"memoryReservationGuarantee,omitempty"`
+	CPUReservationGuarantee    *float64          `json:"cpuReservationGuarantee,omitempty"`
+	CPULimit                   *int              `json:"cpuLimit,omitempty"`
+	MemoryLimit                *int              `json:"memoryLimit,omitempty"`
+	CPUShares                  *int              `json:"cpuShares,omitempty"`
+	MemoryShares               *int              `json:"memoryShares,omitempty"`
+	ExtraConfigs               map[string]string `json:"extraConfigs,omitempty"`
+	PvdcComputePolicyRefs      OpenApiReferences `json:"pvdcComputePolicyRefs,omitempty"`
+	PvdcComputePolicy          *OpenApiReference `json:"pvdcComputePolicy,omitempty"`
+	CompatibleVdcTypes         []string          `json:"compatibleVdcTypes,omitempty"`
+	IsSizingOnly               bool              `json:"isSizingOnly,omitempty"`
+	PvdcID                     string            `json:"pvdcId,omitempty"`
+}
+
+// NsxtEdgeGateway is an NSX-T backed edge gateway. It belongs to an org VDC or, with API 35.0+, to
+// a VDC group (OwnerRef), and is connected to NSX-T backed external networks through its uplinks.
+type NsxtEdgeGateway struct {
+	ID                        string                  `json:"id,omitempty"`
+	Name                      string                  `json:"name"`
+	Description               string                  `json:"description"`
+	Status                    string                  `json:"status,omitempty"`
+	OwnerRef                  *OpenApiReference       `json:"ownerRef,omitempty"` // org VDC or VDC group, API 35.0+
+	OrgVdc                    *OpenApiReference       `json:"orgVdc,omitempty"`   // org VDC, before API 35.0
+	Org                       *OpenApiReference       `json:"orgRef,omitempty"`
+	EdgeGatewayUplinks        []NsxtEdgeGatewayUplink `json:"edgeGatewayUplinks"`
+	DistributedRoutingEnabled *bool                   `json:"distributedRoutingEnabled,omitempty"`
+	EdgeClusterConfig         *NsxtEdgeClusterConfig  `json:"edgeClusterConfig,omitempty"`
+	OrgVdcNetworkCount        *int                    `json:"orgVdcNetworkCount,omitempty"`
+	GatewayBacking            *NsxtEdgeGatewayBacking `json:"gatewayBacking,omitempty"`
+	ServiceNetworkDefinition  string                  `json:"serviceNetworkDefinition,omitempty"`
+}
+
+// NsxtEdgeGatewayUplink connects an NSX-T edge gateway to an external network (UplinkID). The IP
+// addresses of the edge gateway are allocated from the subnets of the external network, either
+// with explicit ranges or with a number of addresses to allocate (QuickAddAllocatedIPCount).
+// A dedicated external network is used by this edge gateway only.
+type NsxtEdgeGatewayUplink struct {
+	UplinkID                 string                 `json:"uplinkId"`
+	UplinkName               string                 `json:"uplinkName,omitempty"`
+	Subnets                  NsxtEdgeGatewaySubnets `json:"subnets"`
+	Connected                bool                   `json:"connected"`
+	QuickAddAllocatedIPCount int                    `json:"quickAddAllocatedIpCount,omitempty"`
+	Dedicated                bool                   `json:"dedicated"`
+}
+
+// NsxtEdgeGatewaySubnets is the list of subnets of an uplink
+type NsxtEdgeGatewaySubnets struct {
+	Values []NsxtEdgeGatewaySubnet `json:"values"`
+}
+
+// NsxtEdgeGatewaySubnet is a subnet of the external network of an uplink, with the IP addresses
+// allocated to the edge gateway
+type NsxtEdgeGatewaySubnet struct {
+	Gateway              string           `json:"gateway"`
+	PrefixLength         int              `json:"prefixLength"`
+	DNSSuffix            string           `json:"dnsSuffix,omitempty"`
+	DNSServer1           string           `json:"dnsServer1,omitempty"`
+	DNSServer2           string           `json:"dnsServer2,omitempty"`
+	IPRanges             *OpenApiIPRanges `json:"ipRanges,omitempty"`
+	Enabled              bool             `json:"enabled"`
+	TotalIPCount         *int             `json:"totalIpCount,omitempty"`
+	UsedIPCount          int              `json:"usedIpCount,omitempty"`
+	PrimaryIP            string           `json:"primaryIp,omitempty"`
+	AutoAllocateIPRanges bool             `json:"autoAllocateIpRanges,omitempty"`
+}
+
+// OpenApiIPRanges is a list of IP ranges
+type OpenApiIPRanges struct {
+	Values []OpenApiIPRange `json:"values"`
+}
+
+// OpenApiIPRange is a range of IP addresses, from StartAddress to EndAddress included
+type OpenApiIPRange struct {
+	StartAddress string `json:"startAddress"`
+	EndAddress   string `json:"endAddress"`
+}
+
+// NsxtEdgeGatewayUsedIpAddress is an IP address of an NSX-T edge gateway used by one of its services,
+// e.g. a NAT rule, an IPsec VPN tunnel or a load balancer virtual service (Category)
+type NsxtEdgeGatewayUsedIpAddress struct {
+	NetworkRef OpenApiReference `json:"networkRef"`
+	IPAddress  string           `json:"ipAddress"`
+	Category   string           `json:"category"`
+}
+
+// NsxtEdgeClusterConfig sets the NSX-T edge cluster running the edge gateway
+type NsxtEdgeClusterConfig struct {
+	PrimaryEdgeCluster NsxtEdgeCluster `json:"primaryEdgeCluster"`
+}
+
+// NsxtEdgeCluster refers to an NSX-T edge cluster, by its vCD reference or its NSX-T ID
+type NsxtEdgeCluster struct {
+	EdgeClusterRef *OpenApiReference `json:"edgeClusterRef,omitempty"`
+	BackingID      string            `json:"backingId,omitempty"`
+}
+
+// NsxtEdgeGatewayBacking describes the NSX-T tier-1 gateway backing an edge gateway
+type NsxtEdgeGatewayBacking struct {
+	BackingID       string            `json:"backingId,omitempty"`
+	GatewayType     string            `json:"gatewayType,omitempty"`
+	NetworkProvider *OpenApiReference `json:"networkProvider,omitempty"`
+}
+
+// OpenApiOrgVdcNetwork is an org VDC network as managed through OpenAPI. NetworkType tells whether
+// the network is routed through an edge gateway (Connection), isolated, or imported from an
+// existing NSX-T segment (BackingNetworkId).
+type OpenApiOrgVdcNetwork struct {
+	ID                      string                          `json:"id,omitempty"`
+	Name                    string                          `json:"name"`
+	Description             string                          `json:"description"`
+	OwnerRef                *OpenApiReference               `json:"ownerRef,omitempty"` // org VDC or VDC group, API 35.0+
+	OrgVdc                  *OpenApiReference               `json:"orgVdc,omitempty"`   // org VDC, before API 35.0
+	Status                  string                          `json:"status,omitempty"`
+	NetworkType             string                          `json:"networkType"` // NAT_ROUTED, ISOLATED or OPAQUE
+	Subnets                 OpenApiOrgVdcNetworkSubnets     `json:"subnets"`
+	Connection              *OpenApiOrgVdcNetworkConnection `json:"connection,omitempty"`
+	BackingNetworkId        string                          `json:"backingNetworkId,omitempty"`
+	BackingNetworkType      string                          `json:"backingNetworkType,omitempty"`
+	ParentNetwork           *OpenApiReference               `json:"parentNetwork,omitempty"`
+	GuestVlanTaggingAllowed *bool                           `json:"guestVlanTaggingAllowed,omitempty"`
+	Shared                  *bool                           `json:"shared,omitempty"`
+	TotalIpCount            *int                            `json:"totalIpCount,omitempty"`
+	UsedIpCount             *int                            `json:"usedIpCount,omitempty"`
+}
+
+// OpenApiOrgVdcNetworkSubnets is the list of subnets of an org VDC network
+type OpenApiOrgVdcNetworkSubnets struct {
+	Values []OpenApiOrgVdcNetworkSubnet `json:"values"`
+}
+
+// OpenApiOrgVdcNetworkSubnet is a subnet of an org VDC network, with its DNS configuration and the
+// static IP pool used by the VMs
+type OpenApiOrgVdcNetworkSubnet struct {
+	Gateway      string          `json:"gateway"`
+	PrefixLength int             `json:"prefixLength"`
+	DNSServer1   string          `json:"dnsServer1"`
+	DNSServer2   string          `json:"dnsServer2"`
+	DNSSuffix    string          `json:"dnsSuffix"`
+	IPRanges     OpenApiIPRanges `json:"ipRanges"`
+}
+
+// OpenApiOrgVdcNetworkConnection connects a routed org VDC network to an edge gateway (RouterRef)
+type OpenApiOrgVdcNetworkConnection struct {
+	RouterRef      OpenApiReference `json:"routerRef"`
+	ConnectionType string           `json:"connectionType,omitempty"` // INTERNAL, SUBINTERFACE or DISTRIBUTED
+}
+
+// OpenApiOrgVdcNetworkDhcp is the DHCP service of an NSX-T backed org VDC network
+type OpenApiOrgVdcNetworkDhcp struct {
+	Enabled    *bool                          `json:"enabled,omitempty"`
+	LeaseTime  *int                           `json:"leaseTime,omitempty"`
+	DhcpPools  []OpenApiOrgVdcNetworkDhcpPool `json:"dhcpPools,omitempty"`
+	Mode       string                         `json:"mode,omitempty"`      // EDGE or NETWORK, API 36.1+
+	IPAddress  string                         `json:"ipAddress,omitempty"` // address of the DHCP service in NETWORK mode
+	DnsServers []string                       `json:"dnsServers,omitempty"`
+}
+
+// OpenApiEntityVersion is the version of an OpenAPI entity, which changes with each update. An update
+// must send the version of the entity it modifies, otherwise it fails.
+type OpenApiEntityVersion struct {
+	Version int `json:"version"`
+}
+
+// NsxtEdgeGatewayDhcpForwarder forwards the DHCP requests of the org VDC networks of an NSX-T edge
+// gateway whose DHCP mode is NsxtDhcpModeRelay to external DHCP servers
+type NsxtEdgeGatewayDhcpForwarder struct {
+	Enabled     bool                  `json:"enabled"`
+	DhcpServers []string              `json:"dhcpServers,omitempty"`
+	Version     *OpenApiEntityVersion `json:"version,omitempty"`
+}
+
+// NsxtEdgeGatewaySlaacProfile sets how the hosts of the IPv6 org VDC networks connected to an NSX-T
+// edge gateway get their addresses: the edge sends router advertisements (ND) telling them to use
+// SLAAC (NsxtSlaacModeSlaac) or the DHCPv6 service of their network (NsxtSlaacModeDhcpv6)
+type NsxtEdgeGatewaySlaacProfile struct {
+	Enabled   bool                                  `json:"enabled"`
+	Mode      string                                `json:"mode"`
+	DNSConfig *NsxtEdgeGatewaySlaacProfileDNSConfig `json:"dnsConfig,omitempty"`
+}
+
+// NsxtEdgeGatewaySlaacProfileDNSConfig is the DNS configuration advertised to the hosts using SLAAC
+type NsxtEdgeGatewaySlaacProfileDNSConfig struct {
+	DNSServerIpv6Addresses []string `json:"dnsServerIpv6Addresses,omitempty"`
+	DomainNames            []string `json:"domainNames,omitempty"`
+}
+
+// NsxtEdgeGatewayStaticRoute is a static route of an NSX-T edge gateway, sending the traffic to
+// NetworkCidr through one of its next hops. Routes with the same name are allowed.
+type NsxtEdgeGatewayStaticRoute struct {
+	ID          string                              `json:"id,omitempty"`
+	Name        string                              `json:"name"`
+	Description string                              `json:"description,omitempty"`
+	NetworkCidr string                              `json:"networkCidr"`
+	NextHops    []NsxtEdgeGatewayStaticRouteNextHop `json:"nextHops"`
+	SystemOwned *bool                               `json:"systemOwned,omitempty"`
+	Version     *OpenApiEntityVersion               `json:"version,omitempty"`
+}
+
+// NsxtEdgeGatewayStaticRouteNextHop is a next hop of a static route. Among the next hops of a
+// route, the one with the lowest AdminDistance (1 to 255) is preferred. Scope restricts the next
+// hop to an org VDC network or to an external network.
+type NsxtEdgeGatewayStaticRouteNextHop struct {
+	IPAddress     string                              `json:"ipAddress"`
+	AdminDistance int                                 `json:"adminDistance"`
+	Scope         *NsxtEdgeGatewayStaticRouteHopScope `json:"scope,omitempty"`
+}
+
+// NsxtEdgeGatewayStaticRouteHopScope is the network where a next hop of a static route is reached
+type NsxtEdgeGatewayStaticRouteHopScope struct {
+	ID        string `json:"id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	ScopeType string `json:"scopeType,omitempty"` // NETWORK or SYSTEM_OWNED
+}
+
+// NsxtEdgeGatewayBgpConfig is the BGP service of an NSX-T edge gateway. LocalASNumber is the
+// autonomous system of the edge gateway, as a number or in the asdot format (e.g. 65000.100).
+type NsxtEdgeGatewayBgpConfig struct {
+	Enabled         bool                               `json:"enabled"`
+	LocalASNumber   string                             `json:"localASNumber,omitempty"`
+	Ecmp            bool                               `json:"ecmp"`
+	GracefulRestart *NsxtEdgeGatewayBgpGracefulRestart `json:"gracefulRestart,omitempty"`
+	Version         *OpenApiEntityVersion              `json:"version,omitempty"`
+}
+
+// NsxtEdgeGatewayBgpGracefulRestart is the graceful restart of the BGP service, with timers in seconds
+type NsxtEdgeGatewayBgpGracefulRestart struct {
+	Mode            string `json:"mode"` // One of the NsxtBgpGracefulRestart* constants
+	RestartTimer    int    `json:"restartTimer,omitempty"`
+	StaleRouteTimer int    `json:"staleRouteTimer,omitempty"`
+}
+
+// NsxtEdgeGatewayBgpNeighbor is a BGP peer of an NSX-T edge gateway. The routes received from and
+// advertised to the neighbor can be filtered with BGP IP prefix lists (InRoutesFilterRef and
+// OutRoutesFilterRef). Timers are in seconds.
+type NsxtEdgeGatewayBgpNeighbor struct {
+	ID                     string                `json:"id,omitempty"`
+	NeighborAddress        string                `json:"neighborAddress"`
+	RemoteASNumber         string                `json:"remoteASNumber"`
+	KeepAliveTimer         int                   `json:"keepAliveTimer,omitempty"`
+	HoldDownTimer          int                   `json:"holdDownTimer,omitempty"`
+	NeighborPassword       string                `json:"neighborPassword,omitempty"` // write only
+	AllowASIn              bool                  `json:"allowASIn"`
+	GracefulRestartMode    string                `json:"gracefulRestartMode,omitempty"`    // One of the NsxtBgpGracefulRestart* constants
+	IpAddressTypeFiltering string                `json:"ipAddressTypeFiltering,omitempty"` // IPV4, IPV6 or DISABLED
+	InRoutesFilterRef      *OpenApiReference     `json:"inRoutesFilterRef,omitempty"`
+	OutRoutesFilterRef     *OpenApiReference     `json:"outRoutesFilterRef,omitempty"`
+	Version                *OpenApiEntityVersion `json:"version,omitempty"`
+}
+
+// NsxtEdgeGatewayBgpIpPrefixList is a list of prefixes used as route filter by the BGP neighbors of
+// an NSX-T edge gateway
+type NsxtEdgeGatewayBgpIpPrefixList struct {
+	ID          string                       `json:"id,omitempty"`
+	Name        string                       `json:"name"`
+	Description string                       `json:"description,omitempty"`
+	Prefixes    []NsxtEdgeGatewayBgpIpPrefix `json:"prefixes"`
+	Version     *OpenApiEntityVersion        `json:"version,omitempty"`
+}
+
+// NsxtEdgeGatewayBgpIpPrefix permits or denies the routes to Network whose prefix length is in the
+// optional range GreaterThanEqualTo-LessThanEqualTo
+type NsxtEdgeGatewayBgpIpPrefix struct {
+	Network            string `json:"network"`
+	Action             string `json:"action"` // NsxtBgpPrefixActionPermit or NsxtBgpPrefixActionDeny
+	GreaterThanEqualTo *int   `json:"greaterThanEqualTo,omitempty"`
+	LessThanEqualTo    *int   `json:"lessThanEqualTo,omitempty"`
+}
+
+// OpenApiOrgVdcNetworkDhcpPool is a range of IP addresses leased by the DHCP service
+type OpenApiOrgVdcNetworkDhcpPool struct {
+	Enabled          *bool          `json:"enabled,omitempty"`
+	IPRange          OpenApiIPRange `json:"ipRange"`
+	MaxLeaseTime     *int           `json:"maxLeaseTime,omitempty"`
+	DefaultLeaseTime *int           `json:"defaultLeaseTime,omitempty"`
+}
+
+// NsxtFirewallGroup is a group of NSX-T entities used as source or destination of firewall and NAT
+// rules. An IP set (FirewallGroupTypeIpSet) lists IP addresses, CIDRs and ranges, while a security
+// group (FirewallGroupTypeSecurityGroup) has org VDC networks as members. The owner is an NSX-T edge
+// gateway or, with API 35.0+, a VDC group.
+type NsxtFirewallGroup struct {
+	ID          string             `json:"id,omitempty"`
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Type        string             `json:"type"`
+	IpAddresses []string           `json:"ipAddresses,omitempty"`
+	Members     []OpenApiReference `json:"members,omitempty"`
+	OwnerRef    *OpenApiReference  `json:"ownerRef,omitempty"`
+	Status      string             `json:"status,omitempty"`
+}
+
+// NsxtFirewallRules is the firewall of an NSX-T edge gateway. The system rules are applied first and
+// the default rules last; only the user defined rules can be changed, as a whole.
+type NsxtFirewallRules struct {
+	SystemRules      []*NsxtFirewallRule `json:"systemRules,omitempty"`
+	DefaultRules     []*NsxtFirewallRule `json:"defaultRules,omitempty"`
+	UserDefinedRules []*NsxtFirewallRule `json:"userDefinedRules"`
+}
+
+// NsxtFirewallRule is a rule of the firewall of an NSX-T edge gateway. Sources and destinations are
+// firewall groups, the traffic is matched by application port profiles; empty lists match any.
+type NsxtFirewallRule struct {
+	ID                        string                `json:"id,omitempty"`
+	Name                      string                `json:"name"`
+	Description               string                `json:"description,omitempty"`
+	ActionValue               string                `json:"actionValue"` // One of the NsxtFirewallRuleAction* constants
+	Enabled                   bool                  `json:"enabled"`
+	SourceFirewallGroups      []OpenApiReference    `json:"sourceFirewallGroups,omitempty"`
+	DestinationFirewallGroups []OpenApiReference    `json:"destinationFirewallGroups,omitempty"`
+	ApplicationPortProfiles   []OpenApiReference    `json:"applicationPortProfiles,omitempty"`
+	IpProtocol                string                `json:"ipProtocol,omitempty"` // One of the NsxtFirewallRuleIp* constants
+	Direction                 string                `json:"direction,omitempty"`  // One of the NsxtFirewallRuleDirection* constants
+	Logging                   bool                  `json:"logging"`
+	Version                   *OpenApiEntityVersion `json:"version,omitempty"`
+}
+
+// VdcGroup is a group of org VDCs (data center group) sharing networks, edge gateways and a
+// distributed firewall. The participating VDCs must be networking candidates of each other (see
+// AdminOrg.GetVdcGroupCandidateVdcs). Needs API 35.0+ (vCD 10.2+).
+type VdcGroup struct {
+	ID                         string                `json:"id,omitempty"`
+	Name                       string                `json:"name"`
+	Description                string                `json:"description,omitempty"`
+	OrgID                      string                `json:"orgId"`
+	Type                       string                `json:"type,omitempty"` // VdcGroupTypeLocal or VdcGroupTypeUniversal
+	ParticipatingOrgVdcs       []ParticipatingOrgVdc `json:"participatingOrgVdcs"`
+	LocalEgress                bool                  `json:"localEgress"`
+	UniversalNetworkingEnabled bool                  `json:"universalNetworkingEnabled"`
+	NetworkPoolID              string                `json:"networkPoolId,omitempty"`
+	NetworkPoolUniversalID     string                `json:"networkPoolUniversalId,omitempty"`
+	NetworkProviderType        string                `json:"networkProviderType,omitempty"` // NSX_T or NSX_V, read-only
+	DfwEnabled                 bool                  `json:"dfwEnabled,omitempty"`          // read-only, see VdcGroup.ActivateDfw
+	Status                     string                `json:"status,omitempty"`
+	ErrorMessage               string                `json:"errorMessage,omitempty"`
+}
+
+// ParticipatingOrgVdc is a member of a VDC group
+type ParticipatingOrgVdc struct {
+	VdcRef               OpenApiReference  `json:"vdcRef"`
+	OrgRef               *OpenApiReference `json:"orgRef,omitempty"`
+	SiteRef              *OpenApiReference `json:"siteRef,omitempty"`
+	NetworkProviderScope string            `json:"networkProviderScope,omitempty"`
+	FaultDomainTag       string            `json:"faultDomainTag,omitempty"`
+	RemoteOrg            bool              `json:"remoteOrg"`
+	Status               string            `json:"status,omitempty"`
+}
+
+// CandidateVdc is an org VDC which can join a VDC group with a given VDC
+type CandidateVdc struct {
+	ID                   string           `json:"id"`
+	Name                 string           `json:"name"`
+	OrgRef               OpenApiReference `json:"orgRef"`
+	SiteRef              OpenApiReference `json:"siteRef"`
+	NetworkProviderScope string           `json:"networkProviderScope,omitempty"`
+	FaultDomainTag       string           `json:"faultDomainTag,omitempty"`
+}
+
+// VdcGroupDfwPolicies is the state of the distributed firewall of a VDC group. The default policy
+// allows or drops the traffic which matches no rule.
+type VdcGroupDfwPolicies struct {
+	Enabled       bool               `json:"enabled"`
+	DefaultPolicy *VdcGroupDfwPolicy `json:"defaultPolicy,omitempty"`
+}
+
+// VdcGroupDfwPolicy is a policy of the distributed firewall of a VDC group
+type VdcGroupDfwPolicy struct {
+	ID          string                `json:"id,omitempty"`
+	Name        string                `json:"name,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Enabled     *bool                 `json:"enabled,omitempty"`
+	Version     *OpenApiEntityVersion `json:"version,omitempty"`
+}
+
+// DistributedFirewallRules is the ordered list of the rules of a policy of the distributed firewall
+// of a VDC group, which vCD replaces as a whole
+type DistributedFirewallRules struct {
+	Values []*DistributedFirewallRule `json:"values"`
+}
+
+// DistributedFirewallRule is a rule of the distributed firewall of a VDC group. Besides the firewall
+// groups and the application port profiles of the edge gateway rules (NsxtFirewallRule), the traffic
+// can be matched by network context profiles, which identify layer 7 applications.
+type DistributedFirewallRule struct {
+	ID                        string                `json:"id,omitempty"`
+	Name                      string                `json:"name"`
+	Description               string                `json:"description,omitempty"`
+	ActionValue               string                `json:"actionValue"` // One of the NsxtFirewallRuleAction* constants
+	Enabled                   bool                  `json:"enabled"`
+	SourceFirewallGroups      []OpenApiReference    `json:"sourceFirewallGroups,omitempty"`
+	DestinationFirewallGroups []OpenApiReference    `json:"destinationFirewallGroups,omitempty"`
+	ApplicationPortProfiles   []OpenApiReference    `json:"applicationPortProfiles,omitempty"`
+	NetworkContextProfiles    []OpenApiReference    `json:"networkContextProfiles,omitempty"`
+	IpProtocol                string                `json:"ipProtocol,omitempty"` // One of the NsxtFirewallRuleIp* constants
+	Direction                 string                `json:"direction,omitempty"`  // One of the NsxtFirewallRuleDirection* constants
+	Logging                   bool                  `json:"logging"`
+	Version                   *OpenApiEntityVersion `json:"version,omitempty"`
+}
+
+// NsxtNetworkContextProfile is a network context profile, which matches the traffic of layer 7
+// applications (NsxtNetworkContextProfileAttributeAppId) or of domains in the rules of the
+// distributed firewall. The profiles of scope SYSTEM are predefined by NSX-T.
+type NsxtNetworkContextProfile struct {
+	ID              string                               `json:"id,omitempty"`
+	Name            string                               `json:"name"`
+	Description     string                               `json:"description,omitempty"`
+	Scope           string                               `json:"scope,omitempty"` // One of the NsxtNetworkContextProfileScope* constants
+	Attributes      []NsxtNetworkContextProfileAttribute `json:"attributes,omitempty"`
+	OrgRef          *OpenApiReference                    `json:"orgRef,omitempty"`
+	ContextEntityId string                               `json:"contextEntityId,omitempty"`
+}
+
+// NsxtNetworkContextProfileAttribute is a criterion of a network context profile, e.g. the APP_ID
+// values of the applications it matches
+type NsxtNetworkContextProfileAttribute struct {
+	Type   string   `json:"type"`
+	Values []string `json:"values"`
+}
+
+// ExternalNetworkV2 is an external network as managed through OpenAPI, which supports both the
+// networks backed by vSphere port groups and the NSX-T backed ones (tier-0 routers or segments)
+type ExternalNetworkV2 struct {
+	ID              string                    `json:"id,omitempty"`
+	Name            string                    `json:"name"`
+	Description     string                    `json:"description"`
+	Subnets         ExternalNetworkV2Subnets  `json:"subnets"`
+	NetworkBackings ExternalNetworkV2Backings `json:"networkBackings"`
+}
+
+// ExternalNetworkV2Subnets is the list of subnets of an external network
+type ExternalNetworkV2Subnets struct {
+	Values []ExternalNetworkV2Subnet `json:"values"`
+}
+
+// ExternalNetworkV2Subnet is a subnet of an external network, with the IP pools which can be
+// allocated to edge gateways
+type ExternalNetworkV2Subnet struct {
+	Gateway      string          `json:"gateway"`
+	PrefixLength int             `json:"prefixLength"`
+	DNSSuffix    string          `json:"dnsSuffix,omitempty"`
+	DNSServer1   string          `json:"dnsServer1,omitempty"`
+	DNSServer2   string          `json:"dnsServer2,omitempty"`
+	IPRanges     OpenApiIPRanges `json:"ipRanges"`
+	Enabled      bool            `json:"enabled"`
+	UsedIPCount  int             `json:"usedIpCount,omitempty"`
+	TotalIPCount int             `json:"totalIpCount,omitempty"`
+}
+
+// ExternalNetworkV2UsedIpAddress is an IP address of an external network allocated to a VM or to an
+// edge gateway
+type ExternalNetworkV2UsedIpAddress struct {
+	EntityID       string            `json:"entityId,omitempty"`
+	EntityName     string            `json:"entityName,omitempty"`
+	VAppName       string            `json:"vAppName,omitempty"`
+	IPAddress      string            `json:"ipAddress"`
+	AllocationType string            `json:"allocationType,omitempty"`
+	Deployed       bool              `json:"deployed"`
+	OrgRef         *OpenApiReference `json:"orgRef,omitempty"`
+	VdcRef         *OpenApiReference `json:"vdcRef,omitempty"`
+}
+
+// ExternalNetworkV2Backings is the list of backings of an external network
+type ExternalNetworkV2Backings struct {
+	Values []ExternalNetworkV2Backing `json:"values"`
+}
+
+// ExternalNetworkV2Backing is the vSphere or NSX-T network backing an external network.
+// NetworkProvider is the vCenter server or the NSX-T manager owning BackingID.
+type ExternalNetworkV2Backing struct {
+	BackingID        string           `json:"backingId"`
+	Name             string           `json:"name,omitempty"`
+	BackingType      string           `json:"backingType,omitempty"`      // before API 35.0
+	BackingTypeValue string           `json:"backingTypeValue,omitempty"` // API 35.0+
+	NetworkProvider  OpenApiReference `json:"networkProvider"`
+}
+
+// NsxtTier0Router is an NSX-T tier-0 router which can back an external network
+type NsxtTier0Router struct {
+	ID            string `json:"id"`
+	Description   string `json:"description"`
+	DisplayName   string `json:"displayName"`
+	ParentTier0ID string `json:"parentTier0Id,omitempty"` // set for VRF tier-0 routers
+}
+
+// NsxtEdgeGatewayQosProfile is a gateway QoS profile of an NSX-T manager, which limits the bandwidth
+// of the traffic going through the NSX-T edge gateways it is assigned to
+type NsxtEdgeGatewayQosProfile struct {
+	ID                 string            `json:"id"`
+	DisplayName        string            `json:"displayName"`
+	Description        string            `json:"description,omitempty"`
+	NsxtManagerRef     *OpenApiReference `json:"nsxTManagerRef,omitempty"`
+	CommittedBandwidth int               `json:"committedBandwidth"` // in Mb/s
+	BurstSize          int               `json:"burstSize"`          // in bytes
+	ExcessAction       string            `json:"excessAction"`       // what to do with the exceeding traffic, e.g. DROP
+}
+
+// NsxtEdgeGatewayQos is the QoS configuration of an NSX-T edge gateway: the gateway QoS profiles
+// limiting its ingress and egress traffic. A nil profile means that the traffic is not limited.
+type NsxtEdgeGatewayQos struct {
+	IngressProfile *OpenApiReference `json:"ingressProfile"`
+	EgressProfile  *OpenApiReference `json:"egressProfile"`
+}
+
+// NsxtSegmentProfile is a segment profile of an NSX-T manager, which sets a feature of the NSX-T
+// segments backing the org VDC networks: IP or MAC discovery, spoof guard, QoS or segment security.
+// Segment profiles are defined in NSX-T.
+type NsxtSegmentProfile struct {
+	ID             string            `json:"id"`
+	DisplayName    string            `json:"displayName"`
+	Description    string            `json:"description,omitempty"`
+	NsxtManagerRef *OpenApiReference `json:"nsxTManagerRef,omitempty"`
+}
+
+// NsxtSegmentProfileTemplate groups segment profiles of an NSX-T manager, to apply them at once to
+// org VDC networks. A nil profile keeps the default of NSX-T.
+type NsxtSegmentProfileTemplate struct {
+	ID                     string            `json:"id,omitempty"`
+	Name                   string            `json:"name"`
+	Description            string            `json:"description,omitempty"`
+	SourceNsxtManagerRef   *OpenApiReference `json:"sourceNsxTManagerRef"`
+	IPDiscoveryProfile     *OpenApiReference `json:"ipDiscoveryProfile,omitempty"`
+	MacDiscoveryProfile    *OpenApiReference `json:"macDiscoveryProfile,omitempty"`
+	SpoofGuardProfile      *OpenApiReference `json:"spoofGuardProfile,omitempty"`
+	QosProfile             *OpenApiReference `json:"qosProfile,omitempty"`
+	SegmentSecurityProfile *OpenApiReference `json:"segmentSecurityProfile,omitempty"`
+}
+
+// OrgVdcNetworkSegmentProfiles are the segment profiles of an NSX-T backed org VDC network: either
+// those of a template (SegmentProfileTemplate) or individual ones. A nil profile keeps the default
+// of NSX-T.
+type OrgVdcNetworkSegmentProfiles struct {
+	SegmentProfileTemplate *OpenApiReference `json:"segmentProfileTemplate"`
+	IPDiscoveryProfile     *OpenApiReference `json:"ipDiscoveryProfile"`
+	MacDiscoveryProfile    *OpenApiReference `json:"macDiscoveryProfile"`
+	SpoofGuardProfile      *OpenApiReference `json:"spoofGuardProfile"`
+	QosProfile             *OpenApiReference `json:"qosProfile"`
+	SegmentSecurityProfile *OpenApiReference `json:"segmentSecurityProfile"`
+}
+
+// IpSpace is a set of IP ranges and prefixes from which the orgs allocate floating IP addresses
+// (IpSpaceRanges) and network prefixes (IpSpacePrefixes), within quotas. The internal scope lists the
+// CIDRs of the IP space, the external scope the networks reachable through its uplinks.
+type IpSpace struct {
+	ID                        string            `json:"id,omitempty"`
+	Name                      string            `json:"name"`
+	Description               string            `json:"description,omitempty"`
+	Type                      string            `json:"type"`             // One of the IpSpacePublic, IpSpaceShared or IpSpacePrivate constants
+	OrgRef                    *OpenApiReference `json:"orgRef,omitempty"` // owner of an IpSpacePrivate IP space
+	IPSpaceInternalScope      []string          `json:"ipSpaceInternalScope"`
+	IPSpaceExternalScope      string            `json:"ipSpaceExternalScope,omitempty"`
+	IPSpaceRanges             IpSpaceRanges     `json:"ipSpaceRanges"`
+	IPSpacePrefixes           []IpSpacePrefixes `json:"ipSpacePrefixes"`
+	RouteAdvertisementEnabled bool              `json:"routeAdvertisementEnabled"`
+	Status                    string            `json:"status,omitempty"`
+}
+
+// IpSpaceRanges are the IP ranges of an IP space, from which floating IP addresses are allocated.
+// Each org can allocate up to DefaultFloatingIPQuota addresses, -1 meaning unlimited.
+type IpSpaceRanges struct {
+	IPRanges               []IpSpaceRange `json:"ipRanges"`
+	DefaultFloatingIPQuota int            `json:"defaultFloatingIPQuota"`
+}
+
+// IpSpaceRange is an IP range of an IP space
+type IpSpaceRange struct {
+	ID             string `json:"id,omitempty"`
+	StartIPAddress string `json:"startIPAddress"`
+	EndIPAddress   string `json:"endIPAddress"`
+}
+
+// IpSpacePrefixes are the prefixes of an IP space with the same length. Each org can allocate up to
+// DefaultQuotaForPrefixLength of them, -1 meaning unlimited.
+type IpSpacePrefixes struct {
+	IPPrefixSequence            []IpSpacePrefixSequence `json:"ipPrefixSequence"`
+	DefaultQuotaForPrefixLength int                     `json:"defaultQuotaForPrefixLength"`
+}
+
+// IpSpacePrefixSequence is a sequence of TotalPrefixCount consecutive prefixes of PrefixLength bits,
+// starting at StartingPrefixIPAddress
+type IpSpacePrefixSequence struct {
+	ID                      string `json:"id,omitempty"`
+	StartingPrefixIPAddress string `json:"startingPrefixIpAddress"`
+	PrefixLength            int    `json:"prefixLength"`
+	TotalPrefixCount        int    `json:"totalPrefixCount"`
+}
+
+// IpSpaceUplink connects an IP space to an NSX-T backed external network (provider gateway), so that
+// the edge gateways connected to the external network can use the IP space
+type IpSpaceUplink struct {
+	ID                 string            `json:"id,omitempty"`
+	Name               string            `json:"name"`
+	Description        string            `json:"description,omitempty"`
+	ExternalNetworkRef *OpenApiReference `json:"externalNetworkRef"`
+	IPSpaceRef         *OpenApiReference `json:"ipSpaceRef"`
+	Status             string            `json:"status,omitempty"`
+}
+
+// IpSpaceIpAllocationRequest requests the allocation of floating IP addresses or of prefixes of an
+// IP space to an org: either a Quantity of them (prefixes of PrefixLength bits), or a specific Value
+type IpSpaceIpAllocationRequest struct {
+	Type         string `json:"type"` // IpSpaceAllocationFloatingIp or IpSpaceAllocationIpPrefix
+	Quantity     *int   `json:"quantity,omitempty"`
+	PrefixLength *int   `json:"prefixLength,omitempty"`
+	Value        string `json:"value,omitempty"`
+}
+
+// IpSpaceIpAllocationRequestResult is an IP address or prefix allocated by an IpSpaceIpAllocationRequest
+type IpSpaceIpAllocationRequestResult struct {
+	ID        string `json:"id"`
+	Value     string `json:"value"`
+	Suggested bool   `json:"suggested"`
+}
+
+// IpSpaceIpAllocation is an IP address or prefix of an IP space allocated to an org. The org can
+// reserve it (IpSpaceAllocationUsedManual) or use it in its entities, e.g. its edge gateways.
+type IpSpaceIpAllocation struct {
+	ID             string            `json:"id,omitempty"`
+	Type           string            `json:"type"`
+	Value          string            `json:"value"`
+	UsageState     string            `json:"usageState,omitempty"` // One of the IpSpaceAllocationUnused, Used or UsedManual constants
+	Description    string            `json:"description,omitempty"`
+	OrgRef         *OpenApiReference `json:"orgRef,omitempty"`
+	UsedByRef      *OpenApiReference `json:"usedByRef,omitempty"`
+	AllocationDate string            `json:"allocationDate,omitempty"`
+}
+
+// IpSpaceOrgAssignment is the use of an IP space by an org, with its quotas. The custom quotas
+// override the default ones of the IP space; nil quotas keep the default.
+type IpSpaceOrgAssignment struct {
+	ID            string                      `json:"id,omitempty"`
+	IPSpaceRef    *OpenApiReference           `json:"ipSpaceRef"`
+	OrgRef        *OpenApiReference           `json:"orgRef"`
+	IPSpaceType   string                      `json:"ipSpaceType,omitempty"`
+	DefaultQuotas *IpSpaceOrgAssignmentQuotas `json:"defaultQuotas,omitempty"` // read-only
+	CustomQuotas  *IpSpaceOrgAssignmentQuotas `json:"customQuotas,omitempty"`
+}
+
+// IpSpaceOrgAssignmentQuotas are the quotas of an org in an IP space: the number of floating IP
+// addresses and of prefixes of each length it can allocate, -1 meaning unlimited
+type IpSpaceOrgAssignmentQuotas struct {
+	FloatingIPQuota *int                 `json:"floatingIPQuota,omitempty"`
+	IPPrefixQuotas  []IpSpacePrefixQuota `json:"ipPrefixQuotas,omitempty"`
+}
+
+// IpSpacePrefixQuota is the number of prefixes of PrefixLength bits an org can allocate
+type IpSpacePrefixQuota struct {
+	PrefixLength int `json:"prefixLength"`
+	Quota        int `json:"quota"`
+}
+
+// DefinedInterface is an interface of runtime defined entities (RDE), which RDE types implement.
+// It declares the behaviors of the types implementing it. Its ID is made of its vendor, namespace
+// (nss) and version.
+type DefinedInterface struct {
+	ID         string `json:"id,omitempty"`
+	Name       string `json:"name"`
+	Namespace  string `json:"nss"`
+	Version    string `json:"version"`
+	Vendor     string `json:"vendor"`
+	IsReadOnly bool   `json:"readonly,omitempty"`
+}
+
+// DefinedEntityType is the type of runtime defined entities (RDE): the JSON schema of their
+// content, and the interfaces whose behaviors they get
+type DefinedEntityType struct {
+	ID          string                 `json:"id,omitempty"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Namespace   string                 `json:"nss"`
+	Version     string                 `json:"version"`
+	Vendor      string                 `json:"vendor"`
+	Interfaces  []string               `json:"interfaces,omitempty"` // IDs of the implemented interfaces
+	Schema      map[string]interface{} `json:"schema,omitempty"`
+	ExternalId  string                 `json:"externalId,omitempty"`
+	IsReadOnly  bool                   `json:"readonly,omitempty"`
+}
+
+// DefinedEntity is a runtime defined entity (RDE): a JSON document whose content follows the schema
+// of its type
+type DefinedEntity struct {
+	ID         string                 `json:"id,omitempty"`
+	EntityType string                 `json:"entityType,omitempty"` // ID of the RDE type
+	Name       string                 `json:"name"`
+	ExternalId string                 `json:"externalId,omitempty"`
+	Entity     map[string]interface{} `json:"entity"`
+	State      string                 `json:"state,omitempty"` // One of the RdeState* constants
+	Owner      *OpenApiReference      `json:"owner,omitempty"`
+	Org        *OpenApiReference      `json:"org,omitempty"`
+}
+
+// Behavior is an operation which can be invoked on runtime defined entities (RDE). It is declared by
+// an RDE interface, and RDE types can override its execution. Execution holds the type of execution
+// ("type", one of the RdeBehaviorExecution* constants) and its settings, which depend on the type.
+type Behavior struct {
+	ID          string                 `json:"id,omitempty"`
+	Name        string                 `json:"name"`
+	Ref         string                 `json:"ref,omitempty"` // ID of the overridden behavior of the interface
+	Description string                 `json:"description,omitempty"`
+	Execution   map[string]interface{} `json:"execution,omitempty"`
+}
+
+// BehaviorAccess is the access level to an RDE needed to invoke one of the behaviors of its type
+type BehaviorAccess struct {
+	BehaviorId    string `json:"behaviorId"`
+	AccessLevelId string `json:"accessLevelId"` // One of the RdeAccessLevel* constants
+}
+
+// BehaviorInvocation holds the arguments and metadata given to a behavior invoked on an RDE
+type BehaviorInvocation struct {
+	Arguments interface{} `json:"arguments,omitempty"`
+	Metadata  interface{} `json:"metadata,omitempty"`
+}
+
+// AuditTrailEvent is an entry of the audit trail, which records the operations done in vCD
+type AuditTrailEvent struct {
+	EventID              string            `json:"eventId"`
+	Description          string            `json:"description,omitempty"`
+	OperatingOrg         *OpenApiReference `json:"operatingOrg,omitempty"`
+	User                 *OpenApiReference `json:"user,omitempty"`
+	EventEntity          *OpenApiReference `json:"eventEntity,omitempty"`
+	TaskID               string            `json:"taskId,omitempty"`
+	TaskCellID           string            `json:"taskCellId,omitempty"`
+	CellID               string            `json:"cellId,omitempty"`
+	EventType            string            `json:"eventType"`
+	ServiceNamespace     string            `json:"serviceNamespace,omitempty"`
+	EventStatus          string            `json:"eventStatus,omitempty"`
+	Timestamp            string            `json:"timestamp"`
+	ExternalIds          []string          `json:"externalIds,omitempty"`
+	AdditionalProperties map[string]string `json:"additionalProperties,omitempty"`
+}
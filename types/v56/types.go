@@ -75,13 +75,18 @@ type CustomizationSection struct {
 // Description: Represents vApp lease settings.
 // Since: 0.9
 type LeaseSettingsSection struct {
+	// Extends OVF Section_Type
+	Xmlns string `xml:"xmlns,attr,omitempty"`
+	Ovf   string `xml:"xmlns:ovf,attr,omitempty"`
+	Info  string `xml:"ovf:Info,omitempty"`
+
 	HREF                      string `xml:"href,attr,omitempty"`
 	Type                      string `xml:"type,attr,omitempty"`
-	DeploymentLeaseExpiration string `xml:"DeploymentLeaseExpiration,omitempty"`
-	DeploymentLeaseInSeconds  int    `xml:"DeploymentLeaseInSeconds,omitempty"`
 	Link                      *Link  `xml:"Link,omitempty"`
-	StorageLeaseExpiration    string `xml:"StorageLeaseExpiration,omitempty"`
+	DeploymentLeaseInSeconds  int    `xml:"DeploymentLeaseInSeconds,omitempty"`
 	StorageLeaseInSeconds     int    `xml:"StorageLeaseInSeconds,omitempty"`
+	DeploymentLeaseExpiration string `xml:"DeploymentLeaseExpiration,omitempty"` // Read-only
+	StorageLeaseExpiration    string `xml:"StorageLeaseExpiration,omitempty"`    // Read-only
 }
 
 // IPRange represents a range of IP addresses, start and end inclusive.
@@ -132,7 +137,7 @@ type NetworkFeatures struct {
 // Description: A list of IP addresses.
 // Since: 0.9
 type IPAddresses struct {
-	IPAddress string `xml:"IpAddress,omitempty"` // An IP address.
+	IPAddress []string `xml:"IpAddress,omitempty"` // An IP address.
 }
 
 // IPRanges represents a list of IP ranges.
@@ -153,6 +158,7 @@ type IPScope struct {
 	IsInherited          bool            `xml:"IsInherited"`                    // True if the IP scope is inherit from parent network.
 	Gateway              string          `xml:"Gateway,omitempty"`              // Gateway of the network.
 	Netmask              string          `xml:"Netmask,omitempty"`              // Network mask.
+	SubnetPrefixLength   int             `xml:"SubnetPrefixLength,omitempty"`   // Network prefix length, replacing Netmask from API 34.0.
 	DNS1                 string          `xml:"Dns1,omitempty"`                 // Primary DNS server.
 	DNS2                 string          `xml:"Dns2,omitempty"`                 // Secondary DNS server.
 	DNSSuffix            string          `xml:"DnsSuffix,omitempty"`            // DNS suffix.
@@ -172,8 +178,8 @@ type SubAllocations struct {
 	HREF string `xml:"href,attr,omitempty"` // The URI of the entity.
 	Type string `xml:"type,attr,omitempty"` // The MIME type of the entity.
 	// Elements
-	Link          LinkList       `xml:"Link,omitempty"`          // A reference to an entity or operation associated with this object.
-	SubAllocation *SubAllocation `xml:"SubAllocation,omitempty"` // IP Range sub allocated to a edge gateway.
+	Link          LinkList         `xml:"Link,omitempty"`          // A reference to an entity or operation associated with this object.
+	SubAllocation []*SubAllocation `xml:"SubAllocation,omitempty"` // IP Range sub allocated to a edge gateway.
 }
 
 // SubAllocation IP range sub allocated to an edge gateway.
@@ -359,20 +365,22 @@ type Vdc struct {
 	Name         string `xml:"name,attr"`
 	Status       string `xml:"status,attr,omitempty"`
 
-	AllocationModel    string                `xml:"AllocationModel"`
-	AvailableNetworks  []*AvailableNetworks  `xml:"AvailableNetworks,omitempty"`
-	Capabilities       []*Capabilities       `xml:"Capabilities,omitempty"`
-	ComputeCapacity    []*ComputeCapacity    `xml:"ComputeCapacity"`
-	Description        string                `xml:"Description,omitempty"`
-	IsEnabled          bool                  `xml:"IsEnabled"`
-	Link               LinkList              `xml:"Link,omitempty"`
-	NetworkQuota       int                   `xml:"NetworkQuota"`
-	NicQuota           int                   `xml:"NicQuota"`
-	ResourceEntities   []*ResourceEntities   `xml:"ResourceEntities,omitempty"`
-	Tasks              *TasksInProgress      `xml:"Tasks,omitempty"`
-	UsedNetworkCount   int                   `xml:"UsedNetworkCount,omitempty"`
-	VdcStorageProfiles []*VdcStorageProfiles `xml:"VdcStorageProfiles"`
-	VMQuota            int                   `xml:"VmQuota"`
+	Link                 LinkList              `xml:"Link,omitempty"`
+	Description          string                `xml:"Description,omitempty"`
+	Tasks                *TasksInProgress      `xml:"Tasks,omitempty"`
+	AllocationModel      string                `xml:"AllocationModel"`
+	ComputeCapacity      []*ComputeCapacity    `xml:"ComputeCapacity"`
+	ResourceEntities     []*ResourceEntities   `xml:"ResourceEntities,omitempty"`
+	AvailableNetworks    []*AvailableNetworks  `xml:"AvailableNetworks,omitempty"`
+	Capabilities         []*Capabilities       `xml:"Capabilities,omitempty"`
+	NicQuota             int                   `xml:"NicQuota"`
+	NetworkQuota         int                   `xml:"NetworkQuota"`
+	UsedNetworkCount     int                   `xml:"UsedNetworkCount,omitempty"`
+	VMQuota              int                   `xml:"VmQuota"`
+	IsEnabled            bool                  `xml:"IsEnabled"`
+	VdcStorageProfiles   []*VdcStorageProfiles `xml:"VdcStorageProfiles"`
+	DefaultComputePolicy *Reference            `xml:"DefaultComputePolicy,omitempty"` // API 32.0+
+	MaxComputePolicy     *Reference            `xml:"MaxComputePolicy,omitempty"`     // API 32.0+
 }
 
 // AdminVdc represents the admin view of an organization vDC.
@@ -381,6 +389,8 @@ type Vdc struct {
 // Description: Represents the admin view of an organization vDC.
 // Since: 0.9
 type AdminVdc struct {
+	XMLName xml.Name `xml:"AdminVdc"`
+	Xmlns   string   `xml:"xmlns,attr,omitempty"`
 	Vdc
 
 	ResourceGuaranteedMemory float64    `xml:"ResourceGuaranteedMemory,omitempty"`
@@ -392,6 +402,8 @@ type AdminVdc struct {
 	UsesFastProvisioning     bool       `xml:"UsesFastProvisioning,omitempty"`
 	OverCommitAllowed        bool       `xml:"OverCommitAllowed,omitempty"`
 	VmDiscoveryEnabled       bool       `xml:"VmDiscoveryEnabled,omitempty"`
+	IsElastic                *bool      `xml:"IsElastic,omitempty"`             // Flex allocation model only (API 32.0+)
+	IncludeMemoryOverhead    *bool      `xml:"IncludeMemoryOverhead,omitempty"` // Flex allocation model only (API 32.0+)
 }
 
 // VdcStorageProfile represents the parameters to create a storage profile in an organization vDC.
@@ -415,26 +427,28 @@ type VdcStorageProfile struct {
 // Since: 5.1
 // https://code.vmware.com/apis/220/vcloud#/doc/doc/types/CreateVdcParamsType.html
 type VdcConfiguration struct {
-	XMLName                  xml.Name           `xml:"CreateVdcParams"`
-	Xmlns                    string             `xml:"xmlns,attr"`
-	Name                     string             `xml:"name,attr"`
-	Description              string             `xml:"Description,omitempty"`
-	AllocationModel          string             `xml:"AllocationModel"`
-	ComputeCapacity          []*ComputeCapacity `xml:"ComputeCapacity"`
-	NicQuota                 int                `xml:"NicQuota,omitempty"`
-	NetworkQuota             int                `xml:"NetworkQuota,omitempty"`
-	VmQuota                  int                `xml:"VmQuota,omitempty"`
-	IsEnabled                bool               `xml:"IsEnabled,omitempty"`
-	VdcStorageProfile        *VdcStorageProfile `xml:"VdcStorageProfile"`
-	ResourceGuaranteedMemory float64            `xml:"ResourceGuaranteedMemory,omitempty"`
-	ResourceGuaranteedCpu    float64            `xml:"ResourceGuaranteedCpu,omitempty"`
-	VCpuInMhz                int64              `xml:"VCpuInMhz,omitempty"`
-	IsThinProvision          bool               `xml:"IsThinProvision,omitempty"`
-	NetworkPoolReference     *Reference         `xml:"NetworkPoolReference,omitempty"`
-	ProviderVdcReference     *Reference         `xml:"ProviderVdcReference"`
-	UsesFastProvisioning     bool               `xml:"UsesFastProvisioning,omitempty"`
-	OverCommitAllowed        bool               `xml:"OverCommitAllowed,omitempty"`
-	VmDiscoveryEnabled       bool               `xml:"VmDiscoveryEnabled,omitempty"`
+	XMLName                  xml.Name             `xml:"CreateVdcParams"`
+	Xmlns                    string               `xml:"xmlns,attr"`
+	Name                     string               `xml:"name,attr"`
+	Description              string               `xml:"Description,omitempty"`
+	AllocationModel          string               `xml:"AllocationModel"`
+	ComputeCapacity          []*ComputeCapacity   `xml:"ComputeCapacity"`
+	NicQuota                 int                  `xml:"NicQuota,omitempty"`
+	NetworkQuota             int                  `xml:"NetworkQuota,omitempty"`
+	VmQuota                  int                  `xml:"VmQuota,omitempty"`
+	IsEnabled                bool                 `xml:"IsEnabled,omitempty"`
+	VdcStorageProfile        []*VdcStorageProfile `xml:"VdcStorageProfile"`
+	ResourceGuaranteedMemory float64              `xml:"ResourceGuaranteedMemory,omitempty"`
+	ResourceGuaranteedCpu    float64              `xml:"ResourceGuaranteedCpu,omitempty"`
+	VCpuInMhz                int64                `xml:"VCpuInMhz,omitempty"`
+	IsThinProvision          bool                 `xml:"IsThinProvision,omitempty"`
+	NetworkPoolReference     *Reference           `xml:"NetworkPoolReference,omitempty"`
+	ProviderVdcReference     *Reference           `xml:"ProviderVdcReference"`
+	UsesFastProvisioning     bool                 `xml:"UsesFastProvisioning,omitempty"`
+	OverCommitAllowed        bool                 `xml:"OverCommitAllowed,omitempty"`
+	VmDiscoveryEnabled       bool                 `xml:"VmDiscoveryEnabled,omitempty"`
+	IsElastic                *bool                `xml:"IsElastic,omitempty"`             // Flex allocation model only (API 32.0+)
+	IncludeMemoryOverhead    *bool                `xml:"IncludeMemoryOverhead,omitempty"` // Flex allocation model only (API 32.0+)
 }
 
 // Task represents an asynchronous operation in vCloud Director.
@@ -465,6 +479,13 @@ type Task struct {
 	Progress         int              `xml:"Progress,omitempty"`
 	Tasks            *TasksInProgress `xml:"Tasks,omitempty"`
 	User             *Reference       `xml:"User,omitempty"`
+	Result           *TaskResult      `xml:"Result,omitempty"`
+}
+
+// TaskResult is the result of a task whose operation returns a value instead of creating an entity,
+// e.g. the addresses allocated from an IP space. ResultContent is usually JSON.
+type TaskResult struct {
+	ResultContent string `xml:"ResultContent,omitempty"`
 }
 
 // CapacityWithUsage represents a capacity and usage of a given resource.
@@ -567,6 +588,21 @@ type OrgList struct {
 	Org  []*Org   `xml:"Org,omitempty"`
 }
 
+// Session represents the session of a user, as returned at login or retrieved later.
+// Type: SessionType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Represents a vCloud Session.
+// Since: 0.9
+type Session struct {
+	HREF   string   `xml:"href,attr,omitempty"`
+	Type   string   `xml:"type,attr,omitempty"`
+	User   string   `xml:"user,attr,omitempty"`   // Name of the user
+	UserID string   `xml:"userId,attr,omitempty"` // ID of the user (URN)
+	Org    string   `xml:"org,attr,omitempty"`    // Name of the org of the user
+	Roles  string   `xml:"roles,attr,omitempty"`  // Comma-separated list of the roles of the user
+	Link   LinkList `xml:"Link,omitempty"`
+}
+
 // Org represents the user view of a vCloud Director organization.
 // Type: OrgType
 // Namespace: http://www.vmware.com/vcloud/v1.5
@@ -591,22 +627,25 @@ type Org struct {
 // Description: Represents the admin view of a vCloud Director organization.
 // Since: 0.9
 type AdminOrg struct {
-	XMLName      xml.Name         `xml:"AdminOrg"`
-	Xmlns        string           `xml:"xmlns,attr"`
-	HREF         string           `xml:"href,attr,omitempty"`
-	Type         string           `xml:"type,attr,omitempty"`
-	ID           string           `xml:"id,attr,omitempty"`
-	OperationKey string           `xml:"operationKey,attr,omitempty"`
-	Name         string           `xml:"name,attr"`
-	Description  string           `xml:"Description,omitempty"`
-	FullName     string           `xml:"FullName"`
-	IsEnabled    bool             `xml:"IsEnabled,omitempty"`
-	Link         LinkList         `xml:"Link,omitempty"`
-	Tasks        *TasksInProgress `xml:"Tasks,omitempty"`
-	OrgSettings  *OrgSettings     `xml:"Settings,omitempty"`
-	Vdcs         *VDCList         `xml:"Vdcs,omitempty"`
-	Networks     *NetworksList    `xml:"Networks,omitempty"`
-	Catalogs     *CatalogsList    `xml:"Catalogs,omitempty"`
+	XMLName        xml.Name         `xml:"AdminOrg"`
+	Xmlns          string           `xml:"xmlns,attr"`
+	HREF           string           `xml:"href,attr,omitempty"`
+	Type           string           `xml:"type,attr,omitempty"`
+	ID             string           `xml:"id,attr,omitempty"`
+	OperationKey   string           `xml:"operationKey,attr,omitempty"`
+	Name           string           `xml:"name,attr"`
+	Description    string           `xml:"Description,omitempty"`
+	FullName       string           `xml:"FullName"`
+	IsEnabled      bool             `xml:"IsEnabled,omitempty"`
+	Link           LinkList         `xml:"Link,omitempty"`
+	Tasks          *TasksInProgress `xml:"Tasks,omitempty"`
+	OrgSettings    *OrgSettings     `xml:"Settings,omitempty"`
+	Users          *OrgUserList     `xml:"Users,omitempty"`
+	Groups         *OrgGroupList    `xml:"Groups,omitempty"`
+	Vdcs           *VDCList         `xml:"Vdcs,omitempty"`
+	Networks       *NetworksList    `xml:"Networks,omitempty"`
+	Catalogs       *CatalogsList    `xml:"Catalogs,omitempty"`
+	RoleReferences *OrgRoleList     `xml:"RoleReferences,omitempty"`
 }
 
 // OrgSettingsType represents the settings for a vCloud Director organization.
@@ -633,15 +672,18 @@ type OrgSettings struct {
 // Description: Represents the user view of a vCloud Director organization.
 // Since: 0.9
 type OrgGeneralSettings struct {
-	HREF string   `xml:"href,attr,omitempty"` // The URI of the entity.
-	Type string   `xml:"type,attr,omitempty"` // The MIME type of the entity.
-	Link LinkList `xml:"Link,omitempty"`      // A reference to an entity or operation associated with this object.
+	Xmlns string   `xml:"xmlns,attr,omitempty"`
+	HREF  string   `xml:"href,attr,omitempty"` // The URI of the entity.
+	Type  string   `xml:"type,attr,omitempty"` // The MIME type of the entity.
+	Link  LinkList `xml:"Link,omitempty"`      // A reference to an entity or operation associated with this object.
 
-	CanPublishCatalogs       bool `xml:"CanPublishCatalogs,omitempty"`
-	DeployedVMQuota          int  `xml:"DeployedVMQuota,omitempty"`
-	StoredVMQuota            int  `xml:"StoredVmQuota,omitempty"`
-	UseServerBootSequence    bool `xml:"UseServerBootSequence,omitempty"`
-	DelayAfterPowerOnSeconds int  `xml:"DelayAfterPowerOnSeconds,omitempty"`
+	CanPublishCatalogs       bool  `xml:"CanPublishCatalogs,omitempty"`
+	CanPublishExternally     *bool `xml:"CanPublishExternally,omitempty"` // Since 31.0
+	CanSubscribe             *bool `xml:"CanSubscribe,omitempty"`         // Since 31.0
+	DeployedVMQuota          int   `xml:"DeployedVMQuota"`                // 0 means unlimited
+	StoredVMQuota            int   `xml:"StoredVmQuota"`                  // 0 means unlimited
+	UseServerBootSequence    bool  `xml:"UseServerBootSequence,omitempty"`
+	DelayAfterPowerOnSeconds int   `xml:"DelayAfterPowerOnSeconds,omitempty"`
 }
 
 // VAppTemplateLeaseSettings represents the vapp template lease settings for a vCloud Director organization.
@@ -650,18 +692,25 @@ type OrgGeneralSettings struct {
 // Description: Represents the vapp template lease settings of a vCloud Director organization.
 // Since: 0.9
 type VAppTemplateLeaseSettings struct {
-	HREF string   `xml:"href,attr,omitempty"` // The URI of the entity.
-	Type string   `xml:"type,attr,omitempty"` // The MIME type of the entity.
-	Link LinkList `xml:"Link,omitempty"`      // A reference to an entity or operation associated with this object.
+	Xmlns string   `xml:"xmlns,attr,omitempty"`
+	HREF  string   `xml:"href,attr,omitempty"` // The URI of the entity.
+	Type  string   `xml:"type,attr,omitempty"` // The MIME type of the entity.
+	Link  LinkList `xml:"Link,omitempty"`      // A reference to an entity or operation associated with this object.
 
 	DeleteOnStorageLeaseExpiration bool `xml:"DeleteOnStorageLeaseExpiration,omitempty"`
 	StorageLeaseSeconds            int  `xml:"StorageLeaseSeconds,omitempty"`
 }
 
+// VAppLeaseSettings represents the vapp lease settings for a vCloud Director organization.
+// Type: VAppLeaseSettingsType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Represents the vapp lease settings of a vCloud Director organization.
+// Since: 0.9
 type VAppLeaseSettings struct {
-	HREF string   `xml:"href,attr,omitempty"` // The URI of the entity.
-	Type string   `xml:"type,attr,omitempty"` // The MIME type of the entity.
-	Link LinkList `xml:"Link,omitempty"`      // A reference to an entity or operation associated with this object.
+	Xmlns string   `xml:"xmlns,attr,omitempty"`
+	HREF  string   `xml:"href,attr,omitempty"` // The URI of the entity.
+	Type  string   `xml:"type,attr,omitempty"` // The MIME type of the entity.
+	Link  LinkList `xml:"Link,omitempty"`      // A reference to an entity or operation associated with this object.
 
 	DeleteOnStorageLeaseExpiration   bool `xml:"DeleteOnStorageLeaseExpiration,allowempty"`
 	DeploymentLeaseSeconds           int  `xml:"DeploymentLeaseSeconds,allowempty"`
@@ -669,12 +718,123 @@ type VAppLeaseSettings struct {
 	PowerOffOnRuntimeLeaseExpiration bool `xml:"PowerOffOnRuntimeLeaseExpiration,allowempty"`
 }
 
+// OrgOperationLimitsSettings represents the operation limits settings for a vCloud Director organization.
+// Type: OrgOperationLimitsSettingsType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Represents operation limits settings of a vCloud Director organization. A value of 0 means unlimited.
+// Since: 5.1
+type OrgOperationLimitsSettings struct {
+	Xmlns string   `xml:"xmlns,attr,omitempty"`
+	HREF  string   `xml:"href,attr,omitempty"` // The URI of the entity.
+	Type  string   `xml:"type,attr,omitempty"` // The MIME type of the entity.
+	Link  LinkList `xml:"Link,omitempty"`      // A reference to an entity or operation associated with this object.
+
+	ConsolesPerVmLimit      int `xml:"ConsolesPerVmLimit"`      // Maximum number of simultaneous console connections per VM.
+	OperationsPerUser       int `xml:"OperationsPerUser"`       // Maximum number of simultaneous resource intensive operations per user.
+	OperationsPerOrg        int `xml:"OperationsPerOrg"`        // Maximum number of simultaneous resource intensive operations per organization.
+	QueuedOperationsPerUser int `xml:"QueuedOperationsPerUser"` // Maximum number of queued resource intensive operations per user.
+	QueuedOperationsPerOrg  int `xml:"QueuedOperationsPerOrg"`  // Maximum number of queued resource intensive operations per organization.
+}
+
+// OrgEmailSettings represents the email (SMTP) settings for a vCloud Director organization.
+// Type: OrgEmailSettingsType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Represents the email settings of a vCloud Director organization.
+// Since: 0.9
+type OrgEmailSettings struct {
+	Xmlns string   `xml:"xmlns,attr,omitempty"`
+	HREF  string   `xml:"href,attr,omitempty"` // The URI of the entity.
+	Type  string   `xml:"type,attr,omitempty"` // The MIME type of the entity.
+	Link  LinkList `xml:"Link,omitempty"`      // A reference to an entity or operation associated with this object.
+
+	IsDefaultSmtpServer     bool                `xml:"IsDefaultSmtpServer"`          // If true, the organization uses the system SMTP server.
+	IsDefaultOrgEmail       bool                `xml:"IsDefaultOrgEmail"`            // If true, the organization uses the system email settings.
+	FromEmailAddress        string              `xml:"FromEmailAddress"`             // Sender address for notifications.
+	DefaultSubjectPrefix    string              `xml:"DefaultSubjectPrefix"`         // Prefix added to the subject of notification emails.
+	IsAlertEmailToAllAdmins bool                `xml:"IsAlertEmailToAllAdmins"`      // If true, alerts are sent to all organization administrators.
+	AlertEmailTo            string              `xml:"AlertEmailTo,omitempty"`       // Comma separated list of recipients, used when IsAlertEmailToAllAdmins is false.
+	SmtpServerSettings      *SmtpServerSettings `xml:"SmtpServerSettings,omitempty"` // Custom SMTP server, used when IsDefaultSmtpServer is false.
+}
+
+// SmtpServerSettings represents the SMTP server settings
+// Type: SmtpServerSettingsType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Represents the SMTP server settings.
+// Since: 0.9
+type SmtpServerSettings struct {
+	IsUseAuthentication bool   `xml:"IsUseAuthentication"` // If true, the server requires authentication.
+	Host                string `xml:"Host"`                // SMTP server host name or IP address.
+	Port                int    `xml:"Port,omitempty"`      // SMTP server port.
+	Username            string `xml:"Username,omitempty"`  // User name, when authentication is required.
+	Password            string `xml:"Password,omitempty"`  // Password, when authentication is required. Never returned by vCD.
+}
+
+// OrgFederationSettings represents the SAML federation settings for a vCloud Director organization.
+// Type: OrgFederationSettingsType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Represents federation settings of a vCloud Director organization.
+// Since: 1.0
 type OrgFederationSettings struct {
-	HREF string   `xml:"href,attr,omitempty"` // The URI of the entity.
-	Type string   `xml:"type,attr,omitempty"` // The MIME type of the entity.
-	Link LinkList `xml:"Link,omitempty"`      // A reference to an entity or operation associated with this object.
+	Xmlns string   `xml:"xmlns,attr,omitempty"`
+	HREF  string   `xml:"href,attr,omitempty"` // The URI of the entity.
+	Type  string   `xml:"type,attr,omitempty"` // The MIME type of the entity.
+	Link  LinkList `xml:"Link,omitempty"`      // A reference to an entity or operation associated with this object.
+
+	SAMLMetadata         string                `xml:"SAMLMetadata,omitempty"`         // Metadata document of the SAML identity provider
+	Enabled              bool                  `xml:"Enabled"`                        // True if the organization uses SAML authentication
+	SamlSPEntityId       string                `xml:"SamlSPEntityId,omitempty"`       // Entity ID used by vCD as service provider. Since 29.0
+	SamlAttributeMapping *SamlAttributeMapping `xml:"SamlAttributeMapping,omitempty"` // Names of the SAML attributes holding user information. Since 29.0
+}
+
+// SamlAttributeMapping defines which SAML assertion attributes contain the user details
+// Type: SamlAttributeMappingType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Mapping of SAML attributes to user properties.
+// Since: 29.0
+type SamlAttributeMapping struct {
+	EmailAttributeName     string `xml:"EmailAttributeName,omitempty"`
+	UserNameAttributeName  string `xml:"UserNameAttributeName,omitempty"`
+	FirstNameAttributeName string `xml:"FirstNameAttributeName,omitempty"`
+	SurnameAttributeName   string `xml:"SurnameAttributeName,omitempty"`
+	FullNameAttributeName  string `xml:"FullNameAttributeName,omitempty"`
+	GroupAttributeName     string `xml:"GroupAttributeName,omitempty"`
+	RoleAttributeName      string `xml:"RoleAttributeName,omitempty"`
+}
+
+// OrgAssociations is the list of the associations of an organization with organizations of other sites
+// Type: OrgAssociationsType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: List of organization associations.
+// Since: 29.0
+type OrgAssociations struct {
+	Xmlns string   `xml:"xmlns,attr,omitempty"`
+	HREF  string   `xml:"href,attr,omitempty"` // The URI of the entity.
+	Type  string   `xml:"type,attr,omitempty"` // The MIME type of the entity.
+	Link  LinkList `xml:"Link,omitempty"`      // A reference to an entity or operation associated with this object.
+
+	OrgAssociationMember []*OrgAssociationMember `xml:"OrgAssociationMember,omitempty"`
+}
+
+// OrgAssociationMember is an organization taking part in a multisite association. The local
+// association data of an organization is exported as an OrgAssociationMember, which is then
+// imported in the organization of the other site.
+// Type: OrgAssociationMemberType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Information about a member of an organization association.
+// Since: 29.0
+type OrgAssociationMember struct {
+	XMLName xml.Name `xml:"OrgAssociationMember"`
+	Xmlns   string   `xml:"xmlns,attr,omitempty"`
+	HREF    string   `xml:"href,attr,omitempty"` // The URI of the entity.
+	Type    string   `xml:"type,attr,omitempty"` // The MIME type of the entity.
+	Link    LinkList `xml:"Link,omitempty"`      // A reference to an entity or operation associated with this object.
 
-	Enabled bool `xml:"Enabled,allowempty"`
+	OrgHref      string `xml:"OrgHref"`          // HREF of the organization at its site
+	SiteId       string `xml:"SiteId"`           // ID of the site of the organization
+	OrgName      string `xml:"OrgName"`          // Name of the organization
+	OrgPublicKey string `xml:"OrgPublicKey"`     // Public key of the organization, used to sign requests across sites
+	Status       string `xml:"Status,omitempty"` // Read-only status of the association: ACTIVE, ASYMMETRIC, UNREACHABLE
+	OrgId        string `xml:"OrgId"`            // ID of the organization at its site
 }
 
 // OrgLdapSettingsType represents the ldap settings for a vCloud Director organization.
@@ -683,12 +843,13 @@ type OrgFederationSettings struct {
 // Description: Represents the ldap settings of a vCloud Director organization.
 // Since: 0.9
 type OrgLdapSettingsType struct {
-	HREF string   `xml:"href,attr,omitempty"` // The URI of the entity.
-	Type string   `xml:"type,attr,omitempty"` // The MIME type of the entity.
-	Link LinkList `xml:"Link,omitempty"`      // A reference to an entity or operation associated with this object.
+	Xmlns string   `xml:"xmlns,attr,omitempty"`
+	HREF  string   `xml:"href,attr,omitempty"` // The URI of the entity.
+	Type  string   `xml:"type,attr,omitempty"` // The MIME type of the entity.
+	Link  LinkList `xml:"Link,omitempty"`      // A reference to an entity or operation associated with this object.
 
-	CustomUsersOu         string                 `xml:"CustomUsersOu,omitempty"`         // If OrgLdapMode is SYSTEM, specifies an LDAP attribute=value pair to use for OU (organizational unit).
 	OrgLdapMode           string                 `xml:"OrgLdapMode,omitempty"`           // LDAP mode you want
+	CustomUsersOu         string                 `xml:"CustomUsersOu,omitempty"`         // If OrgLdapMode is SYSTEM, specifies an LDAP attribute=value pair to use for OU (organizational unit).
 	CustomOrgLdapSettings *CustomOrgLdapSettings `xml:"CustomOrgLdapSettings,omitempty"` // Needs to be set if user chooses custom mode
 }
 
@@ -697,26 +858,27 @@ type OrgLdapSettingsType struct {
 // Namespace: http://www.vmware.com/vcloud/v1.5
 // Description: Represents the custom ldap settings of a vCloud Director organization.
 // Since: 0.9
+// Note. Fields are in the order required by the API
 type CustomOrgLdapSettings struct {
 	HREF string   `xml:"href,attr,omitempty"` // The URI of the entity.
 	Type string   `xml:"type,attr,omitempty"` // The MIME type of the entity.
 	Link LinkList `xml:"Link,omitempty"`      // A reference to an entity or operation associated with this object.
 
-	AuthenticationMechanism  string                  `xml:"AuthenticationMechanism"`
-	ConnectorType            string                  `xml:"ConnectorType"`   // Defines LDAP service implementation type
-	GroupAttributes          *OrgLdapGroupAttributes `xml:"GroupAttributes"` // Defines how LDAP attributes are used when importing a group.
-	GroupSearchBase          string                  `xml:"GroupSearchBase,omitempty"`
 	HostName                 string                  `xml:"HostName,omitempty"`
-	IsGroupSearchBaseEnabled bool                    `xml:"IsGroupSearchBaseEnabled"`
+	Port                     int                     `xml:"Port"`
 	IsSsl                    bool                    `xml:"IsSsl,omitempty"`
 	IsSslAcceptAll           bool                    `xml:"IsSslAcceptAll,omitempty"`
-	Password                 string                  `xml:"Password,omitempty"`
-	Port                     int                     `xml:"Port"`
 	Realm                    string                  `xml:"Realm,omitempty"`
 	SearchBase               string                  `xml:"SearchBase,omitempty"`
-	UseExternalKerberos      bool                    `xml:"UseExternalKerberos"`
-	UserAttributes           *OrgLdapUserAttributes  `xml:"UserAttributes"` // Defines how LDAP attributes are used when importing a user.
 	Username                 string                  `xml:"UserName,omitempty"`
+	Password                 string                  `xml:"Password,omitempty"`
+	AuthenticationMechanism  string                  `xml:"AuthenticationMechanism"`
+	GroupSearchBase          string                  `xml:"GroupSearchBase,omitempty"`
+	IsGroupSearchBaseEnabled bool                    `xml:"IsGroupSearchBaseEnabled"`
+	ConnectorType            string                  `xml:"ConnectorType"`   // Defines LDAP service implementation type
+	UserAttributes           *OrgLdapUserAttributes  `xml:"UserAttributes"`  // Defines how LDAP attributes are used when importing a user.
+	GroupAttributes          *OrgLdapGroupAttributes `xml:"GroupAttributes"` // Defines how LDAP attributes are used when importing a group.
+	UseExternalKerberos      bool                    `xml:"UseExternalKerberos"`
 }
 
 // OrgLdapGroupAttributesType represents the ldap group attribute settings for a vCloud Director organization.
@@ -724,13 +886,14 @@ type CustomOrgLdapSettings struct {
 // Namespace: http://www.vmware.com/vcloud/v1.5
 // Description: Represents the ldap group attribute settings of a vCloud Director organization.
 // Since: 0.9
+// Note. Fields are in the order required by the API
 type OrgLdapGroupAttributes struct {
-	Membership           string `xml:"Membership"`
-	GroupName            string `xml:"GroupName"`
-	BackLinkIdentifier   string `xml:"BackLinkIdentifier,omitempty"`
-	MempershipIdentifier string `xml:"MempershipIdentifier"`
 	ObjectClass          string `xml:"ObjectClass"`
 	ObjectIdentifier     string `xml:"ObjectIdentifier"`
+	GroupName            string `xml:"GroupName"`
+	Membership           string `xml:"Membership"`
+	MempershipIdentifier string `xml:"MembershipIdentifier"`
+	BackLinkIdentifier   string `xml:"BackLinkIdentifier,omitempty"`
 }
 
 // OrgLdapUserAttributesType represents the ldap user attribute settings for a vCloud Director organization.
@@ -738,17 +901,117 @@ type OrgLdapGroupAttributes struct {
 // Namespace: http://www.vmware.com/vcloud/v1.5
 // Description: Represents the ldap user attribute settings of a vCloud Director organization.
 // Since: 0.9
+// Note. Fields are in the order required by the API
 type OrgLdapUserAttributes struct {
+	ObjectClass               string `xml:"ObjectClass"`
+	ObjectIdentifier          string `xml:"ObjectIdentifier"`
+	Username                  string `xml:"UserName,omitempty"`
 	Email                     string `xml:"Email"`
 	FullName                  string `xml:"FullName"`
 	GivenName                 string `xml:"GivenName"`
-	GroupBackLinkIdentifier   string `xml:"GroupBackLinkIdentifier,omitempty"`
-	GroupMempershipIdentifier string `xml:"GroupMempershipIdentifier"`
-	ObjectClass               string `xml:"ObjectClass"`
-	ObjectIdentifier          string `xml:"ObjectIdentifier"`
 	Surname                   string `xml:"Surname"`
 	Telephone                 string `xml:"Telephone"`
-	Username                  string `xml:"UserName,omitempty"`
+	GroupMempershipIdentifier string `xml:"GroupMembershipIdentifier"`
+	GroupBackLinkIdentifier   string `xml:"GroupBackLinkIdentifier,omitempty"`
+}
+
+// OrgGroupList contains a list of references to the groups of an organization
+// Type: GroupsListType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Container for references to groups in the organization.
+// Since: 0.9
+type OrgGroupList struct {
+	GroupReference []*Reference `xml:"GroupReference,omitempty"`
+}
+
+// OrgUserList contains a list of references to users
+// Type: UsersListType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Container for references to users in the organization.
+// Since: 0.9
+type OrgUserList struct {
+	UserReference []*Reference `xml:"UserReference,omitempty"`
+}
+
+// VdcComputePolicyReferences contains the references to the compute policies assigned to a VDC
+// Type: VdcComputePolicyReferencesType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Container for references to VDC compute policies.
+// Since: 32.0
+type VdcComputePolicyReferences struct {
+	XMLName                   xml.Name     `xml:"VdcComputePolicyReferences"`
+	Xmlns                     string       `xml:"xmlns,attr,omitempty"`
+	HREF                      string       `xml:"href,attr,omitempty"`
+	Type                      string       `xml:"type,attr,omitempty"`
+	Link                      LinkList     `xml:"Link,omitempty"`
+	VdcComputePolicyReference []*Reference `xml:"VdcComputePolicyReference,omitempty"`
+}
+
+// User represents a user of an organization
+// Type: UserType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Represents a user.
+// Since: 0.9
+// https://code.vmware.com/apis/220/vcloud#/doc/doc/types/UserType.html
+type User struct {
+	XMLName          xml.Name         `xml:"User"`
+	Xmlns            string           `xml:"xmlns,attr,omitempty"`
+	HREF             string           `xml:"href,attr,omitempty"`
+	Type             string           `xml:"type,attr,omitempty"`
+	ID               string           `xml:"id,attr,omitempty"`
+	OperationKey     string           `xml:"operationKey,attr,omitempty"`
+	Name             string           `xml:"name,attr"`
+	Link             LinkList         `xml:"Link,omitempty"`
+	Description      string           `xml:"Description,omitempty"`
+	Tasks            *TasksInProgress `xml:"Tasks,omitempty"`
+	FullName         string           `xml:"FullName,omitempty"`
+	EmailAddress     string           `xml:"EmailAddress,omitempty"`
+	Telephone        string           `xml:"Telephone,omitempty"`
+	IsEnabled        bool             `xml:"IsEnabled"`
+	IsLocked         bool             `xml:"IsLocked,omitempty"`
+	IM               string           `xml:"IM,omitempty"`
+	NameInSource     string           `xml:"NameInSource,omitempty"`
+	IsAlertEnabled   bool             `xml:"IsAlertEnabled,omitempty"`
+	AlertEmailPrefix string           `xml:"AlertEmailPrefix,omitempty"`
+	AlertEmail       string           `xml:"AlertEmail,omitempty"`
+	IsExternal       bool             `xml:"IsExternal,omitempty"`
+	ProviderType     string           `xml:"ProviderType,omitempty"`
+	IsDefaultCached  bool             `xml:"IsDefaultCached,omitempty"`
+	IsGroupRole      bool             `xml:"IsGroupRole,omitempty"`
+	StoredVmQuota    int              `xml:"StoredVmQuota"`   // 0 means unlimited
+	DeployedVmQuota  int              `xml:"DeployedVmQuota"` // 0 means unlimited
+	Role             *Reference       `xml:"Role,omitempty"`
+	Password         string           `xml:"Password,omitempty"`
+	GroupReferences  *OrgGroupList    `xml:"GroupReferences,omitempty"`
+}
+
+// OrgRoleList contains a list of references to the roles available in an organization
+// Type: OrganizationRolesType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Container for references to roles in the organization.
+// Since: 0.9
+type OrgRoleList struct {
+	RoleReference []*Reference `xml:"RoleReference,omitempty"`
+}
+
+// Group represents a group imported into an organization from an identity provider
+// Type: GroupType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Represents a group, imported from LDAP, SAML or OAuth, and the role assigned to its members.
+// Since: 0.9
+type Group struct {
+	XMLName      xml.Name     `xml:"Group"`
+	Xmlns        string       `xml:"xmlns,attr"`
+	HREF         string       `xml:"href,attr,omitempty"`
+	Type         string       `xml:"type,attr,omitempty"`
+	ID           string       `xml:"id,attr,omitempty"`
+	Name         string       `xml:"name,attr"`
+	Link         LinkList     `xml:"Link,omitempty"`
+	Description  string       `xml:"Description,omitempty"`
+	NameInSource string       `xml:"NameInSource,omitempty"` // Name of the group in its source (LDAP DN or SAML attribute value)
+	UsersList    *OrgUserList `xml:"UsersList,omitempty"`    // Users belonging to the group. Read only
+	ProviderType string       `xml:"ProviderType,omitempty"` // One of INTEGRATED (LDAP), SAML, OAUTH
+	Role         *Reference   `xml:"Role,omitempty"`         // Role assigned to the group members
 }
 
 // VDCList contains a list of references to Org VDCs
@@ -867,11 +1130,51 @@ type AdminCatalog struct {
 // Description: Represents the configuration parameters of a catalog published externally.
 // Since: 5.5
 type PublishExternalCatalogParams struct {
-	IsCachedEnabled          bool   `xml:"IsCacheEnabled,omitempty"`
-	IsPublishedExternally    bool   `xml:"IsPublishedExternally,omitempty"`
+	Xmlns                    string `xml:"xmlns,attr,omitempty"`
+	IsPublishedExternally    bool   `xml:"IsPublishedExternally"`
+	CatalogPublishedUrl      string `xml:"catalogPublishedUrl,omitempty"`
 	Password                 string `xml:"Password,omitempty"`
+	IsCachedEnabled          bool   `xml:"IsCacheEnabled,omitempty"`
 	PreserveIdentityInfoFlag bool   `xml:"PreserveIdentityInfoFlag,omitempty"`
-	CatalogPublishedUrl      string `xml:"catalogPublishedUrl,omitempty"`
+}
+
+// ControlAccessParams specifies the access controls of a resource, such as a catalog or a vApp.
+// Type: ControlAccessParamsType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Used to control access to resources.
+// Since: 0.9
+type ControlAccessParams struct {
+	XMLName             xml.Name           `xml:"ControlAccessParams"`
+	Xmlns               string             `xml:"xmlns,attr"`
+	IsSharedToEveryone  bool               `xml:"IsSharedToEveryone"`            // If true, the resource is shared with everyone in the organization.
+	EveryoneAccessLevel string             `xml:"EveryoneAccessLevel,omitempty"` // If IsSharedToEveryone is true, the access level of everyone. One of the ControlAccess* constants.
+	AccessSettings      *AccessSettingList `xml:"AccessSettings,omitempty"`      // The access settings of the users, groups or organizations the resource is shared with.
+}
+
+// AccessSettingList is a list of access settings
+// Type: AccessSettingsType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: A list of access settings for a resource.
+// Since: 0.9
+type AccessSettingList struct {
+	AccessSetting []*AccessSetting `xml:"AccessSetting"`
+}
+
+// AccessSetting specifies the access level of a subject (an org, a user or a group)
+// Type: AccessSettingType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Specifies who can access the resource.
+// Since: 0.9
+type AccessSetting struct {
+	Subject     *LocalSubject `xml:"Subject"`     // Reference to the org, user or group.
+	AccessLevel string        `xml:"AccessLevel"` // One of the ControlAccess* constants.
+}
+
+// LocalSubject is a reference to the subject of an access setting
+type LocalSubject struct {
+	HREF string `xml:"href,attr"`
+	Name string `xml:"name,attr,omitempty"`
+	Type string `xml:"type,attr"`
 }
 
 // ExternalCatalogSubscription represents the configuration parameters for a catalog that has an external subscription
@@ -902,10 +1205,11 @@ type CatalogStorageProfiles struct {
 // Description: Represents the owner of this entity.
 // Since: 1.5
 type Owner struct {
-	HREF string     `xml:"href,attr,omitempty"`
-	Type string     `xml:"type,attr,omitempty"`
-	Link LinkList   `xml:"Link,omitempty"`
-	User *Reference `xml:"User"`
+	Xmlns string     `xml:"xmlns,attr,omitempty"`
+	HREF  string     `xml:"href,attr,omitempty"`
+	Type  string     `xml:"type,attr,omitempty"`
+	Link  LinkList   `xml:"Link,omitempty"`
+	User  *Reference `xml:"User"`
 }
 
 // Error is the standard error message type used in the vCloud REST API.
@@ -965,11 +1269,13 @@ type UndeployVAppParams struct {
 // Description: Allows you to specify certain capabilities of this virtual machine.
 // Since: 5.1
 type VMCapabilities struct {
+	XMLName             xml.Name `xml:"VmCapabilities"`
+	Xmlns               string   `xml:"xmlns,attr,omitempty"`
 	HREF                string   `xml:"href,attr,omitempty"`
 	Type                string   `xml:"type,attr,omitempty"`
-	CPUHotAddEnabled    bool     `xml:"CpuHotAddEnabled,omitempty"`
 	Link                LinkList `xml:"Link,omitempty"`
-	MemoryHotAddEnabled bool     `xml:"MemoryHotAddEnabled,omitempty"`
+	MemoryHotAddEnabled bool     `xml:"MemoryHotAddEnabled"` // True if memory can be added while the VM is powered on
+	CPUHotAddEnabled    bool     `xml:"CpuHotAddEnabled"`    // True if CPUs can be added while the VM is powered on
 }
 
 // VMs represents a list of virtual machines.
@@ -1004,11 +1310,11 @@ type ComposeVAppParams struct {
 	PowerOn     bool   `xml:"powerOn,attr"`               // True if the vApp should be powered-on at instantiation. Defaults to true.
 	LinkedClone bool   `xml:"linkedClone,attr,omitempty"` // Reserved. Unimplemented.
 	// Elements
-	Description         string                       `xml:"Description,omitempty"`         // Optional description.
-	VAppParent          *Reference                   `xml:"VAppParent,omitempty"`          // Reserved. Unimplemented.
-	InstantiationParams *InstantiationParams         `xml:"InstantiationParams,omitempty"` // Instantiation parameters for the composed vApp.
-	SourcedItem         *SourcedCompositionItemParam `xml:"SourcedItem,omitempty"`         // Composition item. One of: vApp vAppTemplate Vm.
-	AllEULAsAccepted    bool                         `xml:"AllEULAsAccepted,omitempty"`    // True confirms acceptance of all EULAs in a vApp template. Instantiation fails if this element is missing, empty, or set to false and one or more EulaSection elements are present.
+	Description         string                         `xml:"Description,omitempty"`         // Optional description.
+	VAppParent          *Reference                     `xml:"VAppParent,omitempty"`          // Reserved. Unimplemented.
+	InstantiationParams *InstantiationParams           `xml:"InstantiationParams,omitempty"` // Instantiation parameters for the composed vApp.
+	SourcedItem         []*SourcedCompositionItemParam `xml:"SourcedItem,omitempty"`         // Composition items. Each one of: vApp vAppTemplate Vm.
+	AllEULAsAccepted    bool                           `xml:"AllEULAsAccepted,omitempty"`    // True confirms acceptance of all EULAs in a vApp template. Instantiation fails if this element is missing, empty, or set to false and one or more EulaSection elements are present.
 }
 
 type ReComposeVAppParams struct {
@@ -1022,18 +1328,55 @@ type ReComposeVAppParams struct {
 	PowerOn     bool   `xml:"powerOn,attr"`               // True if the vApp should be powered-on at instantiation. Defaults to true.
 	LinkedClone bool   `xml:"linkedClone,attr,omitempty"` // Reserved. Unimplemented.
 	// Elements
-	Description         string                       `xml:"Description,omitempty"`         // Optional description.
-	VAppParent          *Reference                   `xml:"VAppParent,omitempty"`          // Reserved. Unimplemented.
-	InstantiationParams *InstantiationParams         `xml:"InstantiationParams,omitempty"` // Instantiation parameters for the composed vApp.
-	SourcedItem         *SourcedCompositionItemParam `xml:"SourcedItem,omitempty"`         // Composition item. One of: vApp vAppTemplate Vm.
-	AllEULAsAccepted    bool                         `xml:"AllEULAsAccepted,omitempty"`
-	DeleteItem          *DeleteItem                  `xml:"DeleteItem,omitempty"`
+	Description         string                         `xml:"Description,omitempty"`         // Optional description.
+	VAppParent          *Reference                     `xml:"VAppParent,omitempty"`          // Reserved. Unimplemented.
+	InstantiationParams *InstantiationParams           `xml:"InstantiationParams,omitempty"` // Instantiation parameters for the composed vApp.
+	SourcedItem         []*SourcedCompositionItemParam `xml:"SourcedItem,omitempty"`         // Composition items. Each one of: vApp vAppTemplate Vm.
+	AllEULAsAccepted    bool                           `xml:"AllEULAsAccepted,omitempty"`
+	DeleteItem          *DeleteItem                    `xml:"DeleteItem,omitempty"`
 }
 
 type DeleteItem struct {
 	HREF string `xml:"href,attr,omitempty"`
 }
 
+// CaptureVAppParams represents the parameters to capture a vApp into a vApp template.
+// Type: CaptureVAppParamsType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Represents parameters for capturing a vApp to a vApp template.
+// Since: 0.9
+type CaptureVAppParams struct {
+	XMLName xml.Name `xml:"CaptureVAppParams"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Ovf     string   `xml:"xmlns:ovf,attr"`
+	// Attributes
+	Name string `xml:"name,attr"` // Name of the vApp template
+	// Elements
+	Description          string                `xml:"Description,omitempty"`          // Optional description.
+	Source               *Reference            `xml:"Source"`                         // A reference to the vApp to capture.
+	CustomizationSection *CustomizationSection `xml:"CustomizationSection,omitempty"` // Whether the VMs of the vApp template are customized when instantiated.
+	TargetCatalogItem    *Reference            `xml:"TargetCatalogItem,omitempty"`    // Catalog item to overwrite with the vApp template. Since 9.5
+}
+
+// CloneVAppParams represents the parameters to copy a vApp into a VDC.
+// Type: CloneVAppParamsType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Parameters for a cloneVApp request.
+// Since: 0.9
+type CloneVAppParams struct {
+	XMLName xml.Name `xml:"CloneVAppParams"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	// Attributes
+	Name        string `xml:"name,attr"`                  // Name of the new vApp
+	Deploy      bool   `xml:"deploy,attr"`                // True if the new vApp should be deployed. Defaults to true.
+	PowerOn     bool   `xml:"powerOn,attr"`               // True if the new vApp should be powered on. Defaults to true.
+	LinkedClone bool   `xml:"linkedClone,attr,omitempty"` // True if the VMs of the new vApp should be linked clones sharing the disks of the source VMs.
+	// Elements
+	Description    string     `xml:"Description,omitempty"`    // Optional description.
+	Source         *Reference `xml:"Source"`                   // A reference to the vApp to copy.
+	IsSourceDelete bool       `xml:"IsSourceDelete,omitempty"` // True if the source vApp should be deleted after the copy is complete.
+}
+
 // SourcedCompositionItemParam represents a vApp, vApp template or Vm to include in a composed vApp.
 // Type: SourcedCompositionItemParamType
 // Namespace: http://www.vmware.com/vcloud/v1.5
@@ -1050,6 +1393,17 @@ type SourcedCompositionItemParam struct {
 	NetworkAssignment   []*NetworkAssignment `xml:"NetworkAssignment,omitempty"`   // If Source references a Vm, this element maps a network name specified in the Vm to the network name of a vApp network defined in the composed vApp.
 	StorageProfile      *Reference           `xml:"StorageProfile,omitempty"`      // If Source references a Vm, this element contains a reference to a storage profile to be used for the Vm. The specified storage profile must exist in the organization vDC that contains the composed vApp. If not specified, the default storage profile for the vDC is used.
 	LocalityParams      *LocalityParams      `xml:"LocalityParams,omitempty"`      // Represents locality parameters. Locality parameters provide a hint that may help the placement engine optimize placement of a VM and an independent a Disk so that the VM can make efficient use of the disk.
+	ComputePolicy       *ComputePolicy       `xml:"ComputePolicy,omitempty"`       // If Source references a Vm, the compute policies of the Vm. API 33.0+
+}
+
+// ComputePolicy references the compute policies of a VM
+// Type: ComputePolicyType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: The compute policies of a VM.
+// Since: 33.0
+type ComputePolicy struct {
+	VmPlacementPolicy *Reference `xml:"VmPlacementPolicy,omitempty"` // The placement policy of the VM
+	VmSizingPolicy    *Reference `xml:"VmSizingPolicy,omitempty"`    // The sizing policy of the VM
 }
 
 // LocalityParams represents locality parameters. Locality parameters provide a hint that may help the placement engine optimize placement of a VM with respect to another VM or an independent disk.
@@ -1114,6 +1468,8 @@ type VApp struct {
 	InMaintenanceMode bool            `xml:"InMaintenanceMode,omitempty"` // True if this vApp is in maintenance mode. Prevents users from changing vApp metadata.
 	Children          *VAppChildren   `xml:"Children,omitempty"`          // Container for virtual machines included in this vApp.
 	ProductSection    *ProductSection `xml:"ProductSection,omitempty"`
+	// Runtime and storage leases of the vApp
+	LeaseSettingsSection *LeaseSettingsSection `xml:"LeaseSettingsSection,omitempty"`
 }
 
 type ProductSectionList struct {
@@ -1143,10 +1499,21 @@ type Value struct {
 }
 
 type MetadataValue struct {
-	XMLName    xml.Name    `xml:"MetadataValue"`
-	Xsi        string      `xml:"xmlns:xsi,attr"`
-	Xmlns      string      `xml:"xmlns,attr"`
-	TypedValue *TypedValue `xml:"TypedValue"`
+	XMLName    xml.Name           `xml:"MetadataValue"`
+	Xsi        string             `xml:"xmlns:xsi,attr"`
+	Xmlns      string             `xml:"xmlns,attr"`
+	Domain     *MetadataDomainTag `xml:"Domain,omitempty"`
+	TypedValue *TypedValue        `xml:"TypedValue"`
+}
+
+// MetadataDomainTag places a metadata entry in the GENERAL or SYSTEM domain, with a visibility
+// Type: MetadataDomainTagType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: A value of SYSTEM places this MetadataEntry in the SYSTEM domain. Omit or leave empty to place this MetadataEntry in the GENERAL domain.
+// Since: 5.1
+type MetadataDomainTag struct {
+	Visibility string `xml:"visibility,attr"` // One of the MetadataVisibility* constants: READWRITE for GENERAL, PRIVATE or READONLY for SYSTEM
+	Domain     string `xml:",chardata"`       // MetadataDomainGeneral or MetadataDomainSystem
 }
 
 type TypedValue struct {
@@ -1171,14 +1538,19 @@ type Metadata struct {
 // Type: MetadataEntryType
 // Namespace: http://www.vmware.com/vcloud/v1.5
 type MetadataEntry struct {
-	Xmlns      string      `xml:"xmlns,attr"`
-	HREF       string      `xml:"href,attr"`
-	Type       string      `xml:"type,attr,omitempty"`
-	Xsi        string      `xml:"xmlns:xsi,attr"`
-	Domain     string      `xml:"Domain,omitempty"` // A value of SYSTEM places this MetadataEntry in the SYSTEM domain. Omit or leave empty to place this MetadataEntry in the GENERAL domain.
-	Key        string      `xml:"Key"`              // An arbitrary key name. Length cannot exceed 256 UTF-8 characters.
-	Link       []*Link     `xml:"Link,omitempty"`   //A reference to an entity or operation associated with this object.
-	TypedValue *TypedValue `xml:"TypedValue"`
+	Xmlns      string             `xml:"xmlns,attr"`
+	HREF       string             `xml:"href,attr"`
+	Type       string             `xml:"type,attr,omitempty"`
+	Xsi        string             `xml:"xmlns:xsi,attr"`
+	Domain     *MetadataDomainTag `xml:"Domain,omitempty"` // A value of SYSTEM places this MetadataEntry in the SYSTEM domain. Omit or leave empty to place this MetadataEntry in the GENERAL domain.
+	Key        string             `xml:"Key"`              // An arbitrary key name. Length cannot exceed 256 UTF-8 characters.
+	Link       []*Link            `xml:"Link,omitempty"`   //A reference to an entity or operation associated with this object.
+	TypedValue *TypedValue        `xml:"TypedValue"`
+}
+
+// IsSystem returns true if the entry is in the SYSTEM domain
+func (entry *MetadataEntry) IsSystem() bool {
+	return entry.Domain != nil && entry.Domain.Domain == MetadataDomainSystem
 }
 
 // VAppChildren is a container for virtual machines included in this vApp.
@@ -1284,6 +1656,8 @@ type VM struct {
 	// FIXME: Upstream bug? Missing NetworkConnectionSection
 	NetworkConnectionSection *NetworkConnectionSection `xml:"NetworkConnectionSection,omitempty"`
 
+	VmSpecSection *VmSpecSection `xml:"VmSpecSection,omitempty"` // The hardware and guest OS specification of the VM
+
 	VAppScopedLocalID string `xml:"VAppScopedLocalId,omitempty"` // A unique identifier for the virtual machine in the scope of the vApp.
 
 	Snapshots *SnapshotSection `xml:"SnapshotSection,omitempty"`
@@ -1293,6 +1667,8 @@ type VM struct {
 
 	VMCapabilities *VMCapabilities `xml:"VmCapabilities,omitempty"` // Allows you to specify certain capabilities of this virtual machine.
 	StorageProfile *Reference      `xml:"StorageProfile,omitempty"` // A reference to a storage profile to be used for this object. The specified storage profile must exist in the organization vDC that contains the object. If not specified, the default storage profile for the vDC is used.
+	ComputePolicy  *ComputePolicy  `xml:"ComputePolicy,omitempty"`  // The compute policies of the VM. API 33.0+
+	BootOptions    *BootOptions    `xml:"BootOptions,omitempty"`    // The boot options of the VM. API 32.0+
 	ProductSection *ProductSection `xml:"ProductSection,omitempty"`
 }
 
@@ -1302,10 +1678,35 @@ type VirtualHardwareSection struct {
 	XMLName xml.Name `xml:"VirtualHardwareSection"`
 	Xmlns   string   `xml:"vcloud,attr,omitempty"`
 
-	Info string                 `xml:"Info"`
-	HREF string                 `xml:"href,attr,omitempty"`
-	Type string                 `xml:"type,attr,omitempty"`
-	Item []*VirtualHardwareItem `xml:"Item,omitempty"`
+	Info        string                 `xml:"Info"`
+	HREF        string                 `xml:"href,attr,omitempty"`
+	Type        string                 `xml:"type,attr,omitempty"`
+	Item        []*VirtualHardwareItem `xml:"Item,omitempty"`
+	ExtraConfig []*ExtraConfig         `xml:"http://www.vmware.com/schema/ovf ExtraConfig,omitempty"` // Advanced settings of the VM. API 37.1+
+}
+
+// ExtraConfig is an entry of the extra configuration (the advanced VMX settings) of a VM, such as
+// disk.EnableUUID
+type ExtraConfig struct {
+	Key      string `xml:"http://www.vmware.com/schema/ovf key,attr"`
+	Value    string `xml:"http://www.vmware.com/schema/ovf value,attr"`
+	Required bool   `xml:"http://schemas.dmtf.org/ovf/envelope/1 required,attr"`
+}
+
+// VmExtraConfigUpdate is the VM sent to its reconfigureVm action to add, change or remove (with an
+// empty value) entries of its extra configuration. The other settings of the VM are left unchanged.
+type VmExtraConfigUpdate struct {
+	XMLName                xml.Name                    `xml:"Vm"`
+	Xmlns                  string                      `xml:"xmlns,attr"`
+	Name                   string                      `xml:"name,attr"`
+	Description            string                      `xml:"Description,omitempty"`
+	VirtualHardwareSection *VirtualHardwareExtraConfig `xml:"http://schemas.dmtf.org/ovf/envelope/1 VirtualHardwareSection"`
+}
+
+// VirtualHardwareExtraConfig is a virtual hardware section holding only extra configuration entries
+type VirtualHardwareExtraConfig struct {
+	Info        string         `xml:"http://schemas.dmtf.org/ovf/envelope/1 Info"`
+	ExtraConfig []*ExtraConfig `xml:"http://www.vmware.com/schema/ovf ExtraConfig"`
 }
 
 // Each ovf:Item parsed from the ovf:VirtualHardwareSection
@@ -1320,6 +1721,7 @@ type VirtualHardwareItem struct {
 	Address             string                         `xml:"Address,omitempty"`
 	AddressOnParent     int                            `xml:"AddressOnParent,omitempty"`
 	AllocationUnits     string                         `xml:"AllocationUnits,omitempty"`
+	Limit               *int                           `xml:"Limit,omitempty"` // Maximum CPU (MHz) or memory (MB) used by the VM, -1 when unlimited
 	Reservation         int                            `xml:"Reservation,omitempty"`
 	VirtualQuantity     int                            `xml:"VirtualQuantity,omitempty"`
 	Weight              int                            `xml:"Weight,omitempty"`
@@ -1350,10 +1752,52 @@ type VirtualHardwareHostResource struct {
 	StorageProfile    string `xml:"storageProfileHref,attr,omitempty"`
 	OverrideVmDefault bool   `xml:"storageProfileOverrideVmDefault,attr,omitempty"`
 	Disk              string `xml:"disk,attr,omitempty"`
-	//Iops              int    `xml:"iops,attr,omitempty"`
+	Iops              int    `xml:"iops,attr,omitempty"`
 	//OsType            string `xml:"osType,attr,omitempty"`
 }
 
+// VmSpecSection describes the virtual hardware and guest OS of a VM. When updating a VM, the
+// elements which are omitted are left unchanged.
+// Type: VmSpecSectionType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Specification of a virtual machine.
+type VmSpecSection struct {
+	Modified          *bool            `xml:"Modified,attr,omitempty"`     // True when the section is changed by the request.
+	Info              string           `xml:"ovf:Info"`                    // Description of the section.
+	OsType            string           `xml:"OsType,omitempty"`            // vSphere identifier of the guest OS, e.g. ubuntu64Guest.
+	NumCpus           *int             `xml:"NumCpus,omitempty"`           // Number of CPUs.
+	NumCoresPerSocket *int             `xml:"NumCoresPerSocket,omitempty"` // Number of cores among which the CPUs are distributed.
+	HardwareVersion   *HardwareVersion `xml:"HardwareVersion,omitempty"`   // Virtual hardware version, e.g. vmx-14.
+	VmToolsVersion    string           `xml:"VmToolsVersion,omitempty"`    // Read-only VMware tools version.
+	VirtualCpuType    string           `xml:"VirtualCpuType,omitempty"`    // VM32 or VM64.
+	TimeSyncWithHost  *bool            `xml:"TimeSyncWithHost,omitempty"`  // Synchronize the time of the VM with the host.
+	Firmware          string           `xml:"Firmware,omitempty"`          // Boot firmware of the VM, bios or efi. API 33.0+
+}
+
+// BootOptions describes how a VM boots. When updating a VM, the elements which are omitted are left
+// unchanged.
+// Type: BootOptionsType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Boot options for this virtual machine.
+// Since: 32.0
+type BootOptions struct {
+	HREF                 string   `xml:"href,attr,omitempty"`
+	Type                 string   `xml:"type,attr,omitempty"`
+	Link                 LinkList `xml:"Link,omitempty"`
+	BootDelay            *int     `xml:"BootDelay,omitempty"`            // Delay in milliseconds between the power on of the VM and its boot.
+	EnterBiosSetup       *bool    `xml:"EnterBIOSSetup,omitempty"`       // Enter the BIOS or EFI setup at the next boot. Reset by vCD once the VM has booted.
+	BootRetryEnabled     *bool    `xml:"BootRetryEnabled,omitempty"`     // Retry to boot when no boot device is found. API 33.0+
+	BootRetryDelay       *int     `xml:"BootRetryDelay,omitempty"`       // Delay in milliseconds before retrying to boot. API 33.0+
+	EfiSecureBootEnabled *bool    `xml:"EfiSecureBootEnabled,omitempty"` // Only boot signed software. Requires the efi firmware. API 33.0+
+	NetworkBootProtocol  string   `xml:"NetworkBootProtocol,omitempty"`  // Protocol used for network boot, IPv4 or IPv6. API 33.0+
+}
+
+// HardwareVersion is the vSphere name of a virtual hardware version, e.g. vmx-14
+type HardwareVersion struct {
+	HREF  string `xml:"href,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
 // SnapshotSection from VM struct
 type SnapshotSection struct {
 	// Extends OVF Section_Type
@@ -1384,6 +1828,7 @@ type OVFItem struct {
 	Description     string   `xml:"rasd:Description"`
 	ElementName     string   `xml:"rasd:ElementName"`
 	InstanceID      int      `xml:"rasd:InstanceID"`
+	Limit           *int     `xml:"rasd:Limit,omitempty"`
 	Reservation     int      `xml:"rasd:Reservation"`
 	ResourceType    int      `xml:"rasd:ResourceType"`
 	VirtualQuantity int      `xml:"rasd:VirtualQuantity"`
@@ -1392,6 +1837,55 @@ type OVFItem struct {
 	Link            *Link    `xml:"vcloud:Link"`
 }
 
+// RasdItemsList is a list of virtual hardware items, such as the disks and their controllers
+// returned by the virtualHardwareSection/disks link of a VM
+// Type: RasdItemsListType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Since: 0.9
+type RasdItemsList struct {
+	XMLName xml.Name               `xml:"RasdItemsList"`
+	HREF    string                 `xml:"href,attr,omitempty"`
+	Type    string                 `xml:"type,attr,omitempty"`
+	Link    LinkList               `xml:"Link,omitempty"`
+	Item    []*VirtualHardwareItem `xml:"Item,omitempty"`
+}
+
+// OVFRasdItemsList is the payload sent to update a RasdItemsList. Like OVFItem, it spells out the
+// namespace prefixes, which vCD requires for the RASD elements.
+type OVFRasdItemsList struct {
+	XMLName     xml.Name       `xml:"vcloud:RasdItemsList"`
+	XmlnsRasd   string         `xml:"xmlns:rasd,attr"`
+	XmlnsVCloud string         `xml:"xmlns:vcloud,attr"`
+	VCloudHREF  string         `xml:"vcloud:href,attr,omitempty"`
+	VCloudType  string         `xml:"vcloud:type,attr,omitempty"`
+	Item        []*OVFRasdItem `xml:"vcloud:Item"`
+}
+
+// OVFRasdItem is a disk or controller item of an OVFRasdItemsList. The elements follow the order
+// of the RASD schema.
+type OVFRasdItem struct {
+	Address         string             `xml:"rasd:Address,omitempty"`
+	AddressOnParent *int               `xml:"rasd:AddressOnParent,omitempty"`
+	Description     string             `xml:"rasd:Description,omitempty"`
+	ElementName     string             `xml:"rasd:ElementName"`
+	HostResource    []*OVFHostResource `xml:"rasd:HostResource,omitempty"`
+	InstanceID      int                `xml:"rasd:InstanceID"`
+	Parent          int                `xml:"rasd:Parent,omitempty"`
+	ResourceSubType string             `xml:"rasd:ResourceSubType,omitempty"`
+	ResourceType    int                `xml:"rasd:ResourceType"`
+}
+
+// OVFHostResource is the backing of a disk item in an OVFRasdItemsList
+type OVFHostResource struct {
+	BusSubType        string `xml:"vcloud:busSubType,attr,omitempty"`
+	BusType           int    `xml:"vcloud:busType,attr,omitempty"`
+	Capacity          int    `xml:"vcloud:capacity,attr,omitempty"`
+	Disk              string `xml:"vcloud:disk,attr,omitempty"`
+	Iops              int    `xml:"vcloud:iops,attr,omitempty"`
+	StorageProfile    string `xml:"vcloud:storageProfileHref,attr,omitempty"`
+	OverrideVmDefault bool   `xml:"vcloud:storageProfileOverrideVmDefault,attr,omitempty"`
+}
+
 // DeployVAppParams are the parameters to a deploy vApp request
 // Type: DeployVAppParamsType
 // Namespace: http://www.vmware.com/vcloud/v1.5
@@ -1423,24 +1917,24 @@ type GuestCustomizationSection struct {
 	// FIXME: Fix the OVF section
 	Info string `xml:"ovf:Info"`
 	// Elements
-	Enabled               bool     `xml:"Enabled,omitempty"`               // True if guest customization is enabled.
-	ChangeSid             bool     `xml:"ChangeSid,omitempty"`             // True if customization can change the Windows SID of this virtual machine.
-	VirtualMachineID      string   `xml:"VirtualMachineId,omitempty"`      // Virtual machine ID to apply.
-	JoinDomainEnabled     bool     `xml:"JoinDomainEnabled,omitempty"`     // True if this virtual machine can join a Windows Domain.
-	UseOrgSettings        bool     `xml:"UseOrgSettings,omitempty"`        // True if customization should use organization settings (OrgGuestPersonalizationSettings) when joining a Windows Domain.
-	DomainName            string   `xml:"DomainName,omitempty"`            // The name of the Windows Domain to join.
-	DomainUserName        string   `xml:"DomainUserName,omitempty"`        // User name to specify when joining a Windows Domain.
-	DomainUserPassword    string   `xml:"DomainUserPassword,omitempty"`    // Password to use with DomainUserName.
-	MachineObjectOU       string   `xml:"MachineObjectOU,omitempty"`       // The name of the Windows Domain Organizational Unit (OU) in which the computer account for this virtual machine will be created.
-	AdminPasswordEnabled  bool     `xml:"AdminPasswordEnabled,omitempty"`  // True if guest customization can modify administrator password settings for this virtual machine.
-	AdminPasswordAuto     bool     `xml:"AdminPasswordAuto,omitempty"`     // True if the administrator password for this virtual machine should be automatically generated.
-	AdminPassword         string   `xml:"AdminPassword,omitempty"`         // True if the administrator password for this virtual machine should be set to this string. (AdminPasswordAuto must be false.)
-	AdminAutoLogonEnabled bool     `xml:"AdminAutoLogonEnabled,omitempty"` // True if guest administrator should automatically log into this virtual machine.
-	AdminAutoLogonCount   int      `xml:"AdminAutoLogonCount,omitempty"`   // Number of times administrator can automatically log into this virtual machine. In case AdminAutoLogon is set to True, this value should be between 1 and 100. Otherwise, it should be 0.
-	ResetPasswordRequired bool     `xml:"ResetPasswordRequired,omitempty"` // True if the administrator password for this virtual machine must be reset after first use.
-	CustomizationScript   string   `xml:"CustomizationScript,omitempty"`   // Script to run on guest customization. The entire script must appear in this element. Use the XML entity &#13; to represent a newline. Unicode characters can be represented in the form &#xxxx; where xxxx is the character number.
-	ComputerName          string   `xml:"ComputerName,omitempty"`          // Computer name to assign to this virtual machine.
-	Link                  LinkList `xml:"Link,omitempty"`                  // A link to an operation on this section.
+	Enabled               bool     `xml:"Enabled"`                       // True if guest customization is enabled.
+	ChangeSid             bool     `xml:"ChangeSid"`                     // True if customization can change the Windows SID of this virtual machine.
+	VirtualMachineID      string   `xml:"VirtualMachineId,omitempty"`    // Virtual machine ID to apply.
+	JoinDomainEnabled     bool     `xml:"JoinDomainEnabled"`             // True if this virtual machine can join a Windows Domain.
+	UseOrgSettings        bool     `xml:"UseOrgSettings"`                // True if customization should use organization settings (OrgGuestPersonalizationSettings) when joining a Windows Domain.
+	DomainName            string   `xml:"DomainName,omitempty"`          // The name of the Windows Domain to join.
+	DomainUserName        string   `xml:"DomainUserName,omitempty"`      // User name to specify when joining a Windows Domain.
+	DomainUserPassword    string   `xml:"DomainUserPassword,omitempty"`  // Password to use with DomainUserName.
+	MachineObjectOU       string   `xml:"MachineObjectOU,omitempty"`     // The name of the Windows Domain Organizational Unit (OU) in which the computer account for this virtual machine will be created.
+	AdminPasswordEnabled  bool     `xml:"AdminPasswordEnabled"`          // True if guest customization can modify administrator password settings for this virtual machine.
+	AdminPasswordAuto     bool     `xml:"AdminPasswordAuto"`             // True if the administrator password for this virtual machine should be automatically generated.
+	AdminPassword         string   `xml:"AdminPassword,omitempty"`       // True if the administrator password for this virtual machine should be set to this string. (AdminPasswordAuto must be false.)
+	AdminAutoLogonEnabled bool     `xml:"AdminAutoLogonEnabled"`         // True if guest administrator should automatically log into this virtual machine.
+	AdminAutoLogonCount   int      `xml:"AdminAutoLogonCount,omitempty"` // Number of times administrator can automatically log into this virtual machine. In case AdminAutoLogon is set to True, this value should be between 1 and 100. Otherwise, it should be 0.
+	ResetPasswordRequired bool     `xml:"ResetPasswordRequired"`         // True if the administrator password for this virtual machine must be reset after first use.
+	CustomizationScript   string   `xml:"CustomizationScript,omitempty"` // Script to run on guest customization. The entire script must appear in this element. Use the XML entity &#13; to represent a newline. Unicode characters can be represented in the form &#xxxx; where xxxx is the character number.
+	ComputerName          string   `xml:"ComputerName,omitempty"`        // Computer name to assign to this virtual machine.
+	Link                  LinkList `xml:"Link,omitempty"`                // A link to an operation on this section.
 }
 
 // InstantiateVAppTemplateParams represents vApp template instantiation parameters.
@@ -1476,6 +1970,7 @@ type InstantiateVAppTemplateParams struct {
 // Since: 5.1
 type EdgeGateway struct {
 	// Attributes
+	Xmlns        string `xml:"xmlns,attr,omitempty"`
 	HREF         string `xml:"href,attr,omitempty"`         // The URI of the entity.
 	Type         string `xml:"type,attr,omitempty"`         // The MIME type of the entity.
 	ID           string `xml:"id,attr,omitempty"`           // The entity identifier, expressed in URN format. The value of this attribute uniquely identifies the entity, persists for the life of the entity, and is never reused
@@ -1503,6 +1998,7 @@ type GatewayConfiguration struct {
 	EdgeGatewayServiceConfiguration *GatewayFeatures   `xml:"EdgeGatewayServiceConfiguration,omitempty"` // Represents Gateway Features.
 	HaEnabled                       bool               `xml:"HaEnabled,omitempty"`                       // True if this gateway is highly available. (Requires two vShield edge VMs.)
 	UseDefaultRouteForDNSRelay      bool               `xml:"UseDefaultRouteForDnsRelay,omitempty"`      // True if the default gateway on the external network selected for default route should be used as the DNS relay.
+	AdvancedNetworkingEnabled       bool               `xml:"AdvancedNetworkingEnabled,omitempty"`       // True if the gateway uses advanced networking, which gives access to the NSX-V endpoints.
 }
 
 // GatewayInterfaces is a list of Gateway Interfaces.
@@ -1520,15 +2016,15 @@ type GatewayInterfaces struct {
 // Description: Gateway Interface configuration.
 // Since: 5.1
 type GatewayInterface struct {
-	Name                string               `xml:"Name,omitempty"`                // Internally generated name for the Gateway Interface.
-	DisplayName         string               `xml:"DisplayName,omitempty"`         // Gateway Interface display name.
-	Network             *Reference           `xml:"Network"`                       // A reference to the network connected to the gateway interface.
-	InterfaceType       string               `xml:"InterfaceType"`                 // The type of interface: One of: Uplink, Internal
-	SubnetParticipation *SubnetParticipation `xml:"SubnetParticipation,omitempty"` // IP allocation per subnet.
-	ApplyRateLimit      bool                 `xml:"ApplyRateLimit,omitempty"`      // True if rate limiting is applied on this interface.
-	InRateLimit         float64              `xml:"InRateLimit,omitempty"`         // Incoming rate limit expressed as Gbps.
-	OutRateLimit        float64              `xml:"OutRateLimit,omitempty"`        // Outgoing rate limit expressed as Gbps.
-	UseForDefaultRoute  bool                 `xml:"UseForDefaultRoute,omitempty"`  // True if this network is default route for the gateway.
+	Name                string                 `xml:"Name,omitempty"`                // Internally generated name for the Gateway Interface.
+	DisplayName         string                 `xml:"DisplayName,omitempty"`         // Gateway Interface display name.
+	Network             *Reference             `xml:"Network"`                       // A reference to the network connected to the gateway interface.
+	InterfaceType       string                 `xml:"InterfaceType"`                 // The type of interface: One of: Uplink, Internal
+	SubnetParticipation []*SubnetParticipation `xml:"SubnetParticipation,omitempty"` // IP allocation per subnet.
+	ApplyRateLimit      bool                   `xml:"ApplyRateLimit,omitempty"`      // True if rate limiting is applied on this interface.
+	InRateLimit         float64                `xml:"InRateLimit,omitempty"`         // Incoming rate limit expressed as Gbps.
+	OutRateLimit        float64                `xml:"OutRateLimit,omitempty"`        // Outgoing rate limit expressed as Gbps.
+	UseForDefaultRoute  bool                   `xml:"UseForDefaultRoute,omitempty"`  // True if this network is default route for the gateway.
 }
 
 // SubnetParticipation allows to chose which subnets a gateway can be a part of
@@ -1999,14 +2495,32 @@ type QueryResultRecordsType struct {
 	VMRecord                        []*QueryResultVMRecordType                        `xml:"VMRecord"`                        // A record representing a VM result.
 	AdminVMRecord                   []*QueryResultVMRecordType                        `xml:"AdminVMRecord"`                   // A record representing a Admin VM result.
 	VAppRecord                      []*QueryResultVAppRecordType                      `xml:"VAppRecord"`                      // A record representing a VApp result.
+	VAppTemplateRecord              []*QueryResultVAppTemplateRecordType              `xml:"VAppTemplateRecord"`              // A record representing a vApp template result.
+	AdminVAppRecord                 []*QueryResultVAppRecordType                      `xml:"AdminVAppRecord"`                 // A record representing an Admin vApp result.
+	OrgVdcNetworkRecord             []*QueryResultOrgVdcNetworkRecordType             `xml:"OrgVdcNetworkRecord"`             // A record representing an org VDC network result.
 	OrgVdcStorageProfileRecord      []*QueryResultOrgVdcStorageProfileRecordType      `xml:"OrgVdcStorageProfileRecord"`      // A record representing storage profiles
+	AdminOrgVdcStorageProfileRecord []*QueryResultOrgVdcStorageProfileRecordType      `xml:"AdminOrgVdcStorageProfileRecord"` // A record representing storage profiles of all orgs
 	MediaRecord                     []*MediaRecordType                                `xml:"MediaRecord"`                     // A record representing media
 	AdminMediaRecord                []*MediaRecordType                                `xml:"AdminMediaRecord"`                // A record representing Admin media
 	VMWProviderVdcRecord            []*QueryResultVMWProviderVdcRecordType            `xml:"VMWProviderVdcRecord"`            // A record representing a Provider VDC result.
 	ProviderVdcStorageProfileRecord []*QueryResultProviderVdcStorageProfileRecordType `xml:"ProviderVdcStorageProfileRecord"` // A record representing a Provider VDC storage profile result
 	NetworkPoolRecord               []*QueryResultNetworkPoolRecordType               `xml:"NetworkPoolRecord"`               // A record representing a network pool
+	PortgroupRecord                 []*QueryResultPortgroupRecordType                 `xml:"PortgroupRecord"`                 // A record representing a vSphere port group
+	VirtualCenterRecord             []*QueryResultVirtualCenterRecordType             `xml:"VirtualCenterRecord"`             // A record representing a vCenter server
+	HostRecord                      []*QueryResultHostRecordType                      `xml:"HostRecord"`                      // A record representing an ESXi host
+	DatastoreRecord                 []*QueryResultDatastoreRecordType                 `xml:"DatastoreRecord"`                 // A record representing a datastore
+	AdminServiceRecord              []*QueryResultAdminServiceRecordType              `xml:"AdminServiceRecord"`              // A record representing an extension service
+	CellRecord                      []*QueryResultCellRecordType                      `xml:"CellRecord"`                      // A record representing a vCD cell
+	AdminApiFilterRecord            []*QueryResultApiFilterRecordType                 `xml:"AdminApiFilterRecord"`            // A record representing an API filter of an extension service
 	DiskRecord                      []*DiskRecordType                                 `xml:"DiskRecord"`                      // A record representing a independent Disk.
 	AdminDiskRecord                 []*DiskRecordType                                 `xml:"AdminDiskRecord"`                 // A record representing a independent Disk.
+	OrgVdcRecord                    []*QueryResultOrgVdcRecordType                    `xml:"OrgVdcRecord"`                    // A record representing an org VDC
+	AdminOrgVdcRecord               []*QueryResultOrgVdcRecordType                    `xml:"AdminOrgVdcRecord"`               // A record representing an org VDC of any org
+	TaskRecord                      []*QueryResultTaskRecordType                      `xml:"TaskRecord"`                      // A record representing a task
+	AdminTaskRecord                 []*QueryResultTaskRecordType                      `xml:"AdminTaskRecord"`                 // A record representing a task of any org
+	EventRecord                     []*QueryResultEventRecordType                     `xml:"EventRecord"`                     // A record representing an event
+	AdminEventRecord                []*QueryResultEventRecordType                     `xml:"AdminEventRecord"`                // A record representing an event of any org
+	NsxtManagerRecord               []*QueryResultNsxtManagerRecordType               `xml:"NsxTManagerRecord"`               // A record representing an NSX-T manager
 }
 
 // QueryResultEdgeGatewayRecordType represents an edge gateway record as query result.
@@ -2039,6 +2553,8 @@ type QueryResultVMRecordType struct {
 	VdcHREF                 string `xml:"vdc,attr,omitempty"`
 	VAppParentHREF          string `xml:"container,attr,omitempty"`
 	VAppParentName          string `xml:"containerName,attr,omitempty"`
+	IpAddress               string `xml:"ipAddress,attr,omitempty"`   // IP address of the primary network card.
+	DateCreated             string `xml:"dateCreated,attr,omitempty"` // Creation date/time of the VM.
 	HardwareVersion         int    `xml:"hardwareVersion,attr,omitempty"`
 	HighestSupportedVersion int    `xml:"pvdcHighestSupportedHardwareVersion,attr,omitempty"`
 	VmToolsVersion          string `xml:"vmToolsVersion,attr,omitempty"`
@@ -2087,6 +2603,116 @@ type QueryResultVAppRecordType struct {
 	TaskDetails             string `xml:"taskDetails,attr,omitempty"`
 }
 
+// QueryResultVAppTemplateRecordType represents a vApp template as query result.
+type QueryResultVAppTemplateRecordType struct {
+	// Attributes
+	HREF             string `xml:"href,attr,omitempty"`         // The URI of the entity.
+	Name             string `xml:"name,attr"`                   // The name of the entity.
+	CatalogName      string `xml:"catalogName,attr,omitempty"`  // Name of the catalog containing the template.
+	CreationDate     string `xml:"creationDate,attr,omitempty"` // Creation date/time of the template.
+	Busy             bool   `xml:"isBusy,attr,omitempty"`
+	Deployed         bool   `xml:"isDeployed,attr,omitempty"`
+	Enabled          bool   `xml:"isEnabled,attr,omitempty"`
+	Expired          bool   `xml:"isExpired,attr,omitempty"`
+	GoldMaster       bool   `xml:"isGoldMaster,attr,omitempty"`
+	Published        bool   `xml:"isPublished,attr,omitempty"`
+	OwnerName        string `xml:"ownerName,attr,omitempty"`
+	Status           string `xml:"status,attr,omitempty"`
+	StorageKB        int    `xml:"storageKB,attr,omitempty"`
+	StorageProfile   string `xml:"storageProfileName,attr,omitempty"`
+	VdcHREF          string `xml:"vdc,attr,omitempty"`
+	VdcName          string `xml:"vdcName,attr,omitempty"`
+	AutoDeleteNotify bool   `xml:"isAutoDeleteNotified,attr,omitempty"`
+}
+
+// QueryResultOrgVdcNetworkRecordType represents an org VDC network as query result.
+type QueryResultOrgVdcNetworkRecordType struct {
+	// Attributes
+	HREF               string `xml:"href,attr,omitempty"` // The URI of the entity.
+	Name               string `xml:"name,attr,omitempty"` // The name of the entity.
+	ConnectedTo        string `xml:"connectedTo,attr,omitempty"`
+	DefaultGateway     string `xml:"defaultGateway,attr,omitempty"`
+	Dns1               string `xml:"dns1,attr,omitempty"`
+	Dns2               string `xml:"dns2,attr,omitempty"`
+	DnsSuffix          string `xml:"dnsSuffix,attr,omitempty"`
+	Netmask            string `xml:"netmask,attr,omitempty"`
+	LinkType           int    `xml:"linkType,attr,omitempty"` // 0 = direct, 1 = routed, 2 = isolated
+	IsBusy             bool   `xml:"isBusy,attr,omitempty"`
+	IsIpScopeInherited bool   `xml:"isIpScopeInherited,attr,omitempty"`
+	IsShared           bool   `xml:"isShared,attr,omitempty"`
+	VdcHREF            string `xml:"vdc,attr,omitempty"`
+	VdcName            string `xml:"vdcName,attr,omitempty"`
+}
+
+// QueryResultOrgVdcRecordType represents an org VDC as query result.
+type QueryResultOrgVdcRecordType struct {
+	// Attributes
+	HREF                    string `xml:"href,attr,omitempty"` // The URI of the entity.
+	Name                    string `xml:"name,attr,omitempty"` // The name of the entity.
+	Description             string `xml:"description,attr,omitempty"`
+	AllocationModel         string `xml:"allocationModel,attr,omitempty"`
+	Status                  string `xml:"status,attr,omitempty"`
+	OrgHREF                 string `xml:"org,attr,omitempty"`
+	OrgName                 string `xml:"orgName,attr,omitempty"`
+	ProviderVdcHREF         string `xml:"providerVdc,attr,omitempty"`
+	ProviderVdcName         string `xml:"providerVdcName,attr,omitempty"`
+	NetworkPoolHREF         string `xml:"networkPool,attr,omitempty"`
+	CpuAllocationMhz        int    `xml:"cpuAllocationMhz,attr,omitempty"`
+	CpuLimitMhz             int    `xml:"cpuLimitMhz,attr,omitempty"`
+	CpuUsedMhz              int    `xml:"cpuUsedMhz,attr,omitempty"`
+	MemoryAllocationMB      int    `xml:"memoryAllocationMB,attr,omitempty"`
+	MemoryLimitMB           int    `xml:"memoryLimitMB,attr,omitempty"`
+	MemoryUsedMB            int    `xml:"memoryUsedMB,attr,omitempty"`
+	StorageLimitMB          int    `xml:"storageLimitMB,attr,omitempty"`
+	StorageUsedMB           int    `xml:"storageUsedMB,attr,omitempty"`
+	NumberOfVApps           int    `xml:"numberOfVApps,attr,omitempty"`
+	NumberOfVAppTemplates   int    `xml:"numberOfVAppTemplates,attr,omitempty"`
+	NumberOfMedia           int    `xml:"numberOfMedia,attr,omitempty"`
+	NumberOfDisks           int    `xml:"numberOfDisks,attr,omitempty"`
+	NumberOfStorageProfiles int    `xml:"numberOfStorageProfiles,attr,omitempty"`
+	IsBusy                  bool   `xml:"isBusy,attr,omitempty"`
+	IsEnabled               bool   `xml:"isEnabled,attr,omitempty"`
+}
+
+// QueryResultTaskRecordType represents a task as query result.
+type QueryResultTaskRecordType struct {
+	// Attributes
+	HREF             string `xml:"href,attr,omitempty"` // The URI of the entity.
+	Name             string `xml:"name,attr,omitempty"` // The name of the task.
+	Status           string `xml:"status,attr,omitempty"`
+	Operation        string `xml:"operation,attr,omitempty"`
+	OperationFull    string `xml:"operationFull,attr,omitempty"`
+	StartDate        string `xml:"startDate,attr,omitempty"`
+	EndDate          string `xml:"endDate,attr,omitempty"`
+	ObjectHREF       string `xml:"object,attr,omitempty"`
+	ObjectName       string `xml:"objectName,attr,omitempty"`
+	ObjectType       string `xml:"objectType,attr,omitempty"`
+	OwnerHREF        string `xml:"owner,attr,omitempty"`
+	OwnerName        string `xml:"ownerName,attr,omitempty"`
+	OrgHREF          string `xml:"org,attr,omitempty"`
+	OrgName          string `xml:"orgName,attr,omitempty"`
+	ServiceNamespace string `xml:"serviceNamespace,attr,omitempty"`
+}
+
+// QueryResultEventRecordType represents an event as query result.
+type QueryResultEventRecordType struct {
+	// Attributes
+	HREF             string `xml:"href,attr,omitempty"`      // The URI of the entity.
+	EventType        string `xml:"eventType,attr,omitempty"` // The type of the event, e.g. com/vmware/vcloud/event/vm/create
+	EventStatus      int    `xml:"eventStatus,attr,omitempty"`
+	Description      string `xml:"description,attr,omitempty"`
+	Details          string `xml:"details,attr,omitempty"`
+	TimeStamp        string `xml:"timeStamp,attr,omitempty"`
+	EntityHREF       string `xml:"entity,attr,omitempty"`
+	EntityName       string `xml:"entityName,attr,omitempty"`
+	EntityType       string `xml:"entityType,attr,omitempty"`
+	OrgHREF          string `xml:"org,attr,omitempty"`
+	OrgName          string `xml:"orgName,attr,omitempty"`
+	UserName         string `xml:"userName,attr,omitempty"`
+	ServiceNamespace string `xml:"serviceNamespace,attr,omitempty"`
+	ProductVersion   string `xml:"productVersion,attr,omitempty"`
+}
+
 // QueryResultOrgVdcStorageProfileRecordType represents a storage
 // profile as query result.
 type QueryResultOrgVdcStorageProfileRecordType struct {
@@ -2154,6 +2780,317 @@ type QueryResultNetworkPoolRecordType struct {
 	NetworkPoolType int    `xml:"networkPoolType,attr,omitempty"`
 }
 
+// ProviderVdc represents the admin view of a provider VDC
+// Type: ProviderVdcType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Represents a provider VDC.
+// Since: 0.9
+// https://code.vmware.com/apis/220/vcloud#/doc/doc/types/ProviderVdcType.html
+type ProviderVdc struct {
+	XMLName               xml.Name                 `xml:"ProviderVdc"`
+	Xmlns                 string                   `xml:"xmlns,attr,omitempty"`
+	HREF                  string                   `xml:"href,attr,omitempty"`
+	Type                  string                   `xml:"type,attr,omitempty"`
+	ID                    string                   `xml:"id,attr,omitempty"`
+	OperationKey          string                   `xml:"operationKey,attr,omitempty"`
+	Name                  string                   `xml:"name,attr"`
+	Status                int                      `xml:"status,attr,omitempty"`
+	Link                  LinkList                 `xml:"Link,omitempty"`
+	Description           string                   `xml:"Description,omitempty"`
+	Tasks                 *TasksInProgress         `xml:"Tasks,omitempty"`
+	ComputeCapacity       *RootComputeCapacity     `xml:"ComputeCapacity,omitempty"`
+	StorageProfiles       *ProviderStorageProfiles `xml:"StorageProfiles,omitempty"`
+	Capabilities          *Capabilities            `xml:"Capabilities,omitempty"`
+	Vdcs                  *VDCList                 `xml:"Vdcs,omitempty"`
+	IsEnabled             *bool                    `xml:"IsEnabled,omitempty"`
+	NetworkPoolReferences *NetworkPoolReferences   `xml:"NetworkPoolReferences,omitempty"`
+}
+
+// VMWProviderVdc represents the extension view of a provider VDC, with its vSphere backing.
+// Only the elements which are not in ProviderVdc are listed.
+// Type: VMWProviderVdcType
+// Namespace: http://www.vmware.com/vcloud/extension/v1.5
+// Description: Represents the extension view of a provider VDC.
+// Since: 1.0
+// https://code.vmware.com/apis/220/vcloud#/doc/doc/types/VMWProviderVdcType.html
+type VMWProviderVdc struct {
+	XMLName                         xml.Name           `xml:"VMWProviderVdc"`
+	HREF                            string             `xml:"href,attr,omitempty"`
+	Name                            string             `xml:"name,attr"`
+	DataStoreRefs                   *VimObjectRefs     `xml:"DataStoreRefs,omitempty"`
+	ResourcePoolRefs                *VimObjectRefs     `xml:"ResourcePoolRefs,omitempty"`
+	VimServer                       []*Reference       `xml:"VimServer,omitempty"`
+	HostReferences                  *VMWHostReferences `xml:"HostReferences,omitempty"`
+	HighestSupportedHardwareVersion string             `xml:"HighestSupportedHardwareVersion,omitempty"`
+	NsxTManagerReference            *Reference         `xml:"NsxTManagerReference,omitempty"` // API 32.0+, set for NSX-T backed provider VDCs
+	Tasks                           *TasksInProgress   `xml:"Tasks,omitempty"`
+}
+
+// VMWProviderVdcParams holds the parameters to create a provider VDC, backed by vSphere resource
+// pools of a vCenter server and, for NSX-T backed provider VDCs, by an NSX-T manager (API 32.0+)
+// Type: VMWProviderVdcParamsType
+// Namespace: http://www.vmware.com/vcloud/extension/v1.5
+// Description: Parameters for creating a provider VDC.
+// Since: 32.0
+type VMWProviderVdcParams struct {
+	XMLName                         xml.Name       `xml:"http://www.vmware.com/vcloud/extension/v1.5 VMWProviderVdcParams"`
+	Name                            string         `xml:"name,attr"`
+	Description                     string         `xml:"http://www.vmware.com/vcloud/v1.5 Description,omitempty"`
+	ResourcePoolRefs                *VimObjectRefs `xml:"ResourcePoolRefs"`
+	VimServer                       []*Reference   `xml:"VimServer"`
+	NsxTManagerReference            *Reference     `xml:"NsxTManagerReference,omitempty"`
+	NetworkPool                     *Reference     `xml:"NetworkPool,omitempty"`
+	HighestSupportedHardwareVersion string         `xml:"HighestSupportedHardwareVersion,omitempty"`
+	IsEnabled                       *bool          `xml:"IsEnabled,omitempty"`
+	StorageProfile                  []string       `xml:"StorageProfile"` // names of the vSphere storage policies, "*" for any
+}
+
+// RootComputeCapacity represents the compute capacity of a provider VDC
+// Type: RootComputeCapacityType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Represents compute capacity with units.
+// Since: 0.9
+type RootComputeCapacity struct {
+	Cpu       *ProviderVdcCapacity `xml:"Cpu"`
+	Memory    *ProviderVdcCapacity `xml:"Memory"`
+	IsElastic *bool                `xml:"IsElastic,omitempty"`
+	IsHA      *bool                `xml:"IsHA,omitempty"`
+}
+
+// ProviderVdcCapacity represents the capacity and usage of a provider VDC resource
+// Type: ProviderVdcCapacityType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Represents resource capacity in a provider vDC.
+// Since: 0.9
+type ProviderVdcCapacity struct {
+	Units      string `xml:"Units"`
+	Allocation int64  `xml:"Allocation,omitempty"`
+	Reserved   int64  `xml:"Reserved,omitempty"`
+	Total      int64  `xml:"Total,omitempty"`
+	Used       int64  `xml:"Used,omitempty"`
+	Overhead   int64  `xml:"Overhead,omitempty"`
+}
+
+// ProviderStorageProfiles is a list of references to the storage profiles of a provider VDC
+type ProviderStorageProfiles struct {
+	ProviderVdcStorageProfile []*Reference `xml:"ProviderVdcStorageProfile,omitempty"`
+}
+
+// NetworkPoolReferences is a list of references to network pools
+type NetworkPoolReferences struct {
+	NetworkPoolReference []*Reference `xml:"NetworkPoolReference,omitempty"`
+}
+
+// VimObjectRefs is a list of references to vSphere objects
+type VimObjectRefs struct {
+	VimObjectRef []*VimObjectRef `xml:"VimObjectRef,omitempty"`
+}
+
+// VimObjectRef is a reference to a vSphere object, such as a resource pool or a datastore
+// Type: VimObjectRefType
+// Namespace: http://www.vmware.com/vcloud/extension/v1.5
+// Description: Represents the Managed Object Reference (MoRef) and the type of a vSphere object.
+// Since: 0.9
+type VimObjectRef struct {
+	VimServerRef  *Reference `xml:"VimServerRef"`
+	MoRef         string     `xml:"MoRef"`
+	VimObjectType string     `xml:"VimObjectType"`
+}
+
+// VMWHostReferences is a list of references to the hosts of a provider VDC
+type VMWHostReferences struct {
+	HostReference []*Reference `xml:"HostReference,omitempty"`
+}
+
+// QueryResultPortgroupRecordType represents a vSphere port group as query result.
+type QueryResultPortgroupRecordType struct {
+	// Attributes
+	HREF          string `xml:"href,attr,omitempty"` // The URI of the entity.
+	Name          string `xml:"name,attr,omitempty"` // Port group name.
+	MoRef         string `xml:"moref,attr,omitempty"`
+	NetworkName   string `xml:"networkName,attr,omitempty"`
+	PortgroupType string `xml:"portgroupType,attr,omitempty"` // DV_PORTGROUP or NETWORK
+	VcHREF        string `xml:"vc,attr,omitempty"`
+	VcName        string `xml:"vcName,attr,omitempty"`
+	IsVCEnabled   bool   `xml:"isVCEnabled,attr,omitempty"`
+}
+
+// QueryResultVirtualCenterRecordType represents a vCenter server attached to vCD as query result.
+type QueryResultVirtualCenterRecordType struct {
+	// Attributes
+	HREF          string `xml:"href,attr,omitempty"` // The URI of the entity.
+	Name          string `xml:"name,attr,omitempty"` // vCenter name.
+	IsBusy        bool   `xml:"isBusy,attr,omitempty"`
+	IsEnabled     bool   `xml:"isEnabled,attr,omitempty"`
+	IsSupported   bool   `xml:"isSupported,attr,omitempty"`
+	ListenerState string `xml:"listenerState,attr,omitempty"`
+	Status        string `xml:"status,attr,omitempty"`
+	Url           string `xml:"url,attr,omitempty"`
+	UserName      string `xml:"userName,attr,omitempty"`
+	VcVersion     string `xml:"vcVersion,attr,omitempty"`
+	Uuid          string `xml:"uuid,attr,omitempty"`
+	VsmIP         string `xml:"vsmIP,attr,omitempty"` // NSX manager IP
+}
+
+// QueryResultNsxtManagerRecordType represents an NSX-T manager registered in vCD, as query result
+type QueryResultNsxtManagerRecordType struct {
+	HREF string `xml:"href,attr,omitempty"` // The URI of the entity.
+	Name string `xml:"name,attr,omitempty"` // NSX-T manager name.
+	URL  string `xml:"url,attr,omitempty"`  // URL of the NSX-T manager API
+	Site string `xml:"site,attr,omitempty"` // vCD site of the NSX-T manager
+}
+
+// QueryResultHostRecordType represents an ESXi host as query result.
+type QueryResultHostRecordType struct {
+	// Attributes
+	HREF                string `xml:"href,attr,omitempty"` // The URI of the entity.
+	Name                string `xml:"name,attr,omitempty"` // Host name.
+	IsBusy              bool   `xml:"isBusy,attr,omitempty"`
+	IsEnabled           bool   `xml:"isEnabled,attr,omitempty"`
+	IsPrepared          bool   `xml:"isPrepared,attr,omitempty"`
+	IsCrossHostEnabled  bool   `xml:"isCrossHostEnabled,attr,omitempty"`
+	IsHung              bool   `xml:"isHung,attr,omitempty"`
+	IsInMaintenance     bool   `xml:"isInMaintenanceMode,attr,omitempty"`
+	NumberOfVMs         int    `xml:"numberOfVMs,attr,omitempty"`
+	OsVersion           string `xml:"osVersion,attr,omitempty"`
+	State               string `xml:"state,attr,omitempty"`
+	VcHREF              string `xml:"vc,attr,omitempty"`
+	VcName              string `xml:"vcName,attr,omitempty"`
+	CpuType             string `xml:"cpuType,attr,omitempty"`
+	NumberOfCpuPackages int    `xml:"numOfCpusPackages,attr,omitempty"`
+	NumberOfLogicalCpus int    `xml:"numOfCpusLogical,attr,omitempty"`
+}
+
+// QueryResultDatastoreRecordType represents a datastore as query result.
+type QueryResultDatastoreRecordType struct {
+	// Attributes
+	HREF                 string `xml:"href,attr,omitempty"` // The URI of the entity.
+	Name                 string `xml:"name,attr,omitempty"` // Datastore name.
+	DatastoreType        string `xml:"datastoreType,attr,omitempty"`
+	IsEnabled            bool   `xml:"isEnabled,attr,omitempty"`
+	IsDeleted            bool   `xml:"isDeleted,attr,omitempty"`
+	MoRef                string `xml:"moref,attr,omitempty"`
+	VcHREF               string `xml:"vc,attr,omitempty"`
+	VcName               string `xml:"vcName,attr,omitempty"`
+	StorageTotalMB       int64  `xml:"storageMB,attr,omitempty"`
+	StorageUsedMB        int64  `xml:"storageUsedMB,attr,omitempty"`
+	ProvisionedStorageMB int64  `xml:"provisionedStorageMB,attr,omitempty"`
+	RequestedStorageMB   int64  `xml:"requestedStorageMB,attr,omitempty"`
+	NumberOfProviderVdcs int    `xml:"numberOfProviderVdcs,attr,omitempty"`
+}
+
+// QueryResultCellRecordType represents a vCD cell (server) as query result.
+type QueryResultCellRecordType struct {
+	// Attributes
+	HREF       string `xml:"href,attr,omitempty"` // The URI of the entity.
+	Name       string `xml:"name,attr,omitempty"` // Cell name.
+	PrimaryIP  string `xml:"primaryIp,attr,omitempty"`
+	Version    string `xml:"version,attr,omitempty"`   // vCD version of the cell, e.g. 9.7.0.13635483
+	BuildDate  string `xml:"buildDate,attr,omitempty"` // Build date of the vCD version of the cell
+	IsActive   int    `xml:"isActive,attr,omitempty"`  // 1 when the cell is running
+	IsVMwareVc bool   `xml:"isVMwareVc,attr,omitempty"`
+}
+
+// QueryResultAdminServiceRecordType represents an extension service as query result.
+type QueryResultAdminServiceRecordType struct {
+	// Attributes
+	HREF                 string `xml:"href,attr,omitempty"` // The URI of the entity.
+	Name                 string `xml:"name,attr,omitempty"` // Service name.
+	Namespace            string `xml:"namespace,attr,omitempty"`
+	Vendor               string `xml:"vendor,attr,omitempty"`
+	RoutingKey           string `xml:"routingKey,attr,omitempty"`
+	Exchange             string `xml:"exchange,attr,omitempty"`
+	Priority             int    `xml:"priority,attr,omitempty"`
+	Enabled              bool   `xml:"enabled,attr,omitempty"`
+	AuthorizationEnabled bool   `xml:"isAuthorizationEnabled,attr,omitempty"`
+}
+
+// QueryResultApiFilterRecordType represents an API filter of an extension service as query result.
+type QueryResultApiFilterRecordType struct {
+	// Attributes
+	HREF       string `xml:"href,attr,omitempty"`    // The URI of the entity.
+	Service    string `xml:"service,attr,omitempty"` // The URI of the extension service.
+	UrlPattern string `xml:"urlPattern,attr,omitempty"`
+}
+
+// VMWResourcePoolList is the list of the resource pools of a vCenter that are available to back
+// a provider VDC
+// Type: ResourcePoolListType
+// Namespace: http://www.vmware.com/vcloud/extension/v1.5
+// Since: 1.0
+type VMWResourcePoolList struct {
+	ResourcePool []*VMWResourcePool `xml:"ResourcePool,omitempty"`
+}
+
+// VMWResourcePool is a vCenter resource pool
+// Type: ResourcePoolType
+// Namespace: http://www.vmware.com/vcloud/extension/v1.5
+// Since: 1.0
+type VMWResourcePool struct {
+	Name          string         `xml:"name,attr"`
+	MoRef         string         `xml:"MoRef"`
+	VimObjectType string         `xml:"VimObjectType,omitempty"`
+	DataStoreRefs *VimObjectRefs `xml:"DataStoreRefs,omitempty"`
+}
+
+// VMWStorageProfiles is the list of the storage profiles of a vCenter
+// Type: VMWStorageProfilesType
+// Namespace: http://www.vmware.com/vcloud/extension/v1.5
+// Since: 5.1
+type VMWStorageProfiles struct {
+	VMWStorageProfile []*VMWStorageProfile `xml:"VMWStorageProfile,omitempty"`
+}
+
+// VMWStorageProfile is a vCenter storage profile with its capacity
+// Type: VMWStorageProfileType
+// Namespace: http://www.vmware.com/vcloud/extension/v1.5
+// Since: 5.1
+type VMWStorageProfile struct {
+	Name           string `xml:"name,attr"`
+	MoRef          string `xml:"MoRef"`
+	TotalStorageMb int64  `xml:"TotalStorageMb"`
+	FreeStorageMb  int64  `xml:"FreeStorageMb"`
+}
+
+// VMWExternalNetwork represents an external network, backed by vSphere port groups.
+// The elements inherited from NetworkType belong to the vCloud namespace, while the port group
+// references belong to the extension namespace.
+// Type: VMWExternalNetworkType
+// Namespace: http://www.vmware.com/vcloud/extension/v1.5
+// Description: Represents an external network.
+// Since: 1.0
+// https://code.vmware.com/apis/220/vcloud#/doc/doc/types/VMWExternalNetworkType.html
+type VMWExternalNetwork struct {
+	XMLName          xml.Name                      `xml:"http://www.vmware.com/vcloud/extension/v1.5 VMWExternalNetwork"`
+	HREF             string                        `xml:"href,attr,omitempty"`
+	Type             string                        `xml:"type,attr,omitempty"`
+	ID               string                        `xml:"id,attr,omitempty"`
+	OperationKey     string                        `xml:"operationKey,attr,omitempty"`
+	Name             string                        `xml:"name,attr"`
+	Link             LinkList                      `xml:"http://www.vmware.com/vcloud/v1.5 Link,omitempty"`
+	Description      string                        `xml:"http://www.vmware.com/vcloud/v1.5 Description,omitempty"`
+	Tasks            *TasksInProgress              `xml:"http://www.vmware.com/vcloud/v1.5 Tasks,omitempty"`
+	Configuration    *ExternalNetworkConfiguration `xml:"http://www.vmware.com/vcloud/v1.5 Configuration,omitempty"`
+	VimPortGroupRef  *VimObjectRef                 `xml:"VimPortGroupRef,omitempty"` // Deprecated: use VimPortGroupRefs
+	VimPortGroupRefs *VimObjectRefs                `xml:"VimPortGroupRefs,omitempty"`
+}
+
+// ExternalNetworkConfiguration is the configuration of an external network. Unlike
+// NetworkConfiguration, it can hold more than one IP scope (subnet).
+// Type: NetworkConfigurationType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Since: 0.9
+type ExternalNetworkConfiguration struct {
+	IPScopes                       *ExternalNetworkIPScopes `xml:"IpScopes,omitempty"`
+	FenceMode                      string                   `xml:"FenceMode"`
+	RetainNetInfoAcrossDeployments bool                     `xml:"RetainNetInfoAcrossDeployments,omitempty"`
+}
+
+// ExternalNetworkIPScopes is the list of IP scopes (subnets) of an external network
+type ExternalNetworkIPScopes struct {
+	IPScope []*IPScope `xml:"IpScope"`
+}
+
 // Namespace: http://www.vmware.com/vcloud/v1.5
 // Retrieve a list of extension objects and operations.
 // Since: 1.0
@@ -2171,6 +3108,127 @@ type ExternalNetworkReference struct {
 	Name string `xml:"name,attr,omitempty"`
 }
 
+// VCloud is the top-level admin view of the vCD installation. Its description holds the vCD
+// version and build date.
+// Type: VCloudType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Represents the vCloud Director installation.
+// Since: 0.9
+type VCloud struct {
+	XMLName     xml.Name `xml:"VCloud"`
+	HREF        string   `xml:"href,attr,omitempty"`
+	Type        string   `xml:"type,attr,omitempty"`
+	Name        string   `xml:"name,attr"`
+	Link        LinkList `xml:"Link,omitempty"`
+	Description string   `xml:"Description,omitempty"`
+}
+
+// AmqpSettings are the settings of the AMQP broker to which vCD publishes notifications and
+// extension service requests. The password is never returned by vCD.
+// Type: AmqpSettingsType
+// Namespace: http://www.vmware.com/vcloud/extension/v1.5
+// Description: AMQP settings.
+// Since: 1.5
+// https://code.vmware.com/apis/220/vcloud#/doc/doc/types/AmqpSettingsType.html
+type AmqpSettings struct {
+	XMLName          xml.Name `xml:"http://www.vmware.com/vcloud/extension/v1.5 AmqpSettings"`
+	HREF             string   `xml:"href,attr,omitempty"`
+	Type             string   `xml:"type,attr,omitempty"`
+	Link             LinkList `xml:"http://www.vmware.com/vcloud/v1.5 Link,omitempty"`
+	AmqpHost         string   `xml:"AmqpHost"`
+	AmqpPort         int      `xml:"AmqpPort"`
+	AmqpUsername     string   `xml:"AmqpUsername"`
+	AmqpPassword     string   `xml:"AmqpPassword,omitempty"`
+	AmqpExchange     string   `xml:"AmqpExchange"`
+	AmqpVHost        string   `xml:"AmqpVHost"`
+	AmqpUseSSL       bool     `xml:"AmqpUseSSL"`
+	AmqpSslAcceptAll bool     `xml:"AmqpSslAcceptAll"`
+	AmqpPrefix       string   `xml:"AmqpPrefix"`
+}
+
+// AmqpSettingsTest holds the AMQP settings to test before applying them
+// Type: AmqpSettingsTestType
+// Namespace: http://www.vmware.com/vcloud/extension/v1.5
+// Since: 1.5
+type AmqpSettingsTest struct {
+	XMLName      xml.Name      `xml:"http://www.vmware.com/vcloud/extension/v1.5 AmqpSettingsTest"`
+	AmqpSettings *AmqpSettings `xml:"AmqpSettings"`
+}
+
+// AmqpSettingsTestResult is the result of an AMQP settings test
+type AmqpSettingsTestResult struct {
+	Valid bool `xml:"Valid"`
+}
+
+// Notification is an event published by vCD on its message bus (AMQP, or MQTT from vCD 10.0).
+// AMQP messages are XML documents; MQTT messages are JSON documents with the same fields.
+// Type: NotificationType
+// Namespace: http://www.vmware.com/vcloud/extension/v1.5
+// Description: A notification message, such as the creation of an entity or the completion of a task.
+// Since: 1.5
+type Notification struct {
+	XMLName          xml.Name                  `xml:"http://www.vmware.com/vcloud/extension/v1.5 Notification" json:"-"`
+	Type             string                    `xml:"type,attr" json:"type"`       // e.g. com/vmware/vcloud/event/vapp/create
+	EventId          string                    `xml:"eventId,attr" json:"eventId"` // Unique ID of the event
+	Link             LinkList                  `xml:"Link,omitempty" json:"-"`
+	EntityLink       []*NotificationEntityLink `xml:"EntityLink,omitempty" json:"entityLinks,omitempty"`
+	Timestamp        string                    `xml:"Timestamp" json:"timestamp"`
+	OperationSuccess bool                      `xml:"OperationSuccess" json:"operationSuccess"`
+}
+
+// NotificationEntityLink is a reference to an entity concerned by a notification. The relation
+// tells its role: the entity of the event (entity), its parent (up), the user (down), the
+// org (up with type vcloud:org) or the task (task).
+type NotificationEntityLink struct {
+	Rel  string `xml:"rel,attr" json:"rel"`
+	Type string `xml:"type,attr" json:"type"` // e.g. vcloud:vapp
+	Name string `xml:"name,attr" json:"name"`
+	ID   string `xml:"id,attr" json:"id"` // URN of the entity
+}
+
+// AdminService is an extension service: vCD forwards the API requests matching its API filters
+// to the service through the AMQP exchange, with the given routing key.
+// Type: AdminServiceType
+// Namespace: http://www.vmware.com/vcloud/extension/v1.5
+// Description: Admin representation of an extension service.
+// Since: 5.1
+// https://code.vmware.com/apis/220/vcloud#/doc/doc/types/AdminServiceType.html
+type AdminService struct {
+	XMLName              xml.Name         `xml:"http://www.vmware.com/vcloud/extension/v1.5 Service"`
+	HREF                 string           `xml:"href,attr,omitempty"`
+	Type                 string           `xml:"type,attr,omitempty"`
+	ID                   string           `xml:"id,attr,omitempty"`
+	Name                 string           `xml:"name,attr"`
+	Link                 LinkList         `xml:"http://www.vmware.com/vcloud/v1.5 Link,omitempty"`
+	Description          string           `xml:"http://www.vmware.com/vcloud/v1.5 Description,omitempty"`
+	Tasks                *TasksInProgress `xml:"http://www.vmware.com/vcloud/v1.5 Tasks,omitempty"`
+	Namespace            string           `xml:"Namespace"`
+	Enabled              *bool            `xml:"Enabled,omitempty"`
+	AuthorizationEnabled *bool            `xml:"AuthorizationEnabled,omitempty"`
+	RoutingKey           string           `xml:"RoutingKey"`
+	Priority             int              `xml:"Priority,omitempty"`
+	Exchange             string           `xml:"Exchange"`
+	ApiFilters           *ApiFilters      `xml:"ApiFilters,omitempty"`
+}
+
+// ApiFilters is the list of the API filters of an extension service
+type ApiFilters struct {
+	ApiFilter []*ApiFilter `xml:"ApiFilter"`
+}
+
+// ApiFilter is a regular expression for the request URLs that vCD forwards to an extension service
+// Type: ApiFilterType
+// Namespace: http://www.vmware.com/vcloud/extension/v1.5
+// Since: 5.1
+type ApiFilter struct {
+	XMLName    xml.Name `xml:"http://www.vmware.com/vcloud/extension/v1.5 ApiFilter"`
+	HREF       string   `xml:"href,attr,omitempty"`
+	Type       string   `xml:"type,attr,omitempty"`
+	ID         string   `xml:"id,attr,omitempty"`
+	Link       LinkList `xml:"http://www.vmware.com/vcloud/v1.5 Link,omitempty"`
+	UrlPattern string   `xml:"UrlPattern"`
+}
+
 // Type: MediaType
 // Namespace: http://www.vmware.com/vcloud/v1.5
 // https://vdc-repo.vmware.com/vmwb-repository/dcr-public/ca48e1bb-282b-4fdc-b827-649b819249ed/55142cf1-5bb8-4ab1-8d09-b84f717af5ec/doc/doc/types/MediaType.html
@@ -2306,6 +3364,27 @@ type MediaInsertOrEjectParams struct {
 	VCloudExtension *VCloudExtension `xml:"VCloudExtension,omitempty"`
 }
 
+// ScreenTicket is a ticket to open the console of a VM, in the form mks://host/vm-moref/ticket
+// Reference: vCloud API 27.0 - ScreenTicketType
+// https://code.vmware.com/apis/287/vcloud#/doc/doc/types/ScreenTicketType.html
+type ScreenTicket struct {
+	XMLName xml.Name `xml:"ScreenTicket"`
+	Xmlns   string   `xml:"xmlns,attr,omitempty"`
+	Value   string   `xml:",chardata"`
+}
+
+// MksTicket is a ticket to open the console of a VM with the WebMKS console
+// Reference: vCloud API 27.0 - MksTicketType
+// https://code.vmware.com/apis/287/vcloud#/doc/doc/types/MksTicketType.html
+type MksTicket struct {
+	XMLName xml.Name `xml:"MksTicket"`
+	Xmlns   string   `xml:"xmlns,attr,omitempty"`
+	Host    string   `xml:"Host"`   // Host of the console proxy
+	Vmx     string   `xml:"Vmx"`    // Path of the VMX file of the VM
+	Ticket  string   `xml:"Ticket"` // The ticket, valid for 30 seconds
+	Port    int      `xml:"Port"`   // Port of the console proxy
+}
+
 // Parameters for VM pending questions
 // Reference: vCloud API 27.0 - VmPendingQuestionType
 // https://code.vmware.com/apis/287/vcloud#/doc/doc/types/VmPendingQuestionType.html
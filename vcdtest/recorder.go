@@ -0,0 +1,235 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package vcdtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// RecorderMode defines whether a Recorder records real API interactions or replays them
+type RecorderMode string
+
+const (
+	ModeRecord RecorderMode = "record" // Requests are sent to vCD and the interactions recorded
+	ModeReplay RecorderMode = "replay" // Responses are read from the recorded interactions
+)
+
+// ScrubbedValue replaces the credentials found in the recorded interactions
+const ScrubbedValue = "[SCRUBBED]"
+
+// Headers holding credentials, which are never written to the fixture file
+var scrubbedHeaders = []string{
+	"Authorization",
+	"Set-Cookie",
+	"X-Vcloud-Authorization",
+	"X-Vmware-Vcloud-Access-Token",
+}
+
+// Credentials in request and response bodies: XML <Password> elements and JSON password and
+// token fields
+var scrubbedBodyPatterns = []struct {
+	regex       *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`(?s)(<(?:\w+:)?(?:Password|AmqpPassword)>).*?(</(?:\w+:)?(?:Password|AmqpPassword)>)`), "${1}" + ScrubbedValue + "${2}"},
+	{regexp.MustCompile(`("(?:password|refresh_token|access_token|client_secret)"\s*:\s*)"[^"]*"`), `${1}"` + ScrubbedValue + `"`},
+}
+
+// Interaction is a recorded request with its response
+type Interaction struct {
+	Method         string      `json:"method"`
+	URL            string      `json:"url"`
+	RequestBody    string      `json:"request_body,omitempty"`
+	Status         int         `json:"status"`
+	ResponseHeader http.Header `json:"response_header,omitempty"`
+	ResponseBody   string      `json:"response_body,omitempty"`
+}
+
+// Recorder is an http.RoundTripper which records the interactions with vCD to a fixture file and
+// replays them, so that tests can run deterministically without live infrastructure.
+// Credentials are scrubbed before recording. In replay mode, each request gets the first
+// recorded response with the same method, URL and body which was not replayed yet, or with the
+// same method and URL when the body differs.
+//
+// Usage:
+//
+//	recorder, err := vcdtest.NewRecorder("fixtures.json", vcdtest.ModeRecord, vcdClient.Client.Http.Transport)
+//	vcdClient.Client.Http.Transport = recorder
+//	...
+//	err = recorder.Save()
+type Recorder struct {
+	mode      RecorderMode
+	fileName  string
+	transport http.RoundTripper
+
+	mutex        sync.Mutex
+	interactions []Interaction
+	replayed     []bool
+}
+
+// NewRecorder creates a recorder in the given mode. In record mode, the requests are sent through
+// transport (http.DefaultTransport when nil). In replay mode, the interactions are read from fileName.
+func NewRecorder(fileName string, mode RecorderMode, transport http.RoundTripper) (*Recorder, error) {
+	if fileName == "" {
+		return nil, fmt.Errorf("fixture file name is required")
+	}
+	recorder := &Recorder{mode: mode, fileName: fileName, transport: transport}
+	switch mode {
+	case ModeRecord:
+		if recorder.transport == nil {
+			recorder.transport = http.DefaultTransport
+		}
+	case ModeReplay:
+		content, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			return nil, fmt.Errorf("error reading fixture file %s: %s", fileName, err)
+		}
+		err = json.Unmarshal(content, &recorder.interactions)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding fixture file %s: %s", fileName, err)
+		}
+		recorder.replayed = make([]bool, len(recorder.interactions))
+	default:
+		return nil, fmt.Errorf("unknown recorder mode '%s'", mode)
+	}
+	return recorder, nil
+}
+
+// RoundTrip records or replays a request
+func (recorder *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestBody := ""
+	if req.Body != nil {
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("error reading request body: %s", err)
+		}
+		_ = req.Body.Close()
+		requestBody = string(body)
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	if recorder.mode == ModeReplay {
+		return recorder.replay(req, scrubBody(requestBody))
+	}
+
+	resp, err := recorder.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	responseBody, err := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %s", err)
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(responseBody))
+
+	header := copyHeader(resp.Header)
+	for _, name := range scrubbedHeaders {
+		if header.Get(name) != "" {
+			header.Set(name, ScrubbedValue)
+		}
+	}
+	recorder.mutex.Lock()
+	recorder.interactions = append(recorder.interactions, Interaction{
+		Method:         req.Method,
+		URL:            req.URL.String(),
+		RequestBody:    scrubBody(requestBody),
+		Status:         resp.StatusCode,
+		ResponseHeader: header,
+		ResponseBody:   scrubBody(string(responseBody)),
+	})
+	recorder.mutex.Unlock()
+	return resp, nil
+}
+
+// Save writes the recorded interactions to the fixture file. It does nothing in replay mode.
+func (recorder *Recorder) Save() error {
+	if recorder.mode != ModeRecord {
+		return nil
+	}
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	content, err := json.MarshalIndent(recorder.interactions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding interactions: %s", err)
+	}
+	err = ioutil.WriteFile(recorder.fileName, content, os.FileMode(0600))
+	if err != nil {
+		return fmt.Errorf("error writing fixture file %s: %s", recorder.fileName, err)
+	}
+	return nil
+}
+
+// Interactions returns the interactions recorded so far, or read from the fixture file
+func (recorder *Recorder) Interactions() []Interaction {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	interactions := make([]Interaction, len(recorder.interactions))
+	copy(interactions, recorder.interactions)
+	return interactions
+}
+
+// replay returns the recorded response matching the request
+func (recorder *Recorder) replay(req *http.Request, requestBody string) (*http.Response, error) {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	found := -1
+	for index, interaction := range recorder.interactions {
+		if recorder.replayed[index] || interaction.Method != req.Method || interaction.URL != req.URL.String() {
+			continue
+		}
+		if interaction.RequestBody == requestBody {
+			found = index
+			break
+		}
+		if found < 0 {
+			found = index
+		}
+	}
+	if found < 0 {
+		return nil, fmt.Errorf("no recorded interaction left for %s %s", req.Method, req.URL.String())
+	}
+	recorder.replayed[found] = true
+
+	interaction := recorder.interactions[found]
+	header := copyHeader(interaction.ResponseHeader)
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Status, http.StatusText(interaction.Status)),
+		StatusCode:    interaction.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(interaction.ResponseBody)),
+		ContentLength: int64(len(interaction.ResponseBody)),
+		Request:       req,
+	}, nil
+}
+
+// scrubBody replaces the credentials found in a request or response body
+func scrubBody(body string) string {
+	for _, pattern := range scrubbedBodyPatterns {
+		body = pattern.regex.ReplaceAllString(body, pattern.replacement)
+	}
+	return body
+}
+
+// copyHeader returns a deep copy of header, never nil
+func copyHeader(header http.Header) http.Header {
+	copied := make(http.Header, len(header))
+	for name, values := range header {
+		copied[name] = append([]string(nil), values...)
+	}
+	return copied
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package vcdtest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func doRecordedRequest(t *testing.T, client *http.Client, method, url, body string) (*http.Response, string) {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("error creating request: %s", err)
+	}
+	req.Header.Set("x-vcloud-authorization", MockToken)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("error sending request: %s", err)
+	}
+	defer resp.Body.Close()
+	responseBody, _ := ioutil.ReadAll(resp.Body)
+	return resp, string(responseBody)
+}
+
+func TestRecorderRecordAndReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vcdtest")
+	if err != nil {
+		t.Fatalf("error creating temporary directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	fileName := filepath.Join(dir, "fixtures.json")
+
+	server := NewServer()
+	server.HandleXML(http.MethodPost, "/api/admin/users", http.StatusCreated,
+		`<User xmlns="http://www.vmware.com/vcloud/v1.5" name="user1"><Password>secret</Password></User>`)
+	serverUrl := server.URL()
+
+	recorder, err := NewRecorder(fileName, ModeRecord, nil)
+	if err != nil {
+		t.Fatalf("error creating recorder: %s", err)
+	}
+	client := &http.Client{Transport: recorder}
+	loginResp, _ := doRecordedRequest(t, client, http.MethodPost, serverUrl+"/api/sessions", "")
+	if loginResp.Header.Get("x-vcloud-authorization") != MockToken {
+		t.Errorf("recording must not alter the live response headers")
+	}
+	_, recordedOrg := doRecordedRequest(t, client, http.MethodGet, serverUrl+MockOrgPath, "")
+	doRecordedRequest(t, client, http.MethodPost, serverUrl+"/api/admin/users",
+		`<User name="user1"><Password>secret</Password></User>`)
+	err = recorder.Save()
+	if err != nil {
+		t.Fatalf("error saving fixtures: %s", err)
+	}
+	server.Close()
+
+	content, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("error reading fixtures: %s", err)
+	}
+	if strings.Contains(string(content), MockToken) || strings.Contains(string(content), "secret") {
+		t.Errorf("credentials were not scrubbed from the fixtures: %s", content)
+	}
+
+	// The server is closed: the responses now come from the fixture file
+	replayer, err := NewRecorder(fileName, ModeReplay, nil)
+	if err != nil {
+		t.Fatalf("error loading fixtures: %s", err)
+	}
+	if len(replayer.Interactions()) != 3 {
+		t.Fatalf("expected 3 interactions, got %d", len(replayer.Interactions()))
+	}
+	client = &http.Client{Transport: replayer}
+	loginResp, _ = doRecordedRequest(t, client, http.MethodPost, serverUrl+"/api/sessions", "")
+	if loginResp.Header.Get("x-vcloud-authorization") != ScrubbedValue {
+		t.Errorf("expected scrubbed token, got '%s'", loginResp.Header.Get("x-vcloud-authorization"))
+	}
+	orgResp, replayedOrg := doRecordedRequest(t, client, http.MethodGet, serverUrl+MockOrgPath, "")
+	if orgResp.StatusCode != http.StatusOK || replayedOrg != recordedOrg {
+		t.Errorf("unexpected replayed org %d: %s", orgResp.StatusCode, replayedOrg)
+	}
+	userResp, _ := doRecordedRequest(t, client, http.MethodPost, serverUrl+"/api/admin/users",
+		`<User name="user1"><Password>other</Password></User>`)
+	if userResp.StatusCode != http.StatusCreated {
+		t.Errorf("expected status %d, got %d", http.StatusCreated, userResp.StatusCode)
+	}
+
+	// Every interaction is replayed once
+	_, err = client.Get(serverUrl + MockOrgPath)
+	if err == nil || !strings.Contains(err.Error(), "no recorded interaction left") {
+		t.Errorf("expected error for a request not recorded, got %v", err)
+	}
+}
+
+func TestScrubBody(t *testing.T) {
+	tests := map[string]string{
+		`<Password>secret</Password>`:                       `<Password>` + ScrubbedValue + `</Password>`,
+		`<vcloud:Password>secret</vcloud:Password>`:         `<vcloud:Password>` + ScrubbedValue + `</vcloud:Password>`,
+		`<AmqpPassword>secret</AmqpPassword>`:               `<AmqpPassword>` + ScrubbedValue + `</AmqpPassword>`,
+		`{"name":"user1","password": "secret"}`:             `{"name":"user1","password": "` + ScrubbedValue + `"}`,
+		`{"access_token":"abc","refresh_token":"def"}`:      `{"access_token":"` + ScrubbedValue + `","refresh_token":"` + ScrubbedValue + `"}`,
+		`<Org name="test-org"><FullName>x</FullName></Org>`: `<Org name="test-org"><FullName>x</FullName></Org>`,
+	}
+	for body, expected := range tests {
+		if scrubbed := scrubBody(body); scrubbed != expected {
+			t.Errorf("scrubbing %s: expected %s, got %s", body, expected, scrubbed)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package vcdtest
+
+import (
+	"net/http"
+)
+
+// Names and IDs of the entities served by the fake vCD
+const (
+	MockOrgName     = "test-org"
+	MockOrgId       = "11111111-1111-1111-1111-111111111111"
+	MockVdcName     = "test-vdc"
+	MockVdcId       = "22222222-2222-2222-2222-222222222222"
+	MockCatalogName = "test-catalog"
+	MockCatalogId   = "33333333-3333-3333-3333-333333333333"
+	MockTaskId      = "44444444-4444-4444-4444-444444444444"
+)
+
+// Paths of the entities served by the fake vCD
+const (
+	MockOrgPath      = "/api/org/" + MockOrgId
+	MockAdminOrgPath = "/api/admin/org/" + MockOrgId
+	MockVdcPath      = "/api/vdc/" + MockVdcId
+	MockCatalogPath  = "/api/catalog/" + MockCatalogId
+	MockTaskPath     = "/api/task/" + MockTaskId
+)
+
+const versionsXml = `<?xml version="1.0" encoding="UTF-8"?>
+<SupportedVersions xmlns="http://www.vmware.com/vcloud/versions">
+  <VersionInfo deprecated="false">
+    <Version>27.0</Version>
+    <LoginUrl>{{server}}/api/sessions</LoginUrl>
+  </VersionInfo>
+  <VersionInfo deprecated="false">
+    <Version>29.0</Version>
+    <LoginUrl>{{server}}/api/sessions</LoginUrl>
+  </VersionInfo>
+  <VersionInfo deprecated="false">
+    <Version>31.0</Version>
+    <LoginUrl>{{server}}/api/sessions</LoginUrl>
+  </VersionInfo>
+</SupportedVersions>`
+
+const sessionXml = `<?xml version="1.0" encoding="UTF-8"?>
+<Session xmlns="http://www.vmware.com/vcloud/v1.5" user="user" org="` + MockOrgName + `" href="{{server}}/api/session/">
+  <Link rel="down" type="application/vnd.vmware.vcloud.orgList+xml" href="{{server}}/api/org/"/>
+  <Link rel="remove" href="{{server}}/api/session/"/>
+</Session>`
+
+const orgListXml = `<?xml version="1.0" encoding="UTF-8"?>
+<OrgList xmlns="http://www.vmware.com/vcloud/v1.5" type="application/vnd.vmware.vcloud.orgList+xml" href="{{server}}/api/org/">
+  <Org type="application/vnd.vmware.vcloud.org+xml" name="` + MockOrgName + `" href="{{server}}` + MockOrgPath + `"/>
+</OrgList>`
+
+const orgXml = `<?xml version="1.0" encoding="UTF-8"?>
+<Org xmlns="http://www.vmware.com/vcloud/v1.5" name="` + MockOrgName + `" id="urn:vcloud:org:` + MockOrgId + `" type="application/vnd.vmware.vcloud.org+xml" href="{{server}}` + MockOrgPath + `">
+  <Link rel="down" type="application/vnd.vmware.vcloud.vdc+xml" name="` + MockVdcName + `" href="{{server}}` + MockVdcPath + `"/>
+  <Link rel="down" type="application/vnd.vmware.vcloud.catalog+xml" name="` + MockCatalogName + `" href="{{server}}` + MockCatalogPath + `"/>
+  <Description>Organization of the fake vCD</Description>
+  <FullName>` + MockOrgName + `</FullName>
+</Org>`
+
+const adminOrgXml = `<?xml version="1.0" encoding="UTF-8"?>
+<AdminOrg xmlns="http://www.vmware.com/vcloud/v1.5" name="` + MockOrgName + `" id="urn:vcloud:org:` + MockOrgId + `" type="application/vnd.vmware.admin.organization+xml" href="{{server}}` + MockAdminOrgPath + `">
+  <Description>Organization of the fake vCD</Description>
+  <FullName>` + MockOrgName + `</FullName>
+  <IsEnabled>true</IsEnabled>
+  <Settings/>
+  <Vdcs>
+    <Vdc type="application/vnd.vmware.admin.vdc+xml" name="` + MockVdcName + `" href="{{server}}/api/admin/vdc/` + MockVdcId + `"/>
+  </Vdcs>
+  <Catalogs>
+    <CatalogReference type="application/vnd.vmware.admin.catalog+xml" name="` + MockCatalogName + `" href="{{server}}/api/admin/catalog/` + MockCatalogId + `"/>
+  </Catalogs>
+</AdminOrg>`
+
+const vdcXml = `<?xml version="1.0" encoding="UTF-8"?>
+<Vdc xmlns="http://www.vmware.com/vcloud/v1.5" status="1" name="` + MockVdcName + `" id="urn:vcloud:vdc:` + MockVdcId + `" type="application/vnd.vmware.vcloud.vdc+xml" href="{{server}}` + MockVdcPath + `">
+  <Link rel="up" type="application/vnd.vmware.vcloud.org+xml" href="{{server}}` + MockOrgPath + `"/>
+  <AllocationModel>AllocationVApp</AllocationModel>
+  <ResourceEntities/>
+  <AvailableNetworks/>
+  <IsEnabled>true</IsEnabled>
+</Vdc>`
+
+const catalogXml = `<?xml version="1.0" encoding="UTF-8"?>
+<Catalog xmlns="http://www.vmware.com/vcloud/v1.5" name="` + MockCatalogName + `" id="urn:vcloud:catalog:` + MockCatalogId + `" type="application/vnd.vmware.vcloud.catalog+xml" href="{{server}}` + MockCatalogPath + `">
+  <Link rel="up" type="application/vnd.vmware.vcloud.org+xml" href="{{server}}` + MockOrgPath + `"/>
+  <Description>Catalog of the fake vCD</Description>
+  <CatalogItems/>
+  <IsPublished>false</IsPublished>
+</Catalog>`
+
+const taskXml = `<?xml version="1.0" encoding="UTF-8"?>
+<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="success" operationName="mock" operation="Mock task" id="urn:vcloud:task:` + MockTaskId + `" type="application/vnd.vmware.vcloud.task+xml" href="{{server}}` + MockTaskPath + `">
+  <Progress>100</Progress>
+</Task>`
+
+// defaultResponses returns the canned responses of a new fake vCD
+func defaultResponses() map[string]Response {
+	xmlResponse := func(contentType, body string) Response {
+		return Response{ContentType: contentType + ";version=27.0", Body: body}
+	}
+	return map[string]Response{
+		"GET /api/versions": xmlResponse("application/xml", versionsXml),
+		"POST /api/sessions": {
+			ContentType: "application/vnd.vmware.vcloud.session+xml;version=27.0",
+			Body:        sessionXml,
+			Header:      map[string]string{"x-vcloud-authorization": MockToken, "X-VMWARE-VCLOUD-ACCESS-TOKEN": MockAccessToken},
+		},
+		"DELETE /api/sessions":    {Status: http.StatusNoContent},
+		"GET /api/session":        xmlResponse("application/vnd.vmware.vcloud.session+xml", sessionXml),
+		"GET /api/org":            xmlResponse("application/vnd.vmware.vcloud.orgList+xml", orgListXml),
+		"GET " + MockOrgPath:      xmlResponse("application/vnd.vmware.vcloud.org+xml", orgXml),
+		"GET " + MockAdminOrgPath: xmlResponse("application/vnd.vmware.admin.organization+xml", adminOrgXml),
+		"GET " + MockVdcPath:      xmlResponse("application/vnd.vmware.vcloud.vdc+xml", vdcXml),
+		"GET " + MockCatalogPath:  xmlResponse("application/vnd.vmware.vcloud.catalog+xml", catalogXml),
+		"GET " + MockTaskPath:     xmlResponse("application/vnd.vmware.vcloud.task+xml", taskXml),
+	}
+}
@@ -0,0 +1,211 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+// Package vcdtest provides a fake vCloud Director, based on httptest, to unit test code using
+// go-vcloud-director without a live vCD.
+//
+// The fake vCD supports the login flow of govcd.VCDClient.Authenticate and serves canned
+// responses for an organization (MockOrgName), with a VDC (MockVdcName), a catalog
+// (MockCatalogName) and a successful task (MockTaskId). Any other response can be added with
+// Handle, HandleXML or HandleJSON. All the requests received are recorded and can be inspected
+// with Requests and RequestsTo.
+//
+// Usage:
+//
+//	server := vcdtest.NewServer()
+//	defer server.Close()
+//	vcdClient := govcd.NewVCDClient(server.ApiURL(), true)
+//	err := vcdClient.Authenticate("user", "password", vcdtest.MockOrgName)
+//	org, err := govcd.GetOrgByName(vcdClient, vcdtest.MockOrgName)
+package vcdtest
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// ServerPlaceholder is replaced by the base URL of the fake vCD (e.g. http://127.0.0.1:12345) in
+// the bodies and headers of the responses, so that canned responses can contain valid HREFs
+const ServerPlaceholder = "{{server}}"
+
+// MockToken is the authorization token returned by the fake vCD after login
+const MockToken = "vcdtest-token"
+
+// MockAccessToken is the bearer token returned by the fake vCD after login, accepted instead of
+// MockToken in an Authorization header
+const MockAccessToken = "vcdtest-access-token"
+
+// MockTransferPath is the path of the transfer (spool) area of the fake vCD. As in vCD, the upload
+// links under it do not need the authorization token.
+const MockTransferPath = "/transfer/"
+
+// Response is a canned response of the fake vCD
+type Response struct {
+	Status      int               // HTTP status, 200 when not set
+	ContentType string            // Content-Type header
+	Body        string            // Body, where ServerPlaceholder is replaced by the server URL
+	Header      map[string]string // Additional headers
+}
+
+// Request is a request received by the fake vCD
+type Request struct {
+	Method   string
+	Path     string
+	RawQuery string
+	Header   http.Header
+	Body     string
+}
+
+// Server is a fake vCD. The zero value is not usable: use NewServer.
+type Server struct {
+	server *httptest.Server
+
+	mutex     sync.Mutex
+	responses map[string]Response
+	handlers  map[string]http.HandlerFunc
+	requests  []Request
+}
+
+// NewServer starts a fake vCD with the canned responses for the login and the mock organization
+func NewServer() *Server {
+	fake := &Server{responses: make(map[string]Response), handlers: make(map[string]http.HandlerFunc)}
+	for key, response := range defaultResponses() {
+		fake.responses[key] = response
+	}
+	fake.server = httptest.NewServer(http.HandlerFunc(fake.serveHTTP))
+	return fake
+}
+
+// Close shuts down the fake vCD
+func (fake *Server) Close() {
+	fake.server.Close()
+}
+
+// URL returns the base URL of the fake vCD, e.g. http://127.0.0.1:12345
+func (fake *Server) URL() string {
+	return fake.server.URL
+}
+
+// ApiURL returns the API endpoint of the fake vCD, to be used with govcd.NewVCDClient
+func (fake *Server) ApiURL() url.URL {
+	apiUrl, _ := url.Parse(fake.server.URL + "/api")
+	return *apiUrl
+}
+
+// Handle sets the response for the requests with the given method and path (e.g. "/api/org"),
+// replacing the canned one if any
+func (fake *Server) Handle(method, path string, response Response) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	fake.responses[method+" "+path] = response
+}
+
+// HandleXML sets an XML response for the requests with the given method and path
+func (fake *Server) HandleXML(method, path string, status int, body string) {
+	fake.Handle(method, path, Response{Status: status, ContentType: "application/*+xml;version=27.0", Body: body})
+}
+
+// HandleJSON sets a JSON response for the requests with the given method and path
+func (fake *Server) HandleJSON(method, path string, status int, body string) {
+	fake.Handle(method, path, Response{Status: status, ContentType: "application/json", Body: body})
+}
+
+// HandleFunc sets a handler for the requests with the given method and path, for the exchanges
+// which cannot be canned, e.g. WebSocket connections. The requests are still recorded, and
+// rejected without the authorization token.
+func (fake *Server) HandleFunc(method, path string, handler http.HandlerFunc) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	fake.handlers[method+" "+path] = handler
+}
+
+// Requests returns all the requests received so far
+func (fake *Server) Requests() []Request {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	requests := make([]Request, len(fake.requests))
+	copy(requests, fake.requests)
+	return requests
+}
+
+// RequestsTo returns the requests received so far with the given method and path
+func (fake *Server) RequestsTo(method, path string) []Request {
+	var requests []Request
+	for _, request := range fake.Requests() {
+		if request.Method == method && request.Path == path {
+			requests = append(requests, request)
+		}
+	}
+	return requests
+}
+
+// ClearRequests forgets the requests received so far
+func (fake *Server) ClearRequests() {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	fake.requests = nil
+}
+
+// serveHTTP records the request and writes the matching response. Requests without the token
+// returned at login are rejected, except the ones needed to log in and the uploads to the transfer
+// area.
+func (fake *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	key := r.Method + " " + r.URL.Path
+
+	fake.mutex.Lock()
+	fake.requests = append(fake.requests, Request{
+		Method:   r.Method,
+		Path:     r.URL.Path,
+		RawQuery: r.URL.RawQuery,
+		Header:   r.Header,
+		Body:     string(body),
+	})
+	response, found := fake.responses[key]
+	handler := fake.handlers[key]
+	fake.mutex.Unlock()
+
+	if key != "GET /api/versions" && key != "POST /api/sessions" && !strings.HasPrefix(r.URL.Path, MockTransferPath) &&
+		r.Header.Get("x-vcloud-authorization") != MockToken && r.Header.Get("Authorization") != "Bearer "+MockAccessToken {
+		response, found, handler = errorResponse(http.StatusUnauthorized, "UNAUTHORIZED", "not authenticated"), true, nil
+	}
+	if handler != nil {
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		handler(w, r)
+		return
+	}
+	if !found {
+		response = errorResponse(http.StatusNotFound, "RESOURCE_NOT_FOUND",
+			fmt.Sprintf("no response set for %s", key))
+	}
+
+	for name, value := range response.Header {
+		w.Header().Set(name, strings.Replace(value, ServerPlaceholder, fake.server.URL, -1))
+	}
+	if response.ContentType != "" {
+		w.Header().Set("Content-Type", response.ContentType)
+	}
+	status := response.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	_, _ = fmt.Fprint(w, strings.Replace(response.Body, ServerPlaceholder, fake.server.URL, -1))
+}
+
+// errorResponse returns a vCD error with the given status
+func errorResponse(status int, minorErrorCode, message string) Response {
+	return Response{
+		Status:      status,
+		ContentType: "application/vnd.vmware.vcloud.error+xml;version=27.0",
+		Body: fmt.Sprintf(`<Error xmlns="http://www.vmware.com/vcloud/v1.5" majorErrorCode="%d" minorErrorCode="%s" message="%s"/>`,
+			status, minorErrorCode, message),
+	}
+}
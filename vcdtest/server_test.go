@@ -0,0 +1,83 @@
+/*
+ * Copyright 2019 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package vcdtest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func doRequest(t *testing.T, method, url, token, body string) (*http.Response, string) {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("error creating request: %s", err)
+	}
+	if token != "" {
+		req.Header.Set("x-vcloud-authorization", token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("error sending request: %s", err)
+	}
+	defer resp.Body.Close()
+	responseBody, _ := ioutil.ReadAll(resp.Body)
+	return resp, string(responseBody)
+}
+
+func TestServerLogin(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	resp, body := doRequest(t, http.MethodGet, server.URL()+"/api/versions", "", "")
+	if resp.StatusCode != http.StatusOK || !strings.Contains(body, server.URL()+"/api/sessions") {
+		t.Errorf("unexpected versions response %d: %s", resp.StatusCode, body)
+	}
+
+	resp, _ = doRequest(t, http.MethodGet, server.URL()+MockOrgPath, "", "")
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected unauthenticated request to be rejected, got %d", resp.StatusCode)
+	}
+
+	resp, _ = doRequest(t, http.MethodPost, server.URL()+"/api/sessions", "", "")
+	token := resp.Header.Get("x-vcloud-authorization")
+	if token != MockToken {
+		t.Fatalf("unexpected token %s", token)
+	}
+	resp, body = doRequest(t, http.MethodGet, server.URL()+MockOrgPath, token, "")
+	if resp.StatusCode != http.StatusOK || !strings.Contains(body, `name="`+MockOrgName+`"`) {
+		t.Errorf("unexpected org response %d: %s", resp.StatusCode, body)
+	}
+}
+
+func TestServerHandleAndRecord(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	server.HandleXML(http.MethodPut, MockVdcPath, http.StatusAccepted,
+		`<Task xmlns="http://www.vmware.com/vcloud/v1.5" status="running" href="{{server}}`+MockTaskPath+`"/>`)
+	resp, body := doRequest(t, http.MethodPut, server.URL()+MockVdcPath+"?force=true", MockToken, "<Vdc/>")
+	if resp.StatusCode != http.StatusAccepted || !strings.Contains(body, server.URL()+MockTaskPath) {
+		t.Errorf("unexpected response %d: %s", resp.StatusCode, body)
+	}
+
+	resp, _ = doRequest(t, http.MethodDelete, server.URL()+MockVdcPath, MockToken, "")
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for a request without response, got %d", resp.StatusCode)
+	}
+
+	requests := server.RequestsTo(http.MethodPut, MockVdcPath)
+	if len(requests) != 1 || requests[0].Body != "<Vdc/>" || requests[0].RawQuery != "force=true" {
+		t.Errorf("unexpected recorded requests %#v", requests)
+	}
+	if len(server.Requests()) != 2 {
+		t.Errorf("expected 2 recorded requests, got %d", len(server.Requests()))
+	}
+	server.ClearRequests()
+	if len(server.Requests()) != 0 {
+		t.Errorf("expected no recorded requests after clearing them")
+	}
+}